@@ -0,0 +1,201 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package attestations
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/gittuf/gittuf/internal/third_party/go-git"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing/filemode"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing/object"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+)
+
+const (
+	// Ref is the Git ref that stores the repository's attestations.
+	Ref = "refs/gittuf/attestations"
+
+	verificationSummariesDir = "verification-summaries"
+)
+
+var (
+	ErrAttestationNotFound = errors.New("requested attestation not found")
+)
+
+// StoreVerificationSummary records the signed verification summary attestation
+// in the attestations namespace and creates an RSL entry for the update. The
+// attestation is stored at a path derived from the ref and commit it is about,
+// replacing any previously stored verification summary for the same ref and
+// commit.
+func StoreVerificationSummary(repo *git.Repository, env *sslibdsse.Envelope, signCommit bool) error {
+	statement, err := GetVerificationSummary(env)
+	if err != nil {
+		return err
+	}
+	subject := statement.Subject[0]
+
+	envBytes, err := json.Marshal(env)
+	if err != nil {
+		return err
+	}
+	blobID, err := gitinterface.WriteBlob(repo, envBytes)
+	if err != nil {
+		return err
+	}
+
+	lock, err := gitinterface.LockRepository(repo, gitinterface.DefaultLockTimeout)
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock() //nolint:errcheck
+
+	originalCommitID, err := gitinterface.GetTip(repo, Ref)
+	if err != nil {
+		if !errors.Is(err, plumbing.ErrReferenceNotFound) {
+			return err
+		}
+		originalCommitID = plumbing.ZeroHash
+	}
+
+	files := map[string]plumbing.Hash{}
+	if !originalCommitID.IsZero() {
+		files, err = getFiles(repo, originalCommitID)
+		if err != nil {
+			return err
+		}
+	}
+	files[verificationSummaryPath(subject.Name, subject.Digest[DigestAlgorithmGitCommit])] = blobID
+
+	treeID, err := writeTreeForFiles(repo, files)
+	if err != nil {
+		return err
+	}
+
+	commitMessage := fmt.Sprintf("Add verification summary for '%s' at '%s'", subject.Name, subject.Digest[DigestAlgorithmGitCommit])
+	commitID, err := gitinterface.Commit(repo, treeID, Ref, commitMessage, signCommit)
+	if err != nil {
+		return err
+	}
+
+	// We must reset to original attestations commit if err != nil from here
+	// onwards.
+
+	if err := rsl.NewReferenceEntry(Ref, commitID).CommitWhileLocked(repo, signCommit); err != nil {
+		if originalCommitID.IsZero() {
+			return errors.Join(err, repo.Storer.RemoveReference(plumbing.ReferenceName(Ref)))
+		}
+		return gitinterface.ResetDueToError(err, repo, Ref, originalCommitID)
+	}
+
+	return nil
+}
+
+// LoadVerificationSummary returns the signed verification summary attestation
+// stored for the specified commit of the ref. If no verification summary is
+// stored for them, ErrAttestationNotFound is returned.
+func LoadVerificationSummary(ctx context.Context, repo *git.Repository, refName string, commitID plumbing.Hash) (*sslibdsse.Envelope, error) {
+	tip, err := gitinterface.GetTip(repo, Ref)
+	if err != nil {
+		if errors.Is(err, plumbing.ErrReferenceNotFound) {
+			return nil, ErrAttestationNotFound
+		}
+		return nil, err
+	}
+
+	commit, err := repo.CommitObject(tip)
+	if err != nil {
+		return nil, err
+	}
+
+	blobID, err := gitinterface.GetPathIDInTree(repo, commit.TreeHash, verificationSummaryPath(refName, commitID.String()))
+	if err != nil {
+		if errors.Is(err, gitinterface.ErrTreeDoesNotHavePath) {
+			return nil, ErrAttestationNotFound
+		}
+		return nil, err
+	}
+
+	envBytes, err := gitinterface.ReadBlob(ctx, repo, blobID)
+	if err != nil {
+		return nil, err
+	}
+
+	env := &sslibdsse.Envelope{}
+	if err := json.Unmarshal(envBytes, env); err != nil {
+		return nil, err
+	}
+
+	return env, nil
+}
+
+// verificationSummaryPath returns the path of the verification summary for the
+// commit of the ref in the attestations tree. The ref is encoded so that it is
+// a single path component.
+func verificationSummaryPath(refName, commitID string) string {
+	return path.Join(verificationSummariesDir, base64.URLEncoding.EncodeToString([]byte(refName)), commitID)
+}
+
+// getFiles returns the path and blob ID of every file in the commit's tree.
+func getFiles(repo *git.Repository, commitID plumbing.Hash) (map[string]plumbing.Hash, error) {
+	commit, err := repo.CommitObject(commitID)
+	if err != nil {
+		return nil, err
+	}
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, err
+	}
+
+	files := map[string]plumbing.Hash{}
+	err = tree.Files().ForEach(func(f *object.File) error {
+		files[f.Name] = f.Hash
+		return nil
+	})
+
+	return files, err
+}
+
+// writeTreeForFiles writes the trees needed to store each of the files at its
+// path, returning the ID of the root tree.
+func writeTreeForFiles(repo *git.Repository, files map[string]plumbing.Hash) (plumbing.Hash, error) {
+	subtreeFiles := map[string]map[string]plumbing.Hash{}
+	entries := []object.TreeEntry{}
+	for filePath, blobID := range files {
+		dir, rest, isNested := strings.Cut(filePath, "/")
+		if !isNested {
+			entries = append(entries, object.TreeEntry{Name: filePath, Mode: filemode.Regular, Hash: blobID})
+			continue
+		}
+
+		if _, has := subtreeFiles[dir]; !has {
+			subtreeFiles[dir] = map[string]plumbing.Hash{}
+		}
+		subtreeFiles[dir][rest] = blobID
+	}
+
+	dirs := make([]string, 0, len(subtreeFiles))
+	for dir := range subtreeFiles {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+
+	for _, dir := range dirs {
+		subtreeID, err := writeTreeForFiles(repo, subtreeFiles[dir])
+		if err != nil {
+			return plumbing.ZeroHash, err
+		}
+		entries = append(entries, object.TreeEntry{Name: dir, Mode: filemode.Dir, Hash: subtreeID})
+	}
+
+	return gitinterface.WriteTree(repo, entries)
+}
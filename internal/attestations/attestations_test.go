@@ -0,0 +1,99 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package attestations
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/gittuf/gittuf/internal/signerverifier"
+	"github.com/gittuf/gittuf/internal/third_party/go-git"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/storage/memory"
+	"github.com/go-git/go-billy/v5/memfs"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStoreAndLoadVerificationSummary(t *testing.T) {
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signer := loadTestSigner(t, "test-key")
+	policyCommitID := plumbing.NewHash("2222222222222222222222222222222222222222")
+	mainCommitID := plumbing.NewHash("1111111111111111111111111111111111111111")
+	featureCommitID := plumbing.NewHash("3333333333333333333333333333333333333333")
+
+	createSummary := func(t *testing.T, refName string, commitID plumbing.Hash) *sslibdsse.Envelope {
+		t.Helper()
+
+		statement := NewVerificationSummary(refName, commitID, "refs/gittuf/policy", policyCommitID, time.Now())
+		env, err := SignVerificationSummary(context.Background(), statement, signer)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return env
+	}
+
+	_, err = LoadVerificationSummary(context.Background(), repo, "refs/heads/main", mainCommitID)
+	assert.ErrorIs(t, err, ErrAttestationNotFound)
+
+	mainEnv := createSummary(t, "refs/heads/main", mainCommitID)
+	err = StoreVerificationSummary(repo, mainEnv, false)
+	assert.Nil(t, err)
+
+	latestEntry, err := rsl.GetLatestEntry(repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if assert.IsType(t, &rsl.ReferenceEntry{}, latestEntry) {
+		assert.Equal(t, Ref, latestEntry.(*rsl.ReferenceEntry).RefName)
+	}
+
+	featureEnv := createSummary(t, "refs/heads/feature", featureCommitID)
+	err = StoreVerificationSummary(repo, featureEnv, false)
+	assert.Nil(t, err)
+
+	env, err := LoadVerificationSummary(context.Background(), repo, "refs/heads/main", mainCommitID)
+	assert.Nil(t, err)
+	assert.Equal(t, mainEnv, env)
+
+	env, err = LoadVerificationSummary(context.Background(), repo, "refs/heads/feature", featureCommitID)
+	assert.Nil(t, err)
+	assert.Equal(t, featureEnv, env)
+
+	_, err = LoadVerificationSummary(context.Background(), repo, "refs/heads/feature", mainCommitID)
+	assert.ErrorIs(t, err, ErrAttestationNotFound)
+
+	// A newer verification summary for the same commit replaces the previous
+	// one
+	newMainEnv := createSummary(t, "refs/heads/main", mainCommitID)
+	err = StoreVerificationSummary(repo, newMainEnv, false)
+	assert.Nil(t, err)
+
+	env, err = LoadVerificationSummary(context.Background(), repo, "refs/heads/main", mainCommitID)
+	assert.Nil(t, err)
+	assert.Equal(t, newMainEnv, env)
+}
+
+func loadTestSigner(t *testing.T, keyName string) sslibdsse.SignerVerifier {
+	t.Helper()
+
+	keyBytes, err := os.ReadFile(filepath.Join("test-data", keyName))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signer, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(keyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return signer
+}
@@ -0,0 +1,152 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package attestations
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing"
+	"github.com/gittuf/gittuf/internal/version"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+)
+
+const (
+	// StatementType is the type of in-toto attestation statements.
+	StatementType = "https://in-toto.io/Statement/v1"
+
+	// PayloadType is the DSSE payload type of in-toto attestations.
+	PayloadType = "application/vnd.in-toto+json"
+
+	// VerificationSummaryPredicateType is the predicate type of SLSA
+	// verification summary attestations (VSAs).
+	VerificationSummaryPredicateType = "https://slsa.dev/verification_summary/v1"
+
+	// VerifierID identifies gittuf as the verifier in the VSAs it issues.
+	VerifierID = "https://gittuf.dev/verifier"
+
+	// DigestAlgorithmGitCommit is the in-toto digest algorithm for Git commit
+	// IDs.
+	DigestAlgorithmGitCommit = "gitCommit"
+
+	VerificationResultPassed = "PASSED"
+	VerificationResultFailed = "FAILED"
+)
+
+var (
+	ErrInvalidVerificationSummary = errors.New("envelope does not contain a valid verification summary attestation")
+)
+
+// Statement is an in-toto attestation statement whose predicate is a
+// verification summary.
+type Statement struct {
+	Type          string                `json:"_type"`
+	Subject       []*ResourceDescriptor `json:"subject"`
+	PredicateType string                `json:"predicateType"`
+	Predicate     *VerificationSummary  `json:"predicate"`
+}
+
+// ResourceDescriptor describes a resource, such as a commit, in an in-toto
+// attestation.
+type ResourceDescriptor struct {
+	Name   string            `json:"name,omitempty"`
+	URI    string            `json:"uri,omitempty"`
+	Digest map[string]string `json:"digest"`
+}
+
+// Verifier identifies the entity that performed the verification recorded in
+// a verification summary.
+type Verifier struct {
+	ID      string            `json:"id"`
+	Version map[string]string `json:"version,omitempty"`
+}
+
+// VerificationSummary is the predicate of a SLSA verification summary
+// attestation. It records that the subject was verified against a policy by a
+// verifier, and the result of the verification.
+type VerificationSummary struct {
+	Verifier           *Verifier             `json:"verifier"`
+	TimeVerified       time.Time             `json:"timeVerified"`
+	ResourceURI        string                `json:"resourceUri"`
+	Policy             *ResourceDescriptor   `json:"policy"`
+	InputAttestations  []*ResourceDescriptor `json:"inputAttestations,omitempty"`
+	VerificationResult string                `json:"verificationResult"`
+	VerifiedLevels     []string              `json:"verifiedLevels"`
+}
+
+// NewVerificationSummary returns a verification summary attestation statement
+// recording that the commit at the tip of the ref passed verification against
+// the gittuf policy commit policyCommitID in policyRefName.
+func NewVerificationSummary(refName string, commitID plumbing.Hash, policyRefName string, policyCommitID plumbing.Hash, timeVerified time.Time) *Statement {
+	return &Statement{
+		Type: StatementType,
+		Subject: []*ResourceDescriptor{{
+			Name:   refName,
+			Digest: map[string]string{DigestAlgorithmGitCommit: commitID.String()},
+		}},
+		PredicateType: VerificationSummaryPredicateType,
+		Predicate: &VerificationSummary{
+			Verifier: &Verifier{
+				ID:      VerifierID,
+				Version: map[string]string{"gittuf": version.GetVersion()},
+			},
+			TimeVerified: timeVerified.UTC(),
+			ResourceURI:  refName,
+			Policy: &ResourceDescriptor{
+				Name:   policyRefName,
+				Digest: map[string]string{DigestAlgorithmGitCommit: policyCommitID.String()},
+			},
+			VerificationResult: VerificationResultPassed,
+			VerifiedLevels:     []string{},
+		},
+	}
+}
+
+// SignVerificationSummary creates a DSSE envelope for the verification summary
+// attestation statement and signs it using the signer.
+func SignVerificationSummary(ctx context.Context, statement *Statement, signer sslibdsse.SignerVerifier) (*sslibdsse.Envelope, error) {
+	statementBytes, err := json.Marshal(statement)
+	if err != nil {
+		return nil, err
+	}
+
+	env := &sslibdsse.Envelope{
+		Signatures:  []sslibdsse.Signature{},
+		PayloadType: PayloadType,
+		Payload:     base64.StdEncoding.EncodeToString(statementBytes),
+	}
+
+	return dsse.SignEnvelope(ctx, env, signer)
+}
+
+// GetVerificationSummary returns the verification summary attestation
+// statement in the envelope. Note that the envelope's signatures are not
+// verified.
+func GetVerificationSummary(env *sslibdsse.Envelope) (*Statement, error) {
+	if env.PayloadType != PayloadType {
+		return nil, ErrInvalidVerificationSummary
+	}
+
+	statementBytes, err := env.DecodeB64Payload()
+	if err != nil {
+		return nil, errors.Join(ErrInvalidVerificationSummary, err)
+	}
+
+	statement := &Statement{}
+	if err := json.Unmarshal(statementBytes, statement); err != nil {
+		return nil, errors.Join(ErrInvalidVerificationSummary, err)
+	}
+
+	if statement.Type != StatementType || statement.PredicateType != VerificationSummaryPredicateType || statement.Predicate == nil {
+		return nil, ErrInvalidVerificationSummary
+	}
+	if len(statement.Subject) != 1 || len(statement.Subject[0].Name) == 0 || len(statement.Subject[0].Digest[DigestAlgorithmGitCommit]) == 0 {
+		return nil, ErrInvalidVerificationSummary
+	}
+
+	return statement, nil
+}
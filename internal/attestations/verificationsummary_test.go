@@ -0,0 +1,78 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package attestations
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerificationSummary(t *testing.T) {
+	refName := "refs/heads/main"
+	commitID := plumbing.NewHash("1111111111111111111111111111111111111111")
+	policyCommitID := plumbing.NewHash("2222222222222222222222222222222222222222")
+	timeVerified := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	statement := NewVerificationSummary(refName, commitID, "refs/gittuf/policy", policyCommitID, timeVerified)
+	assert.Equal(t, StatementType, statement.Type)
+	assert.Equal(t, VerificationSummaryPredicateType, statement.PredicateType)
+	assert.Equal(t, []*ResourceDescriptor{{Name: refName, Digest: map[string]string{DigestAlgorithmGitCommit: commitID.String()}}}, statement.Subject)
+	assert.Equal(t, VerifierID, statement.Predicate.Verifier.ID)
+	assert.Contains(t, statement.Predicate.Verifier.Version, "gittuf")
+	assert.Equal(t, timeVerified, statement.Predicate.TimeVerified)
+	assert.Equal(t, refName, statement.Predicate.ResourceURI)
+	assert.Equal(t, &ResourceDescriptor{Name: "refs/gittuf/policy", Digest: map[string]string{DigestAlgorithmGitCommit: policyCommitID.String()}}, statement.Predicate.Policy)
+	assert.Equal(t, VerificationResultPassed, statement.Predicate.VerificationResult)
+
+	signer := loadTestSigner(t, "test-key")
+
+	env, err := SignVerificationSummary(context.Background(), statement, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, PayloadType, env.PayloadType)
+
+	err = dsse.VerifyEnvelope(context.Background(), env, []sslibdsse.Verifier{signer}, 1)
+	assert.Nil(t, err)
+
+	// The statement survives a round trip through the envelope's encoding
+	envBytes, err := json.Marshal(env)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decodedEnv := &sslibdsse.Envelope{}
+	if err := json.Unmarshal(envBytes, decodedEnv); err != nil {
+		t.Fatal(err)
+	}
+
+	decodedStatement, err := GetVerificationSummary(decodedEnv)
+	assert.Nil(t, err)
+	assert.Equal(t, statement, decodedStatement)
+
+	t.Run("not a verification summary", func(t *testing.T) {
+		env, err := dsse.CreateEnvelope(statement)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = GetVerificationSummary(env)
+		assert.ErrorIs(t, err, ErrInvalidVerificationSummary)
+
+		statement := NewVerificationSummary(refName, commitID, "refs/gittuf/policy", policyCommitID, timeVerified)
+		statement.PredicateType = "https://slsa.dev/provenance/v1"
+		env, err = SignVerificationSummary(context.Background(), statement, signer)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = GetVerificationSummary(env)
+		assert.ErrorIs(t, err, ErrInvalidVerificationSummary)
+	})
+}
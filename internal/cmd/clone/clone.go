@@ -3,12 +3,18 @@
 package clone
 
 import (
+	"github.com/gittuf/gittuf/internal/policy"
 	"github.com/gittuf/gittuf/internal/repository"
 	"github.com/spf13/cobra"
 )
 
 type options struct {
-	branch string
+	branch               string
+	filter               string
+	expectedPolicyCommit string
+	expectedRootKeys     []string
+	resume               bool
+	abort                bool
 }
 
 func (o *options) AddFlags(cmd *cobra.Command) {
@@ -19,6 +25,43 @@ func (o *options) AddFlags(cmd *cobra.Command) {
 		"",
 		"Specify branch to check out",
 	)
+
+	cmd.Flags().StringVar(
+		&o.filter,
+		"filter",
+		"",
+		"Perform a partial clone using the specified object filter, such as blob:none (gittuf refs are always fetched in full)",
+	)
+
+	cmd.Flags().StringVar(
+		&o.expectedPolicyCommit,
+		"expected-policy-commit",
+		"",
+		"policy commit, obtained out of band, that the cloned policy must be or descend from",
+	)
+
+	cmd.Flags().StringArrayVar(
+		&o.expectedRootKeys,
+		"expected-root-key",
+		[]string{},
+		"ID of a root key, obtained out of band, that must have signed the cloned root of trust",
+	)
+
+	cmd.Flags().BoolVar(
+		&o.resume,
+		"resume",
+		false,
+		"keep the progress of the clone if it is interrupted, and resume a previously interrupted clone",
+	)
+
+	cmd.Flags().BoolVar(
+		&o.abort,
+		"abort",
+		false,
+		"remove the directory of an incomplete clone of the repository",
+	)
+
+	cmd.MarkFlagsMutuallyExclusive("resume", "abort")
 }
 
 func (o *options) Run(cmd *cobra.Command, args []string) error {
@@ -26,7 +69,26 @@ func (o *options) Run(cmd *cobra.Command, args []string) error {
 	if len(args) > 1 {
 		dir = args[1]
 	}
-	_, err := repository.Clone(cmd.Context(), args[0], dir, o.branch)
+
+	if o.abort {
+		return repository.AbortClone(args[0], dir)
+	}
+
+	opts := []repository.CloneOption{}
+	if len(o.filter) > 0 {
+		opts = append(opts, repository.WithPartialClone(o.filter))
+	}
+	if o.resume {
+		opts = append(opts, repository.WithResumableClone())
+	}
+	if len(o.expectedPolicyCommit) > 0 || len(o.expectedRootKeys) > 0 {
+		opts = append(opts, repository.WithExpectedPolicy(&policy.PolicyPins{
+			PolicyCommitID: o.expectedPolicyCommit,
+			RootKeyIDs:     o.expectedRootKeys,
+		}))
+	}
+
+	_, err := repository.Clone(cmd.Context(), args[0], dir, o.branch, opts...)
 	return err
 }
 
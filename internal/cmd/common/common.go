@@ -9,14 +9,19 @@ import (
 	"os/exec"
 	"strings"
 
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/gittuf/gittuf/internal/signerverifier/age"
 	"github.com/gittuf/gittuf/internal/signerverifier/gpg"
+	"github.com/gittuf/gittuf/internal/signerverifier/minisign"
 	sslibsv "github.com/secure-systems-lab/go-securesystemslib/signerverifier"
 )
 
 const (
-	GPGKeyPrefix = "gpg:"
-	FulcioPrefix = "fulcio:"
-	EvalModeKey  = "GITTUF_EVAL"
+	GPGKeyPrefix      = "gpg:"
+	FulcioPrefix      = "fulcio:"
+	AgeKeyPrefix      = "age:"
+	MinisignKeyPrefix = "minisign:"
+	EvalModeKey       = "GITTUF_EVAL"
 )
 
 var ErrNotInEvalMode = fmt.Errorf("this feature is only available with eval mode, and can UNDERMINE repository security; override by setting %s=1", EvalModeKey)
@@ -69,6 +74,36 @@ func ReadKeyBytes(key string) ([]byte, error) {
 		if err != nil {
 			return nil, err
 		}
+	case strings.HasPrefix(key, AgeKeyPrefix):
+		identityBytes, err := os.ReadFile(strings.TrimPrefix(key, AgeKeyPrefix))
+		if err != nil {
+			return nil, err
+		}
+
+		ageKey, err := age.LoadPublicKey(identityBytes)
+		if err != nil {
+			return nil, err
+		}
+
+		kb, err = json.Marshal(ageKey)
+		if err != nil {
+			return nil, err
+		}
+	case strings.HasPrefix(key, MinisignKeyPrefix):
+		publicKeyBytes, err := os.ReadFile(strings.TrimPrefix(key, MinisignKeyPrefix))
+		if err != nil {
+			return nil, err
+		}
+
+		minisignKey, err := minisign.LoadMinisignPublicKeyFromBytes(publicKeyBytes)
+		if err != nil {
+			return nil, err
+		}
+
+		kb, err = json.Marshal(minisignKey)
+		if err != nil {
+			return nil, err
+		}
 	default:
 		kb, err = os.ReadFile(key)
 		if err != nil {
@@ -82,3 +117,14 @@ func ReadKeyBytes(key string) ([]byte, error) {
 func EvalMode() bool {
 	return os.Getenv(EvalModeKey) == "1"
 }
+
+// RemoteName returns the remote passed in as the command's argument, if any.
+// Otherwise, the remote configured for the repository's gittuf refs is
+// returned.
+func RemoteName(repo *repository.Repository, args []string) (string, error) {
+	if len(args) > 0 {
+		return args[0], nil
+	}
+
+	return repo.GetGittufRemote()
+}
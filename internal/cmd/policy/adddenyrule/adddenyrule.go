@@ -0,0 +1,71 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package adddenyrule
+
+import (
+	"os"
+
+	"github.com/gittuf/gittuf/internal/cmd/policy/persistent"
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	p            *persistent.Options
+	policyName   string
+	ruleName     string
+	rulePatterns []string
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&o.policyName,
+		"policy-name",
+		policy.TargetsRoleName,
+		"policy file to add rule to",
+	)
+
+	cmd.Flags().StringVar(
+		&o.ruleName,
+		"rule-name",
+		"",
+		"name of rule",
+	)
+	cmd.MarkFlagRequired("rule-name") //nolint:errcheck
+
+	cmd.Flags().StringArrayVar(
+		&o.rulePatterns,
+		"rule-pattern",
+		[]string{},
+		"patterns used to identify namespaces rule applies to",
+	)
+	cmd.MarkFlagRequired("rule-pattern") //nolint:errcheck
+}
+
+func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	repo, err := repository.LoadRepository(".")
+	if err != nil {
+		return err
+	}
+
+	keyBytes, err := os.ReadFile(o.p.SigningKey)
+	if err != nil {
+		return err
+	}
+
+	return repo.AddDenyRule(cmd.Context(), keyBytes, o.policyName, o.ruleName, o.rulePatterns, true)
+}
+
+func New(persistent *persistent.Options) *cobra.Command {
+	o := &options{p: persistent}
+	cmd := &cobra.Command{
+		Use:   "add-deny-rule",
+		Short: "Add a new deny rule to a policy file",
+		Long:  `This command allows users to add a new deny rule to the specified policy file. By default, the main policy file is selected. A deny rule rejects all changes to the namespaces it matches, unless a prior rule in the policy matches the namespace and terminates the search for rules.`,
+		RunE:  o.Run,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
@@ -36,7 +36,7 @@ func (o *options) AddFlags(cmd *cobra.Command) {
 }
 
 func (o *options) Run(cmd *cobra.Command, _ []string) error {
-	repo, err := repository.LoadRepository()
+	repo, err := repository.LoadRepository(".")
 	if err != nil {
 		return err
 	}
@@ -64,7 +64,7 @@ func New(persistent *persistent.Options) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "add-key",
 		Short: "Add a trusted key to a policy file",
-		Long:  `This command allows users to add a trusted key to the specified policy file. By default, the main policy file is selected. Note that the keys can be specified from disk using the custom securesystemslib format, from the GPG keyring using the "gpg:<fingerprint>" format, or as a Sigstore identity as "fulcio:<identity>::<issuer>".`,
+		Long:  `This command allows users to add a trusted key to the specified policy file. By default, the main policy file is selected. Note that the keys can be specified from disk using the custom securesystemslib format, from the GPG keyring using the "gpg:<fingerprint>" format, as a Sigstore identity as "fulcio:<identity>::<issuer>", or as a minisign or signify public key file using the "minisign:<path>" format.`,
 		RunE:  o.Run,
 	}
 	o.AddFlags(cmd)
@@ -54,7 +54,7 @@ func (o *options) AddFlags(cmd *cobra.Command) {
 }
 
 func (o *options) Run(cmd *cobra.Command, _ []string) error {
-	repo, err := repository.LoadRepository()
+	repo, err := repository.LoadRepository(".")
 	if err != nil {
 		return err
 	}
@@ -82,7 +82,7 @@ func New(persistent *persistent.Options) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "add-rule",
 		Short: "Add a new rule to a policy file",
-		Long:  `This command allows users to add a new rule to the specified policy file. By default, the main policy file is selected. Note that authorized keys can be specified from disk using the custom securesystemslib format, from the GPG keyring using the "gpg:<fingerprint>" format, or as a Sigstore identity as "fulcio:<identity>::<issuer>".`,
+		Long:  `This command allows users to add a new rule to the specified policy file. By default, the main policy file is selected. Note that authorized keys can be specified from disk using the custom securesystemslib format, from the GPG keyring using the "gpg:<fingerprint>" format, as a Sigstore identity as "fulcio:<identity>::<issuer>", or as a minisign or signify public key file using the "minisign:<path>" format.`,
 		RunE:  o.Run,
 	}
 	o.AddFlags(cmd)
@@ -0,0 +1,102 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package listrules
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+const indent = "    "
+
+type options struct {
+	json bool
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(
+		&o.json,
+		"json",
+		false,
+		"print the rules as JSON",
+	)
+}
+
+func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	repo, err := repository.LoadRepository(".")
+	if err != nil {
+		return err
+	}
+
+	tree, err := repo.ListRules(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	if o.json {
+		treeBytes, err := json.MarshalIndent(tree, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(string(treeBytes))
+		return nil
+	}
+
+	for _, node := range tree {
+		fmt.Printf("Policy '%s' (threshold %d)\n", node.Name, node.Threshold)
+		fmt.Printf("%skeys: %s\n", indent, strings.Join(node.KeyIDs, ", "))
+		printRules(node.Children, 1)
+	}
+
+	return nil
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:   "list-rules",
+		Short: "List the rules in the policy",
+		Long:  `This command lists the rules in the repository's current policy as a tree. Each top level policy file is listed with its rules, and rules that delegate to another policy file are listed with the rules of that policy file beneath them. Rules are listed in the order they are considered during verification.`,
+		RunE:  o.Run,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
+
+func printRules(nodes []*policy.DelegationTreeNode, depth int) {
+	prefix := strings.Repeat(indent, depth)
+
+	for _, node := range nodes {
+		attributes := []string{}
+		if node.Deny {
+			attributes = append(attributes, "deny")
+		} else {
+			attributes = append(attributes, fmt.Sprintf("threshold %d", node.Threshold))
+		}
+		if node.Terminating {
+			attributes = append(attributes, "terminating")
+		}
+
+		fmt.Printf("%sRule '%s' (%s)\n", prefix, node.Name, strings.Join(attributes, ", "))
+		fmt.Printf("%s%spaths: %s\n", prefix, indent, strings.Join(node.Paths, ", "))
+		if len(node.Refs) > 0 {
+			fmt.Printf("%s%srefs: %s\n", prefix, indent, strings.Join(node.Refs, ", "))
+		}
+		if len(node.KeyIDs) > 0 {
+			fmt.Printf("%s%skeys: %s\n", prefix, indent, strings.Join(node.KeyIDs, ", "))
+		}
+		if node.Expires != nil {
+			fmt.Printf("%s%sexpires: %s\n", prefix, indent, node.Expires.Format(time.RFC3339))
+		}
+
+		printRules(node.Children, depth+1)
+	}
+}
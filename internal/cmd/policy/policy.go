@@ -3,11 +3,21 @@
 package policy
 
 import (
+	"github.com/gittuf/gittuf/internal/cmd/policy/adddenyrule"
 	"github.com/gittuf/gittuf/internal/cmd/policy/addkey"
 	"github.com/gittuf/gittuf/internal/cmd/policy/addrule"
 	i "github.com/gittuf/gittuf/internal/cmd/policy/init"
+	"github.com/gittuf/gittuf/internal/cmd/policy/listrules"
 	"github.com/gittuf/gittuf/internal/cmd/policy/persistent"
 	"github.com/gittuf/gittuf/internal/cmd/policy/removerule"
+	"github.com/gittuf/gittuf/internal/cmd/policy/setallowedhashes"
+	"github.com/gittuf/gittuf/internal/cmd/policy/setkeyvalidity"
+	"github.com/gittuf/gittuf/internal/cmd/policy/setmindistinctsigners"
+	"github.com/gittuf/gittuf/internal/cmd/policy/setrequiresignedcommits"
+	"github.com/gittuf/gittuf/internal/cmd/policy/setruleexpiry"
+	"github.com/gittuf/gittuf/internal/cmd/policy/setrulerefs"
+	"github.com/gittuf/gittuf/internal/cmd/policy/setrulethreshold"
+	"github.com/gittuf/gittuf/internal/cmd/policy/setverifymergecommits"
 	"github.com/gittuf/gittuf/internal/cmd/trustpolicy/remote"
 	"github.com/spf13/cobra"
 )
@@ -21,10 +31,20 @@ func New() *cobra.Command {
 	o.AddPersistentFlags(cmd)
 
 	cmd.AddCommand(i.New(o))
+	cmd.AddCommand(adddenyrule.New(o))
 	cmd.AddCommand(addkey.New(o))
 	cmd.AddCommand(addrule.New(o))
+	cmd.AddCommand(listrules.New())
 	cmd.AddCommand(remote.New())
 	cmd.AddCommand(removerule.New(o))
+	cmd.AddCommand(setallowedhashes.New(o))
+	cmd.AddCommand(setkeyvalidity.New(o))
+	cmd.AddCommand(setmindistinctsigners.New(o))
+	cmd.AddCommand(setrequiresignedcommits.New(o))
+	cmd.AddCommand(setruleexpiry.New(o))
+	cmd.AddCommand(setrulerefs.New(o))
+	cmd.AddCommand(setrulethreshold.New(o))
+	cmd.AddCommand(setverifymergecommits.New(o))
 
 	return cmd
 }
@@ -35,7 +35,7 @@ func (o *options) AddFlags(cmd *cobra.Command) {
 }
 
 func (o *options) Run(cmd *cobra.Command, _ []string) error {
-	repo, err := repository.LoadRepository()
+	repo, err := repository.LoadRepository(".")
 	if err != nil {
 		return err
 	}
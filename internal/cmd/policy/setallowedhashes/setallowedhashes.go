@@ -0,0 +1,70 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package setallowedhashes
+
+import (
+	"os"
+
+	"github.com/gittuf/gittuf/internal/cmd/policy/persistent"
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	p             *persistent.Options
+	policyName    string
+	ruleName      string
+	allowedHashes []string
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&o.policyName,
+		"policy-name",
+		policy.TargetsRoleName,
+		"policy file containing rule",
+	)
+
+	cmd.Flags().StringVar(
+		&o.ruleName,
+		"rule-name",
+		"",
+		"name of rule",
+	)
+	cmd.MarkFlagRequired("rule-name") //nolint:errcheck
+
+	cmd.Flags().StringArrayVar(
+		&o.allowedHashes,
+		"allowed-hash",
+		[]string{},
+		"Git blob ID that files protected by the rule are allowed to have",
+	)
+}
+
+func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	repo, err := repository.LoadRepository(".")
+	if err != nil {
+		return err
+	}
+
+	keyBytes, err := os.ReadFile(o.p.SigningKey)
+	if err != nil {
+		return err
+	}
+
+	return repo.SetAllowedHashes(cmd.Context(), keyBytes, o.policyName, o.ruleName, o.allowedHashes, true)
+}
+
+func New(persistent *persistent.Options) *cobra.Command {
+	o := &options{p: persistent}
+	cmd := &cobra.Command{
+		Use:   "set-allowed-hashes",
+		Short: "Pin the contents of files protected by a rule",
+		Long:  `This command allows users to pin the contents of the files protected by a rule in the specified policy file to a set of Git blob IDs. By default, the main policy file is selected. Changes that set a protected file's contents to a blob ID that isn't allowed are rejected. If no hashes are specified, the rule's pin is removed.`,
+		RunE:  o.Run,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
@@ -0,0 +1,95 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package setkeyvalidity
+
+import (
+	"os"
+	"time"
+
+	"github.com/gittuf/gittuf/internal/cmd/policy/persistent"
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	p          *persistent.Options
+	policyName string
+	keyID      string
+	notBefore  string
+	notAfter   string
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&o.policyName,
+		"policy-name",
+		policy.TargetsRoleName,
+		"policy file containing key",
+	)
+
+	cmd.Flags().StringVar(
+		&o.keyID,
+		"key-id",
+		"",
+		"ID of key",
+	)
+	cmd.MarkFlagRequired("key-id") //nolint:errcheck
+
+	cmd.Flags().StringVar(
+		&o.notBefore,
+		"not-before",
+		"",
+		"time (RFC 3339) before which commits signed by the key are not trusted",
+	)
+
+	cmd.Flags().StringVar(
+		&o.notAfter,
+		"not-after",
+		"",
+		"time (RFC 3339) after which commits signed by the key are not trusted",
+	)
+}
+
+func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	var notBefore, notAfter time.Time
+	if len(o.notBefore) > 0 {
+		t, err := time.Parse(time.RFC3339, o.notBefore)
+		if err != nil {
+			return err
+		}
+		notBefore = t
+	}
+	if len(o.notAfter) > 0 {
+		t, err := time.Parse(time.RFC3339, o.notAfter)
+		if err != nil {
+			return err
+		}
+		notAfter = t
+	}
+
+	repo, err := repository.LoadRepository(".")
+	if err != nil {
+		return err
+	}
+
+	keyBytes, err := os.ReadFile(o.p.SigningKey)
+	if err != nil {
+		return err
+	}
+
+	return repo.SetKeyValidityWindow(cmd.Context(), keyBytes, o.policyName, o.keyID, notBefore, notAfter, true)
+}
+
+func New(persistent *persistent.Options) *cobra.Command {
+	o := &options{p: persistent}
+	cmd := &cobra.Command{
+		Use:   "set-key-validity",
+		Short: "Restrict a key to signing commits created within a period",
+		Long:  `This command allows users to restrict a key authorized by the rules in the specified policy file to signing commits created between --not-before and --not-after. Either bound may be omitted. If both are omitted, the key's validity window is removed. By default, the main policy file is selected.`,
+		RunE:  o.Run,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
@@ -0,0 +1,78 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package setmindistinctsigners
+
+import (
+	"os"
+
+	"github.com/gittuf/gittuf/internal/cmd/policy/persistent"
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	p          *persistent.Options
+	policyName string
+	ruleName   string
+	count      int
+	window     int
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&o.policyName,
+		"policy-name",
+		policy.TargetsRoleName,
+		"policy file containing rule",
+	)
+
+	cmd.Flags().StringVar(
+		&o.ruleName,
+		"rule-name",
+		"",
+		"name of rule",
+	)
+	cmd.MarkFlagRequired("rule-name") //nolint:errcheck
+
+	cmd.Flags().IntVar(
+		&o.count,
+		"count",
+		0,
+		"minimum number of distinct authorized keys that must sign the recent commits",
+	)
+
+	cmd.Flags().IntVar(
+		&o.window,
+		"window",
+		0,
+		"number of recent commits to inspect",
+	)
+}
+
+func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	repo, err := repository.LoadRepository(".")
+	if err != nil {
+		return err
+	}
+
+	keyBytes, err := os.ReadFile(o.p.SigningKey)
+	if err != nil {
+		return err
+	}
+
+	return repo.SetMinDistinctSigners(cmd.Context(), keyBytes, o.policyName, o.ruleName, o.count, o.window, true)
+}
+
+func New(persistent *persistent.Options) *cobra.Command {
+	o := &options{p: persistent}
+	cmd := &cobra.Command{
+		Use:   "set-min-distinct-signers",
+		Short: "Require multiple distinct signers over the recent history of protected refs",
+		Long:  `This command allows users to require that the last window commits of the refs protected by a rule in the specified policy file are signed by at least count distinct keys authorized by the rule. By default, the main policy file is selected. If the count is zero, the rule's requirement is removed.`,
+		RunE:  o.Run,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
@@ -0,0 +1,70 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package setrequiresignedcommits
+
+import (
+	"os"
+
+	"github.com/gittuf/gittuf/internal/cmd/policy/persistent"
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	p          *persistent.Options
+	policyName string
+	ruleName   string
+	disable    bool
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&o.policyName,
+		"policy-name",
+		policy.TargetsRoleName,
+		"policy file containing rule",
+	)
+
+	cmd.Flags().StringVar(
+		&o.ruleName,
+		"rule-name",
+		"",
+		"name of rule",
+	)
+	cmd.MarkFlagRequired("rule-name") //nolint:errcheck
+
+	cmd.Flags().BoolVar(
+		&o.disable,
+		"disable",
+		false,
+		"stop requiring signed commits for the rule",
+	)
+}
+
+func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	repo, err := repository.LoadRepository(".")
+	if err != nil {
+		return err
+	}
+
+	keyBytes, err := os.ReadFile(o.p.SigningKey)
+	if err != nil {
+		return err
+	}
+
+	return repo.SetRequireSignedCommits(cmd.Context(), keyBytes, o.policyName, o.ruleName, !o.disable, true)
+}
+
+func New(persistent *persistent.Options) *cobra.Command {
+	o := &options{p: persistent}
+	cmd := &cobra.Command{
+		Use:   "set-require-signed-commits",
+		Short: "Require every commit introduced in protected refs to be signed by an authorized key",
+		Long:  `This command allows users to require that every commit introduced in the refs protected by a rule in the specified policy file is signed by one of the rule's authorized keys, rather than only the RSL entry that records the change. By default, the main policy file is selected. If --disable is set, the rule's requirement is removed.`,
+		RunE:  o.Run,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
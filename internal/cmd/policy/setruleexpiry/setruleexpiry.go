@@ -0,0 +1,80 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package setruleexpiry
+
+import (
+	"os"
+	"time"
+
+	"github.com/gittuf/gittuf/internal/cmd/policy/persistent"
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	p          *persistent.Options
+	policyName string
+	ruleName   string
+	expires    string
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&o.policyName,
+		"policy-name",
+		policy.TargetsRoleName,
+		"policy file containing rule",
+	)
+
+	cmd.Flags().StringVar(
+		&o.ruleName,
+		"rule-name",
+		"",
+		"name of rule",
+	)
+	cmd.MarkFlagRequired("rule-name") //nolint:errcheck
+
+	cmd.Flags().StringVar(
+		&o.expires,
+		"expires",
+		"",
+		"time (RFC 3339) after which the rule no longer applies",
+	)
+}
+
+func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	var expires time.Time
+	if len(o.expires) > 0 {
+		t, err := time.Parse(time.RFC3339, o.expires)
+		if err != nil {
+			return err
+		}
+		expires = t
+	}
+
+	repo, err := repository.LoadRepository(".")
+	if err != nil {
+		return err
+	}
+
+	keyBytes, err := os.ReadFile(o.p.SigningKey)
+	if err != nil {
+		return err
+	}
+
+	return repo.SetRuleExpiry(cmd.Context(), keyBytes, o.policyName, o.ruleName, expires, true)
+}
+
+func New(persistent *persistent.Options) *cobra.Command {
+	o := &options{p: persistent}
+	cmd := &cobra.Command{
+		Use:   "set-rule-expiry",
+		Short: "Set the time after which a rule no longer applies",
+		Long:  `This command allows users to set the time, passed using --expires, after which a rule in the specified policy file no longer applies. An expired rule does not authorize any keys, even if the policy file itself has not expired. If --expires is omitted, the rule's expiry is removed. By default, the main policy file is selected.`,
+		RunE:  o.Run,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
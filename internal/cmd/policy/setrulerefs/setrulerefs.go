@@ -0,0 +1,70 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package setrulerefs
+
+import (
+	"os"
+
+	"github.com/gittuf/gittuf/internal/cmd/policy/persistent"
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	p          *persistent.Options
+	policyName string
+	ruleName   string
+	refs       []string
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&o.policyName,
+		"policy-name",
+		policy.TargetsRoleName,
+		"policy file containing rule",
+	)
+
+	cmd.Flags().StringVar(
+		&o.ruleName,
+		"rule-name",
+		"",
+		"name of rule",
+	)
+	cmd.MarkFlagRequired("rule-name") //nolint:errcheck
+
+	cmd.Flags().StringArrayVar(
+		&o.refs,
+		"ref",
+		[]string{},
+		"pattern of Git refs the rule applies to, such as refs/heads/prod",
+	)
+}
+
+func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	repo, err := repository.LoadRepository(".")
+	if err != nil {
+		return err
+	}
+
+	keyBytes, err := os.ReadFile(o.p.SigningKey)
+	if err != nil {
+		return err
+	}
+
+	return repo.SetRuleRefs(cmd.Context(), keyBytes, o.policyName, o.ruleName, o.refs, true)
+}
+
+func New(persistent *persistent.Options) *cobra.Command {
+	o := &options{p: persistent}
+	cmd := &cobra.Command{
+		Use:   "set-rule-refs",
+		Short: "Restrict a rule protecting file paths to changes made on certain refs",
+		Long:  `This command allows users to restrict a rule in the specified policy file that protects file paths so that it only applies to changes made on the Git refs matching the patterns passed using --ref. By default, the main policy file is selected. If no patterns are passed, the rule's restriction is removed.`,
+		RunE:  o.Run,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
@@ -0,0 +1,70 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package setrulethreshold
+
+import (
+	"os"
+
+	"github.com/gittuf/gittuf/internal/cmd/policy/persistent"
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	p          *persistent.Options
+	policyName string
+	ruleName   string
+	threshold  int
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&o.policyName,
+		"policy-name",
+		policy.TargetsRoleName,
+		"policy file containing rule",
+	)
+
+	cmd.Flags().StringVar(
+		&o.ruleName,
+		"rule-name",
+		"",
+		"name of rule",
+	)
+	cmd.MarkFlagRequired("rule-name") //nolint:errcheck
+
+	cmd.Flags().IntVar(
+		&o.threshold,
+		"threshold",
+		1,
+		"number of distinct authorized keys that must sign a change",
+	)
+}
+
+func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	repo, err := repository.LoadRepository(".")
+	if err != nil {
+		return err
+	}
+
+	keyBytes, err := os.ReadFile(o.p.SigningKey)
+	if err != nil {
+		return err
+	}
+
+	return repo.SetRuleThreshold(cmd.Context(), keyBytes, o.policyName, o.ruleName, o.threshold, true)
+}
+
+func New(persistent *persistent.Options) *cobra.Command {
+	o := &options{p: persistent}
+	cmd := &cobra.Command{
+		Use:   "set-rule-threshold",
+		Short: "Set the number of authorized keys that must sign changes protected by a rule",
+		Long:  `This command allows users to set the number of distinct keys authorized by a rule in the specified policy file that must sign a change. By default, the main policy file is selected. For rules protecting Git refs, each commit must carry signatures from the threshold of keys, with additional signers recorded as co-signatures in the commit's "Gittuf-Co-Signature" trailers.`,
+		RunE:  o.Run,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
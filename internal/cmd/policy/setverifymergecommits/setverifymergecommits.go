@@ -0,0 +1,70 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package setverifymergecommits
+
+import (
+	"os"
+
+	"github.com/gittuf/gittuf/internal/cmd/policy/persistent"
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	p          *persistent.Options
+	policyName string
+	ruleName   string
+	disable    bool
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&o.policyName,
+		"policy-name",
+		policy.TargetsRoleName,
+		"policy file containing rule",
+	)
+
+	cmd.Flags().StringVar(
+		&o.ruleName,
+		"rule-name",
+		"",
+		"name of rule",
+	)
+	cmd.MarkFlagRequired("rule-name") //nolint:errcheck
+
+	cmd.Flags().BoolVar(
+		&o.disable,
+		"disable",
+		false,
+		"stop verifying merge commits for the rule",
+	)
+}
+
+func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	repo, err := repository.LoadRepository(".")
+	if err != nil {
+		return err
+	}
+
+	keyBytes, err := os.ReadFile(o.p.SigningKey)
+	if err != nil {
+		return err
+	}
+
+	return repo.SetVerifyMergeCommits(cmd.Context(), keyBytes, o.policyName, o.ruleName, !o.disable, true)
+}
+
+func New(persistent *persistent.Options) *cobra.Command {
+	o := &options{p: persistent}
+	cmd := &cobra.Command{
+		Use:   "set-verify-merge-commits",
+		Short: "Require merge commits in protected refs to only combine the changes of their parents",
+		Long:  `This command allows users to require that merge commits in the refs protected by a rule in the specified policy file do not introduce changes that are not made by any of their parents. By default, the main policy file is selected. If --disable is set, the rule's requirement is removed.`,
+		RunE:  o.Run,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
@@ -3,6 +3,9 @@
 package root
 
 import (
+	"log/slog"
+	"os"
+
 	"github.com/gittuf/gittuf/internal/cmd/clone"
 	"github.com/gittuf/gittuf/internal/cmd/policy"
 	"github.com/gittuf/gittuf/internal/cmd/rsl"
@@ -11,14 +14,44 @@ import (
 	"github.com/gittuf/gittuf/internal/cmd/verifyref"
 	"github.com/gittuf/gittuf/internal/cmd/verifytag"
 	"github.com/gittuf/gittuf/internal/cmd/version"
+	"github.com/gittuf/gittuf/internal/logging"
 	"github.com/spf13/cobra"
 )
 
+type options struct {
+	verbose bool
+}
+
+func (o *options) AddPersistentFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().BoolVarP(
+		&o.verbose,
+		"verbose",
+		"v",
+		false,
+		"log all verification and sync events to stderr rather than only warnings",
+	)
+}
+
+func (o *options) PreRun(cmd *cobra.Command, _ []string) {
+	// Warnings, such as a verified ref not being protected by the policy, are
+	// always reported
+	level := slog.LevelWarn
+	if o.verbose {
+		level = slog.LevelDebug
+	}
+
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: level}))
+	cmd.SetContext(logging.NewContext(cmd.Context(), logger))
+}
+
 func New() *cobra.Command {
+	o := &options{}
 	cmd := &cobra.Command{
-		Use:   "gittuf",
-		Short: "A security layer for Git repositories, powered by TUF",
+		Use:              "gittuf",
+		Short:            "A security layer for Git repositories, powered by TUF",
+		PersistentPreRun: o.PreRun,
 	}
+	o.AddPersistentFlags(cmd)
 
 	cmd.AddCommand(clone.New())
 	cmd.AddCommand(trust.New())
@@ -0,0 +1,34 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package amend
+
+import (
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct{}
+
+func (o *options) AddFlags(_ *cobra.Command) {}
+
+func (o *options) Run(_ *cobra.Command, args []string) error {
+	repo, err := repository.LoadRepository(".")
+	if err != nil {
+		return err
+	}
+
+	return repo.AmendLatestRSLEntry(args[0], true)
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:   "amend",
+		Short: "Replace the latest RSL entry with the latest state of a Git reference, if the entry has not been pushed",
+		Args:  cobra.ExactArgs(1),
+		RunE:  o.Run,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
@@ -8,8 +8,10 @@ import (
 )
 
 type options struct {
-	skip    bool
-	message string
+	skip       bool
+	forcePush  bool
+	breakGlass bool
+	message    string
 }
 
 func (o *options) AddFlags(cmd *cobra.Command) {
@@ -21,6 +23,20 @@ func (o *options) AddFlags(cmd *cobra.Command) {
 		"mark annotated entries as to be skipped",
 	)
 
+	cmd.Flags().BoolVar(
+		&o.forcePush,
+		"force-push",
+		false,
+		"authorize non-fast-forward ref updates recorded in annotated entries",
+	)
+
+	cmd.Flags().BoolVar(
+		&o.breakGlass,
+		"break-glass",
+		false,
+		"authorize ref updates recorded in annotated entries using an emergency key",
+	)
+
 	cmd.Flags().StringVarP(
 		&o.message,
 		"message",
@@ -32,11 +48,19 @@ func (o *options) AddFlags(cmd *cobra.Command) {
 }
 
 func (o *options) Run(_ *cobra.Command, args []string) error {
-	repo, err := repository.LoadRepository()
+	repo, err := repository.LoadRepository(".")
 	if err != nil {
 		return err
 	}
 
+	if o.forcePush {
+		return repo.RecordRSLForcePushAnnotation(args, o.message, true)
+	}
+
+	if o.breakGlass {
+		return repo.RecordRSLBreakGlassAnnotation(args, o.message, true)
+	}
+
 	return repo.RecordRSLAnnotation(args, o.skip, o.message, true)
 }
 
@@ -12,6 +12,8 @@ import (
 
 type options struct {
 	commitID string
+	delete   bool
+	verify   bool
 }
 
 func (o *options) AddFlags(cmd *cobra.Command) {
@@ -22,10 +24,26 @@ func (o *options) AddFlags(cmd *cobra.Command) {
 		"",
 		fmt.Sprintf("commit ID (eval mode only, set %s=1)", common.EvalModeKey),
 	)
+
+	cmd.Flags().BoolVar(
+		&o.delete,
+		"delete",
+		false,
+		"record the deletion of the Git reference, which must be specified using its fully qualified name",
+	)
+
+	cmd.Flags().BoolVar(
+		&o.verify,
+		"verify",
+		false,
+		"verify the Git reference's latest state against the policy and only record it if verification succeeds",
+	)
+
+	cmd.MarkFlagsMutuallyExclusive("commit", "delete", "verify")
 }
 
-func (o *options) Run(_ *cobra.Command, args []string) error {
-	repo, err := repository.LoadRepository()
+func (o *options) Run(cmd *cobra.Command, args []string) error {
+	repo, err := repository.LoadRepository(".")
 	if err != nil {
 		return err
 	}
@@ -38,6 +56,14 @@ func (o *options) Run(_ *cobra.Command, args []string) error {
 		return repo.RecordRSLEntryForReferenceAtCommit(args[0], o.commitID, true)
 	}
 
+	if o.delete {
+		return repo.RecordRSLEntryForDeletion(args[0], true)
+	}
+
+	if o.verify {
+		return repo.RecordAndVerify(cmd.Context(), args[0], true)
+	}
+
 	return repo.RecordRSLEntryForReference(args[0], true)
 }
 
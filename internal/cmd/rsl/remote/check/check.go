@@ -5,6 +5,7 @@ package check
 import (
 	"fmt"
 
+	"github.com/gittuf/gittuf/internal/cmd/common"
 	"github.com/gittuf/gittuf/internal/repository"
 	"github.com/spf13/cobra"
 )
@@ -13,23 +14,28 @@ type options struct {
 }
 
 func (o *options) Run(cmd *cobra.Command, args []string) error {
-	repo, err := repository.LoadRepository()
+	repo, err := repository.LoadRepository(".")
 	if err != nil {
 		return err
 	}
 
-	hasUpdates, hasDiverged, err := repo.CheckRemoteRSLForUpdates(cmd.Context(), args[0])
+	remoteName, err := common.RemoteName(repo, args)
+	if err != nil {
+		return err
+	}
+
+	hasUpdates, hasDiverged, err := repo.CheckRemoteRSLForUpdates(cmd.Context(), remoteName)
 	if err != nil {
 		return err
 	}
 
 	if hasUpdates {
-		fmt.Printf("RSL at remote %s has updates", args[0])
+		fmt.Printf("RSL at remote %s has updates", remoteName)
 		if hasDiverged {
 			fmt.Printf(" and has diverged from local RSL")
 		}
 	} else {
-		fmt.Printf("RSL at remote %s has no updates", args[0])
+		fmt.Printf("RSL at remote %s has no updates", remoteName)
 	}
 
 	fmt.Println() // Trailing newline
@@ -40,9 +46,9 @@ func (o *options) Run(cmd *cobra.Command, args []string) error {
 func New() *cobra.Command {
 	o := &options{}
 	cmd := &cobra.Command{
-		Use:   "check <remote>",
+		Use:   "check [remote]",
 		Short: "Check remote RSL for updates, for development use only",
-		Args:  cobra.ExactArgs(1),
+		Args:  cobra.MaximumNArgs(1),
 		RunE:  o.Run,
 	}
 
@@ -0,0 +1,77 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package compare
+
+import (
+	"fmt"
+
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+}
+
+func (o *options) Run(cmd *cobra.Command, args []string) error {
+	repo, err := repository.LoadRepository(".")
+	if err != nil {
+		return err
+	}
+
+	authoritativeRemote, err := repo.GetGittufRemote()
+	if err != nil {
+		return err
+	}
+
+	remoteNames := []string{authoritativeRemote}
+	for _, remoteName := range args {
+		if remoteName != authoritativeRemote {
+			remoteNames = append(remoteNames, remoteName)
+		}
+	}
+
+	if _, err := repo.FetchRSLFromRemotes(cmd.Context(), remoteNames); err != nil {
+		return err
+	}
+
+	statuses, err := repo.CompareRSLTrackers(remoteNames)
+	if err != nil {
+		return err
+	}
+
+	for _, remoteName := range remoteNames {
+		status, has := statuses[remoteName]
+		if !has {
+			fmt.Printf("%s: no RSL\n", remoteName)
+			continue
+		}
+
+		switch {
+		case remoteName == authoritativeRemote:
+			fmt.Printf("%s: %s (authoritative)\n", remoteName, status.Tip.String())
+		case status.HasDiverged:
+			fmt.Printf("%s: %s (diverged from %s)\n", remoteName, status.Tip.String(), authoritativeRemote)
+		case status.HasUpdates:
+			fmt.Printf("%s: %s (ahead of %s)\n", remoteName, status.Tip.String(), authoritativeRemote)
+		case status.IsBehind:
+			fmt.Printf("%s: %s (behind %s)\n", remoteName, status.Tip.String(), authoritativeRemote)
+		default:
+			fmt.Printf("%s: %s (matches %s)\n", remoteName, status.Tip.String(), authoritativeRemote)
+		}
+	}
+
+	return nil
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:   "compare <remote>...",
+		Short: "Compare the RSLs of several remotes with the authoritative remote's RSL",
+		Long:  "This command fetches the RSL from each of the specified remotes and from the authoritative remote, which is the remote set using the GITTUF_REMOTE environment variable or the gittuf.remote Git config option, falling back to origin. Each remote's RSL is stored in its own remote tracker, and only the authoritative remote's RSL is used to update the local RSL. The RSL of each remote is then compared with the authoritative remote's RSL.",
+		Args:  cobra.MinimumNArgs(1),
+		RunE:  o.Run,
+	}
+
+	return cmd
+}
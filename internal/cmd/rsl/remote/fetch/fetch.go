@@ -0,0 +1,39 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package fetch
+
+import (
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+}
+
+func (o *options) Run(cmd *cobra.Command, args []string) error {
+	repo, err := repository.LoadRepository(".")
+	if err != nil {
+		return err
+	}
+
+	remoteName, err := common.RemoteName(repo, args)
+	if err != nil {
+		return err
+	}
+
+	return repo.FetchRSL(cmd.Context(), remoteName)
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:   "fetch [remote]",
+		Short: "Fetch new RSL entries from the specified remote",
+		Long:  "This command fetches the RSL entries added at the specified remote since the last fetch, and checks that they chain onto the previously fetched entries. A remote RSL that was rewound is rejected unless the rewind is authorized by a force push annotation.",
+		Args:  cobra.MaximumNArgs(1),
+		RunE:  o.Run,
+	}
+
+	return cmd
+}
@@ -3,6 +3,7 @@
 package pull
 
 import (
+	"github.com/gittuf/gittuf/internal/cmd/common"
 	"github.com/gittuf/gittuf/internal/repository"
 	"github.com/spf13/cobra"
 )
@@ -11,20 +12,25 @@ type options struct {
 }
 
 func (o *options) Run(cmd *cobra.Command, args []string) error {
-	repo, err := repository.LoadRepository()
+	repo, err := repository.LoadRepository(".")
 	if err != nil {
 		return err
 	}
 
-	return repo.PullRSL(cmd.Context(), args[0])
+	remoteName, err := common.RemoteName(repo, args)
+	if err != nil {
+		return err
+	}
+
+	return repo.PullRSL(cmd.Context(), remoteName)
 }
 
 func New() *cobra.Command {
 	o := &options{}
 	cmd := &cobra.Command{
-		Use:   "pull <remote>",
+		Use:   "pull [remote]",
 		Short: "Pull RSL from the specified remote",
-		Args:  cobra.ExactArgs(1),
+		Args:  cobra.MaximumNArgs(1),
 		RunE:  o.Run,
 	}
 
@@ -0,0 +1,39 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package reconcile
+
+import (
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+}
+
+func (o *options) Run(cmd *cobra.Command, args []string) error {
+	repo, err := repository.LoadRepository(".")
+	if err != nil {
+		return err
+	}
+
+	remoteName, err := common.RemoteName(repo, args)
+	if err != nil {
+		return err
+	}
+
+	return repo.ReconcileRSL(cmd.Context(), remoteName, true)
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:   "reconcile [remote]",
+		Short: "Merge the local RSL with the RSL at the specified remote",
+		Long:  "This command fetches the RSL from the specified remote and, if it has diverged from the local RSL, re-creates the local entries that are not present at the remote on top of the remote's entries. The RSLs can only be reconciled if the local and remote entries record updates for different refs. Otherwise, they must be reconciled manually.",
+		Args:  cobra.MaximumNArgs(1),
+		RunE:  o.Run,
+	}
+
+	return cmd
+}
@@ -4,8 +4,11 @@ package remote
 
 import (
 	"github.com/gittuf/gittuf/internal/cmd/rsl/remote/check"
+	"github.com/gittuf/gittuf/internal/cmd/rsl/remote/compare"
+	"github.com/gittuf/gittuf/internal/cmd/rsl/remote/fetch"
 	"github.com/gittuf/gittuf/internal/cmd/rsl/remote/pull"
 	"github.com/gittuf/gittuf/internal/cmd/rsl/remote/push"
+	"github.com/gittuf/gittuf/internal/cmd/rsl/remote/reconcile"
 	"github.com/spf13/cobra"
 )
 
@@ -13,11 +16,15 @@ func New() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "remote",
 		Short: "Tools for managing remote RSLs",
+		Long:  "These commands operate on the specified remote. If no remote is specified, the remote set using the GITTUF_REMOTE environment variable or the gittuf.remote Git config option is used, falling back to origin.",
 	}
 
 	cmd.AddCommand(check.New())
+	cmd.AddCommand(compare.New())
+	cmd.AddCommand(fetch.New())
 	cmd.AddCommand(pull.New())
 	cmd.AddCommand(push.New())
+	cmd.AddCommand(reconcile.New())
 
 	return cmd
 }
@@ -3,6 +3,7 @@
 package rsl
 
 import (
+	"github.com/gittuf/gittuf/internal/cmd/rsl/amend"
 	"github.com/gittuf/gittuf/internal/cmd/rsl/annotate"
 	"github.com/gittuf/gittuf/internal/cmd/rsl/record"
 	"github.com/gittuf/gittuf/internal/cmd/rsl/remote"
@@ -15,6 +16,7 @@ func New() *cobra.Command {
 		Short: "Tools to manage the repository's reference state log",
 	}
 
+	cmd.AddCommand(amend.New())
 	cmd.AddCommand(annotate.New())
 	cmd.AddCommand(record.New())
 	cmd.AddCommand(remote.New())
@@ -0,0 +1,59 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package addemergencykey
+
+import (
+	"os"
+
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/cmd/trust/persistent"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	p            *persistent.Options
+	emergencyKey string
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&o.emergencyKey,
+		"emergency-key",
+		"",
+		"emergency key to add to root of trust",
+	)
+	cmd.MarkFlagRequired("emergency-key") //nolint:errcheck
+}
+
+func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	repo, err := repository.LoadRepository(".")
+	if err != nil {
+		return err
+	}
+
+	rootKeyBytes, err := os.ReadFile(o.p.SigningKey)
+	if err != nil {
+		return err
+	}
+
+	emergencyKeyBytes, err := common.ReadKeyBytes(o.emergencyKey)
+	if err != nil {
+		return err
+	}
+
+	return repo.AddEmergencyKey(cmd.Context(), rootKeyBytes, emergencyKeyBytes, true)
+}
+
+func New(persistent *persistent.Options) *cobra.Command {
+	o := &options{p: persistent}
+	cmd := &cobra.Command{
+		Use:   "add-emergency-key",
+		Short: "Add emergency key to gittuf root of trust",
+		Long:  `This command allows users to add a new key trusted to authorize break-glass access. RSL entries rejected by the policy are accepted if break-glass annotations signed by a threshold of the emergency keys refer to them. An emergency key must not be trusted by any other role in the root of trust. Note that authorized keys can be specified from disk using the custom securesystemslib format, from the GPG keyring using the "gpg:<fingerprint>" format, as a Sigstore identity as "fulcio:<identity>::<issuer>", or as a minisign or signify public key file using the "minisign:<path>" format.`,
+		RunE:  o.Run,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
@@ -7,12 +7,14 @@ import (
 
 	"github.com/gittuf/gittuf/internal/cmd/common"
 	"github.com/gittuf/gittuf/internal/cmd/trust/persistent"
+	"github.com/gittuf/gittuf/internal/policy"
 	"github.com/gittuf/gittuf/internal/repository"
 	"github.com/spf13/cobra"
 )
 
 type options struct {
 	p          *persistent.Options
+	policyName string
 	targetsKey string
 }
 
@@ -24,10 +26,17 @@ func (o *options) AddFlags(cmd *cobra.Command) {
 		"policy key to add to root of trust",
 	)
 	cmd.MarkFlagRequired("policy-key") //nolint:errcheck
+
+	cmd.Flags().StringVar(
+		&o.policyName,
+		"policy-name",
+		policy.TargetsRoleName,
+		"name of top level policy file the key is trusted for",
+	)
 }
 
 func (o *options) Run(cmd *cobra.Command, _ []string) error {
-	repo, err := repository.LoadRepository()
+	repo, err := repository.LoadRepository(".")
 	if err != nil {
 		return err
 	}
@@ -42,7 +51,7 @@ func (o *options) Run(cmd *cobra.Command, _ []string) error {
 		return err
 	}
 
-	return repo.AddTopLevelTargetsKey(cmd.Context(), rootKeyBytes, targetsKeyBytes, true)
+	return repo.AddTopLevelPolicyKey(cmd.Context(), rootKeyBytes, o.policyName, targetsKeyBytes, true)
 }
 
 func New(persistent *persistent.Options) *cobra.Command {
@@ -50,7 +59,7 @@ func New(persistent *persistent.Options) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "add-policy-key",
 		Short: "Add Policy key to gittuf root of trust",
-		Long:  `This command allows users to add a new trusted key for the main policy file. Note that authorized keys can be specified from disk using the custom securesystemslib format, from the GPG keyring using the "gpg:<fingerprint>" format, or as a Sigstore identity as "fulcio:<identity>::<issuer>".`,
+		Long:  `This command allows users to add a new trusted key for the main policy file, or for another top level policy file specified using --policy-name. Note that authorized keys can be specified from disk using the custom securesystemslib format, from the GPG keyring using the "gpg:<fingerprint>" format, as a Sigstore identity as "fulcio:<identity>::<issuer>", or as a minisign or signify public key file using the "minisign:<path>" format.`,
 		RunE:  o.Run,
 	}
 	o.AddFlags(cmd)
@@ -0,0 +1,59 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package addrslwriterkey
+
+import (
+	"os"
+
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/cmd/trust/persistent"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	p            *persistent.Options
+	rslWriterKey string
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&o.rslWriterKey,
+		"rsl-writer-key",
+		"",
+		"RSL writer key to add to root of trust",
+	)
+	cmd.MarkFlagRequired("rsl-writer-key") //nolint:errcheck
+}
+
+func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	repo, err := repository.LoadRepository(".")
+	if err != nil {
+		return err
+	}
+
+	rootKeyBytes, err := os.ReadFile(o.p.SigningKey)
+	if err != nil {
+		return err
+	}
+
+	rslWriterKeyBytes, err := common.ReadKeyBytes(o.rslWriterKey)
+	if err != nil {
+		return err
+	}
+
+	return repo.AddRSLWriterKey(cmd.Context(), rootKeyBytes, rslWriterKeyBytes, true)
+}
+
+func New(persistent *persistent.Options) *cobra.Command {
+	o := &options{p: persistent}
+	cmd := &cobra.Command{
+		Use:   "add-rsl-writer-key",
+		Short: "Add RSL writer key to gittuf root of trust",
+		Long:  `This command allows users to add a new key trusted to sign RSL entries. Once an RSL writer key is added, every RSL entry must be signed by one of the trusted RSL writer keys. Note that authorized keys can be specified from disk using the custom securesystemslib format, from the GPG keyring using the "gpg:<fingerprint>" format, as a Sigstore identity as "fulcio:<identity>::<issuer>", or as a minisign or signify public key file using the "minisign:<path>" format.`,
+		RunE:  o.Run,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
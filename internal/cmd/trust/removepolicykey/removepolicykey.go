@@ -7,12 +7,14 @@ import (
 	"strings"
 
 	"github.com/gittuf/gittuf/internal/cmd/trust/persistent"
+	"github.com/gittuf/gittuf/internal/policy"
 	"github.com/gittuf/gittuf/internal/repository"
 	"github.com/spf13/cobra"
 )
 
 type options struct {
 	p            *persistent.Options
+	policyName   string
 	targetsKeyID string
 }
 
@@ -24,10 +26,17 @@ func (o *options) AddFlags(cmd *cobra.Command) {
 		"ID of Policy key to be removed from root of trust",
 	)
 	cmd.MarkFlagRequired("policy-key-ID") //nolint:errcheck
+
+	cmd.Flags().StringVar(
+		&o.policyName,
+		"policy-name",
+		policy.TargetsRoleName,
+		"name of top level policy file the key is removed from",
+	)
 }
 
 func (o *options) Run(cmd *cobra.Command, _ []string) error {
-	repo, err := repository.LoadRepository()
+	repo, err := repository.LoadRepository(".")
 	if err != nil {
 		return err
 	}
@@ -37,7 +46,7 @@ func (o *options) Run(cmd *cobra.Command, _ []string) error {
 		return err
 	}
 
-	return repo.RemoveTopLevelTargetsKey(cmd.Context(), rootKeyBytes, strings.ToLower(o.targetsKeyID), true)
+	return repo.RemoveTopLevelPolicyKey(cmd.Context(), rootKeyBytes, o.policyName, strings.ToLower(o.targetsKeyID), true)
 }
 
 func New(persistent *persistent.Options) *cobra.Command {
@@ -0,0 +1,53 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package removerslwriterkey
+
+import (
+	"os"
+	"strings"
+
+	"github.com/gittuf/gittuf/internal/cmd/trust/persistent"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	p              *persistent.Options
+	rslWriterKeyID string
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&o.rslWriterKeyID,
+		"rsl-writer-key-ID",
+		"",
+		"ID of RSL writer key to be removed from root of trust",
+	)
+	cmd.MarkFlagRequired("rsl-writer-key-ID") //nolint:errcheck
+}
+
+func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	repo, err := repository.LoadRepository(".")
+	if err != nil {
+		return err
+	}
+
+	rootKeyBytes, err := os.ReadFile(o.p.SigningKey)
+	if err != nil {
+		return err
+	}
+
+	return repo.RemoveRSLWriterKey(cmd.Context(), rootKeyBytes, strings.ToLower(o.rslWriterKeyID), true)
+}
+
+func New(persistent *persistent.Options) *cobra.Command {
+	o := &options{p: persistent}
+	cmd := &cobra.Command{
+		Use:   "remove-rsl-writer-key",
+		Short: "Remove RSL writer key from gittuf root of trust",
+		RunE:  o.Run,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
@@ -0,0 +1,68 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package rotatepolicykey
+
+import (
+	"os"
+	"strings"
+
+	"github.com/gittuf/gittuf/internal/cmd/trust/persistent"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	p               *persistent.Options
+	oldTargetsKeyID string
+	newTargetsKey   string
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&o.oldTargetsKeyID,
+		"old-policy-key-ID",
+		"",
+		"ID of Policy key to be replaced in root of trust",
+	)
+	cmd.MarkFlagRequired("old-policy-key-ID") //nolint:errcheck
+
+	cmd.Flags().StringVar(
+		&o.newTargetsKey,
+		"new-policy-key",
+		"",
+		"signing key to trust for the Policy in place of the old key",
+	)
+	cmd.MarkFlagRequired("new-policy-key") //nolint:errcheck
+}
+
+func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	repo, err := repository.LoadRepository(".")
+	if err != nil {
+		return err
+	}
+
+	rootKeyBytes, err := os.ReadFile(o.p.SigningKey)
+	if err != nil {
+		return err
+	}
+
+	newTargetsKeyBytes, err := os.ReadFile(o.newTargetsKey)
+	if err != nil {
+		return err
+	}
+
+	return repo.RotateTargetsSigningKey(cmd.Context(), rootKeyBytes, strings.ToLower(o.oldTargetsKeyID), newTargetsKeyBytes, true)
+}
+
+func New(persistent *persistent.Options) *cobra.Command {
+	o := &options{p: persistent}
+	cmd := &cobra.Command{
+		Use:   "rotate-policy-key",
+		Short: "Replace Policy key in gittuf root of trust",
+		Long:  `This command allows users to replace a key trusted for the main policy file with a new key. The root of trust is updated and the policy file is re-signed using the new key in a single policy change. The new key must be a signing key in the custom securesystemslib format.`,
+		RunE:  o.Run,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
@@ -0,0 +1,52 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package setemergencythreshold
+
+import (
+	"os"
+
+	"github.com/gittuf/gittuf/internal/cmd/trust/persistent"
+	"github.com/gittuf/gittuf/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	p         *persistent.Options
+	threshold int
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().IntVar(
+		&o.threshold,
+		"threshold",
+		1,
+		"number of emergency keys that must authorize break-glass access",
+	)
+	cmd.MarkFlagRequired("threshold") //nolint:errcheck
+}
+
+func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	repo, err := repository.LoadRepository(".")
+	if err != nil {
+		return err
+	}
+
+	rootKeyBytes, err := os.ReadFile(o.p.SigningKey)
+	if err != nil {
+		return err
+	}
+
+	return repo.SetEmergencyThreshold(cmd.Context(), rootKeyBytes, o.threshold, true)
+}
+
+func New(persistent *persistent.Options) *cobra.Command {
+	o := &options{p: persistent}
+	cmd := &cobra.Command{
+		Use:   "set-emergency-threshold",
+		Short: "Set the number of emergency keys required for break-glass access",
+		RunE:  o.Run,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
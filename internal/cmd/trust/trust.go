@@ -3,10 +3,16 @@
 package trust
 
 import (
+	"github.com/gittuf/gittuf/internal/cmd/trust/addemergencykey"
 	"github.com/gittuf/gittuf/internal/cmd/trust/addpolicykey"
+	"github.com/gittuf/gittuf/internal/cmd/trust/addrslwriterkey"
 	i "github.com/gittuf/gittuf/internal/cmd/trust/init"
 	"github.com/gittuf/gittuf/internal/cmd/trust/persistent"
+	"github.com/gittuf/gittuf/internal/cmd/trust/removeemergencykey"
 	"github.com/gittuf/gittuf/internal/cmd/trust/removepolicykey"
+	"github.com/gittuf/gittuf/internal/cmd/trust/removerslwriterkey"
+	"github.com/gittuf/gittuf/internal/cmd/trust/rotatepolicykey"
+	"github.com/gittuf/gittuf/internal/cmd/trust/setemergencythreshold"
 	"github.com/gittuf/gittuf/internal/cmd/trustpolicy/remote"
 	"github.com/spf13/cobra"
 )
@@ -20,9 +26,15 @@ func New() *cobra.Command {
 	o.AddPersistentFlags(cmd)
 
 	cmd.AddCommand(i.New(o))
+	cmd.AddCommand(addemergencykey.New(o))
 	cmd.AddCommand(addpolicykey.New(o))
+	cmd.AddCommand(addrslwriterkey.New(o))
 	cmd.AddCommand(remote.New())
+	cmd.AddCommand(removeemergencykey.New(o))
 	cmd.AddCommand(removepolicykey.New(o))
+	cmd.AddCommand(removerslwriterkey.New(o))
+	cmd.AddCommand(rotatepolicykey.New(o))
+	cmd.AddCommand(setemergencythreshold.New(o))
 
 	return cmd
 }
@@ -12,6 +12,7 @@ func New() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "remote",
 		Short: "Tools for managing remote policies",
+		Long:  "These commands operate on the specified remote. If no remote is specified, the remote set using the GITTUF_REMOTE environment variable or the gittuf.remote Git config option is used, falling back to origin.",
 	}
 
 	cmd.AddCommand(pull.New())
@@ -3,12 +3,25 @@
 package verifyref
 
 import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/gittuf/gittuf/internal/policy"
 	"github.com/gittuf/gittuf/internal/repository"
 	"github.com/spf13/cobra"
 )
 
 type options struct {
-	full bool
+	full                 bool
+	expectedPolicyCommit string
+	expectedRootKeys     []string
+	policyEntry          string
+	targetID             string
+	vsaSigningKey        string
+	vsaOutput            string
+	storeVSA             bool
 }
 
 func (o *options) AddFlags(cmd *cobra.Command) {
@@ -19,14 +32,121 @@ func (o *options) AddFlags(cmd *cobra.Command) {
 		false,
 		"perform verification from the start of the RSL",
 	)
+
+	cmd.Flags().StringVar(
+		&o.expectedPolicyCommit,
+		"expected-policy-commit",
+		"",
+		"policy commit, obtained out of band, that the current policy must be or descend from",
+	)
+
+	cmd.Flags().StringArrayVar(
+		&o.expectedRootKeys,
+		"expected-root-key",
+		[]string{},
+		"ID of a root key, obtained out of band, that must have signed the current root of trust",
+	)
+
+	cmd.Flags().StringVar(
+		&o.policyEntry,
+		"policy-entry",
+		"",
+		"ID of the RSL entry for the policy to verify against instead of the policy selected automatically",
+	)
+
+	cmd.Flags().StringVar(
+		&o.targetID,
+		"target-id",
+		"",
+		"target recorded in the ref's RSL entry to verify when using --policy-entry (default: current tip of the ref)",
+	)
+
+	cmd.Flags().StringVar(
+		&o.vsaSigningKey,
+		"vsa-signing-key",
+		"",
+		"signing key used to issue a verification summary attestation if verification succeeds",
+	)
+
+	cmd.Flags().StringVar(
+		&o.vsaOutput,
+		"vsa-output",
+		"",
+		"path to write the verification summary attestation to (default: standard output)",
+	)
+
+	cmd.Flags().BoolVar(
+		&o.storeVSA,
+		"store-vsa",
+		false,
+		"record the verification summary attestation in the repository's attestations namespace",
+	)
 }
 
 func (o *options) Run(cmd *cobra.Command, args []string) error {
-	repo, err := repository.LoadRepository()
+	repo, err := repository.LoadRepository(".")
+	if err != nil {
+		return err
+	}
+
+	if len(o.policyEntry) > 0 {
+		return repo.VerifyRefAtPolicy(cmd.Context(), args[0], o.targetID, o.policyEntry)
+	}
+
+	opts := []repository.VerifyRefOption{}
+	if len(o.expectedPolicyCommit) > 0 || len(o.expectedRootKeys) > 0 {
+		opts = append(opts, repository.WithPolicyPins(&policy.PolicyPins{
+			PolicyCommitID: o.expectedPolicyCommit,
+			RootKeyIDs:     o.expectedRootKeys,
+		}))
+	}
+
+	report := &policy.VerificationReport{}
+	ctx := policy.NewReportContext(cmd.Context(), report)
+
+	if len(o.vsaSigningKey) == 0 {
+		if err := repo.VerifyRef(ctx, args[0], o.full, opts...); err != nil {
+			return err
+		}
+
+		printBreakGlassUses(cmd, report)
+		return nil
+	}
+
+	signingKeyBytes, err := os.ReadFile(o.vsaSigningKey)
+	if err != nil {
+		return err
+	}
+
+	env, err := repo.VerifyRefAndAttest(ctx, args[0], o.full, signingKeyBytes, o.storeVSA, true, opts...)
 	if err != nil {
 		return err
 	}
-	return repo.VerifyRef(cmd.Context(), args[0], o.full)
+	printBreakGlassUses(cmd, report)
+
+	envBytes, err := json.MarshalIndent(env, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if len(o.vsaOutput) > 0 {
+		return os.WriteFile(o.vsaOutput, envBytes, 0o600)
+	}
+
+	fmt.Println(string(envBytes))
+	return nil
+}
+
+// printBreakGlassUses warns about the RSL entries that were accepted only
+// because of break-glass access.
+func printBreakGlassUses(cmd *cobra.Command, report *policy.VerificationReport) {
+	for _, use := range report.BreakGlassUses() {
+		annotationIDs := make([]string, 0, len(use.AnnotationIDs))
+		for _, id := range use.AnnotationIDs {
+			annotationIDs = append(annotationIDs, id.String())
+		}
+		fmt.Fprintf(cmd.ErrOrStderr(), "warning: RSL entry '%s' for '%s' was accepted using break-glass access (annotations: %s): %s\n", use.EntryID.String(), use.RefName, strings.Join(annotationIDs, ", "), use.Err.Error())
+	}
 }
 
 func New() *cobra.Command {
@@ -38,6 +158,10 @@ func New() *cobra.Command {
 		RunE:  o.Run,
 	}
 	o.AddFlags(cmd)
+	cmd.MarkFlagsMutuallyExclusive("policy-entry", "full")
+	cmd.MarkFlagsMutuallyExclusive("policy-entry", "expected-policy-commit")
+	cmd.MarkFlagsMutuallyExclusive("policy-entry", "expected-root-key")
+	cmd.MarkFlagsMutuallyExclusive("policy-entry", "vsa-signing-key")
 
 	return cmd
 }
@@ -4,6 +4,7 @@ package common
 
 import (
 	"bytes"
+	"context"
 	"encoding/pem"
 	"errors"
 	"fmt"
@@ -19,6 +20,7 @@ import (
 	"github.com/gittuf/gittuf/internal/third_party/go-git"
 	"github.com/gittuf/gittuf/internal/third_party/go-git/config"
 	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing/filemode"
 	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing/object"
 	"github.com/jonboulle/clockwork"
 )
@@ -114,6 +116,14 @@ func CreateTestRSLAnnotationEntryCommit(t *testing.T, repo *git.Repository, anno
 		lines = append(lines, fmt.Sprintf("%s: false", rsl.SkipKey))
 	}
 
+	if annotation.ForcePush {
+		lines = append(lines, fmt.Sprintf("%s: true", rsl.ForcePushKey))
+	}
+
+	if annotation.BreakGlass {
+		lines = append(lines, fmt.Sprintf("%s: true", rsl.BreakGlassKey))
+	}
+
 	if len(annotation.Message) != 0 {
 		var message strings.Builder
 		messageBlock := pem.Block{
@@ -145,7 +155,7 @@ func CreateTestRSLAnnotationEntryCommit(t *testing.T, repo *git.Repository, anno
 			When:  testClock.Now(),
 		},
 		Message:      commitMessage,
-		TreeHash:     plumbing.ZeroHash,
+		TreeHash:     gitinterface.EmptyTree(),
 		ParentHashes: []plumbing.Hash{ref.Hash()},
 	}
 
@@ -162,7 +172,7 @@ func CreateTestRSLAnnotationEntryCommit(t *testing.T, repo *git.Repository, anno
 // SignTestCommit signs the test commit using the specified key stored in the
 // repository. Note that the GPG key is loaded relative to the package
 // containing the test.
-func SignTestCommit(t *testing.T, repo *git.Repository, commit *object.Commit, keyName string) *object.Commit {
+func SignTestCommit(t testing.TB, repo *git.Repository, commit *object.Commit, keyName string) *object.Commit {
 	t.Helper()
 
 	commitEncoded := repo.Storer.NewEncodedObject()
@@ -193,6 +203,39 @@ func SignTestCommit(t *testing.T, repo *git.Repository, commit *object.Commit, k
 	return commit
 }
 
+// NewTestGPGSigner returns a gitinterface.ObjectSigner that signs Git objects
+// using the specified key stored in the repository, so that objects created by
+// gittuf itself can be signed with test keys rather than the user's Git config.
+// Note that the GPG key is loaded relative to the package containing the test.
+func NewTestGPGSigner(t testing.TB, keyName string) gitinterface.ObjectSigner {
+	t.Helper()
+
+	signingKeyBytes, err := os.ReadFile(filepath.Join("test-data", keyName))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyring, err := openpgp.ReadArmoredKeyRing(bytes.NewReader(signingKeyBytes))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &testGPGSigner{entity: keyring[0]}
+}
+
+type testGPGSigner struct {
+	entity *openpgp.Entity
+}
+
+func (s *testGPGSigner) Sign(_ context.Context, data []byte) ([]byte, error) {
+	sig := new(strings.Builder)
+	if err := openpgp.ArmoredDetachSign(sig, s.entity, bytes.NewReader(data), nil); err != nil {
+		return nil, err
+	}
+
+	return []byte(sig.String()), nil
+}
+
 // SignTestTag signs the specified tag using the test key stored in the
 // repository.  Note that the GPG key is loaded relative to the package
 // containing the test.
@@ -233,7 +276,7 @@ func SignTestTag(t *testing.T, repo *git.Repository, tag *object.Tag, keyName st
 // first commit contains a tree with one object (an empty blob), the second with
 // two objects (both empty blobs), and so on. Each commit is signed using the
 // specified key.
-func AddNTestCommitsToSpecifiedRef(t *testing.T, repo *git.Repository, refName string, n int, keyName string) []plumbing.Hash {
+func AddNTestCommitsToSpecifiedRef(t testing.TB, repo *git.Repository, refName string, n int, keyName string) []plumbing.Hash {
 	t.Helper()
 
 	emptyBlobHash, err := gitinterface.WriteBlob(repo, []byte{})
@@ -295,6 +338,148 @@ func AddNTestCommitsToSpecifiedRef(t *testing.T, repo *git.Repository, refName s
 	return commitIDs
 }
 
+// AddTestCoSignedCommitToSpecifiedRef adds a commit with an empty tree to the
+// specified ref. The commit is co-signed using each of the co-signer keys and
+// then signed using the specified key.
+func AddTestCoSignedCommitToSpecifiedRef(t testing.TB, repo *git.Repository, refName string, coSignerKeyNames []string, keyName string) plumbing.Hash {
+	t.Helper()
+
+	treeHash, err := gitinterface.WriteTree(repo, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	refNameTyped := plumbing.ReferenceName(refName)
+	ref, err := repo.Reference(refNameTyped, true)
+	if err != nil {
+		if !errors.Is(err, plumbing.ErrReferenceNotFound) {
+			t.Fatal(err)
+		}
+		if err := repo.Storer.SetReference(plumbing.NewHashReference(refNameTyped, plumbing.ZeroHash)); err != nil {
+			t.Fatal(err)
+		}
+		ref, err = repo.Reference(refNameTyped, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	commit := gitinterface.CreateCommitObject(testGitConfig, treeHash, ref.Hash(), "Test co-signed commit", testClock)
+	for _, coSignerKeyName := range coSignerKeyNames {
+		payload, err := gitinterface.GetCommitCoSignaturePayload(commit)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		signingKeyBytes, err := os.ReadFile(filepath.Join("test-data", coSignerKeyName))
+		if err != nil {
+			t.Fatal(err)
+		}
+		keyring, err := openpgp.ReadArmoredKeyRing(bytes.NewReader(signingKeyBytes))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		sig := new(strings.Builder)
+		if err := openpgp.ArmoredDetachSign(sig, keyring[0], bytes.NewReader(payload), nil); err != nil {
+			t.Fatal(err)
+		}
+
+		gitinterface.AddCommitCoSignature(commit, sig.String())
+	}
+	commit = SignTestCommit(t, repo, commit, keyName)
+
+	commitID, err := gitinterface.ApplyCommit(repo, commit, ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return commitID
+}
+
+// AddTestNoteToSpecifiedRef adds a note with the specified contents for the
+// object to the notes ref. The note is stored using a two character fanout,
+// i.e., at "ab/cdef..." for object "abcdef...". The notes commit is signed
+// using the specified key.
+func AddTestNoteToSpecifiedRef(t testing.TB, repo *git.Repository, notesRefName string, objectID plumbing.Hash, contents []byte, keyName string) plumbing.Hash {
+	t.Helper()
+
+	noteBlobID, err := gitinterface.WriteBlob(repo, contents)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fanoutName := objectID.String()[:2]
+	noteName := objectID.String()[2:]
+
+	refNameTyped := plumbing.ReferenceName(notesRefName)
+	ref, err := repo.Reference(refNameTyped, true)
+	if err != nil {
+		if !errors.Is(err, plumbing.ErrReferenceNotFound) {
+			t.Fatal(err)
+		}
+		if err := repo.Storer.SetReference(plumbing.NewHashReference(refNameTyped, plumbing.ZeroHash)); err != nil {
+			t.Fatal(err)
+		}
+		ref, err = repo.Reference(refNameTyped, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Retain the existing notes, replacing any existing note for the object
+	topLevelEntries := []object.TreeEntry{}
+	fanoutEntries := []object.TreeEntry{}
+	if !ref.Hash().IsZero() {
+		parentCommit, err := repo.CommitObject(ref.Hash())
+		if err != nil {
+			t.Fatal(err)
+		}
+		parentTree, err := parentCommit.Tree()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, entry := range parentTree.Entries {
+			if entry.Name != fanoutName {
+				topLevelEntries = append(topLevelEntries, entry)
+				continue
+			}
+
+			fanoutTree, err := repo.TreeObject(entry.Hash)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, fanoutEntry := range fanoutTree.Entries {
+				if fanoutEntry.Name != noteName {
+					fanoutEntries = append(fanoutEntries, fanoutEntry)
+				}
+			}
+		}
+	}
+
+	fanoutEntries = append(fanoutEntries, object.TreeEntry{Name: noteName, Mode: filemode.Regular, Hash: noteBlobID})
+	fanoutTreeID, err := gitinterface.WriteTree(repo, fanoutEntries)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	topLevelEntries = append(topLevelEntries, object.TreeEntry{Name: fanoutName, Mode: filemode.Dir, Hash: fanoutTreeID})
+	treeID, err := gitinterface.WriteTree(repo, topLevelEntries)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	commit := gitinterface.CreateCommitObject(testGitConfig, treeID, ref.Hash(), "Notes added by 'git notes add'", testClock)
+	commit = SignTestCommit(t, repo, commit, keyName)
+	commitID, err := gitinterface.ApplyCommit(repo, commit, ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return commitID
+}
+
 // CreateTestSignedTag creates a signed tag in the repository pointing to the
 // target object. The tag is signed using the specified key.
 func CreateTestSignedTag(t *testing.T, repo *git.Repository, tagName string, target plumbing.Hash, keyName string) plumbing.Hash {
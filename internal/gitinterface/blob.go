@@ -3,19 +3,34 @@
 package gitinterface
 
 import (
+	"context"
 	"errors"
 	"io"
 
 	"github.com/gittuf/gittuf/internal/third_party/go-git"
 	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing/object"
 	"github.com/gittuf/gittuf/internal/third_party/go-git/storage/memory"
 )
 
 var ErrWrittenBlobLengthMismatch = errors.New("length of blob written does not match length of contents")
 
-// ReadBlob returns the contents of a the blob referenced by blobID.
-func ReadBlob(repo *git.Repository, blobID plumbing.Hash) ([]byte, error) {
-	blob, err := repo.BlobObject(blobID)
+// ReadBlob returns the contents of a the blob referenced by blobID. If the blob
+// is missing in a partial clone, it is fetched from the promisor remote. If ctx
+// is done before the blob is read in full, the context's error is returned.
+func ReadBlob(ctx context.Context, repo *git.Repository, blobID plumbing.Hash) ([]byte, error) {
+	objectStorer := &contextStorer{EncodedObjectStorer: repo.Storer, ctx: ctx}
+
+	blob, err := object.GetBlob(objectStorer, blobID)
+	if errors.Is(err, plumbing.ErrObjectNotFound) {
+		if fetchErr := FetchMissingObjects(ctx, repo, []plumbing.Hash{blobID}); fetchErr != nil {
+			if errors.Is(fetchErr, ErrNotPartialClone) {
+				return nil, err
+			}
+			return nil, errors.Join(err, fetchErr)
+		}
+		blob, err = object.GetBlob(objectStorer, blobID)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -24,6 +39,7 @@ func ReadBlob(repo *git.Repository, blobID plumbing.Hash) ([]byte, error) {
 	if err != nil {
 		return nil, err
 	}
+	defer reader.Close() //nolint:errcheck
 
 	return io.ReadAll(reader)
 }
@@ -3,9 +3,12 @@
 package gitinterface
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"testing"
+	"time"
 
 	"github.com/gittuf/gittuf/internal/third_party/go-git"
 	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing"
@@ -47,7 +50,7 @@ func TestReadBlob(t *testing.T) {
 	t.Run("test expected file", func(t *testing.T) {
 		expectedHash := "2ecdd330475d93568ed27f717a84a7fe207d1c58"
 
-		contents, err := ReadBlob(repo, plumbing.NewHash(expectedHash))
+		contents, err := ReadBlob(context.Background(), repo, plumbing.NewHash(expectedHash))
 		if err != nil {
 			t.Error(err)
 		}
@@ -57,7 +60,7 @@ func TestReadBlob(t *testing.T) {
 	})
 
 	t.Run("test nonexistent blob", func(t *testing.T) {
-		_, err := ReadBlob(repo, plumbing.ZeroHash)
+		_, err := ReadBlob(context.Background(), repo, plumbing.ZeroHash)
 		assert.ErrorIs(t, err, plumbing.ErrObjectNotFound)
 	})
 
@@ -73,9 +76,68 @@ func TestReadBlob(t *testing.T) {
 			t.Fatal(err)
 		}
 
-		_, err = ReadBlob(repo, treeHash)
+		_, err = ReadBlob(context.Background(), repo, treeHash)
 		assert.ErrorIs(t, err, plumbing.ErrObjectNotFound)
 	})
+
+	t.Run("test read is discarded when context is canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		// The context is canceled while the blob is read
+		storer := &cancelingStorer{Storage: repo.Storer.(*memory.Storage), cancel: cancel}
+		cancelingRepo, err := git.Open(storer, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = ReadBlob(ctx, cancelingRepo, writtenHash)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, 1, storer.reads)
+
+		// The blob isn't read once the context is canceled
+		_, err = ReadBlob(ctx, cancelingRepo, writtenHash)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, 1, storer.reads)
+	})
+
+	t.Run("test slow read is aborted when context is canceled", func(t *testing.T) {
+		largeContents := bytes.Repeat([]byte("a"), 1000)
+		largeHash, err := WriteBlob(repo, largeContents)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Reading the blob in full takes ten seconds
+		storer := &slowStorer{Storage: repo.Storer.(*memory.Storage), delay: 10 * time.Millisecond}
+		slowRepo, err := git.Open(storer, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		_, err = ReadBlob(ctx, slowRepo, largeHash)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Less(t, time.Since(start), time.Second)
+		assert.Less(t, storer.reads, len(largeContents))
+
+		// The tree is also read subject to the context
+		treeHash, err := WriteTree(repo, []object.TreeEntry{{Name: "blob", Mode: filemode.Regular, Hash: largeHash}})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		start = time.Now()
+		_, err = ReadTree(ctx, slowRepo, treeHash)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Less(t, time.Since(start), time.Second)
+	})
 }
 
 func TestWriteBlob(t *testing.T) {
@@ -118,3 +180,64 @@ func TestEmptyBlob(t *testing.T) {
 	// $ git hash-object -t blob --stdin < /dev/null
 	assert.Equal(t, "e69de29bb2d1d6434b8b29ae775ad8c2e48c5391", hash.String())
 }
+
+// cancelingStorer is a storer that cancels a context when an object is read.
+type cancelingStorer struct {
+	*memory.Storage
+	cancel context.CancelFunc
+	reads  int
+}
+
+func (s *cancelingStorer) EncodedObject(objectType plumbing.ObjectType, objectID plumbing.Hash) (plumbing.EncodedObject, error) {
+	s.reads++
+	s.cancel()
+	return s.Storage.EncodedObject(objectType, objectID)
+}
+
+// slowStorer is a storer whose objects' contents are read one byte at a time,
+// waiting for delay before each byte.
+type slowStorer struct {
+	*memory.Storage
+	delay time.Duration
+	reads int
+}
+
+func (s *slowStorer) EncodedObject(objectType plumbing.ObjectType, objectID plumbing.Hash) (plumbing.EncodedObject, error) {
+	obj, err := s.Storage.EncodedObject(objectType, objectID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &slowObject{EncodedObject: obj, storer: s}, nil
+}
+
+type slowObject struct {
+	plumbing.EncodedObject
+	storer *slowStorer
+}
+
+func (o *slowObject) Reader() (io.ReadCloser, error) {
+	reader, err := o.EncodedObject.Reader()
+	if err != nil {
+		return nil, err
+	}
+
+	return &slowReader{ReadCloser: reader, storer: o.storer}, nil
+}
+
+type slowReader struct {
+	io.ReadCloser
+	storer *slowStorer
+}
+
+func (r *slowReader) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	time.Sleep(r.storer.delay)
+	n, err := r.ReadCloser.Read(p[:1])
+	r.storer.reads += n
+
+	return n, err
+}
@@ -0,0 +1,148 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gitinterface
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/gittuf/gittuf/internal/third_party/go-git"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing/format/packfile"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing/revlist"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/storage/memory"
+)
+
+const bundleSignature = "# v2 git bundle"
+
+var (
+	ErrInvalidBundle                   = errors.New("invalid git bundle")
+	ErrBundlePrerequisitesNotSupported = errors.New("git bundles with prerequisite commits are not supported")
+)
+
+// CreateBundle writes a git bundle containing the specified refs to w. The
+// bundle uses the v2 format, and it includes every object reachable from the
+// refs, so it can be read without access to the repository. The bundle can
+// also be read using `git clone` or `git fetch`.
+func CreateBundle(repo *git.Repository, refs []string, w io.Writer) error {
+	header := bundleSignature + "\n"
+	tips := make([]plumbing.Hash, 0, len(refs))
+	for _, refName := range refs {
+		ref, err := repo.Reference(plumbing.ReferenceName(refName), true)
+		if err != nil {
+			return err
+		}
+
+		header += fmt.Sprintf("%s %s\n", ref.Hash().String(), refName)
+		tips = append(tips, ref.Hash())
+	}
+	header += "\n"
+
+	objects, err := revlist.Objects(repo.Storer, tips, nil)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.WriteString(w, header); err != nil {
+		return err
+	}
+
+	encoder := packfile.NewEncoder(w, repo.Storer, false)
+	_, err = encoder.Encode(objects, 10)
+	return err
+}
+
+// ReadBundleToMemory reads a git bundle in the v2 format into a new in-memory
+// repository, setting each of the refs recorded in the bundle. This allows the
+// contents of the bundle to be inspected before they are imported into another
+// repository. Bundles that depend on prerequisite commits are not supported, as
+// the prerequisites are unavailable in the in-memory repository. The HEAD
+// recorded in bundles created with --all is ignored.
+func ReadBundleToMemory(bundle io.Reader) (*git.Repository, error) {
+	reader := bufio.NewReader(bundle)
+
+	signature, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, errors.Join(ErrInvalidBundle, err)
+	}
+	if strings.TrimSuffix(signature, "\n") != bundleSignature {
+		return nil, fmt.Errorf("%w: unsupported signature '%s'", ErrInvalidBundle, strings.TrimSpace(signature))
+	}
+
+	refs := []*plumbing.Reference{}
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, errors.Join(ErrInvalidBundle, err)
+		}
+		line = strings.TrimSuffix(line, "\n")
+		if len(line) == 0 {
+			// The header ends with an empty line
+			break
+		}
+
+		if strings.HasPrefix(line, "-") {
+			return nil, ErrBundlePrerequisitesNotSupported
+		}
+
+		objectID, refName, found := strings.Cut(line, " ")
+		if found && plumbing.IsHash(objectID) && refName == plumbing.HEAD.String() {
+			// Bundles created with --all also record HEAD, which is not
+			// imported
+			continue
+		}
+		if !found || !plumbing.IsHash(objectID) || !strings.HasPrefix(refName, RefPrefix) {
+			return nil, fmt.Errorf("%w: unexpected ref line '%s'", ErrInvalidBundle, line)
+		}
+
+		refs = append(refs, plumbing.NewHashReference(plumbing.ReferenceName(refName), plumbing.NewHash(objectID)))
+	}
+
+	repo, err := git.Init(memory.NewStorage(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(refs) == 0 {
+		return repo, nil
+	}
+
+	if err := packfile.UpdateObjectStorage(repo.Storer, reader); err != nil {
+		return nil, errors.Join(ErrInvalidBundle, err)
+	}
+
+	for _, ref := range refs {
+		if _, err := repo.Storer.EncodedObject(plumbing.AnyObject, ref.Hash()); err != nil {
+			return nil, fmt.Errorf("%w: target of '%s' is missing", ErrInvalidBundle, ref.Name().String())
+		}
+
+		if err := repo.Storer.SetReference(ref); err != nil {
+			return nil, err
+		}
+	}
+
+	return repo, nil
+}
+
+// CopyObjects copies every object in the source repository into the target
+// repository. Objects that already exist in the target repository are left
+// unchanged.
+func CopyObjects(source, target *git.Repository) error {
+	iter, err := source.Storer.IterEncodedObjects(plumbing.AnyObject)
+	if err != nil {
+		return err
+	}
+	defer iter.Close()
+
+	return iter.ForEach(func(obj plumbing.EncodedObject) error {
+		if err := target.Storer.HasEncodedObject(obj.Hash()); err == nil {
+			return nil
+		}
+
+		_, err := target.Storer.SetEncodedObject(obj)
+		return err
+	})
+}
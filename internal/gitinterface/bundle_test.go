@@ -0,0 +1,124 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gitinterface
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/third_party/go-git"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing/filemode"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing/object"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/storage/memory"
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBundle(t *testing.T) {
+	refName := "refs/heads/main"
+
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	commitIDs := []plumbing.Hash{}
+	parentID := plumbing.ZeroHash
+	for _, contents := range []string{"first", "second"} {
+		blobID, err := WriteBlob(repo, []byte(contents))
+		if err != nil {
+			t.Fatal(err)
+		}
+		treeHash, err := WriteTree(repo, []object.TreeEntry{{Name: "file", Mode: filemode.Regular, Hash: blobID}})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		commitID, err := WriteCommit(repo, CreateCommitObject(testGitConfig, treeHash, parentID, "Test commit", testClock))
+		if err != nil {
+			t.Fatal(err)
+		}
+		commitIDs = append(commitIDs, commitID)
+		parentID = commitID
+	}
+	if err := repo.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(refName), commitIDs[1])); err != nil {
+		t.Fatal(err)
+	}
+
+	bundle := &bytes.Buffer{}
+	err = CreateBundle(repo, []string{refName}, bundle)
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(bundle.String(), bundleSignature+"\n"+commitIDs[1].String()+" "+refName+"\n\n"))
+
+	t.Run("read bundle", func(t *testing.T) {
+		bundleRepo, err := ReadBundleToMemory(bytes.NewReader(bundle.Bytes()))
+		assert.Nil(t, err)
+
+		tip, err := GetTip(bundleRepo, refName)
+		assert.Nil(t, err)
+		assert.Equal(t, commitIDs[1], tip)
+
+		// The bundle contains the entire history of the ref
+		for _, commitID := range commitIDs {
+			commit, err := bundleRepo.CommitObject(commitID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			tree, err := bundleRepo.TreeObject(commit.TreeHash)
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = bundleRepo.BlobObject(tree.Entries[0].Hash)
+			assert.Nil(t, err)
+		}
+	})
+
+	t.Run("read bundle with HEAD", func(t *testing.T) {
+		// The header is the same as one written by git bundle create --all
+		headBundle := bytes.Replace(bundle.Bytes(), []byte(bundleSignature+"\n"), []byte(bundleSignature+"\n"+commitIDs[1].String()+" HEAD\n"), 1)
+
+		bundleRepo, err := ReadBundleToMemory(bytes.NewReader(headBundle))
+		assert.Nil(t, err)
+
+		tip, err := GetTip(bundleRepo, refName)
+		assert.Nil(t, err)
+		assert.Equal(t, commitIDs[1], tip)
+	})
+
+	t.Run("copy objects", func(t *testing.T) {
+		bundleRepo, err := ReadBundleToMemory(bytes.NewReader(bundle.Bytes()))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		targetRepo, err := git.Init(memory.NewStorage(), memfs.New())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = CopyObjects(bundleRepo, targetRepo)
+		assert.Nil(t, err)
+
+		for _, commitID := range commitIDs {
+			_, err := targetRepo.CommitObject(commitID)
+			assert.Nil(t, err)
+		}
+	})
+
+	t.Run("invalid bundles", func(t *testing.T) {
+		_, err := ReadBundleToMemory(strings.NewReader("# v3 git bundle\n\n"))
+		assert.ErrorIs(t, err, ErrInvalidBundle)
+
+		_, err = ReadBundleToMemory(strings.NewReader(bundleSignature + "\n" + commitIDs[1].String() + " main\n\n"))
+		assert.ErrorIs(t, err, ErrInvalidBundle)
+
+		_, err = ReadBundleToMemory(strings.NewReader(bundleSignature + "\n-" + commitIDs[0].String() + " Test commit\n" + commitIDs[1].String() + " " + refName + "\n\n"))
+		assert.ErrorIs(t, err, ErrBundlePrerequisitesNotSupported)
+
+		// The packfile is truncated
+		_, err = ReadBundleToMemory(bytes.NewReader(bundle.Bytes()[:bundle.Len()-30]))
+		assert.ErrorIs(t, err, ErrInvalidBundle)
+	})
+}
@@ -3,28 +3,45 @@
 package gitinterface
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"sort"
 
 	"github.com/gittuf/gittuf/internal/third_party/go-git"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing/filemode"
 	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing/object"
 )
 
 // GetCommitFilePaths returns all the file paths of the provided commit object.
 // This strictly enumerates all the files recursively in the commit object's
-// tree.
+// tree. Only tree objects are read, so the files' blobs need not be present in
+// the repository, such as in a blobless partial clone.
 func GetCommitFilePaths(commit *object.Commit) ([]string, error) {
-	filesIter, err := commit.Files()
+	tree, err := commit.Tree()
 	if err != nil {
 		return nil, err
 	}
 
+	walker := object.NewTreeWalker(tree, true, nil)
+	defer walker.Close()
+
 	paths := []string{}
-	if err := filesIter.ForEach(func(f *object.File) error {
-		paths = append(paths, f.Name)
-		return nil
-	}); err != nil {
-		return nil, err
+	for {
+		name, entry, err := walker.Next()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, err
+		}
+
+		if entry.Mode == filemode.Dir || entry.Mode == filemode.Submodule {
+			continue
+		}
+
+		paths = append(paths, name)
 	}
 
 	sort.Slice(paths, func(i, j int) bool {
@@ -34,18 +51,15 @@ func GetCommitFilePaths(commit *object.Commit) ([]string, error) {
 	return paths, nil
 }
 
+var ErrNotMergeCommit = errors.New("commit is not a merge commit")
+
 // GetFilePathsChangedByCommit returns the paths changed by the commit relative
 // to its parent commit. If the commit is a merge commit, i.e., it has more than
-// one parent, no changes are returned.
-//
-// Currently, this function does not verify that the tree for a merge commit
-// matches one of its parents. In a future version, this behavior may change and
-// return an error if a multi-parent commit seems invalid.
+// one parent, no changes are returned. GetFilePathsChangedByMergeCommit can be
+// used to identify changes introduced by a merge commit itself.
 func GetFilePathsChangedByCommit(repo *git.Repository, commit *object.Commit) ([]string, error) {
 	if len(commit.ParentHashes) > 1 {
 		// merge commits are expected not to introduce changes themselves
-		// TODO: should we check that the merge commit's tree matches one of its
-		// parents (usually the last)?
 		return nil, nil
 	}
 
@@ -62,6 +76,123 @@ func GetFilePathsChangedByCommit(repo *git.Repository, commit *object.Commit) ([
 	return GetDiffFilePaths(commit, parentCommit)
 }
 
+// GetFilePathsChangedByMergeCommit returns the paths in a merge commit's tree
+// that are not attributable to any of its parents, i.e., the changes introduced
+// by the merge commit itself rather than by the branches it merges. A path is
+// attributable to a parent if the merge commit's tree has the same entry for it
+// as the parent, or if neither has an entry for it.
+//
+// For a merge of two parents, paths modified by both parents since their merge
+// base that differ from both parents are not returned, as the merge commit may
+// have to resolve conflicts between them. These paths are returned by
+// GetFilePathsResolvedByMergeCommit instead, and as the merge commit may put
+// arbitrary contents in them, they must be verified against the merge commit's
+// own signature. Octopus merges cannot resolve conflicts, so every path must
+// match one of the parents.
+//
+// If the commit has fewer than two parents, ErrNotMergeCommit is returned.
+func GetFilePathsChangedByMergeCommit(repo *git.Repository, commit *object.Commit) ([]string, error) {
+	unattributedPaths, _, err := getMergeCommitChanges(repo, commit)
+	return unattributedPaths, err
+}
+
+// GetFilePathsResolvedByMergeCommit returns the paths in the tree of a merge of
+// two parents that differ from both parents and were modified by both parents
+// since their merge base, i.e., the paths in which the merge commit resolves
+// conflicts between its parents. Their contents are chosen by the merge
+// commit's author rather than either parent. If the commit has fewer than two
+// parents, ErrNotMergeCommit is returned.
+func GetFilePathsResolvedByMergeCommit(repo *git.Repository, commit *object.Commit) ([]string, error) {
+	_, resolvedPaths, err := getMergeCommitChanges(repo, commit)
+	return resolvedPaths, err
+}
+
+// getMergeCommitChanges returns the paths in the merge commit's tree that are
+// not attributable to any of its parents and the paths in which it resolves
+// conflicts between its parents, see GetFilePathsChangedByMergeCommit and
+// GetFilePathsResolvedByMergeCommit.
+func getMergeCommitChanges(repo *git.Repository, commit *object.Commit) ([]string, []string, error) {
+	if len(commit.ParentHashes) < 2 {
+		return nil, nil, ErrNotMergeCommit
+	}
+
+	parents := make([]*object.Commit, 0, len(commit.ParentHashes))
+	for _, parentID := range commit.ParentHashes {
+		parent, err := repo.CommitObject(parentID)
+		if err != nil {
+			return nil, nil, err
+		}
+		parents = append(parents, parent)
+	}
+
+	// A path is unattributed only if it differs from every parent
+	unattributedPaths := map[string]bool{}
+	for i, parent := range parents {
+		paths, err := GetDiffFilePaths(commit, parent)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		differingPaths := map[string]bool{}
+		for _, path := range paths {
+			if i == 0 || unattributedPaths[path] {
+				differingPaths[path] = true
+			}
+		}
+		unattributedPaths = differingPaths
+	}
+
+	resolvedPaths := map[string]bool{}
+	if len(parents) == 2 && len(unattributedPaths) > 0 {
+		// If the parents have no common history, every path in both parents
+		// is treated as modified by both
+		var mergeBase *object.Commit
+		mergeBases, err := parents[0].MergeBase(parents[1])
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(mergeBases) > 0 {
+			mergeBase = mergeBases[0]
+		}
+
+		firstParentPaths, err := GetDiffFilePaths(parents[0], mergeBase)
+		if err != nil {
+			return nil, nil, err
+		}
+		secondParentPaths, err := GetDiffFilePaths(parents[1], mergeBase)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		modifiedByFirstParent := map[string]bool{}
+		for _, path := range firstParentPaths {
+			modifiedByFirstParent[path] = true
+		}
+		for _, path := range secondParentPaths {
+			if modifiedByFirstParent[path] && unattributedPaths[path] {
+				delete(unattributedPaths, path)
+				resolvedPaths[path] = true
+			}
+		}
+	}
+
+	return sortedPaths(unattributedPaths), sortedPaths(resolvedPaths), nil
+}
+
+// sortedPaths returns the paths in the set in sorted order.
+func sortedPaths(pathSet map[string]bool) []string {
+	paths := []string{}
+	for path := range pathSet {
+		paths = append(paths, path)
+	}
+
+	sort.Slice(paths, func(i, j int) bool {
+		return paths[i] < paths[j]
+	})
+
+	return paths
+}
+
 // GetDiffFilePaths enumerates all the changed file paths between the two
 // commits. If one of the commits is nil, the other commit's tree is enumerated.
 func GetDiffFilePaths(commitA, commitB *object.Commit) ([]string, error) {
@@ -91,10 +222,13 @@ func GetDiffFilePaths(commitA, commitB *object.Commit) ([]string, error) {
 
 // diff is a helper that enumerates and sorts the paths of all files that differ
 // between the two trees. If a file is renamed, both its source name and
-// destination name are recorded.
+// destination name are recorded. Renames are not detected as that requires
+// reading the contents of the files' blobs, which may not be present in the
+// repository. Instead, a renamed file is seen as a deletion of the source and
+// an addition of the destination, which results in the same set of paths.
 func diff(treeA, treeB *object.Tree) ([]string, error) {
 	changesSet := map[string]bool{}
-	changes, err := treeA.Diff(treeB)
+	changes, err := object.DiffTreeWithOptions(context.Background(), treeA, treeB, &object.DiffTreeOptions{DetectRenames: false})
 	if err != nil {
 		return nil, err
 	}
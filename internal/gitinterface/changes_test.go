@@ -4,6 +4,7 @@ package gitinterface
 
 import (
 	"fmt"
+	"sort"
 	"testing"
 
 	"github.com/gittuf/gittuf/internal/third_party/go-git"
@@ -597,4 +598,184 @@ func TestGetFilePathsChangedByCommit(t *testing.T) {
 		assert.Nil(t, err)
 		assert.Equal(t, []string{"a"}, diffs)
 	})
+
+	t.Run("blobs missing from repository", func(t *testing.T) {
+		// Simulate a blobless partial clone, where trees refer to blobs that
+		// aren't present locally
+		missingBlobIDs := []plumbing.Hash{
+			plumbing.NewHash("1111111111111111111111111111111111111111"),
+			plumbing.NewHash("2222222222222222222222222222222222222222"),
+		}
+
+		treeA, err := WriteTree(repo, []object.TreeEntry{
+			{Name: "a", Mode: filemode.Regular, Hash: missingBlobIDs[0]},
+			{Name: "c", Mode: filemode.Regular, Hash: missingBlobIDs[0]},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// a is renamed with changes and c is modified
+		treeB, err := WriteTree(repo, []object.TreeEntry{
+			{Name: "b", Mode: filemode.Regular, Hash: missingBlobIDs[1]},
+			{Name: "c", Mode: filemode.Regular, Hash: missingBlobIDs[1]},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		cA := CreateCommitObject(testGitConfig, treeA, plumbing.ZeroHash, "Test commit", testClock)
+		cAID, err := WriteCommit(repo, cA)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		cB := CreateCommitObject(testGitConfig, treeB, cAID, "Test commit", testClock)
+		cBID, err := WriteCommit(repo, cB)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		commitA, err := repo.CommitObject(cAID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		commitB, err := repo.CommitObject(cBID)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		diffs, err := GetFilePathsChangedByCommit(repo, commitA)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"a", "c"}, diffs)
+
+		diffs, err = GetFilePathsChangedByCommit(repo, commitB)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"a", "b", "c"}, diffs)
+	})
+}
+
+func TestGetFilePathsChangedByMergeCommit(t *testing.T) {
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	blobIDs := map[string]plumbing.Hash{}
+	for _, contents := range []string{"a", "b", "c", "a-first", "a-second", "a-resolved", "malicious"} {
+		blobID, err := WriteBlob(repo, []byte(contents))
+		if err != nil {
+			t.Fatal(err)
+		}
+		blobIDs[contents] = blobID
+	}
+
+	// createCommit writes a commit with the specified parents whose tree maps
+	// each file name to the blob with the specified contents
+	createCommit := func(t *testing.T, files map[string]string, parentIDs ...plumbing.Hash) *object.Commit {
+		t.Helper()
+
+		entries := []object.TreeEntry{}
+		for name, contents := range files {
+			entries = append(entries, object.TreeEntry{Name: name, Mode: filemode.Regular, Hash: blobIDs[contents]})
+		}
+		sort.Slice(entries, func(i, j int) bool {
+			return entries[i].Name < entries[j].Name
+		})
+		treeID, err := WriteTree(repo, entries)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		commit := CreateCommitObject(testGitConfig, treeID, plumbing.ZeroHash, "Test commit", testClock)
+		commit.ParentHashes = parentIDs
+		commitID, err := WriteCommit(repo, commit)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		commit, err = repo.CommitObject(commitID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return commit
+	}
+
+	base := createCommit(t, map[string]string{"a": "a"})
+	first := createCommit(t, map[string]string{"a": "a", "b": "b"}, base.Hash)
+	second := createCommit(t, map[string]string{"a": "a", "c": "c"}, base.Hash)
+
+	t.Run("clean merge", func(t *testing.T) {
+		merge := createCommit(t, map[string]string{"a": "a", "b": "b", "c": "c"}, first.Hash, second.Hash)
+
+		paths, err := GetFilePathsChangedByMergeCommit(repo, merge)
+		assert.Nil(t, err)
+		assert.Empty(t, paths)
+	})
+
+	t.Run("sneaky merge", func(t *testing.T) {
+		// The merge modifies a file neither branch changed and adds a new file
+		merge := createCommit(t, map[string]string{"a": "malicious", "b": "b", "c": "c", "d": "malicious"}, first.Hash, second.Hash)
+
+		paths, err := GetFilePathsChangedByMergeCommit(repo, merge)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"a", "d"}, paths)
+	})
+
+	t.Run("merge that drops a change", func(t *testing.T) {
+		// Reverting one side's change matches the other side
+		merge := createCommit(t, map[string]string{"a": "a", "c": "c"}, first.Hash, second.Hash)
+
+		paths, err := GetFilePathsChangedByMergeCommit(repo, merge)
+		assert.Nil(t, err)
+		assert.Empty(t, paths)
+	})
+
+	t.Run("conflict resolution", func(t *testing.T) {
+		firstConflicting := createCommit(t, map[string]string{"a": "a-first"}, base.Hash)
+		secondConflicting := createCommit(t, map[string]string{"a": "a-second"}, base.Hash)
+
+		merge := createCommit(t, map[string]string{"a": "a-resolved"}, firstConflicting.Hash, secondConflicting.Hash)
+		paths, err := GetFilePathsChangedByMergeCommit(repo, merge)
+		assert.Nil(t, err)
+		assert.Empty(t, paths)
+
+		// The resolved path is reported separately
+		paths, err = GetFilePathsResolvedByMergeCommit(repo, merge)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"a"}, paths)
+
+		merge = createCommit(t, map[string]string{"a": "a-resolved", "b": "malicious"}, firstConflicting.Hash, secondConflicting.Hash)
+		paths, err = GetFilePathsChangedByMergeCommit(repo, merge)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"b"}, paths)
+
+		// Picking one side's version does not resolve anything
+		merge = createCommit(t, map[string]string{"a": "a-first"}, firstConflicting.Hash, secondConflicting.Hash)
+		paths, err = GetFilePathsResolvedByMergeCommit(repo, merge)
+		assert.Nil(t, err)
+		assert.Empty(t, paths)
+	})
+
+	t.Run("octopus merge", func(t *testing.T) {
+		third := createCommit(t, map[string]string{"a": "a-first"}, base.Hash)
+
+		merge := createCommit(t, map[string]string{"a": "a-first", "b": "b", "c": "c"}, first.Hash, second.Hash, third.Hash)
+		paths, err := GetFilePathsChangedByMergeCommit(repo, merge)
+		assert.Nil(t, err)
+		assert.Empty(t, paths)
+
+		merge = createCommit(t, map[string]string{"a": "a-resolved", "b": "b", "c": "c"}, first.Hash, second.Hash, third.Hash)
+		paths, err = GetFilePathsChangedByMergeCommit(repo, merge)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"a"}, paths)
+	})
+
+	t.Run("not a merge commit", func(t *testing.T) {
+		_, err := GetFilePathsChangedByMergeCommit(repo, first)
+		assert.ErrorIs(t, err, ErrNotMergeCommit)
+
+		_, err = GetFilePathsResolvedByMergeCommit(repo, first)
+		assert.ErrorIs(t, err, ErrNotMergeCommit)
+	})
 }
@@ -5,7 +5,9 @@ package gitinterface
 import (
 	"context"
 	"errors"
+	"fmt"
 	"io"
+	"time"
 
 	"github.com/gittuf/gittuf/internal/signerverifier"
 	"github.com/gittuf/gittuf/internal/third_party/go-git"
@@ -17,9 +19,63 @@ import (
 	"github.com/jonboulle/clockwork"
 )
 
+// CommitOptions contains the optional parameters of Commit.
+type CommitOptions struct {
+	// CommitterName and CommitterEmail override the committer identity that is
+	// otherwise read from the repository's Git config.
+	CommitterName  string
+	CommitterEmail string
+
+	// Clock overrides the clock used to timestamp the commit, allowing callers
+	// to create commits with deterministic timestamps.
+	Clock clockwork.Clock
+
+	// Signer overrides the signing program configured in the user's Git
+	// config when the commit is signed.
+	Signer ObjectSigner
+}
+
+// CommitOption is used to configure Commit.
+type CommitOption func(*CommitOptions)
+
+// WithCommitter sets the committer identity used for the commit, rather than
+// the user's identity from the repository's Git config. This allows commits
+// created by gittuf, such as RSL and policy commits, to be attributed to a
+// dedicated identity.
+func WithCommitter(name, email string) CommitOption {
+	return func(o *CommitOptions) {
+		o.CommitterName = name
+		o.CommitterEmail = email
+	}
+}
+
+// WithClock sets the clock used to timestamp the commit, rather than the
+// system clock. This allows commits, such as RSL and policy commits, to be
+// created reproducibly.
+func WithClock(clock clockwork.Clock) CommitOption {
+	return func(o *CommitOptions) {
+		o.Clock = clock
+	}
+}
+
+// WithSigner sets the signer used to sign the commit, rather than the signing
+// program and key configured in the user's Git config. This allows commits to
+// be signed using keys gittuf cannot access directly, such as via an external
+// signing program. The option has no effect if the commit is not signed.
+func WithSigner(signer ObjectSigner) CommitOption {
+	return func(o *CommitOptions) {
+		o.Signer = signer
+	}
+}
+
 // Commit creates a new commit in the repo and sets targetRef's HEAD to the
 // commit.
-func Commit(repo *git.Repository, treeHash plumbing.Hash, targetRef string, message string, sign bool) (plumbing.Hash, error) {
+func Commit(repo *git.Repository, treeHash plumbing.Hash, targetRef string, message string, sign bool, opts ...CommitOption) (plumbing.Hash, error) {
+	options := &CommitOptions{}
+	for _, fn := range opts {
+		fn(options)
+	}
+
 	gitConfig, err := getGitConfig(repo)
 	if err != nil {
 		return plumbing.ZeroHash, err
@@ -43,10 +99,10 @@ func Commit(repo *git.Repository, treeHash plumbing.Hash, targetRef string, mess
 		}
 	}
 
-	commit := CreateCommitObject(gitConfig, treeHash, curRef.Hash(), message, clock)
+	commit := createCommitObjectWithOptions(gitConfig, treeHash, curRef.Hash(), message, options)
 
 	if sign {
-		signature, err := signCommit(commit)
+		signature, err := signCommit(commit, options.Signer)
 		if err != nil {
 			return plumbing.ZeroHash, err
 		}
@@ -56,6 +112,51 @@ func Commit(repo *git.Repository, treeHash plumbing.Hash, targetRef string, mess
 	return ApplyCommit(repo, commit, curRef)
 }
 
+// CreateCommitObjectForRef returns the unsigned commit that Commit would create
+// for targetRef with the specified tree and message. The commit is not written
+// to the repository and targetRef is not updated, so this can be used to
+// preview a commit before it's created.
+func CreateCommitObjectForRef(repo *git.Repository, treeHash plumbing.Hash, targetRef string, message string, opts ...CommitOption) (*object.Commit, error) {
+	options := &CommitOptions{}
+	for _, fn := range opts {
+		fn(options)
+	}
+
+	gitConfig, err := getGitConfig(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	parentHash := plumbing.ZeroHash
+	curRef, err := repo.Reference(plumbing.ReferenceName(targetRef), true)
+	if err == nil {
+		parentHash = curRef.Hash()
+	} else if !errors.Is(err, plumbing.ErrReferenceNotFound) {
+		return nil, err
+	}
+
+	return createCommitObjectWithOptions(gitConfig, treeHash, parentHash, message, options), nil
+}
+
+// SignCommit signs the commit using the signer set with WithSigner or, if no
+// signer is set, the signing program in the user's Git config. Any existing
+// signature is replaced. This can be used to sign a commit created using
+// CreateCommitObjectForRef before it's written to the repository.
+func SignCommit(commit *object.Commit, opts ...CommitOption) error {
+	options := &CommitOptions{}
+	for _, fn := range opts {
+		fn(options)
+	}
+
+	signature, err := signCommit(commit, options.Signer)
+	if err != nil {
+		return err
+	}
+	commit.PGPSignature = signature
+
+	return nil
+}
+
 // ApplyCommit writes a commit object in the repository and updates the
 // specified reference to point to the commit.
 func ApplyCommit(repo *git.Repository, commit *object.Commit, curRef *plumbing.Reference) (plumbing.Hash, error) {
@@ -80,26 +181,140 @@ func WriteCommit(repo *git.Repository, commit *object.Commit) (plumbing.Hash, er
 }
 
 // VerifyCommitSignature is used to verify a cryptographic signature associated
-// with commit using TUF public keys.
-func VerifyCommitSignature(ctx context.Context, commit *object.Commit, key *tuf.Key) error {
+// with commit using TUF public keys. The options can be used to verify Sigstore
+// signatures issued by a private Sigstore deployment.
+func VerifyCommitSignature(ctx context.Context, commit *object.Commit, key *tuf.Key, opts ...VerificationOption) error {
+	_, err := VerifyCommitSignatureWithMetadata(ctx, commit, key, opts...)
+	return err
+}
+
+// VerifyCommitSignatureWithMetadata is used to verify a cryptographic signature
+// associated with commit using TUF public keys. If the signature is verified
+// successfully, information about the signature such as the key that verified
+// it and the time it was created is returned. If the key is restricted to a
+// validity window using WithKeyValidityWindow and the trusted time set using
+// WithTrustedTime, or the commit's committer time if no trusted time is set, is
+// outside it, ErrOutsideKeyValidityWindow is returned, which also wraps
+// ErrIncorrectVerificationKey.
+func VerifyCommitSignatureWithMetadata(ctx context.Context, commit *object.Commit, key *tuf.Key, opts ...VerificationOption) (*SignatureMetadata, error) {
+	metadata, err := verifyCommitSignature(ctx, commit, []byte(commit.PGPSignature), key, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkKeyValidityWindow(commit.Committer.When, opts...); err != nil {
+		return nil, err
+	}
+
+	return metadata, nil
+}
+
+// VerifyDetachedCommitSignature is used to verify a cryptographic signature
+// over commit that is stored outside the commit, such as in an attestation or a
+// Git note, using TUF public keys. The signature must be over the commit's
+// contents without any embedded signature, which is what Git signs when the
+// signature is embedded in the commit. The signature is expected in the same
+// format as embedded signatures for the key's type. Any signature embedded in
+// the commit is ignored. The key validity window is checked as described for
+// VerifyCommitSignatureWithMetadata.
+func VerifyDetachedCommitSignature(ctx context.Context, commit *object.Commit, signature []byte, key *tuf.Key, opts ...VerificationOption) error {
+	if _, err := verifyCommitSignature(ctx, commit, signature, key, opts...); err != nil {
+		return err
+	}
+
+	return checkKeyValidityWindow(commit.Committer.When, opts...)
+}
+
+// checkKeyValidityWindow checks that the signed object existed within the key's
+// validity window, if one is set using WithKeyValidityWindow. The trusted time
+// set using WithTrustedTime is checked if it is set. Otherwise, objectTime, the
+// timestamp recorded in the signed object, is checked. As the object's
+// timestamp is controlled by the signer, who could backdate the object to a
+// time the key was trusted, callers that know when the object was recorded
+// must set the trusted time.
+func checkKeyValidityWindow(objectTime time.Time, opts ...VerificationOption) error {
+	options := &VerificationOptions{}
+	for _, fn := range opts {
+		fn(options)
+	}
+	if options.KeyNotBefore.IsZero() && options.KeyNotAfter.IsZero() {
+		return nil
+	}
+
+	trustedTime := options.TrustedTime
+	if trustedTime.IsZero() {
+		trustedTime = objectTime
+	}
+	if !options.KeyNotBefore.IsZero() && trustedTime.Before(options.KeyNotBefore) {
+		return errors.Join(ErrIncorrectVerificationKey, fmt.Errorf("%w: signature verified as of %s, key trusted from %s", ErrOutsideKeyValidityWindow, trustedTime.UTC().Format(time.RFC3339), options.KeyNotBefore.UTC().Format(time.RFC3339)))
+	}
+	if !options.KeyNotAfter.IsZero() && trustedTime.After(options.KeyNotAfter) {
+		return errors.Join(ErrIncorrectVerificationKey, fmt.Errorf("%w: signature verified as of %s, key trusted until %s", ErrOutsideKeyValidityWindow, trustedTime.UTC().Format(time.RFC3339), options.KeyNotAfter.UTC().Format(time.RFC3339)))
+	}
+
+	return nil
+}
+
+// verifyCommitSignature verifies the signature over the commit's contents
+// using the key based on the key's type.
+func verifyCommitSignature(ctx context.Context, commit *object.Commit, signature []byte, key *tuf.Key, opts ...VerificationOption) (*SignatureMetadata, error) {
 	switch key.KeyType {
 	case signerverifier.GPGKeyType:
-		if _, err := commit.Verify(key.KeyVal.Public); err != nil {
-			return ErrIncorrectVerificationKey
+		commitContents, err := getCommitBytesWithoutSignature(commit)
+		if err != nil {
+			return nil, err
 		}
 
-		return nil
+		signingTime, err := verifyGPGSignature(key, commitContents, string(signature), opts...)
+		if err != nil {
+			return nil, err
+		}
+
+		return &SignatureMetadata{Key: key, SigningTime: signingTime}, nil
 	case signerverifier.FulcioKeyType:
 		commitContents, err := getCommitBytesWithoutSignature(commit)
 		if err != nil {
-			return errors.Join(ErrVerifyingSigstoreSignature, err)
+			return nil, errors.Join(ErrVerifyingSigstoreSignature, err)
+		}
+
+		cert, err := verifyGitsignSignature(ctx, key, commitContents, signature, opts...)
+		if err != nil {
+			return nil, err
+		}
+
+		return &SignatureMetadata{
+			Key:         key,
+			Identity:    key.KeyVal.Identity,
+			Issuer:      key.KeyVal.Issuer,
+			SigningTime: cert.NotBefore,
+		}, nil
+	case signerverifier.MinisignKeyType:
+		commitContents, err := getCommitBytesWithoutSignature(commit)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := verifyMinisignSignature(key, commitContents, string(signature)); err != nil {
+			return nil, err
 		}
-		commitSignature := []byte(commit.PGPSignature)
 
-		return verifyGitsignSignature(ctx, key, commitContents, commitSignature)
+		return &SignatureMetadata{Key: key}, nil
 	}
 
-	return ErrUnknownSigningMethod
+	if signerverifier.IsRegisteredKeyType(key.KeyType) {
+		commitContents, err := getCommitBytesWithoutSignature(commit)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := verifyRegisteredKeyTypeSignature(ctx, key, commitContents, signature); err != nil {
+			return nil, err
+		}
+
+		return &SignatureMetadata{Key: key}, nil
+	}
+
+	return nil, ErrUnknownSigningMethod
 }
 
 // CreateCommitObject returns a commit object using the specified parameters.
@@ -123,6 +338,37 @@ func CreateCommitObject(gitConfig *config.Config, treeHash plumbing.Hash, parent
 	return commit
 }
 
+// createCommitObjectWithOptions returns a commit object using the specified
+// parameters, applying the committer identity and clock set in options.
+func createCommitObjectWithOptions(gitConfig *config.Config, treeHash plumbing.Hash, parentHash plumbing.Hash, message string, options *CommitOptions) *object.Commit {
+	commitClock := clock
+	if options.Clock != nil {
+		commitClock = options.Clock
+	}
+
+	commit := CreateCommitObject(gitConfig, treeHash, parentHash, message, commitClock)
+	if len(options.CommitterName) > 0 {
+		commit.Committer.Name = options.CommitterName
+	}
+	if len(options.CommitterEmail) > 0 {
+		commit.Committer.Email = options.CommitterEmail
+	}
+
+	return commit
+}
+
+// ReadCommit returns the commit with the specified ID. If ctx is done before
+// the commit is read in full, the context's error is returned. The returned
+// commit reads the objects it refers to without regard to ctx.
+func ReadCommit(ctx context.Context, repo *git.Repository, commitID plumbing.Hash) (*object.Commit, error) {
+	obj, err := (&contextStorer{EncodedObjectStorer: repo.Storer, ctx: ctx}).EncodedObject(plumbing.CommitObject, commitID)
+	if err != nil {
+		return nil, err
+	}
+
+	return object.DecodeCommit(repo.Storer, obj)
+}
+
 // KnowsCommit indicates if the commit under test, identified by commitID, has a
 // path to commit. If commit is the same as the commit under test or if commit
 // is an ancestor of commit under test, KnowsCommit returns true.
@@ -139,15 +385,25 @@ func KnowsCommit(repo *git.Repository, commitID plumbing.Hash, commit *object.Co
 	return commit.IsAncestor(commitUnderTest)
 }
 
-func signCommit(commit *object.Commit) (string, error) {
+// signCommit signs the commit using the signer if one is specified, and using
+// the user's Git config otherwise.
+func signCommit(commit *object.Commit, signer ObjectSigner) (string, error) {
 	commitContents, err := getCommitBytesWithoutSignature(commit)
 	if err != nil {
 		return "", err
 	}
 
+	if signer != nil {
+		return signGitObjectWithSigner(context.Background(), commitContents, signer)
+	}
+
 	return signGitObject(commitContents)
 }
 
+// getCommitBytesWithoutSignature returns the encoding of the commit that is
+// signed. The tree and parent IDs are encoded in the width of the object format
+// gittuf is built for, so SHA-256 commits can be verified using a build with
+// the sha256 tag.
 func getCommitBytesWithoutSignature(commit *object.Commit) ([]byte, error) {
 	commitEncoded := memory.NewStorage().NewEncodedObject()
 	if err := commit.EncodeWithoutSignature(commitEncoded); err != nil {
@@ -5,24 +5,163 @@ package gitinterface
 import (
 	"bytes"
 	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/ProtonMail/go-crypto/openpgp/packet"
 	"github.com/gittuf/gittuf/internal/signerverifier"
+	"github.com/gittuf/gittuf/internal/signerverifier/external"
 	"github.com/gittuf/gittuf/internal/signerverifier/gpg"
+	"github.com/gittuf/gittuf/internal/signerverifier/minisign"
 	"github.com/gittuf/gittuf/internal/third_party/go-git"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/config"
 	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing"
 	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing/object"
 	"github.com/gittuf/gittuf/internal/third_party/go-git/storage/memory"
+	"github.com/gittuf/gittuf/internal/tuf"
 	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/jonboulle/clockwork"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
 	sslibsv "github.com/secure-systems-lab/go-securesystemslib/signerverifier"
 	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/blake2b"
 )
 
+func TestCommit(t *testing.T) {
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	refName := "refs/heads/main"
+	clock = testClock
+	getGitConfig = func(repo *git.Repository) (*config.Config, error) {
+		return testGitConfig, nil
+	}
+
+	emptyTreeHash, err := WriteTree(repo, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("use committer from Git config", func(t *testing.T) {
+		commitID, err := Commit(repo, emptyTreeHash, refName, "Initial commit", false)
+		assert.Nil(t, err)
+
+		commit, err := repo.CommitObject(commitID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, testGitConfig.User.Name, commit.Committer.Name)
+		assert.Equal(t, testGitConfig.User.Email, commit.Committer.Email)
+		assert.Equal(t, commit.Author, commit.Committer)
+	})
+
+	t.Run("use specified committer", func(t *testing.T) {
+		commitID, err := Commit(repo, emptyTreeHash, refName, "Second commit", false, WithCommitter("gittuf", "gittuf@example.com"))
+		assert.Nil(t, err)
+
+		commit, err := repo.CommitObject(commitID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, "gittuf", commit.Committer.Name)
+		assert.Equal(t, "gittuf@example.com", commit.Committer.Email)
+		assert.Equal(t, testGitConfig.User.Name, commit.Author.Name)
+		assert.Equal(t, testGitConfig.User.Email, commit.Author.Email)
+
+		tip, err := GetTip(repo, refName)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, commitID, tip)
+	})
+
+	t.Run("use specified clock", func(t *testing.T) {
+		fixedClock := clockwork.NewFakeClockAt(time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC))
+
+		commitID, err := Commit(repo, emptyTreeHash, refName, "Third commit", false, WithClock(fixedClock))
+		assert.Nil(t, err)
+
+		commit, err := repo.CommitObject(commitID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.True(t, fixedClock.Now().Equal(commit.Committer.When))
+		assert.True(t, fixedClock.Now().Equal(commit.Author.When))
+	})
+
+	t.Run("use specified signer", func(t *testing.T) {
+		if runtime.GOOS == "windows" {
+			t.Skip("fake signing programs are shell scripts")
+		}
+
+		// The fake signing program records the signed contents
+		tmpDir := t.TempDir()
+		signedContentsPath := filepath.Join(tmpDir, "signed-contents")
+		program := filepath.Join(tmpDir, "signer")
+		script := fmt.Sprintf("#!/bin/sh\ncat > '%s'\nprintf 'fake signature'\n", signedContentsPath)
+		if err := os.WriteFile(program, []byte(script), 0o755); err != nil { //nolint:gosec
+			t.Fatal(err)
+		}
+		signer, err := external.NewSigner(program, "test-key")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		commitID, err := Commit(repo, emptyTreeHash, refName, "Signed commit", true, WithSigner(signer))
+		assert.Nil(t, err)
+
+		commit, err := repo.CommitObject(commitID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// The signature is stored as a newline terminated block
+		assert.Equal(t, "fake signature\n", commit.PGPSignature)
+
+		expectedContents, err := getCommitBytesWithoutSignature(commit)
+		if err != nil {
+			t.Fatal(err)
+		}
+		signedContents, err := os.ReadFile(signedContentsPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, expectedContents, signedContents)
+
+		// A failing signer does not create a commit
+		failingProgram := filepath.Join(tmpDir, "failing-signer")
+		if err := os.WriteFile(failingProgram, []byte("#!/bin/sh\nexit 1\n"), 0o755); err != nil { //nolint:gosec
+			t.Fatal(err)
+		}
+		failingSigner, err := external.NewSigner(failingProgram, "test-key")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = Commit(repo, emptyTreeHash, refName, "Unsigned commit", true, WithSigner(failingSigner))
+		assert.ErrorIs(t, err, ErrUnableToSign)
+		assert.ErrorIs(t, err, external.ErrSigningProgramFailed)
+
+		tip, err := GetTip(repo, refName)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, commitID, tip)
+	})
+}
+
 func TestCreateCommitObject(t *testing.T) {
 	commit := CreateCommitObject(testGitConfig, plumbing.ZeroHash, plumbing.ZeroHash, "Test commit", testClock)
 
@@ -34,51 +173,111 @@ func TestCreateCommitObject(t *testing.T) {
 	assert.Equal(t, "22ddfd55fb5fba7b37b50b068d1527a1b0f9f561", enc.Hash().String())
 }
 
+func TestCreateCommitObjectForRef(t *testing.T) {
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	refName := "refs/heads/main"
+	clock = testClock
+	getGitConfig = func(repo *git.Repository) (*config.Config, error) {
+		return testGitConfig, nil
+	}
+
+	emptyTreeHash, err := WriteTree(repo, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("ref does not exist", func(t *testing.T) {
+		commit, err := CreateCommitObjectForRef(repo, emptyTreeHash, refName, "Initial commit")
+		assert.Nil(t, err)
+		assert.Empty(t, commit.ParentHashes)
+		assert.Equal(t, emptyTreeHash, commit.TreeHash)
+
+		_, err = repo.Reference(plumbing.ReferenceName(refName), true)
+		assert.ErrorIs(t, err, plumbing.ErrReferenceNotFound)
+	})
+
+	t.Run("matches created commit", func(t *testing.T) {
+		parentID, err := Commit(repo, emptyTreeHash, refName, "Initial commit", false)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		commit, err := CreateCommitObjectForRef(repo, emptyTreeHash, refName, "Second commit", WithCommitter("gittuf", "gittuf@example.com"))
+		assert.Nil(t, err)
+		assert.Equal(t, []plumbing.Hash{parentID}, commit.ParentHashes)
+		assert.Equal(t, "gittuf", commit.Committer.Name)
+
+		tip, err := GetTip(repo, refName)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, parentID, tip)
+
+		enc := memory.NewStorage().NewEncodedObject()
+		if err := commit.Encode(enc); err != nil {
+			t.Fatal(err)
+		}
+
+		commitID, err := Commit(repo, emptyTreeHash, refName, "Second commit", false, WithCommitter("gittuf", "gittuf@example.com"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, commitID, enc.Hash())
+	})
+}
+
+func TestSignCommit(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake signing programs are shell scripts")
+	}
+
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clock = testClock
+	getGitConfig = func(repo *git.Repository) (*config.Config, error) {
+		return testGitConfig, nil
+	}
+
+	emptyTreeHash, err := WriteTree(repo, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	program := filepath.Join(t.TempDir(), "signer")
+	if err := os.WriteFile(program, []byte("#!/bin/sh\ncat > /dev/null\necho 'fake signature'\n"), 0o755); err != nil { //nolint:gosec
+		t.Fatal(err)
+	}
+	signer, err := external.NewSigner(program, "test-key")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	commit, err := CreateCommitObjectForRef(repo, emptyTreeHash, "refs/heads/main", "Signed commit")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = SignCommit(commit, WithSigner(signer))
+	assert.Nil(t, err)
+	assert.Equal(t, "fake signature\n", commit.PGPSignature)
+
+	// The signed commit is not written to the repository
+	_, err = repo.Reference(plumbing.ReferenceName("refs/heads/main"), true)
+	assert.ErrorIs(t, err, plumbing.ErrReferenceNotFound)
+}
+
 func TestVerifyCommitSignature(t *testing.T) {
 	gpgSignedCommit := createTestSignedCommit(t)
 
 	// FIXME: fix gitsign testing
-	gitsignSignedCommit := &object.Commit{
-		Hash: plumbing.NewHash("d6b230478965e25477263aa65f1ca6d23d0c0d97"),
-		Author: object.Signature{
-			Name:  "Aditya Sirish",
-			Email: "aditya@saky.in",
-			When:  time.Date(2023, time.August, 1, 15, 44, 23, 0, time.FixedZone("", -4*3600)),
-		},
-		Committer: object.Signature{
-			Name:  "Aditya Sirish",
-			Email: "aditya@saky.in",
-			When:  time.Date(2023, time.August, 1, 15, 44, 23, 0, time.FixedZone("", -4*3600)),
-		},
-		PGPSignature: `-----BEGIN SIGNED MESSAGE-----
-MIIEMAYJKoZIhvcNAQcCoIIEITCCBB0CAQExDTALBglghkgBZQMEAgEwCwYJKoZI
-hvcNAQcBoIIC0DCCAswwggJToAMCAQICFHIJCrBVHxoHlGos++k1xJxcElGaMAoG
-CCqGSM49BAMDMDcxFTATBgNVBAoTDHNpZ3N0b3JlLmRldjEeMBwGA1UEAxMVc2ln
-c3RvcmUtaW50ZXJtZWRpYXRlMB4XDTIzMDgwMTE5NDQzMVoXDTIzMDgwMTE5NTQz
-MVowADBZMBMGByqGSM49AgEGCCqGSM49AwEHA0IABD8d752TJfGtANVYoiJJn+o6
-JPKj5NwEZs1AcVRT2qElikVun5t+bQ07iDFa/Xiun5ytZrEK2YJVgqdntLd6hSOj
-ggFyMIIBbjAOBgNVHQ8BAf8EBAMCB4AwEwYDVR0lBAwwCgYIKwYBBQUHAwMwHQYD
-VR0OBBYEFAuYzgyBA01YSSN1v0fYenGo7+PcMB8GA1UdIwQYMBaAFN/T6c9WJBGW
-+ajY6ShVosYuGGQ/MBwGA1UdEQEB/wQSMBCBDmFkaXR5YUBzYWt5LmluMCwGCisG
-AQQBg78wAQEEHmh0dHBzOi8vZ2l0aHViLmNvbS9sb2dpbi9vYXV0aDAuBgorBgEE
-AYO/MAEIBCAMHmh0dHBzOi8vZ2l0aHViLmNvbS9sb2dpbi9vYXV0aDCBigYKKwYB
-BAHWeQIEAgR8BHoAeAB2AN09MGrGxxEyYxkeHJlnNwKiSl643jyt/4eKcoAvKe6O
-AAABibKhcJgAAAQDAEcwRQIgcWuz6NhFgdL0fNni6j0SOQnAgFpPEaN8jDH70mbD
-uPMCIQCX8koEnIX4c9crMT1hfoBBf1Z/CHJ6HLLHpQwWfEUMIzAKBggqhkjOPQQD
-AwNnADBkAjBozIBaBtEu7JUyYLH7Ly698E0o8DdIOmqcUMUYWNC6zyJVdrL5gAla
-mQSxfObSQasCMHQuw8youTjmFJXT7pNOYX4DW25knt+6P+W/m6zwcRRe3dMjmUAB
-gdBJb32+XXJMRDGCASYwggEiAgEBME8wNzEVMBMGA1UEChMMc2lnc3RvcmUuZGV2
-MR4wHAYDVQQDExVzaWdzdG9yZS1pbnRlcm1lZGlhdGUCFHIJCrBVHxoHlGos++k1
-xJxcElGaMAsGCWCGSAFlAwQCAaBpMBgGCSqGSIb3DQEJAzELBgkqhkiG9w0BBwEw
-HAYJKoZIhvcNAQkFMQ8XDTIzMDgwMTE5NDQzMlowLwYJKoZIhvcNAQkEMSIEIBe6
-VHcVlkO8jRm/fbUipwxwxNaI7UFDAL38Jl8eUj/5MAoGCCqGSM49BAMCBEgwRgIh
-AIYiRbnVeWjjgX2XwljDryzQN5RhUQaVH/AcUj+tbvWxAiEAhm9l3BU58tQsgyJW
-oYBpMWLgg6AUzpxx9mITZ2EKr4c=
------END SIGNED MESSAGE-----
-`,
-		Message:  "Test commit\n",
-		TreeHash: plumbing.NewHash("4b825dc642cb6eb9a060e54bf8d69288fbee4904"),
-	}
+	gitsignSignedCommit := createTestGitsignSignedCommit()
 
 	keyBytes, err := os.ReadFile(filepath.Join("test-data", "gpg-pubkey.asc"))
 	if err != nil {
@@ -119,6 +318,549 @@ oYBpMWLgg6AUzpxx9mITZ2EKr4c=
 		err := VerifyCommitSignature(context.Background(), gitsignSignedCommit, gpgKey)
 		assert.ErrorIs(t, err, ErrIncorrectVerificationKey)
 	})
+
+	t.Run("gitsign signed commit with custom trust bundle", func(t *testing.T) {
+		// The commit's signing certificate is not issued by the private
+		// Fulcio instance
+		bundle, _ := createTestFulcioTrustBundle(t)
+		roots, intermediates, err := LoadFulcioTrustBundle(bundle)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = VerifyCommitSignature(context.Background(), gitsignSignedCommit, fulcioKey, WithFulcioRoots(roots, intermediates), WithRekorURL("https://rekor.example.com"))
+		assert.ErrorIs(t, err, ErrIncorrectVerificationKey)
+	})
+
+	t.Run("gitsign signed commit with custom trust bundle from environment", func(t *testing.T) {
+		bundle, _ := createTestFulcioTrustBundle(t)
+		t.Setenv(SigstoreFulcioRootsEnvKey, writeTestFulcioTrustBundle(t, bundle))
+		t.Setenv(SigstoreRekorURLEnvKey, "https://rekor.example.com")
+
+		err := VerifyCommitSignature(context.Background(), gitsignSignedCommit, fulcioKey)
+		assert.ErrorIs(t, err, ErrIncorrectVerificationKey)
+	})
+
+	t.Run("gitsign signed commit with untrusted OIDC issuer", func(t *testing.T) {
+		err := VerifyCommitSignature(context.Background(), gitsignSignedCommit, fulcioKey, WithTrustedOIDCIssuers("https://accounts.example.com"))
+		assert.ErrorIs(t, err, ErrIncorrectVerificationKey)
+		assert.ErrorIs(t, err, ErrUntrustedOIDCIssuer)
+	})
+
+	t.Run("gpg signed commit inside key validity window", func(t *testing.T) {
+		// The commit is created and recorded at the time of testClock
+		trustedTime := testClock.Now()
+
+		err := VerifyCommitSignature(context.Background(), gpgSignedCommit, gpgKey, WithKeyValidityWindow(trustedTime.Add(-time.Hour), trustedTime.Add(time.Hour)), WithTrustedTime(trustedTime))
+		assert.Nil(t, err)
+
+		err = VerifyCommitSignature(context.Background(), gpgSignedCommit, gpgKey, WithKeyValidityWindow(trustedTime, time.Time{}), WithTrustedTime(trustedTime))
+		assert.Nil(t, err)
+
+		err = VerifyCommitSignature(context.Background(), gpgSignedCommit, gpgKey, WithKeyValidityWindow(time.Time{}, trustedTime.Add(time.Hour)), WithTrustedTime(trustedTime))
+		assert.Nil(t, err)
+	})
+
+	t.Run("gpg signed commit before key validity window", func(t *testing.T) {
+		notBefore := testClock.Now().Add(24 * time.Hour)
+
+		err := VerifyCommitSignature(context.Background(), gpgSignedCommit, gpgKey, WithKeyValidityWindow(notBefore, time.Time{}), WithTrustedTime(testClock.Now()))
+		assert.ErrorIs(t, err, ErrIncorrectVerificationKey)
+		assert.ErrorIs(t, err, ErrOutsideKeyValidityWindow)
+	})
+
+	t.Run("gpg signed commit after key validity window", func(t *testing.T) {
+		notAfter := testClock.Now().Add(-24 * time.Hour)
+
+		err := VerifyCommitSignature(context.Background(), gpgSignedCommit, gpgKey, WithKeyValidityWindow(time.Time{}, notAfter), WithTrustedTime(testClock.Now()))
+		assert.ErrorIs(t, err, ErrIncorrectVerificationKey)
+		assert.ErrorIs(t, err, ErrOutsideKeyValidityWindow)
+	})
+
+	t.Run("backdated gpg signed commit after key validity window", func(t *testing.T) {
+		// The commit claims to be created within the window, but was only
+		// recorded after the window ended
+		notAfter := testClock.Now().Add(time.Hour)
+
+		err := VerifyCommitSignature(context.Background(), gpgSignedCommit, gpgKey, WithKeyValidityWindow(time.Time{}, notAfter), WithTrustedTime(notAfter.Add(time.Hour)))
+		assert.ErrorIs(t, err, ErrIncorrectVerificationKey)
+		assert.ErrorIs(t, err, ErrOutsideKeyValidityWindow)
+
+		// Without a trusted time, the window is checked against the commit's
+		// own timestamp
+		err = VerifyCommitSignature(context.Background(), gpgSignedCommit, gpgKey, WithKeyValidityWindow(time.Time{}, notAfter))
+		assert.Nil(t, err)
+	})
+}
+
+func TestVerifyCommitSignatureWithGPGSubkey(t *testing.T) {
+	subkeySignedCommit := createTestSignedCommitWithKey(t, "gpg-subkey-privkey.asc")
+
+	keyBytes, err := os.ReadFile(filepath.Join("test-data", "gpg-subkey-pubkey.asc"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	subkeyKey, err := gpg.LoadGPGKeyFromBytes(keyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyBytes, err = os.ReadFile(filepath.Join("test-data", "gpg-pubkey.asc"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	gpgKey, err := gpg.LoadGPGKeyFromBytes(keyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("commit signed by subkey of trusted key", func(t *testing.T) {
+		// The primary key can only certify, so the commit must be signed by the
+		// signing subkey
+		assert.Equal(t, "f81cc338de644ad0962cbdc012028d6dca498871", subkeyKey.KeyID)
+
+		err := VerifyCommitSignature(context.Background(), subkeySignedCommit, subkeyKey)
+		assert.Nil(t, err)
+	})
+
+	t.Run("commit signed by subkey of untrusted key", func(t *testing.T) {
+		err := VerifyCommitSignature(context.Background(), subkeySignedCommit, gpgKey)
+		assert.ErrorIs(t, err, ErrIncorrectVerificationKey)
+	})
+}
+
+func TestVerifyCommitSignatureWithMultipleGPGUserIDs(t *testing.T) {
+	// The commit is authored using the key's secondary user ID
+	commit := createTestSignedCommitWithKeyAndAuthor(t, "gpg-multiple-uids-privkey.asc", object.Signature{
+		Name:  "Jane Doe",
+		Email: "jane@example.org",
+		When:  testClock.Now(),
+	})
+
+	keyBytes, err := os.ReadFile(filepath.Join("test-data", "gpg-multiple-uids-pubkey.asc"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := gpg.LoadGPGKeyFromBytes(keyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	emails, err := gpg.GetEmails(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Contains(t, emails, commit.Author.Email)
+
+	err = VerifyCommitSignature(context.Background(), commit, key)
+	assert.Nil(t, err)
+}
+
+func TestVerifyCommitSignatureWithExpiredGPGKey(t *testing.T) {
+	// The key was created on 2020-01-01 and expired on 2021-01-01
+	keyBytes, err := os.ReadFile(filepath.Join("test-data", "gpg-expired-pubkey.asc"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expiredKey, err := gpg.LoadGPGKeyFromBytes(keyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	createCommit := func(when time.Time, signature string) *object.Commit {
+		return &object.Commit{
+			Author: object.Signature{
+				Name:  "Jane Doe",
+				Email: "jane.doe@example.com",
+				When:  when,
+			},
+			Committer: object.Signature{
+				Name:  "Jane Doe",
+				Email: "jane.doe@example.com",
+				When:  when,
+			},
+			PGPSignature: signature,
+			Message:      "Test commit\n",
+			TreeHash:     plumbing.NewHash("4b825dc642cb6eb9a060e54bf8d69288fbee4904"),
+		}
+	}
+
+	t.Run("commit signed before key expired", func(t *testing.T) {
+		commit := createCommit(time.Date(2020, time.June, 1, 0, 0, 0, 0, time.UTC), `-----BEGIN PGP SIGNATURE-----
+
+iHUEABYIAB0WIQQrhDiRZSusWkF29MtZHcuDWMnpkQUCXtRFAAAKCRBZHcuDWMnp
+kW2vAQDjPjKKNw/xZ+/xCxJlDc78hhrsftIMwjS/NVYCqE3aYAEAqTlO6MZmiEkL
+WCtGT4gdnIWEr2h9syE4NFFUsrIaeA4=
+=L3C5
+-----END PGP SIGNATURE-----
+`)
+
+		metadata, err := VerifyCommitSignatureWithMetadata(context.Background(), commit, expiredKey, WithTrustedTime(commit.Committer.When))
+		assert.Nil(t, err)
+		assert.Equal(t, time.Date(2020, time.June, 1, 0, 0, 0, 0, time.UTC), metadata.SigningTime.UTC())
+
+		// The commit was only recorded after the key expired, so its
+		// signature is no longer trusted even though it claims to have been
+		// created before
+		_, err = VerifyCommitSignatureWithMetadata(context.Background(), commit, expiredKey, WithTrustedTime(time.Date(2021, time.June, 1, 0, 0, 0, 0, time.UTC)))
+		assert.ErrorIs(t, err, ErrExpiredGPGKey)
+		assert.ErrorIs(t, err, ErrIncorrectVerificationKey)
+	})
+
+	t.Run("commit signed after key expired", func(t *testing.T) {
+		commit := createCommit(time.Date(2021, time.June, 1, 0, 0, 0, 0, time.UTC), `-----BEGIN PGP SIGNATURE-----
+
+iHUEABYIAB0WIQQrhDiRZSusWkF29MtZHcuDWMnpkQUCYLV4gAAKCRBZHcuDWMnp
+kVuCAP9JoifNi0+bHsDb6jGp5kttSYPeou5gokPy1drQ5IUVjgEA6W7LyNvjrAnN
+bAbZa3sjbwuD2mK3pihUNOBGna98mgI=
+=DCrr
+-----END PGP SIGNATURE-----
+`)
+
+		err := VerifyCommitSignature(context.Background(), commit, expiredKey, WithTrustedTime(commit.Committer.When))
+		assert.ErrorIs(t, err, ErrExpiredGPGKey)
+		assert.ErrorIs(t, err, ErrIncorrectVerificationKey)
+	})
+}
+
+func TestVerifyCommitSignatureWithMinisignKey(t *testing.T) {
+	seed := sha256.Sum256([]byte("gittuf minisign test key"))
+	privateKey := ed25519.NewKeyFromSeed(seed[:])
+	keyNum := []byte{0xd4, 0x67, 0x0a, 0x7e, 0x1e, 0x2a, 0xf9, 0x3e}
+
+	keyBytes := append([]byte("Ed"), keyNum...)
+	keyBytes = append(keyBytes, privateKey.Public().(ed25519.PublicKey)...)
+	minisignKey, err := minisign.LoadMinisignPublicKeyFromBytes([]byte(fmt.Sprintf("untrusted comment: minisign public key\n%s\n", base64.StdEncoding.EncodeToString(keyBytes))))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gpgKeyBytes, err := os.ReadFile(filepath.Join("test-data", "gpg-pubkey.asc"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	gpgKey, err := gpg.LoadGPGKeyFromBytes(gpgKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	createMinisignSignedCommit := func(t *testing.T) *object.Commit {
+		t.Helper()
+
+		commit := CreateCommitObject(testGitConfig, EmptyTree(), plumbing.ZeroHash, "Test commit", testClock)
+		commitContents, err := getCommitBytesWithoutSignature(commit)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// minisign signs the BLAKE2b-512 digest of the data by default
+		digest := blake2b.Sum512(commitContents)
+		sig := ed25519.Sign(privateKey, digest[:])
+		sigBytes := append([]byte("ED"), keyNum...)
+		sigBytes = append(sigBytes, sig...)
+
+		trustedComment := "timestamp:1700000000"
+		globalSig := ed25519.Sign(privateKey, append(sig, []byte(trustedComment)...))
+
+		commit.PGPSignature = fmt.Sprintf("untrusted comment: signature from minisign secret key\n%s\ntrusted comment: %s\n%s\n", base64.StdEncoding.EncodeToString(sigBytes), trustedComment, base64.StdEncoding.EncodeToString(globalSig))
+		return commit
+	}
+
+	t.Run("minisign signed commit", func(t *testing.T) {
+		commit := createMinisignSignedCommit(t)
+
+		metadata, err := VerifyCommitSignatureWithMetadata(context.Background(), commit, minisignKey)
+		assert.Nil(t, err)
+		assert.Equal(t, minisignKey.KeyID, metadata.Key.KeyID)
+	})
+
+	t.Run("modified commit", func(t *testing.T) {
+		commit := createMinisignSignedCommit(t)
+		commit.Message = "Modified commit"
+
+		err := VerifyCommitSignature(context.Background(), commit, minisignKey)
+		assert.ErrorIs(t, err, ErrIncorrectVerificationKey)
+	})
+
+	t.Run("malformed minisign signature", func(t *testing.T) {
+		commit := createMinisignSignedCommit(t)
+		commit.PGPSignature = strings.Join(strings.Split(commit.PGPSignature, "\n")[:3], "\n")
+
+		err := VerifyCommitSignature(context.Background(), commit, minisignKey)
+		assert.ErrorIs(t, err, ErrIncorrectVerificationKey)
+		assert.ErrorIs(t, err, minisign.ErrInvalidMinisignSignature)
+	})
+
+	t.Run("use minisign signed commit with gpg key", func(t *testing.T) {
+		commit := createMinisignSignedCommit(t)
+
+		err := VerifyCommitSignature(context.Background(), commit, gpgKey)
+		assert.ErrorIs(t, err, ErrIncorrectVerificationKey)
+	})
+
+	t.Run("use gpg signed commit with minisign key", func(t *testing.T) {
+		commit := createTestSignedCommit(t)
+
+		err := VerifyCommitSignature(context.Background(), commit, minisignKey)
+		assert.ErrorIs(t, err, ErrIncorrectVerificationKey)
+		assert.NotErrorIs(t, err, minisign.ErrInvalidMinisignSignature)
+	})
+}
+
+func TestVerifyCommitSignatureWithRegisteredKeyType(t *testing.T) {
+	// The plugin key type uses Ed25519 signatures over the commit contents
+	keyType := "test-plugin-ed25519"
+	if err := signerverifier.RegisterKeyType(keyType, func(key *tuf.Key) (sslibdsse.SignerVerifier, error) {
+		return sslibsv.NewED25519SignerVerifierFromSSLibKey(key)
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	seed := sha256.Sum256([]byte("gittuf plugin test key"))
+	privateKey := ed25519.NewKeyFromSeed(seed[:])
+	pluginKey := &tuf.Key{
+		KeyType: keyType,
+		Scheme:  keyType,
+		KeyID:   "plugin-key",
+		KeyVal: sslibsv.KeyVal{
+			Public: hex.EncodeToString(privateKey.Public().(ed25519.PublicKey)),
+		},
+	}
+
+	createPluginSignedCommit := func(t *testing.T) *object.Commit {
+		t.Helper()
+
+		commit := CreateCommitObject(testGitConfig, EmptyTree(), plumbing.ZeroHash, "Test commit", testClock)
+		commitContents, err := getCommitBytesWithoutSignature(commit)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		commit.PGPSignature = string(ed25519.Sign(privateKey, commitContents))
+		return commit
+	}
+
+	t.Run("plugin signed commit", func(t *testing.T) {
+		commit := createPluginSignedCommit(t)
+
+		metadata, err := VerifyCommitSignatureWithMetadata(context.Background(), commit, pluginKey)
+		assert.Nil(t, err)
+		assert.Equal(t, pluginKey.KeyID, metadata.Key.KeyID)
+	})
+
+	t.Run("modified commit", func(t *testing.T) {
+		commit := createPluginSignedCommit(t)
+		commit.Message = "Modified commit"
+
+		err := VerifyCommitSignature(context.Background(), commit, pluginKey)
+		assert.ErrorIs(t, err, ErrIncorrectVerificationKey)
+	})
+
+	t.Run("use gpg signed commit with plugin key", func(t *testing.T) {
+		commit := createTestSignedCommit(t)
+
+		err := VerifyCommitSignature(context.Background(), commit, pluginKey)
+		assert.ErrorIs(t, err, ErrIncorrectVerificationKey)
+	})
+
+	t.Run("unregistered key type", func(t *testing.T) {
+		commit := createPluginSignedCommit(t)
+		unknownKey := *pluginKey
+		unknownKey.KeyType = "unregistered"
+
+		err := VerifyCommitSignature(context.Background(), commit, &unknownKey)
+		assert.ErrorIs(t, err, ErrUnknownSigningMethod)
+	})
+}
+
+func TestVerifyCommitSignatureWithRSAGPGKeys(t *testing.T) {
+	// createRSASignedCommit returns a commit signed using a new RSA GPG key of
+	// the specified size, along with the key
+	createRSASignedCommit := func(t *testing.T, bits int) (*object.Commit, *tuf.Key) {
+		t.Helper()
+
+		entity, err := openpgp.NewEntity(testName, "", testEmail, &packet.Config{Algorithm: packet.PubKeyAlgoRSA, RSABits: bits})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		publicKey := new(bytes.Buffer)
+		w, err := armor.Encode(publicKey, openpgp.PublicKeyType, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := entity.Serialize(w); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+		key, err := gpg.LoadGPGKeyFromBytes(publicKey.Bytes())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		commit := CreateCommitObject(testGitConfig, EmptyTree(), plumbing.ZeroHash, "Test commit", testClock)
+		commitContents, err := getCommitBytesWithoutSignature(commit)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sig := new(strings.Builder)
+		if err := openpgp.ArmoredDetachSign(sig, entity, bytes.NewReader(commitContents), nil); err != nil {
+			t.Fatal(err)
+		}
+		commit.PGPSignature = sig.String()
+
+		return commit, key
+	}
+
+	for _, bits := range []int{2048, 3072, 4096} {
+		t.Run(fmt.Sprintf("%d bits", bits), func(t *testing.T) {
+			commit, key := createRSASignedCommit(t, bits)
+
+			err := VerifyCommitSignature(context.Background(), commit, key)
+			assert.Nil(t, err)
+		})
+	}
+
+	t.Run("key below minimum size", func(t *testing.T) {
+		commit, key := createRSASignedCommit(t, 1024)
+
+		err := VerifyCommitSignature(context.Background(), commit, key)
+		assert.ErrorIs(t, err, ErrIncorrectVerificationKey)
+		assert.ErrorIs(t, err, signerverifier.ErrRSAKeyTooSmall)
+
+		if err := signerverifier.SetMinimumRSAKeySize(1024); err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() {
+			signerverifier.SetMinimumRSAKeySize(signerverifier.DefaultMinimumRSAKeySize) //nolint:errcheck
+		})
+
+		err = VerifyCommitSignature(context.Background(), commit, key)
+		assert.Nil(t, err)
+	})
+}
+
+func TestVerifyCommitSignatureWithMetadata(t *testing.T) {
+	gpgSignedCommit := createTestSignedCommit(t)
+
+	keyBytes, err := os.ReadFile(filepath.Join("test-data", "gpg-pubkey.asc"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gpgKey, err := gpg.LoadGPGKeyFromBytes(keyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fulcioKey := &sslibsv.SSLibKey{
+		KeyType: signerverifier.FulcioKeyType,
+		Scheme:  "fulcio",
+		KeyVal: sslibsv.KeyVal{
+			Identity: "aditya@saky.in",
+			Issuer:   "https://github.com/login/oauth",
+		},
+	}
+
+	t.Run("gpg signed commit", func(t *testing.T) {
+		metadata, err := VerifyCommitSignatureWithMetadata(context.Background(), gpgSignedCommit, gpgKey)
+		assert.Nil(t, err)
+		assert.Equal(t, gpgKey.KeyID, metadata.Key.KeyID)
+		assert.False(t, metadata.SigningTime.IsZero())
+		assert.Empty(t, metadata.Identity)
+		assert.Empty(t, metadata.Issuer)
+	})
+
+	t.Run("use gpg signed commit with gitsign key", func(t *testing.T) {
+		metadata, err := VerifyCommitSignatureWithMetadata(context.Background(), gpgSignedCommit, fulcioKey)
+		assert.ErrorIs(t, err, ErrIncorrectVerificationKey)
+		assert.Nil(t, metadata)
+	})
+}
+
+func TestVerifyDetachedCommitSignature(t *testing.T) {
+	keyBytes, err := os.ReadFile(filepath.Join("test-data", "gpg-pubkey.asc"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gpgKey, err := gpg.LoadGPGKeyFromBytes(keyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fulcioKey := &sslibsv.SSLibKey{
+		KeyType: signerverifier.FulcioKeyType,
+		Scheme:  "fulcio",
+		KeyVal: sslibsv.KeyVal{
+			Identity: "aditya@saky.in",
+			Issuer:   "https://github.com/login/oauth",
+		},
+	}
+
+	// detach removes the signature embedded in the commit and returns it
+	detach := func(commit *object.Commit) []byte {
+		signature := []byte(commit.PGPSignature)
+		commit.PGPSignature = ""
+		return signature
+	}
+
+	t.Run("gpg detached signature", func(t *testing.T) {
+		commit := createTestSignedCommit(t)
+		signature := detach(commit)
+
+		err := VerifyDetachedCommitSignature(context.Background(), commit, signature, gpgKey)
+		assert.Nil(t, err)
+
+		// The commit no longer carries an embedded signature
+		err = VerifyCommitSignature(context.Background(), commit, gpgKey)
+		assert.ErrorIs(t, err, ErrIncorrectVerificationKey)
+	})
+
+	t.Run("gpg detached signature for a different commit", func(t *testing.T) {
+		commit := createTestSignedCommit(t)
+		signature := detach(commit)
+		commit.Message = "Modified test commit"
+
+		err := VerifyDetachedCommitSignature(context.Background(), commit, signature, gpgKey)
+		assert.ErrorIs(t, err, ErrIncorrectVerificationKey)
+	})
+
+	t.Run("gpg detached signature outside key validity window", func(t *testing.T) {
+		commit := createTestSignedCommit(t)
+		signature := detach(commit)
+		notBefore := testClock.Now().Add(24 * time.Hour)
+
+		err := VerifyDetachedCommitSignature(context.Background(), commit, signature, gpgKey, WithKeyValidityWindow(notBefore, time.Time{}))
+		assert.ErrorIs(t, err, ErrOutsideKeyValidityWindow)
+	})
+
+	t.Run("gpg detached signature with gitsign key", func(t *testing.T) {
+		commit := createTestSignedCommit(t)
+		signature := detach(commit)
+
+		err := VerifyDetachedCommitSignature(context.Background(), commit, signature, fulcioKey)
+		assert.ErrorIs(t, err, ErrIncorrectVerificationKey)
+	})
+
+	t.Run("gitsign detached signature with gpg key", func(t *testing.T) {
+		commit := createTestGitsignSignedCommit()
+		signature := detach(commit)
+
+		err := VerifyDetachedCommitSignature(context.Background(), commit, signature, gpgKey)
+		assert.ErrorIs(t, err, ErrIncorrectVerificationKey)
+	})
+
+	t.Run("gitsign detached signature with untrusted OIDC issuer", func(t *testing.T) {
+		commit := createTestGitsignSignedCommit()
+		signature := detach(commit)
+
+		err := VerifyDetachedCommitSignature(context.Background(), commit, signature, fulcioKey, WithTrustedOIDCIssuers("https://accounts.example.com"))
+		assert.ErrorIs(t, err, ErrIncorrectVerificationKey)
+		assert.ErrorIs(t, err, ErrUntrustedOIDCIssuer)
+	})
 }
 
 func TestKnowsCommit(t *testing.T) {
@@ -192,20 +934,78 @@ func TestKnowsCommit(t *testing.T) {
 	})
 }
 
+// createTestGitsignSignedCommit returns a commit signed using gitsign with the
+// public Sigstore instance.
+func createTestGitsignSignedCommit() *object.Commit {
+	return &object.Commit{
+		Hash: plumbing.NewHash("d6b230478965e25477263aa65f1ca6d23d0c0d97"),
+		Author: object.Signature{
+			Name:  "Aditya Sirish",
+			Email: "aditya@saky.in",
+			When:  time.Date(2023, time.August, 1, 15, 44, 23, 0, time.FixedZone("", -4*3600)),
+		},
+		Committer: object.Signature{
+			Name:  "Aditya Sirish",
+			Email: "aditya@saky.in",
+			When:  time.Date(2023, time.August, 1, 15, 44, 23, 0, time.FixedZone("", -4*3600)),
+		},
+		PGPSignature: `-----BEGIN SIGNED MESSAGE-----
+MIIEMAYJKoZIhvcNAQcCoIIEITCCBB0CAQExDTALBglghkgBZQMEAgEwCwYJKoZI
+hvcNAQcBoIIC0DCCAswwggJToAMCAQICFHIJCrBVHxoHlGos++k1xJxcElGaMAoG
+CCqGSM49BAMDMDcxFTATBgNVBAoTDHNpZ3N0b3JlLmRldjEeMBwGA1UEAxMVc2ln
+c3RvcmUtaW50ZXJtZWRpYXRlMB4XDTIzMDgwMTE5NDQzMVoXDTIzMDgwMTE5NTQz
+MVowADBZMBMGByqGSM49AgEGCCqGSM49AwEHA0IABD8d752TJfGtANVYoiJJn+o6
+JPKj5NwEZs1AcVRT2qElikVun5t+bQ07iDFa/Xiun5ytZrEK2YJVgqdntLd6hSOj
+ggFyMIIBbjAOBgNVHQ8BAf8EBAMCB4AwEwYDVR0lBAwwCgYIKwYBBQUHAwMwHQYD
+VR0OBBYEFAuYzgyBA01YSSN1v0fYenGo7+PcMB8GA1UdIwQYMBaAFN/T6c9WJBGW
++ajY6ShVosYuGGQ/MBwGA1UdEQEB/wQSMBCBDmFkaXR5YUBzYWt5LmluMCwGCisG
+AQQBg78wAQEEHmh0dHBzOi8vZ2l0aHViLmNvbS9sb2dpbi9vYXV0aDAuBgorBgEE
+AYO/MAEIBCAMHmh0dHBzOi8vZ2l0aHViLmNvbS9sb2dpbi9vYXV0aDCBigYKKwYB
+BAHWeQIEAgR8BHoAeAB2AN09MGrGxxEyYxkeHJlnNwKiSl643jyt/4eKcoAvKe6O
+AAABibKhcJgAAAQDAEcwRQIgcWuz6NhFgdL0fNni6j0SOQnAgFpPEaN8jDH70mbD
+uPMCIQCX8koEnIX4c9crMT1hfoBBf1Z/CHJ6HLLHpQwWfEUMIzAKBggqhkjOPQQD
+AwNnADBkAjBozIBaBtEu7JUyYLH7Ly698E0o8DdIOmqcUMUYWNC6zyJVdrL5gAla
+mQSxfObSQasCMHQuw8youTjmFJXT7pNOYX4DW25knt+6P+W/m6zwcRRe3dMjmUAB
+gdBJb32+XXJMRDGCASYwggEiAgEBME8wNzEVMBMGA1UEChMMc2lnc3RvcmUuZGV2
+MR4wHAYDVQQDExVzaWdzdG9yZS1pbnRlcm1lZGlhdGUCFHIJCrBVHxoHlGos++k1
+xJxcElGaMAsGCWCGSAFlAwQCAaBpMBgGCSqGSIb3DQEJAzELBgkqhkiG9w0BBwEw
+HAYJKoZIhvcNAQkFMQ8XDTIzMDgwMTE5NDQzMlowLwYJKoZIhvcNAQkEMSIEIBe6
+VHcVlkO8jRm/fbUipwxwxNaI7UFDAL38Jl8eUj/5MAoGCCqGSM49BAMCBEgwRgIh
+AIYiRbnVeWjjgX2XwljDryzQN5RhUQaVH/AcUj+tbvWxAiEAhm9l3BU58tQsgyJW
+oYBpMWLgg6AUzpxx9mITZ2EKr4c=
+-----END SIGNED MESSAGE-----
+`,
+		Message:  "Test commit\n",
+		TreeHash: plumbing.NewHash("4b825dc642cb6eb9a060e54bf8d69288fbee4904"),
+	}
+}
+
 func createTestSignedCommit(t *testing.T) *object.Commit {
 	t.Helper()
 
+	return createTestSignedCommitWithKey(t, "gpg-privkey.asc")
+}
+
+func createTestSignedCommitWithKey(t *testing.T, keyName string) *object.Commit {
+	t.Helper()
+
+	return createTestSignedCommitWithKeyAndAuthor(t, keyName, object.Signature{
+		Name:  testName,
+		Email: testEmail,
+		When:  testClock.Now(),
+	})
+}
+
+func createTestSignedCommitWithKeyAndAuthor(t *testing.T, keyName string, author object.Signature) *object.Commit {
+	t.Helper()
+
 	repo, err := git.Init(memory.NewStorage(), memfs.New())
 	if err != nil {
 		t.Fatal(err)
 	}
 
 	testCommit := &object.Commit{
-		Author: object.Signature{
-			Name:  testName,
-			Email: testEmail,
-			When:  testClock.Now(),
-		},
+		Author: author,
 		Committer: object.Signature{
 			Name:  testName,
 			Email: testEmail,
@@ -224,7 +1024,7 @@ func createTestSignedCommit(t *testing.T) *object.Commit {
 		t.Fatal(err)
 	}
 
-	signingKeyBytes, err := os.ReadFile(filepath.Join("test-data", "gpg-privkey.asc"))
+	signingKeyBytes, err := os.ReadFile(filepath.Join("test-data", keyName))
 	if err != nil {
 		t.Fatal(err)
 	}
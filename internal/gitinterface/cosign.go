@@ -0,0 +1,182 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gitinterface
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gittuf/gittuf/internal/signerverifier"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing/object"
+	"github.com/gittuf/gittuf/internal/tuf"
+)
+
+// CoSignatureTrailerKey is the commit message trailer used to record
+// co-signatures. Each co-signature is recorded in its own trailer, as
+// "Gittuf-Co-Signature: <base64 encoded armored GPG signature>".
+const CoSignatureTrailerKey = "Gittuf-Co-Signature"
+
+var (
+	ErrInvalidCoSignature        = errors.New("commit co-signature is malformed")
+	ErrInvalidSignatureThreshold = errors.New("signature threshold must be at least 1")
+	ErrSignatureThresholdNotMet  = errors.New("commit is not signed by a threshold of the specified keys")
+)
+
+// GetCommitCoSignatures returns the armored co-signatures recorded in the
+// commit's trailers.
+func GetCommitCoSignatures(commit *object.Commit) ([]string, error) {
+	signatures := []string{}
+	for _, line := range strings.Split(commit.Message, "\n") {
+		if !isCoSignatureTrailer(line) {
+			continue
+		}
+
+		signature, err := base64.StdEncoding.DecodeString(strings.TrimSpace(strings.TrimPrefix(line, CoSignatureTrailerKey+":")))
+		if err != nil {
+			return nil, errors.Join(ErrInvalidCoSignature, err)
+		}
+
+		signatures = append(signatures, string(signature))
+	}
+
+	return signatures, nil
+}
+
+// GetCommitCoSignaturePayload returns the contents of the commit that
+// co-signers sign. The payload is the commit without its signature and without
+// any co-signature trailers, so co-signatures can be added in any order. The
+// commit's primary signature, in contrast, covers the co-signature trailers and
+// must therefore be created after all the co-signatures are added.
+func GetCommitCoSignaturePayload(commit *object.Commit) ([]byte, error) {
+	payloadCommit := *commit
+	payloadCommit.PGPSignature = ""
+	payloadCommit.Message = stripCoSignatureTrailers(commit.Message)
+
+	return getCommitBytesWithoutSignature(&payloadCommit)
+}
+
+// AddCommitCoSignature records the armored signature as a co-signature in the
+// commit's trailers. The commit's primary signature, if any, is removed as it
+// does not cover the new trailer.
+func AddCommitCoSignature(commit *object.Commit, signature string) {
+	message := strings.TrimRight(commit.Message, "\n")
+	if !hasCoSignatureTrailers(message) {
+		// Separate the first trailer from the message body
+		message += "\n"
+	}
+
+	commit.Message = message + "\n" + CoSignatureTrailerKey + ": " + base64.StdEncoding.EncodeToString([]byte(signature)) + "\n"
+	commit.PGPSignature = ""
+}
+
+// GetCommitSigners returns the keys that signed the commit, either using the
+// commit's signature or a co-signature. Each key is returned at most once, in
+// the order of the specified keys. Co-signatures are only supported for GPG
+// keys. The options are applied when verifying each signature.
+func GetCommitSigners(ctx context.Context, commit *object.Commit, keys []*tuf.Key, opts ...VerificationOption) ([]*tuf.Key, error) {
+	coSignatures, err := GetCommitCoSignatures(commit)
+	if err != nil {
+		return nil, err
+	}
+
+	var payload []byte
+	if len(coSignatures) > 0 {
+		payload, err = GetCommitCoSignaturePayload(commit)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	signers := []*tuf.Key{}
+	for _, key := range keys {
+		err := VerifyCommitSignature(ctx, commit, key, opts...)
+		if err == nil {
+			signers = append(signers, key)
+			continue
+		}
+		if !errors.Is(err, ErrIncorrectVerificationKey) && !errors.Is(err, ErrUnknownSigningMethod) {
+			return nil, err
+		}
+
+		if key.KeyType != signerverifier.GPGKeyType {
+			continue
+		}
+		for _, signature := range coSignatures {
+			if _, err := verifyGPGSignature(key, payload, signature, opts...); err == nil {
+				signers = append(signers, key)
+				break
+			}
+		}
+	}
+
+	return signers, nil
+}
+
+// VerifyCommitSignatureWithKeys checks that the commit is signed by at least
+// threshold of the specified keys, using the commit's signature and its
+// co-signatures, see GetCommitSigners. Keys are identified by their public
+// contents, so a key that's specified more than once, even under different key
+// IDs, is only counted once. Keys of types that cannot sign Git commits are
+// ignored. The options are applied when verifying each signature. If the
+// commit is not signed by enough keys, an error wrapping
+// ErrSignatureThresholdNotMet is returned.
+func VerifyCommitSignatureWithKeys(ctx context.Context, commit *object.Commit, keys []*tuf.Key, threshold int, opts ...VerificationOption) error {
+	if threshold < 1 {
+		return ErrInvalidSignatureThreshold
+	}
+
+	distinctKeys := []*tuf.Key{}
+	for _, key := range keys {
+		isDuplicate := false
+		for _, distinctKey := range distinctKeys {
+			if tuf.IsSameKey(key, distinctKey) {
+				isDuplicate = true
+				break
+			}
+		}
+		if !isDuplicate {
+			distinctKeys = append(distinctKeys, key)
+		}
+	}
+
+	signers, err := GetCommitSigners(ctx, commit, distinctKeys, opts...)
+	if err != nil {
+		return err
+	}
+
+	if len(signers) < threshold {
+		return fmt.Errorf("%w: signed by %d of %d required keys", ErrSignatureThresholdNotMet, len(signers), threshold)
+	}
+
+	return nil
+}
+
+func isCoSignatureTrailer(line string) bool {
+	return strings.HasPrefix(line, CoSignatureTrailerKey+":")
+}
+
+func hasCoSignatureTrailers(message string) bool {
+	for _, line := range strings.Split(message, "\n") {
+		if isCoSignatureTrailer(line) {
+			return true
+		}
+	}
+	return false
+}
+
+// stripCoSignatureTrailers removes the co-signature trailers from the commit
+// message. Trailing newlines are normalized so that the result does not depend
+// on how the trailers were separated from the message body.
+func stripCoSignatureTrailers(message string) string {
+	lines := []string{}
+	for _, line := range strings.Split(message, "\n") {
+		if !isCoSignatureTrailer(line) {
+			lines = append(lines, line)
+		}
+	}
+
+	return strings.TrimRight(strings.Join(lines, "\n"), "\n") + "\n"
+}
@@ -0,0 +1,216 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gitinterface
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/gittuf/gittuf/internal/signerverifier"
+	"github.com/gittuf/gittuf/internal/signerverifier/gpg"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing/object"
+	"github.com/gittuf/gittuf/internal/tuf"
+	sslibsv "github.com/secure-systems-lab/go-securesystemslib/signerverifier"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommitCoSignatures(t *testing.T) {
+	key := loadTestGPGKey(t, "gpg-pubkey.asc")
+	subkeyKey := loadTestGPGKey(t, "gpg-subkey-pubkey.asc")
+	keys := []*tuf.Key{key, subkeyKey}
+
+	newCommit := func() *object.Commit {
+		return &object.Commit{
+			Author:    object.Signature{Name: testName, Email: testEmail, When: testClock.Now()},
+			Committer: object.Signature{Name: testName, Email: testEmail, When: testClock.Now()},
+			Message:   "Test commit\n",
+			TreeHash:  plumbing.ZeroHash,
+		}
+	}
+
+	coSign := func(t *testing.T, commit *object.Commit, keyName string) {
+		t.Helper()
+
+		payload, err := GetCommitCoSignaturePayload(commit)
+		if err != nil {
+			t.Fatal(err)
+		}
+		AddCommitCoSignature(commit, signTestPayload(t, keyName, payload))
+	}
+
+	t.Run("no co-signatures", func(t *testing.T) {
+		commit := newCommit()
+
+		coSignatures, err := GetCommitCoSignatures(commit)
+		assert.Nil(t, err)
+		assert.Empty(t, coSignatures)
+
+		signers, err := GetCommitSigners(context.Background(), commit, keys)
+		assert.Nil(t, err)
+		assert.Empty(t, signers)
+	})
+
+	t.Run("one co-signature", func(t *testing.T) {
+		commit := newCommit()
+		coSign(t, commit, "gpg-subkey-privkey.asc")
+
+		assert.True(t, strings.HasPrefix(commit.Message, "Test commit\n\n"+CoSignatureTrailerKey+": "))
+
+		coSignatures, err := GetCommitCoSignatures(commit)
+		assert.Nil(t, err)
+		assert.Len(t, coSignatures, 1)
+
+		signers, err := GetCommitSigners(context.Background(), commit, keys)
+		assert.Nil(t, err)
+		assert.Equal(t, []*tuf.Key{subkeyKey}, signers)
+
+		// The primary signature covers the co-signature trailers
+		commit.PGPSignature = signTestPayload(t, "gpg-privkey.asc", getTestCommitBytesWithoutSignature(t, commit))
+
+		signers, err = GetCommitSigners(context.Background(), commit, keys)
+		assert.Nil(t, err)
+		assert.Equal(t, []*tuf.Key{key, subkeyKey}, signers)
+	})
+
+	t.Run("two co-signatures", func(t *testing.T) {
+		commit := newCommit()
+		coSign(t, commit, "gpg-privkey.asc")
+		coSign(t, commit, "gpg-subkey-privkey.asc")
+
+		coSignatures, err := GetCommitCoSignatures(commit)
+		assert.Nil(t, err)
+		assert.Len(t, coSignatures, 2)
+
+		signers, err := GetCommitSigners(context.Background(), commit, keys)
+		assert.Nil(t, err)
+		assert.Equal(t, []*tuf.Key{key, subkeyKey}, signers)
+	})
+
+	t.Run("co-signature over different contents", func(t *testing.T) {
+		commit := newCommit()
+		coSign(t, commit, "gpg-subkey-privkey.asc")
+		commit.Message = strings.Replace(commit.Message, "Test commit", "Modified commit", 1)
+
+		signers, err := GetCommitSigners(context.Background(), commit, keys)
+		assert.Nil(t, err)
+		assert.Empty(t, signers)
+	})
+
+	t.Run("malformed co-signature", func(t *testing.T) {
+		commit := newCommit()
+		commit.Message += "\n" + CoSignatureTrailerKey + ": not-base64!\n"
+
+		_, err := GetCommitCoSignatures(commit)
+		assert.ErrorIs(t, err, ErrInvalidCoSignature)
+	})
+}
+
+func TestVerifyCommitSignatureWithKeys(t *testing.T) {
+	key := loadTestGPGKey(t, "gpg-pubkey.asc")
+	subkeyKey := loadTestGPGKey(t, "gpg-subkey-pubkey.asc")
+
+	// Keys of types that cannot sign Git commits are ignored
+	ed25519Key := &tuf.Key{
+		KeyID:   "ed25519-key",
+		KeyType: signerverifier.ED25519KeyType,
+		Scheme:  signerverifier.ED25519KeyType,
+		KeyVal:  sslibsv.KeyVal{Public: "public"},
+	}
+
+	commit := &object.Commit{
+		Author:    object.Signature{Name: testName, Email: testEmail, When: testClock.Now()},
+		Committer: object.Signature{Name: testName, Email: testEmail, When: testClock.Now()},
+		Message:   "Test commit\n",
+		TreeHash:  plumbing.ZeroHash,
+	}
+	payload, err := GetCommitCoSignaturePayload(commit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	AddCommitCoSignature(commit, signTestPayload(t, "gpg-subkey-privkey.asc", payload))
+	commit.PGPSignature = signTestPayload(t, "gpg-privkey.asc", getTestCommitBytesWithoutSignature(t, commit))
+
+	t.Run("threshold met", func(t *testing.T) {
+		err := VerifyCommitSignatureWithKeys(context.Background(), commit, []*tuf.Key{ed25519Key, key, subkeyKey}, 2)
+		assert.Nil(t, err)
+
+		err = VerifyCommitSignatureWithKeys(context.Background(), commit, []*tuf.Key{subkeyKey}, 1)
+		assert.Nil(t, err)
+	})
+
+	t.Run("threshold not met", func(t *testing.T) {
+		err := VerifyCommitSignatureWithKeys(context.Background(), commit, []*tuf.Key{ed25519Key, key, subkeyKey}, 3)
+		assert.ErrorIs(t, err, ErrSignatureThresholdNotMet)
+
+		err = VerifyCommitSignatureWithKeys(context.Background(), commit, []*tuf.Key{ed25519Key}, 1)
+		assert.ErrorIs(t, err, ErrSignatureThresholdNotMet)
+	})
+
+	t.Run("key specified more than once is counted once", func(t *testing.T) {
+		err := VerifyCommitSignatureWithKeys(context.Background(), commit, []*tuf.Key{key, key}, 2)
+		assert.ErrorIs(t, err, ErrSignatureThresholdNotMet)
+
+		// The same key listed under a different key ID
+		renamedKey := *key
+		renamedKey.KeyID = "renamed-key"
+		err = VerifyCommitSignatureWithKeys(context.Background(), commit, []*tuf.Key{key, &renamedKey}, 2)
+		assert.ErrorIs(t, err, ErrSignatureThresholdNotMet)
+	})
+
+	t.Run("invalid threshold", func(t *testing.T) {
+		err := VerifyCommitSignatureWithKeys(context.Background(), commit, []*tuf.Key{key}, 0)
+		assert.ErrorIs(t, err, ErrInvalidSignatureThreshold)
+	})
+}
+
+func loadTestGPGKey(t *testing.T, keyName string) *tuf.Key {
+	t.Helper()
+
+	keyBytes, err := os.ReadFile(filepath.Join("test-data", keyName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := gpg.LoadGPGKeyFromBytes(keyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return key
+}
+
+func signTestPayload(t *testing.T, keyName string, payload []byte) string {
+	t.Helper()
+
+	signingKeyBytes, err := os.ReadFile(filepath.Join("test-data", keyName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyring, err := openpgp.ReadArmoredKeyRing(bytes.NewReader(signingKeyBytes))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sig := new(strings.Builder)
+	if err := openpgp.ArmoredDetachSign(sig, keyring[0], bytes.NewReader(payload), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	return sig.String()
+}
+
+func getTestCommitBytesWithoutSignature(t *testing.T, commit *object.Commit) []byte {
+	t.Helper()
+
+	contents, err := getCommitBytesWithoutSignature(commit)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return contents
+}
@@ -0,0 +1,116 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gitinterface
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gittuf/gittuf/internal/third_party/go-git"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/config"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/storage/filesystem"
+)
+
+// DumbHTTPConfigKey is the option in a remote's section of the repository's Git
+// config that indicates the remote must be fetched from using the dumb HTTP
+// protocol, e.g., `git config remote.origin.gittufDumbHTTP true`.
+const DumbHTTPConfigKey = "gittufDumbHTTP"
+
+var (
+	ErrRefsNotAdvertised          = errors.New("remote does not advertise requested refs")
+	ErrDumbHTTPUnsupportedStorage = errors.New("fetching using the dumb HTTP protocol requires a repository on disk")
+)
+
+// WithDumbHTTP configures fetches to use the dumb HTTP protocol, which is
+// served by some mirrors that only expose a repository's files over HTTP. This
+// is also enabled for remotes that set DumbHTTPConfigKey. It has no effect on
+// pushes, as the protocol is read-only.
+func WithDumbHTTP() SyncOption {
+	return func(o *SyncOptions) {
+		o.DumbHTTP = true
+	}
+}
+
+// usesDumbHTTP returns true if the remote is configured to be fetched from
+// using the dumb HTTP protocol.
+func usesDumbHTTP(repo *git.Repository, remoteName string) (bool, error) {
+	repoConfig, err := repo.Config()
+	if err != nil {
+		return false, err
+	}
+
+	value := repoConfig.Raw.Section("remote").Subsection(remoteName).Option(DumbHTTPConfigKey)
+	if len(value) == 0 {
+		return false, nil
+	}
+
+	return strconv.ParseBool(value)
+}
+
+// fetchRefSpecOverDumbHTTP fetches the refspecs from the remote using the dumb
+// HTTP protocol. go-git only implements the smart protocols, so the fetch is
+// performed using the Git binary. The objects reachable from the fetched refs
+// are retrieved in full. If any of the refspecs does not match a ref
+// advertised by the remote, ErrRefsNotAdvertised is returned and no refs are
+// updated.
+func fetchRefSpecOverDumbHTTP(ctx context.Context, repo *git.Repository, remoteName string, refs []config.RefSpec) error {
+	storage, ok := repo.Storer.(*filesystem.Storage)
+	if !ok {
+		return ErrDumbHTTPUnsupportedStorage
+	}
+	gitDir := storage.Filesystem().Root()
+
+	// With the dumb protocol, the advertised refs are read from the remote's
+	// info/refs file
+	output, err := runGitCommand(ctx, "--git-dir", gitDir, "ls-remote", "--refs", remoteName)
+	if err != nil {
+		return err
+	}
+	if len(output) == 0 {
+		// Consistent with fetches using go-git, fetching from an empty remote
+		// is not an error
+		return nil
+	}
+
+	advertisedRefs := []plumbing.ReferenceName{}
+	for _, line := range strings.Split(output, "\n") {
+		_, refName, found := strings.Cut(line, "\t")
+		if !found {
+			continue
+		}
+		advertisedRefs = append(advertisedRefs, plumbing.ReferenceName(refName))
+	}
+
+	missing := []string{}
+	for _, refSpec := range refs {
+		matched := false
+		for _, refName := range advertisedRefs {
+			if refSpec.Match(refName) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			missing = append(missing, refSpec.Src())
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: %s", ErrRefsNotAdvertised, strings.Join(missing, ", "))
+	}
+
+	args := []string{"--git-dir", gitDir, "fetch", "--no-tags", "--no-write-fetch-head", "--recurse-submodules=no", remoteName}
+	for _, refSpec := range refs {
+		args = append(args, refSpec.String())
+	}
+	if _, err := runGitCommand(ctx, args...); err != nil {
+		return err
+	}
+
+	// The fetched objects may be in new packfiles that go-git must index
+	storage.Reindex()
+	return nil
+}
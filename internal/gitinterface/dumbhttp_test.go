@@ -0,0 +1,135 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gitinterface
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/third_party/go-git"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/config"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/storage/memory"
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFetchOverDumbHTTP(t *testing.T) {
+	refName := "refs/gittuf/reference-state-log"
+	missingRefName := "refs/gittuf/policy"
+
+	remoteTmpDir := t.TempDir()
+	remoteRepo, err := git.PlainInit(remoteTmpDir, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	emptyTreeID, err := WriteTree(remoteRepo, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// addRemoteCommit adds a commit to the ref in the remote, and updates the
+	// files the dumb HTTP protocol relies on
+	addRemoteCommit := func(t *testing.T) {
+		t.Helper()
+
+		if _, err := Commit(remoteRepo, emptyTreeID, refName, "Test commit", false); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := runGitCommand(context.Background(), "--git-dir", remoteTmpDir, "update-server-info"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The remote's files are served as is, so clients can't use the smart
+	// protocol
+	server := httptest.NewServer(http.FileServer(http.Dir(remoteTmpDir)))
+	defer server.Close()
+
+	createLocalRepository := func(t *testing.T) *git.Repository {
+		t.Helper()
+
+		localRepo, err := git.PlainInit(t.TempDir(), true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := localRepo.CreateRemote(&config.RemoteConfig{Name: DefaultRemoteName, URLs: []string{server.URL}}); err != nil {
+			t.Fatal(err)
+		}
+		return localRepo
+	}
+
+	t.Run("fetch with option", func(t *testing.T) {
+		addRemoteCommit(t)
+		localRepo := createLocalRepository(t)
+
+		err := Fetch(context.Background(), localRepo, DefaultRemoteName, []string{refName}, true, WithDumbHTTP())
+		assert.Nil(t, err)
+
+		remoteTip, err := GetTip(remoteRepo, refName)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, ref := range []string{refName, RemoteRef(refName, DefaultRemoteName)} {
+			localTip, err := GetTip(localRepo, ref)
+			assert.Nil(t, err)
+			assert.Equal(t, remoteTip, localTip)
+		}
+
+		// The fetched objects can be read using go-git
+		_, err = localRepo.CommitObject(remoteTip)
+		assert.Nil(t, err)
+	})
+
+	t.Run("fetch with remote config", func(t *testing.T) {
+		addRemoteCommit(t)
+		localRepo := createLocalRepository(t)
+
+		localConfig, err := localRepo.Config()
+		if err != nil {
+			t.Fatal(err)
+		}
+		localConfig.Raw.Section("remote").Subsection(DefaultRemoteName).SetOption(DumbHTTPConfigKey, "true")
+		if err := localRepo.SetConfig(localConfig); err != nil {
+			t.Fatal(err)
+		}
+
+		err = Fetch(context.Background(), localRepo, DefaultRemoteName, []string{refName}, true)
+		assert.Nil(t, err)
+
+		remoteTip, err := GetTip(remoteRepo, refName)
+		if err != nil {
+			t.Fatal(err)
+		}
+		localTip, err := GetTip(localRepo, refName)
+		assert.Nil(t, err)
+		assert.Equal(t, remoteTip, localTip)
+	})
+
+	t.Run("ref not advertised", func(t *testing.T) {
+		localRepo := createLocalRepository(t)
+
+		err := Fetch(context.Background(), localRepo, DefaultRemoteName, []string{refName, missingRefName}, true, WithDumbHTTP())
+		assert.ErrorIs(t, err, ErrRefsNotAdvertised)
+		assert.ErrorContains(t, err, missingRefName)
+
+		// No refs are fetched
+		_, err = GetTip(localRepo, refName)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("in-memory repository", func(t *testing.T) {
+		localRepo, err := git.Init(memory.NewStorage(), memfs.New())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := localRepo.CreateRemote(&config.RemoteConfig{Name: DefaultRemoteName, URLs: []string{server.URL}}); err != nil {
+			t.Fatal(err)
+		}
+
+		err = Fetch(context.Background(), localRepo, DefaultRemoteName, []string{refName}, true, WithDumbHTTP())
+		assert.ErrorIs(t, err, ErrDumbHTTPUnsupportedStorage)
+	})
+}
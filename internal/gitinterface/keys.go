@@ -3,19 +3,27 @@
 package gitinterface
 
 import (
+	"bytes"
 	"context"
+	"crypto/x509"
 	"errors"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
+	"strings"
+	"time"
 
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	pgpErrors "github.com/ProtonMail/go-crypto/openpgp/errors"
+	"github.com/ProtonMail/go-crypto/openpgp/packet"
 	"github.com/gittuf/gittuf/internal/signerverifier"
+	"github.com/gittuf/gittuf/internal/signerverifier/minisign"
 	"github.com/gittuf/gittuf/internal/tuf"
 	"github.com/sigstore/cosign/v2/pkg/cosign"
 	gitsignVerifier "github.com/sigstore/gitsign/pkg/git"
 	gitsignRekor "github.com/sigstore/gitsign/pkg/rekor"
-	"github.com/sigstore/sigstore/pkg/fulcioroots"
 )
 
 var (
@@ -24,8 +32,31 @@ var (
 	ErrUnableToSign               = errors.New("unable to sign Git object")
 	ErrIncorrectVerificationKey   = errors.New("incorrect key provided to verify signature")
 	ErrVerifyingSigstoreSignature = errors.New("unable to verify Sigstore signature")
+	ErrInvalidGPGSignature        = errors.New("GPG signature is invalid or malformed")
+	ErrExpiredGPGKey              = errors.New("GPG key had expired when the signed object was recorded")
+	ErrOutsideKeyValidityWindow   = errors.New("signature was verified outside the key's validity window")
 )
 
+// SignatureMetadata contains information about a verified signature on a Git
+// object.
+type SignatureMetadata struct {
+	// Key is the trusted key that verified the signature.
+	Key *tuf.Key
+
+	// Identity is the identity the signing certificate was issued to. It is
+	// only set for Sigstore signatures.
+	Identity string
+
+	// Issuer is the OIDC issuer that attested to the identity in the signing
+	// certificate. It is only set for Sigstore signatures.
+	Issuer string
+
+	// SigningTime is when the signature was created. For GPG signatures, this
+	// is the creation time recorded in the signature. For Sigstore signatures,
+	// this is the start of the signing certificate's validity window.
+	SigningTime time.Time
+}
+
 type SigningMethod int
 
 const (
@@ -34,6 +65,20 @@ const (
 	SigningMethodX509
 )
 
+// String returns the name of the signing method as used for the gpg.format
+// option in the Git config.
+func (m SigningMethod) String() string {
+	switch m {
+	case SigningMethodGPG:
+		return "gpg"
+	case SigningMethodSSH:
+		return "ssh"
+	case SigningMethodX509:
+		return "x509"
+	}
+	return "unknown"
+}
+
 const (
 	DefaultSigningProgramGPG  string = "gpg"
 	DefaultSigningProgramSSH  string = "ssh-keygen"
@@ -70,6 +115,17 @@ func GetSigningCommand() (string, []string, error) {
 	return program, args, nil
 }
 
+// GetSigningMethod returns the method used to sign Git objects as configured
+// in the user's Git config.
+func GetSigningMethod() (SigningMethod, error) {
+	gitConfig, err := getConfig()
+	if err != nil {
+		return -1, err
+	}
+
+	return getSigningMethod(gitConfig)
+}
+
 func getSigningInfo() (SigningMethod, string, string, error) {
 	gitConfig, err := getConfig()
 	if err != nil {
@@ -141,6 +197,36 @@ func getSigningProgram(gitConfig map[string]string, signingMethod SigningMethod)
 	return DefaultSigningProgramGPG
 }
 
+// ObjectSigner signs the encoded contents of Git objects. The signatures must be
+// in a format Git can verify, such as an armored GPG signature or an SSH
+// signature. external.Signer, which invokes a signing program in the style of
+// Git's gpg.program option, implements ObjectSigner.
+type ObjectSigner interface {
+	Sign(ctx context.Context, data []byte) ([]byte, error)
+}
+
+// signGitObjectWithSigner signs a Git commit or tag using the signer. Git
+// objects store the signature as a block of lines, so the returned signature
+// is terminated by a newline, as is the case for signatures created by GPG.
+// This ensures the signature matches what is read back from the object.
+func signGitObjectWithSigner(ctx context.Context, contents []byte, signer ObjectSigner) (string, error) {
+	sig, err := signer.Sign(ctx, contents)
+	if err != nil {
+		return "", errors.Join(ErrUnableToSign, err)
+	}
+
+	if len(sig) == 0 {
+		return "", ErrUnableToSign
+	}
+
+	signature := string(sig)
+	if !strings.HasSuffix(signature, "\n") {
+		signature += "\n"
+	}
+
+	return signature, nil
+}
+
 // signGitObject signs a Git commit or tag using the user's configured Git
 // config.
 func signGitObject(contents []byte) (string, error) {
@@ -205,15 +291,22 @@ func signGitObject(contents []byte) (string, error) {
 }
 
 // verifyGitsignSignature handles the Sigstore-specific workflow involved in
-// verifying commit or tag signatures issued by gitsign.
-func verifyGitsignSignature(ctx context.Context, key *tuf.Key, data, signature []byte) error {
-	root, err := fulcioroots.Get()
+// verifying commit or tag signatures issued by gitsign. The verified signing
+// certificate is returned. The Sigstore instance used for verification can be
+// configured using opts.
+func verifyGitsignSignature(ctx context.Context, key *tuf.Key, data, signature []byte, opts ...VerificationOption) (*x509.Certificate, error) {
+	options, err := getVerificationOptions(opts...)
 	if err != nil {
-		return errors.Join(ErrVerifyingSigstoreSignature, err)
+		return nil, errors.Join(ErrVerifyingSigstoreSignature, err)
 	}
-	intermediate, err := fulcioroots.GetIntermediates()
+
+	if err := checkOIDCIssuerIsTrusted(options, key.KeyVal.Issuer); err != nil {
+		return nil, errors.Join(ErrIncorrectVerificationKey, err)
+	}
+
+	root, intermediate, err := getFulcioRoots(options)
 	if err != nil {
-		return errors.Join(ErrVerifyingSigstoreSignature, err)
+		return nil, errors.Join(ErrVerifyingSigstoreSignature, err)
 	}
 
 	verifier, err := gitsignVerifier.NewCertVerifier(
@@ -221,22 +314,22 @@ func verifyGitsignSignature(ctx context.Context, key *tuf.Key, data, signature [
 		gitsignVerifier.WithIntermediatePool(intermediate),
 	)
 	if err != nil {
-		return errors.Join(ErrVerifyingSigstoreSignature, err)
+		return nil, errors.Join(ErrVerifyingSigstoreSignature, err)
 	}
 
 	verifiedCert, err := verifier.Verify(ctx, data, signature, true)
 	if err != nil {
-		return ErrIncorrectVerificationKey
+		return nil, ErrIncorrectVerificationKey
 	}
 
-	rekor, err := gitsignRekor.New(signerverifier.RekorServer)
+	rekor, err := gitsignRekor.New(options.RekorURL)
 	if err != nil {
-		return errors.Join(ErrVerifyingSigstoreSignature, err)
+		return nil, errors.Join(ErrVerifyingSigstoreSignature, err)
 	}
 
 	ctPub, err := cosign.GetCTLogPubs(ctx)
 	if err != nil {
-		return errors.Join(ErrVerifyingSigstoreSignature, err)
+		return nil, errors.Join(ErrVerifyingSigstoreSignature, err)
 	}
 
 	checkOpts := &cosign.CheckOpts{
@@ -252,8 +345,141 @@ func verifyGitsignSignature(ctx context.Context, key *tuf.Key, data, signature [
 	}
 
 	if _, err := cosign.ValidateAndUnpackCert(verifiedCert, checkOpts); err != nil {
+		return nil, ErrIncorrectVerificationKey
+	}
+
+	return verifiedCert, nil
+}
+
+// verifyGPGSignature verifies the armored GPG signature over data using the
+// key, returning the time the signature was created. The signature may be
+// issued by the key's primary key or by any of its signing subkeys. As Git
+// objects are typically verified long after they are signed, expiration is
+// checked at the trusted time set using WithTrustedTime, such as when the
+// object was recorded in the RSL, rather than the current time. The current
+// time is used if no trusted time is set. The creation time recorded in the
+// signature alone is not used as the signer could backdate it, but the key
+// must not have expired by then either. If the primary key or the signing
+// subkey had expired at either time, ErrExpiredGPGKey is returned, which also
+// wraps ErrIncorrectVerificationKey.
+func verifyGPGSignature(key *tuf.Key, data []byte, signature string, opts ...VerificationOption) (time.Time, error) {
+	options := &VerificationOptions{}
+	for _, fn := range opts {
+		fn(options)
+	}
+	trustedTime := options.TrustedTime
+	if trustedTime.IsZero() {
+		trustedTime = time.Now()
+	}
+
+	keyring, err := openpgp.ReadArmoredKeyRing(strings.NewReader(key.KeyVal.Public))
+	if err != nil {
+		return time.Time{}, ErrIncorrectVerificationKey
+	}
+
+	sig, err := parseGPGSignature(signature)
+	if err != nil {
+		// The signature may have been created using a different signing
+		// method, so the key is not the right one
+		return time.Time{}, ErrIncorrectVerificationKey
+	}
+	signingTime := sig.CreationTime
+
+	// As keys only expire once, checking the later of the two times ensures
+	// the key was valid at both
+	checkTime := trustedTime
+	if signingTime.After(checkTime) {
+		checkTime = signingTime
+	}
+
+	config := &packet.Config{Time: func() time.Time { return checkTime }}
+	if _, err := openpgp.CheckArmoredDetachedSignature(keyring, bytes.NewReader(data), strings.NewReader(signature), config); err != nil {
+		if errors.Is(err, pgpErrors.ErrKeyExpired) {
+			return time.Time{}, errors.Join(ErrIncorrectVerificationKey, ErrExpiredGPGKey)
+		}
+		return time.Time{}, ErrIncorrectVerificationKey
+	}
+
+	if err := checkGPGSigningKeySize(keyring, sig); err != nil {
+		return time.Time{}, errors.Join(ErrIncorrectVerificationKey, err)
+	}
+
+	return signingTime, nil
+}
+
+// checkGPGSigningKeySize checks that the key in the keyring that issued the
+// signature, which may be a subkey, is not an RSA key smaller than the minimum
+// size configured in the signerverifier package.
+func checkGPGSigningKeySize(keyring openpgp.EntityList, sig *packet.Signature) error {
+	if sig.IssuerKeyId == nil {
+		return nil
+	}
+
+	for _, key := range keyring.KeysById(*sig.IssuerKeyId) {
+		switch key.PublicKey.PubKeyAlgo {
+		case packet.PubKeyAlgoRSA, packet.PubKeyAlgoRSASignOnly:
+			bits, err := key.PublicKey.BitLength()
+			if err != nil {
+				return err
+			}
+			if err := signerverifier.CheckRSAKeySize(int(bits)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// verifyMinisignSignature verifies the minisign or signify signature over data
+// using the key. These signatures are carried in the same header of the Git
+// object as GPG and Sigstore signatures. If the signature was created using a
+// different signing method or key, ErrIncorrectVerificationKey is returned. A
+// malformed minisign signature also wraps minisign.ErrInvalidMinisignSignature.
+func verifyMinisignSignature(key *tuf.Key, data []byte, signature string) error {
+	if !minisign.IsSignature([]byte(signature)) {
 		return ErrIncorrectVerificationKey
 	}
 
+	if err := minisign.Verify(key, data, []byte(signature)); err != nil {
+		return errors.Join(ErrIncorrectVerificationKey, err)
+	}
+
 	return nil
 }
+
+// verifyRegisteredKeyTypeSignature verifies the signature over data using the
+// signer verifier registered for the key's custom key type.
+func verifyRegisteredKeyTypeSignature(ctx context.Context, key *tuf.Key, data, signature []byte) error {
+	verifier, err := signerverifier.NewSignerVerifierFromTUFKey(key)
+	if err != nil {
+		return err
+	}
+
+	if err := verifier.Verify(ctx, data, signature); err != nil {
+		return errors.Join(ErrIncorrectVerificationKey, err)
+	}
+
+	return nil
+}
+
+// parseGPGSignature returns the signature packet of an armored GPG signature,
+// which records the signature's creation time and issuer.
+func parseGPGSignature(signature string) (*packet.Signature, error) {
+	block, err := armor.Decode(strings.NewReader(signature))
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := packet.Read(block.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	sig, ok := p.(*packet.Signature)
+	if !ok {
+		return nil, ErrInvalidGPGSignature
+	}
+
+	return sig, nil
+}
@@ -188,3 +188,10 @@ func TestGetSigningInfo(t *testing.T) {
 		}
 	}
 }
+
+func TestSigningMethodString(t *testing.T) {
+	assert.Equal(t, "gpg", SigningMethodGPG.String())
+	assert.Equal(t, "ssh", SigningMethodSSH.String())
+	assert.Equal(t, "x509", SigningMethodX509.String())
+	assert.Equal(t, "unknown", SigningMethod(-1).String())
+}
@@ -0,0 +1,120 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gitinterface
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/gittuf/gittuf/internal/third_party/go-git"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/storage/filesystem"
+	billy "github.com/go-git/go-billy/v5"
+)
+
+const (
+	repositoryLockFileName = "gittuf.lock"
+
+	// DefaultLockTimeout is how long LockRepository waits for another gittuf
+	// process to release the repository lock.
+	DefaultLockTimeout = 30 * time.Second
+)
+
+var (
+	ErrRepositoryLocked = errors.New("repository is locked by another gittuf process")
+
+	// lockRetryInterval is how often an acquisition is retried while the
+	// repository is locked.
+	lockRetryInterval = 50 * time.Millisecond
+
+	// staleLockAge is the age after which a lock file is assumed to have been
+	// left behind by a gittuf process that exited without releasing it.
+	staleLockAge = 10 * time.Minute
+)
+
+// RepositoryLock is an advisory lock that serializes writes made by gittuf
+// processes to the same repository, such as the creation of RSL entries and
+// policy commits.
+type RepositoryLock struct {
+	fs       billy.Filesystem
+	fileName string
+}
+
+// LockRepository acquires the repository's gittuf lock, which is a file in the
+// Git directory, waiting for up to timeout if it is held by another process. A
+// lock file older than staleLockAge is considered stale and is removed. Locking
+// is a no-op for repositories that are not stored on a filesystem, such as
+// in-memory repositories. The returned lock must be released using Unlock.
+func LockRepository(repo *git.Repository, timeout time.Duration) (*RepositoryLock, error) {
+	storage, ok := repo.Storer.(*filesystem.Storage)
+	if !ok {
+		return &RepositoryLock{}, nil
+	}
+	fs := storage.Filesystem()
+
+	deadline := time.Now().Add(timeout)
+	for {
+		lockFile, err := fs.OpenFile(repositoryLockFileName, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+		if err == nil {
+			// The PID is recorded to help users identify the holder of a
+			// lock that must be removed manually
+			_, writeErr := fmt.Fprintf(lockFile, "%d\n", os.Getpid())
+			closeErr := lockFile.Close()
+			if err := errors.Join(writeErr, closeErr); err != nil {
+				fs.Remove(repositoryLockFileName) //nolint:errcheck
+				return nil, err
+			}
+
+			return &RepositoryLock{fs: fs, fileName: repositoryLockFileName}, nil
+		}
+		if !os.IsExist(err) {
+			return nil, err
+		}
+
+		removed, err := removeStaleLock(fs, repositoryLockFileName)
+		if err != nil {
+			return nil, err
+		}
+		if removed {
+			continue
+		}
+
+		if time.Now().After(deadline) {
+			return nil, ErrRepositoryLocked
+		}
+		time.Sleep(lockRetryInterval)
+	}
+}
+
+// Unlock releases the repository lock.
+func (l *RepositoryLock) Unlock() error {
+	if l.fs == nil {
+		return nil
+	}
+
+	return l.fs.Remove(l.fileName)
+}
+
+// removeStaleLock removes the lock file if it is older than staleLockAge. It
+// returns true if the lock file no longer exists.
+func removeStaleLock(fs billy.Filesystem, fileName string) (bool, error) {
+	info, err := fs.Stat(fileName)
+	if err != nil {
+		if os.IsNotExist(err) {
+			// The lock was released in the meantime
+			return true, nil
+		}
+		return false, err
+	}
+
+	if time.Since(info.ModTime()) < staleLockAge {
+		return false, nil
+	}
+
+	if err := fs.Remove(fileName); err != nil && !os.IsNotExist(err) {
+		return false, err
+	}
+
+	return true, nil
+}
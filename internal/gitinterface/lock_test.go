@@ -0,0 +1,103 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gitinterface
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gittuf/gittuf/internal/third_party/go-git"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/storage/memory"
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLockRepository(t *testing.T) {
+	t.Run("on-disk repository", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		repo, err := git.PlainInit(tmpDir, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		lockFilePath := filepath.Join(tmpDir, repositoryLockFileName)
+
+		lock, err := LockRepository(repo, DefaultLockTimeout)
+		assert.Nil(t, err)
+		assert.FileExists(t, lockFilePath)
+
+		// The lock is held, so a second acquisition times out
+		_, err = LockRepository(repo, 100*time.Millisecond)
+		assert.ErrorIs(t, err, ErrRepositoryLocked)
+
+		err = lock.Unlock()
+		assert.Nil(t, err)
+		assert.NoFileExists(t, lockFilePath)
+
+		lock, err = LockRepository(repo, 100*time.Millisecond)
+		assert.Nil(t, err)
+		assert.Nil(t, lock.Unlock())
+	})
+
+	t.Run("lock released while waiting", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		repo, err := git.PlainInit(tmpDir, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		lock, err := LockRepository(repo, DefaultLockTimeout)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		go func() {
+			time.Sleep(200 * time.Millisecond)
+			lock.Unlock() //nolint:errcheck
+		}()
+
+		secondLock, err := LockRepository(repo, DefaultLockTimeout)
+		assert.Nil(t, err)
+		assert.Nil(t, secondLock.Unlock())
+	})
+
+	t.Run("stale lock", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		repo, err := git.PlainInit(tmpDir, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		lockFilePath := filepath.Join(tmpDir, repositoryLockFileName)
+
+		// Simulate a lock left behind by a process that exited
+		if err := os.WriteFile(lockFilePath, []byte("1\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		staleTime := time.Now().Add(-2 * staleLockAge)
+		if err := os.Chtimes(lockFilePath, staleTime, staleTime); err != nil {
+			t.Fatal(err)
+		}
+
+		lock, err := LockRepository(repo, 100*time.Millisecond)
+		assert.Nil(t, err)
+		assert.Nil(t, lock.Unlock())
+	})
+
+	t.Run("in-memory repository", func(t *testing.T) {
+		repo, err := git.Init(memory.NewStorage(), memfs.New())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		lock, err := LockRepository(repo, DefaultLockTimeout)
+		assert.Nil(t, err)
+
+		// Locking is a no-op, so acquiring it again succeeds
+		secondLock, err := LockRepository(repo, 100*time.Millisecond)
+		assert.Nil(t, err)
+
+		assert.Nil(t, secondLock.Unlock())
+		assert.Nil(t, lock.Unlock())
+	})
+}
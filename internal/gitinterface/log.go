@@ -76,3 +76,53 @@ func GetCommitsBetweenRange(repo *git.Repository, commitNewID, commitOldID plumb
 
 	return commits, nil
 }
+
+// GetCommitsBetweenRangeOldestFirst returns the same commits as
+// GetCommitsBetweenRange, ordered such that every commit appears after its
+// parents in the range. Commits that aren't ordered relative to one another
+// are sorted by commit ID so that the returned order is deterministic.
+func GetCommitsBetweenRangeOldestFirst(repo *git.Repository, commitNewID, commitOldID plumbing.Hash) ([]*object.Commit, error) {
+	commits, err := GetCommitsBetweenRange(repo, commitNewID, commitOldID)
+	if err != nil {
+		return nil, err
+	}
+
+	inRange := make(map[plumbing.Hash]bool, len(commits))
+	for _, commit := range commits {
+		inRange[commit.Hash] = true
+	}
+
+	// Track how many of each commit's parents are yet to be ordered, and the
+	// children to revisit once a commit is ordered
+	pendingParents := make(map[plumbing.Hash]int, len(commits))
+	children := map[plumbing.Hash][]*object.Commit{}
+	ready := []*object.Commit{}
+	for _, commit := range commits {
+		for _, parentID := range commit.ParentHashes {
+			if inRange[parentID] {
+				pendingParents[commit.Hash]++
+				children[parentID] = append(children[parentID], commit)
+			}
+		}
+
+		if pendingParents[commit.Hash] == 0 {
+			ready = append(ready, commit)
+		}
+	}
+
+	ordered := make([]*object.Commit, 0, len(commits))
+	for len(ready) > 0 {
+		commit := ready[0]
+		ready = ready[1:]
+		ordered = append(ordered, commit)
+
+		for _, child := range children[commit.Hash] {
+			pendingParents[child.Hash]--
+			if pendingParents[child.Hash] == 0 {
+				ready = append(ready, child)
+			}
+		}
+	}
+
+	return ordered, nil
+}
@@ -103,3 +103,74 @@ func TestGetCommitsBetweenRange(t *testing.T) {
 		assert.Equal(t, expectedCommits, commits)
 	})
 }
+
+func TestGetCommitsBetweenRangeOldestFirst(t *testing.T) {
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	treeHash, err := WriteTree(repo, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	writeCommit := func(t *testing.T, message string, parentIDs ...plumbing.Hash) plumbing.Hash {
+		t.Helper()
+
+		commit := CreateCommitObject(testGitConfig, treeHash, plumbing.ZeroHash, message, testClock)
+		commit.ParentHashes = parentIDs
+		commitID, err := WriteCommit(repo, commit)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return commitID
+	}
+
+	// base <- a1 <- a2 <- merge
+	//     ^-- b1 <---------/
+	base := writeCommit(t, "base")
+	a1 := writeCommit(t, "a1", base)
+	a2 := writeCommit(t, "a2", a1)
+	b1 := writeCommit(t, "b1", base)
+	merge := writeCommit(t, "merge", a2, b1)
+
+	positions := func(commits []*object.Commit) map[plumbing.Hash]int {
+		p := make(map[plumbing.Hash]int, len(commits))
+		for i, commit := range commits {
+			p[commit.Hash] = i
+		}
+		return p
+	}
+
+	t.Run("range with merge", func(t *testing.T) {
+		commits, err := GetCommitsBetweenRangeOldestFirst(repo, merge, base)
+		assert.Nil(t, err)
+		assert.Len(t, commits, 4)
+
+		p := positions(commits)
+		assert.NotContains(t, p, base)
+		assert.Less(t, p[a1], p[a2])
+		assert.Less(t, p[a2], p[merge])
+		assert.Less(t, p[b1], p[merge])
+		assert.Equal(t, merge, commits[3].Hash)
+	})
+
+	t.Run("all commits", func(t *testing.T) {
+		commits, err := GetCommitsBetweenRangeOldestFirst(repo, merge, plumbing.ZeroHash)
+		assert.Nil(t, err)
+		assert.Len(t, commits, 5)
+		assert.Equal(t, base, commits[0].Hash)
+		assert.Equal(t, merge, commits[4].Hash)
+	})
+
+	t.Run("order is deterministic", func(t *testing.T) {
+		first, err := GetCommitsBetweenRangeOldestFirst(repo, merge, base)
+		if err != nil {
+			t.Fatal(err)
+		}
+		second, err := GetCommitsBetweenRangeOldestFirst(repo, merge, base)
+		assert.Nil(t, err)
+		assert.Equal(t, first, second)
+	})
+}
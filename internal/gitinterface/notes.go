@@ -0,0 +1,45 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gitinterface
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/gittuf/gittuf/internal/third_party/go-git"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing/object"
+)
+
+const NotesRefPrefix = "refs/notes/"
+
+// GetNotesChangedByCommit returns the IDs of the objects whose notes were
+// added, modified, or removed by the notes commit relative to its parent
+// commit. In a notes tree, each note is stored at a path derived from the ID of
+// the object it annotates. Git may split the ID across directories, known as
+// fanout, so that a note can be stored at "ab/cdef...", for example. The fanout
+// is removed when computing the returned IDs. Paths that do not correspond to
+// an object ID once the fanout is removed are not notes and are ignored. As with
+// GetFilePathsChangedByCommit, no changes are returned for merge commits.
+func GetNotesChangedByCommit(repo *git.Repository, commit *object.Commit) ([]plumbing.Hash, error) {
+	paths, err := GetFilePathsChangedByCommit(repo, commit)
+	if err != nil {
+		return nil, err
+	}
+
+	objectIDs := []plumbing.Hash{}
+	for _, path := range paths {
+		objectID := strings.ReplaceAll(path, "/", "")
+		if !plumbing.IsHash(objectID) {
+			continue
+		}
+
+		objectIDs = append(objectIDs, plumbing.NewHash(objectID))
+	}
+
+	sort.Slice(objectIDs, func(i, j int) bool {
+		return objectIDs[i].String() < objectIDs[j].String()
+	})
+
+	return objectIDs, nil
+}
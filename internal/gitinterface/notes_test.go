@@ -0,0 +1,86 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gitinterface
+
+import (
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/third_party/go-git"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing/filemode"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing/object"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/storage/memory"
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetNotesChangedByCommit(t *testing.T) {
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	annotatedA := plumbing.NewHash("abcdef1234567890abcdef1234567890abcdef12")
+	annotatedB := plumbing.NewHash("1234567890abcdef1234567890abcdef12345678")
+
+	noteBlobID, err := WriteBlob(repo, []byte("Reviewed-by: Jane Doe"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	createCommit := func(t *testing.T, entries []object.TreeEntry, parentID plumbing.Hash) *object.Commit {
+		t.Helper()
+
+		treeHash, err := WriteTree(repo, entries)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		commitID, err := WriteCommit(repo, CreateCommitObject(testGitConfig, treeHash, parentID, "Notes added by 'git notes add'", testClock))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		commit, err := repo.CommitObject(commitID)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return commit
+	}
+
+	// Add a note without fanout
+	firstCommit := createCommit(t, []object.TreeEntry{
+		{Name: annotatedA.String(), Mode: filemode.Regular, Hash: noteBlobID},
+	}, plumbing.ZeroHash)
+
+	notes, err := GetNotesChangedByCommit(repo, firstCommit)
+	assert.Nil(t, err)
+	assert.Equal(t, []plumbing.Hash{annotatedA}, notes)
+
+	// Add a note with fanout, along with a file that isn't a note
+	fanoutTreeHash, err := WriteTree(repo, []object.TreeEntry{
+		{Name: annotatedB.String()[2:], Mode: filemode.Regular, Hash: noteBlobID},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	secondCommit := createCommit(t, []object.TreeEntry{
+		{Name: annotatedA.String(), Mode: filemode.Regular, Hash: noteBlobID},
+		{Name: annotatedB.String()[:2], Mode: filemode.Dir, Hash: fanoutTreeHash},
+		{Name: "README", Mode: filemode.Regular, Hash: noteBlobID},
+	}, firstCommit.Hash)
+
+	notes, err = GetNotesChangedByCommit(repo, secondCommit)
+	assert.Nil(t, err)
+	assert.Equal(t, []plumbing.Hash{annotatedB}, notes)
+
+	// Remove a note
+	thirdCommit := createCommit(t, []object.TreeEntry{
+		{Name: annotatedB.String()[:2], Mode: filemode.Dir, Hash: fanoutTreeHash},
+	}, secondCommit.Hash)
+
+	notes, err = GetNotesChangedByCommit(repo, thirdCommit)
+	assert.Nil(t, err)
+	assert.Equal(t, []plumbing.Hash{annotatedA}, notes)
+}
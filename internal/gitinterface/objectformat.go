@@ -0,0 +1,90 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gitinterface
+
+import (
+	"crypto"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gittuf/gittuf/internal/third_party/go-git"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing"
+	formatcfg "github.com/gittuf/gittuf/internal/third_party/go-git/plumbing/format/config"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing/hash"
+)
+
+const (
+	extensionsSection = "extensions"
+	objectFormatKey   = "objectformat"
+)
+
+var (
+	ErrUnsupportedObjectFormat = errors.New("repository object format is not supported by this build of gittuf, SHA-256 repositories require building with the sha256 tag")
+	ErrInvalidObjectID         = errors.New("invalid Git object ID")
+)
+
+// SupportedObjectFormat returns the object format, i.e., the hash algorithm
+// used to identify Git objects, that gittuf was built for. The width of
+// plumbing.Hash is fixed at build time: SHA-1 by default, and SHA-256 when
+// built with the sha256 build tag. A single build therefore cannot operate on
+// repositories of both formats.
+func SupportedObjectFormat() formatcfg.ObjectFormat {
+	if hash.CryptoType == crypto.SHA256 {
+		return formatcfg.SHA256
+	}
+	return formatcfg.SHA1
+}
+
+// GetObjectFormat returns the object format of the repository. Repositories
+// that do not declare an object format use SHA-1.
+func GetObjectFormat(repo *git.Repository) (formatcfg.ObjectFormat, error) {
+	config, err := repo.Config()
+	if err != nil {
+		return "", err
+	}
+
+	if len(config.Extensions.ObjectFormat) > 0 {
+		return config.Extensions.ObjectFormat, nil
+	}
+
+	// go-git only sets the extensions when initializing a repository, so the
+	// object format of existing repositories is read from the raw config
+	if config.Raw.HasSection(extensionsSection) {
+		if objectFormat := config.Raw.Section(extensionsSection).Option(objectFormatKey); len(objectFormat) > 0 {
+			return formatcfg.ObjectFormat(strings.ToLower(objectFormat)), nil
+		}
+	}
+
+	return formatcfg.DefaultObjectFormat, nil
+}
+
+// CheckObjectFormat returns ErrUnsupportedObjectFormat if the repository's
+// object format does not match the format gittuf was built for. Object IDs
+// of the wrong width would otherwise be silently truncated or padded.
+func CheckObjectFormat(repo *git.Repository) error {
+	objectFormat, err := GetObjectFormat(repo)
+	if err != nil {
+		return err
+	}
+
+	if objectFormat != SupportedObjectFormat() {
+		return fmt.Errorf("%w: repository uses '%s', expected '%s'", ErrUnsupportedObjectFormat, objectFormat, SupportedObjectFormat())
+	}
+	return nil
+}
+
+// ParseHash parses the hex encoded Git object ID. Unlike plumbing.NewHash,
+// which pads or truncates its input, ParseHash returns ErrInvalidObjectID if
+// the ID is not of the width used by the supported object format.
+func ParseHash(objectID string) (plumbing.Hash, error) {
+	if len(objectID) != hash.HexSize {
+		return plumbing.ZeroHash, fmt.Errorf("%w: '%s' is not a %s object ID", ErrInvalidObjectID, objectID, SupportedObjectFormat())
+	}
+	if _, err := hex.DecodeString(objectID); err != nil {
+		return plumbing.ZeroHash, errors.Join(ErrInvalidObjectID, err)
+	}
+
+	return plumbing.NewHash(objectID), nil
+}
@@ -0,0 +1,56 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build sha256
+
+package gitinterface
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/signerverifier/gpg"
+	"github.com/gittuf/gittuf/internal/third_party/go-git"
+	formatcfg "github.com/gittuf/gittuf/internal/third_party/go-git/plumbing/format/config"
+	"github.com/stretchr/testify/assert"
+)
+
+// These tests only run when gittuf is built with the sha256 tag, which
+// switches go-git's object IDs to SHA-256.
+func TestSHA256Repository(t *testing.T) {
+	repo, err := git.PlainInitWithOptions(t.TempDir(), &git.PlainInitOptions{ObjectFormat: formatcfg.SHA256})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = CheckObjectFormat(repo)
+	assert.Nil(t, err)
+
+	keyBytes, err := os.ReadFile(filepath.Join("test-data", "gpg-pubkey.asc"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	gpgKey, err := gpg.LoadGPGKeyFromBytes(keyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	commit := createTestSignedCommit(t)
+	commitID, err := WriteCommit(repo, commit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Len(t, commitID.String(), 64)
+
+	storedCommit, err := repo.CommitObject(commitID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = VerifyCommitSignature(context.Background(), storedCommit, gpgKey)
+	assert.Nil(t, err)
+
+	parsedCommitID, err := ParseHash(commitID.String())
+	assert.Nil(t, err)
+	assert.Equal(t, commitID, parsedCommitID)
+}
@@ -0,0 +1,123 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gitinterface
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/third_party/go-git"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing"
+	formatcfg "github.com/gittuf/gittuf/internal/third_party/go-git/plumbing/format/config"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing/hash"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckObjectFormat(t *testing.T) {
+	unsupportedObjectFormat := formatcfg.SHA256
+	if SupportedObjectFormat() == formatcfg.SHA256 {
+		unsupportedObjectFormat = formatcfg.SHA1
+	}
+
+	t.Run("repository without object format", func(t *testing.T) {
+		repo, err := git.PlainInit(t.TempDir(), false)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		objectFormat, err := GetObjectFormat(repo)
+		assert.Nil(t, err)
+		assert.Equal(t, formatcfg.SHA1, objectFormat)
+	})
+
+	t.Run("repository with supported object format", func(t *testing.T) {
+		repo := createRepositoryWithObjectFormat(t, SupportedObjectFormat())
+
+		objectFormat, err := GetObjectFormat(repo)
+		assert.Nil(t, err)
+		assert.Equal(t, SupportedObjectFormat(), objectFormat)
+
+		err = CheckObjectFormat(repo)
+		assert.Nil(t, err)
+	})
+
+	t.Run("repository with unsupported object format", func(t *testing.T) {
+		repo := createRepositoryWithObjectFormat(t, unsupportedObjectFormat)
+
+		objectFormat, err := GetObjectFormat(repo)
+		assert.Nil(t, err)
+		assert.Equal(t, unsupportedObjectFormat, objectFormat)
+
+		err = CheckObjectFormat(repo)
+		assert.ErrorIs(t, err, ErrUnsupportedObjectFormat)
+	})
+}
+
+func TestParseHash(t *testing.T) {
+	tests := map[string]struct {
+		objectID      string
+		expectedHash  plumbing.Hash
+		expectedError error
+	}{
+		"valid object ID": {
+			objectID:     strings.Repeat("ab", hash.Size),
+			expectedHash: plumbing.NewHash(strings.Repeat("ab", hash.Size)),
+		},
+		"zero hash": {
+			objectID:     plumbing.ZeroHash.String(),
+			expectedHash: plumbing.ZeroHash,
+		},
+		"short object ID": {
+			objectID:      "abcdef",
+			expectedError: ErrInvalidObjectID,
+		},
+		"object ID of SHA-1 and SHA-256 combined width": {
+			objectID:      strings.Repeat("ab", 52),
+			expectedError: ErrInvalidObjectID,
+		},
+		"non-hex object ID": {
+			objectID:      strings.Repeat("z", hash.HexSize),
+			expectedError: ErrInvalidObjectID,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			objectID, err := ParseHash(test.objectID)
+			if test.expectedError != nil {
+				assert.ErrorIs(t, err, test.expectedError)
+			} else {
+				assert.Nil(t, err)
+				assert.Equal(t, test.expectedHash, objectID)
+			}
+		})
+	}
+}
+
+// createRepositoryWithObjectFormat creates a repository that declares the
+// object format in its config the way Git does, which go-git only reads as a
+// raw option.
+func createRepositoryWithObjectFormat(t *testing.T, objectFormat formatcfg.ObjectFormat) *git.Repository {
+	t.Helper()
+
+	tmpDir := t.TempDir()
+	repo, err := git.PlainInit(tmpDir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config, err := repo.Config()
+	if err != nil {
+		t.Fatal(err)
+	}
+	config.Raw.Section(extensionsSection).SetOption(objectFormatKey, string(objectFormat))
+	if err := repo.Storer.SetConfig(config); err != nil {
+		t.Fatal(err)
+	}
+
+	repo, err = git.PlainOpen(tmpDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return repo
+}
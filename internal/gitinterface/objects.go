@@ -0,0 +1,158 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gitinterface
+
+import (
+	"errors"
+	"io"
+	"sync"
+
+	"github.com/gittuf/gittuf/internal/third_party/go-git"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing/object"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing/storer"
+)
+
+var ErrObjectReaderIsReadOnly = errors.New("object reader does not support writing objects")
+
+// ObjectReader provides read access to a repository's objects that is safe for
+// concurrent use by multiple goroutines, such as when verifying commits in
+// parallel. A *git.Repository must not be shared across goroutines: go-git's
+// object storage is not safe for concurrent use as the filesystem storage
+// shares packfile handles, indexes, and caches between reads.
+//
+// ObjectReader serializes access to the underlying storage and copies each
+// object into memory before it's decoded, so that decoding and any subsequent
+// processing, like signature verification, can proceed concurrently. Objects
+// returned by ObjectReader load related objects, such as a commit's parents or
+// tree, through the reader, and so may also be used concurrently. Objects must
+// not be written to the repository while the reader is in use.
+type ObjectReader struct {
+	storer *lockedObjectStorer
+}
+
+// NewObjectReader returns an ObjectReader for the objects in repo.
+func NewObjectReader(repo *git.Repository) *ObjectReader {
+	return &ObjectReader{storer: &lockedObjectStorer{storer: repo.Storer}}
+}
+
+// CommitObject returns the commit with the specified ID.
+func (r *ObjectReader) CommitObject(commitID plumbing.Hash) (*object.Commit, error) {
+	return object.GetCommit(r.storer, commitID)
+}
+
+// TagObject returns the annotated tag with the specified ID.
+func (r *ObjectReader) TagObject(tagID plumbing.Hash) (*object.Tag, error) {
+	return object.GetTag(r.storer, tagID)
+}
+
+// TreeObject returns the tree with the specified ID.
+func (r *ObjectReader) TreeObject(treeID plumbing.Hash) (*object.Tree, error) {
+	return object.GetTree(r.storer, treeID)
+}
+
+// ReadBlob returns the contents of the blob with the specified ID. Unlike the
+// ReadBlob function, missing blobs are not fetched in partial clones, as
+// fetching writes to the repository.
+func (r *ObjectReader) ReadBlob(blobID plumbing.Hash) ([]byte, error) {
+	blob, err := object.GetBlob(r.storer, blobID)
+	if err != nil {
+		return nil, err
+	}
+
+	reader, err := blob.Reader()
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close() //nolint:errcheck
+
+	return io.ReadAll(reader)
+}
+
+// HasObject indicates if the object with the specified ID is present in the
+// repository.
+func (r *ObjectReader) HasObject(objectID plumbing.Hash) bool {
+	return r.storer.HasEncodedObject(objectID) == nil
+}
+
+// lockedObjectStorer wraps an object storer so that it may be used by multiple
+// goroutines. Reads hold a mutex for their duration and return in-memory
+// copies of the objects. Writes are not supported.
+type lockedObjectStorer struct {
+	mu     sync.Mutex
+	storer storer.EncodedObjectStorer
+}
+
+func (s *lockedObjectStorer) NewEncodedObject() plumbing.EncodedObject {
+	return &plumbing.MemoryObject{}
+}
+
+func (s *lockedObjectStorer) SetEncodedObject(plumbing.EncodedObject) (plumbing.Hash, error) {
+	return plumbing.ZeroHash, ErrObjectReaderIsReadOnly
+}
+
+func (s *lockedObjectStorer) EncodedObject(objectType plumbing.ObjectType, objectID plumbing.Hash) (plumbing.EncodedObject, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	obj, err := s.storer.EncodedObject(objectType, objectID)
+	if err != nil {
+		return nil, err
+	}
+
+	return copyToMemoryObject(obj)
+}
+
+func (s *lockedObjectStorer) IterEncodedObjects(objectType plumbing.ObjectType) (storer.EncodedObjectIter, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	iter, err := s.storer.IterEncodedObjects(objectType)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only the IDs are gathered while holding the lock, the objects are loaded
+	// on demand using EncodedObject
+	objectIDs := []plumbing.Hash{}
+	if err := iter.ForEach(func(obj plumbing.EncodedObject) error {
+		objectIDs = append(objectIDs, obj.Hash())
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return storer.NewEncodedObjectLookupIter(s, objectType, objectIDs), nil
+}
+
+func (s *lockedObjectStorer) HasEncodedObject(objectID plumbing.Hash) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.storer.HasEncodedObject(objectID)
+}
+
+func (s *lockedObjectStorer) EncodedObjectSize(objectID plumbing.Hash) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.storer.EncodedObjectSize(objectID)
+}
+
+// copyToMemoryObject reads obj into a new in-memory object, so that its
+// contents can be read without accessing the storage it was loaded from.
+func copyToMemoryObject(obj plumbing.EncodedObject) (plumbing.EncodedObject, error) {
+	reader, err := obj.Reader()
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close() //nolint:errcheck
+
+	memObj := &plumbing.MemoryObject{}
+	memObj.SetType(obj.Type())
+	if _, err := io.Copy(memObj, reader); err != nil {
+		return nil, err
+	}
+
+	return memObj, nil
+}
@@ -0,0 +1,152 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gitinterface
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/third_party/go-git"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing/filemode"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing/object"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestObjectReader(t *testing.T) {
+	repo, err := git.PlainInit(t.TempDir(), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	numCommits := 50
+	commitIDs := make([]plumbing.Hash, 0, numCommits)
+	blobIDs := make([]plumbing.Hash, 0, numCommits)
+	parentID := plumbing.ZeroHash
+	for i := 0; i < numCommits; i++ {
+		blobID, err := WriteBlob(repo, []byte(fmt.Sprintf("contents %d", i)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		treeID, err := WriteTree(repo, []object.TreeEntry{{Name: fmt.Sprintf("%d", i), Mode: filemode.Regular, Hash: blobID}})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		commit := CreateCommitObject(testGitConfig, treeID, parentID, fmt.Sprintf("Commit %d", i), testClock)
+		commitID, err := WriteCommit(repo, commit)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		blobIDs = append(blobIDs, blobID)
+		commitIDs = append(commitIDs, commitID)
+		parentID = commitID
+	}
+
+	t.Run("read objects", func(t *testing.T) {
+		reader := NewObjectReader(repo)
+
+		commit, err := reader.CommitObject(commitIDs[1])
+		assert.Nil(t, err)
+		assert.Equal(t, commitIDs[1], commit.Hash)
+		assert.Equal(t, "Commit 1", commit.Message)
+
+		parent, err := commit.Parent(0)
+		assert.Nil(t, err)
+		assert.Equal(t, commitIDs[0], parent.Hash)
+
+		tree, err := reader.TreeObject(commit.TreeHash)
+		assert.Nil(t, err)
+		assert.Equal(t, blobIDs[1], tree.Entries[0].Hash)
+
+		contents, err := reader.ReadBlob(blobIDs[1])
+		assert.Nil(t, err)
+		assert.Equal(t, []byte("contents 1"), contents)
+
+		assert.True(t, reader.HasObject(commitIDs[1]))
+		assert.False(t, reader.HasObject(plumbing.ZeroHash))
+
+		_, err = reader.CommitObject(plumbing.ZeroHash)
+		assert.ErrorIs(t, err, plumbing.ErrObjectNotFound)
+
+		_, err = reader.TagObject(commitIDs[1])
+		assert.ErrorIs(t, err, plumbing.ErrObjectNotFound)
+	})
+
+	t.Run("read objects concurrently", func(t *testing.T) {
+		// This test is most useful when run with the race detector enabled
+		reader := NewObjectReader(repo)
+
+		numWorkers := 16
+		errs := make(chan error, numWorkers)
+		wg := sync.WaitGroup{}
+		for w := 0; w < numWorkers; w++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				for i := range commitIDs {
+					commit, err := reader.CommitObject(commitIDs[i])
+					if err != nil {
+						errs <- err
+						return
+					}
+					if commit.Hash != commitIDs[i] {
+						errs <- fmt.Errorf("expected commit '%s', got '%s'", commitIDs[i], commit.Hash)
+						return
+					}
+
+					tree, err := commit.Tree()
+					if err != nil {
+						errs <- err
+						return
+					}
+					contents, err := reader.ReadBlob(tree.Entries[0].Hash)
+					if err != nil {
+						errs <- err
+						return
+					}
+					if string(contents) != fmt.Sprintf("contents %d", i) {
+						errs <- fmt.Errorf("unexpected contents '%s' for commit %d", string(contents), i)
+						return
+					}
+				}
+
+				// Walk the history from the latest commit
+				commit, err := reader.CommitObject(commitIDs[len(commitIDs)-1])
+				if err != nil {
+					errs <- err
+					return
+				}
+				count := 0
+				if err := object.NewCommitPreorderIter(commit, nil, nil).ForEach(func(*object.Commit) error {
+					count++
+					return nil
+				}); err != nil {
+					errs <- err
+					return
+				}
+				if count != len(commitIDs) {
+					errs <- fmt.Errorf("expected %d commits in history, got %d", len(commitIDs), count)
+				}
+			}()
+		}
+
+		wg.Wait()
+		close(errs)
+		for err := range errs {
+			t.Error(err)
+		}
+	})
+
+	t.Run("writes are not supported", func(t *testing.T) {
+		reader := NewObjectReader(repo)
+
+		obj := reader.storer.NewEncodedObject()
+		obj.SetType(plumbing.BlobObject)
+		_, err := reader.storer.SetEncodedObject(obj)
+		assert.ErrorIs(t, err, ErrObjectReaderIsReadOnly)
+	})
+}
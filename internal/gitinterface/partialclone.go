@@ -0,0 +1,175 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gitinterface
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/gittuf/gittuf/internal/third_party/go-git"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/config"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/storage/filesystem"
+)
+
+// BloblessFilter is the partial clone filter that omits all blobs, which are
+// then fetched on demand.
+const BloblessFilter = "blob:none"
+
+var ErrNotPartialClone = errors.New("repository is not a partial clone")
+
+// CloneOptions contains the optional parameters of CloneAndFetch.
+type CloneOptions struct {
+	// Filter is the object filter used for a partial clone, such as
+	// BloblessFilter. A full clone is performed if it is empty.
+	Filter string
+}
+
+// CloneOption is used to configure CloneAndFetch.
+type CloneOption func(*CloneOptions)
+
+// WithPartialClone configures CloneAndFetch to perform a partial clone using the
+// specified object filter. The filter is only applied to the repository's
+// branches and tags, while the additionally requested refs, such as gittuf's
+// RSL and policy refs, are always fetched in full so that they can be verified.
+// Objects omitted by the filter are fetched lazily when they're needed.
+func WithPartialClone(filter string) CloneOption {
+	return func(o *CloneOptions) {
+		o.Filter = filter
+	}
+}
+
+// partialCloneAndFetch creates a partial clone of the repository at remoteURL in
+// dir. go-git does not support object filters, so the fetches are performed
+// using the Git binary.
+func partialCloneAndFetch(ctx context.Context, remoteURL, dir, initialBranch string, refs []string, filter string) (*git.Repository, error) {
+	repo, err := git.PlainInit(dir, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := repo.CreateRemote(&config.RemoteConfig{Name: DefaultRemoteName, URLs: []string{remoteURL}}); err != nil {
+		return nil, err
+	}
+
+	// The requested refs are fetched before the remote is configured as a
+	// promisor so that their objects are fetched in full
+	if len(refs) > 0 {
+		args := []string{"-C", dir, "fetch", "--no-tags", DefaultRemoteName}
+		for _, r := range refs {
+			for _, remoteName := range []string{DefaultRemoteName, ""} {
+				refSpec, err := RefSpec(repo, r, remoteName, true)
+				if err != nil {
+					return nil, err
+				}
+				args = append(args, refSpec.String())
+			}
+		}
+		if _, err := runGitCommand(ctx, args...); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := runGitCommand(ctx, "-C", dir, "config", fmt.Sprintf("remote.%s.promisor", DefaultRemoteName), "true"); err != nil {
+		return nil, err
+	}
+	if _, err := runGitCommand(ctx, "-C", dir, "config", fmt.Sprintf("remote.%s.partialclonefilter", DefaultRemoteName), filter); err != nil {
+		return nil, err
+	}
+	if _, err := runGitCommand(ctx, "-C", dir, "fetch", fmt.Sprintf("--filter=%s", filter), DefaultRemoteName); err != nil {
+		return nil, err
+	}
+
+	branch := plumbing.ReferenceName(initialBranch).Short()
+	if len(branch) == 0 {
+		if _, err := runGitCommand(ctx, "-C", dir, "remote", "set-head", DefaultRemoteName, "--auto"); err != nil {
+			return nil, err
+		}
+		remoteHead, err := runGitCommand(ctx, "-C", dir, "symbolic-ref", "--short", fmt.Sprintf("%s%s/%s", RemoteRefPrefix, DefaultRemoteName, plumbing.HEAD))
+		if err != nil {
+			return nil, err
+		}
+		branch = strings.TrimPrefix(remoteHead, DefaultRemoteName+"/")
+	}
+
+	// Checking out the branch fetches the blobs in its tree
+	if _, err := runGitCommand(ctx, "-C", dir, "checkout", branch); err != nil {
+		return nil, err
+	}
+
+	// The repository is opened again so that go-git picks up the objects
+	// fetched by the Git binary
+	return git.PlainOpen(dir)
+}
+
+// FetchMissingObjects fetches the specified objects from the promisor remote of
+// a partial clone. Objects that are already present are not fetched again.
+func FetchMissingObjects(ctx context.Context, repo *git.Repository, objectIDs []plumbing.Hash) error {
+	storage, ok := repo.Storer.(*filesystem.Storage)
+	if !ok {
+		return ErrNotPartialClone
+	}
+
+	remoteName, err := getPromisorRemote(repo)
+	if err != nil {
+		return err
+	}
+
+	// This mirrors how Git fetches missing objects in partial clones
+	args := []string{"--git-dir", storage.Filesystem().Root(), "-c", "fetch.negotiationAlgorithm=noop", "fetch", "--no-tags", "--no-write-fetch-head", "--recurse-submodules=no", fmt.Sprintf("--filter=%s", BloblessFilter), remoteName}
+	missing := false
+	for _, objectID := range objectIDs {
+		if err := repo.Storer.HasEncodedObject(objectID); err == nil {
+			continue
+		}
+		args = append(args, objectID.String())
+		missing = true
+	}
+	if !missing {
+		return nil
+	}
+
+	if _, err := runGitCommand(ctx, args...); err != nil {
+		return err
+	}
+
+	// The fetched objects are in a new packfile that go-git must index
+	storage.Reindex()
+	return nil
+}
+
+// getPromisorRemote returns the name of the remote that objects omitted from a
+// partial clone can be fetched from.
+func getPromisorRemote(repo *git.Repository) (string, error) {
+	repoConfig, err := repo.Config()
+	if err != nil {
+		return "", err
+	}
+
+	for _, subsection := range repoConfig.Raw.Section("remote").Subsections {
+		if subsection.Option("promisor") == "true" {
+			return subsection.Name, nil
+		}
+	}
+
+	return "", ErrNotPartialClone
+}
+
+// runGitCommand invokes the Git binary with the specified arguments and returns
+// its trimmed output.
+func runGitCommand(ctx context.Context, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("%w: %s", err, stderr.String())
+	}
+
+	return strings.TrimSpace(stdout.String()), nil
+}
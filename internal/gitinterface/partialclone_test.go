@@ -0,0 +1,120 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gitinterface
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/third_party/go-git"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing/filemode"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing/object"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/storage/memory"
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPartialCloneAndFetch(t *testing.T) {
+	refName := "refs/heads/main"
+	gittufRefName := "refs/gittuf/test"
+
+	remoteTmpDir := t.TempDir()
+	remoteRepo, err := git.PlainInit(remoteTmpDir, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Partial clones must be allowed by the remote
+	remoteConfig, err := remoteRepo.Config()
+	if err != nil {
+		t.Fatal(err)
+	}
+	remoteConfig.Raw.Section("uploadpack").SetOption("allowFilter", "true")
+	if err := remoteRepo.SetConfig(remoteConfig); err != nil {
+		t.Fatal(err)
+	}
+
+	blobIDs := []plumbing.Hash{}
+	for _, contents := range []string{"old", "new", "gittuf"} {
+		blobID, err := WriteBlob(remoteRepo, []byte(contents))
+		if err != nil {
+			t.Fatal(err)
+		}
+		blobIDs = append(blobIDs, blobID)
+	}
+	oldBlobID, newBlobID, gittufBlobID := blobIDs[0], blobIDs[1], blobIDs[2]
+
+	for _, blobID := range []plumbing.Hash{oldBlobID, newBlobID} {
+		treeID, err := WriteTree(remoteRepo, []object.TreeEntry{{Name: "file", Mode: filemode.Regular, Hash: blobID}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Commit(remoteRepo, treeID, refName, "Update file", false); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	gittufTreeID, err := WriteTree(remoteRepo, []object.TreeEntry{{Name: "metadata", Mode: filemode.Regular, Hash: gittufBlobID}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Commit(remoteRepo, gittufTreeID, gittufRefName, "Update metadata", false); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := remoteRepo.Storer.SetReference(plumbing.NewSymbolicReference(plumbing.HEAD, plumbing.ReferenceName(refName))); err != nil {
+		t.Fatal(err)
+	}
+
+	localRepo, err := CloneAndFetch(context.Background(), remoteTmpDir, t.TempDir(), "", []string{gittufRefName}, WithPartialClone(BloblessFilter))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, ref := range []string{refName, gittufRefName} {
+		remoteTip, err := GetTip(remoteRepo, ref)
+		if err != nil {
+			t.Fatal(err)
+		}
+		localTip, err := GetTip(localRepo, ref)
+		assert.Nil(t, err)
+		assert.Equal(t, remoteTip, localTip)
+	}
+
+	// The gittuf ref is fetched in full, and the blob in the checked out tree
+	// is fetched for the worktree
+	assert.Nil(t, localRepo.Storer.HasEncodedObject(gittufBlobID))
+	assert.Nil(t, localRepo.Storer.HasEncodedObject(newBlobID))
+
+	// Blobs only in the history of the branch are omitted
+	assert.ErrorIs(t, localRepo.Storer.HasEncodedObject(oldBlobID), plumbing.ErrObjectNotFound)
+
+	// The omitted blob is fetched when it's read
+	contents, err := ReadBlob(context.Background(), localRepo, oldBlobID)
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("old"), contents)
+	assert.Nil(t, localRepo.Storer.HasEncodedObject(oldBlobID))
+}
+
+func TestFetchMissingObjects(t *testing.T) {
+	t.Run("in-memory repository", func(t *testing.T) {
+		repo, err := git.Init(memory.NewStorage(), memfs.New())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = FetchMissingObjects(context.Background(), repo, []plumbing.Hash{EmptyBlob()})
+		assert.ErrorIs(t, err, ErrNotPartialClone)
+	})
+
+	t.Run("full repository", func(t *testing.T) {
+		repo, err := git.PlainInit(t.TempDir(), true)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = FetchMissingObjects(context.Background(), repo, []plumbing.Hash{EmptyBlob()})
+		assert.ErrorIs(t, err, ErrNotPartialClone)
+	})
+}
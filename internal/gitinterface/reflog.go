@@ -0,0 +1,203 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gitinterface
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/gittuf/gittuf/internal/third_party/go-git"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing/object"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/storage/filesystem"
+)
+
+const reflogDirName = "logs"
+
+var ErrInvalidReflogEntry = errors.New("invalid reflog entry")
+
+// ReflogEntry records a single update of a ref in its reflog.
+type ReflogEntry struct {
+	OldID     plumbing.Hash
+	NewID     plumbing.Hash
+	Committer object.Signature
+	Message   string
+}
+
+// GetReflog returns the entries in the reflog of the specified ref, with the
+// most recent update first, as `git reflog` does. Reflogs are maintained by
+// Git when it updates refs, and are only written for refs outside refs/heads
+// and refs/remotes if core.logAllRefUpdates is set to always. A ref without a
+// reflog has no entries. GetReflog returns no entries for repositories that
+// are not stored on a filesystem, such as in-memory repositories.
+func GetReflog(repo *git.Repository, refName string) ([]*ReflogEntry, error) {
+	storage, ok := repo.Storer.(*filesystem.Storage)
+	if !ok {
+		return nil, nil
+	}
+
+	file, err := storage.Filesystem().Open(path.Join(reflogDirName, refName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer file.Close() //nolint:errcheck
+
+	entries := []*ReflogEntry{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if len(strings.TrimSpace(line)) == 0 {
+			continue
+		}
+
+		entry, err := parseReflogEntry(line)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read reflog of '%s': %w", refName, err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	// The reflog is written oldest first
+	for i, j := 0, len(entries)-1; i < j; i, j = i+1, j-1 {
+		entries[i], entries[j] = entries[j], entries[i]
+	}
+
+	return entries, nil
+}
+
+// GetReflogOnlyCommits returns the IDs of the commits recorded in the reflog
+// of the specified ref that are no longer reachable from any ref in the
+// repository, such as commits that were discarded when the ref was rewound or
+// force pushed. The commits are returned with the most recently recorded one
+// first. Commits that have already been garbage collected are skipped.
+func GetReflogOnlyCommits(repo *git.Repository, refName string) ([]plumbing.Hash, error) {
+	entries, err := GetReflog(repo, refName)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := []plumbing.Hash{}
+	seen := map[plumbing.Hash]bool{}
+	for _, entry := range entries {
+		for _, commitID := range []plumbing.Hash{entry.NewID, entry.OldID} {
+			if commitID.IsZero() || seen[commitID] {
+				continue
+			}
+			seen[commitID] = true
+
+			if _, err := repo.CommitObject(commitID); err != nil {
+				if errors.Is(err, plumbing.ErrObjectNotFound) {
+					continue
+				}
+				return nil, err
+			}
+			candidates = append(candidates, commitID)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+
+	reachable, err := getReachableCommits(repo, candidates)
+	if err != nil {
+		return nil, err
+	}
+
+	unreachable := []plumbing.Hash{}
+	for _, commitID := range candidates {
+		if !reachable[commitID] {
+			unreachable = append(unreachable, commitID)
+		}
+	}
+
+	return unreachable, nil
+}
+
+// parseReflogEntry parses a line of a reflog, which has the form
+// `<old-id> <new-id> <name> <<email>> <timestamp> <timezone>\t<message>`.
+func parseReflogEntry(line string) (*ReflogEntry, error) {
+	header, message, _ := strings.Cut(line, "\t")
+
+	oldID, rest, found := strings.Cut(header, " ")
+	if !found || !plumbing.IsHash(oldID) {
+		return nil, fmt.Errorf("%w: '%s'", ErrInvalidReflogEntry, line)
+	}
+	newID, identity, found := strings.Cut(rest, " ")
+	if !found || !plumbing.IsHash(newID) {
+		return nil, fmt.Errorf("%w: '%s'", ErrInvalidReflogEntry, line)
+	}
+
+	entry := &ReflogEntry{
+		OldID:   plumbing.NewHash(oldID),
+		NewID:   plumbing.NewHash(newID),
+		Message: message,
+	}
+	entry.Committer.Decode([]byte(identity))
+
+	return entry, nil
+}
+
+// getReachableCommits walks the history of every ref in the repository and
+// returns which of the candidate commits were found. The walk stops as soon as
+// all of the candidates are found.
+func getReachableCommits(repo *git.Repository, candidates []plumbing.Hash) (map[plumbing.Hash]bool, error) {
+	isCandidate := map[plumbing.Hash]bool{}
+	for _, commitID := range candidates {
+		isCandidate[commitID] = true
+	}
+
+	refs, err := repo.References()
+	if err != nil {
+		return nil, err
+	}
+	queue := []plumbing.Hash{}
+	if err := refs.ForEach(func(ref *plumbing.Reference) error {
+		if ref.Type() == plumbing.HashReference && !ref.Hash().IsZero() {
+			queue = append(queue, ref.Hash())
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	reachable := map[plumbing.Hash]bool{}
+	visited := map[plumbing.Hash]bool{}
+	for len(queue) > 0 && len(reachable) < len(isCandidate) {
+		objectID := queue[0]
+		queue = queue[1:]
+		if visited[objectID] {
+			continue
+		}
+		visited[objectID] = true
+
+		obj, err := repo.Object(plumbing.AnyObject, objectID)
+		if err != nil {
+			if errors.Is(err, plumbing.ErrObjectNotFound) {
+				continue
+			}
+			return nil, err
+		}
+
+		switch obj := obj.(type) {
+		case *object.Tag:
+			queue = append(queue, obj.Target)
+		case *object.Commit:
+			if isCandidate[obj.Hash] {
+				reachable[obj.Hash] = true
+			}
+			queue = append(queue, obj.ParentHashes...)
+		}
+	}
+
+	return reachable, nil
+}
@@ -0,0 +1,137 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gitinterface
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/third_party/go-git"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/storage/memory"
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetReflog(t *testing.T) {
+	refName := "refs/heads/main"
+
+	t.Run("ref was moved", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		repo, err := git.PlainInit(tmpDir, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		firstCommitID := writeTestReflogCommit(t, repo, plumbing.ZeroHash, "first")
+		secondCommitID := writeTestReflogCommit(t, repo, firstCommitID, "second")
+		rewrittenCommitID := writeTestReflogCommit(t, repo, firstCommitID, "rewritten")
+
+		// The ref is rewound from the second commit to a rewritten version
+		if err := repo.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(refName), rewrittenCommitID)); err != nil {
+			t.Fatal(err)
+		}
+		writeTestReflog(t, tmpDir, refName,
+			fmt.Sprintf("%s %s Jane Doe <jane.doe@example.com> 814694400 +0000\tcommit (initial): first", plumbing.ZeroHash, firstCommitID),
+			fmt.Sprintf("%s %s Jane Doe <jane.doe@example.com> 814698000 +0000\tcommit: second", firstCommitID, secondCommitID),
+			fmt.Sprintf("%s %s Jane Doe <jane.doe@example.com> 814701600 -0500\treset: moving to HEAD~1", secondCommitID, firstCommitID),
+			fmt.Sprintf("%s %s Jane Doe <jane.doe@example.com> 814705200 -0500\tcommit: rewritten", firstCommitID, rewrittenCommitID),
+		)
+
+		entries, err := GetReflog(repo, refName)
+		assert.Nil(t, err)
+		if assert.Equal(t, 4, len(entries)) {
+			// The most recent update is first
+			assert.Equal(t, firstCommitID, entries[0].OldID)
+			assert.Equal(t, rewrittenCommitID, entries[0].NewID)
+			assert.Equal(t, "commit: rewritten", entries[0].Message)
+			assert.Equal(t, "Jane Doe", entries[0].Committer.Name)
+			assert.Equal(t, "jane.doe@example.com", entries[0].Committer.Email)
+			assert.Equal(t, int64(814705200), entries[0].Committer.When.Unix())
+
+			assert.Equal(t, secondCommitID, entries[1].OldID)
+			assert.Equal(t, firstCommitID, entries[1].NewID)
+			assert.Equal(t, "reset: moving to HEAD~1", entries[1].Message)
+
+			assert.Equal(t, plumbing.ZeroHash, entries[3].OldID)
+			assert.Equal(t, firstCommitID, entries[3].NewID)
+		}
+
+		// Only the discarded commit is no longer reachable
+		commitIDs, err := GetReflogOnlyCommits(repo, refName)
+		assert.Nil(t, err)
+		assert.Equal(t, []plumbing.Hash{secondCommitID}, commitIDs)
+
+		// The commit is no longer lost once another ref points to it
+		if err := repo.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName("refs/heads/recovered"), secondCommitID)); err != nil {
+			t.Fatal(err)
+		}
+		commitIDs, err = GetReflogOnlyCommits(repo, refName)
+		assert.Nil(t, err)
+		assert.Empty(t, commitIDs)
+	})
+
+	t.Run("ref without reflog", func(t *testing.T) {
+		repo, err := git.PlainInit(t.TempDir(), true)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		entries, err := GetReflog(repo, refName)
+		assert.Nil(t, err)
+		assert.Empty(t, entries)
+
+		commitIDs, err := GetReflogOnlyCommits(repo, refName)
+		assert.Nil(t, err)
+		assert.Empty(t, commitIDs)
+	})
+
+	t.Run("invalid reflog", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		repo, err := git.PlainInit(tmpDir, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		writeTestReflog(t, tmpDir, refName, "not a reflog entry")
+
+		_, err = GetReflog(repo, refName)
+		assert.ErrorIs(t, err, ErrInvalidReflogEntry)
+	})
+
+	t.Run("in-memory repository", func(t *testing.T) {
+		repo, err := git.Init(memory.NewStorage(), memfs.New())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		entries, err := GetReflog(repo, refName)
+		assert.Nil(t, err)
+		assert.Empty(t, entries)
+	})
+}
+
+func writeTestReflogCommit(t *testing.T, repo *git.Repository, parentID plumbing.Hash, message string) plumbing.Hash {
+	t.Helper()
+
+	commitID, err := WriteCommit(repo, CreateCommitObject(testGitConfig, EmptyTree(), parentID, message, testClock))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return commitID
+}
+
+func writeTestReflog(t *testing.T, gitDir, refName string, lines ...string) {
+	t.Helper()
+
+	reflogPath := filepath.Join(gitDir, reflogDirName, filepath.FromSlash(refName))
+	if err := os.MkdirAll(filepath.Dir(reflogPath), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(reflogPath, []byte(strings.Join(lines, "\n")+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,252 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gitinterface
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/gittuf/gittuf/internal/third_party/go-git"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/storage/filesystem"
+	billy "github.com/go-git/go-billy/v5"
+)
+
+const (
+	packedRefsFileName = "packed-refs"
+	lockFileSuffix     = ".lock"
+	packedRefsHeader   = "# pack-refs with: sorted \n"
+)
+
+var ErrPackedRefsLocked = errors.New("packed-refs file is locked by another process")
+
+// PackRefs moves the loose refs with any of the specified prefixes into the
+// repository's packed-refs file, leaving all other loose refs untouched. Refs that are being
+// updated, i.e., those that have a corresponding lock file, are not packed.
+// Similarly, a loose ref is not removed if it changes while the packed-refs
+// file is written. Symbolic refs are never packed. PackRefs is a no-op for
+// repositories that are not stored on a filesystem, such as in-memory
+// repositories.
+func PackRefs(repo *git.Repository, prefixes ...string) error {
+	storage, ok := repo.Storer.(*filesystem.Storage)
+	if !ok {
+		return nil
+	}
+	fs := storage.Filesystem()
+
+	// Take the packed-refs lock the same way Git does, so that we don't race
+	// with a concurrent pack-refs or ref deletion.
+	lockFile, err := fs.OpenFile(packedRefsFileName+lockFileSuffix, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+	if err != nil {
+		if os.IsExist(err) {
+			return ErrPackedRefsLocked
+		}
+		return err
+	}
+	lockFileName := lockFile.Name()
+	renamed := false
+	defer func() {
+		if !renamed {
+			lockFile.Close()        //nolint:errcheck
+			fs.Remove(lockFileName) //nolint:errcheck
+		}
+	}()
+
+	looseRefs := map[string]plumbing.Hash{}
+	for _, prefix := range prefixes {
+		if err := readLooseRefs(fs, strings.TrimSuffix(prefix, "/"), prefix, looseRefs); err != nil {
+			return err
+		}
+	}
+	if len(looseRefs) == 0 {
+		return nil
+	}
+
+	packedRefs, err := readPackedRefs(fs)
+	if err != nil {
+		return err
+	}
+	for name, hash := range looseRefs {
+		// Loose refs take precedence over packed refs, so any peeled value
+		// recorded for the previously packed ref is stale
+		packedRefs[name] = []string{fmt.Sprintf("%s %s", hash.String(), name)}
+	}
+
+	names := make([]string, 0, len(packedRefs))
+	for name := range packedRefs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	contents := bytes.NewBufferString(packedRefsHeader)
+	for _, name := range names {
+		for _, line := range packedRefs[name] {
+			contents.WriteString(line + "\n")
+		}
+	}
+
+	if _, err := lockFile.Write(contents.Bytes()); err != nil {
+		return err
+	}
+	if err := lockFile.Close(); err != nil {
+		return err
+	}
+	if err := fs.Rename(lockFileName, packedRefsFileName); err != nil {
+		return err
+	}
+	renamed = true
+
+	// Now that the refs are packed, remove the loose copies that haven't
+	// changed in the meantime
+	for name, hash := range looseRefs {
+		if err := removeLooseRefIfUnchanged(fs, name, hash); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// readLooseRefs walks the refs directory dir and records the hashes of all the
+// loose refs with the specified prefix that are safe to pack.
+func readLooseRefs(fs billy.Filesystem, dir, prefix string, refs map[string]plumbing.Hash) error {
+	files, err := fs.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	for _, file := range files {
+		name := path.Join(dir, file.Name())
+
+		if file.IsDir() {
+			if err := readLooseRefs(fs, name, prefix, refs); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if strings.HasSuffix(name, lockFileSuffix) || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+
+		// The ref is mid-update
+		if _, err := fs.Stat(name + lockFileSuffix); err == nil {
+			continue
+		}
+
+		hash, isHash, err := readLooseRef(fs, name)
+		if err != nil {
+			return err
+		}
+		if !isHash {
+			continue
+		}
+
+		refs[name] = hash
+	}
+
+	return nil
+}
+
+// readLooseRef returns the hash recorded in a loose ref. If the ref is a
+// symbolic ref, false is returned.
+func readLooseRef(fs billy.Filesystem, name string) (plumbing.Hash, bool, error) {
+	file, err := fs.Open(name)
+	if err != nil {
+		return plumbing.ZeroHash, false, err
+	}
+	defer file.Close() //nolint:errcheck
+
+	contents, err := io.ReadAll(file)
+	if err != nil {
+		return plumbing.ZeroHash, false, err
+	}
+
+	line := strings.TrimSpace(string(contents))
+	if !plumbing.IsHash(line) {
+		return plumbing.ZeroHash, false, nil
+	}
+
+	return plumbing.NewHash(line), true, nil
+}
+
+// readPackedRefs returns the entries in the packed-refs file keyed by ref name.
+// Each entry includes the line recording the ref and its peeled value, if any.
+func readPackedRefs(fs billy.Filesystem) (map[string][]string, error) {
+	packedRefs := map[string][]string{}
+
+	file, err := fs.Open(packedRefsFileName)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return packedRefs, nil
+		}
+		return nil, err
+	}
+	defer file.Close() //nolint:errcheck
+
+	lastRef := ""
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		switch {
+		case len(line) == 0, strings.HasPrefix(line, "#"):
+			continue
+		case strings.HasPrefix(line, "^"):
+			if lastRef != "" {
+				packedRefs[lastRef] = append(packedRefs[lastRef], line)
+			}
+		default:
+			split := strings.SplitN(line, " ", 2)
+			if len(split) != 2 {
+				return nil, fmt.Errorf("malformed packed-refs line '%s'", line)
+			}
+			lastRef = split[1]
+			packedRefs[lastRef] = []string{line}
+		}
+	}
+
+	return packedRefs, scanner.Err()
+}
+
+// removeLooseRefIfUnchanged removes the loose ref if it still points to the
+// expected hash. The ref is locked while it is inspected so that a concurrent
+// update is not lost.
+func removeLooseRefIfUnchanged(fs billy.Filesystem, name string, expectedHash plumbing.Hash) error {
+	lockFile, err := fs.OpenFile(name+lockFileSuffix, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+	if err != nil {
+		if os.IsExist(err) {
+			// The ref is being updated, leave the loose copy in place as it
+			// takes precedence over the packed copy
+			return nil
+		}
+		return err
+	}
+	defer func() {
+		lockFile.Close()                 //nolint:errcheck
+		fs.Remove(name + lockFileSuffix) //nolint:errcheck
+	}()
+
+	hash, isHash, err := readLooseRef(fs, name)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	if !isHash || hash != expectedHash {
+		return nil
+	}
+
+	return fs.Remove(name)
+}
@@ -0,0 +1,105 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gitinterface
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/third_party/go-git"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/storage/memory"
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPackRefs(t *testing.T) {
+	prefix := "refs/gittuf/"
+	trackerPrefix := "refs/remotes/origin/gittuf/"
+	packedRef := "refs/gittuf/packed"
+	nestedPackedRef := "refs/gittuf/nested/packed"
+	lockedRef := "refs/gittuf/locked"
+	trackerRef := "refs/remotes/origin/gittuf/tracker"
+	otherRef := "refs/heads/main"
+
+	t.Run("on-disk repository", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		repo, err := git.PlainInit(tmpDir, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		refs := map[string]plumbing.Hash{}
+		for _, refName := range []string{packedRef, nestedPackedRef, lockedRef, trackerRef, otherRef} {
+			commitID, err := WriteCommit(repo, CreateCommitObject(testGitConfig, EmptyTree(), plumbing.ZeroHash, refName, testClock))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := repo.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(refName), commitID)); err != nil {
+				t.Fatal(err)
+			}
+			refs[refName] = commitID
+		}
+
+		// Simulate an in-progress update of lockedRef
+		if err := os.WriteFile(filepath.Join(tmpDir, lockedRef+lockFileSuffix), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		err = PackRefs(repo, prefix, trackerPrefix)
+		assert.Nil(t, err)
+
+		assert.NoFileExists(t, filepath.Join(tmpDir, packedRef))
+		assert.NoFileExists(t, filepath.Join(tmpDir, trackerRef))
+		assert.NoFileExists(t, filepath.Join(tmpDir, nestedPackedRef))
+		assert.FileExists(t, filepath.Join(tmpDir, lockedRef))
+		assert.FileExists(t, filepath.Join(tmpDir, otherRef))
+		assert.NoFileExists(t, filepath.Join(tmpDir, packedRefsFileName+lockFileSuffix))
+
+		packedRefsContents, err := os.ReadFile(filepath.Join(tmpDir, packedRefsFileName))
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Contains(t, string(packedRefsContents), packedRef)
+		assert.Contains(t, string(packedRefsContents), nestedPackedRef)
+		assert.Contains(t, string(packedRefsContents), trackerRef)
+		assert.NotContains(t, string(packedRefsContents), lockedRef)
+		assert.NotContains(t, string(packedRefsContents), otherRef)
+
+		for refName, expectedTip := range refs {
+			tip, err := GetTip(repo, refName)
+			assert.Nil(t, err)
+			assert.Equal(t, expectedTip, tip)
+		}
+
+		// Packing again is a no-op
+		err = PackRefs(repo, prefix, trackerPrefix)
+		assert.Nil(t, err)
+	})
+
+	t.Run("packed-refs locked", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		repo, err := git.PlainInit(tmpDir, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(filepath.Join(tmpDir, packedRefsFileName+lockFileSuffix), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		err = PackRefs(repo, prefix)
+		assert.ErrorIs(t, err, ErrPackedRefsLocked)
+	})
+
+	t.Run("in-memory repository", func(t *testing.T) {
+		repo, err := git.Init(memory.NewStorage(), memfs.New())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = PackRefs(repo, prefix)
+		assert.Nil(t, err)
+	})
+}
@@ -0,0 +1,276 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gitinterface
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/gittuf/gittuf/internal/third_party/go-git"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/config"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/storage/filesystem"
+)
+
+// incompleteCloneMarkerFileName is the name of the file in the Git directory
+// that marks a clone that has not completed.
+const incompleteCloneMarkerFileName = "gittuf-clone-incomplete"
+
+var (
+	ErrFetchingRefsAfterClone  = errors.New("repository was cloned but the requested refs could not be fetched")
+	ErrRemoteHEADNotDetermined = errors.New("unable to determine the branch to check out from the remote's HEAD")
+	ErrNotIncompleteClone      = errors.New("repository is not an incomplete clone")
+	ErrCloneRemoteMismatch     = errors.New("incomplete clone is of a different remote")
+)
+
+// ResumableCloneAndFetch clones the repository at remoteURL into dir and
+// additionally fetches the specified refs, like CloneAndFetch. Unlike
+// CloneAndFetch, progress is kept when the clone is interrupted, and calling
+// ResumableCloneAndFetch again for the same dir resumes the clone rather than
+// starting over. The remote's branches are fetched one at a time, and branches
+// whose remote trackers are already up to date are not fetched again. The
+// fetches are configured using opts, e.g., to retry transient failures.
+//
+// dir must either not exist, be empty, or contain a clone of the repository
+// that was interrupted, see CheckIncompleteClone. The directory is never
+// removed on failure.
+func ResumableCloneAndFetch(ctx context.Context, remoteURL, dir, initialBranch string, refs []string, opts ...SyncOption) (*git.Repository, error) {
+	options := &SyncOptions{}
+	for _, fn := range opts {
+		fn(options)
+	}
+
+	repo, err := git.PlainOpen(dir)
+	if err != nil {
+		if !errors.Is(err, git.ErrRepositoryNotExists) {
+			return nil, err
+		}
+
+		repo, err = git.PlainInit(dir, false)
+		if err != nil {
+			return nil, err
+		}
+		if err := MarkCloneIncomplete(repo); err != nil {
+			return nil, err
+		}
+	} else if err := CheckIncompleteClone(repo, remoteURL); err != nil {
+		return nil, err
+	}
+
+	if _, err := repo.Remote(DefaultRemoteName); err != nil {
+		if !errors.Is(err, git.ErrRemoteNotFound) {
+			return nil, err
+		}
+
+		if _, err := repo.CreateRemote(&config.RemoteConfig{
+			Name:  DefaultRemoteName,
+			URLs:  []string{remoteURL},
+			Fetch: []config.RefSpec{config.RefSpec(fmt.Sprintf(config.DefaultFetchRefSpec, DefaultRemoteName))},
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	remoteRefs, err := listRemoteRefs(ctx, repo, options)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, ref := range remoteRefs {
+		if !ref.Name().IsBranch() {
+			continue
+		}
+
+		trackerRefName := plumbing.NewRemoteReferenceName(DefaultRemoteName, ref.Name().Short())
+		trackerTip, err := GetTip(repo, trackerRefName.String())
+		if err == nil && trackerTip == ref.Hash() {
+			// Fetched before the clone was interrupted
+			continue
+		}
+
+		refSpec := config.RefSpec(fmt.Sprintf("+%s:%s", ref.Name().String(), trackerRefName.String()))
+		if err := FetchRefSpec(ctx, repo, DefaultRemoteName, []config.RefSpec{refSpec}, opts...); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := FetchRefSpec(ctx, repo, DefaultRemoteName, []config.RefSpec{"+refs/tags/*:refs/tags/*"}, opts...); err != nil && !errors.Is(err, git.NoMatchingRefSpecError{}) {
+		return nil, err
+	}
+
+	if len(refs) > 0 {
+		if err := Fetch(ctx, repo, DefaultRemoteName, refs, true, opts...); err != nil {
+			return nil, errors.Join(ErrFetchingRefsAfterClone, err)
+		}
+	}
+
+	if err := checkoutInitialBranch(repo, initialBranch, remoteRefs); err != nil {
+		return nil, err
+	}
+
+	if err := unmarkCloneIncomplete(repo); err != nil {
+		return nil, err
+	}
+
+	return repo, nil
+}
+
+// MarkCloneIncomplete records in the repository's Git directory that it is a
+// clone that has not completed, which allows the clone to be resumed or
+// aborted. The marker is removed once ResumableCloneAndFetch completes the
+// clone.
+func MarkCloneIncomplete(repo *git.Repository) error {
+	storage, ok := repo.Storer.(*filesystem.Storage)
+	if !ok {
+		return ErrNotIncompleteClone
+	}
+
+	markerFile, err := storage.Filesystem().Create(incompleteCloneMarkerFileName)
+	if err != nil {
+		return err
+	}
+	return markerFile.Close()
+}
+
+// CheckIncompleteClone checks that the repository is marked as a clone that has
+// not completed, see MarkCloneIncomplete, and that it is a clone of remoteURL.
+// If the repository is not marked, ErrNotIncompleteClone is returned, so that a
+// complete clone or an unrelated repository is never resumed or removed. If the
+// default remote is declared with a different URL, ErrCloneRemoteMismatch is
+// returned.
+func CheckIncompleteClone(repo *git.Repository, remoteURL string) error {
+	storage, ok := repo.Storer.(*filesystem.Storage)
+	if !ok {
+		return ErrNotIncompleteClone
+	}
+
+	if _, err := storage.Filesystem().Stat(incompleteCloneMarkerFileName); err != nil {
+		if os.IsNotExist(err) {
+			return ErrNotIncompleteClone
+		}
+		return err
+	}
+
+	remote, err := repo.Remote(DefaultRemoteName)
+	if err != nil {
+		if errors.Is(err, git.ErrRemoteNotFound) {
+			// The clone was interrupted before the remote was declared
+			return nil
+		}
+		return err
+	}
+	for _, url := range remote.Config().URLs {
+		if url != remoteURL {
+			return fmt.Errorf("%w: '%s' is cloned from '%s'", ErrCloneRemoteMismatch, remoteURL, url)
+		}
+	}
+
+	return nil
+}
+
+// unmarkCloneIncomplete removes the marker of a clone that has not completed.
+func unmarkCloneIncomplete(repo *git.Repository) error {
+	storage, ok := repo.Storer.(*filesystem.Storage)
+	if !ok {
+		return nil
+	}
+
+	if err := storage.Filesystem().Remove(incompleteCloneMarkerFileName); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// listRemoteRefs returns the refs advertised by the default remote.
+func listRemoteRefs(ctx context.Context, repo *git.Repository, options *SyncOptions) ([]*plumbing.Reference, error) {
+	remote, err := repo.Remote(DefaultRemoteName)
+	if err != nil {
+		return nil, err
+	}
+
+	var remoteRefs []*plumbing.Reference
+	err = withRetry(ctx, options, func() error {
+		var err error
+		remoteRefs, err = remote.ListContext(ctx, &git.ListOptions{PeelingOption: git.IgnorePeeled})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return remoteRefs, nil
+}
+
+// checkoutInitialBranch creates the initial branch from its remote tracker and
+// checks it out, unless a branch was already checked out before the clone was
+// interrupted. If initialBranch is not specified, the branch the remote's HEAD
+// points to is used.
+func checkoutInitialBranch(repo *git.Repository, initialBranch string, remoteRefs []*plumbing.Reference) error {
+	if _, err := repo.Head(); err == nil {
+		return nil
+	}
+
+	branchRefName := plumbing.ReferenceName(initialBranch)
+	if len(initialBranch) > 0 && !strings.HasPrefix(initialBranch, BranchRefPrefix) {
+		branchRefName = plumbing.NewBranchReferenceName(initialBranch)
+	}
+	if len(initialBranch) == 0 {
+		remoteHEAD, err := getRemoteHEADBranch(remoteRefs)
+		if err != nil {
+			return err
+		}
+		branchRefName = remoteHEAD
+	}
+
+	trackerTip, err := GetTip(repo, plumbing.NewRemoteReferenceName(DefaultRemoteName, branchRefName.Short()).String())
+	if err != nil {
+		return err
+	}
+
+	if err := repo.Storer.SetReference(plumbing.NewHashReference(branchRefName, trackerTip)); err != nil {
+		return err
+	}
+	if err := repo.Storer.SetReference(plumbing.NewSymbolicReference(plumbing.HEAD, branchRefName)); err != nil {
+		return err
+	}
+	if err := repo.CreateBranch(&config.Branch{Name: branchRefName.Short(), Remote: DefaultRemoteName, Merge: branchRefName}); err != nil && !errors.Is(err, git.ErrBranchExists) {
+		return err
+	}
+
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return err
+	}
+	return worktree.Reset(&git.ResetOptions{Commit: trackerTip, Mode: git.HardReset})
+}
+
+// getRemoteHEADBranch returns the branch the remote's HEAD points to. If the
+// remote does not advertise HEAD as a symbolic ref, the branch HEAD points to
+// is identified by its tip.
+func getRemoteHEADBranch(remoteRefs []*plumbing.Reference) (plumbing.ReferenceName, error) {
+	var head *plumbing.Reference
+	for _, ref := range remoteRefs {
+		if ref.Name() == plumbing.HEAD {
+			head = ref
+			break
+		}
+	}
+	if head == nil {
+		return "", ErrRemoteHEADNotDetermined
+	}
+
+	if head.Type() == plumbing.SymbolicReference {
+		return head.Target(), nil
+	}
+
+	for _, ref := range remoteRefs {
+		if ref.Name().IsBranch() && ref.Hash() == head.Hash() {
+			return ref.Name(), nil
+		}
+	}
+
+	return "", ErrRemoteHEADNotDetermined
+}
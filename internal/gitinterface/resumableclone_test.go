@@ -0,0 +1,215 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gitinterface
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/third_party/go-git"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/config"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResumableCloneAndFetch(t *testing.T) {
+	refName := "refs/heads/main"
+	anotherRefName := "refs/heads/feature"
+	gittufRefName := "refs/gittuf/test"
+
+	createRemote := func(t *testing.T) (string, *git.Repository, plumbing.Hash, plumbing.Hash) {
+		t.Helper()
+
+		remoteTmpDir := t.TempDir()
+		remoteRepo, err := git.PlainInit(remoteTmpDir, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		emptyTreeHash, err := WriteTree(remoteRepo, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		mainCommitID, err := Commit(remoteRepo, emptyTreeHash, refName, "Commit to main", false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		otherCommitID, err := Commit(remoteRepo, emptyTreeHash, anotherRefName, "Commit to feature", false)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := remoteRepo.Storer.SetReference(plumbing.NewSymbolicReference(plumbing.HEAD, plumbing.ReferenceName(refName))); err != nil {
+			t.Fatal(err)
+		}
+
+		return remoteTmpDir, remoteRepo, mainCommitID, otherCommitID
+	}
+
+	t.Run("clone without interruption", func(t *testing.T) {
+		remoteTmpDir, remoteRepo, mainCommitID, otherCommitID := createRemote(t)
+		emptyTreeHash, err := WriteTree(remoteRepo, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		gittufCommitID, err := Commit(remoteRepo, emptyTreeHash, gittufRefName, "Commit to gittuf ref", false)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		localTmpDir := t.TempDir()
+		localRepo, err := ResumableCloneAndFetch(context.Background(), remoteTmpDir, localTmpDir, "", []string{gittufRefName})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		head, err := localRepo.Head()
+		assert.Nil(t, err)
+		assert.Equal(t, plumbing.ReferenceName(refName), head.Name())
+		assert.Equal(t, mainCommitID, head.Hash())
+
+		trackerTip, err := GetTip(localRepo, plumbing.NewRemoteReferenceName(DefaultRemoteName, "feature").String())
+		assert.Nil(t, err)
+		assert.Equal(t, otherCommitID, trackerTip)
+
+		localGittufTip, err := GetTip(localRepo, gittufRefName)
+		assert.Nil(t, err)
+		assert.Equal(t, gittufCommitID, localGittufTip)
+
+		// A complete clone is not resumed
+		_, err = ResumableCloneAndFetch(context.Background(), remoteTmpDir, localTmpDir, "", []string{gittufRefName})
+		assert.ErrorIs(t, err, ErrNotIncompleteClone)
+	})
+
+	t.Run("resume interrupted clone", func(t *testing.T) {
+		remoteTmpDir, _, mainCommitID, otherCommitID := createRemote(t)
+		localTmpDir := t.TempDir()
+
+		// Simulate a clone that was interrupted after fetching one branch
+		localRepo, err := git.PlainInit(localTmpDir, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := MarkCloneIncomplete(localRepo); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := localRepo.CreateRemote(&config.RemoteConfig{
+			Name:  DefaultRemoteName,
+			URLs:  []string{remoteTmpDir},
+			Fetch: []config.RefSpec{config.RefSpec(fmt.Sprintf(config.DefaultFetchRefSpec, DefaultRemoteName))},
+		}); err != nil {
+			t.Fatal(err)
+		}
+		mainTrackerRefName := plumbing.NewRemoteReferenceName(DefaultRemoteName, "main")
+		refSpec := config.RefSpec(fmt.Sprintf("+%s:%s", refName, mainTrackerRefName))
+		if err := FetchRefSpec(context.Background(), localRepo, DefaultRemoteName, []config.RefSpec{refSpec}); err != nil {
+			t.Fatal(err)
+		}
+
+		localRepo, err = ResumableCloneAndFetch(context.Background(), remoteTmpDir, localTmpDir, refName, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		head, err := localRepo.Head()
+		assert.Nil(t, err)
+		assert.Equal(t, plumbing.ReferenceName(refName), head.Name())
+		assert.Equal(t, mainCommitID, head.Hash())
+
+		trackerTip, err := GetTip(localRepo, plumbing.NewRemoteReferenceName(DefaultRemoteName, "feature").String())
+		assert.Nil(t, err)
+		assert.Equal(t, otherCommitID, trackerTip)
+
+		branchConfig, err := localRepo.Branch("main")
+		assert.Nil(t, err)
+		assert.Equal(t, DefaultRemoteName, branchConfig.Remote)
+
+		worktree, err := localRepo.Worktree()
+		if err != nil {
+			t.Fatal(err)
+		}
+		status, err := worktree.Status()
+		assert.Nil(t, err)
+		assert.True(t, status.IsClean())
+	})
+
+	t.Run("resume after failing to fetch refs", func(t *testing.T) {
+		remoteTmpDir, remoteRepo, mainCommitID, _ := createRemote(t)
+		localTmpDir := t.TempDir()
+
+		// The gittuf ref doesn't exist in the remote yet
+		localRepo, err := ResumableCloneAndFetch(context.Background(), remoteTmpDir, localTmpDir, "", []string{gittufRefName})
+		assert.ErrorIs(t, err, ErrFetchingRefsAfterClone)
+		assert.Nil(t, localRepo)
+
+		// The branches fetched before the failure are kept
+		openedRepo, err := git.PlainOpen(localTmpDir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		trackerTip, err := GetTip(openedRepo, plumbing.NewRemoteReferenceName(DefaultRemoteName, "main").String())
+		assert.Nil(t, err)
+		assert.Equal(t, mainCommitID, trackerTip)
+
+		emptyTreeHash, err := WriteTree(remoteRepo, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		gittufCommitID, err := Commit(remoteRepo, emptyTreeHash, gittufRefName, "Commit to gittuf ref", false)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		localRepo, err = ResumableCloneAndFetch(context.Background(), remoteTmpDir, localTmpDir, "", []string{gittufRefName})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		head, err := localRepo.Head()
+		assert.Nil(t, err)
+		assert.Equal(t, mainCommitID, head.Hash())
+
+		localGittufTip, err := GetTip(localRepo, gittufRefName)
+		assert.Nil(t, err)
+		assert.Equal(t, gittufCommitID, localGittufTip)
+	})
+
+	t.Run("resume clone of a different remote", func(t *testing.T) {
+		remoteTmpDir, _, _, _ := createRemote(t)
+		otherRemoteTmpDir, _, _, _ := createRemote(t)
+		localTmpDir := t.TempDir()
+
+		// The gittuf ref doesn't exist in the remote, so the clone is
+		// incomplete
+		_, err := ResumableCloneAndFetch(context.Background(), remoteTmpDir, localTmpDir, "", []string{gittufRefName})
+		assert.ErrorIs(t, err, ErrFetchingRefsAfterClone)
+
+		_, err = ResumableCloneAndFetch(context.Background(), otherRemoteTmpDir, localTmpDir, "", []string{gittufRefName})
+		assert.ErrorIs(t, err, ErrCloneRemoteMismatch)
+	})
+}
+
+func TestCloneAndFetchWithMissingRef(t *testing.T) {
+	remoteTmpDir := t.TempDir()
+	remoteRepo, err := git.PlainInit(remoteTmpDir, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	emptyTreeHash, err := WriteTree(remoteRepo, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Commit(remoteRepo, emptyTreeHash, "refs/heads/main", "Commit to main", false); err != nil {
+		t.Fatal(err)
+	}
+	if err := remoteRepo.Storer.SetReference(plumbing.NewSymbolicReference(plumbing.HEAD, "refs/heads/main")); err != nil {
+		t.Fatal(err)
+	}
+
+	// The clone succeeds, so the repository is returned with the error
+	localRepo, err := CloneAndFetch(context.Background(), remoteTmpDir, t.TempDir(), "", []string{"refs/gittuf/test"})
+	assert.ErrorIs(t, err, ErrFetchingRefsAfterClone)
+	assert.NotNil(t, localRepo)
+}
@@ -0,0 +1,157 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gitinterface
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gittuf/gittuf/internal/third_party/go-git"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing/hash"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing/object"
+)
+
+// minShortHashLength is the minimum length of an abbreviated object ID, as in
+// Git.
+const minShortHashLength = 4
+
+var (
+	ErrRevisionNotFound  = errors.New("unable to resolve revision (must be a reference or a commit identifier)")
+	ErrAmbiguousRevision = errors.New("abbreviated commit identifier is ambiguous")
+	ErrRevisionNotCommit = errors.New("revision does not identify a commit")
+)
+
+// ResolveCommit resolves the revision to a commit. The revision may be HEAD, a
+// fully qualified ref, a branch or tag name, a remote tracker such as
+// "origin/main", or a full or abbreviated commit ID. Refs are resolved in the
+// same order as AbsoluteReference, i.e., branches are preferred to tags, and
+// symbolic refs are followed to their targets. Annotated tags are peeled to
+// the commit they point to. As in Git, a ref is preferred to an abbreviated
+// commit ID with the same name, and ErrAmbiguousRevision is returned if an
+// abbreviated commit ID matches more than one commit. Revisions that use Git's
+// ancestry operators, such as "HEAD~1", are also supported.
+//
+// ErrRevisionNotFound is returned if the revision does not match any ref or
+// object, and ErrRevisionNotCommit is returned if it identifies an object that
+// is neither a commit nor an annotated tag of a commit.
+func ResolveCommit(repo *git.Repository, rev string) (*object.Commit, error) {
+	if len(rev) == 0 {
+		return nil, ErrRevisionNotFound
+	}
+
+	if plumbing.IsHash(rev) {
+		return peelToCommit(repo, plumbing.NewHash(rev))
+	}
+
+	for _, refName := range candidateRefNames(rev) {
+		ref, err := repo.Reference(plumbing.ReferenceName(refName), true)
+		if err == nil {
+			return peelToCommit(repo, ref.Hash())
+		}
+		if !errors.Is(err, plumbing.ErrReferenceNotFound) {
+			return nil, err
+		}
+	}
+
+	if isShortHash(rev) {
+		commitIDs, err := findCommitsWithPrefix(repo, rev)
+		if err != nil {
+			return nil, err
+		}
+
+		switch len(commitIDs) {
+		case 0:
+			return nil, fmt.Errorf("%w: '%s'", ErrRevisionNotFound, rev)
+		case 1:
+			return repo.CommitObject(commitIDs[0])
+		default:
+			return nil, fmt.Errorf("%w: '%s' matches %d commits", ErrAmbiguousRevision, rev, len(commitIDs))
+		}
+	}
+
+	if strings.ContainsAny(rev, "~^") {
+		commitID, err := repo.ResolveRevision(plumbing.Revision(rev))
+		if err != nil {
+			return nil, errors.Join(ErrRevisionNotFound, err)
+		}
+
+		return repo.CommitObject(*commitID)
+	}
+
+	return nil, fmt.Errorf("%w: '%s'", ErrRevisionNotFound, rev)
+}
+
+// candidateRefNames returns the refs that the revision may refer to in the
+// order they are checked.
+func candidateRefNames(rev string) []string {
+	if rev == plumbing.HEAD.String() || strings.HasPrefix(rev, RefPrefix) {
+		return []string{rev}
+	}
+
+	return []string{
+		BranchRefPrefix + rev,
+		TagRefPrefix + rev,
+		RemoteRefPrefix + rev,
+		RemoteRefPrefix + rev + "/" + plumbing.HEAD.String(),
+	}
+}
+
+// peelToCommit returns the commit identified by objectID, peeling annotated
+// tags to their targets.
+func peelToCommit(repo *git.Repository, objectID plumbing.Hash) (*object.Commit, error) {
+	obj, err := repo.Object(plumbing.AnyObject, objectID)
+	if err != nil {
+		if errors.Is(err, plumbing.ErrObjectNotFound) {
+			return nil, fmt.Errorf("%w: '%s'", ErrRevisionNotFound, objectID.String())
+		}
+		return nil, err
+	}
+
+	switch obj := obj.(type) {
+	case *object.Commit:
+		return obj, nil
+	case *object.Tag:
+		return peelToCommit(repo, obj.Target)
+	default:
+		return nil, fmt.Errorf("%w: '%s' is a %s", ErrRevisionNotCommit, objectID.String(), obj.Type().String())
+	}
+}
+
+// isShortHash returns true if the revision may be an abbreviated object ID.
+func isShortHash(rev string) bool {
+	if len(rev) < minShortHashLength || len(rev) >= hash.HexSize {
+		return false
+	}
+
+	for _, c := range rev {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", c) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// findCommitsWithPrefix returns the IDs of the commits in the repository whose
+// IDs start with the abbreviated commit ID.
+func findCommitsWithPrefix(repo *git.Repository, prefix string) ([]plumbing.Hash, error) {
+	prefix = strings.ToLower(prefix)
+
+	iter, err := repo.Storer.IterEncodedObjects(plumbing.CommitObject)
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Close()
+
+	commitIDs := []plumbing.Hash{}
+	err = iter.ForEach(func(obj plumbing.EncodedObject) error {
+		if strings.HasPrefix(obj.Hash().String(), prefix) {
+			commitIDs = append(commitIDs, obj.Hash())
+		}
+		return nil
+	})
+
+	return commitIDs, err
+}
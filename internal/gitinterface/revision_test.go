@@ -0,0 +1,185 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gitinterface
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/third_party/go-git"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/config"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/storage/memory"
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveCommit(t *testing.T) {
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clock = testClock
+	getGitConfig = func(repo *git.Repository) (*config.Config, error) {
+		return testGitConfig, nil
+	}
+
+	emptyTreeID, err := WriteTree(repo, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	firstCommitID, err := Commit(repo, emptyTreeID, "refs/heads/main", "First commit", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	secondCommitID, err := Commit(repo, emptyTreeID, "refs/heads/main", "Second commit", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	featureCommitID, err := Commit(repo, emptyTreeID, "refs/heads/feature", "Feature commit", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := repo.Storer.SetReference(plumbing.NewSymbolicReference(plumbing.HEAD, plumbing.ReferenceName("refs/heads/main"))); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName("refs/remotes/origin/main"), firstCommitID)); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.Storer.SetReference(plumbing.NewSymbolicReference(plumbing.ReferenceName("refs/remotes/origin/HEAD"), plumbing.ReferenceName("refs/remotes/origin/main"))); err != nil {
+		t.Fatal(err)
+	}
+
+	annotatedTagID, err := Tag(repo, firstCommitID, "v1", "v1", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName("refs/tags/lightweight"), featureCommitID)); err != nil {
+		t.Fatal(err)
+	}
+
+	// A tag with the same name as a branch, the branch must be preferred
+	if err := repo.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName("refs/tags/feature"), firstCommitID)); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		rev              string
+		expectedCommitID plumbing.Hash
+		expectedError    error
+	}{
+		"branch name": {
+			rev:              "main",
+			expectedCommitID: secondCommitID,
+		},
+		"fully qualified branch": {
+			rev:              "refs/heads/feature",
+			expectedCommitID: featureCommitID,
+		},
+		"branch preferred to tag": {
+			rev:              "feature",
+			expectedCommitID: featureCommitID,
+		},
+		"annotated tag name": {
+			rev:              "v1",
+			expectedCommitID: firstCommitID,
+		},
+		"fully qualified annotated tag": {
+			rev:              "refs/tags/v1",
+			expectedCommitID: firstCommitID,
+		},
+		"annotated tag ID": {
+			rev:              annotatedTagID.String(),
+			expectedCommitID: firstCommitID,
+		},
+		"lightweight tag": {
+			rev:              "lightweight",
+			expectedCommitID: featureCommitID,
+		},
+		"HEAD": {
+			rev:              "HEAD",
+			expectedCommitID: secondCommitID,
+		},
+		"HEAD with ancestry operator": {
+			rev:              "HEAD~1",
+			expectedCommitID: firstCommitID,
+		},
+		"remote tracker": {
+			rev:              "origin/main",
+			expectedCommitID: firstCommitID,
+		},
+		"symbolic remote HEAD": {
+			rev:              "origin",
+			expectedCommitID: firstCommitID,
+		},
+		"full commit ID": {
+			rev:              featureCommitID.String(),
+			expectedCommitID: featureCommitID,
+		},
+		"abbreviated commit ID": {
+			rev:              secondCommitID.String()[:7],
+			expectedCommitID: secondCommitID,
+		},
+		"empty revision": {
+			rev:           "",
+			expectedError: ErrRevisionNotFound,
+		},
+		"unknown ref": {
+			rev:           "does-not-exist",
+			expectedError: ErrRevisionNotFound,
+		},
+		"unknown full commit ID": {
+			rev:           plumbing.ZeroHash.String(),
+			expectedError: ErrRevisionNotFound,
+		},
+		"unknown abbreviated commit ID": {
+			rev:           "0000000",
+			expectedError: ErrRevisionNotFound,
+		},
+		"abbreviated commit ID too short": {
+			rev:           secondCommitID.String()[:3],
+			expectedError: ErrRevisionNotFound,
+		},
+		"tree ID": {
+			rev:           emptyTreeID.String(),
+			expectedError: ErrRevisionNotCommit,
+		},
+	}
+
+	for name, test := range tests {
+		commit, err := ResolveCommit(repo, test.rev)
+		if test.expectedError != nil {
+			assert.ErrorIs(t, err, test.expectedError, fmt.Sprintf("unexpected error in test '%s'", name))
+		} else {
+			assert.Nil(t, err, fmt.Sprintf("unexpected error in test '%s'", name))
+			assert.Equal(t, test.expectedCommitID, commit.Hash, fmt.Sprintf("unexpected commit in test '%s'", name))
+		}
+	}
+
+	t.Run("ambiguous abbreviated commit ID", func(t *testing.T) {
+		// Create commits until two of them share an abbreviated ID
+		seen := map[string]bool{}
+		ambiguousPrefix := ""
+		for i := 0; i < 10000 && ambiguousPrefix == ""; i++ {
+			commit := CreateCommitObject(testGitConfig, emptyTreeID, plumbing.ZeroHash, fmt.Sprintf("Commit %d", i), testClock)
+			commitID, err := WriteCommit(repo, commit)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			prefix := commitID.String()[:minShortHashLength]
+			if seen[prefix] {
+				ambiguousPrefix = prefix
+			}
+			seen[prefix] = true
+		}
+		if ambiguousPrefix == "" {
+			t.Fatal("unable to create commits with ambiguous abbreviated ID")
+		}
+
+		_, err := ResolveCommit(repo, ambiguousPrefix)
+		assert.ErrorIs(t, err, ErrAmbiguousRevision)
+	})
+}
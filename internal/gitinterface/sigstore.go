@@ -0,0 +1,237 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gitinterface
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gittuf/gittuf/internal/signerverifier"
+	"github.com/sigstore/sigstore/pkg/fulcioroots"
+)
+
+const (
+	// SigstoreFulcioRootsEnvKey is the environment variable that can be set
+	// to the path of a PEM bundle with the root and intermediate certificates
+	// of a private Fulcio instance.
+	SigstoreFulcioRootsEnvKey = "GITTUF_SIGSTORE_FULCIO_ROOTS"
+
+	// SigstoreRekorURLEnvKey is the environment variable that can be set to
+	// the URL of a private Rekor instance.
+	SigstoreRekorURLEnvKey = "GITTUF_SIGSTORE_REKOR_URL"
+
+	// SigstoreOIDCIssuersEnvKey is the environment variable that can be set to
+	// a comma separated list of the OIDC issuers that are trusted to attest to
+	// signers' identities.
+	SigstoreOIDCIssuersEnvKey = "GITTUF_SIGSTORE_OIDC_ISSUERS"
+)
+
+var (
+	ErrInvalidFulcioTrustBundle = errors.New("trust bundle for Fulcio contains no valid certificates")
+	ErrUntrustedOIDCIssuer      = errors.New("OIDC issuer of Sigstore identity is not trusted")
+)
+
+// VerificationOptions contains the optional parameters used to verify
+// signatures on Git objects. Most of them only apply to Sigstore signatures and
+// allow verifying signatures issued by a private Sigstore deployment. Sigstore
+// options that are not set are read from the environment, falling back to the
+// public good Sigstore instance.
+type VerificationOptions struct {
+	// FulcioRoots and FulcioIntermediates are the certificate pools used to
+	// verify signing certificates.
+	FulcioRoots         *x509.CertPool
+	FulcioIntermediates *x509.CertPool
+
+	// RekorURL is the Rekor instance used to check that the signature was
+	// recorded in the transparency log.
+	RekorURL string
+
+	// TrustedOIDCIssuers restricts the OIDC issuers that may attest to the
+	// signer's identity. All issuers are trusted if it is empty.
+	TrustedOIDCIssuers []string
+
+	// KeyNotBefore and KeyNotAfter bound the period during which the key is
+	// trusted. Signatures are rejected if TrustedTime is outside the period.
+	// Zero values leave the period unbounded.
+	KeyNotBefore time.Time
+	KeyNotAfter  time.Time
+
+	// TrustedTime is when the signed object is known to have existed, such as
+	// when it was recorded in the RSL. It is checked against the key's
+	// validity window and the expiry of GPG keys. If it is not set, the
+	// validity window is checked against the signed object's own timestamp,
+	// which is controlled by the signer, and expiry against the current time.
+	TrustedTime time.Time
+}
+
+// VerificationOption is used to configure signature verification.
+type VerificationOption func(*VerificationOptions)
+
+// WithFulcioRoots sets the root and intermediate certificates used to verify
+// Sigstore signing certificates, such as those of a private Fulcio instance.
+// LoadFulcioTrustBundle can be used to create the certificate pools.
+func WithFulcioRoots(roots, intermediates *x509.CertPool) VerificationOption {
+	return func(o *VerificationOptions) {
+		o.FulcioRoots = roots
+		o.FulcioIntermediates = intermediates
+	}
+}
+
+// WithRekorURL sets the Rekor instance used to verify Sigstore signatures.
+func WithRekorURL(url string) VerificationOption {
+	return func(o *VerificationOptions) {
+		o.RekorURL = url
+	}
+}
+
+// WithTrustedOIDCIssuers restricts the OIDC issuers that may attest to the
+// identity in a Sigstore signing certificate.
+func WithTrustedOIDCIssuers(issuers ...string) VerificationOption {
+	return func(o *VerificationOptions) {
+		o.TrustedOIDCIssuers = issuers
+	}
+}
+
+// WithKeyValidityWindow restricts the key to verifying signatures on objects
+// known to have existed between notBefore and notAfter, as indicated by
+// WithTrustedTime. Either bound may be left as the zero value.
+func WithKeyValidityWindow(notBefore, notAfter time.Time) VerificationOption {
+	return func(o *VerificationOptions) {
+		o.KeyNotBefore = notBefore
+		o.KeyNotAfter = notAfter
+	}
+}
+
+// WithTrustedTime sets when the signed object is known to have existed, which
+// is checked against the key's validity window.
+func WithTrustedTime(trustedTime time.Time) VerificationOption {
+	return func(o *VerificationOptions) {
+		o.TrustedTime = trustedTime
+	}
+}
+
+// LoadFulcioTrustBundle parses a PEM bundle of Fulcio certificates.
+// Self-signed certificates are returned as roots, while all other certificates
+// are returned as intermediates.
+func LoadFulcioTrustBundle(contents []byte) (*x509.CertPool, *x509.CertPool, error) {
+	roots := x509.NewCertPool()
+	intermediates := x509.NewCertPool()
+
+	found := false
+	for {
+		var block *pem.Block
+		block, contents = pem.Decode(contents)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, nil, errors.Join(ErrInvalidFulcioTrustBundle, err)
+		}
+
+		if cert.CheckSignatureFrom(cert) == nil {
+			roots.AddCert(cert)
+		} else {
+			intermediates.AddCert(cert)
+		}
+		found = true
+	}
+
+	if !found {
+		return nil, nil, ErrInvalidFulcioTrustBundle
+	}
+
+	return roots, intermediates, nil
+}
+
+// getVerificationOptions applies opts and fills in any Sigstore options that
+// are not set using the environment. If the Fulcio certificates are not set in
+// either, they are left unset so that they are only fetched for the public good
+// instance when they're needed, using getFulcioRoots.
+func getVerificationOptions(opts ...VerificationOption) (*VerificationOptions, error) {
+	options := &VerificationOptions{}
+	for _, fn := range opts {
+		fn(options)
+	}
+
+	if options.FulcioRoots == nil {
+		if bundlePath := os.Getenv(SigstoreFulcioRootsEnvKey); len(bundlePath) > 0 {
+			contents, err := os.ReadFile(bundlePath)
+			if err != nil {
+				return nil, err
+			}
+
+			options.FulcioRoots, options.FulcioIntermediates, err = LoadFulcioTrustBundle(contents)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if len(options.RekorURL) == 0 {
+		options.RekorURL = os.Getenv(SigstoreRekorURLEnvKey)
+		if len(options.RekorURL) == 0 {
+			options.RekorURL = signerverifier.RekorServer
+		}
+	}
+
+	if len(options.TrustedOIDCIssuers) == 0 {
+		if issuers := os.Getenv(SigstoreOIDCIssuersEnvKey); len(issuers) > 0 {
+			for _, issuer := range strings.Split(issuers, ",") {
+				if issuer = strings.TrimSpace(issuer); len(issuer) > 0 {
+					options.TrustedOIDCIssuers = append(options.TrustedOIDCIssuers, issuer)
+				}
+			}
+		}
+	}
+
+	return options, nil
+}
+
+// getFulcioRoots returns the Fulcio root and intermediate certificates set in
+// options, falling back to those of the public good instance.
+func getFulcioRoots(options *VerificationOptions) (*x509.CertPool, *x509.CertPool, error) {
+	if options.FulcioRoots != nil {
+		intermediates := options.FulcioIntermediates
+		if intermediates == nil {
+			intermediates = x509.NewCertPool()
+		}
+		return options.FulcioRoots, intermediates, nil
+	}
+
+	roots, err := fulcioroots.Get()
+	if err != nil {
+		return nil, nil, err
+	}
+	intermediates, err := fulcioroots.GetIntermediates()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return roots, intermediates, nil
+}
+
+// checkOIDCIssuerIsTrusted returns an error if issuer is not one of the OIDC
+// issuers trusted in options.
+func checkOIDCIssuerIsTrusted(options *VerificationOptions, issuer string) error {
+	if len(options.TrustedOIDCIssuers) == 0 {
+		return nil
+	}
+
+	for _, trustedIssuer := range options.TrustedOIDCIssuers {
+		if issuer == trustedIssuer {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: '%s'", ErrUntrustedOIDCIssuer, issuer)
+}
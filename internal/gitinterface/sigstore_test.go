@@ -0,0 +1,187 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gitinterface
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadFulcioTrustBundle(t *testing.T) {
+	t.Run("root and intermediate", func(t *testing.T) {
+		bundle, leafCert := createTestFulcioTrustBundle(t)
+
+		roots, intermediates, err := LoadFulcioTrustBundle(bundle)
+		assert.Nil(t, err)
+
+		// The leaf chains through the intermediate to the root
+		verifyOpts := x509.VerifyOptions{Roots: roots, Intermediates: intermediates, KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning}}
+		chains, err := leafCert.Verify(verifyOpts)
+		assert.Nil(t, err)
+		if assert.Equal(t, 1, len(chains)) && assert.Equal(t, 3, len(chains[0])) {
+			assert.Equal(t, "private-fulcio-intermediate", chains[0][1].Subject.CommonName)
+			assert.Equal(t, "private-fulcio-root", chains[0][2].Subject.CommonName)
+		}
+
+		// The intermediate must not be trusted as a root, so the leaf does not
+		// verify without it
+		verifyOpts.Intermediates = nil
+		_, err = leafCert.Verify(verifyOpts)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("no certificates", func(t *testing.T) {
+		_, _, err := LoadFulcioTrustBundle([]byte("not a certificate"))
+		assert.ErrorIs(t, err, ErrInvalidFulcioTrustBundle)
+	})
+}
+
+func TestGetVerificationOptions(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		options, err := getVerificationOptions()
+		assert.Nil(t, err)
+		assert.Nil(t, options.FulcioRoots)
+		assert.Equal(t, "https://rekor.sigstore.dev", options.RekorURL)
+		assert.Empty(t, options.TrustedOIDCIssuers)
+	})
+
+	t.Run("from environment", func(t *testing.T) {
+		bundle, _ := createTestFulcioTrustBundle(t)
+		bundlePath := writeTestFulcioTrustBundle(t, bundle)
+
+		t.Setenv(SigstoreFulcioRootsEnvKey, bundlePath)
+		t.Setenv(SigstoreRekorURLEnvKey, "https://rekor.example.com")
+		t.Setenv(SigstoreOIDCIssuersEnvKey, "https://accounts.example.com, https://login.example.com")
+
+		options, err := getVerificationOptions()
+		assert.Nil(t, err)
+		assert.NotNil(t, options.FulcioRoots)
+		assert.NotNil(t, options.FulcioIntermediates)
+		assert.Equal(t, "https://rekor.example.com", options.RekorURL)
+		assert.Equal(t, []string{"https://accounts.example.com", "https://login.example.com"}, options.TrustedOIDCIssuers)
+	})
+
+	t.Run("options take precedence over environment", func(t *testing.T) {
+		t.Setenv(SigstoreRekorURLEnvKey, "https://rekor.example.com")
+		t.Setenv(SigstoreOIDCIssuersEnvKey, "https://accounts.example.com")
+
+		options, err := getVerificationOptions(WithRekorURL("https://rekor.internal.example.com"), WithTrustedOIDCIssuers("https://login.example.com"))
+		assert.Nil(t, err)
+		assert.Equal(t, "https://rekor.internal.example.com", options.RekorURL)
+		assert.Equal(t, []string{"https://login.example.com"}, options.TrustedOIDCIssuers)
+	})
+
+	t.Run("invalid bundle in environment", func(t *testing.T) {
+		t.Setenv(SigstoreFulcioRootsEnvKey, writeTestFulcioTrustBundle(t, []byte("not a certificate")))
+
+		_, err := getVerificationOptions()
+		assert.ErrorIs(t, err, ErrInvalidFulcioTrustBundle)
+	})
+}
+
+func TestCheckOIDCIssuerIsTrusted(t *testing.T) {
+	options := &VerificationOptions{}
+	assert.Nil(t, checkOIDCIssuerIsTrusted(options, "https://github.com/login/oauth"))
+
+	options.TrustedOIDCIssuers = []string{"https://accounts.example.com"}
+	assert.Nil(t, checkOIDCIssuerIsTrusted(options, "https://accounts.example.com"))
+	assert.ErrorIs(t, checkOIDCIssuerIsTrusted(options, "https://github.com/login/oauth"), ErrUntrustedOIDCIssuer)
+}
+
+// createTestFulcioTrustBundle returns a PEM bundle with a self-signed root and
+// an intermediate issued by it, along with a leaf certificate issued by the
+// intermediate. The leaf is not included in the bundle.
+func createTestFulcioTrustBundle(t *testing.T) ([]byte, *x509.Certificate) {
+	t.Helper()
+
+	rootKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{Organization: []string{"example.com"}, CommonName: "private-fulcio-root"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	rootDER, err := x509.CreateCertificate(rand.Reader, rootTemplate, rootTemplate, &rootKey.PublicKey, rootKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootCert, err := x509.ParseCertificate(rootDER)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	intermediateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	intermediateTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(2),
+		Subject:               pkix.Name{Organization: []string{"example.com"}, CommonName: "private-fulcio-intermediate"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	intermediateDER, err := x509.CreateCertificate(rand.Reader, intermediateTemplate, rootCert, &intermediateKey.PublicKey, rootKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	intermediateCert, err := x509.ParseCertificate(intermediateDER)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	leafKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leafTemplate := &x509.Certificate{
+		SerialNumber: big.NewInt(3),
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+	}
+	leafDER, err := x509.CreateCertificate(rand.Reader, leafTemplate, intermediateCert, &leafKey.PublicKey, intermediateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leafCert, err := x509.ParseCertificate(leafDER)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bundle := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: rootDER})
+	bundle = append(bundle, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: intermediateDER})...)
+
+	return bundle, leafCert
+}
+
+func writeTestFulcioTrustBundle(t *testing.T, bundle []byte) string {
+	t.Helper()
+
+	bundlePath := filepath.Join(t.TempDir(), "fulcio.pem")
+	if err := os.WriteFile(bundlePath, bundle, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return bundlePath
+}
@@ -0,0 +1,103 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gitinterface
+
+import (
+	"errors"
+	"io"
+	"sort"
+
+	"github.com/gittuf/gittuf/internal/third_party/go-git"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing/filemode"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing/object"
+)
+
+// SubmoduleChange records a change to a submodule's gitlink entry. For newly
+// added submodules, OldCommitID is the zero hash. For removed submodules,
+// NewCommitID is the zero hash.
+type SubmoduleChange struct {
+	Path        string
+	OldCommitID plumbing.Hash
+	NewCommitID plumbing.Hash
+}
+
+// GetSubmoduleChangesByCommit returns the changes to submodule gitlink entries
+// made by the commit relative to its parent commit. If the commit is a merge
+// commit, i.e., it has more than one parent, no changes are returned, matching
+// GetFilePathsChangedByCommit.
+func GetSubmoduleChangesByCommit(repo *git.Repository, commit *object.Commit) ([]SubmoduleChange, error) {
+	if len(commit.ParentHashes) > 1 {
+		return nil, nil
+	}
+
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, err
+	}
+	gitlinks, err := getGitlinks(tree)
+	if err != nil {
+		return nil, err
+	}
+
+	parentGitlinks := map[string]plumbing.Hash{}
+	if len(commit.ParentHashes) == 1 {
+		parentCommit, err := repo.CommitObject(commit.ParentHashes[0])
+		if err != nil {
+			return nil, err
+		}
+
+		parentTree, err := parentCommit.Tree()
+		if err != nil {
+			return nil, err
+		}
+
+		parentGitlinks, err = getGitlinks(parentTree)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	changes := []SubmoduleChange{}
+	for path, commitID := range gitlinks {
+		if parentCommitID := parentGitlinks[path]; parentCommitID != commitID {
+			changes = append(changes, SubmoduleChange{Path: path, OldCommitID: parentCommitID, NewCommitID: commitID})
+		}
+	}
+	for path, parentCommitID := range parentGitlinks {
+		if _, exists := gitlinks[path]; !exists {
+			changes = append(changes, SubmoduleChange{Path: path, OldCommitID: parentCommitID, NewCommitID: plumbing.ZeroHash})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+
+	return changes, nil
+}
+
+// getGitlinks returns the commit IDs of all the gitlink entries in the tree,
+// keyed by their paths.
+func getGitlinks(tree *object.Tree) (map[string]plumbing.Hash, error) {
+	gitlinks := map[string]plumbing.Hash{}
+
+	walker := object.NewTreeWalker(tree, true, nil)
+	defer walker.Close()
+
+	for {
+		name, entry, err := walker.Next()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, err
+		}
+
+		if entry.Mode == filemode.Submodule {
+			gitlinks[name] = entry.Hash
+		}
+	}
+
+	return gitlinks, nil
+}
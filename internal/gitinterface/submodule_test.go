@@ -0,0 +1,114 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gitinterface
+
+import (
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/third_party/go-git"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing/filemode"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing/object"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/storage/memory"
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetSubmoduleChangesByCommit(t *testing.T) {
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	emptyBlobHash := EmptyBlob()
+	submoduleCommitA := plumbing.NewHash("abcdef1234567890abcdef1234567890abcdef12")
+	submoduleCommitB := plumbing.NewHash("1234567890abcdef1234567890abcdef12345678")
+
+	createCommit := func(t *testing.T, entries []object.TreeEntry, parentID plumbing.Hash) *object.Commit {
+		t.Helper()
+
+		treeHash, err := WriteTree(repo, entries)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		commitID, err := WriteCommit(repo, CreateCommitObject(testGitConfig, treeHash, parentID, "Test commit", testClock))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		commit, err := repo.CommitObject(commitID)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return commit
+	}
+
+	nestedTreeHash, err := WriteTree(repo, []object.TreeEntry{
+		{Name: "nested", Mode: filemode.Submodule, Hash: submoduleCommitA},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Add a file and a submodule
+	firstCommit := createCommit(t, []object.TreeEntry{
+		{Name: "a", Mode: filemode.Regular, Hash: emptyBlobHash},
+		{Name: "sub", Mode: filemode.Submodule, Hash: submoduleCommitA},
+	}, plumbing.ZeroHash)
+
+	// Update the submodule and add a nested submodule
+	secondCommit := createCommit(t, []object.TreeEntry{
+		{Name: "a", Mode: filemode.Regular, Hash: emptyBlobHash},
+		{Name: "dir", Mode: filemode.Dir, Hash: nestedTreeHash},
+		{Name: "sub", Mode: filemode.Submodule, Hash: submoduleCommitB},
+	}, firstCommit.Hash)
+
+	// Remove the top level submodule, leave the nested one unchanged
+	thirdCommit := createCommit(t, []object.TreeEntry{
+		{Name: "a", Mode: filemode.Regular, Hash: emptyBlobHash},
+		{Name: "dir", Mode: filemode.Dir, Hash: nestedTreeHash},
+	}, secondCommit.Hash)
+
+	// Merge commits are not inspected
+	mergeCommit := CreateCommitObject(testGitConfig, EmptyTree(), firstCommit.Hash, "Merge commit", testClock)
+	mergeCommit.ParentHashes = append(mergeCommit.ParentHashes, secondCommit.Hash)
+
+	tests := map[string]struct {
+		commit          *object.Commit
+		expectedChanges []SubmoduleChange
+	}{
+		"submodule added in root commit": {
+			commit: firstCommit,
+			expectedChanges: []SubmoduleChange{
+				{Path: "sub", OldCommitID: plumbing.ZeroHash, NewCommitID: submoduleCommitA},
+			},
+		},
+		"submodule updated and nested submodule added": {
+			commit: secondCommit,
+			expectedChanges: []SubmoduleChange{
+				{Path: "dir/nested", OldCommitID: plumbing.ZeroHash, NewCommitID: submoduleCommitA},
+				{Path: "sub", OldCommitID: submoduleCommitA, NewCommitID: submoduleCommitB},
+			},
+		},
+		"submodule removed": {
+			commit: thirdCommit,
+			expectedChanges: []SubmoduleChange{
+				{Path: "sub", OldCommitID: submoduleCommitB, NewCommitID: plumbing.ZeroHash},
+			},
+		},
+		"merge commit": {
+			commit:          mergeCommit,
+			expectedChanges: nil,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			changes, err := GetSubmoduleChangesByCommit(repo, test.commit)
+			assert.Nil(t, err)
+			assert.Equal(t, test.expectedChanges, changes)
+		})
+	}
+}
@@ -5,16 +5,58 @@ package gitinterface
 import (
 	"context"
 	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
 
+	"github.com/gittuf/gittuf/internal/logging"
 	"github.com/gittuf/gittuf/internal/third_party/go-git"
 	"github.com/gittuf/gittuf/internal/third_party/go-git/config"
 	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing"
 	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing/transport"
+	githttp "github.com/gittuf/gittuf/internal/third_party/go-git/plumbing/transport/http"
 	"github.com/gittuf/gittuf/internal/third_party/go-git/storage/memory"
 	"github.com/go-git/go-billy/v5/memfs"
 )
 
-const DefaultRemoteName = "origin"
+const (
+	DefaultRemoteName = "origin"
+
+	maxSyncRetryDelay = time.Minute
+)
+
+// SyncOptions contains the configurable options for pushing to and fetching
+// from remotes.
+type SyncOptions struct {
+	// RetryAttempts is the maximum number of times a push or fetch is
+	// attempted. Retries are disabled for values less than 2.
+	RetryAttempts int
+
+	// RetryBaseDelay is the delay before the first retry. The delay doubles
+	// for each subsequent retry.
+	RetryBaseDelay time.Duration
+
+	// DumbHTTP indicates fetches use the dumb HTTP protocol.
+	DumbHTTP bool
+}
+
+// SyncOption is used to configure pushes and fetches.
+type SyncOption func(*SyncOptions)
+
+// WithRetry configures pushes and fetches to be retried with exponential
+// backoff when they fail due to transient network or transport errors. The
+// operation is attempted at most attempts times, waiting baseDelay before the
+// first retry. Failures such as authentication errors or rejected updates are
+// never retried.
+func WithRetry(attempts int, baseDelay time.Duration) SyncOption {
+	return func(o *SyncOptions) {
+		o.RetryAttempts = attempts
+		o.RetryBaseDelay = baseDelay
+	}
+}
 
 // PushRefSpec pushes from repo to the specified remote using pre-constructed
 // refspecs. For more information on the Git refspec, please consult:
@@ -22,7 +64,12 @@ const DefaultRemoteName = "origin"
 //
 // All pushes are set to be atomic as the intent of using multiple refs is to
 // sync the RSL.
-func PushRefSpec(ctx context.Context, repo *git.Repository, remoteName string, refs []config.RefSpec) error {
+func PushRefSpec(ctx context.Context, repo *git.Repository, remoteName string, refs []config.RefSpec, opts ...SyncOption) error {
+	options := &SyncOptions{}
+	for _, fn := range opts {
+		fn(options)
+	}
+
 	remote, err := repo.Remote(remoteName)
 	if err != nil {
 		return err
@@ -34,7 +81,11 @@ func PushRefSpec(ctx context.Context, repo *git.Repository, remoteName string, r
 		Atomic:     true,
 	}
 
-	err = remote.PushContext(ctx, pushOpts)
+	logging.FromContext(ctx).DebugContext(ctx, logging.EventPushRefSpecs, "remote", remoteName, "refspecs", refs)
+
+	err = withRetry(ctx, options, func() error {
+		return remote.PushContext(ctx, pushOpts)
+	})
 	if errors.Is(err, git.NoErrAlreadyUpToDate) {
 		return nil
 	}
@@ -46,7 +97,7 @@ func PushRefSpec(ctx context.Context, repo *git.Repository, remoteName string, r
 // https://git-scm.com/book/en/v2/Git-Internals-The-Refspec.
 //
 // The refspecs are constructed to be fast-forward only.
-func Push(ctx context.Context, repo *git.Repository, remoteName string, refs []string) error {
+func Push(ctx context.Context, repo *git.Repository, remoteName string, refs []string, opts ...SyncOption) error {
 	refSpecs := make([]config.RefSpec, 0, len(refs))
 	for _, r := range refs {
 		refSpec, err := RefSpec(repo, r, "", true)
@@ -56,13 +107,49 @@ func Push(ctx context.Context, repo *git.Repository, remoteName string, refs []s
 		refSpecs = append(refSpecs, refSpec)
 	}
 
-	return PushRefSpec(ctx, repo, remoteName, refSpecs)
+	return PushRefSpec(ctx, repo, remoteName, refSpecs, opts...)
+}
+
+// PushToRemotes pushes the specified Git refs to each of the specified remotes
+// using Push. A failure to push to one remote does not abort pushes to the
+// other remotes. The returned map records the result of the push for each
+// remote, with a nil value indicating the push succeeded. If the push to any
+// remote fails, an error aggregating all the failures is also returned.
+func PushToRemotes(ctx context.Context, repo *git.Repository, remoteNames []string, refs []string, opts ...SyncOption) (map[string]error, error) {
+	results := make(map[string]error, len(remoteNames))
+	errs := []error{}
+	for _, remoteName := range remoteNames {
+		err := Push(ctx, repo, remoteName, refs, opts...)
+		results[remoteName] = err
+		if err != nil {
+			errs = append(errs, fmt.Errorf("unable to push to remote '%s': %w", remoteName, err))
+		}
+	}
+
+	return results, errors.Join(errs...)
 }
 
 // FetchRefSpec fetches to the repo from the specified remote using
 // pre-constructed refspecs. For more information on the Git refspec, please
 // consult: https://git-scm.com/book/en/v2/Git-Internals-The-Refspec.
-func FetchRefSpec(ctx context.Context, repo *git.Repository, remoteName string, refs []config.RefSpec) error {
+//
+// If the dumb HTTP protocol is selected using WithDumbHTTP or the remote's
+// config, ErrRefsNotAdvertised is returned if the remote does not have any of
+// the requested refs.
+func FetchRefSpec(ctx context.Context, repo *git.Repository, remoteName string, refs []config.RefSpec, opts ...SyncOption) error {
+	options := &SyncOptions{}
+	for _, fn := range opts {
+		fn(options)
+	}
+
+	if !options.DumbHTTP {
+		dumbHTTP, err := usesDumbHTTP(repo, remoteName)
+		if err != nil {
+			return err
+		}
+		options.DumbHTTP = dumbHTTP
+	}
+
 	remote, err := repo.Remote(remoteName)
 	if err != nil {
 		return err
@@ -73,7 +160,17 @@ func FetchRefSpec(ctx context.Context, repo *git.Repository, remoteName string,
 		RefSpecs:   refs,
 	}
 
-	err = remote.FetchContext(ctx, fetchOpts)
+	logging.FromContext(ctx).DebugContext(ctx, logging.EventFetchRefSpecs, "remote", remoteName, "refspecs", refs, "dumb_http", options.DumbHTTP)
+
+	if options.DumbHTTP {
+		return withRetry(ctx, options, func() error {
+			return fetchRefSpecOverDumbHTTP(ctx, repo, remoteName, refs)
+		})
+	}
+
+	err = withRetry(ctx, options, func() error {
+		return remote.FetchContext(ctx, fetchOpts)
+	})
 	if errors.Is(err, transport.ErrEmptyRemoteRepository) || errors.Is(err, git.NoErrAlreadyUpToDate) {
 		return nil
 	}
@@ -87,7 +184,7 @@ func FetchRefSpec(ctx context.Context, repo *git.Repository, remoteName string,
 // The fastForwardOnly flag controls if the constructed refspec allows
 // non-fast-forward fetches. The target of the refspec is the same as the
 // requested ref. Also, the remote tracker for the ref is also always updated.
-func Fetch(ctx context.Context, repo *git.Repository, remoteName string, refs []string, fastForwardOnly bool) error {
+func Fetch(ctx context.Context, repo *git.Repository, remoteName string, refs []string, fastForwardOnly bool, opts ...SyncOption) error {
 	refSpecs := make([]config.RefSpec, 0, len(refs))
 	for _, r := range refs {
 		// Add the remote tracker destination
@@ -105,12 +202,48 @@ func Fetch(ctx context.Context, repo *git.Repository, remoteName string, refs []
 		refSpecs = append(refSpecs, refSpec)
 	}
 
-	return FetchRefSpec(ctx, repo, remoteName, refSpecs)
+	return FetchRefSpec(ctx, repo, remoteName, refSpecs, opts...)
+}
+
+// FetchFromRemotes fetches the specified Git refs from each of the specified
+// remotes using Fetch. A failure to fetch from one remote does not abort
+// fetches from the other remotes. The returned map records the result of the
+// fetch for each remote, with a nil value indicating the fetch succeeded. If the
+// fetch from any remote fails, an error aggregating all the failures is also
+// returned.
+//
+// Note that as Fetch updates the local refs in addition to each remote's
+// tracker refs, remotes that have diverged from one another will result in
+// failures when fastForwardOnly is set.
+func FetchFromRemotes(ctx context.Context, repo *git.Repository, remoteNames []string, refs []string, fastForwardOnly bool, opts ...SyncOption) (map[string]error, error) {
+	results := make(map[string]error, len(remoteNames))
+	errs := []error{}
+	for _, remoteName := range remoteNames {
+		err := Fetch(ctx, repo, remoteName, refs, fastForwardOnly, opts...)
+		results[remoteName] = err
+		if err != nil {
+			errs = append(errs, fmt.Errorf("unable to fetch from remote '%s': %w", remoteName, err))
+		}
+	}
+
+	return results, errors.Join(errs...)
 }
 
 // CloneAndFetch clones a repository using the specified URL and additionally
-// fetches the specified refs.
-func CloneAndFetch(ctx context.Context, remoteURL, dir, initialBranch string, refs []string) (*git.Repository, error) {
+// fetches the specified refs. A partial clone can be requested using
+// WithPartialClone. If the repository is cloned but the specified refs cannot
+// be fetched, the cloned repository is returned along with an error wrapping
+// ErrFetchingRefsAfterClone.
+func CloneAndFetch(ctx context.Context, remoteURL, dir, initialBranch string, refs []string, opts ...CloneOption) (*git.Repository, error) {
+	options := &CloneOptions{}
+	for _, fn := range opts {
+		fn(options)
+	}
+
+	if len(options.Filter) > 0 {
+		return partialCloneAndFetch(ctx, remoteURL, dir, initialBranch, refs, options.Filter)
+	}
+
 	repo, err := git.PlainCloneContext(ctx, dir, false, createCloneOptions(remoteURL, initialBranch))
 	if err != nil {
 		return nil, err
@@ -143,9 +276,67 @@ func fetchRefs(ctx context.Context, repo *git.Repository, refs []string, fastFor
 	if len(refs) > 0 {
 		err := Fetch(ctx, repo, DefaultRemoteName, refs, fastForwardOnly)
 		if err != nil {
-			return nil, err
+			return repo, errors.Join(ErrFetchingRefsAfterClone, err)
 		}
 	}
 
 	return repo, nil
 }
+
+// withRetry invokes fn, retrying it with exponential backoff as configured in
+// options for as long as it fails with a retryable error. Waiting for a retry
+// is abandoned if ctx is cancelled.
+func withRetry(ctx context.Context, options *SyncOptions, fn func() error) error {
+	delay := options.RetryBaseDelay
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || attempt >= options.RetryAttempts || !isRetryableSyncError(err) {
+			return err
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return errors.Join(err, ctx.Err())
+		case <-timer.C:
+		}
+
+		delay *= 2
+		if delay > maxSyncRetryDelay {
+			delay = maxSyncRetryDelay
+		}
+	}
+}
+
+// isRetryableSyncError returns true if the error returned by a push or fetch
+// is due to a transient network or transport failure. Errors such as failed
+// authentication, a missing repository, or rejected updates are not retryable.
+func isRetryableSyncError(err error) bool {
+	// Context errors satisfy net.Error, but must not be retried
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+
+	if errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.EPIPE) {
+		return true
+	}
+
+	// The HTTP transport wraps unexpected status codes, which can't be
+	// unwrapped using errors.As
+	var unexpectedErr *plumbing.UnexpectedError
+	if errors.As(err, &unexpectedErr) {
+		var httpErr *githttp.Err
+		if errors.As(unexpectedErr.Err, &httpErr) {
+			statusCode := httpErr.StatusCode()
+			return statusCode >= http.StatusInternalServerError || statusCode == http.StatusTooManyRequests || statusCode == http.StatusRequestTimeout
+		}
+	}
+
+	return false
+}
@@ -5,12 +5,19 @@ package gitinterface
 import (
 	"context"
 	"fmt"
+	"net"
+	"path/filepath"
+	"syscall"
 	"testing"
+	"time"
 
 	"github.com/gittuf/gittuf/internal/third_party/go-git"
 	"github.com/gittuf/gittuf/internal/third_party/go-git/config"
 	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing"
 	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing/object"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing/transport"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing/transport/client"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing/transport/file"
 	"github.com/gittuf/gittuf/internal/third_party/go-git/storage/memory"
 	"github.com/go-git/go-billy/v5/memfs"
 	"github.com/stretchr/testify/assert"
@@ -261,6 +268,71 @@ func TestPush(t *testing.T) {
 	})
 }
 
+func TestPushToRemotes(t *testing.T) {
+	refName := "refs/heads/main"
+	refNameTyped := plumbing.ReferenceName(refName)
+
+	// The local repo can be in-memory
+	repoLocal, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Create tmp dirs for remote repos so we have URLs for them
+	remoteRepos := map[string]*git.Repository{}
+	for _, remoteName := range []string{"origin", "backup"} {
+		tmpDir := t.TempDir()
+
+		repoRemote, err := git.PlainInit(tmpDir, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		remoteRepos[remoteName] = repoRemote
+
+		_, err = repoLocal.CreateRemote(&config.RemoteConfig{
+			Name: remoteName,
+			URLs: []string{tmpDir},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// This remote does not exist on disk, so pushes to it must fail
+	_, err = repoLocal.CreateRemote(&config.RemoteConfig{
+		Name: "broken",
+		URLs: []string{filepath.Join(t.TempDir(), "does-not-exist")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	emptyTreeHash, err := WriteTree(repoLocal, []object.TreeEntry{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	localCommitID, err := Commit(repoLocal, emptyTreeHash, refName, "Test commit", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := PushToRemotes(context.Background(), repoLocal, []string{"broken", "origin", "backup"}, []string{refName})
+	assert.NotNil(t, err)
+	assert.NotNil(t, results["broken"])
+	assert.Nil(t, results["origin"])
+	assert.Nil(t, results["backup"])
+
+	// The failure for the first remote must not have prevented pushes to the
+	// others
+	for _, repoRemote := range remoteRepos {
+		refRemote, err := repoRemote.Reference(refNameTyped, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, localCommitID, refRemote.Hash())
+	}
+}
+
 func TestFetchRefSpec(t *testing.T) {
 	remoteName := "origin"
 	refName := "refs/heads/main"
@@ -526,6 +598,171 @@ func TestFetch(t *testing.T) {
 	})
 }
 
+func TestFetchFromRemotes(t *testing.T) {
+	remoteName := "origin"
+	refName := "refs/heads/main"
+	refNameTyped := plumbing.ReferenceName(refName)
+
+	// The local repo can be in-memory
+	repoLocal, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := repoLocal.Storer.SetReference(plumbing.NewSymbolicReference(plumbing.HEAD, refNameTyped)); err != nil {
+		t.Fatal(err)
+	}
+
+	// Create tmp dir for remote repo so we have a URL for it
+	tmpDir := t.TempDir()
+
+	repoRemote, err := git.PlainInit(tmpDir, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = repoLocal.CreateRemote(&config.RemoteConfig{
+		Name: remoteName,
+		URLs: []string{tmpDir},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// This remote does not exist on disk, so fetches from it must fail
+	_, err = repoLocal.CreateRemote(&config.RemoteConfig{
+		Name: "broken",
+		URLs: []string{filepath.Join(t.TempDir(), "does-not-exist")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	emptyTreeHash, err := WriteTree(repoRemote, []object.TreeEntry{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	remoteCommitID, err := Commit(repoRemote, emptyTreeHash, refName, "Test commit", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := FetchFromRemotes(context.Background(), repoLocal, []string{"broken", remoteName}, []string{refName}, true)
+	assert.NotNil(t, err)
+	assert.NotNil(t, results["broken"])
+	assert.Nil(t, results[remoteName])
+
+	assertLocalRefAndRemoteTrackerRef(t, repoLocal, refName, remoteName, remoteCommitID)
+}
+
+func TestSyncWithRetry(t *testing.T) {
+	remoteName := "origin"
+	refName := "refs/heads/main"
+	refSpecs := []config.RefSpec{config.RefSpec(fmt.Sprintf("%s:%s", refName, refName))}
+	networkErr := &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}
+
+	// setupRepos returns a local repository with a commit on refName and a
+	// remote named remoteName that is reached using the flaky transport
+	setupRepos := func(t *testing.T, flaky *flakyTransport) *git.Repository {
+		t.Helper()
+
+		client.InstallProtocol(flakyProtocol, flaky)
+		t.Cleanup(func() {
+			client.InstallProtocol(flakyProtocol, nil)
+		})
+
+		tmpDir := t.TempDir()
+		if _, err := git.PlainInit(tmpDir, true); err != nil {
+			t.Fatal(err)
+		}
+
+		repoLocal, err := git.Init(memory.NewStorage(), memfs.New())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := repoLocal.CreateRemote(&config.RemoteConfig{
+			Name: remoteName,
+			URLs: []string{fmt.Sprintf("%s://%s", flakyProtocol, tmpDir)},
+		}); err != nil {
+			t.Fatal(err)
+		}
+
+		emptyTreeHash, err := WriteTree(repoLocal, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Commit(repoLocal, emptyTreeHash, refName, "Test commit", false); err != nil {
+			t.Fatal(err)
+		}
+
+		return repoLocal
+	}
+
+	t.Run("push succeeds after transient failures", func(t *testing.T) {
+		flaky := &flakyTransport{failures: 2, err: networkErr}
+		repoLocal := setupRepos(t, flaky)
+
+		err := PushRefSpec(context.Background(), repoLocal, remoteName, refSpecs, WithRetry(3, time.Millisecond))
+		assert.Nil(t, err)
+		assert.Equal(t, 3, flaky.attempts)
+	})
+
+	t.Run("fetch succeeds after transient failures", func(t *testing.T) {
+		flaky := &flakyTransport{failures: 0, err: networkErr}
+		repoLocal := setupRepos(t, flaky)
+
+		if err := PushRefSpec(context.Background(), repoLocal, remoteName, refSpecs); err != nil {
+			t.Fatal(err)
+		}
+
+		flaky.attempts = 0
+		flaky.failures = 1
+
+		err := FetchRefSpec(context.Background(), repoLocal, remoteName, refSpecs, WithRetry(2, time.Millisecond))
+		assert.Nil(t, err)
+		assert.Equal(t, 2, flaky.attempts)
+	})
+
+	t.Run("retries exhausted", func(t *testing.T) {
+		flaky := &flakyTransport{failures: 5, err: networkErr}
+		repoLocal := setupRepos(t, flaky)
+
+		err := PushRefSpec(context.Background(), repoLocal, remoteName, refSpecs, WithRetry(2, time.Millisecond))
+		assert.ErrorIs(t, err, syscall.ECONNREFUSED)
+		assert.Equal(t, 2, flaky.attempts)
+	})
+
+	t.Run("no retries by default", func(t *testing.T) {
+		flaky := &flakyTransport{failures: 1, err: networkErr}
+		repoLocal := setupRepos(t, flaky)
+
+		err := PushRefSpec(context.Background(), repoLocal, remoteName, refSpecs)
+		assert.ErrorIs(t, err, syscall.ECONNREFUSED)
+		assert.Equal(t, 1, flaky.attempts)
+	})
+
+	t.Run("authentication errors are not retried", func(t *testing.T) {
+		flaky := &flakyTransport{failures: 1, err: transport.ErrAuthenticationRequired}
+		repoLocal := setupRepos(t, flaky)
+
+		err := PushRefSpec(context.Background(), repoLocal, remoteName, refSpecs, WithRetry(3, time.Millisecond))
+		assert.ErrorIs(t, err, transport.ErrAuthenticationRequired)
+		assert.Equal(t, 1, flaky.attempts)
+	})
+
+	t.Run("cancelled context stops retries", func(t *testing.T) {
+		flaky := &flakyTransport{failures: 5, err: networkErr}
+		repoLocal := setupRepos(t, flaky)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		flaky.onAttempt = cancel
+
+		err := PushRefSpec(ctx, repoLocal, remoteName, refSpecs, WithRetry(5, time.Hour))
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, 1, flaky.attempts)
+	})
+}
+
 func TestCloneAndFetch(t *testing.T) {
 	refName := "refs/heads/main"
 	anotherRefName := "refs/heads/feature"
@@ -793,3 +1030,48 @@ func assertLocalRefAndRemoteTrackerRef(t *testing.T, repo *git.Repository, refNa
 	}
 	assert.Equal(t, expectedCommitID, localRemoteTrackerRef.Hash())
 }
+
+const flakyProtocol = "flaky"
+
+// flakyTransport wraps the file transport, failing the first few sessions with
+// the specified error.
+type flakyTransport struct {
+	failures  int
+	attempts  int
+	err       error
+	onAttempt func()
+}
+
+func (f *flakyTransport) NewUploadPackSession(endpoint *transport.Endpoint, auth transport.AuthMethod) (transport.UploadPackSession, error) {
+	if err := f.attempt(); err != nil {
+		return nil, err
+	}
+
+	return file.DefaultClient.NewUploadPackSession(fileEndpoint(endpoint), auth)
+}
+
+func (f *flakyTransport) NewReceivePackSession(endpoint *transport.Endpoint, auth transport.AuthMethod) (transport.ReceivePackSession, error) {
+	if err := f.attempt(); err != nil {
+		return nil, err
+	}
+
+	return file.DefaultClient.NewReceivePackSession(fileEndpoint(endpoint), auth)
+}
+
+func (f *flakyTransport) attempt() error {
+	f.attempts++
+	if f.onAttempt != nil {
+		f.onAttempt()
+	}
+
+	if f.attempts <= f.failures {
+		return f.err
+	}
+	return nil
+}
+
+func fileEndpoint(endpoint *transport.Endpoint) *transport.Endpoint {
+	fileEndpoint := *endpoint
+	fileEndpoint.Protocol = "file"
+	return &fileEndpoint
+}
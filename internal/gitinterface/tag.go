@@ -104,15 +104,32 @@ func CreateTagObject(gitConfig *config.Config, targetObj object.Object, name, me
 }
 
 // VerifyTagSignature is used to verify a cryptographic signature associated
-// with tag using TUF public keys.
-func VerifyTagSignature(ctx context.Context, tag *object.Tag, key *tuf.Key) error {
+// with tag using TUF public keys. The options can be used to verify Sigstore
+// signatures issued by a private Sigstore deployment. If the key is restricted
+// to a validity window using WithKeyValidityWindow and the trusted time set
+// using WithTrustedTime, or the tag's tagger time if no trusted time is set, is
+// outside it, ErrOutsideKeyValidityWindow is returned, which also wraps
+// ErrIncorrectVerificationKey.
+func VerifyTagSignature(ctx context.Context, tag *object.Tag, key *tuf.Key, opts ...VerificationOption) error {
+	if err := verifyTagSignature(ctx, tag, key, opts...); err != nil {
+		return err
+	}
+
+	return checkKeyValidityWindow(tag.Tagger.When, opts...)
+}
+
+// verifyTagSignature verifies the tag's signature using the key based on the
+// key's type.
+func verifyTagSignature(ctx context.Context, tag *object.Tag, key *tuf.Key, opts ...VerificationOption) error {
 	switch key.KeyType {
 	case signerverifier.GPGKeyType:
-		if _, err := tag.Verify(key.KeyVal.Public); err != nil {
-			return ErrIncorrectVerificationKey
+		tagContents, err := getTagBytesWithoutSignature(tag)
+		if err != nil {
+			return err
 		}
 
-		return nil
+		_, err = verifyGPGSignature(key, tagContents, tag.PGPSignature, opts...)
+		return err
 	case signerverifier.FulcioKeyType:
 		tagContents, err := getTagBytesWithoutSignature(tag)
 		if err != nil {
@@ -120,7 +137,24 @@ func VerifyTagSignature(ctx context.Context, tag *object.Tag, key *tuf.Key) erro
 		}
 		tagSignature := []byte(tag.PGPSignature)
 
-		return verifyGitsignSignature(ctx, key, tagContents, tagSignature)
+		_, err = verifyGitsignSignature(ctx, key, tagContents, tagSignature, opts...)
+		return err
+	case signerverifier.MinisignKeyType:
+		tagContents, err := getTagBytesWithoutSignature(tag)
+		if err != nil {
+			return err
+		}
+
+		return verifyMinisignSignature(key, tagContents, tag.PGPSignature)
+	}
+
+	if signerverifier.IsRegisteredKeyType(key.KeyType) {
+		tagContents, err := getTagBytesWithoutSignature(tag)
+		if err != nil {
+			return err
+		}
+
+		return verifyRegisteredKeyTypeSignature(ctx, key, tagContents, []byte(tag.PGPSignature))
 	}
 
 	return ErrUnknownSigningMethod
@@ -9,6 +9,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/ProtonMail/go-crypto/openpgp"
 	"github.com/gittuf/gittuf/internal/signerverifier"
@@ -104,6 +105,17 @@ func TestVerifyTagSignature(t *testing.T) {
 		err = VerifyTagSignature(context.Background(), gpgSignedTag, fulcioKey)
 		assert.ErrorIs(t, err, ErrIncorrectVerificationKey)
 	})
+
+	t.Run("gpg signed tag and key validity window", func(t *testing.T) {
+		trustedTime := testClock.Now()
+
+		err = VerifyTagSignature(context.Background(), gpgSignedTag, gpgKey, WithKeyValidityWindow(trustedTime.Add(-time.Hour), trustedTime.Add(time.Hour)), WithTrustedTime(trustedTime))
+		assert.Nil(t, err)
+
+		err = VerifyTagSignature(context.Background(), gpgSignedTag, gpgKey, WithKeyValidityWindow(time.Time{}, trustedTime.Add(-time.Hour)), WithTrustedTime(trustedTime))
+		assert.ErrorIs(t, err, ErrIncorrectVerificationKey)
+		assert.ErrorIs(t, err, ErrOutsideKeyValidityWindow)
+	})
 }
 
 func createTestSignedTag(t *testing.T) *object.Tag {
@@ -3,6 +3,8 @@
 package gitinterface
 
 import (
+	"context"
+	"errors"
 	"sort"
 
 	"github.com/gittuf/gittuf/internal/third_party/go-git"
@@ -11,6 +13,8 @@ import (
 	"github.com/gittuf/gittuf/internal/third_party/go-git/storage/memory"
 )
 
+var ErrTreeDoesNotHavePath = errors.New("tree does not have requested path")
+
 // WriteTree creates a Git tree with the specified entries. It sorts the entries
 // prior to creating the tree.
 func WriteTree(repo *git.Repository, entries []object.TreeEntry) (plumbing.Hash, error) {
@@ -37,3 +41,35 @@ func EmptyTree() plumbing.Hash {
 
 	return obj.Hash()
 }
+
+// ReadTree returns the tree with the specified ID. If ctx is done before the
+// tree is read in full, the context's error is returned. The returned tree
+// reads the objects it refers to without regard to ctx.
+func ReadTree(ctx context.Context, repo *git.Repository, treeID plumbing.Hash) (*object.Tree, error) {
+	obj, err := (&contextStorer{EncodedObjectStorer: repo.Storer, ctx: ctx}).EncodedObject(plumbing.TreeObject, treeID)
+	if err != nil {
+		return nil, err
+	}
+
+	return object.DecodeTree(repo.Storer, obj)
+}
+
+// GetPathIDInTree returns the ID of the Git object at the specified path in the
+// tree. If the path does not exist in the tree, ErrTreeDoesNotHavePath is
+// returned.
+func GetPathIDInTree(repo *git.Repository, treeID plumbing.Hash, path string) (plumbing.Hash, error) {
+	tree, err := repo.TreeObject(treeID)
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+
+	entry, err := tree.FindEntry(path)
+	if err != nil {
+		if errors.Is(err, object.ErrEntryNotFound) || errors.Is(err, object.ErrDirectoryNotFound) {
+			return plumbing.ZeroHash, ErrTreeDoesNotHavePath
+		}
+		return plumbing.ZeroHash, err
+	}
+
+	return entry.Hash, nil
+}
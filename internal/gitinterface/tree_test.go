@@ -65,3 +65,56 @@ func TestEmptyTree(t *testing.T) {
 	// $ git hash-object -t tree --stdin < /dev/null
 	assert.Equal(t, "4b825dc642cb6eb9a060e54bf8d69288fbee4904", hash.String())
 }
+
+func TestGetPathIDInTree(t *testing.T) {
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	blobID, err := WriteBlob(repo, []byte("test file"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	subTreeID, err := WriteTree(repo, []object.TreeEntry{{Name: "file", Mode: filemode.Regular, Hash: blobID}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	treeID, err := WriteTree(repo, []object.TreeEntry{
+		{Name: "file", Mode: filemode.Regular, Hash: blobID},
+		{Name: "dir", Mode: filemode.Dir, Hash: subTreeID},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("file at root", func(t *testing.T) {
+		pathID, err := GetPathIDInTree(repo, treeID, "file")
+		assert.Nil(t, err)
+		assert.Equal(t, blobID, pathID)
+	})
+
+	t.Run("file in subdirectory", func(t *testing.T) {
+		pathID, err := GetPathIDInTree(repo, treeID, "dir/file")
+		assert.Nil(t, err)
+		assert.Equal(t, blobID, pathID)
+	})
+
+	t.Run("subdirectory", func(t *testing.T) {
+		pathID, err := GetPathIDInTree(repo, treeID, "dir")
+		assert.Nil(t, err)
+		assert.Equal(t, subTreeID, pathID)
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := GetPathIDInTree(repo, treeID, "missing")
+		assert.ErrorIs(t, err, ErrTreeDoesNotHavePath)
+	})
+
+	t.Run("missing directory", func(t *testing.T) {
+		_, err := GetPathIDInTree(repo, treeID, "missing/file")
+		assert.ErrorIs(t, err, ErrTreeDoesNotHavePath)
+	})
+}
@@ -3,14 +3,17 @@
 package gitinterface
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"io"
 	"path"
 	"strings"
 
 	"github.com/gittuf/gittuf/internal/third_party/go-git"
 	"github.com/gittuf/gittuf/internal/third_party/go-git/config"
 	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing/storer"
 	"github.com/jonboulle/clockwork"
 )
 
@@ -175,3 +178,64 @@ func RemoteRef(refName, remoteName string) string {
 
 	return remotePath
 }
+
+// contextStorer wraps a repository's object store so that reads honor a
+// context. As go-git's storers do not accept a context, a lookup that is in
+// progress can't be interrupted. Instead, ctx is checked before each object is
+// looked up and before each read of an object's contents, so a slow read is
+// aborted between chunks once ctx is done. Reads are never abandoned while they
+// run, as the storer is not safe for concurrent use and the caller may continue
+// to use it.
+type contextStorer struct {
+	storer.EncodedObjectStorer
+	ctx context.Context
+}
+
+// EncodedObject returns the object with the specified type and ID. The
+// returned object's contents are read subject to the storer's context.
+func (s *contextStorer) EncodedObject(objectType plumbing.ObjectType, objectID plumbing.Hash) (plumbing.EncodedObject, error) {
+	if err := s.ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	obj, err := s.EncodedObjectStorer.EncodedObject(objectType, objectID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &contextObject{EncodedObject: obj, ctx: s.ctx}, nil
+}
+
+// contextObject is an object whose contents are read subject to ctx.
+type contextObject struct {
+	plumbing.EncodedObject
+	ctx context.Context
+}
+
+func (o *contextObject) Reader() (io.ReadCloser, error) {
+	if err := o.ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	reader, err := o.EncodedObject.Reader()
+	if err != nil {
+		return nil, err
+	}
+
+	return &contextReader{ReadCloser: reader, ctx: o.ctx}, nil
+}
+
+// contextReader returns the context's error instead of reading once ctx is
+// done.
+type contextReader struct {
+	io.ReadCloser
+	ctx context.Context
+}
+
+func (r *contextReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	return r.ReadCloser.Read(p)
+}
@@ -0,0 +1,61 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package logging lets callers observe gittuf's verification and sync
+// operations. Structured events are emitted to a *slog.Logger carried by the
+// context passed into those operations. When no logger is set, events are
+// discarded.
+package logging
+
+import (
+	"context"
+	"log/slog"
+)
+
+// Messages of the events emitted by gittuf. Each event also records attributes
+// describing it, such as the rule, key, or remote involved.
+const (
+	EventVerifyRef          = "verifying ref"
+	EventMetadataVerified   = "metadata verified"
+	EventDelegationVisited  = "delegation visited"
+	EventKeyMatched         = "key matched"
+	EventThresholdProgress  = "threshold progress"
+	EventFetchRefSpecs      = "fetching refspecs"
+	EventPushRefSpecs       = "pushing refspecs"
+	EventRSLEntryReconciled = "RSL entry reconciled"
+	EventBreakGlassUsed     = "break-glass access used"
+	EventRefNotProtected    = "ref not protected by policy"
+)
+
+type loggerKey struct{}
+
+var noopLogger = slog.New(discardHandler{})
+
+// NewContext returns a copy of ctx that carries logger. gittuf operations
+// invoked with the returned context emit their events to logger.
+func NewContext(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// FromContext returns the logger carried by ctx. If ctx does not carry a
+// logger, a logger that discards all events is returned.
+func FromContext(ctx context.Context) *slog.Logger {
+	if ctx == nil {
+		return noopLogger
+	}
+
+	logger, ok := ctx.Value(loggerKey{}).(*slog.Logger)
+	if !ok || logger == nil {
+		return noopLogger
+	}
+
+	return logger
+}
+
+// discardHandler is a slog.Handler that is never enabled, so events are
+// dropped before their attributes are evaluated.
+type discardHandler struct{}
+
+func (discardHandler) Enabled(context.Context, slog.Level) bool  { return false }
+func (discardHandler) Handle(context.Context, slog.Record) error { return nil }
+func (d discardHandler) WithAttrs([]slog.Attr) slog.Handler      { return d }
+func (d discardHandler) WithGroup(string) slog.Handler           { return d }
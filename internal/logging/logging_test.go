@@ -0,0 +1,36 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package logging
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromContext(t *testing.T) {
+	t.Run("no logger set", func(t *testing.T) {
+		ctx := context.Background()
+
+		logger := FromContext(ctx)
+		assert.NotNil(t, logger)
+		assert.False(t, logger.Enabled(ctx, slog.LevelError))
+
+		// Emitting events must not fail
+		logger.DebugContext(ctx, EventKeyMatched, "key_id", "test")
+	})
+
+	t.Run("logger set", func(t *testing.T) {
+		buffer := &bytes.Buffer{}
+		logger := slog.New(slog.NewTextHandler(buffer, &slog.HandlerOptions{Level: slog.LevelDebug}))
+		ctx := NewContext(context.Background(), logger)
+
+		assert.Equal(t, logger, FromContext(ctx))
+
+		FromContext(ctx).DebugContext(ctx, EventKeyMatched, "key_id", "test")
+		assert.Contains(t, buffer.String(), `msg="key matched" key_id=test`)
+	})
+}
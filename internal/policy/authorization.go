@@ -0,0 +1,158 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/gittuf/gittuf/internal/tuf"
+)
+
+// AuthorizationExpression describes the combinations of keys that are trusted
+// to sign for a path. An expression for a rule is satisfied by signatures from
+// Threshold of the rule's KeyIDs, or by satisfying any of the expressions in
+// AnyOf, which record the rules the rule delegates to that also match the path.
+// The expression returned for a path does not correspond to a rule, and is
+// satisfied by satisfying any of the expressions in AnyOf. The expression for a
+// rule that has expired has no keys and cannot be satisfied.
+type AuthorizationExpression struct {
+	RuleName  string                     `json:"rule_name,omitempty"`
+	Threshold int                        `json:"threshold,omitempty"`
+	KeyIDs    []string                   `json:"key_ids,omitempty"`
+	Expired   bool                       `json:"expired,omitempty"`
+	AnyOf     []*AuthorizationExpression `json:"any_of,omitempty"`
+}
+
+// String returns a human readable form of the expression, such as
+// "2 of {A, B, C} OR 1 of {D}".
+func (a *AuthorizationExpression) String() string {
+	terms := []string{}
+	if len(a.RuleName) > 0 {
+		if a.Expired {
+			terms = append(terms, fmt.Sprintf("expired rule '%s'", a.RuleName))
+		} else {
+			terms = append(terms, fmt.Sprintf("%d of {%s}", a.Threshold, strings.Join(a.KeyIDs, ", ")))
+		}
+	}
+	for _, expression := range a.AnyOf {
+		term := expression.String()
+		if len(expression.AnyOf) > 0 {
+			term = fmt.Sprintf("(%s)", term)
+		}
+		terms = append(terms, term)
+	}
+
+	return strings.Join(terms, " OR ")
+}
+
+// FindAuthorizationExpressionForPath traverses the policy to identify the
+// rules that protect the path, and returns the combinations of keys that are
+// trusted to sign for it. Unlike FindPublicKeysForPath, which returns a flat
+// list of keys, the returned expression records each rule's threshold and the
+// rules it delegates to, so that the structure of the policy for the path can
+// be explained. The rules are traversed in the same order as
+// FindPublicKeysForPath, so terminating rules shadow the rules after them, and
+// only the rules of the top level policy that owns the path are considered. If
+// no rules protect the path, nil is returned. If the path matches a deny rule,
+// ErrPathDenied is returned.
+func (s *State) FindAuthorizationExpressionForPath(ctx context.Context, path string) (*AuthorizationExpression, error) {
+	if err := s.Verify(ctx); err != nil {
+		return nil, err
+	}
+
+	topLevelRoleNames, err := s.TopLevelTargetsRoleNames()
+	if err != nil {
+		return nil, err
+	}
+
+	// Only the top level policy that owns the path is considered, see
+	// findRulesForPath
+	for _, roleName := range topLevelRoleNames {
+		targetsMetadata, err := s.GetTargetsMetadata(roleName)
+		if err != nil {
+			return nil, err
+		}
+
+		expressions, _, err := s.findAuthorizationExpressions(path, targetsMetadata.Delegations.Roles)
+		if err != nil {
+			return nil, err
+		}
+		if len(expressions) > 0 {
+			return &AuthorizationExpression{AnyOf: expressions}, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// findAuthorizationExpressions returns the expressions for the delegations that
+// match the path, recursing into the metadata of each delegated role. The last
+// delegation, the allow rule, is skipped. The returned bool indicates that a
+// terminating delegation was encountered and that no further delegations must
+// be considered.
+func (s *State) findAuthorizationExpressions(path string, delegations []tuf.Delegation) ([]*AuthorizationExpression, bool, error) {
+	expressions := []*AuthorizationExpression{}
+	if len(delegations) <= 1 {
+		return expressions, false, nil
+	}
+
+	for _, delegation := range delegations[:len(delegations)-1] {
+		if !delegationMatches(delegation, path, "") {
+			continue
+		}
+
+		if delegation.Deny {
+			return nil, false, fmt.Errorf("%w: rule '%s' matches '%s'", ErrPathDenied, delegation.Name, path)
+		}
+
+		// An expired rule still protects the path but authorizes no keys, so
+		// its expression cannot be satisfied
+		if delegation.IsExpired(s.now()) {
+			expressions = append(expressions, &AuthorizationExpression{
+				RuleName:  delegation.Name,
+				Threshold: delegation.Threshold,
+				Expired:   true,
+			})
+			if delegation.Terminating {
+				return expressions, true, nil
+			}
+			continue
+		}
+
+		expression := &AuthorizationExpression{
+			RuleName:  delegation.Name,
+			Threshold: delegation.Threshold,
+			KeyIDs:    delegation.KeyIDs,
+		}
+		expressions = append(expressions, expression)
+
+		if !s.HasTargetsRole(delegation.Name) {
+			continue
+		}
+
+		delegatedMetadata, err := s.GetTargetsMetadata(delegation.Name)
+		if err != nil {
+			return nil, false, err
+		}
+
+		terminated := false
+		if delegatedMetadata.Delegations != nil {
+			var delegatedExpressions []*AuthorizationExpression
+			delegatedExpressions, terminated, err = s.findAuthorizationExpressions(path, delegatedMetadata.Delegations.Roles)
+			if err != nil {
+				return nil, false, err
+			}
+			if len(delegatedExpressions) > 0 {
+				expression.AnyOf = delegatedExpressions
+			}
+		}
+
+		if terminated || delegation.Terminating {
+			return expressions, true, nil
+		}
+	}
+
+	return expressions, false, nil
+}
@@ -0,0 +1,206 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/signerverifier"
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	"github.com/gittuf/gittuf/internal/signerverifier/gpg"
+	"github.com/gittuf/gittuf/internal/tuf"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStateFindAuthorizationExpressionForPath(t *testing.T) {
+	rootKey, err := tuf.LoadKeyFromBytes(rootPubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gpgKey, err := gpg.LoadGPGKeyFromBytes(gpgPubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	targets1Key := loadTestKey(t, "targets-1.pub")
+	targets2Key := loadTestKey(t, "targets-2.pub")
+
+	createState := func(t *testing.T, terminating bool) *State {
+		t.Helper()
+
+		state := createTestStateWithPolicy(t)
+
+		// Changes to src/ require two of three keys, or signatures meeting the
+		// requirements of the rules protect-src delegates to
+		targetsMetadata, err := state.GetTargetsMetadata(TargetsRoleName)
+		if err != nil {
+			t.Fatal(err)
+		}
+		targetsMetadata.Delegations.Roles = targetsMetadata.Delegations.Roles[len(targetsMetadata.Delegations.Roles)-1:]
+		targetsMetadata, err = AddOrUpdateDelegation(targetsMetadata, "protect-src", []*tuf.Key{targets1Key, targets2Key, rootKey}, []string{"file:src/*"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		targetsMetadata, err = SetRuleThreshold(targetsMetadata, "protect-src", 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		targetsMetadata, err = AddOrUpdateDelegation(targetsMetadata, "protect-all", []*tuf.Key{gpgKey}, []string{"file:*", "file:*/*"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		targetsMetadata, err = AddOrUpdateDenyRule(targetsMetadata, "deny-secrets", []string{"file:secrets/*"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if terminating {
+			for i := range targetsMetadata.Delegations.Roles {
+				if targetsMetadata.Delegations.Roles[i].Name == "protect-src" {
+					targetsMetadata.Delegations.Roles[i].Terminating = true
+				}
+			}
+		}
+		state.TargetsEnvelope = signTestEnvelope(t, targetsMetadata, rootKeyBytes)
+
+		delegatedMetadata := InitializeTargetsMetadata()
+		delegatedMetadata, err = AddOrUpdateDelegation(delegatedMetadata, "protect-src-lib", []*tuf.Key{gpgKey, targets1Key}, []string{"file:src/lib*"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		delegatedMetadata, err = SetRuleThreshold(delegatedMetadata, "protect-src-lib", 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		state.DelegationEnvelopes = map[string]*sslibdsse.Envelope{
+			"protect-src": signTestEnvelope(t, delegatedMetadata, loadTestKeyBytes(t, "targets-1"), loadTestKeyBytes(t, "targets-2")),
+		}
+
+		return state
+	}
+
+	protectAll := &AuthorizationExpression{RuleName: "protect-all", Threshold: 1, KeyIDs: []string{gpgKey.KeyID}}
+
+	t.Run("nested thresholds", func(t *testing.T) {
+		state := createState(t, false)
+
+		expression, err := state.FindAuthorizationExpressionForPath(testCtx, "file:src/lib.go")
+		assert.Nil(t, err)
+		assert.Equal(t, &AuthorizationExpression{
+			AnyOf: []*AuthorizationExpression{
+				{
+					RuleName:  "protect-src",
+					Threshold: 2,
+					KeyIDs:    []string{targets1Key.KeyID, targets2Key.KeyID, rootKey.KeyID},
+					AnyOf: []*AuthorizationExpression{
+						{RuleName: "protect-src-lib", Threshold: 2, KeyIDs: []string{gpgKey.KeyID, targets1Key.KeyID}},
+					},
+				},
+				protectAll,
+			},
+		}, expression)
+
+		expectedString := fmt.Sprintf("(2 of {%s, %s, %s} OR 2 of {%s, %s}) OR 1 of {%s}", targets1Key.KeyID, targets2Key.KeyID, rootKey.KeyID, gpgKey.KeyID, targets1Key.KeyID, gpgKey.KeyID)
+		assert.Equal(t, expectedString, expression.String())
+
+		// The expression can be serialized for tooling
+		expressionBytes, err := json.Marshal(expression)
+		if err != nil {
+			t.Fatal(err)
+		}
+		decodedExpression := &AuthorizationExpression{}
+		if err := json.Unmarshal(expressionBytes, decodedExpression); err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, expression, decodedExpression)
+	})
+
+	t.Run("delegated rule does not match", func(t *testing.T) {
+		state := createState(t, false)
+
+		expression, err := state.FindAuthorizationExpressionForPath(testCtx, "file:src/main.go")
+		assert.Nil(t, err)
+		assert.Equal(t, &AuthorizationExpression{
+			AnyOf: []*AuthorizationExpression{
+				{RuleName: "protect-src", Threshold: 2, KeyIDs: []string{targets1Key.KeyID, targets2Key.KeyID, rootKey.KeyID}},
+				protectAll,
+			},
+		}, expression)
+		assert.Equal(t, fmt.Sprintf("2 of {%s, %s, %s} OR 1 of {%s}", targets1Key.KeyID, targets2Key.KeyID, rootKey.KeyID, gpgKey.KeyID), expression.String())
+	})
+
+	t.Run("terminating rule", func(t *testing.T) {
+		state := createState(t, true)
+
+		expression, err := state.FindAuthorizationExpressionForPath(testCtx, "file:src/lib.go")
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(expression.AnyOf))
+		assert.Equal(t, "protect-src", expression.AnyOf[0].RuleName)
+		assert.Equal(t, "protect-src-lib", expression.AnyOf[0].AnyOf[0].RuleName)
+
+		expression, err = state.FindAuthorizationExpressionForPath(testCtx, "file:docs/README.md")
+		assert.Nil(t, err)
+		assert.Equal(t, &AuthorizationExpression{AnyOf: []*AuthorizationExpression{protectAll}}, expression)
+	})
+
+	t.Run("unprotected path", func(t *testing.T) {
+		state := createState(t, false)
+
+		expression, err := state.FindAuthorizationExpressionForPath(testCtx, "git:refs/heads/main")
+		assert.Nil(t, err)
+		assert.Nil(t, expression)
+	})
+
+	t.Run("denied path", func(t *testing.T) {
+		state := createState(t, false)
+
+		_, err := state.FindAuthorizationExpressionForPath(testCtx, "file:secrets/key")
+		assert.ErrorIs(t, err, ErrPathDenied)
+	})
+}
+
+func loadTestKeyBytes(t *testing.T, name string) []byte {
+	t.Helper()
+
+	keyBytes, err := os.ReadFile(filepath.Join("test-data", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return keyBytes
+}
+
+func loadTestKey(t *testing.T, name string) *tuf.Key {
+	t.Helper()
+
+	key, err := tuf.LoadKeyFromBytes(loadTestKeyBytes(t, name))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return key
+}
+
+func signTestEnvelope(t *testing.T, metadata any, signingKeys ...[]byte) *sslibdsse.Envelope {
+	t.Helper()
+
+	env, err := dsse.CreateEnvelope(metadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, keyBytes := range signingKeys {
+		signer, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(keyBytes)
+		if err != nil {
+			t.Fatal(err)
+		}
+		env, err = dsse.SignEnvelope(testCtx, env, signer)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return env
+}
@@ -0,0 +1,76 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/gittuf/gittuf/internal/tuf"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+)
+
+// BundleType identifies a JSON document created by State.ExportBundle.
+const BundleType = "https://gittuf.dev/policy-bundle/v1"
+
+var ErrInvalidPolicyBundle = errors.New("invalid gittuf policy bundle")
+
+// Bundle is a portable representation of a policy State. It contains all the
+// signed metadata and the root public keys, so a policy can be inspected and
+// verified without access to the repository it was recorded in.
+type Bundle struct {
+	Type                string                         `json:"type"`
+	RootEnvelope        *sslibdsse.Envelope            `json:"root"`
+	TargetsEnvelope     *sslibdsse.Envelope            `json:"targets,omitempty"`
+	DelegationEnvelopes map[string]*sslibdsse.Envelope `json:"delegations,omitempty"`
+	RootPublicKeys      []*tuf.Key                     `json:"root_public_keys"`
+}
+
+// ExportBundle serializes the State's metadata and root public keys into a
+// single JSON document. The State can be reconstructed from the document using
+// LoadStateFromBundle.
+func (s *State) ExportBundle() ([]byte, error) {
+	if s.RootEnvelope == nil {
+		return nil, fmt.Errorf("%w: root metadata is missing", ErrInvalidPolicyBundle)
+	}
+
+	bundle := &Bundle{
+		Type:                BundleType,
+		RootEnvelope:        s.RootEnvelope,
+		TargetsEnvelope:     s.TargetsEnvelope,
+		DelegationEnvelopes: s.DelegationEnvelopes,
+		RootPublicKeys:      s.RootPublicKeys,
+	}
+
+	return json.Marshal(bundle)
+}
+
+// LoadStateFromBundle reconstructs a State from a document created by
+// State.ExportBundle. The State is verified before it is returned.
+func LoadStateFromBundle(ctx context.Context, contents []byte) (*State, error) {
+	bundle := &Bundle{}
+	if err := json.Unmarshal(contents, bundle); err != nil {
+		return nil, errors.Join(ErrInvalidPolicyBundle, err)
+	}
+
+	if bundle.Type != BundleType {
+		return nil, fmt.Errorf("%w: unknown bundle type '%s'", ErrInvalidPolicyBundle, bundle.Type)
+	}
+	if bundle.RootEnvelope == nil {
+		return nil, fmt.Errorf("%w: root metadata is missing", ErrInvalidPolicyBundle)
+	}
+
+	state := &State{
+		RootEnvelope:        bundle.RootEnvelope,
+		TargetsEnvelope:     bundle.TargetsEnvelope,
+		DelegationEnvelopes: bundle.DelegationEnvelopes,
+		RootPublicKeys:      bundle.RootPublicKeys,
+	}
+	if err := state.Verify(ctx); err != nil {
+		return nil, err
+	}
+
+	return state, nil
+}
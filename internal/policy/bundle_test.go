@@ -0,0 +1,90 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportBundle(t *testing.T) {
+	t.Run("round trip", func(t *testing.T) {
+		repo, _ := createTestRepository(t, createTestStateWithPolicy)
+
+		state, err := LoadCurrentState(testCtx, repo)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		bundleContents, err := state.ExportBundle()
+		assert.Nil(t, err)
+
+		loadedState, err := LoadStateFromBundle(testCtx, bundleContents)
+		assert.Nil(t, err)
+		assert.Equal(t, state, loadedState)
+		assert.Nil(t, loadedState.Verify(testCtx))
+	})
+
+	t.Run("root only", func(t *testing.T) {
+		state := createTestStateWithOnlyRoot(t)
+
+		bundleContents, err := state.ExportBundle()
+		assert.Nil(t, err)
+
+		loadedState, err := LoadStateFromBundle(testCtx, bundleContents)
+		assert.Nil(t, err)
+		assert.Equal(t, state, loadedState)
+	})
+
+	t.Run("missing root", func(t *testing.T) {
+		state := &State{}
+
+		_, err := state.ExportBundle()
+		assert.ErrorIs(t, err, ErrInvalidPolicyBundle)
+	})
+}
+
+func TestLoadStateFromBundle(t *testing.T) {
+	state := createTestStateWithPolicy(t)
+	bundleContents, err := state.ExportBundle()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("malformed bundle", func(t *testing.T) {
+		_, err := LoadStateFromBundle(testCtx, []byte("not a bundle"))
+		assert.ErrorIs(t, err, ErrInvalidPolicyBundle)
+	})
+
+	t.Run("unknown bundle type", func(t *testing.T) {
+		bundle := &Bundle{}
+		if err := json.Unmarshal(bundleContents, bundle); err != nil {
+			t.Fatal(err)
+		}
+		bundle.Type = "unknown"
+		contents, err := json.Marshal(bundle)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = LoadStateFromBundle(testCtx, contents)
+		assert.ErrorIs(t, err, ErrInvalidPolicyBundle)
+	})
+
+	t.Run("tampered metadata", func(t *testing.T) {
+		bundle := &Bundle{}
+		if err := json.Unmarshal(bundleContents, bundle); err != nil {
+			t.Fatal(err)
+		}
+		bundle.RootEnvelope.Signatures = nil
+		contents, err := json.Marshal(bundle)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = LoadStateFromBundle(testCtx, contents)
+		assert.NotNil(t, err)
+	})
+}
@@ -0,0 +1,223 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/gittuf/gittuf/internal/third_party/go-git"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing"
+	"github.com/gittuf/gittuf/internal/tuf"
+)
+
+// verifyEntryForCache is used to verify entries that are not in the cache. It
+// is a variable so that it can be overridden in tests.
+var verifyEntryForCache = verifyEntry
+
+// VerificationCache records the RSL entries that have been verified for each
+// ref, along with the policy RSL entry that the verification was performed
+// with. A cached result is only reused when the ref's latest RSL entry is
+// unchanged and the policy has not changed in a way that affects the ref. The
+// zero value is not usable, use NewVerificationCache instead.
+type VerificationCache struct {
+	mu      sync.Mutex
+	results map[string]verificationResult
+}
+
+type verificationResult struct {
+	entryID       plumbing.Hash
+	policyEntryID plumbing.Hash
+}
+
+// NewVerificationCache returns an empty VerificationCache.
+func NewVerificationCache() *VerificationCache {
+	return &VerificationCache{results: map[string]verificationResult{}}
+}
+
+func (c *VerificationCache) get(target string) (verificationResult, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	result, ok := c.results[target]
+	return result, ok
+}
+
+func (c *VerificationCache) set(target string, result verificationResult) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.results[target] = result
+}
+
+// VerifyRefWithCache is similar to VerifyRef, but it skips verifying the
+// target ref's latest RSL entry if the cache indicates it has been verified
+// previously. When the policy has advanced since the cached verification, the
+// policy used for the cached verification is compared with the latest policy.
+// If the rules that apply to the ref, the file rules, the RSL writers, or the
+// emergency keys have changed, the cached result is ignored and the entry is
+// verified again using the latest policy. Otherwise, the cached result is updated to record the
+// latest policy. Only successful verifications are cached.
+func VerifyRefWithCache(ctx context.Context, repo *git.Repository, target string, cache *VerificationCache) error {
+	policyEntry, _, err := rsl.GetLatestReferenceEntryForRef(repo, PolicyRef)
+	if err != nil {
+		return err
+	}
+
+	latestEntry, annotations, err := rsl.GetLatestReferenceEntryForRef(repo, target)
+	if err != nil {
+		return err
+	}
+
+	if err := verifyRefMatchesEntry(repo, latestEntry); err != nil {
+		return err
+	}
+
+	policyState, err := LoadStateForEntry(ctx, repo, policyEntry)
+	if err != nil {
+		return err
+	}
+
+	if result, ok := cache.get(target); ok && result.entryID == latestEntry.ID {
+		if result.policyEntryID == policyEntry.ID {
+			return nil
+		}
+
+		cachedPolicyEntry, err := rsl.GetEntry(repo, result.policyEntryID)
+		if err != nil {
+			return err
+		}
+		cachedPolicyState, err := LoadStateForEntry(ctx, repo, cachedPolicyEntry)
+		if err != nil {
+			return err
+		}
+
+		affected, err := policyChangeAffectsRef(ctx, cachedPolicyState, policyState, target)
+		if err != nil {
+			return err
+		}
+		if !affected {
+			cache.set(target, verificationResult{entryID: latestEntry.ID, policyEntryID: policyEntry.ID})
+			return nil
+		}
+	}
+
+	if err := verifyEntryForCache(ctx, repo, policyState, latestEntry, annotations); err != nil {
+		return err
+	}
+
+	cache.set(target, verificationResult{entryID: latestEntry.ID, policyEntryID: policyEntry.ID})
+	return nil
+}
+
+// policyChangeAffectsRef indicates if the change from prev to next affects the
+// verification of the target ref.
+func policyChangeAffectsRef(ctx context.Context, prev, next *State, target string) (bool, error) {
+	prevRules, err := getRulesForRef(ctx, prev, target)
+	if err != nil {
+		return false, err
+	}
+
+	nextRules, err := getRulesForRef(ctx, next, target)
+	if err != nil {
+		return false, err
+	}
+
+	return !reflect.DeepEqual(prevRules, nextRules), nil
+}
+
+// refRules captures the parts of a policy that are used to verify a ref's RSL
+// entries. Commits may modify any file, so all the file rules are captured.
+type refRules struct {
+	refRules      []tuf.Delegation
+	refKeys       map[string]*tuf.Key
+	fileRules     []tuf.Delegation
+	fileKeys      map[string]*tuf.Key
+	rslWriterKeys []*tuf.Key
+
+	// The emergency role can authorize entries that the rules reject
+	emergencyKeys      []*tuf.Key
+	emergencyThreshold int
+}
+
+// getRulesForRef returns the rules used to verify the target ref's RSL entries.
+// The state is expected to have been verified when it was loaded.
+func getRulesForRef(ctx context.Context, state *State, target string) (*refRules, error) {
+	rslWriterKeys, err := state.FindRSLWriterKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	emergencyKeys, emergencyThreshold, err := state.FindEmergencyKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	rules := &refRules{
+		refKeys:            map[string]*tuf.Key{},
+		fileKeys:           map[string]*tuf.Key{},
+		rslWriterKeys:      rslWriterKeys,
+		emergencyKeys:      emergencyKeys,
+		emergencyThreshold: emergencyThreshold,
+	}
+
+	roleNames := state.targetsRoleNames()
+	if len(roleNames) == 0 {
+		return rules, nil
+	}
+
+	delegations, keys, denyRule, err := state.findDelegationsForPath(ctx, fmt.Sprintf("git:%s", target), "") // FIXME: "git:" shouldn't be here
+	if err != nil {
+		return nil, err
+	}
+	if denyRule != nil {
+		delegations = append(delegations, *denyRule)
+	}
+	rules.refRules = delegations
+	for _, delegation := range delegations {
+		for _, keyID := range delegation.KeyIDs {
+			key, has := keys[keyID]
+			if !has {
+				return nil, fmt.Errorf("%w: rule '%s' authorizes key '%s'", tuf.ErrDelegationKeyMissing, delegation.Name, keyID)
+			}
+			rules.refKeys[keyID] = key
+		}
+	}
+
+	// roleNames is sorted so that the file rules are compared in a
+	// deterministic order
+	for _, roleName := range roleNames {
+		targetsMetadata, err := state.GetTargetsMetadata(roleName)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, delegation := range targetsMetadata.Delegations.Roles {
+			isFileRule := false
+			for _, pattern := range delegation.Paths {
+				if strings.HasPrefix(pattern, "file:") { // FIXME: "file:" shouldn't be here
+					isFileRule = true
+					break
+				}
+			}
+			if !isFileRule {
+				continue
+			}
+
+			rules.fileRules = append(rules.fileRules, delegation)
+			for _, keyID := range delegation.KeyIDs {
+				key, has := targetsMetadata.Delegations.Keys[keyID]
+				if !has {
+					return nil, fmt.Errorf("%w: rule '%s' authorizes key '%s'", tuf.ErrDelegationKeyMissing, delegation.Name, keyID)
+				}
+				rules.fileKeys[keyID] = key
+			}
+		}
+	}
+
+	return rules, nil
+}
@@ -0,0 +1,97 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/common"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/gittuf/gittuf/internal/signerverifier"
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	"github.com/gittuf/gittuf/internal/third_party/go-git"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifyRefWithCache(t *testing.T) {
+	repo, state := createTestRepository(t, createTestStateWithPolicy)
+	mainRefName := "refs/heads/main"
+	featureRefName := "refs/heads/feature"
+
+	for _, refName := range []string{mainRefName, featureRefName} {
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyName)
+		common.CreateTestRSLReferenceEntryCommit(t, repo, rsl.NewReferenceEntry(refName, commitIDs[0]), gpgKeyName)
+	}
+
+	verifiedRefs := []string{}
+	verifyEntryForCache = func(ctx context.Context, repo *git.Repository, policy *State, entry *rsl.ReferenceEntry, annotations []*rsl.AnnotationEntry) error {
+		verifiedRefs = append(verifiedRefs, entry.RefName)
+		return verifyEntry(ctx, repo, policy, entry, annotations)
+	}
+	t.Cleanup(func() {
+		verifyEntryForCache = verifyEntry
+	})
+
+	cache := NewVerificationCache()
+
+	for _, refName := range []string{mainRefName, featureRefName} {
+		err := VerifyRefWithCache(testCtx, repo, refName, cache)
+		assert.Nil(t, err)
+	}
+	assert.Equal(t, []string{mainRefName, featureRefName}, verifiedRefs)
+
+	// Cached results are reused
+	verifiedRefs = []string{}
+	for _, refName := range []string{mainRefName, featureRefName} {
+		err := VerifyRefWithCache(testCtx, repo, refName, cache)
+		assert.Nil(t, err)
+	}
+	assert.Empty(t, verifiedRefs)
+
+	// Advance the policy so that main is denied
+	targetsMetadata, err := state.GetTargetsMetadata(TargetsRoleName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	targetsMetadata, err = AddOrUpdateDenyRule(targetsMetadata, "deny-main", []string{"git:" + mainRefName})
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(rootKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	targetsEnv, err := dsse.CreateEnvelope(targetsMetadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	targetsEnv, err = dsse.SignEnvelope(testCtx, targetsEnv, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state.TargetsEnvelope = targetsEnv
+	if err := state.Commit(testCtx, repo, "Deny main", false); err != nil {
+		t.Fatal(err)
+	}
+
+	// The cached result for main is ignored as its rules changed
+	verifiedRefs = []string{}
+	err = VerifyRefWithCache(testCtx, repo, mainRefName, cache)
+	assert.ErrorIs(t, err, ErrPathDenied)
+	assert.Equal(t, []string{mainRefName}, verifiedRefs)
+
+	// The cached result for feature is reused as its rules are unchanged
+	verifiedRefs = []string{}
+	err = VerifyRefWithCache(testCtx, repo, featureRefName, cache)
+	assert.Nil(t, err)
+	assert.Empty(t, verifiedRefs)
+
+	// A new entry for the ref is verified
+	commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, featureRefName, 1, gpgKeyName)
+	common.CreateTestRSLReferenceEntryCommit(t, repo, rsl.NewReferenceEntry(featureRefName, commitIDs[0]), gpgKeyName)
+
+	err = VerifyRefWithCache(testCtx, repo, featureRefName, cache)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{featureRefName}, verifiedRefs)
+}
@@ -5,9 +5,11 @@ package policy
 import (
 	"context"
 	"testing"
+	"time"
 
 	_ "embed"
 
+	"github.com/gittuf/gittuf/internal/gitinterface"
 	"github.com/gittuf/gittuf/internal/rsl"
 	"github.com/gittuf/gittuf/internal/signerverifier"
 	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
@@ -16,6 +18,7 @@ import (
 	"github.com/gittuf/gittuf/internal/third_party/go-git/storage/memory"
 	"github.com/gittuf/gittuf/internal/tuf"
 	"github.com/go-git/go-billy/v5/memfs"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
 )
 
 var testCtx = context.Background()
@@ -26,10 +29,19 @@ var rootKeyBytes []byte
 //go:embed test-data/root.pub
 var rootPubKeyBytes []byte
 
+//go:embed test-data/targets-1
+var targets1KeyBytes []byte
+
+//go:embed test-data/targets-1.pub
+var targets1PubKeyBytes []byte
+
 //go:embed test-data/gpg-pubkey.asc
 var gpgPubKeyBytes []byte
 
-func createTestRepository(t *testing.T, stateCreator func(*testing.T) *State) (*git.Repository, *State) {
+//go:embed test-data/gpg-pubkey-2.asc
+var gpgPubKey2Bytes []byte
+
+func createTestRepository(t testing.TB, stateCreator func(testing.TB) *State) (*git.Repository, *State) {
 	t.Helper()
 
 	state := stateCreator(t)
@@ -53,7 +65,7 @@ func createTestRepository(t *testing.T, stateCreator func(*testing.T) *State) (*
 	return repo, state
 }
 
-func createTestStateWithOnlyRoot(t *testing.T) *State {
+func createTestStateWithOnlyRoot(t testing.TB) *State {
 	t.Helper()
 
 	signer, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(rootKeyBytes)
@@ -83,7 +95,7 @@ func createTestStateWithOnlyRoot(t *testing.T) *State {
 	}
 }
 
-func createTestStateWithPolicy(t *testing.T) *State {
+func createTestStateWithPolicy(t testing.TB) *State {
 	t.Helper()
 
 	signer, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(rootKeyBytes)
@@ -141,7 +153,7 @@ func createTestStateWithPolicy(t *testing.T) *State {
 	}
 }
 
-func createTestStateWithTagPolicy(t *testing.T) *State {
+func createTestStateWithRSLWriter(t testing.TB) *State {
 	t.Helper()
 
 	state := createTestStateWithPolicy(t)
@@ -150,14 +162,50 @@ func createTestStateWithTagPolicy(t *testing.T) *State {
 	if err != nil {
 		t.Fatal(err)
 	}
-	targetsMetadata, err := state.GetTargetsMetadata(TargetsRoleName)
+	rootMetadata, err := state.GetRootMetadata()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootMetadata = AddRSLWriterKey(rootMetadata, gpgKey)
+	setTestRootMetadata(t, state, rootMetadata)
+
+	return state
+}
+
+// setTestRootMetadata replaces the root metadata of state with rootMetadata,
+// signed using the test root key.
+func setTestRootMetadata(t testing.TB, state *State, rootMetadata *tuf.RootMetadata) {
+	t.Helper()
+
+	rootEnv, err := dsse.CreateEnvelope(rootMetadata)
 	if err != nil {
 		t.Fatal(err)
 	}
-	targetsMetadata, err = AddOrUpdateDelegation(targetsMetadata, "protect-tags", []*tuf.Key{gpgKey}, []string{"git:refs/tags/*"})
+	signer, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(rootKeyBytes)
 	if err != nil {
 		t.Fatal(err)
 	}
+	rootEnv, err = dsse.SignEnvelope(context.Background(), rootEnv, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state.RootEnvelope = rootEnv
+}
+
+// createTestStateWithTargets returns a state that extends the policy created by
+// createTestStateWithPolicy with the changes mutate makes to the top level
+// targets metadata. The updated metadata is signed using the test root key.
+func createTestStateWithTargets(t testing.TB, mutate func(*tuf.TargetsMetadata)) *State {
+	t.Helper()
+
+	state := createTestStateWithPolicy(t)
+
+	targetsMetadata, err := state.GetTargetsMetadata(TargetsRoleName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mutate(targetsMetadata)
+
 	targetsEnv, err := dsse.CreateEnvelope(targetsMetadata)
 	if err != nil {
 		t.Fatal(err)
@@ -175,36 +223,296 @@ func createTestStateWithTagPolicy(t *testing.T) *State {
 	return state
 }
 
-func createTestStateWithTagPolicyForUnauthorizedTest(t *testing.T) *State {
+// createTestStateWithEmergencyRole returns a state that extends the policy
+// created by createTestStateWithPolicy with an emergency role. Both GPG keys
+// are emergency keys, and both must authorize the use of break-glass access.
+func createTestStateWithEmergencyRole(t testing.TB) *State {
 	t.Helper()
 
 	state := createTestStateWithPolicy(t)
 
-	rootKey, err := tuf.LoadKeyFromBytes(rootPubKeyBytes)
+	gpgKey, err := gpg.LoadGPGKeyFromBytes(gpgPubKeyBytes)
 	if err != nil {
 		t.Fatal(err)
 	}
-	targetsMetadata, err := state.GetTargetsMetadata(TargetsRoleName)
+	gpgKey2, err := gpg.LoadGPGKeyFromBytes(gpgPubKey2Bytes)
 	if err != nil {
 		t.Fatal(err)
 	}
-	targetsMetadata, err = AddOrUpdateDelegation(targetsMetadata, "protect-tags", []*tuf.Key{rootKey}, []string{"git:refs/tags/*"})
+	rootMetadata, err := state.GetRootMetadata()
 	if err != nil {
 		t.Fatal(err)
 	}
-	targetsEnv, err := dsse.CreateEnvelope(targetsMetadata)
+	rootMetadata, err = AddEmergencyKey(rootMetadata, gpgKey)
 	if err != nil {
 		t.Fatal(err)
 	}
-	signer, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(rootKeyBytes)
+	rootMetadata, err = AddEmergencyKey(rootMetadata, gpgKey2)
 	if err != nil {
 		t.Fatal(err)
 	}
-	targetsEnv, err = dsse.SignEnvelope(context.Background(), targetsEnv, signer)
+	rootMetadata, err = SetEmergencyThreshold(rootMetadata, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	setTestRootMetadata(t, state, rootMetadata)
+
+	return state
+}
+
+func createTestStateWithDenyRule(t testing.TB) *State {
+	t.Helper()
+
+	gpgKey, err := gpg.LoadGPGKeyFromBytes(gpgPubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return createTestStateWithTargets(t, func(targetsMetadata *tuf.TargetsMetadata) {
+		// The deny rule for secrets shadows the broader rule for all files
+		if _, err := AddOrUpdateDelegation(targetsMetadata, "protect-files", []*tuf.Key{gpgKey}, []string{"file:*"}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := AddOrUpdateDenyRule(targetsMetadata, "deny-secrets", []string{"file:secrets/*"}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := AddOrUpdateDenyRule(targetsMetadata, "deny-frozen", []string{"git:refs/heads/frozen"}); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+// createTestStateWithMultiplePolicies returns a state with a second top level
+// policy, security, in addition to the policy created by
+// createTestStateWithPolicy. The security policy is trusted to be signed by
+// targets-1, and it protects files in the ci directory using the second GPG
+// key.
+func createTestStateWithMultiplePolicies(t testing.TB) *State {
+	t.Helper()
+
+	state := createTestStateWithPolicy(t)
+
+	securitySigner, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(targets1KeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	securityKey, err := tuf.LoadKeyFromBytes(targets1PubKeyBytes)
 	if err != nil {
 		t.Fatal(err)
 	}
-	state.TargetsEnvelope = targetsEnv
+
+	rootMetadata, err := state.GetRootMetadata()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootMetadata, err = AddTopLevelTargetsRoleKey(rootMetadata, "security", securityKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	setTestRootMetadata(t, state, rootMetadata)
+
+	gpgKey2, err := gpg.LoadGPGKeyFromBytes(gpgPubKey2Bytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	securityMetadata := InitializeTargetsMetadata()
+	securityMetadata, err = AddOrUpdateDelegation(securityMetadata, "protect-ci", []*tuf.Key{gpgKey2}, []string{"file:ci/*"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	securityEnv, err := dsse.CreateEnvelope(securityMetadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	securityEnv, err = dsse.SignEnvelope(context.Background(), securityEnv, securitySigner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state.DelegationEnvelopes = map[string]*sslibdsse.Envelope{"security": securityEnv}
 
 	return state
 }
+
+func createTestStateWithAllowedHashes(t testing.TB) *State {
+	t.Helper()
+
+	return createTestStateWithTargets(t, func(targetsMetadata *tuf.TargetsMetadata) {
+		// common.AddNTestCommitsToSpecifiedRef creates empty files
+		if _, err := SetAllowedHashes(targetsMetadata, "protect-files-1-and-2", []string{gitinterface.EmptyBlob().String()}); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+func createTestStateWithMergeCommitVerification(t testing.TB) *State {
+	t.Helper()
+
+	return createTestStateWithTargets(t, func(targetsMetadata *tuf.TargetsMetadata) {
+		if _, err := SetVerifyMergeCommits(targetsMetadata, "protect-main", true); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+func createTestStateWithSignedCommitsRequirement(t testing.TB) *State {
+	t.Helper()
+
+	return createTestStateWithTargets(t, func(targetsMetadata *tuf.TargetsMetadata) {
+		if _, err := SetRequireSignedCommits(targetsMetadata, "protect-main", true); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+func createTestStateWithKeyValidityWindow(t testing.TB, notBefore, notAfter time.Time) *State {
+	t.Helper()
+
+	gpgKey, err := gpg.LoadGPGKeyFromBytes(gpgPubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return createTestStateWithTargets(t, func(targetsMetadata *tuf.TargetsMetadata) {
+		if _, err := SetKeyValidityWindow(targetsMetadata, gpgKey.KeyID, notBefore, notAfter); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+// createTestStateWithExpiringRule returns a state with a second rule for the
+// main branch and the file 1 in addition to the rules created by
+// createTestStateWithPolicy. The second rule trusts the second GPG key until
+// the specified time.
+func createTestStateWithExpiringRule(t testing.TB, expires time.Time) *State {
+	t.Helper()
+
+	gpgKey2, err := gpg.LoadGPGKeyFromBytes(gpgPubKey2Bytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return createTestStateWithTargets(t, func(targetsMetadata *tuf.TargetsMetadata) {
+		if _, err := AddOrUpdateDelegation(targetsMetadata, "temporary-access", []*tuf.Key{gpgKey2}, []string{"git:refs/heads/main", "file:1"}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := SetRuleExpiry(targetsMetadata, "temporary-access", expires); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+func createTestStateWithDistinctSigners(t testing.TB) *State {
+	t.Helper()
+
+	gpgKey, err := gpg.LoadGPGKeyFromBytes(gpgPubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gpgKey2, err := gpg.LoadGPGKeyFromBytes(gpgPubKey2Bytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return createTestStateWithTargets(t, func(targetsMetadata *tuf.TargetsMetadata) {
+		for _, delegation := range targetsMetadata.Delegations.Roles {
+			if delegation.Name != "protect-main" && delegation.Name != "protect-files-1-and-2" {
+				continue
+			}
+			if _, err := AddOrUpdateDelegation(targetsMetadata, delegation.Name, []*tuf.Key{gpgKey, gpgKey2}, delegation.Paths); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if _, err := SetMinDistinctSigners(targetsMetadata, "protect-main", 2, 3); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+func createTestStateWithCoSigners(t testing.TB) *State {
+	t.Helper()
+
+	gpgKey, err := gpg.LoadGPGKeyFromBytes(gpgPubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gpgKey2, err := gpg.LoadGPGKeyFromBytes(gpgPubKey2Bytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return createTestStateWithTargets(t, func(targetsMetadata *tuf.TargetsMetadata) {
+		if _, err := AddOrUpdateDelegation(targetsMetadata, "protect-main", []*tuf.Key{gpgKey, gpgKey2}, []string{"git:refs/heads/main"}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := SetRuleThreshold(targetsMetadata, "protect-main", 2); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+func createTestStateWithFileCoSigners(t testing.TB) *State {
+	t.Helper()
+
+	gpgKey, err := gpg.LoadGPGKeyFromBytes(gpgPubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gpgKey2, err := gpg.LoadGPGKeyFromBytes(gpgPubKey2Bytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return createTestStateWithTargets(t, func(targetsMetadata *tuf.TargetsMetadata) {
+		if _, err := AddOrUpdateDelegation(targetsMetadata, "protect-files-1-and-2", []*tuf.Key{gpgKey, gpgKey2}, []string{"file:1", "file:2"}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := SetRuleThreshold(targetsMetadata, "protect-files-1-and-2", 2); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+func createTestStateWithNotesPolicy(t testing.TB) *State {
+	t.Helper()
+
+	gpgKey, err := gpg.LoadGPGKeyFromBytes(gpgPubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return createTestStateWithTargets(t, func(targetsMetadata *tuf.TargetsMetadata) {
+		if _, err := AddOrUpdateDelegation(targetsMetadata, "protect-notes", []*tuf.Key{gpgKey}, []string{"git:refs/notes/*"}); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+func createTestStateWithTagPolicy(t testing.TB) *State {
+	t.Helper()
+
+	gpgKey, err := gpg.LoadGPGKeyFromBytes(gpgPubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return createTestStateWithTargets(t, func(targetsMetadata *tuf.TargetsMetadata) {
+		if _, err := AddOrUpdateDelegation(targetsMetadata, "protect-tags", []*tuf.Key{gpgKey}, []string{"git:refs/tags/*"}); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+func createTestStateWithTagPolicyForUnauthorizedTest(t testing.TB) *State {
+	t.Helper()
+
+	rootKey, err := tuf.LoadKeyFromBytes(rootPubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return createTestStateWithTargets(t, func(targetsMetadata *tuf.TargetsMetadata) {
+		if _, err := AddOrUpdateDelegation(targetsMetadata, "protect-tags", []*tuf.Key{rootKey}, []string{"git:refs/tags/*"}); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
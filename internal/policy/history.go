@@ -0,0 +1,276 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/gittuf/gittuf/internal/third_party/go-git"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing/object"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+)
+
+// StateHistoryEntry summarizes a policy state recorded in the RSL.
+type StateHistoryEntry struct {
+	// RSLEntry is the RSL entry that recorded the policy state.
+	RSLEntry *rsl.ReferenceEntry
+
+	// Timestamp is the commit time of the policy state.
+	Timestamp time.Time
+
+	// RootVersion is the version of the root metadata.
+	RootVersion int
+
+	// TargetsVersion is the version of the top level targets metadata. It is
+	// set to 0 if the policy state has no targets metadata.
+	TargetsVersion int
+
+	// RootSigners contains the IDs of the keys that signed the root metadata.
+	RootSigners []string
+
+	// TargetsSigners contains the IDs of the keys that signed the top level
+	// targets metadata.
+	TargetsSigners []string
+}
+
+// GetStateHistory returns a summary of each policy state recorded in the RSL,
+// in the order the states were recorded. The policy states are only verified
+// if verify is set, as verifying every historical state can be expensive.
+func GetStateHistory(ctx context.Context, repo *git.Repository, verify bool) ([]*StateHistoryEntry, error) {
+	entries, _, err := rsl.GetReferenceEntriesForRef(repo, PolicyRef)
+	if err != nil {
+		return nil, err
+	}
+
+	history := make([]*StateHistoryEntry, 0, len(entries))
+	for _, entry := range entries {
+		state, err := loadStateForEntry(ctx, repo, entry, verify)
+		if err != nil {
+			return nil, err
+		}
+
+		policyCommit, err := repo.CommitObject(entry.TargetID)
+		if err != nil {
+			return nil, err
+		}
+
+		rootMetadata, err := state.GetRootMetadata()
+		if err != nil {
+			return nil, err
+		}
+
+		historyEntry := &StateHistoryEntry{
+			RSLEntry:    entry,
+			Timestamp:   policyCommit.Committer.When,
+			RootVersion: rootMetadata.Version,
+			RootSigners: getEnvelopeSigners(state.RootEnvelope),
+		}
+
+		if state.TargetsEnvelope != nil {
+			targetsMetadata, err := state.GetTargetsMetadata(TargetsRoleName)
+			if err != nil {
+				return nil, err
+			}
+
+			historyEntry.TargetsVersion = targetsMetadata.Version
+			historyEntry.TargetsSigners = getEnvelopeSigners(state.TargetsEnvelope)
+		}
+
+		history = append(history, historyEntry)
+	}
+
+	return history, nil
+}
+
+func getEnvelopeSigners(env *sslibdsse.Envelope) []string {
+	signers := make([]string, 0, len(env.Signatures))
+	for _, signature := range env.Signatures {
+		signers = append(signers, signature.KeyID)
+	}
+
+	return signers
+}
+
+// PathChangeEntry identifies an RSL entry that introduced commits changing a
+// path.
+type PathChangeEntry struct {
+	// RSLEntry is the RSL entry that introduced the commits.
+	RSLEntry *rsl.ReferenceEntry
+
+	// Commits contains the commits introduced by the entry that change the
+	// path, oldest first.
+	Commits []*PathChangeCommit
+}
+
+// PathChangeCommit identifies a commit that changes a path.
+type PathChangeCommit struct {
+	// Commit is the commit that changes the path.
+	Commit *object.Commit
+
+	// SignerKeyID is the ID of the key that verified the commit's signature.
+	// The key is identified using the policy in effect when the RSL entry was
+	// recorded. It is empty if the commit is not signed by any key in that
+	// policy. This does not indicate that the signer was authorized to change
+	// the path, VerifyRef must be used for that.
+	SignerKeyID string
+}
+
+// GetRSLEntriesForPath returns the RSL entries that introduced commits changing
+// the specified path, in the order the entries were recorded. If path is a
+// directory, changes to any file within it are included. The commits
+// introduced by an entry are those between the entry's target and the target
+// of the previous entry for the same ref. Entries for gittuf's own refs and for
+// tags are not considered. Merge commits are not considered either, as they do
+// not change any paths themselves.
+func GetRSLEntriesForPath(ctx context.Context, repo *git.Repository, path string) ([]*PathChangeEntry, error) {
+	path = strings.Trim(path, "/")
+
+	iter, err := rsl.Iterator(repo, rsl.WithOldestFirst())
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		matches        = []*PathChangeEntry{}
+		priorTargetIDs = map[string]plumbing.Hash{}
+		policyEntry    *rsl.ReferenceEntry
+		states         = map[plumbing.Hash]*State{}
+	)
+
+	err = iter.ForEach(func(e rsl.Entry) error {
+		entry, isReferenceEntry := e.(*rsl.ReferenceEntry)
+		if !isReferenceEntry {
+			return nil
+		}
+
+		if entry.RefName == PolicyRef {
+			policyEntry = entry
+			return nil
+		}
+		if strings.HasPrefix(entry.RefName, rsl.GittufNamespacePrefix) || strings.HasPrefix(entry.RefName, gitinterface.TagRefPrefix) {
+			return nil
+		}
+
+		priorTargetID := priorTargetIDs[entry.RefName]
+		priorTargetIDs[entry.RefName] = entry.TargetID
+		if entry.TargetID.IsZero() {
+			return nil
+		}
+
+		commits, err := gitinterface.GetCommitsBetweenRangeOldestFirst(repo, entry.TargetID, priorTargetID)
+		if err != nil {
+			return err
+		}
+
+		var state *State
+		pathChangeEntry := &PathChangeEntry{RSLEntry: entry}
+		for _, commit := range commits {
+			changed, err := commitChangesPath(repo, commit, path)
+			if err != nil {
+				return err
+			}
+			if !changed {
+				continue
+			}
+
+			if state == nil && policyEntry != nil {
+				state, err = getStateForPolicyEntry(ctx, repo, policyEntry, states)
+				if err != nil {
+					return err
+				}
+			}
+
+			signerKeyID, err := findCommitSignerKeyID(ctx, state, commit)
+			if err != nil {
+				return err
+			}
+
+			pathChangeEntry.Commits = append(pathChangeEntry.Commits, &PathChangeCommit{Commit: commit, SignerKeyID: signerKeyID})
+		}
+
+		if len(pathChangeEntry.Commits) > 0 {
+			matches = append(matches, pathChangeEntry)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return matches, nil
+}
+
+// commitChangesPath indicates if the commit changes the path or, if the path
+// is a directory, any file within it.
+func commitChangesPath(repo *git.Repository, commit *object.Commit, path string) (bool, error) {
+	changedPaths, err := gitinterface.GetFilePathsChangedByCommit(repo, commit)
+	if err != nil {
+		return false, err
+	}
+
+	for _, changedPath := range changedPaths {
+		if changedPath == path || strings.HasPrefix(changedPath, path+"/") {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// getStateForPolicyEntry loads the policy state recorded by the entry, reusing
+// previously loaded states.
+func getStateForPolicyEntry(ctx context.Context, repo *git.Repository, entry *rsl.ReferenceEntry, states map[plumbing.Hash]*State) (*State, error) {
+	if state, has := states[entry.ID]; has {
+		return state, nil
+	}
+
+	state, err := LoadStateForEntry(ctx, repo, entry)
+	if err != nil {
+		return nil, err
+	}
+	states[entry.ID] = state
+
+	return state, nil
+}
+
+// findCommitSignerKeyID returns the ID of the key in the policy that verifies
+// the commit's signature. If no key in the policy verifies the signature, or
+// if there is no policy, an empty string is returned.
+func findCommitSignerKeyID(ctx context.Context, policy *State, commit *object.Commit) (string, error) {
+	if policy == nil || len(commit.PGPSignature) == 0 {
+		return "", nil
+	}
+
+	keys, err := policy.PublicKeys()
+	if err != nil {
+		return "", err
+	}
+
+	keyIDs := make([]string, 0, len(keys))
+	for keyID := range keys {
+		keyIDs = append(keyIDs, keyID)
+	}
+	sort.Strings(keyIDs)
+
+	for _, keyID := range keyIDs {
+		err := verifyCommitSignature(ctx, policy, commit, keys[keyID])
+		if err == nil {
+			return keyID, nil
+		}
+		if errors.Is(err, gitinterface.ErrIncorrectVerificationKey) || errors.Is(err, gitinterface.ErrUnknownSigningMethod) {
+			continue
+		}
+
+		return "", err
+	}
+
+	return "", nil
+}
@@ -0,0 +1,143 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/common"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/gittuf/gittuf/internal/signerverifier"
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	"github.com/gittuf/gittuf/internal/signerverifier/gpg"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing"
+	"github.com/gittuf/gittuf/internal/tuf"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetStateHistory(t *testing.T) {
+	repo, _ := createTestRepository(t, createTestStateWithOnlyRoot)
+
+	rootKey, err := tuf.LoadKeyFromBytes(rootPubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(rootKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Record a second policy state with a new root version and targets
+	// metadata
+	state := createTestStateWithPolicy(t)
+	rootMetadata, err := state.GetRootMetadata()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootMetadata.SetVersion(2)
+	rootEnv, err := dsse.CreateEnvelope(rootMetadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootEnv, err = dsse.SignEnvelope(testCtx, rootEnv, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state.RootEnvelope = rootEnv
+
+	if err := state.Commit(testCtx, repo, "Update policy", false); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, verify := range []bool{false, true} {
+		history, err := GetStateHistory(testCtx, repo, verify)
+		assert.Nil(t, err)
+		if !assert.Equal(t, 2, len(history)) {
+			continue
+		}
+
+		assert.Equal(t, PolicyRef, history[0].RSLEntry.RefName)
+		assert.Equal(t, 1, history[0].RootVersion)
+		assert.Equal(t, 0, history[0].TargetsVersion)
+		assert.Equal(t, []string{rootKey.KeyID}, history[0].RootSigners)
+		assert.Nil(t, history[0].TargetsSigners)
+		assert.False(t, history[0].Timestamp.IsZero())
+
+		assert.Equal(t, PolicyRef, history[1].RSLEntry.RefName)
+		assert.Equal(t, 2, history[1].RootVersion)
+		assert.Equal(t, 1, history[1].TargetsVersion)
+		assert.Equal(t, []string{rootKey.KeyID}, history[1].RootSigners)
+		assert.Equal(t, []string{rootKey.KeyID}, history[1].TargetsSigners)
+		assert.False(t, history[1].Timestamp.Before(history[0].Timestamp))
+	}
+}
+
+func TestGetRSLEntriesForPath(t *testing.T) {
+	repo, _ := createTestRepository(t, createTestStateWithPolicy)
+	mainRefName := "refs/heads/main"
+	featureRefName := "refs/heads/feature"
+
+	gpgKey, err := gpg.LoadGPGKeyFromBytes(gpgPubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The ith commit on each ref adds the file named i
+	mainCommitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, mainRefName, 3, gpgKeyName)
+	firstEntryID := common.CreateTestRSLReferenceEntryCommit(t, repo, rsl.NewReferenceEntry(mainRefName, mainCommitIDs[0]), gpgKeyName)
+	secondEntryID := common.CreateTestRSLReferenceEntryCommit(t, repo, rsl.NewReferenceEntry(mainRefName, mainCommitIDs[2]), gpgKeyName)
+
+	featureCommitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, featureRefName, 1, gpgKeyName)
+	featureEntryID := common.CreateTestRSLReferenceEntryCommit(t, repo, rsl.NewReferenceEntry(featureRefName, featureCommitIDs[0]), gpgKeyName)
+
+	tests := map[string]struct {
+		path             string
+		expectedEntryIDs []plumbing.Hash
+		expectedCommits  [][]plumbing.Hash
+	}{
+		"path changed in entries for different refs": {
+			path:             "1",
+			expectedEntryIDs: []plumbing.Hash{firstEntryID, featureEntryID},
+			expectedCommits:  [][]plumbing.Hash{{mainCommitIDs[0]}, {featureCommitIDs[0]}},
+		},
+		"path changed by one of several commits in entry": {
+			path:             "2",
+			expectedEntryIDs: []plumbing.Hash{secondEntryID},
+			expectedCommits:  [][]plumbing.Hash{{mainCommitIDs[1]}},
+		},
+		"path changed by last commit in entry": {
+			path:             "3",
+			expectedEntryIDs: []plumbing.Hash{secondEntryID},
+			expectedCommits:  [][]plumbing.Hash{{mainCommitIDs[2]}},
+		},
+		"path never changed": {
+			path:             "4",
+			expectedEntryIDs: []plumbing.Hash{},
+			expectedCommits:  [][]plumbing.Hash{},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			entries, err := GetRSLEntriesForPath(testCtx, repo, test.path)
+			assert.Nil(t, err, fmt.Sprintf("unexpected error in test '%s'", name))
+
+			entryIDs := []plumbing.Hash{}
+			commits := [][]plumbing.Hash{}
+			for _, entry := range entries {
+				entryIDs = append(entryIDs, entry.RSLEntry.ID)
+
+				commitIDs := []plumbing.Hash{}
+				for _, commit := range entry.Commits {
+					commitIDs = append(commitIDs, commit.Commit.Hash)
+					assert.Equal(t, gpgKey.KeyID, commit.SignerKeyID, fmt.Sprintf("unexpected signer in test '%s'", name))
+				}
+				commits = append(commits, commitIDs)
+			}
+
+			assert.Equal(t, test.expectedEntryIDs, entryIDs, fmt.Sprintf("unexpected entries in test '%s'", name))
+			assert.Equal(t, test.expectedCommits, commits, fmt.Sprintf("unexpected commits in test '%s'", name))
+		})
+	}
+}
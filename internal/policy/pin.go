@@ -0,0 +1,138 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/gittuf/gittuf/internal/signerverifier"
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	"github.com/gittuf/gittuf/internal/third_party/go-git"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing"
+	"github.com/gittuf/gittuf/internal/tuf"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+)
+
+var ErrPolicyPinMismatch = errors.New("policy does not match pinned trust anchor")
+
+// PolicyPins records trust anchors for a repository's policy that are
+// established out of band, such as when a repository is first cloned. Pins
+// that are not set are not checked.
+type PolicyPins struct {
+	// PolicyCommitID is a policy commit that the repository's current policy
+	// must be or descend from.
+	PolicyCommitID string
+
+	// RootKeyIDs are the IDs of root keys that must each have signed the root
+	// metadata of the repository's current policy.
+	RootKeyIDs []string
+}
+
+// CheckPolicyPins checks that the repository's current policy, as recorded in
+// the RSL, matches the pins. If the policy does not match,
+// ErrPolicyPinMismatch is returned. This allows the root of trust to be
+// bootstrapped using information obtained out of band rather than trusting
+// whichever policy is fetched.
+func CheckPolicyPins(ctx context.Context, repo *git.Repository, pins *PolicyPins) error {
+	if pins == nil || (len(pins.PolicyCommitID) == 0 && len(pins.RootKeyIDs) == 0) {
+		return nil
+	}
+
+	policyEntry, _, err := rsl.GetLatestReferenceEntryForRef(repo, PolicyRef)
+	if err != nil {
+		return err
+	}
+
+	if len(pins.PolicyCommitID) > 0 {
+		if !plumbing.IsHash(pins.PolicyCommitID) {
+			return fmt.Errorf("%w: '%s' is not a valid commit ID", ErrPolicyPinMismatch, pins.PolicyCommitID)
+		}
+
+		pinnedCommit, err := repo.CommitObject(plumbing.NewHash(pins.PolicyCommitID))
+		if err != nil {
+			if errors.Is(err, plumbing.ErrObjectNotFound) {
+				return fmt.Errorf("%w: pinned policy commit '%s' not found", ErrPolicyPinMismatch, pins.PolicyCommitID)
+			}
+			return err
+		}
+
+		knowsCommit, err := gitinterface.KnowsCommit(repo, policyEntry.TargetID, pinnedCommit)
+		if err != nil {
+			return err
+		}
+		if !knowsCommit {
+			return fmt.Errorf("%w: current policy '%s' does not descend from pinned policy commit '%s'", ErrPolicyPinMismatch, policyEntry.TargetID.String(), pins.PolicyCommitID)
+		}
+	}
+
+	if len(pins.RootKeyIDs) > 0 {
+		state, err := LoadStateForEntry(ctx, repo, policyEntry)
+		if err != nil {
+			return err
+		}
+
+		for _, keyID := range pins.RootKeyIDs {
+			if err := state.verifyRootSignedByKey(ctx, keyID); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// verifyRootSignedByKey checks that the root metadata has a valid signature
+// from the root public key with the specified ID. The ID is derived from the
+// key's contents rather than trusting the ID recorded in the policy, so that a
+// different key cannot masquerade as the pinned key.
+func (s *State) verifyRootSignedByKey(ctx context.Context, keyID string) error {
+	for _, key := range s.RootPublicKeys {
+		if key.KeyID != keyID {
+			continue
+		}
+
+		derivedKeyID, err := deriveKeyID(key)
+		if err != nil {
+			return err
+		}
+		if derivedKeyID != keyID {
+			return fmt.Errorf("%w: root key '%s' does not match its contents", ErrPolicyPinMismatch, keyID)
+		}
+
+		verifier, err := signerverifier.NewSignerVerifierFromTUFKey(key)
+		if err != nil {
+			return err
+		}
+
+		if err := dsse.VerifyEnvelope(ctx, s.RootEnvelope, []sslibdsse.Verifier{verifier}, 1); err != nil {
+			return fmt.Errorf("%w: root metadata is not signed by pinned key '%s'", ErrPolicyPinMismatch, keyID)
+		}
+
+		return nil
+	}
+
+	return fmt.Errorf("%w: pinned key '%s' is not a root key", ErrPolicyPinMismatch, keyID)
+}
+
+// deriveKeyID calculates the ID of the key from its contents.
+func deriveKeyID(key *tuf.Key) (string, error) {
+	keyCopy := *key
+	keyCopy.KeyID = ""
+
+	keyBytes, err := json.Marshal(&keyCopy)
+	if err != nil {
+		return "", err
+	}
+
+	derivedKey, err := tuf.LoadKeyFromBytes(keyBytes)
+	if err != nil {
+		return "", err
+	}
+
+	return derivedKey.KeyID, nil
+}
@@ -0,0 +1,146 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing"
+	"github.com/gittuf/gittuf/internal/tuf"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckPolicyPins(t *testing.T) {
+	repo, state := createTestRepository(t, createTestStateWithPolicy)
+
+	firstPolicyRef, err := repo.Reference(plumbing.ReferenceName(PolicyRef), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := state.Commit(testCtx, repo, "Update test state", false); err != nil {
+		t.Fatal(err)
+	}
+
+	currentPolicyRef, err := repo.Reference(plumbing.ReferenceName(PolicyRef), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rslRef, err := repo.Reference(plumbing.ReferenceName(rsl.Ref), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rootKey, err := tuf.LoadKeyFromBytes(rootPubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targetsKeyBytes, err := os.ReadFile(filepath.Join("test-data", "targets-1.pub"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	targetsKey, err := tuf.LoadKeyFromBytes(targetsKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		pins *PolicyPins
+		err  error
+	}{
+		"no pins": {
+			pins: nil,
+		},
+		"empty pins": {
+			pins: &PolicyPins{},
+		},
+		"current policy commit": {
+			pins: &PolicyPins{PolicyCommitID: currentPolicyRef.Hash().String()},
+		},
+		"earlier policy commit": {
+			pins: &PolicyPins{PolicyCommitID: firstPolicyRef.Hash().String()},
+		},
+		"root key": {
+			pins: &PolicyPins{RootKeyIDs: []string{rootKey.KeyID}},
+		},
+		"policy commit and root key": {
+			pins: &PolicyPins{PolicyCommitID: firstPolicyRef.Hash().String(), RootKeyIDs: []string{rootKey.KeyID}},
+		},
+		"unrelated commit": {
+			pins: &PolicyPins{PolicyCommitID: rslRef.Hash().String()},
+			err:  ErrPolicyPinMismatch,
+		},
+		"unknown commit": {
+			pins: &PolicyPins{PolicyCommitID: plumbing.ZeroHash.String()},
+			err:  ErrPolicyPinMismatch,
+		},
+		"invalid commit ID": {
+			pins: &PolicyPins{PolicyCommitID: "not-a-commit"},
+			err:  ErrPolicyPinMismatch,
+		},
+		"unknown root key": {
+			pins: &PolicyPins{RootKeyIDs: []string{targetsKey.KeyID}},
+			err:  ErrPolicyPinMismatch,
+		},
+		"one of several root keys unknown": {
+			pins: &PolicyPins{RootKeyIDs: []string{rootKey.KeyID, targetsKey.KeyID}},
+			err:  ErrPolicyPinMismatch,
+		},
+	}
+
+	for name, test := range tests {
+		err := CheckPolicyPins(testCtx, repo, test.pins)
+		if test.err == nil {
+			assert.Nil(t, err, name)
+		} else {
+			assert.ErrorIs(t, err, test.err, name)
+		}
+	}
+}
+
+func TestVerifyRootSignedByKey(t *testing.T) {
+	rootKey, err := tuf.LoadKeyFromBytes(rootPubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targetsKeyBytes, err := os.ReadFile(filepath.Join("test-data", "targets-1.pub"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	targetsKey, err := tuf.LoadKeyFromBytes(targetsKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("root key signed root metadata", func(t *testing.T) {
+		state := createTestStateWithPolicy(t)
+
+		err := state.verifyRootSignedByKey(testCtx, rootKey.KeyID)
+		assert.Nil(t, err)
+	})
+
+	t.Run("root key did not sign root metadata", func(t *testing.T) {
+		state := createTestStateWithPolicy(t)
+		state.RootPublicKeys = append(state.RootPublicKeys, targetsKey)
+
+		err := state.verifyRootSignedByKey(testCtx, targetsKey.KeyID)
+		assert.ErrorIs(t, err, ErrPolicyPinMismatch)
+	})
+
+	t.Run("key ID does not match key", func(t *testing.T) {
+		state := createTestStateWithPolicy(t)
+
+		spoofedKey := *targetsKey
+		spoofedKey.KeyID = rootKey.KeyID
+		state.RootPublicKeys = []*tuf.Key{&spoofedKey}
+
+		err := state.verifyRootSignedByKey(testCtx, rootKey.KeyID)
+		assert.ErrorIs(t, err, ErrPolicyPinMismatch)
+	})
+}
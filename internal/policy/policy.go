@@ -5,12 +5,17 @@ package policy
 import (
 	"errors"
 
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/logging"
 	"github.com/gittuf/gittuf/internal/rsl"
 	"github.com/gittuf/gittuf/internal/signerverifier"
 	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
@@ -19,6 +24,7 @@ import (
 	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing/filemode"
 	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing/object"
 	"github.com/gittuf/gittuf/internal/tuf"
+	"github.com/jonboulle/clockwork"
 	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
 )
 
@@ -35,6 +41,12 @@ const (
 	// TargetsRoleName defines the expected name for the top level gittuf policy file.
 	TargetsRoleName = "targets"
 
+	// RSLWriterRoleName defines the name of the role in the root of trust whose keys are trusted to sign RSL entries.
+	RSLWriterRoleName = "rsl-writer"
+
+	// EmergencyRoleName defines the name of the role in the root of trust whose keys are trusted to authorize ref updates that the policy's rules reject, i.e., break-glass access.
+	EmergencyRoleName = "emergency"
+
 	// DefaultCommitMessage defines the fallback message to use when updating the policy ref if an action specific message is unavailable.
 	DefaultCommitMessage = "Update policy state"
 
@@ -48,10 +60,69 @@ var (
 	ErrDanglingDelegationMetadata = errors.New("unreachable targets metadata found")
 	ErrNotRSLEntry                = errors.New("RSL entry expected, annotation found instead")
 	ErrDelegationNotFound         = errors.New("required delegation entry not found")
+	ErrKeyNotFound                = errors.New("key referenced by role not found in metadata")
+	ErrPathDenied                 = errors.New("path is protected by a deny rule")
+	ErrPolicyRefNotFound          = errors.New("policy ref not found")
+	ErrPolicyNotInitialized       = errors.New("policy has not been initialized, no policy commits recorded")
+	ErrMetadataVersionNotBumped   = errors.New("modified metadata does not increment version of committed metadata")
+	ErrNoVersionBumpSigners       = errors.New("no signers provided to re-sign metadata after version bump")
+	ErrRootKeysMismatch           = errors.New("root public keys in policy's keys tree do not match root metadata")
+	ErrRuleNameConflictsWithRole  = errors.New("rule has the same name as a top level policy in the root of trust")
+	ErrEmergencyKeyNotDistinct    = errors.New("emergency role key is also trusted by another role in the root of trust")
+	ErrDelegationEscalatesScope   = errors.New("rule protects namespaces that are not protected by the rule delegating to it")
+	ErrRuleNameInMultiplePolicies = errors.New("rule name is declared in more than one top level policy")
+	ErrConflictingKeyIDs          = errors.New("key ID refers to different keys in different top level policies")
+	ErrInvalidTopLevelPolicy      = errors.New("top level policy is not declared correctly in the root of trust")
+	ErrRuleExpired                = errors.New("namespace is only protected by expired rules")
 )
 
 var ErrPolicyExists = errors.New("cannot initialize Policy namespace as it exists already")
 
+// InvalidPolicyTreeError describes the problems with an invalid policy tree.
+// It wraps ErrInvalidPolicyTree.
+type InvalidPolicyTreeError struct {
+	// EmptyTree indicates the policy tree is Git's empty tree, which gittuf
+	// does not use to record a policy.
+	EmptyTree bool
+
+	// UnexpectedEntries contains the names of unexpected entries in the root
+	// of the policy tree.
+	UnexpectedEntries []string
+
+	// MissingMetadataTree indicates the policy tree has no metadata subtree.
+	MissingMetadataTree bool
+
+	// MissingKeysTree indicates the policy tree has no keys subtree.
+	MissingKeysTree bool
+}
+
+func (e *InvalidPolicyTreeError) Error() string {
+	if e.EmptyTree {
+		return fmt.Sprintf("%s: policy tree is the empty tree, expected '%s' and '%s' trees", ErrInvalidPolicyTree.Error(), metadataTreeEntryName, rootPublicKeysTreeEntryName)
+	}
+
+	problems := []string{}
+	if len(e.UnexpectedEntries) > 0 {
+		problems = append(problems, fmt.Sprintf("unexpected entries '%s'", strings.Join(e.UnexpectedEntries, "', '")))
+	}
+	if e.MissingMetadataTree {
+		problems = append(problems, fmt.Sprintf("missing '%s' tree", metadataTreeEntryName))
+	}
+	if e.MissingKeysTree {
+		problems = append(problems, fmt.Sprintf("missing '%s' tree", rootPublicKeysTreeEntryName))
+	}
+
+	return fmt.Sprintf("%s: %s", ErrInvalidPolicyTree.Error(), strings.Join(problems, ", "))
+}
+
+func (e *InvalidPolicyTreeError) Unwrap() error {
+	return ErrInvalidPolicyTree
+}
+
+func (e *InvalidPolicyTreeError) isInvalid() bool {
+	return e.EmptyTree || len(e.UnexpectedEntries) > 0 || e.MissingMetadataTree || e.MissingKeysTree
+}
+
 // InitializeNamespace creates a git ref for the policy. Initially, the entry
 // has a zero hash.
 func InitializeNamespace(repo *git.Repository) error {
@@ -81,6 +152,56 @@ type State struct {
 	TargetsEnvelope     *sslibdsse.Envelope
 	DelegationEnvelopes map[string]*sslibdsse.Envelope
 	RootPublicKeys      []*tuf.Key
+
+	// clock determines whether rules have expired when the policy is
+	// evaluated. If it is not set, the current time is used. See
+	// State.ForEvaluation.
+	clock clockwork.Clock
+}
+
+// EvaluationOptions contains the optional behavior used to evaluate the rules
+// in a policy.
+type EvaluationOptions struct {
+	Clock clockwork.Clock
+}
+
+// EvaluationOption is used to configure State.ForEvaluation.
+type EvaluationOption func(*EvaluationOptions)
+
+// WithEvaluationClock configures the clock used to determine whether rules in
+// the policy have expired.
+func WithEvaluationClock(clock clockwork.Clock) EvaluationOption {
+	return func(o *EvaluationOptions) {
+		o.Clock = clock
+	}
+}
+
+// WithEvaluationTime configures the rules in the policy to be evaluated as of
+// the specified time, such as the time an RSL entry was recorded.
+func WithEvaluationTime(evaluationTime time.Time) EvaluationOption {
+	return WithEvaluationClock(clockwork.NewFakeClockAt(evaluationTime))
+}
+
+// ForEvaluation returns a copy of the State that evaluates its rules using the
+// specified options. The copy shares the State's metadata, so neither must be
+// modified while the other is in use.
+func (s *State) ForEvaluation(opts ...EvaluationOption) *State {
+	options := &EvaluationOptions{Clock: s.clock}
+	for _, fn := range opts {
+		fn(options)
+	}
+
+	state := *s
+	state.clock = options.Clock
+	return &state
+}
+
+// now returns the time the rules in the policy are evaluated at.
+func (s *State) now() time.Time {
+	if s.clock == nil {
+		return time.Now()
+	}
+	return s.clock.Now()
 }
 
 // LoadState returns the State of the repository's policy corresponding to the
@@ -95,19 +216,109 @@ func LoadState(ctx context.Context, repo *git.Repository, rslEntryID plumbing.Ha
 }
 
 // LoadCurrentState returns the State corresponding to the repository's current
-// active policy.
+// active policy. If no policy has been recorded yet, such as right after the
+// policy namespace is initialized, ErrPolicyNotInitialized is returned.
 func LoadCurrentState(ctx context.Context, repo *git.Repository) (*State, error) {
 	e, _, err := rsl.GetLatestReferenceEntryForRef(repo, PolicyRef)
 	if err != nil {
+		if errors.Is(err, rsl.ErrRSLEntryNotFound) {
+			// This is expected right after the policy and RSL namespaces are
+			// initialized
+			return nil, errors.Join(ErrPolicyNotInitialized, err)
+		}
 		return nil, err
 	}
 
 	return LoadStateForEntry(ctx, repo, e)
 }
 
+// LoadStateFromRef returns the State recorded at the tip of the specified
+// policy-shaped ref. Unlike LoadCurrentState, the ref's tip is not looked up in
+// the RSL, so this can be used to review policy that hasn't been merged into
+// the local policy ref, such as the policy in a remote tracker ref. The State
+// is verified before it is returned. If the ref does not exist or is empty,
+// ErrPolicyRefNotFound is returned.
+func LoadStateFromRef(ctx context.Context, repo *git.Repository, refName string) (*State, error) {
+	tipID, err := gitinterface.GetTip(repo, refName)
+	if err != nil {
+		if errors.Is(err, plumbing.ErrReferenceNotFound) {
+			return nil, fmt.Errorf("%w: '%s'", ErrPolicyRefNotFound, refName)
+		}
+		return nil, err
+	}
+	if tipID.IsZero() {
+		return nil, fmt.Errorf("%w: '%s' has no policy commits", ErrPolicyRefNotFound, refName)
+	}
+
+	policyCommit, err := repo.CommitObject(tipID)
+	if err != nil {
+		return nil, err
+	}
+
+	return loadStateForCommit(ctx, repo, policyCommit, true)
+}
+
+// RemoteTrackerRef returns the remote tracking ref for the policy namespace on
+// the specified remote. For example, for 'origin', the remote tracker ref is
+// 'refs/remotes/origin/gittuf/policy'.
+func RemoteTrackerRef(remote string) string {
+	return gitinterface.RemoteRef(PolicyRef, remote)
+}
+
 // LoadStateForEntry returns the State for a specified RSL entry for the policy
-// namespace.
+// namespace. If the entry does not record a policy commit, i.e., its target is
+// the zero hash, ErrPolicyNotInitialized is returned. If the entry's target is
+// the empty tree, or the policy commit's tree does not contain exactly the
+// metadata and keys trees, an error wrapping ErrInvalidPolicyTree is returned.
 func LoadStateForEntry(ctx context.Context, repo *git.Repository, e rsl.Entry) (*State, error) {
+	return loadStateForEntry(ctx, repo, e, true)
+}
+
+// loadStateForEntry returns the State for a specified RSL entry for the policy
+// namespace. The State is only verified if verify is set.
+func loadStateForEntry(ctx context.Context, repo *git.Repository, e rsl.Entry, verify bool) (*State, error) {
+	policyCommit, err := getPolicyCommitForEntry(ctx, repo, e)
+	if err != nil {
+		return nil, err
+	}
+
+	return loadStateForCommit(ctx, repo, policyCommit, verify)
+}
+
+// loadStateForCommit returns the State recorded in the specified policy commit.
+// The State is only verified if verify is set.
+func loadStateForCommit(ctx context.Context, repo *git.Repository, policyCommit *object.Commit, verify bool) (*State, error) {
+	state, err := readPolicyTree(ctx, repo, policyCommit, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if verify {
+		if err := state.Verify(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	return state, nil
+}
+
+// RecoverPolicyState is a best-effort loader for the policy state recorded in
+// the specified RSL entry, meant for diagnosing corrupted policy refs. Unlike
+// LoadStateForEntry, it does not stop at the first problem with the policy
+// tree and it does not verify the loaded State. The returned State contains all
+// the metadata and keys that could be loaded, while the returned error
+// aggregates all the problems encountered. Note that the State may be returned
+// alongside an error, and it must not be trusted for verification.
+func RecoverPolicyState(ctx context.Context, repo *git.Repository, e rsl.Entry) (*State, error) {
+	policyCommit, err := getPolicyCommitForEntry(ctx, repo, e)
+	if err != nil {
+		return nil, err
+	}
+
+	return readPolicyTree(ctx, repo, policyCommit, true)
+}
+
+func getPolicyCommitForEntry(ctx context.Context, repo *git.Repository, e rsl.Entry) (*object.Commit, error) {
 	entry, ok := e.(*rsl.ReferenceEntry)
 	if !ok {
 		return nil, ErrNotRSLEntry
@@ -117,23 +328,35 @@ func LoadStateForEntry(ctx context.Context, repo *git.Repository, e rsl.Entry) (
 		return nil, rsl.ErrRSLEntryDoesNotMatchRef
 	}
 
-	policyCommit, err := repo.CommitObject(entry.TargetID)
-	if err != nil {
-		return nil, err
+	// An entry may record the policy ref in its initialized state, without any
+	// policy commits
+	if entry.TargetID.IsZero() {
+		return nil, fmt.Errorf("%w: entry '%s' records no policy commit", ErrPolicyNotInitialized, entry.ID.String())
 	}
 
-	policyRootTree, err := repo.TreeObject(policyCommit.TreeHash)
-	if err != nil {
-		return nil, err
+	// The policy ref must point to a commit, the empty tree is not a valid
+	// target and must not be mistaken for an uninitialized policy
+	if entry.TargetID == gitinterface.EmptyTree() {
+		return nil, fmt.Errorf("entry '%s' records the empty tree instead of a policy commit: %w", entry.ID.String(), &InvalidPolicyTreeError{EmptyTree: true})
 	}
 
-	if len(policyRootTree.Entries) > 2 {
-		return nil, ErrInvalidPolicyTree
+	return gitinterface.ReadCommit(ctx, repo, entry.TargetID)
+}
+
+// readPolicyTree loads the metadata and keys in the policy commit's tree. In
+// best effort mode, problems are accumulated rather than returned immediately,
+// and a State is always returned with whatever could be loaded. Reads from the
+// object store are abandoned if ctx is done, irrespective of bestEffort.
+func readPolicyTree(ctx context.Context, repo *git.Repository, policyCommit *object.Commit, bestEffort bool) (*State, error) {
+	policyRootTree, err := gitinterface.ReadTree(ctx, repo, policyCommit.TreeHash)
+	if err != nil {
+		return nil, err
 	}
 
 	var (
 		metadataTreeID plumbing.Hash
 		keysTreeID     plumbing.Hash
+		treeErr        = &InvalidPolicyTreeError{EmptyTree: policyCommit.TreeHash == gitinterface.EmptyTree()}
 	)
 
 	for _, e := range policyRootTree.Entries {
@@ -143,70 +366,204 @@ func LoadStateForEntry(ctx context.Context, repo *git.Repository, e rsl.Entry) (
 		case rootPublicKeysTreeEntryName:
 			keysTreeID = e.Hash
 		default:
-			return nil, ErrInvalidPolicyTree
+			treeErr.UnexpectedEntries = append(treeErr.UnexpectedEntries, e.Name)
 		}
 	}
+	treeErr.MissingMetadataTree = metadataTreeID.IsZero()
+	treeErr.MissingKeysTree = keysTreeID.IsZero()
+
+	errs := []error{}
+	if treeErr.isInvalid() {
+		if !bestEffort {
+			return nil, treeErr
+		}
+		errs = append(errs, treeErr)
+	}
 
 	state := &State{}
 
-	metadataTree, err := repo.TreeObject(metadataTreeID)
+	if !metadataTreeID.IsZero() {
+		metadataTree, err := gitinterface.ReadTree(ctx, repo, metadataTreeID)
+		if err != nil {
+			if !bestEffort || ctx.Err() != nil {
+				return nil, err
+			}
+			errs = append(errs, fmt.Errorf("unable to load metadata tree: %w", err))
+		} else {
+			for _, entry := range metadataTree.Entries {
+				env, err := readEnvelope(ctx, repo, entry.Hash)
+				if err != nil {
+					if !bestEffort || ctx.Err() != nil {
+						return nil, err
+					}
+					errs = append(errs, fmt.Errorf("unable to load metadata '%s': %w", entry.Name, err))
+					continue
+				}
+
+				switch entry.Name {
+				case fmt.Sprintf("%s.json", RootRoleName):
+					state.RootEnvelope = env
+				case fmt.Sprintf("%s.json", TargetsRoleName):
+					state.TargetsEnvelope = env
+				default:
+					if state.DelegationEnvelopes == nil {
+						state.DelegationEnvelopes = map[string]*sslibdsse.Envelope{}
+					}
+
+					state.DelegationEnvelopes[strings.TrimSuffix(entry.Name, ".json")] = env
+				}
+			}
+		}
+	}
+
+	if !keysTreeID.IsZero() {
+		keysTree, err := gitinterface.ReadTree(ctx, repo, keysTreeID)
+		if err != nil {
+			if !bestEffort || ctx.Err() != nil {
+				return nil, err
+			}
+			errs = append(errs, fmt.Errorf("unable to load keys tree: %w", err))
+		} else {
+			for _, entry := range keysTree.Entries {
+				key, err := readKey(ctx, repo, entry.Hash)
+				if err != nil {
+					if !bestEffort || ctx.Err() != nil {
+						return nil, err
+					}
+					errs = append(errs, fmt.Errorf("unable to load key '%s': %w", entry.Name, err))
+					continue
+				}
+
+				if state.RootPublicKeys == nil {
+					state.RootPublicKeys = []*tuf.Key{}
+				}
+
+				state.RootPublicKeys = append(state.RootPublicKeys, key)
+			}
+		}
+	}
+
+	return state, errors.Join(errs...)
+}
+
+func readEnvelope(ctx context.Context, repo *git.Repository, blobID plumbing.Hash) (*sslibdsse.Envelope, error) {
+	contents, err := gitinterface.ReadBlob(ctx, repo, blobID)
 	if err != nil {
 		return nil, err
 	}
 
-	keysTree, err := repo.TreeObject(keysTreeID)
+	env := &sslibdsse.Envelope{}
+	if err := json.Unmarshal(contents, env); err != nil {
+		return nil, err
+	}
+
+	return env, nil
+}
+
+func readKey(ctx context.Context, repo *git.Repository, blobID plumbing.Hash) (*tuf.Key, error) {
+	contents, err := gitinterface.ReadBlob(ctx, repo, blobID)
 	if err != nil {
 		return nil, err
 	}
 
-	for _, entry := range metadataTree.Entries {
-		contents, err := gitinterface.ReadBlob(repo, entry.Hash)
-		if err != nil {
-			return nil, err
-		}
+	return tuf.LoadKeyFromBytes(contents)
+}
 
-		env := &sslibdsse.Envelope{}
-		if err := json.Unmarshal(contents, env); err != nil {
-			return nil, err
-		}
+// committedBlob records a blob in the tree of the policy ref's tip along with
+// the JSON encoding of the object it stores.
+type committedBlob struct {
+	id       plumbing.Hash
+	contents []byte
+}
 
-		switch entry.Name {
-		case fmt.Sprintf("%s.json", RootRoleName):
-			state.RootEnvelope = env
-		case fmt.Sprintf("%s.json", TargetsRoleName):
-			state.TargetsEnvelope = env
-		default:
-			if state.DelegationEnvelopes == nil {
-				state.DelegationEnvelopes = map[string]*sslibdsse.Envelope{}
-			}
+// committedBlobs records the metadata and key blobs in the tree of the policy
+// ref's tip, indexed by role name and key ID respectively.
+type committedBlobs struct {
+	metadata map[string]committedBlob
+	keys     map[string]committedBlob
+}
 
-			state.DelegationEnvelopes[strings.TrimSuffix(entry.Name, ".json")] = env
-		}
+// getCommittedBlobs returns the blobs in the tree of the policy ref's tip so
+// that State.Commit can reuse them for metadata and keys that are unchanged.
+// The contents recorded for each blob are re-encoded from the decoded object so
+// that they can be compared with the State irrespective of how the blob was
+// serialized when it was written. If the policy ref does not exist yet or its
+// tree cannot be read, no blobs are returned.
+func getCommittedBlobs(ctx context.Context, repo *git.Repository) (*committedBlobs, error) {
+	committed := &committedBlobs{
+		metadata: map[string]committedBlob{},
+		keys:     map[string]committedBlob{},
 	}
 
-	for _, entry := range keysTree.Entries {
-		contents, err := gitinterface.ReadBlob(repo, entry.Hash)
-		if err != nil {
-			return nil, err
+	tipID, err := gitinterface.GetTip(repo, PolicyRef)
+	if err != nil {
+		if errors.Is(err, plumbing.ErrReferenceNotFound) {
+			return committed, nil
 		}
+		return nil, err
+	}
+	if tipID.IsZero() {
+		return committed, nil
+	}
+
+	policyCommit, err := repo.CommitObject(tipID)
+	if err != nil {
+		return nil, err
+	}
+	policyRootTree, err := repo.TreeObject(policyCommit.TreeHash)
+	if err != nil {
+		return nil, err
+	}
 
-		key, err := tuf.LoadKeyFromBytes(contents)
+	for _, e := range policyRootTree.Entries {
+		tree, err := repo.TreeObject(e.Hash)
 		if err != nil {
-			return nil, err
+			// The tip's tree is invalid, there is nothing to reuse
+			continue
 		}
 
-		if state.RootPublicKeys == nil {
-			state.RootPublicKeys = []*tuf.Key{}
-		}
+		switch e.Name {
+		case metadataTreeEntryName:
+			for _, entry := range tree.Entries {
+				env, err := readEnvelope(ctx, repo, entry.Hash)
+				if err != nil {
+					continue
+				}
+				contents, err := json.Marshal(env)
+				if err != nil {
+					return nil, err
+				}
+
+				committed.metadata[strings.TrimSuffix(entry.Name, ".json")] = committedBlob{id: entry.Hash, contents: contents}
+			}
+		case rootPublicKeysTreeEntryName:
+			for _, entry := range tree.Entries {
+				key, err := readKey(ctx, repo, entry.Hash)
+				if err != nil {
+					continue
+				}
+				contents, err := json.Marshal(key)
+				if err != nil {
+					return nil, err
+				}
 
-		state.RootPublicKeys = append(state.RootPublicKeys, key)
+				committed.keys[key.KeyID] = committedBlob{id: entry.Hash, contents: contents}
+			}
+		}
 	}
 
-	if err := state.Verify(ctx); err != nil {
-		return nil, err
+	return committed, nil
+}
+
+// writePolicyBlob writes the contents of a metadata or key file to the
+// repository. If the contents are unchanged from the committed blob, the
+// committed blob is reused instead.
+func writePolicyBlob(repo *git.Repository, contents []byte, committed committedBlob) (plumbing.Hash, error) {
+	if !committed.id.IsZero() && bytes.Equal(committed.contents, contents) {
+		return committed.id, nil
 	}
 
-	return state, nil
+	return gitinterface.WriteBlob(repo, contents)
 }
 
 // GetStateForCommit scans the RSL to identify the first time a commit was seen
@@ -216,20 +573,52 @@ func LoadStateForEntry(ctx context.Context, repo *git.Repository, e rsl.Entry) (
 // error is returned. Identifying the policy in this case is left to the calling
 // workflow.
 func GetStateForCommit(ctx context.Context, repo *git.Repository, commit *object.Commit) (*State, error) {
+	state, _, err := getStateForCommit(ctx, repo, commit)
+	return state, err
+}
+
+// getStateEvaluatedForCommit returns the State for the commit as described for
+// GetStateForCommit, evaluated as of the time of the RSL entry that first
+// recorded the commit. This ensures rule expiry and key validity windows are
+// applied as of when the commit was first recorded rather than when the
+// verification is performed or the commit's own timestamps.
+func getStateEvaluatedForCommit(ctx context.Context, repo *git.Repository, commit *object.Commit) (*State, error) {
+	state, firstSeenTime, err := getStateForCommit(ctx, repo, commit)
+	if err != nil || state == nil {
+		return nil, err
+	}
+
+	return state.ForEvaluation(WithEvaluationTime(firstSeenTime)), nil
+}
+
+// getStateForCommit returns the State for the commit as described for
+// GetStateForCommit, along with the time of the RSL entry that first recorded
+// the commit.
+func getStateForCommit(ctx context.Context, repo *git.Repository, commit *object.Commit) (*State, time.Time, error) {
 	firstSeenEntry, _, err := rsl.GetFirstReferenceEntryForCommit(repo, commit)
 	if err != nil {
 		if errors.Is(err, rsl.ErrNoRecordOfCommit) {
-			return nil, nil
+			return nil, time.Time{}, nil
 		}
-		return nil, err
+		return nil, time.Time{}, err
 	}
 
 	commitPolicyEntry, _, err := rsl.GetLatestReferenceEntryForRefBefore(repo, PolicyRef, firstSeenEntry.ID)
 	if err != nil {
-		return nil, err
+		return nil, time.Time{}, err
+	}
+
+	state, err := LoadStateForEntry(ctx, repo, commitPolicyEntry)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	firstSeenEntryObj, err := repo.CommitObject(firstSeenEntry.ID)
+	if err != nil {
+		return nil, time.Time{}, err
 	}
 
-	return LoadStateForEntry(ctx, repo, commitPolicyEntry)
+	return state, firstSeenEntryObj.Committer.When, nil
 }
 
 // PublicKeys returns all the public keys associated with a state.
@@ -252,33 +641,81 @@ func (s *State) PublicKeys() (map[string]*tuf.Key, error) {
 		allKeys[keyID] = key
 	}
 
-	// Add keys from top level targets metadata
-	if s.TargetsEnvelope == nil {
+	// Add keys from top level and delegated targets metadata
+	roleNames := s.targetsRoleNames()
+	if len(roleNames) == 0 {
 		// Early states where this hasn't been initialized yet
 		return nil, err
 	}
-	targetsMetadata, err := s.GetTargetsMetadata(TargetsRoleName)
+	for _, roleName := range roleNames {
+		targetsMetadata, err := s.GetTargetsMetadata(roleName)
+		if err != nil {
+			return nil, err
+		}
+		for keyID, key := range targetsMetadata.Delegations.Keys {
+			key := key
+			allKeys[keyID] = key
+		}
+	}
+
+	return allKeys, nil
+}
+
+// UnreferencedKeys returns the IDs of keys in the State that are not
+// referenced by any role in the root metadata or by any delegation in the
+// targets metadata. The keys considered are the root public keys, the keys in
+// the root metadata, and the keys in the delegations of each targets metadata.
+// The IDs are returned in sorted order, and can be used to prune the unused
+// keys from the policy.
+func (s *State) UnreferencedKeys() ([]string, error) {
+	allKeyIDs := map[string]bool{}
+	referencedKeyIDs := map[string]bool{}
+
+	for _, key := range s.RootPublicKeys {
+		allKeyIDs[key.KeyID] = true
+	}
+
+	rootMetadata, err := s.GetRootMetadata()
 	if err != nil {
 		return nil, err
 	}
-	for keyID, key := range targetsMetadata.Delegations.Keys {
-		key := key
-		allKeys[keyID] = key
+	for keyID := range rootMetadata.Keys {
+		allKeyIDs[keyID] = true
+	}
+	for _, role := range rootMetadata.Roles {
+		for _, keyID := range role.KeyIDs {
+			referencedKeyIDs[keyID] = true
+		}
 	}
 
-	// Add keys from delegated targets metadata
-	for roleName := range s.DelegationEnvelopes {
-		delegatedMetadata, err := s.GetTargetsMetadata(roleName)
+	for _, roleName := range s.targetsRoleNames() {
+		targetsMetadata, err := s.GetTargetsMetadata(roleName)
 		if err != nil {
 			return nil, err
 		}
-		for keyID, key := range delegatedMetadata.Delegations.Keys {
-			key := key
-			allKeys[keyID] = key
+		if targetsMetadata.Delegations == nil {
+			continue
+		}
+
+		for keyID := range targetsMetadata.Delegations.Keys {
+			allKeyIDs[keyID] = true
+		}
+		for _, delegation := range targetsMetadata.Delegations.Roles {
+			for _, keyID := range delegation.KeyIDs {
+				referencedKeyIDs[keyID] = true
+			}
 		}
 	}
 
-	return allKeys, nil
+	unreferencedKeyIDs := []string{}
+	for keyID := range allKeyIDs {
+		if !referencedKeyIDs[keyID] {
+			unreferencedKeyIDs = append(unreferencedKeyIDs, keyID)
+		}
+	}
+	sort.Strings(unreferencedKeyIDs)
+
+	return unreferencedKeyIDs, nil
 }
 
 // FindAuthorizedSigningKeyIDs traverses the policy metadata to identify the
@@ -297,12 +734,13 @@ func (s *State) FindAuthorizedSigningKeyIDs(ctx context.Context, roleName string
 		return rootMetadata.Roles[RootRoleName].KeyIDs, nil
 	}
 
-	if roleName == TargetsRoleName {
-		if _, ok := rootMetadata.Roles[TargetsRoleName]; !ok {
+	if isTopLevelPolicy(rootMetadata, roleName) {
+		if role, ok := rootMetadata.Roles[roleName]; ok {
+			return role.KeyIDs, nil
+		}
+		if roleName == TargetsRoleName {
 			return nil, ErrDelegationNotFound
 		}
-
-		return rootMetadata.Roles[TargetsRoleName].KeyIDs, nil
 	}
 
 	entry, err := s.findDelegationEntry(roleName)
@@ -313,63 +751,558 @@ func (s *State) FindAuthorizedSigningKeyIDs(ctx context.Context, roleName string
 	return entry.KeyIDs, nil
 }
 
-// FindPublicKeysForPath identifies the trusted keys for the path. If the path
-// protected in gittuf policy, the trusted keys are returned.
-func (s *State) FindPublicKeysForPath(ctx context.Context, path string) ([]*tuf.Key, error) {
-	if err := s.Verify(ctx); err != nil {
-		return nil, err
-	}
-
-	targetsMetadata, err := s.GetTargetsMetadata(TargetsRoleName)
+// FindRSLWriterKeys returns the public keys trusted to sign RSL entries. If the
+// root of trust does not declare the RSL writer role, no keys are returned and
+// RSL entries may be signed by any key.
+func (s *State) FindRSLWriterKeys() ([]*tuf.Key, error) {
+	rootMetadata, err := s.GetRootMetadata()
 	if err != nil {
 		return nil, err
 	}
 
-	allPublicKeys := targetsMetadata.Delegations.Keys
-	delegationsQueue := targetsMetadata.Delegations.Roles
+	role, ok := rootMetadata.Roles[RSLWriterRoleName]
+	if !ok {
+		return nil, nil
+	}
 
-	trustedKeys := []*tuf.Key{}
-	for {
-		if len(delegationsQueue) <= 1 {
-			return trustedKeys, nil
+	keys := []*tuf.Key{}
+	for _, keyID := range role.KeyIDs {
+		key, ok := rootMetadata.Keys[keyID]
+		if !ok {
+			return nil, fmt.Errorf("%w: '%s'", ErrKeyNotFound, keyID)
 		}
 
-		delegation := delegationsQueue[0]
-		delegationsQueue = delegationsQueue[1:]
+		keys = append(keys, key)
+	}
 
-		if delegation.Matches(path) {
-			for _, keyID := range delegation.KeyIDs {
-				key := allPublicKeys[keyID]
-				trustedKeys = append(trustedKeys, key)
-			}
+	return keys, nil
+}
 
-			if s.HasTargetsRole(delegation.Name) {
-				delegatedMetadata, err := s.GetTargetsMetadata(delegation.Name)
-				if err != nil {
-					return nil, err
-				}
-				for keyID, key := range delegatedMetadata.Delegations.Keys {
-					allPublicKeys[keyID] = key
-				}
+// FindEmergencyKeys returns the public keys of the root of trust's emergency
+// role, along with the number of them that must authorize the use of
+// break-glass access. The threshold returned is never lower than the minimum
+// emergency threshold, even if the root of trust declares a lower one. If the
+// root of trust does not declare the emergency role, no keys are returned and
+// break-glass access is unavailable.
+func (s *State) FindEmergencyKeys() ([]*tuf.Key, int, error) {
+	rootMetadata, err := s.GetRootMetadata()
+	if err != nil {
+		return nil, 0, err
+	}
 
-				if delegation.Terminating {
-					// Remove other delegations from the queue
-					delegationsQueue = delegatedMetadata.Delegations.Roles
-				} else {
-					// Depth first, so newly discovered delegations go first
-					// Also, we skip the allow-rule, so we don't include the
-					// last element in the delegatedMetadata list.
-					delegationsQueue = append(delegatedMetadata.Delegations.Roles[:len(delegatedMetadata.Delegations.Roles)-1], delegationsQueue...)
-				}
-			}
+	role, ok := rootMetadata.Roles[EmergencyRoleName]
+	if !ok {
+		return nil, 0, nil
+	}
+
+	keys := []*tuf.Key{}
+	for _, keyID := range role.KeyIDs {
+		key, ok := rootMetadata.Keys[keyID]
+		if !ok {
+			return nil, 0, fmt.Errorf("%w: '%s'", ErrKeyNotFound, keyID)
 		}
+
+		keys = append(keys, key)
+	}
+
+	threshold := role.Threshold
+	if minThreshold := minEmergencyThreshold(rootMetadata); threshold < minThreshold {
+		threshold = minThreshold
 	}
+
+	return keys, threshold, nil
 }
 
-// Verify performs a self-contained verification of all the metadata in the
-// State starting from the Root. Any metadata that is unreachable in the
+// keyValidityWindow returns the period during which the key with the specified
+// ID is trusted to sign commits, as recorded in the targets metadata of the
+// policy. If more than one set of metadata records a window for the key, the
+// period in which all of them overlap is returned. Zero values indicate the
+// period is unbounded.
+func (s *State) keyValidityWindow(keyID string) (time.Time, time.Time, error) {
+	var notBefore, notAfter time.Time
+
+	for _, roleName := range s.targetsRoleNames() {
+		targetsMetadata, err := s.GetTargetsMetadata(roleName)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		if targetsMetadata.Delegations == nil {
+			continue
+		}
+
+		window, has := targetsMetadata.Delegations.KeyValidity[keyID]
+		if !has || window == nil {
+			continue
+		}
+		if window.NotBefore != nil && window.NotBefore.After(notBefore) {
+			notBefore = *window.NotBefore
+		}
+		if window.NotAfter != nil && (notAfter.IsZero() || window.NotAfter.Before(notAfter)) {
+			notAfter = *window.NotAfter
+		}
+	}
+
+	return notBefore, notAfter, nil
+}
+
+// FindPublicKeysForPath identifies the trusted keys for the path. If the path
+// protected in gittuf policy, the trusted keys are returned. If the path matches
+// a deny rule, ErrPathDenied is returned as no key may be trusted for it. Rules
+// restricted to certain refs are applied irrespective of the ref being changed,
+// see FindPublicKeysForPathOnRef.
+func (s *State) FindPublicKeysForPath(ctx context.Context, path string) ([]*tuf.Key, error) {
+	return s.FindPublicKeysForPathOnRef(ctx, path, "")
+}
+
+// FindPublicKeysForPathOnRef identifies the trusted keys for the path when it
+// is changed on the specified ref. Rules that are restricted to certain refs
+// only apply if refName matches one of their ref patterns. If refName is empty,
+// such rules are applied irrespective of the ref. When the root of trust
+// declares more than one top level policy, only the keys trusted by the policy
+// that owns the path are returned, see findRulesForPath.
+func (s *State) FindPublicKeysForPathOnRef(ctx context.Context, path, refName string) ([]*tuf.Key, error) {
+	authorizedKeys, err := s.FindAuthorizedKeysForPath(ctx, path, refName)
+	if err != nil {
+		return nil, err
+	}
+
+	trustedKeys := []*tuf.Key{}
+	for _, ruleKeys := range authorizedKeys {
+		trustedKeys = append(trustedKeys, ruleKeys.Keys...)
+	}
+
+	return trustedKeys, nil
+}
+
+// FindAuthorizedKeysForPath identifies the rules that contribute trusted keys
+// for the path when it is changed on the specified ref, along with the keys
+// each rule authorizes. The keys returned by FindPublicKeysForPathOnRef are
+// the keys of all the returned rules. The rules are traversed as follows:
+//
+//   - Rules are visited depth first. When a rule matches and has delegated
+//     metadata, the rules in that metadata are visited before the rules that
+//     follow the matching rule.
+//   - Keys accumulate across matching rules. A matching non-terminating rule
+//     contributes its keys, and the matching rules visited after it, whether
+//     delegated by it or declared after it, contribute their keys in addition.
+//     Keys are therefore inherited from each matching rule along the chain of
+//     delegations.
+//   - A matching terminating rule discards every rule that would be visited
+//     after it, other than the rules it delegates to. Keys contributed before
+//     the terminating rule are retained. This applies to terminating rules in
+//     delegated metadata too, which discard the remaining rules of their
+//     ancestors.
+//   - The allow rule at the end of each metadata contributes no keys.
+//   - If a deny rule matches, ErrPathDenied is returned.
+//   - A matching rule that has expired is returned with Expired set, but
+//     contributes no keys, and the rules it delegates to are not visited. It
+//     still discards the rules after it if it is terminating. If every
+//     matching rule has expired, ErrRuleExpired is returned rather than
+//     treating the path as unprotected.
+//
+// When the root of trust declares more than one top level policy, only the
+// rules of the policy that owns the path are returned, see findRulesForPath.
+func (s *State) FindAuthorizedKeysForPath(ctx context.Context, path, refName string) ([]AuthorizedKeys, error) {
+	if err := s.Verify(ctx); err != nil {
+		return nil, err
+	}
+
+	rules, err := s.findRulesForPath(ctx, path, refName)
+	if err != nil {
+		return nil, err
+	}
+	if rules.denyRule != nil {
+		return nil, fmt.Errorf("%w: rule '%s' matches '%s'", ErrPathDenied, rules.denyRule.Name, path)
+	}
+
+	authorizedKeys := make([]AuthorizedKeys, 0, len(rules.delegations))
+	for _, delegation := range rules.delegations {
+		if delegation.IsExpired(s.now()) {
+			authorizedKeys = append(authorizedKeys, AuthorizedKeys{RuleName: delegation.Name, Expired: true, Keys: []*tuf.Key{}})
+			continue
+		}
+
+		ruleKeys := AuthorizedKeys{RuleName: delegation.Name, Keys: []*tuf.Key{}}
+		for _, keyID := range delegation.KeyIDs {
+			key, has := rules.keys[keyID]
+			if !has {
+				return nil, fmt.Errorf("%w: rule '%s' authorizes key '%s'", tuf.ErrDelegationKeyMissing, delegation.Name, keyID)
+			}
+			ruleKeys.Keys = append(ruleKeys.Keys, key)
+		}
+		authorizedKeys = append(authorizedKeys, ruleKeys)
+	}
+
+	if err := checkAuthorizedKeysNotExpired(path, authorizedKeys); err != nil {
+		return nil, err
+	}
+
+	return authorizedKeys, nil
+}
+
+// pathRules records the rules of a top level policy that protect a path, as
+// identified by findRulesForPathInPolicy.
+type pathRules struct {
+	// delegations are the matching rules, in the order they are visited.
+	// Rules that have expired have no key IDs.
+	delegations []tuf.Delegation
+
+	// keys are the keys declared in the metadata visited, keyed by their IDs.
+	keys map[string]*tuf.Key
+
+	// denyRule is the deny rule that matched, if any. When it is set, the
+	// traversal stopped at it, and delegations is empty.
+	denyRule *tuf.Delegation
+}
+
+// findRulesForPath identifies the top level policy that owns the path when it
+// is changed on the specified ref, and returns the rules in it that protect the
+// path. The owning policy is the first top level policy, in the order of
+// precedence recorded in the root of trust, with a rule that matches the path,
+// including deny rules and rules that have expired. The rules of the other top
+// level policies are not considered, so a policy can never authorize keys for,
+// or otherwise change how, a path that an earlier policy protects is verified.
+// If no policy has a rule that matches, no rules are returned.
+func (s *State) findRulesForPath(ctx context.Context, path, refName string) (*pathRules, error) {
+	topLevelRoleNames, err := s.TopLevelTargetsRoleNames()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, roleName := range topLevelRoleNames {
+		rules, err := s.findRulesForPathInPolicy(ctx, roleName, path, refName)
+		if err != nil {
+			return nil, err
+		}
+		if rules.denyRule != nil || len(rules.delegations) > 0 {
+			return rules, nil
+		}
+	}
+
+	return &pathRules{delegations: []tuf.Delegation{}, keys: map[string]*tuf.Key{}}, nil
+}
+
+// findRulesForPathInPolicy traverses the rules starting at the specified top
+// level policy to identify the rules that protect the path on the ref. See
+// FindAuthorizedKeysForPath for the semantics of the traversal. If a deny rule
+// matches, the traversal stops and the deny rule is returned. Matching rules
+// that have expired are returned without any key IDs, and the rules they
+// delegate to are not visited. Only the keys declared in the metadata of the
+// policy are returned.
+func (s *State) findRulesForPathInPolicy(ctx context.Context, topLevelRoleName, path, refName string) (*pathRules, error) {
+	targetsMetadata, err := s.GetTargetsMetadata(topLevelRoleName)
+	if err != nil {
+		return nil, err
+	}
+
+	rules := &pathRules{delegations: []tuf.Delegation{}, keys: map[string]*tuf.Key{}}
+	for keyID, key := range targetsMetadata.Delegations.Keys {
+		rules.keys[keyID] = key
+	}
+	delegationsQueue := targetsMetadata.Delegations.Roles
+
+	logger := logging.FromContext(ctx)
+
+	for {
+		if len(delegationsQueue) <= 1 {
+			return rules, nil
+		}
+
+		delegation := delegationsQueue[0]
+		delegationsQueue = delegationsQueue[1:]
+
+		matches := delegationMatches(delegation, path, refName)
+		logger.DebugContext(ctx, logging.EventDelegationVisited, "rule", delegation.Name, "policy", topLevelRoleName, "path", path, "ref", refName, "matched", matches)
+		if !matches {
+			continue
+		}
+
+		if delegation.Deny {
+			rules.delegations = []tuf.Delegation{}
+			rules.denyRule = &delegation
+			return rules, nil
+		}
+
+		if delegation.IsExpired(s.now()) {
+			delegation.KeyIDs = nil
+			rules.delegations = append(rules.delegations, delegation)
+			delegationsQueue = enqueueDelegatedRoles(delegationsQueue, delegation, nil)
+			continue
+		}
+
+		rules.delegations = append(rules.delegations, delegation)
+
+		var delegatedRoles []tuf.Delegation
+		if s.HasTargetsRole(delegation.Name) {
+			delegatedMetadata, err := s.GetTargetsMetadata(delegation.Name)
+			if err != nil {
+				return nil, err
+			}
+			for keyID, key := range delegatedMetadata.Delegations.Keys {
+				rules.keys[keyID] = key
+			}
+			delegatedRoles = delegatedMetadata.Delegations.Roles
+		}
+
+		delegationsQueue = enqueueDelegatedRoles(delegationsQueue, delegation, delegatedRoles)
+	}
+}
+
+// delegationMatches checks if the delegation applies to the path when it is
+// changed on the specified ref. A delegation that has expired still matches,
+// so that the namespaces it protects are not left unprotected. Callers must
+// ensure an expired delegation contributes no keys.
+func delegationMatches(delegation tuf.Delegation, path, refName string) bool {
+	return delegation.MatchesOnRef(path, refName)
+}
+
+// checkAuthorizedKeysNotExpired returns an error wrapping ErrRuleExpired if
+// every rule that matched the path has expired.
+func checkAuthorizedKeysNotExpired(path string, authorizedKeys []AuthorizedKeys) error {
+	expiredRuleNames := []string{}
+	for _, ruleKeys := range authorizedKeys {
+		if !ruleKeys.Expired {
+			return nil
+		}
+		expiredRuleNames = append(expiredRuleNames, ruleKeys.RuleName)
+	}
+
+	return expiredRulesError(path, expiredRuleNames)
+}
+
+// checkDelegationsNotExpired returns an error wrapping ErrRuleExpired if every
+// delegation that matched the path has expired at the specified time.
+func checkDelegationsNotExpired(path string, delegations []tuf.Delegation, now time.Time) error {
+	expiredRuleNames := []string{}
+	for _, delegation := range delegations {
+		if !delegation.IsExpired(now) {
+			return nil
+		}
+		expiredRuleNames = append(expiredRuleNames, delegation.Name)
+	}
+
+	return expiredRulesError(path, expiredRuleNames)
+}
+
+func expiredRulesError(path string, expiredRuleNames []string) error {
+	if len(expiredRuleNames) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("%w: rules '%s' matching '%s' have expired", ErrRuleExpired, strings.Join(expiredRuleNames, "', '"), path)
+}
+
+// enqueueDelegatedRoles returns the delegations queue to continue a traversal
+// with after the delegation matched. The delegated roles, which are the rules
+// in the delegation's metadata if it exists, are visited next. If the
+// delegation is terminating, the rest of the queue is discarded. The allow
+// rule at the end of the delegated roles is only retained when it ends the
+// queue, as traversals stop when the allow rule is the only rule remaining.
+func enqueueDelegatedRoles(queue []tuf.Delegation, delegation tuf.Delegation, delegatedRoles []tuf.Delegation) []tuf.Delegation {
+	if delegation.Terminating {
+		// Remove other delegations from the queue
+		return delegatedRoles
+	}
+
+	if len(delegatedRoles) == 0 {
+		return queue
+	}
+
+	// Depth first, so newly discovered delegations go first. The delegated
+	// roles are copied so that the policy's metadata isn't modified when
+	// appending the rest of the queue.
+	newQueue := make([]tuf.Delegation, 0, len(delegatedRoles)-1+len(queue))
+	newQueue = append(newQueue, delegatedRoles[:len(delegatedRoles)-1]...)
+	return append(newQueue, queue...)
+}
+
+// FindDelegationsForPath identifies the rules in the policy that protect the
+// specified path. The keys trusted by the matched rules are also returned,
+// keyed by their key IDs. If the path matches a deny rule, ErrPathDenied is
+// returned.
+func (s *State) FindDelegationsForPath(ctx context.Context, path string) ([]tuf.Delegation, map[string]*tuf.Key, error) {
+	return s.FindDelegationsForPathOnRef(ctx, path, "")
+}
+
+// FindDelegationsForPathOnRef identifies the rules in the policy that protect
+// the specified path when it is changed on the specified ref. As with
+// FindPublicKeysForPathOnRef, rules restricted to certain refs only apply if
+// refName matches, or if refName is empty. Matching rules that have expired are
+// returned without any key IDs. If every matching rule has expired,
+// ErrRuleExpired is returned.
+func (s *State) FindDelegationsForPathOnRef(ctx context.Context, path, refName string) ([]tuf.Delegation, map[string]*tuf.Key, error) {
+	if err := s.Verify(ctx); err != nil {
+		return nil, nil, err
+	}
+
+	matchedDelegations, allPublicKeys, denyRule, err := s.findDelegationsForPath(ctx, path, refName)
+	if err != nil {
+		return nil, nil, err
+	}
+	if denyRule != nil {
+		return nil, nil, fmt.Errorf("%w: rule '%s' matches '%s'", ErrPathDenied, denyRule.Name, path)
+	}
+	if err := checkDelegationsNotExpired(path, matchedDelegations, s.now()); err != nil {
+		return nil, nil, err
+	}
+
+	return matchedDelegations, allPublicKeys, nil
+}
+
+// findDelegationsForPath identifies the rules in the policy that protect the
+// path on the ref, without verifying the policy, along with the keys declared
+// in the policy that owns the path. If the path matches a deny rule, the deny
+// rule is returned. See findRulesForPath for how the owning policy is
+// identified.
+func (s *State) findDelegationsForPath(ctx context.Context, path, refName string) ([]tuf.Delegation, map[string]*tuf.Key, *tuf.Delegation, error) {
+	rules, err := s.findRulesForPath(ctx, path, refName)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return rules.delegations, rules.keys, rules.denyRule, nil
+}
+
+// AuthorizedKeys records the keys a rule in the policy authorizes to sign for
+// a namespace. Deny rules and expired rules authorize no keys.
+type AuthorizedKeys struct {
+	RuleName string     `json:"rule_name"`
+	Deny     bool       `json:"deny,omitempty"`
+	Expired  bool       `json:"expired,omitempty"`
+	Keys     []*tuf.Key `json:"keys"`
+}
+
+// AuthorizedKeysByRef enumerates the Git ref patterns protected by the rules in
+// the policy and identifies the keys authorized to sign for each of them. The
+// returned map is keyed by the pattern, and records each rule that applies to
+// the pattern alongside the keys it authorizes, in the order the rules are
+// encountered when traversing the policy. Rules for patterns are resolved
+// using the same traversal as FindPublicKeysForPath, so terminating rules
+// shadow the rules after them. If a pattern matches a deny rule, only the deny
+// rule is recorded for it. Rules that have expired are recorded with Expired
+// set and no keys.
+func (s *State) AuthorizedKeysByRef(ctx context.Context) (map[string][]AuthorizedKeys, error) {
+	if err := s.Verify(ctx); err != nil {
+		return nil, err
+	}
+
+	patterns := map[string]bool{}
+	for _, roleName := range s.targetsRoleNames() {
+		targetsMetadata, err := s.GetTargetsMetadata(roleName)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, delegation := range targetsMetadata.Delegations.Roles {
+			for _, pattern := range delegation.Paths {
+				if strings.HasPrefix(pattern, "git:") { // FIXME: "git:" shouldn't be here
+					patterns[pattern] = true
+				}
+			}
+		}
+	}
+
+	authorizedKeys := map[string][]AuthorizedKeys{}
+	for pattern := range patterns {
+		delegations, allPublicKeys, denyRule, err := s.findDelegationsForPath(ctx, pattern, "")
+		if err != nil {
+			return nil, err
+		}
+
+		if denyRule != nil {
+			authorizedKeys[pattern] = []AuthorizedKeys{{RuleName: denyRule.Name, Deny: true, Keys: []*tuf.Key{}}}
+			continue
+		}
+
+		rules := []AuthorizedKeys{}
+		for _, delegation := range delegations {
+			keys := []*tuf.Key{}
+			for _, keyID := range delegation.KeyIDs {
+				key, has := allPublicKeys[keyID]
+				if !has {
+					return nil, fmt.Errorf("%w: rule '%s' authorizes key '%s'", ErrKeyNotFound, delegation.Name, keyID)
+				}
+				keys = append(keys, key)
+			}
+
+			rules = append(rules, AuthorizedKeys{RuleName: delegation.Name, Expired: delegation.IsExpired(s.now()), Keys: keys})
+		}
+		authorizedKeys[pattern] = rules
+	}
+
+	return authorizedKeys, nil
+}
+
+// IsRefProtected checks if any rule in the policy applies to changes made on
+// the specified ref. This is the case if a rule protects the ref itself, or if
+// a rule protects file paths and is not restricted to other refs. A ref that
+// matches a deny rule is also protected, as it may not be changed at all.
+// Expired rules and the allow rule are not considered. Verifying a ref that is
+// not protected trivially succeeds, which this can be used to detect.
+func (s *State) IsRefProtected(ctx context.Context, refName string) (bool, error) {
+	if err := s.Verify(ctx); err != nil {
+		return false, err
+	}
+
+	delegations, _, denyRule, err := s.findDelegationsForPath(ctx, fmt.Sprintf("git:%s", refName), refName) // FIXME: "git:" shouldn't be here
+	if err != nil {
+		return false, err
+	}
+	if denyRule != nil || len(delegations) > 0 {
+		return true, nil
+	}
+
+	for _, roleName := range s.targetsRoleNames() {
+		targetsMetadata, err := s.GetTargetsMetadata(roleName)
+		if err != nil {
+			return false, err
+		}
+
+		for _, delegation := range targetsMetadata.Delegations.Roles {
+			if delegation.Name == AllowRuleName || !delegation.AppliesToRef(refName) {
+				continue
+			}
+
+			for _, pattern := range delegation.Paths {
+				if strings.HasPrefix(pattern, "file:") { // FIXME: "file:" shouldn't be here
+					return true, nil
+				}
+			}
+		}
+	}
+
+	return false, nil
+}
+
+// VerifyOptions contains the optional checks performed by State.Verify.
+type VerifyOptions struct {
+	StrictDelegationScope bool
+}
+
+// VerifyOption is used to configure State.Verify.
+type VerifyOption func(*VerifyOptions)
+
+// WithStrictDelegationScope configures State.Verify to check that every rule
+// delegated to by another rule only protects namespaces protected by the
+// delegating rule, so that a rule's signers cannot expand their own scope. If
+// the delegating rule is restricted to certain refs, the refs of the delegated
+// rule must also be within them. Rules in the top level policies and deny rules
+// are not checked.
+func WithStrictDelegationScope() VerifyOption {
+	return func(o *VerifyOptions) {
+		o.StrictDelegationScope = true
+	}
+}
+
+// Verify performs a self-contained verification of all the metadata in the
+// State starting from the Root. Any metadata that is unreachable in the
 // delegations graph returns an error.
-func (s *State) Verify(ctx context.Context) error {
+func (s *State) Verify(ctx context.Context, opts ...VerifyOption) error {
+	options := &VerifyOptions{}
+	for _, fn := range opts {
+		fn(options)
+	}
+
 	rootVerifiers := []sslibdsse.Verifier{}
 	for _, k := range s.RootPublicKeys {
 		sv, err := signerverifier.NewSignerVerifierFromTUFKey(k)
@@ -383,10 +1316,6 @@ func (s *State) Verify(ctx context.Context) error {
 		return err
 	}
 
-	if s.TargetsEnvelope == nil {
-		return nil
-	}
-
 	rootMetadata := &tuf.RootMetadata{}
 	rootContents, err := s.RootEnvelope.DecodeB64Payload()
 	if err != nil {
@@ -396,21 +1325,29 @@ func (s *State) Verify(ctx context.Context) error {
 		return err
 	}
 
-	targetsVerifiers := []sslibdsse.Verifier{}
-	for _, keyID := range rootMetadata.Roles[TargetsRoleName].KeyIDs {
-		key := rootMetadata.Keys[keyID]
-		sv, err := signerverifier.NewSignerVerifierFromTUFKey(key)
-		if err != nil {
-			return err
-		}
+	// Metadata written for a newer major version of the specification may
+	// rely on fields this version of gittuf doesn't enforce, so it's rejected
+	// rather than partially verified
+	if err := rootMetadata.CheckSpecVersion(); err != nil {
+		return fmt.Errorf("unable to verify root of trust: %w", err)
+	}
 
-		targetsVerifiers = append(targetsVerifiers, sv)
+	if err := s.verifyRootPublicKeys(rootMetadata); err != nil {
+		return err
 	}
-	if err := dsse.VerifyEnvelope(ctx, s.TargetsEnvelope, targetsVerifiers, rootMetadata.Roles[TargetsRoleName].Threshold); err != nil {
+
+	if err := verifyEmergencyRoleKeys(rootMetadata); err != nil {
 		return err
 	}
 
-	if len(s.DelegationEnvelopes) == 0 {
+	if err := verifyTopLevelPolicies(rootMetadata); err != nil {
+		return err
+	}
+
+	logger := logging.FromContext(ctx)
+	logger.DebugContext(ctx, logging.EventMetadataVerified, "role", RootRoleName, "threshold", len(rootVerifiers))
+
+	if s.TargetsEnvelope == nil && len(s.DelegationEnvelopes) == 0 {
 		return nil
 	}
 
@@ -419,99 +1356,468 @@ func (s *State) Verify(ctx context.Context) error {
 		delegationEnvelopes[k] = v
 	}
 
-	targetsMetadata := &tuf.TargetsMetadata{}
-	targetsContents, err := s.TargetsEnvelope.DecodeB64Payload()
+	// Each top level policy is verified using the keys and threshold assigned
+	// to it in the root metadata. The delegations of all the top level policies
+	// are then verified together in the loop below. Delegated metadata is
+	// stored by rule name, so rule names share one namespace across the top
+	// level policies. Each rule is tracked with the top level policy it
+	// descends from, and its metadata is only verified using the keys declared
+	// in that policy's delegation graph.
+	type queuedDelegation struct {
+		delegation tuf.Delegation
+		policyName string
+	}
+
+	topLevelRoleNames := topLevelTargetsRoleNames(rootMetadata)
+	isTopLevelRole := map[string]bool{}
+	delegationKeys := map[string]map[string]*tuf.Key{}
+	ruleOwners := map[string]string{}
+	delegationsQueue := []queuedDelegation{}
+	for _, roleName := range topLevelRoleNames {
+		isTopLevelRole[roleName] = true
+		delegationKeys[roleName] = map[string]*tuf.Key{}
+
+		targetsEnvelope := s.getEnvelope(roleName)
+		if targetsEnvelope == nil {
+			continue
+		}
+		delete(delegationEnvelopes, roleName)
+
+		role := rootMetadata.Roles[roleName]
+		targetsVerifiers := []sslibdsse.Verifier{}
+		for _, keyID := range role.KeyIDs {
+			key := rootMetadata.Keys[keyID]
+			sv, err := signerverifier.NewSignerVerifierFromTUFKey(key)
+			if err != nil {
+				return err
+			}
+
+			targetsVerifiers = append(targetsVerifiers, sv)
+		}
+		if err := dsse.VerifyEnvelope(ctx, targetsEnvelope, targetsVerifiers, role.Threshold); err != nil {
+			return err
+		}
+		logger.DebugContext(ctx, logging.EventMetadataVerified, "role", roleName, "threshold", role.Threshold)
+
+		// The top level targets metadata is validated even if there are no
+		// delegated envelopes, so that its rules are known to be well formed,
+		// e.g., that every key they authorize is present
+		targetsMetadata := &tuf.TargetsMetadata{}
+		targetsContents, err := targetsEnvelope.DecodeB64Payload()
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(targetsContents, targetsMetadata); err != nil {
+			return err
+		}
+
+		if err := targetsMetadata.CheckSpecVersion(); err != nil {
+			return fmt.Errorf("unable to verify policy '%s': %w", roleName, err)
+		}
+
+		if err := targetsMetadata.Validate(); err != nil {
+			return err
+		}
+
+		if targetsMetadata.Delegations == nil {
+			continue
+		}
+
+		for keyID, key := range targetsMetadata.Delegations.Keys {
+			delegationKeys[roleName][keyID] = key
+		}
+		for _, delegation := range targetsMetadata.Delegations.Roles {
+			delegationsQueue = append(delegationsQueue, queuedDelegation{delegation: delegation, policyName: roleName})
+		}
+	}
+
+	for {
+		if len(delegationsQueue) == 0 {
+			break
+		}
+
+		delegation := delegationsQueue[0].delegation
+		policyName := delegationsQueue[0].policyName
+		delegationsQueue = delegationsQueue[1:]
+
+		if isTopLevelRole[delegation.Name] {
+			return fmt.Errorf("%w: '%s'", ErrRuleNameConflictsWithRole, delegation.Name)
+		}
+
+		if delegation.Name != AllowRuleName {
+			if owner, has := ruleOwners[delegation.Name]; has && owner != policyName {
+				return fmt.Errorf("%w: rule '%s' is declared in policies '%s' and '%s'", ErrRuleNameInMultiplePolicies, delegation.Name, owner, policyName)
+			}
+			ruleOwners[delegation.Name] = policyName
+		}
+
+		delegationEnvelope, ok := delegationEnvelopes[delegation.Name]
+		if !ok {
+			// Delegation does not have an envelope to verify
+			continue
+		}
+		delete(delegationEnvelopes, delegation.Name)
+
+		delegationVerifiers := make([]sslibdsse.Verifier, 0, len(delegation.KeyIDs))
+		for _, keyID := range delegation.KeyIDs {
+			key, has := delegationKeys[policyName][keyID]
+			if !has {
+				return fmt.Errorf("%w: rule '%s' authorizes key '%s'", tuf.ErrDelegationKeyMissing, delegation.Name, keyID)
+			}
+			sv, err := signerverifier.NewSignerVerifierFromTUFKey(key)
+			if err != nil {
+				return err
+			}
+
+			delegationVerifiers = append(delegationVerifiers, sv)
+		}
+
+		if err := dsse.VerifyEnvelope(ctx, delegationEnvelope, delegationVerifiers, delegation.Threshold); err != nil {
+			return err
+		}
+		logger.DebugContext(ctx, logging.EventMetadataVerified, "role", delegation.Name, "threshold", delegation.Threshold)
+
+		delegationMetadata := &tuf.TargetsMetadata{}
+		delegationContents, err := delegationEnvelope.DecodeB64Payload()
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(delegationContents, delegationMetadata); err != nil {
+			return err
+		}
+
+		if err := delegationMetadata.CheckSpecVersion(); err != nil {
+			return fmt.Errorf("unable to verify policy '%s': %w", delegation.Name, err)
+		}
+
+		if err := delegationMetadata.Validate(); err != nil {
+			return err
+		}
+
+		if delegationMetadata.Delegations == nil {
+			continue
+		}
+
+		if options.StrictDelegationScope {
+			for _, childDelegation := range delegationMetadata.Delegations.Roles {
+				if err := verifyDelegationScope(delegation, childDelegation); err != nil {
+					return err
+				}
+			}
+		}
+
+		for keyID, key := range delegationMetadata.Delegations.Keys {
+			delegationKeys[policyName][keyID] = key
+		}
+
+		for _, childDelegation := range delegationMetadata.Delegations.Roles {
+			delegationsQueue = append(delegationsQueue, queuedDelegation{delegation: childDelegation, policyName: policyName})
+		}
+	}
+
+	if len(delegationEnvelopes) != 0 {
+		return ErrDanglingDelegationMetadata
+	}
+
+	// Keys are looked up by ID without regard to the top level policy in some
+	// places, such as when finding a key's validity window, so a key ID must
+	// refer to the same key everywhere
+	allKeys := map[string]*tuf.Key{}
+	allKeysOwners := map[string]string{}
+	for _, roleName := range topLevelRoleNames {
+		for keyID, key := range delegationKeys[roleName] {
+			if existingKey, has := allKeys[keyID]; has && !tuf.IsSameKey(existingKey, key) {
+				return fmt.Errorf("%w: '%s' in policies '%s' and '%s'", ErrConflictingKeyIDs, keyID, allKeysOwners[keyID], roleName)
+			}
+			allKeys[keyID] = key
+			allKeysOwners[keyID] = roleName
+		}
+	}
+
+	return nil
+}
+
+// CheckRuleNameIsAvailable returns an error wrapping
+// ErrRuleNameInMultiplePolicies if a rule with the specified name is declared
+// under a different top level policy than the specified role, which may be a
+// top level policy or a rule. Delegated metadata is stored by rule name, so
+// rule names must be unique across the top level policies. The policy is not
+// verified.
+func (s *State) CheckRuleNameIsAvailable(roleName, ruleName string) error {
+	ruleOwners, err := s.getRuleOwners()
 	if err != nil {
 		return err
 	}
-	if err := json.Unmarshal(targetsContents, targetsMetadata); err != nil {
+
+	policyName := roleName
+	if owner, has := ruleOwners[roleName]; has {
+		policyName = owner
+	}
+
+	if owner, has := ruleOwners[ruleName]; has && owner != policyName {
+		return fmt.Errorf("%w: rule '%s' is declared in policy '%s'", ErrRuleNameInMultiplePolicies, ruleName, owner)
+	}
+
+	return nil
+}
+
+// getRuleOwners traverses the delegations of each top level policy and returns
+// the name of the top level policy each rule is declared under. If a rule is
+// declared under more than one top level policy, the first one is recorded.
+func (s *State) getRuleOwners() (map[string]string, error) {
+	topLevelRoleNames, err := s.TopLevelTargetsRoleNames()
+	if err != nil {
+		return nil, err
+	}
+
+	ruleOwners := map[string]string{}
+	for _, topLevelRoleName := range topLevelRoleNames {
+		if !s.HasTargetsRole(topLevelRoleName) {
+			continue
+		}
+
+		queue := []string{topLevelRoleName}
+		for len(queue) > 0 {
+			roleName := queue[0]
+			queue = queue[1:]
+
+			if !s.HasTargetsRole(roleName) {
+				continue
+			}
+			targetsMetadata, err := s.GetTargetsMetadata(roleName)
+			if err != nil {
+				return nil, err
+			}
+			if targetsMetadata.Delegations == nil {
+				continue
+			}
+
+			for _, delegation := range targetsMetadata.Delegations.Roles {
+				if delegation.Name == AllowRuleName {
+					continue
+				}
+				if _, has := ruleOwners[delegation.Name]; has {
+					continue
+				}
+				ruleOwners[delegation.Name] = topLevelRoleName
+				queue = append(queue, delegation.Name)
+			}
+		}
+	}
+
+	return ruleOwners, nil
+}
+
+// VersionBumpPolicy determines how State.Commit handles metadata for a role
+// that is modified without incrementing its version relative to the metadata
+// in the policy ref.
+type VersionBumpPolicy struct {
+	// Auto indicates the version is incremented and the metadata re-signed
+	// using Signers. Otherwise, ErrMetadataVersionNotBumped is returned.
+	Auto    bool
+	Signers []sslibdsse.Signer
+}
+
+// CommitOptions contains the optional behavior of State.Commit.
+type CommitOptions struct {
+	PruneUnreferencedKeys bool
+	CommitterName         string
+	CommitterEmail        string
+	Clock                 clockwork.Clock
+	VersionBumpPolicies   map[string]VersionBumpPolicy
+}
+
+// CommitOption is used to configure State.Commit.
+type CommitOption func(*CommitOptions)
+
+// WithPruneUnreferencedKeys configures State.Commit to drop root public keys
+// that are no longer referenced by the root or targets metadata before the
+// keys tree is written. This is useful after a key is rotated out.
+func WithPruneUnreferencedKeys() CommitOption {
+	return func(o *CommitOptions) {
+		o.PruneUnreferencedKeys = true
+	}
+}
+
+// WithCommitter configures State.Commit to use the specified committer
+// identity for the policy commit and its RSL entry, rather than the user's
+// identity from the repository's Git config.
+func WithCommitter(name, email string) CommitOption {
+	return func(o *CommitOptions) {
+		o.CommitterName = name
+		o.CommitterEmail = email
+	}
+}
+
+// WithClock configures State.Commit to use the specified clock to timestamp
+// the policy commit and its RSL entry, so that policy commits can be created
+// reproducibly.
+func WithClock(clock clockwork.Clock) CommitOption {
+	return func(o *CommitOptions) {
+		o.Clock = clock
+	}
+}
+
+// WithStrictVersionBump configures State.Commit to return
+// ErrMetadataVersionNotBumped if the metadata of any of the specified roles is
+// modified but its version is not greater than the version in the policy ref.
+func WithStrictVersionBump(roleNames ...string) CommitOption {
+	return func(o *CommitOptions) {
+		if o.VersionBumpPolicies == nil {
+			o.VersionBumpPolicies = map[string]VersionBumpPolicy{}
+		}
+		for _, roleName := range roleNames {
+			o.VersionBumpPolicies[roleName] = VersionBumpPolicy{}
+		}
+	}
+}
+
+// WithAutoVersionBump configures State.Commit to increment the version of the
+// specified role's metadata if it is modified but its version is not greater
+// than the version in the policy ref. The metadata is then re-signed using the
+// signers, replacing its existing signatures, so the signers must meet the
+// role's threshold.
+func WithAutoVersionBump(roleName string, signers ...sslibdsse.Signer) CommitOption {
+	return func(o *CommitOptions) {
+		if o.VersionBumpPolicies == nil {
+			o.VersionBumpPolicies = map[string]VersionBumpPolicy{}
+		}
+		o.VersionBumpPolicies[roleName] = VersionBumpPolicy{Auto: true, Signers: signers}
+	}
+}
+
+// Commit verifies and writes the State to the policy namespace. It also creates
+// an RSL entry recording the new tip of the policy namespace.
+func (s *State) Commit(ctx context.Context, repo *git.Repository, commitMessage string, signCommit bool, opts ...CommitOption) error {
+	options := &CommitOptions{}
+	for _, fn := range opts {
+		fn(options)
+	}
+
+	policyRootTreeID, err := s.prepareCommit(ctx, repo, options)
+	if err != nil {
 		return err
 	}
 
-	if err := targetsMetadata.Validate(); err != nil {
+	if len(commitMessage) == 0 {
+		commitMessage = DefaultCommitMessage
+	}
+
+	// The policy commit and its RSL entry are created while holding the
+	// repository's gittuf lock so that a concurrent gittuf process cannot
+	// interleave its own changes
+	lock, err := gitinterface.LockRepository(repo, gitinterface.DefaultLockTimeout)
+	if err != nil {
 		return err
 	}
+	defer lock.Unlock() //nolint:errcheck
 
-	// Note: If targetsMetadata.Delegations == nil while delegationEnvelopes is
-	// not empty, we probably want to error out. This should panic.
-	delegationKeys := targetsMetadata.Delegations.Keys
-	delegationsQueue := targetsMetadata.Delegations.Roles
+	ref, err := repo.Reference(plumbing.ReferenceName(PolicyRef), true)
+	if err != nil {
+		return err
+	}
+	originalCommitID := ref.Hash()
 
-	// We can likely process top level targets and all delegated envelopes in
-	// the loop below by combining the two but this separated model seems easier
-	// to reason about. Else, we define a custom starting delegation from root
-	// to targets in the queue and start this loop from there.
+	gitCommitOpts := options.gitCommitOptions()
 
-	for {
-		if len(delegationsQueue) == 0 {
-			break
-		}
+	commitID, err := gitinterface.Commit(repo, policyRootTreeID, PolicyRef, commitMessage, signCommit, gitCommitOpts...)
+	if err != nil {
+		return err
+	}
 
-		delegation := delegationsQueue[0]
-		delegationsQueue = delegationsQueue[1:]
+	// We must reset to original policy commit if err != nil from here onwards.
 
-		delegationEnvelope, ok := delegationEnvelopes[delegation.Name]
-		if !ok {
-			// Delegation does not have an envelope to verify
-			continue
-		}
-		delete(delegationEnvelopes, delegation.Name)
+	if err := rsl.NewReferenceEntry(PolicyRef, commitID).CommitWhileLocked(repo, signCommit, gitCommitOpts...); err != nil {
+		return gitinterface.ResetDueToError(err, repo, PolicyRef, originalCommitID)
+	}
 
-		delegationVerifiers := make([]sslibdsse.Verifier, 0, len(delegation.KeyIDs))
-		for _, keyID := range delegation.KeyIDs {
-			key := delegationKeys[keyID]
-			sv, err := signerverifier.NewSignerVerifierFromTUFKey(key)
-			if err != nil {
-				return err
-			}
+	return nil
+}
 
-			delegationVerifiers = append(delegationVerifiers, sv)
-		}
+// StagedPolicy is a policy commit prepared by State.StageTree that has not been
+// created.
+type StagedPolicy struct {
+	// TreeID is the ID of the policy's root tree, which is written to the
+	// repository along with the metadata and keys trees.
+	TreeID plumbing.Hash
 
-		if err := dsse.VerifyEnvelope(ctx, delegationEnvelope, delegationVerifiers, delegation.Threshold); err != nil {
-			return err
-		}
+	// Commit is the unsigned commit that State.Commit would create.
+	Commit *object.Commit
 
-		delegationMetadata := &tuf.TargetsMetadata{}
-		delegationContents, err := delegationEnvelope.DecodeB64Payload()
-		if err != nil {
-			return err
-		}
-		if err := json.Unmarshal(delegationContents, delegationMetadata); err != nil {
-			return err
-		}
+	// CommitBytes is the encoding of Commit without a signature, i.e., the
+	// contents that are signed if the policy commit is signed.
+	CommitBytes []byte
+}
 
-		if err := delegationMetadata.Validate(); err != nil {
-			return err
-		}
+// StageTree prepares the policy commit that State.Commit would create with the
+// same message and options, without creating it. The policy's trees are
+// written to the repository, but PolicyRef and the RSL are not updated. This
+// allows the policy commit to be reviewed, diffed, or signed externally before
+// it is created. Note that options which modify the state, such as
+// WithPruneUnreferencedKeys, are applied to the state.
+func (s *State) StageTree(ctx context.Context, repo *git.Repository, commitMessage string, opts ...CommitOption) (*StagedPolicy, error) {
+	options := &CommitOptions{}
+	for _, fn := range opts {
+		fn(options)
+	}
 
-		if delegationMetadata.Delegations == nil {
-			continue
-		}
+	policyRootTreeID, err := s.prepareCommit(ctx, repo, options)
+	if err != nil {
+		return nil, err
+	}
 
-		for keyID, key := range delegationMetadata.Delegations.Keys {
-			delegationKeys[keyID] = key
-		}
+	if len(commitMessage) == 0 {
+		commitMessage = DefaultCommitMessage
+	}
 
-		delegationsQueue = append(delegationsQueue, delegationMetadata.Delegations.Roles...)
+	commit, err := gitinterface.CreateCommitObjectForRef(repo, policyRootTreeID, PolicyRef, commitMessage, options.gitCommitOptions()...)
+	if err != nil {
+		return nil, err
 	}
 
-	if len(delegationEnvelopes) != 0 {
-		return ErrDanglingDelegationMetadata
+	commitObj := &plumbing.MemoryObject{}
+	if err := commit.EncodeWithoutSignature(commitObj); err != nil {
+		return nil, err
+	}
+	reader, err := commitObj.Reader()
+	if err != nil {
+		return nil, err
+	}
+	commitBytes, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
+	return &StagedPolicy{
+		TreeID:      policyRootTreeID,
+		Commit:      commit,
+		CommitBytes: commitBytes,
+	}, nil
 }
 
-// Commit verifies and writes the State to the policy namespace. It also creates
-// an RSL entry recording the new tip of the policy namespace.
-func (s *State) Commit(ctx context.Context, repo *git.Repository, commitMessage string, signCommit bool) error {
+// prepareCommit applies the options that modify the state, verifies it, and
+// writes the policy's trees to the repository, returning the ID of the policy
+// root tree.
+func (s *State) prepareCommit(ctx context.Context, repo *git.Repository, options *CommitOptions) (plumbing.Hash, error) {
+	if options.PruneUnreferencedKeys {
+		if err := s.pruneUnreferencedKeys(); err != nil {
+			return plumbing.ZeroHash, err
+		}
+	}
+
+	if len(options.VersionBumpPolicies) > 0 {
+		if err := s.applyVersionBumpPolicies(ctx, repo, options.VersionBumpPolicies); err != nil {
+			return plumbing.ZeroHash, err
+		}
+	}
+
 	if err := s.Verify(ctx); err != nil {
-		return err
+		return plumbing.ZeroHash, err
 	}
 
-	if len(commitMessage) == 0 {
-		commitMessage = DefaultCommitMessage
+	committed, err := getCommittedBlobs(ctx, repo)
+	if err != nil {
+		return plumbing.ZeroHash, err
 	}
 
 	metadata := map[string]*sslibdsse.Envelope{}
@@ -530,12 +1836,12 @@ func (s *State) Commit(ctx context.Context, repo *git.Repository, commitMessage
 	for name, env := range metadata {
 		metadataContents, err := json.Marshal(env)
 		if err != nil {
-			return err
+			return plumbing.ZeroHash, err
 		}
 
-		blobID, err := gitinterface.WriteBlob(repo, metadataContents)
+		blobID, err := writePolicyBlob(repo, metadataContents, committed.metadata[name])
 		if err != nil {
-			return err
+			return plumbing.ZeroHash, err
 		}
 
 		metadataEntries = append(metadataEntries, object.TreeEntry{
@@ -546,19 +1852,19 @@ func (s *State) Commit(ctx context.Context, repo *git.Repository, commitMessage
 	}
 	metadataTreeID, err := gitinterface.WriteTree(repo, metadataEntries)
 	if err != nil {
-		return err
+		return plumbing.ZeroHash, err
 	}
 
 	keysEntries := []object.TreeEntry{}
 	for _, key := range s.RootPublicKeys {
 		keyContents, err := json.Marshal(key)
 		if err != nil {
-			return err
+			return plumbing.ZeroHash, err
 		}
 
-		blobID, err := gitinterface.WriteBlob(repo, keyContents)
+		blobID, err := writePolicyBlob(repo, keyContents, committed.keys[key.KeyID])
 		if err != nil {
-			return err
+			return plumbing.ZeroHash, err
 		}
 
 		keysEntries = append(keysEntries, object.TreeEntry{
@@ -569,7 +1875,7 @@ func (s *State) Commit(ctx context.Context, repo *git.Repository, commitMessage
 	}
 	keysTreeID, err := gitinterface.WriteTree(repo, keysEntries)
 	if err != nil {
-		return err
+		return plumbing.ZeroHash, err
 	}
 
 	policyRootTreeID, err := gitinterface.WriteTree(repo, []object.TreeEntry{
@@ -584,26 +1890,322 @@ func (s *State) Commit(ctx context.Context, repo *git.Repository, commitMessage
 			Hash: keysTreeID,
 		},
 	})
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+
+	return policyRootTreeID, nil
+}
+
+// gitCommitOptions returns the options used to create the policy commit and
+// its RSL entry.
+func (o *CommitOptions) gitCommitOptions() []gitinterface.CommitOption {
+	gitCommitOpts := []gitinterface.CommitOption{}
+	if len(o.CommitterName) > 0 || len(o.CommitterEmail) > 0 {
+		gitCommitOpts = append(gitCommitOpts, gitinterface.WithCommitter(o.CommitterName, o.CommitterEmail))
+	}
+	if o.Clock != nil {
+		gitCommitOpts = append(gitCommitOpts, gitinterface.WithClock(o.Clock))
+	}
+	return gitCommitOpts
+}
+
+// applyVersionBumpPolicies compares the metadata of each role with a version
+// bump policy against the metadata committed in the policy ref. If the metadata
+// is modified without a version greater than the committed version, the policy
+// determines whether an error is returned or the version is bumped. Roles that
+// are not in the policy ref yet are not checked.
+func (s *State) applyVersionBumpPolicies(ctx context.Context, repo *git.Repository, policies map[string]VersionBumpPolicy) error {
+	tipID, err := gitinterface.GetTip(repo, PolicyRef)
+	if err != nil {
+		if errors.Is(err, plumbing.ErrReferenceNotFound) {
+			return nil
+		}
+		return err
+	}
+	if tipID.IsZero() {
+		return nil
+	}
+	policyCommit, err := repo.CommitObject(tipID)
+	if err != nil {
+		return err
+	}
+	committedState, err := loadStateForCommit(ctx, repo, policyCommit, false)
 	if err != nil {
 		return err
 	}
 
-	ref, err := repo.Reference(plumbing.ReferenceName(PolicyRef), true)
+	for roleName, policy := range policies {
+		env := s.getEnvelope(roleName)
+		committedEnv := committedState.getEnvelope(roleName)
+		if env == nil || committedEnv == nil || env.Payload == committedEnv.Payload {
+			continue
+		}
+
+		version, err := getMetadataVersion(env)
+		if err != nil {
+			return err
+		}
+		committedVersion, err := getMetadataVersion(committedEnv)
+		if err != nil {
+			return err
+		}
+		if version > committedVersion {
+			continue
+		}
+
+		if !policy.Auto {
+			return fmt.Errorf("%w: '%s' has version %d, committed version is %d", ErrMetadataVersionNotBumped, roleName, version, committedVersion)
+		}
+		if len(policy.Signers) == 0 {
+			return fmt.Errorf("%w: '%s'", ErrNoVersionBumpSigners, roleName)
+		}
+
+		var metadata interface{ SetVersion(int) }
+		if roleName == RootRoleName {
+			metadata = &tuf.RootMetadata{}
+		} else {
+			metadata = &tuf.TargetsMetadata{}
+		}
+		payload, err := env.DecodeB64Payload()
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(payload, metadata); err != nil {
+			return err
+		}
+		metadata.SetVersion(committedVersion + 1)
+
+		newEnv, err := dsse.CreateEnvelope(metadata)
+		if err != nil {
+			return err
+		}
+		for _, signer := range policy.Signers {
+			newEnv, err = dsse.SignEnvelope(ctx, newEnv, signer)
+			if err != nil {
+				return err
+			}
+		}
+
+		switch roleName {
+		case RootRoleName:
+			s.RootEnvelope = newEnv
+		case TargetsRoleName:
+			s.TargetsEnvelope = newEnv
+		default:
+			s.DelegationEnvelopes[roleName] = newEnv
+		}
+	}
+
+	return nil
+}
+
+// getEnvelope returns the envelope of the specified role's metadata, or nil if
+// the State has no metadata for the role.
+func (s *State) getEnvelope(roleName string) *sslibdsse.Envelope {
+	switch roleName {
+	case RootRoleName:
+		return s.RootEnvelope
+	case TargetsRoleName:
+		return s.TargetsEnvelope
+	default:
+		return s.DelegationEnvelopes[roleName]
+	}
+}
+
+// SetTargetsEnvelope records env as the metadata of the specified top level
+// policy or rule. The metadata of TargetsRoleName is held in TargetsEnvelope.
+// The other top level policies listed in the root of trust are held in
+// DelegationEnvelopes by name, alongside the metadata of rules, as the policy
+// does not permit rules that share a name with a top level policy.
+// ErrMetadataNotFound is returned if roleName is neither a top level policy nor
+// a rule declared in the policy.
+func (s *State) SetTargetsEnvelope(roleName string, env *sslibdsse.Envelope) error {
+	if roleName == TargetsRoleName {
+		s.TargetsEnvelope = env
+		return nil
+	}
+
+	rootMetadata, err := s.GetRootMetadata()
 	if err != nil {
 		return err
 	}
-	originalCommitID := ref.Hash()
+	if isReservedRoleName(roleName) {
+		return ErrMetadataNotFound
+	}
+	if !isTopLevelPolicy(rootMetadata, roleName) {
+		if _, err := s.findDelegationEntry(roleName); err != nil {
+			if errors.Is(err, ErrDelegationNotFound) {
+				return ErrMetadataNotFound
+			}
+			return err
+		}
+	}
+
+	if s.DelegationEnvelopes == nil {
+		s.DelegationEnvelopes = map[string]*sslibdsse.Envelope{}
+	}
+	s.DelegationEnvelopes[roleName] = env
+
+	return nil
+}
+
+// getMetadataVersion returns the version recorded in the metadata in the
+// envelope.
+func getMetadataVersion(env *sslibdsse.Envelope) (int, error) {
+	payload, err := env.DecodeB64Payload()
+	if err != nil {
+		return 0, err
+	}
+
+	metadata := struct {
+		Version int `json:"version"`
+	}{}
+	if err := json.Unmarshal(payload, &metadata); err != nil {
+		return 0, err
+	}
+
+	return metadata.Version, nil
+}
+
+// verifyRootPublicKeys checks that the root public keys in the policy's keys
+// tree, which are used to verify the root metadata, agree with the keys
+// recorded in the root metadata. Every key trusted for the root role in the
+// root metadata must be present in the tree, and a key in the tree must have
+// the same contents as the key with the same ID in the root metadata. Keys
+// that are no longer root keys may remain in the tree until they are pruned, as
+// they must still sign the root metadata. If the keys disagree,
+// ErrRootKeysMismatch is returned.
+func (s *State) verifyRootPublicKeys(rootMetadata *tuf.RootMetadata) error {
+	treeKeys := map[string]*tuf.Key{}
+	for _, key := range s.RootPublicKeys {
+		if metadataKey, has := rootMetadata.Keys[key.KeyID]; has && !tuf.IsSameKey(key, metadataKey) {
+			return fmt.Errorf("%w: key '%s' in keys tree does not match the key in root metadata", ErrRootKeysMismatch, key.KeyID)
+		}
+		treeKeys[key.KeyID] = key
+	}
+
+	rootRole, has := rootMetadata.Roles[RootRoleName]
+	if !has {
+		return fmt.Errorf("%w: root metadata does not define the root role", ErrRootKeysMismatch)
+	}
+	for _, keyID := range rootRole.KeyIDs {
+		if _, has := treeKeys[keyID]; !has {
+			return fmt.Errorf("%w: root key '%s' is not present in keys tree", ErrRootKeysMismatch, keyID)
+		}
+	}
+
+	return nil
+}
+
+// verifyEmergencyRoleKeys checks that the keys of the emergency role, if it is
+// declared, are not trusted by any other role in the root metadata. This
+// ensures that break-glass access requires keys that are reserved for
+// emergencies. If a key is shared, ErrEmergencyKeyNotDistinct is returned.
+func verifyEmergencyRoleKeys(rootMetadata *tuf.RootMetadata) error {
+	emergencyRole, has := rootMetadata.Roles[EmergencyRoleName]
+	if !has {
+		return nil
+	}
+
+	for roleName, role := range rootMetadata.Roles {
+		if roleName == EmergencyRoleName {
+			continue
+		}
+
+		for _, keyID := range role.KeyIDs {
+			for _, emergencyKeyID := range emergencyRole.KeyIDs {
+				if keyID == emergencyKeyID {
+					return fmt.Errorf("%w: key '%s' is trusted by role '%s'", ErrEmergencyKeyNotDistinct, keyID, roleName)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// verifyTopLevelPolicies checks that every top level policy the root metadata
+// lists is declared as a role, is listed once, and does not use the name of a
+// role that is not a policy. If not, ErrInvalidTopLevelPolicy is returned.
+func verifyTopLevelPolicies(rootMetadata *tuf.RootMetadata) error {
+	listed := map[string]bool{}
+	for _, roleName := range rootMetadata.TopLevelPolicies {
+		switch {
+		case roleName == TargetsRoleName || isReservedRoleName(roleName):
+			return fmt.Errorf("%w: '%s' cannot be listed as a top level policy", ErrInvalidTopLevelPolicy, roleName)
+		case listed[roleName]:
+			return fmt.Errorf("%w: '%s' is listed more than once", ErrInvalidTopLevelPolicy, roleName)
+		}
+		listed[roleName] = true
+
+		if _, declared := rootMetadata.Roles[roleName]; !declared {
+			return fmt.Errorf("%w: '%s' is not declared as a role", ErrInvalidTopLevelPolicy, roleName)
+		}
+	}
 
-	commitID, err := gitinterface.Commit(repo, policyRootTreeID, PolicyRef, commitMessage, signCommit)
+	return nil
+}
+
+// verifyDelegationScope checks that the namespaces protected by the delegated
+// rule are protected by the rule delegating to it. Deny rules only narrow what
+// the delegating rule's signers may authorize, so they are not checked. The
+// implicit allow rule that ends every set of rules trusts no keys, so it does
+// not widen the delegating rule's scope either.
+func verifyDelegationScope(parent, delegation tuf.Delegation) error {
+	if delegation.Deny || delegation.Name == AllowRuleName {
+		return nil
+	}
+
+	for _, pattern := range delegation.Paths {
+		if !parent.CoversPattern(pattern) {
+			return fmt.Errorf("%w: rule '%s' protects '%s', which is not protected by rule '%s'", ErrDelegationEscalatesScope, delegation.Name, pattern, parent.Name)
+		}
+	}
+
+	for _, pattern := range delegation.Refs {
+		if !parent.CoversRefPattern(pattern) {
+			return fmt.Errorf("%w: rule '%s' applies to ref '%s', to which rule '%s' does not apply", ErrDelegationEscalatesScope, delegation.Name, pattern, parent.Name)
+		}
+	}
+
+	return nil
+}
+
+// pruneUnreferencedKeys removes keys from RootPublicKeys that are not present in
+// the root metadata or in the delegations of any targets metadata.
+func (s *State) pruneUnreferencedKeys() error {
+	referencedKeyIDs := map[string]bool{}
+
+	rootMetadata, err := s.GetRootMetadata()
 	if err != nil {
 		return err
 	}
+	for keyID := range rootMetadata.Keys {
+		referencedKeyIDs[keyID] = true
+	}
 
-	// We must reset to original policy commit if err != nil from here onwards.
+	for _, roleName := range s.targetsRoleNames() {
+		targetsMetadata, err := s.GetTargetsMetadata(roleName)
+		if err != nil {
+			return err
+		}
+		if targetsMetadata.Delegations == nil {
+			continue
+		}
 
-	if err := rsl.NewReferenceEntry(PolicyRef, commitID).Commit(repo, signCommit); err != nil {
-		return gitinterface.ResetDueToError(err, repo, PolicyRef, originalCommitID)
+		for keyID := range targetsMetadata.Delegations.Keys {
+			referencedKeyIDs[keyID] = true
+		}
+	}
+
+	rootPublicKeys := []*tuf.Key{}
+	for _, key := range s.RootPublicKeys {
+		if referencedKeyIDs[key.KeyID] {
+			rootPublicKeys = append(rootPublicKeys, key)
+		}
 	}
+	s.RootPublicKeys = rootPublicKeys
 
 	return nil
 }
@@ -654,8 +2256,81 @@ func (s *State) HasTargetsRole(roleName string) bool {
 	return ok
 }
 
+// TopLevelTargetsRoleNames returns the names of the top level policies that
+// are declared in the root of trust and initialized in the State. The root of
+// trust records the top level policies other than TargetsRoleName explicitly.
+// TargetsRoleName is always first, followed by the other top level policies in
+// the order the root of trust lists them, which is the order they take
+// precedence in.
+func (s *State) TopLevelTargetsRoleNames() ([]string, error) {
+	rootMetadata, err := s.GetRootMetadata()
+	if err != nil {
+		return nil, err
+	}
+
+	roleNames := []string{}
+	for _, roleName := range topLevelTargetsRoleNames(rootMetadata) {
+		if s.HasTargetsRole(roleName) {
+			roleNames = append(roleNames, roleName)
+		}
+	}
+
+	return roleNames, nil
+}
+
+// targetsRoleNames returns the names of all the targets metadata in the State,
+// both top level and delegated. TargetsRoleName is first if present, followed
+// by the others in lexical order.
+func (s *State) targetsRoleNames() []string {
+	roleNames := []string{}
+	if s.TargetsEnvelope != nil {
+		roleNames = append(roleNames, TargetsRoleName)
+	}
+
+	delegatedRoleNames := make([]string, 0, len(s.DelegationEnvelopes))
+	for roleName := range s.DelegationEnvelopes {
+		delegatedRoleNames = append(delegatedRoleNames, roleName)
+	}
+	sort.Strings(delegatedRoleNames)
+
+	return append(roleNames, delegatedRoleNames...)
+}
+
+// isReservedRoleName indicates if the specified name is used by a role in the
+// root of trust that is not a policy.
+func isReservedRoleName(roleName string) bool {
+	return roleName == RootRoleName || roleName == RSLWriterRoleName || roleName == EmergencyRoleName
+}
+
+// isTopLevelPolicy indicates if the role with the specified name is a top
+// level policy in the root metadata.
+func isTopLevelPolicy(rootMetadata *tuf.RootMetadata, roleName string) bool {
+	for _, topLevelRoleName := range topLevelTargetsRoleNames(rootMetadata) {
+		if roleName == topLevelRoleName {
+			return true
+		}
+	}
+
+	return false
+}
+
+// topLevelTargetsRoleNames returns the names of the top level policies declared
+// in the root metadata. TargetsRoleName is always included first, even if the
+// root metadata does not declare it, so that its metadata is never treated as
+// a delegation.
+func topLevelTargetsRoleNames(rootMetadata *tuf.RootMetadata) []string {
+	roleNames := []string{TargetsRoleName}
+	for _, roleName := range rootMetadata.TopLevelPolicies {
+		if roleName != TargetsRoleName {
+			roleNames = append(roleNames, roleName)
+		}
+	}
+
+	return roleNames
+}
+
 func (s *State) findDelegationEntry(roleName string) (tuf.Delegation, error) {
-	topLevelTargetsMetadata, err := s.GetTargetsMetadata(TargetsRoleName)
+	topLevelRoleNames, err := s.TopLevelTargetsRoleNames()
 	if err != nil {
 		return tuf.Delegation{}, err
 	}
@@ -675,7 +2350,14 @@ func (s *State) findDelegationEntry(roleName string) (tuf.Delegation, error) {
 		delegationTargetsMetadata[name] = targetsMetadata
 	}
 
-	delegationsQueue := topLevelTargetsMetadata.Delegations.Roles
+	delegationsQueue := []tuf.Delegation{}
+	for _, topLevelRoleName := range topLevelRoleNames {
+		topLevelTargetsMetadata, err := s.GetTargetsMetadata(topLevelRoleName)
+		if err != nil {
+			return tuf.Delegation{}, err
+		}
+		delegationsQueue = append(delegationsQueue, topLevelTargetsMetadata.Delegations.Roles...)
+	}
 
 	for {
 		if len(delegationsQueue) == 0 {
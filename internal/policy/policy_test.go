@@ -4,10 +4,17 @@ package policy
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/gittuf/gittuf/internal/third_party/go-git"
 	"github.com/gittuf/gittuf/internal/third_party/go-git/storage/memory"
@@ -19,7 +26,10 @@ import (
 	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
 	"github.com/gittuf/gittuf/internal/signerverifier/gpg"
 	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing/filemode"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing/object"
 	"github.com/gittuf/gittuf/internal/tuf"
+	"github.com/jonboulle/clockwork"
 	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
 	sslibsv "github.com/secure-systems-lab/go-securesystemslib/signerverifier"
 	"github.com/stretchr/testify/assert"
@@ -112,6 +122,320 @@ func TestLoadStateForEntry(t *testing.T) {
 	}
 
 	assert.Equal(t, state, loadedState)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = LoadStateForEntry(ctx, repo, entry)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestLoadStateForUninitializedPolicy(t *testing.T) {
+	createInitializedRepository := func(t *testing.T) *git.Repository {
+		t.Helper()
+
+		repo, err := git.Init(memory.NewStorage(), memfs.New())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := InitializeNamespace(repo); err != nil {
+			t.Fatal(err)
+		}
+		if err := rsl.InitializeNamespace(repo); err != nil {
+			t.Fatal(err)
+		}
+
+		return repo
+	}
+
+	t.Run("freshly initialized namespaces", func(t *testing.T) {
+		repo := createInitializedRepository(t)
+
+		state, err := LoadCurrentState(testCtx, repo)
+		assert.ErrorIs(t, err, ErrPolicyNotInitialized)
+		assert.ErrorIs(t, err, rsl.ErrRSLEntryNotFound)
+		assert.Nil(t, state)
+	})
+
+	t.Run("entry for zero hash", func(t *testing.T) {
+		repo := createInitializedRepository(t)
+
+		entry := rsl.NewReferenceEntry(PolicyRef, plumbing.ZeroHash)
+		if err := entry.Commit(repo, false); err != nil {
+			t.Fatal(err)
+		}
+
+		_, err := LoadStateForEntry(testCtx, repo, entry)
+		assert.ErrorIs(t, err, ErrPolicyNotInitialized)
+
+		_, err = LoadCurrentState(testCtx, repo)
+		assert.ErrorIs(t, err, ErrPolicyNotInitialized)
+	})
+
+	t.Run("entry for empty tree", func(t *testing.T) {
+		repo := createInitializedRepository(t)
+
+		entry := rsl.NewReferenceEntry(PolicyRef, gitinterface.EmptyTree())
+		if err := entry.Commit(repo, false); err != nil {
+			t.Fatal(err)
+		}
+
+		// The empty tree is not a valid policy target, and it's not treated as
+		// an uninitialized policy either
+		_, err := LoadStateForEntry(testCtx, repo, entry)
+		assert.ErrorIs(t, err, ErrInvalidPolicyTree)
+		assert.NotErrorIs(t, err, ErrPolicyNotInitialized)
+		assert.Contains(t, err.Error(), "records the empty tree instead of a policy commit")
+
+		_, err = LoadCurrentState(testCtx, repo)
+		assert.ErrorIs(t, err, ErrInvalidPolicyTree)
+		assert.NotErrorIs(t, err, ErrPolicyNotInitialized)
+	})
+
+	t.Run("policy commit with empty tree", func(t *testing.T) {
+		repo := createInitializedRepository(t)
+
+		commitID, err := gitinterface.Commit(repo, gitinterface.EmptyTree(), PolicyRef, "Empty policy", false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		entry := rsl.NewReferenceEntry(PolicyRef, commitID)
+		if err := entry.Commit(repo, false); err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = LoadCurrentState(testCtx, repo)
+		var treeErr *InvalidPolicyTreeError
+		if assert.ErrorAs(t, err, &treeErr) {
+			assert.True(t, treeErr.EmptyTree)
+		}
+		assert.ErrorIs(t, err, ErrInvalidPolicyTree)
+		assert.NotErrorIs(t, err, ErrPolicyNotInitialized)
+	})
+}
+
+func TestLoadStateFromRef(t *testing.T) {
+	remoteName := "origin"
+	trackerRef := RemoteTrackerRef(remoteName)
+	assert.Equal(t, "refs/remotes/origin/gittuf/policy", trackerRef)
+
+	t.Run("policy in remote tracker", func(t *testing.T) {
+		repo, state := createTestRepository(t, createTestStateWithPolicy)
+
+		policyTip, err := gitinterface.GetTip(repo, PolicyRef)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := repo.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(trackerRef), policyTip)); err != nil {
+			t.Fatal(err)
+		}
+
+		loadedState, err := LoadStateFromRef(testCtx, repo, trackerRef)
+		assert.Nil(t, err)
+		assert.Equal(t, state, loadedState)
+
+		// The local policy ref is unaffected
+		localTip, err := gitinterface.GetTip(repo, PolicyRef)
+		assert.Nil(t, err)
+		assert.Equal(t, policyTip, localTip)
+	})
+
+	t.Run("missing remote tracker", func(t *testing.T) {
+		repo, _ := createTestRepository(t, createTestStateWithPolicy)
+
+		_, err := LoadStateFromRef(testCtx, repo, trackerRef)
+		assert.ErrorIs(t, err, ErrPolicyRefNotFound)
+	})
+
+	t.Run("empty policy ref", func(t *testing.T) {
+		repo, err := git.Init(memory.NewStorage(), memfs.New())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := InitializeNamespace(repo); err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = LoadStateFromRef(testCtx, repo, PolicyRef)
+		assert.ErrorIs(t, err, ErrPolicyRefNotFound)
+	})
+
+	t.Run("policy in remote tracker fails verification", func(t *testing.T) {
+		repo, _ := createTestRepository(t, createTestStateWithPolicy)
+
+		policyTip, err := gitinterface.GetTip(repo, PolicyRef)
+		if err != nil {
+			t.Fatal(err)
+		}
+		policyCommit, err := repo.CommitObject(policyTip)
+		if err != nil {
+			t.Fatal(err)
+		}
+		policyTree, err := repo.TreeObject(policyCommit.TreeHash)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Strip the signatures from the top level targets metadata
+		rootEntries := []object.TreeEntry{}
+		for _, entry := range policyTree.Entries {
+			if entry.Name == metadataTreeEntryName {
+				metadataTree, err := repo.TreeObject(entry.Hash)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				metadataEntries := []object.TreeEntry{}
+				for _, metadataEntry := range metadataTree.Entries {
+					if metadataEntry.Name == fmt.Sprintf("%s.json", TargetsRoleName) {
+						env, err := readEnvelope(testCtx, repo, metadataEntry.Hash)
+						if err != nil {
+							t.Fatal(err)
+						}
+						env.Signatures = nil
+						envContents, err := json.Marshal(env)
+						if err != nil {
+							t.Fatal(err)
+						}
+						metadataEntry.Hash, err = gitinterface.WriteBlob(repo, envContents)
+						if err != nil {
+							t.Fatal(err)
+						}
+					}
+					metadataEntries = append(metadataEntries, metadataEntry)
+				}
+
+				entry.Hash, err = gitinterface.WriteTree(repo, metadataEntries)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+			rootEntries = append(rootEntries, entry)
+		}
+		treeHash, err := gitinterface.WriteTree(repo, rootEntries)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := gitinterface.Commit(repo, treeHash, trackerRef, "Tamper with policy", false); err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = LoadStateFromRef(testCtx, repo, trackerRef)
+		assert.NotNil(t, err)
+		assert.NotErrorIs(t, err, ErrPolicyRefNotFound)
+	})
+}
+
+func TestLoadStateForEntryWithInvalidPolicyTree(t *testing.T) {
+	tests := map[string]struct {
+		modifyEntries       func([]object.TreeEntry) []object.TreeEntry
+		unexpectedEntries   []string
+		missingMetadataTree bool
+		missingKeysTree     bool
+	}{
+		"unexpected entry": {
+			modifyEntries: func(entries []object.TreeEntry) []object.TreeEntry {
+				return append(entries, object.TreeEntry{Name: "unexpected", Mode: filemode.Regular, Hash: gitinterface.EmptyBlob()})
+			},
+			unexpectedEntries: []string{"unexpected"},
+		},
+		"missing keys tree": {
+			modifyEntries: func(entries []object.TreeEntry) []object.TreeEntry {
+				return removeTreeEntry(entries, rootPublicKeysTreeEntryName)
+			},
+			missingKeysTree: true,
+		},
+		"missing metadata tree": {
+			modifyEntries: func(entries []object.TreeEntry) []object.TreeEntry {
+				return removeTreeEntry(entries, metadataTreeEntryName)
+			},
+			missingMetadataTree: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			repo, state := createTestRepository(t, createTestStateWithOnlyRoot)
+
+			policyRef, err := repo.Reference(plumbing.ReferenceName(PolicyRef), true)
+			if err != nil {
+				t.Fatal(err)
+			}
+			policyCommit, err := repo.CommitObject(policyRef.Hash())
+			if err != nil {
+				t.Fatal(err)
+			}
+			policyTree, err := repo.TreeObject(policyCommit.TreeHash)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			entries := make([]object.TreeEntry, len(policyTree.Entries))
+			copy(entries, policyTree.Entries)
+
+			treeHash, err := gitinterface.WriteTree(repo, test.modifyEntries(entries))
+			if err != nil {
+				t.Fatal(err)
+			}
+			commitID, err := gitinterface.Commit(repo, treeHash, PolicyRef, "Corrupt policy", false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			entry := rsl.NewReferenceEntry(PolicyRef, commitID)
+			if err := entry.Commit(repo, false); err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = LoadStateForEntry(testCtx, repo, entry)
+			assert.ErrorIs(t, err, ErrInvalidPolicyTree)
+
+			var treeErr *InvalidPolicyTreeError
+			if assert.ErrorAs(t, err, &treeErr) {
+				assert.Equal(t, test.unexpectedEntries, treeErr.UnexpectedEntries)
+				assert.Equal(t, test.missingMetadataTree, treeErr.MissingMetadataTree)
+				assert.Equal(t, test.missingKeysTree, treeErr.MissingKeysTree)
+			}
+
+			recoveredState, err := RecoverPolicyState(testCtx, repo, entry)
+			assert.ErrorIs(t, err, ErrInvalidPolicyTree)
+			if assert.NotNil(t, recoveredState) {
+				if test.missingMetadataTree {
+					assert.Nil(t, recoveredState.RootEnvelope)
+				} else {
+					assert.Equal(t, state.RootEnvelope, recoveredState.RootEnvelope)
+				}
+
+				if test.missingKeysTree {
+					assert.Nil(t, recoveredState.RootPublicKeys)
+				} else {
+					assert.Equal(t, state.RootPublicKeys, recoveredState.RootPublicKeys)
+				}
+			}
+		})
+	}
+}
+
+func TestRecoverPolicyState(t *testing.T) {
+	repo, state := createTestRepository(t, createTestStateWithPolicy)
+
+	entry, _, err := rsl.GetLatestReferenceEntryForRef(repo, PolicyRef)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	recoveredState, err := RecoverPolicyState(testCtx, repo, entry)
+	assert.Nil(t, err)
+	assert.Equal(t, state, recoveredState)
+}
+
+func removeTreeEntry(entries []object.TreeEntry, name string) []object.TreeEntry {
+	filtered := []object.TreeEntry{}
+	for _, entry := range entries {
+		if entry.Name != name {
+			filtered = append(filtered, entry)
+		}
+	}
+	return filtered
 }
 
 func TestStateKeys(t *testing.T) {
@@ -143,6 +467,61 @@ func TestStateKeys(t *testing.T) {
 	assert.Equal(t, expectedKeys, keys)
 }
 
+func TestStateUnreferencedKeys(t *testing.T) {
+	t.Run("no unreferenced keys", func(t *testing.T) {
+		state := createTestStateWithPolicy(t)
+
+		keyIDs, err := state.UnreferencedKeys()
+		assert.Nil(t, err)
+		assert.Empty(t, keyIDs)
+	})
+
+	t.Run("orphaned keys", func(t *testing.T) {
+		state := createTestStateWithPolicy(t)
+
+		orphanedDelegationKeyBytes, err := os.ReadFile(filepath.Join("test-data", "targets-1.pub"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		orphanedDelegationKey, err := tuf.LoadKeyFromBytes(orphanedDelegationKeyBytes)
+		if err != nil {
+			t.Fatal(err)
+		}
+		orphanedRootKeyBytes, err := os.ReadFile(filepath.Join("test-data", "targets-2.pub"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		orphanedRootKey, err := tuf.LoadKeyFromBytes(orphanedRootKeyBytes)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// The delegation key is added to the targets metadata without a rule
+		// that authorizes it
+		targetsMetadata, err := state.GetTargetsMetadata(TargetsRoleName)
+		if err != nil {
+			t.Fatal(err)
+		}
+		targetsMetadata.Delegations.AddKey(orphanedDelegationKey)
+		state.TargetsEnvelope, err = dsse.CreateEnvelope(targetsMetadata)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// The root key lingers in the keys tree after being rotated out
+		state.RootPublicKeys = append(state.RootPublicKeys, orphanedRootKey)
+
+		expectedKeyIDs := []string{orphanedDelegationKey.KeyID, orphanedRootKey.KeyID}
+		if expectedKeyIDs[0] > expectedKeyIDs[1] {
+			expectedKeyIDs[0], expectedKeyIDs[1] = expectedKeyIDs[1], expectedKeyIDs[0]
+		}
+
+		keyIDs, err := state.UnreferencedKeys()
+		assert.Nil(t, err)
+		assert.Equal(t, expectedKeyIDs, keyIDs)
+	})
+}
+
 func TestStateVerify(t *testing.T) {
 	state := createTestStateWithOnlyRoot(t)
 
@@ -163,69 +542,1544 @@ func TestStateVerify(t *testing.T) {
 	assert.NotNil(t, err)
 }
 
-func TestStateCommit(t *testing.T) {
-	repo, _ := createTestRepository(t, createTestStateWithOnlyRoot)
-
-	policyRef, err := repo.Reference(plumbing.ReferenceName(PolicyRef), true)
+func TestStateVerifyRootPublicKeys(t *testing.T) {
+	rootKey, err := tuf.LoadKeyFromBytes(rootPubKeyBytes)
 	if err != nil {
-		t.Error(err)
+		t.Fatal(err)
 	}
-	assert.NotEqual(t, plumbing.ZeroHash, policyRef.Hash())
+	targets1Key := loadTestKey(t, "targets-1.pub")
+	targets2Key := loadTestKey(t, "targets-2.pub")
 
-	rslRef, err := repo.Reference(plumbing.ReferenceName(rsl.Ref), true)
+	t.Run("root key missing from keys tree", func(t *testing.T) {
+		rootMetadata := InitializeRootMetadata(rootKey)
+		rootMetadata.AddKey(targets1Key)
+		rootMetadata.AddRole(RootRoleName, tuf.Role{
+			KeyIDs:    []string{rootKey.KeyID, targets1Key.KeyID},
+			Threshold: 1,
+		})
+
+		state := &State{
+			RootPublicKeys: []*tuf.Key{rootKey, targets1Key},
+			RootEnvelope:   signTestEnvelope(t, rootMetadata, rootKeyBytes, loadTestKeyBytes(t, "targets-1")),
+		}
+		err := state.Verify(testCtx)
+		assert.Nil(t, err)
+
+		// The second root key is removed from the keys tree
+		state.RootPublicKeys = []*tuf.Key{rootKey}
+		err = state.Verify(testCtx)
+		assert.ErrorIs(t, err, ErrRootKeysMismatch)
+	})
+
+	t.Run("key in keys tree does not match root metadata", func(t *testing.T) {
+		rootMetadata := AddTargetsKey(InitializeRootMetadata(rootKey), targets1Key)
+
+		// The keys tree has a different key masquerading as the targets key
+		spoofedKey := *targets2Key
+		spoofedKey.KeyID = targets1Key.KeyID
+		state := &State{RootPublicKeys: []*tuf.Key{rootKey, &spoofedKey}}
+
+		err := state.verifyRootPublicKeys(rootMetadata)
+		assert.ErrorIs(t, err, ErrRootKeysMismatch)
+
+		state.RootPublicKeys = []*tuf.Key{rootKey, targets1Key}
+		err = state.verifyRootPublicKeys(rootMetadata)
+		assert.Nil(t, err)
+	})
+}
+
+func TestStateVerifyEmergencyRoleKeys(t *testing.T) {
+	state := createTestStateWithEmergencyRole(t)
+	err := state.Verify(testCtx)
+	assert.Nil(t, err)
+
+	rootKey, err := tuf.LoadKeyFromBytes(rootPubKeyBytes)
 	if err != nil {
-		t.Error(err)
+		t.Fatal(err)
 	}
-	assert.NotEqual(t, plumbing.ZeroHash, rslRef.Hash())
 
-	tmpEntry, err := rsl.GetEntry(repo, rslRef.Hash())
-	if err != nil {
-		t.Error(err)
+	// The root key is also declared as an emergency key, bypassing the check
+	// in AddEmergencyKey
+	rootMetadata := InitializeRootMetadata(rootKey)
+	rootMetadata.AddRole(EmergencyRoleName, tuf.Role{
+		KeyIDs:    []string{rootKey.KeyID},
+		Threshold: 1,
+	})
+
+	state = &State{
+		RootPublicKeys: []*tuf.Key{rootKey},
+		RootEnvelope:   signTestEnvelope(t, rootMetadata, rootKeyBytes),
 	}
-	entry := tmpEntry.(*rsl.ReferenceEntry)
-	assert.Equal(t, entry.TargetID, policyRef.Hash())
+	err = state.Verify(testCtx)
+	assert.ErrorIs(t, err, ErrEmergencyKeyNotDistinct)
 }
 
-func TestStateGetRootMetadata(t *testing.T) {
-	state := createTestStateWithOnlyRoot(t)
+func TestStateVerifySpecVersion(t *testing.T) {
+	rootKey, err := tuf.LoadKeyFromBytes(rootPubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
 
-	rootMetadata, err := state.GetRootMetadata()
-	assert.Nil(t, err)
-	assert.Equal(t, 1, rootMetadata.Version)
-	assert.Equal(t, "52e3b8e73279d6ebdd62a5016e2725ff284f569665eb92ccb145d83817a02997", rootMetadata.Roles[RootRoleName].KeyIDs[0])
+	t.Run("unknown fields are tolerated and preserved", func(t *testing.T) {
+		rootMetadata := InitializeRootMetadata(rootKey)
+		rootMetadata.SpecVersion = "1.5"
+		rootMetadata.UnrecognizedFields = map[string]json.RawMessage{
+			"future_field": json.RawMessage(`{"enabled":true}`),
+		}
+
+		state := &State{
+			RootPublicKeys: []*tuf.Key{rootKey},
+			RootEnvelope:   signTestEnvelope(t, rootMetadata, rootKeyBytes),
+		}
+		err := state.Verify(testCtx)
+		assert.Nil(t, err)
+
+		loadedRootMetadata, err := state.GetRootMetadata()
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, rootMetadata.UnrecognizedFields, loadedRootMetadata.UnrecognizedFields)
+	})
+
+	t.Run("root of trust with newer spec version", func(t *testing.T) {
+		rootMetadata := InitializeRootMetadata(rootKey)
+		rootMetadata.SpecVersion = "2.0"
+
+		state := &State{
+			RootPublicKeys: []*tuf.Key{rootKey},
+			RootEnvelope:   signTestEnvelope(t, rootMetadata, rootKeyBytes),
+		}
+		err := state.Verify(testCtx)
+		assert.ErrorIs(t, err, tuf.ErrUnsupportedSpecVersion)
+	})
+
+	t.Run("policy with newer spec version", func(t *testing.T) {
+		state := createTestStateWithPolicy(t)
+
+		targetsMetadata, err := state.GetTargetsMetadata(TargetsRoleName)
+		if err != nil {
+			t.Fatal(err)
+		}
+		targetsMetadata.SpecVersion = "2.0"
+		state.TargetsEnvelope = signTestEnvelope(t, targetsMetadata, rootKeyBytes)
+
+		err = state.Verify(testCtx)
+		assert.ErrorIs(t, err, tuf.ErrUnsupportedSpecVersion)
+	})
 }
 
-func TestStateFindPublicKeysForPath(t *testing.T) {
-	state := createTestStateWithPolicy(t)
+func TestStateVerifyWithRSARootKeys(t *testing.T) {
+	for _, bits := range []int{2048, 3072, 4096} {
+		t.Run(fmt.Sprintf("%d bits", bits), func(t *testing.T) {
+			privateKey, err := rsa.GenerateKey(rand.Reader, bits)
+			if err != nil {
+				t.Fatal(err)
+			}
+			publicKeyBytes, err := x509.MarshalPKIXPublicKey(privateKey.Public())
+			if err != nil {
+				t.Fatal(err)
+			}
 
-	gpgKeyBytes, err := os.ReadFile(filepath.Join("test-data", "gpg-pubkey.asc"))
-	if err != nil {
-		t.Fatal(err)
+			key := &tuf.Key{
+				KeyType:             signerverifier.RSAKeyType,
+				Scheme:              sslibsv.RSAKeyScheme,
+				KeyIDHashAlgorithms: []string{"sha256", "sha512"},
+				KeyVal: sslibsv.KeyVal{
+					Public:  string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicKeyBytes})),
+					Private: string(pem.EncodeToMemory(&pem.Block{Type: sslibsv.RSAPrivateKeyPEM, Bytes: x509.MarshalPKCS1PrivateKey(privateKey)})),
+				},
+			}
+			keyBytes, err := json.Marshal(key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rootKey, err := tuf.LoadKeyFromBytes(keyBytes)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rootKey.KeyVal.Private = ""
+
+			state := &State{
+				RootPublicKeys: []*tuf.Key{rootKey},
+				RootEnvelope:   signTestEnvelope(t, InitializeRootMetadata(rootKey), keyBytes),
+			}
+			err = state.Verify(testCtx)
+			assert.Nil(t, err)
+		})
 	}
-	gpgKey, err := gpg.LoadGPGKeyFromBytes(gpgKeyBytes)
+}
+
+func TestStateVerifyWithStrictDelegationScope(t *testing.T) {
+	gpgKey, err := gpg.LoadGPGKeyFromBytes(gpgPubKeyBytes)
 	if err != nil {
 		t.Fatal(err)
 	}
+	targets1Key := loadTestKey(t, "targets-1.pub")
+
+	// createState returns a state where protect-src delegates to a rule
+	// protecting the specified patterns, optionally restricted to refs
+	createState := func(t *testing.T, patterns, refPatterns []string, deny bool) *State {
+		t.Helper()
+
+		state := createTestStateWithPolicy(t)
+
+		targetsMetadata, err := state.GetTargetsMetadata(TargetsRoleName)
+		if err != nil {
+			t.Fatal(err)
+		}
+		targetsMetadata, err = AddOrUpdateDelegation(targetsMetadata, "protect-src", []*tuf.Key{targets1Key}, []string{"file:src/*", "file:src/*/*"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		targetsMetadata, err = SetRuleRefs(targetsMetadata, "protect-src", []string{"refs/heads/main", "refs/heads/release/*"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		state.TargetsEnvelope = signTestEnvelope(t, targetsMetadata, rootKeyBytes)
+
+		delegatedMetadata := InitializeTargetsMetadata()
+		if deny {
+			delegatedMetadata, err = AddOrUpdateDenyRule(delegatedMetadata, "protect-sub", patterns)
+		} else {
+			delegatedMetadata, err = AddOrUpdateDelegation(delegatedMetadata, "protect-sub", []*tuf.Key{gpgKey}, patterns)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(refPatterns) > 0 {
+			delegatedMetadata, err = SetRuleRefs(delegatedMetadata, "protect-sub", refPatterns)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		state.DelegationEnvelopes = map[string]*sslibdsse.Envelope{
+			"protect-src": signTestEnvelope(t, delegatedMetadata, targets1KeyBytes),
+		}
+
+		return state
+	}
 
 	tests := map[string]struct {
-		path string
-		keys []*sslibsv.SSLibKey
+		patterns      []string
+		refPatterns   []string
+		deny          bool
+		expectedError error
 	}{
-		"public keys for refs/heads/main": {
-			path: "git:refs/heads/main",
-			keys: []*sslibsv.SSLibKey{gpgKey},
+		"same path": {
+			patterns: []string{"file:src/*"},
 		},
-		"public keys for unprotected branch": {
-			path: "git:refs/heads/unprotected",
-			keys: []*sslibsv.SSLibKey{},
+		"narrower path": {
+			patterns: []string{"file:src/lib*", "file:src/main.go", "file:src/pkg/*.go"},
 		},
-	}
+		"narrower refs": {
+			patterns:    []string{"file:src/lib*"},
+			refPatterns: []string{"refs/heads/release/v1"},
+		},
+		"path outside delegated scope": {
+			patterns:      []string{"file:docs/*"},
+			expectedError: ErrDelegationEscalatesScope,
+		},
+		"path deeper than delegated scope": {
+			patterns:      []string{"file:src/*/*/*"},
+			expectedError: ErrDelegationEscalatesScope,
+		},
+		"wildcard broader than delegated scope": {
+			patterns:      []string{"file:*"},
+			expectedError: ErrDelegationEscalatesScope,
+		},
+		"one of several paths outside delegated scope": {
+			patterns:      []string{"file:src/lib*", "file:ci/*"},
+			expectedError: ErrDelegationEscalatesScope,
+		},
+		"refs outside delegated scope": {
+			patterns:      []string{"file:src/lib*"},
+			refPatterns:   []string{"refs/heads/*"},
+			expectedError: ErrDelegationEscalatesScope,
+		},
+		"deny rule outside delegated scope": {
+			patterns: []string{"file:docs/*"},
+			deny:     true,
+		},
+	}
+
+	for name, test := range tests {
+		state := createState(t, test.patterns, test.refPatterns, test.deny)
+
+		// The scope is only checked in strict mode
+		err := state.Verify(testCtx)
+		assert.Nil(t, err, fmt.Sprintf("unexpected error in test '%s'", name))
+
+		err = state.Verify(testCtx, WithStrictDelegationScope())
+		if test.expectedError != nil {
+			assert.ErrorIs(t, err, test.expectedError, fmt.Sprintf("unexpected error in test '%s'", name))
+		} else {
+			assert.Nil(t, err, fmt.Sprintf("unexpected error in test '%s'", name))
+		}
+	}
+}
+
+func TestStateCommit(t *testing.T) {
+	repo, _ := createTestRepository(t, createTestStateWithOnlyRoot)
+
+	policyRef, err := repo.Reference(plumbing.ReferenceName(PolicyRef), true)
+	if err != nil {
+		t.Error(err)
+	}
+	assert.NotEqual(t, plumbing.ZeroHash, policyRef.Hash())
+
+	rslRef, err := repo.Reference(plumbing.ReferenceName(rsl.Ref), true)
+	if err != nil {
+		t.Error(err)
+	}
+	assert.NotEqual(t, plumbing.ZeroHash, rslRef.Hash())
+
+	tmpEntry, err := rsl.GetEntry(repo, rslRef.Hash())
+	if err != nil {
+		t.Error(err)
+	}
+	entry := tmpEntry.(*rsl.ReferenceEntry)
+	assert.Equal(t, entry.TargetID, policyRef.Hash())
+}
+
+func TestStateCommitWithPruneUnreferencedKeys(t *testing.T) {
+	// The old key has been rotated out of root metadata, but it still lingers
+	// in the state's root public keys
+	oldKeyBytes, err := os.ReadFile(filepath.Join("test-data", "targets-1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	oldSigner, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(oldKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	oldPubKeyBytes, err := os.ReadFile(filepath.Join("test-data", "targets-1.pub"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	oldKey, err := tuf.LoadKeyFromBytes(oldPubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	createState := func(t testing.TB) *State {
+		t.Helper()
+
+		state := createTestStateWithOnlyRoot(t)
+		rootEnv, err := dsse.SignEnvelope(context.Background(), state.RootEnvelope, oldSigner)
+		if err != nil {
+			t.Fatal(err)
+		}
+		state.RootEnvelope = rootEnv
+		state.RootPublicKeys = append(state.RootPublicKeys, oldKey)
+
+		return state
+	}
+
+	t.Run("without pruning", func(t *testing.T) {
+		repo, _ := createTestRepository(t, createState)
+
+		state, err := LoadCurrentState(context.Background(), repo)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, 2, len(state.RootPublicKeys))
+	})
+
+	t.Run("with pruning", func(t *testing.T) {
+		repo, _ := createTestRepository(t, createState)
+
+		state, err := LoadCurrentState(context.Background(), repo)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = state.Commit(context.Background(), repo, "Prune keys", false, WithPruneUnreferencedKeys())
+		assert.Nil(t, err)
+
+		state, err = LoadCurrentState(context.Background(), repo)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if assert.Equal(t, 1, len(state.RootPublicKeys)) {
+			assert.NotEqual(t, oldKey.KeyID, state.RootPublicKeys[0].KeyID)
+		}
+	})
+}
+
+func TestStateCommitWithCommitter(t *testing.T) {
+	repo, state := createTestRepository(t, createTestStateWithOnlyRoot)
+
+	err := state.Commit(context.Background(), repo, "Use gittuf identity", false, WithCommitter("gittuf", "gittuf@example.com"))
+	assert.Nil(t, err)
+
+	policyTip, err := gitinterface.GetTip(repo, PolicyRef)
+	if err != nil {
+		t.Fatal(err)
+	}
+	policyCommit, err := repo.CommitObject(policyTip)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "gittuf", policyCommit.Committer.Name)
+	assert.Equal(t, "gittuf@example.com", policyCommit.Committer.Email)
+
+	rslTip, err := gitinterface.GetTip(repo, rsl.Ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rslCommit, err := repo.CommitObject(rslTip)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "gittuf", rslCommit.Committer.Name)
+	assert.Equal(t, "gittuf@example.com", rslCommit.Committer.Email)
+}
+
+func TestStateCommitWithClock(t *testing.T) {
+	repo, state := createTestRepository(t, createTestStateWithOnlyRoot)
+
+	fixedClock := clockwork.NewFakeClockAt(time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC))
+
+	err := state.Commit(context.Background(), repo, "Use fixed time", false, WithClock(fixedClock))
+	assert.Nil(t, err)
+
+	for _, refName := range []string{PolicyRef, rsl.Ref} {
+		tip, err := gitinterface.GetTip(repo, refName)
+		if err != nil {
+			t.Fatal(err)
+		}
+		commit, err := repo.CommitObject(tip)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.True(t, fixedClock.Now().Equal(commit.Committer.When))
+		assert.True(t, fixedClock.Now().Equal(commit.Author.When))
+	}
+}
+
+func TestStateCommitReusesUnchangedBlobs(t *testing.T) {
+	signer, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(rootKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// setTargetsVersion updates the version of the state's targets metadata
+	// and re-signs it
+	setTargetsVersion := func(t *testing.T, state *State, version int) {
+		t.Helper()
+
+		targetsMetadata, err := state.GetTargetsMetadata(TargetsRoleName)
+		if err != nil {
+			t.Fatal(err)
+		}
+		targetsMetadata.SetVersion(version)
+
+		env, err := dsse.CreateEnvelope(targetsMetadata)
+		if err != nil {
+			t.Fatal(err)
+		}
+		env, err = dsse.SignEnvelope(context.Background(), env, signer)
+		if err != nil {
+			t.Fatal(err)
+		}
+		state.TargetsEnvelope = env
+	}
+
+	// getObjectStoreSize returns the number of objects in the repository and
+	// their total size
+	getObjectStoreSize := func(t *testing.T, repo *git.Repository) (int, int64) {
+		t.Helper()
+
+		iter, err := repo.Storer.IterEncodedObjects(plumbing.AnyObject)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		count, size := 0, int64(0)
+		if err := iter.ForEach(func(obj plumbing.EncodedObject) error {
+			count++
+			size += obj.Size()
+			return nil
+		}); err != nil {
+			t.Fatal(err)
+		}
+
+		return count, size
+	}
+
+	t.Run("unchanged key stored in a different encoding", func(t *testing.T) {
+		repo, state := createTestRepository(t, createTestStateWithPolicy)
+
+		// Rewrite the policy tree with the keys stored as indented JSON
+		policyTip, err := gitinterface.GetTip(repo, PolicyRef)
+		if err != nil {
+			t.Fatal(err)
+		}
+		policyCommit, err := repo.CommitObject(policyTip)
+		if err != nil {
+			t.Fatal(err)
+		}
+		policyTree, err := repo.TreeObject(policyCommit.TreeHash)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		keyBlobIDs := map[string]plumbing.Hash{}
+		rootEntries := []object.TreeEntry{}
+		for _, entry := range policyTree.Entries {
+			if entry.Name == rootPublicKeysTreeEntryName {
+				keysEntries := []object.TreeEntry{}
+				for _, key := range state.RootPublicKeys {
+					keyContents, err := json.MarshalIndent(key, "", "  ")
+					if err != nil {
+						t.Fatal(err)
+					}
+					blobID, err := gitinterface.WriteBlob(repo, keyContents)
+					if err != nil {
+						t.Fatal(err)
+					}
+					keyBlobIDs[key.KeyID] = blobID
+					keysEntries = append(keysEntries, object.TreeEntry{Name: key.KeyID, Mode: filemode.Regular, Hash: blobID})
+				}
+
+				entry.Hash, err = gitinterface.WriteTree(repo, keysEntries)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+			rootEntries = append(rootEntries, entry)
+		}
+		policyTreeID, err := gitinterface.WriteTree(repo, rootEntries)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := gitinterface.Commit(repo, policyTreeID, PolicyRef, "Rewrite keys", false); err != nil {
+			t.Fatal(err)
+		}
+
+		setTargetsVersion(t, state, 2)
+		err = state.Commit(context.Background(), repo, "Update targets", false)
+		assert.Nil(t, err)
+
+		policyTip, err = gitinterface.GetTip(repo, PolicyRef)
+		if err != nil {
+			t.Fatal(err)
+		}
+		policyCommit, err = repo.CommitObject(policyTip)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for keyID, blobID := range keyBlobIDs {
+			keyPath := fmt.Sprintf("%s/%s", rootPublicKeysTreeEntryName, keyID)
+			committedBlobID, err := gitinterface.GetPathIDInTree(repo, policyCommit.TreeHash, keyPath)
+			assert.Nil(t, err)
+			assert.Equal(t, blobID, committedBlobID)
+		}
+	})
+
+	// Each policy update that only changes the targets metadata must add
+	// exactly the new targets blob, the metadata tree, the policy tree, the
+	// policy commit, and the RSL entry to the object store
+	numUpdates := 100
+	expectedObjects := numUpdates * 5
+
+	t.Run("object store growth", func(t *testing.T) {
+		repo, state := createTestRepository(t, createTestStateWithPolicy)
+
+		initialCount, initialSize := getObjectStoreSize(t, repo)
+		for i := 0; i < numUpdates; i++ {
+			setTargetsVersion(t, state, i+2)
+			if err := state.Commit(context.Background(), repo, fmt.Sprintf("Update %d", i), false); err != nil {
+				t.Fatal(err)
+			}
+		}
+		count, size := getObjectStoreSize(t, repo)
+
+		assert.Equal(t, expectedObjects, count-initialCount)
+		t.Logf("%d policy updates added %d objects totalling %d bytes", numUpdates, count-initialCount, size-initialSize)
+	})
+}
+
+func TestStateStageTree(t *testing.T) {
+	repo, state := createTestRepository(t, createTestStateWithPolicy)
+
+	policyTip, err := gitinterface.GetTip(repo, PolicyRef)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rslTip, err := gitinterface.GetTip(repo, rsl.Ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fixedClock := clockwork.NewFakeClockAt(time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC))
+	opts := []CommitOption{WithClock(fixedClock), WithCommitter("gittuf", "gittuf@example.com")}
+
+	staged, err := state.StageTree(testCtx, repo, "Staged policy", opts...)
+	assert.Nil(t, err)
+
+	// The refs are not updated
+	currentPolicyTip, err := gitinterface.GetTip(repo, PolicyRef)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, policyTip, currentPolicyTip)
+	currentRSLTip, err := gitinterface.GetTip(repo, rsl.Ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, rslTip, currentRSLTip)
+
+	// The staged tree is written and can be loaded
+	_, err = repo.TreeObject(staged.TreeID)
+	assert.Nil(t, err)
+	assert.Equal(t, staged.TreeID, staged.Commit.TreeHash)
+	assert.Equal(t, []plumbing.Hash{policyTip}, staged.Commit.ParentHashes)
+	assert.True(t, strings.HasPrefix(string(staged.CommitBytes), fmt.Sprintf("tree %s\nparent %s\n", staged.TreeID.String(), policyTip.String())))
+
+	// Committing the policy with the same options creates the staged commit
+	err = state.Commit(testCtx, repo, "Staged policy", false, opts...)
+	assert.Nil(t, err)
+
+	newPolicyTip, err := gitinterface.GetTip(repo, PolicyRef)
+	if err != nil {
+		t.Fatal(err)
+	}
+	enc := &plumbing.MemoryObject{}
+	if err := staged.Commit.Encode(enc); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, newPolicyTip, enc.Hash())
+
+	// An invalid state is not staged
+	state.RootEnvelope.Signatures = nil
+	_, err = state.StageTree(testCtx, repo, "Invalid policy")
+	assert.NotNil(t, err)
+}
+
+func TestStateCommitWithVersionBump(t *testing.T) {
+	signer, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(rootKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gpgKey, err := gpg.LoadGPGKeyFromBytes(gpgPubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// modifyTargets adds a rule to the state's targets metadata and sets its
+	// version to the specified value
+	modifyTargets := func(t *testing.T, state *State, version int) {
+		t.Helper()
+
+		targetsMetadata, err := state.GetTargetsMetadata(TargetsRoleName)
+		if err != nil {
+			t.Fatal(err)
+		}
+		targetsMetadata, err = AddOrUpdateDelegation(targetsMetadata, "protect-feature", []*tuf.Key{gpgKey}, []string{"git:refs/heads/feature"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		targetsMetadata.SetVersion(version)
+
+		env, err := dsse.CreateEnvelope(targetsMetadata)
+		if err != nil {
+			t.Fatal(err)
+		}
+		env, err = dsse.SignEnvelope(context.Background(), env, signer)
+		if err != nil {
+			t.Fatal(err)
+		}
+		state.TargetsEnvelope = env
+	}
+
+	getTargetsVersion := func(t *testing.T, state *State) int {
+		t.Helper()
+
+		targetsMetadata, err := state.GetTargetsMetadata(TargetsRoleName)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return targetsMetadata.Version
+	}
+
+	t.Run("unchanged metadata", func(t *testing.T) {
+		repo, state := createTestRepository(t, createTestStateWithPolicy)
+
+		err := state.Commit(context.Background(), repo, "Unchanged", false, WithStrictVersionBump(RootRoleName, TargetsRoleName))
+		assert.Nil(t, err)
+	})
+
+	t.Run("changed metadata without version bump", func(t *testing.T) {
+		repo, state := createTestRepository(t, createTestStateWithPolicy)
+		committedVersion := getTargetsVersion(t, state)
+
+		modifyTargets(t, state, committedVersion)
+
+		err := state.Commit(context.Background(), repo, "Add rule", false, WithStrictVersionBump(TargetsRoleName))
+		assert.ErrorIs(t, err, ErrMetadataVersionNotBumped)
+
+		// Without a version bump policy, the metadata is committed as is
+		err = state.Commit(context.Background(), repo, "Add rule", false)
+		assert.Nil(t, err)
+	})
+
+	t.Run("changed metadata with version bump", func(t *testing.T) {
+		repo, state := createTestRepository(t, createTestStateWithPolicy)
+		committedVersion := getTargetsVersion(t, state)
+
+		modifyTargets(t, state, committedVersion+1)
+
+		err := state.Commit(context.Background(), repo, "Add rule", false, WithStrictVersionBump(TargetsRoleName))
+		assert.Nil(t, err)
+	})
+
+	t.Run("auto version bump", func(t *testing.T) {
+		repo, state := createTestRepository(t, createTestStateWithPolicy)
+		committedVersion := getTargetsVersion(t, state)
+
+		modifyTargets(t, state, committedVersion)
+
+		err := state.Commit(context.Background(), repo, "Add rule", false, WithAutoVersionBump(TargetsRoleName, signer))
+		assert.Nil(t, err)
+
+		state, err = LoadCurrentState(context.Background(), repo)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, committedVersion+1, getTargetsVersion(t, state))
+		_, err = state.findDelegationEntry("protect-feature")
+		assert.Nil(t, err)
+	})
+
+	t.Run("auto version bump without signers", func(t *testing.T) {
+		repo, state := createTestRepository(t, createTestStateWithPolicy)
+		committedVersion := getTargetsVersion(t, state)
+
+		modifyTargets(t, state, committedVersion)
+
+		err := state.Commit(context.Background(), repo, "Add rule", false, WithAutoVersionBump(TargetsRoleName))
+		assert.ErrorIs(t, err, ErrNoVersionBumpSigners)
+	})
+}
+
+func TestStateGetRootMetadata(t *testing.T) {
+	state := createTestStateWithOnlyRoot(t)
+
+	rootMetadata, err := state.GetRootMetadata()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, rootMetadata.Version)
+	assert.Equal(t, "52e3b8e73279d6ebdd62a5016e2725ff284f569665eb92ccb145d83817a02997", rootMetadata.Roles[RootRoleName].KeyIDs[0])
+}
+
+func TestStateSetTargetsEnvelope(t *testing.T) {
+	// The envelope recorded for each role is the targets policy's envelope
+	env := createTestStateWithPolicy(t).TargetsEnvelope
+
+	t.Run("targets policy", func(t *testing.T) {
+		state := createTestStateWithMultiplePolicies(t)
+
+		err := state.SetTargetsEnvelope(TargetsRoleName, env)
+		assert.Nil(t, err)
+		assert.Equal(t, env, state.TargetsEnvelope)
+	})
+
+	t.Run("second top level policy", func(t *testing.T) {
+		state := createTestStateWithMultiplePolicies(t)
+
+		err := state.SetTargetsEnvelope("security", env)
+		assert.Nil(t, err)
+		assert.Equal(t, env, state.DelegationEnvelopes["security"])
+	})
+
+	t.Run("rule in second top level policy", func(t *testing.T) {
+		state := createTestStateWithMultiplePolicies(t)
+
+		err := state.SetTargetsEnvelope("protect-ci", env)
+		assert.Nil(t, err)
+		assert.Equal(t, env, state.DelegationEnvelopes["protect-ci"])
+	})
+
+	t.Run("unknown policy", func(t *testing.T) {
+		state := createTestStateWithMultiplePolicies(t)
+
+		err := state.SetTargetsEnvelope("unknown", env)
+		assert.ErrorIs(t, err, ErrMetadataNotFound)
+		assert.NotContains(t, state.DelegationEnvelopes, "unknown")
+	})
+
+	t.Run("reserved role", func(t *testing.T) {
+		state := createTestStateWithMultiplePolicies(t)
+
+		err := state.SetTargetsEnvelope(RootRoleName, env)
+		assert.ErrorIs(t, err, ErrMetadataNotFound)
+		assert.NotContains(t, state.DelegationEnvelopes, RootRoleName)
+	})
+}
+
+func TestStateFindRSLWriterKeys(t *testing.T) {
+	t.Run("role not declared", func(t *testing.T) {
+		state := createTestStateWithPolicy(t)
+
+		keys, err := state.FindRSLWriterKeys()
+		assert.Nil(t, err)
+		assert.Nil(t, keys)
+	})
+
+	t.Run("role declared", func(t *testing.T) {
+		state := createTestStateWithRSLWriter(t)
+
+		gpgKey, err := gpg.LoadGPGKeyFromBytes(gpgPubKeyBytes)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		keys, err := state.FindRSLWriterKeys()
+		assert.Nil(t, err)
+		assert.Equal(t, []*tuf.Key{gpgKey}, keys)
+	})
+}
+
+func TestStateFindPublicKeysForPath(t *testing.T) {
+	state := createTestStateWithPolicy(t)
+
+	gpgKeyBytes, err := os.ReadFile(filepath.Join("test-data", "gpg-pubkey.asc"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	gpgKey, err := gpg.LoadGPGKeyFromBytes(gpgKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		path string
+		keys []*sslibsv.SSLibKey
+	}{
+		"public keys for refs/heads/main": {
+			path: "git:refs/heads/main",
+			keys: []*sslibsv.SSLibKey{gpgKey},
+		},
+		"public keys for unprotected branch": {
+			path: "git:refs/heads/unprotected",
+			keys: []*sslibsv.SSLibKey{},
+		},
+	}
+
+	for name, test := range tests {
+		keys, err := state.FindPublicKeysForPath(context.Background(), test.path)
+		assert.Nil(t, err, fmt.Sprintf("unexpected error in test '%s'", name))
+		assert.Equal(t, test.keys, keys, fmt.Sprintf("policy keys for path '%s' don't match expected keys in test '%s'", test.path, name))
+	}
+}
+
+func TestStateFindAuthorizedKeysForPath(t *testing.T) {
+	rootKey, err := tuf.LoadKeyFromBytes(rootPubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gpgKey, err := gpg.LoadGPGKeyFromBytes(gpgPubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	targets1Key := loadTestKey(t, "targets-1.pub")
+	targets2Key := loadTestKey(t, "targets-2.pub")
+
+	// createState returns a state with the rules protect-src, protect-src-tests,
+	// and protect-all, in that order. protect-src delegates to protect-src-lib.
+	// The named rules are made terminating.
+	createState := func(t *testing.T, terminatingRules ...string) *State {
+		t.Helper()
+
+		setTerminating := func(metadata *tuf.TargetsMetadata) {
+			for i := range metadata.Delegations.Roles {
+				for _, ruleName := range terminatingRules {
+					if metadata.Delegations.Roles[i].Name == ruleName {
+						metadata.Delegations.Roles[i].Terminating = true
+					}
+				}
+			}
+		}
+
+		state := createTestStateWithPolicy(t)
+
+		targetsMetadata, err := state.GetTargetsMetadata(TargetsRoleName)
+		if err != nil {
+			t.Fatal(err)
+		}
+		targetsMetadata.Delegations.Roles = targetsMetadata.Delegations.Roles[len(targetsMetadata.Delegations.Roles)-1:]
+		targetsMetadata, err = AddOrUpdateDelegation(targetsMetadata, "protect-src", []*tuf.Key{targets1Key}, []string{"file:src/*"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		targetsMetadata, err = AddOrUpdateDelegation(targetsMetadata, "protect-src-tests", []*tuf.Key{targets2Key}, []string{"file:src/test*"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		targetsMetadata, err = AddOrUpdateDelegation(targetsMetadata, "protect-all", []*tuf.Key{gpgKey}, []string{"file:*", "file:*/*"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		setTerminating(targetsMetadata)
+		state.TargetsEnvelope = signTestEnvelope(t, targetsMetadata, rootKeyBytes)
+
+		delegatedMetadata := InitializeTargetsMetadata()
+		delegatedMetadata, err = AddOrUpdateDelegation(delegatedMetadata, "protect-src-lib", []*tuf.Key{rootKey}, []string{"file:src/lib*"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		setTerminating(delegatedMetadata)
+		state.DelegationEnvelopes = map[string]*sslibdsse.Envelope{
+			"protect-src": signTestEnvelope(t, delegatedMetadata, loadTestKeyBytes(t, "targets-1")),
+		}
+
+		return state
+	}
+
+	tests := map[string]struct {
+		terminatingRules []string
+		path             string
+		expectedKeys     []AuthorizedKeys
+	}{
+		"keys accumulate across non-terminating rules": {
+			path: "file:src/lib.go",
+			expectedKeys: []AuthorizedKeys{
+				{RuleName: "protect-src", Keys: []*tuf.Key{targets1Key}},
+				{RuleName: "protect-src-lib", Keys: []*tuf.Key{rootKey}},
+				{RuleName: "protect-all", Keys: []*tuf.Key{gpgKey}},
+			},
+		},
+		"keys accumulate across rules without delegated metadata": {
+			path: "file:src/test.go",
+			expectedKeys: []AuthorizedKeys{
+				{RuleName: "protect-src", Keys: []*tuf.Key{targets1Key}},
+				{RuleName: "protect-src-tests", Keys: []*tuf.Key{targets2Key}},
+				{RuleName: "protect-all", Keys: []*tuf.Key{gpgKey}},
+			},
+		},
+		"terminating rule discards later rules but not its delegations": {
+			terminatingRules: []string{"protect-src"},
+			path:             "file:src/lib.go",
+			expectedKeys: []AuthorizedKeys{
+				{RuleName: "protect-src", Keys: []*tuf.Key{targets1Key}},
+				{RuleName: "protect-src-lib", Keys: []*tuf.Key{rootKey}},
+			},
+		},
+		"terminating rule that does not match has no effect": {
+			terminatingRules: []string{"protect-src"},
+			path:             "file:docs/README.md",
+			expectedKeys: []AuthorizedKeys{
+				{RuleName: "protect-all", Keys: []*tuf.Key{gpgKey}},
+			},
+		},
+		"terminating rule without delegated metadata discards later rules": {
+			terminatingRules: []string{"protect-src-tests"},
+			path:             "file:src/test.go",
+			expectedKeys: []AuthorizedKeys{
+				{RuleName: "protect-src", Keys: []*tuf.Key{targets1Key}},
+				{RuleName: "protect-src-tests", Keys: []*tuf.Key{targets2Key}},
+			},
+		},
+		"delegated terminating rule retains inherited keys and discards ancestors' later rules": {
+			terminatingRules: []string{"protect-src-lib"},
+			path:             "file:src/lib.go",
+			expectedKeys: []AuthorizedKeys{
+				{RuleName: "protect-src", Keys: []*tuf.Key{targets1Key}},
+				{RuleName: "protect-src-lib", Keys: []*tuf.Key{rootKey}},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			state := createState(t, test.terminatingRules...)
+
+			authorizedKeys, err := state.FindAuthorizedKeysForPath(testCtx, test.path, "")
+			assert.Nil(t, err)
+			assert.Equal(t, test.expectedKeys, authorizedKeys)
+
+			expectedTrustedKeys := []*tuf.Key{}
+			for _, ruleKeys := range test.expectedKeys {
+				expectedTrustedKeys = append(expectedTrustedKeys, ruleKeys.Keys...)
+			}
+			trustedKeys, err := state.FindPublicKeysForPath(testCtx, test.path)
+			assert.Nil(t, err)
+			assert.Equal(t, expectedTrustedKeys, trustedKeys)
+
+			// The traversal must not modify the policy, so repeating it
+			// yields the same result
+			authorizedKeys, err = state.FindAuthorizedKeysForPath(testCtx, test.path, "")
+			assert.Nil(t, err)
+			assert.Equal(t, test.expectedKeys, authorizedKeys)
+
+			delegations, _, err := state.FindDelegationsForPath(testCtx, test.path)
+			assert.Nil(t, err)
+			delegationNames := []string{}
+			for _, delegation := range delegations {
+				delegationNames = append(delegationNames, delegation.Name)
+			}
+			expectedDelegationNames := []string{}
+			for _, ruleKeys := range test.expectedKeys {
+				expectedDelegationNames = append(expectedDelegationNames, ruleKeys.RuleName)
+			}
+			assert.Equal(t, expectedDelegationNames, delegationNames)
+		})
+	}
+
+	t.Run("denied path", func(t *testing.T) {
+		state := createState(t)
+
+		targetsMetadata, err := state.GetTargetsMetadata(TargetsRoleName)
+		if err != nil {
+			t.Fatal(err)
+		}
+		targetsMetadata, err = AddOrUpdateDenyRule(targetsMetadata, "deny-secrets", []string{"file:secrets/*"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		state.TargetsEnvelope = signTestEnvelope(t, targetsMetadata, rootKeyBytes)
+
+		authorizedKeys, err := state.FindAuthorizedKeysForPath(testCtx, "file:secrets/token", "")
+		assert.ErrorIs(t, err, ErrPathDenied)
+		assert.Nil(t, authorizedKeys)
+	})
+}
+
+func TestStateFindPublicKeysForPathWithMissingDelegationKey(t *testing.T) {
+	state := createTestStateWithPolicy(t)
+
+	targetsMetadata, err := state.GetTargetsMetadata(TargetsRoleName)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The rules still refer to the removed key
+	targetsMetadata.Delegations.Keys = map[string]*tuf.Key{}
+
+	signer, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(rootKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	targetsEnv, err := dsse.CreateEnvelope(targetsMetadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	targetsEnv, err = dsse.SignEnvelope(testCtx, targetsEnv, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state.TargetsEnvelope = targetsEnv
+
+	err = state.Verify(testCtx)
+	assert.ErrorIs(t, err, tuf.ErrDelegationKeyMissing)
+
+	keys, err := state.FindPublicKeysForPath(testCtx, "git:refs/heads/main")
+	assert.ErrorIs(t, err, tuf.ErrDelegationKeyMissing)
+	assert.Nil(t, keys)
+}
+
+func TestStateFindPublicKeysForPathWithDenyRule(t *testing.T) {
+	state := createTestStateWithDenyRule(t)
+
+	gpgKey, err := gpg.LoadGPGKeyFromBytes(gpgPubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keys, err := state.FindPublicKeysForPath(testCtx, "file:README.md")
+	assert.Nil(t, err)
+	assert.Equal(t, []*tuf.Key{gpgKey}, keys)
+
+	keys, err = state.FindPublicKeysForPath(testCtx, "file:secrets/token")
+	assert.ErrorIs(t, err, ErrPathDenied)
+	assert.Nil(t, keys)
+
+	delegations, _, err := state.FindDelegationsForPath(testCtx, "file:secrets/token")
+	assert.ErrorIs(t, err, ErrPathDenied)
+	assert.Nil(t, delegations)
+
+	// Rules that don't match are unaffected
+	keys, err = state.FindPublicKeysForPath(testCtx, "git:refs/heads/main")
+	assert.Nil(t, err)
+	assert.Equal(t, []*tuf.Key{gpgKey}, keys)
+}
+
+func TestStateFindPublicKeysForPathWithExpiringRule(t *testing.T) {
+	expires := time.Date(2030, time.January, 1, 0, 0, 0, 0, time.UTC)
+	state := createTestStateWithExpiringRule(t, expires)
+
+	gpgKey, err := gpg.LoadGPGKeyFromBytes(gpgPubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gpgKey2, err := gpg.LoadGPGKeyFromBytes(gpgPubKey2Bytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("before rule expires", func(t *testing.T) {
+		state := state.ForEvaluation(WithEvaluationTime(expires.Add(-time.Hour)))
+
+		keys, err := state.FindPublicKeysForPath(testCtx, "git:refs/heads/main")
+		assert.Nil(t, err)
+		assert.ElementsMatch(t, []*tuf.Key{gpgKey, gpgKey2}, keys)
+	})
+
+	t.Run("after rule expires", func(t *testing.T) {
+		state := state.ForEvaluation(WithEvaluationClock(clockwork.NewFakeClockAt(expires.Add(time.Hour))))
+
+		keys, err := state.FindPublicKeysForPath(testCtx, "git:refs/heads/main")
+		assert.Nil(t, err)
+		assert.Equal(t, []*tuf.Key{gpgKey}, keys)
+
+		// The expired rule still matches but authorizes no keys
+		delegations, _, err := state.FindDelegationsForPath(testCtx, "git:refs/heads/main")
+		assert.Nil(t, err)
+		if assert.Equal(t, 2, len(delegations)) {
+			assert.Equal(t, "protect-main", delegations[0].Name)
+			assert.Equal(t, "temporary-access", delegations[1].Name)
+			assert.Empty(t, delegations[1].KeyIDs)
+		}
+
+		authorizedKeys, err := state.FindAuthorizedKeysForPath(testCtx, "git:refs/heads/main", "")
+		assert.Nil(t, err)
+		if assert.Equal(t, 2, len(authorizedKeys)) {
+			assert.True(t, authorizedKeys[1].Expired)
+			assert.Empty(t, authorizedKeys[1].Keys)
+		}
+	})
+
+	t.Run("namespace only protected by expired rule", func(t *testing.T) {
+		state := createTestStateWithPolicy(t)
+
+		targetsMetadata, err := state.GetTargetsMetadata(TargetsRoleName)
+		if err != nil {
+			t.Fatal(err)
+		}
+		targetsMetadata, err = AddOrUpdateDelegation(targetsMetadata, "contractor-access", []*tuf.Key{gpgKey2}, []string{"file:contractor/*"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		targetsMetadata, err = SetRuleExpiry(targetsMetadata, "contractor-access", expires)
+		if err != nil {
+			t.Fatal(err)
+		}
+		state.TargetsEnvelope = signTestEnvelope(t, targetsMetadata, rootKeyBytes)
+
+		keys, err := state.ForEvaluation(WithEvaluationTime(expires.Add(-time.Hour))).FindPublicKeysForPath(testCtx, "file:contractor/notes.txt")
+		assert.Nil(t, err)
+		assert.Equal(t, []*tuf.Key{gpgKey2}, keys)
+
+		// The namespace is not left unprotected once the rule expires
+		expiredState := state.ForEvaluation(WithEvaluationTime(expires.Add(time.Hour)))
+
+		keys, err = expiredState.FindPublicKeysForPath(testCtx, "file:contractor/notes.txt")
+		assert.ErrorIs(t, err, ErrRuleExpired)
+		assert.Nil(t, keys)
+
+		_, _, err = expiredState.FindDelegationsForPath(testCtx, "file:contractor/notes.txt")
+		assert.ErrorIs(t, err, ErrRuleExpired)
+
+		expression, err := expiredState.FindAuthorizationExpressionForPath(testCtx, "file:contractor/notes.txt")
+		assert.Nil(t, err)
+		if assert.NotNil(t, expression) && assert.Equal(t, 1, len(expression.AnyOf)) {
+			assert.True(t, expression.AnyOf[0].Expired)
+			assert.Empty(t, expression.AnyOf[0].KeyIDs)
+		}
+	})
+}
+
+func TestStateFindPublicKeysForPathOnRef(t *testing.T) {
+	state := createTestStateWithPolicy(t)
+
+	gpgKey, err := gpg.LoadGPGKeyFromBytes(gpgPubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targetsMetadata, err := state.GetTargetsMetadata(TargetsRoleName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	targetsMetadata, err = AddOrUpdateDelegation(targetsMetadata, "protect-prod-deploy", []*tuf.Key{gpgKey}, []string{"file:deploy/*.yaml"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	targetsMetadata, err = SetRuleRefs(targetsMetadata, "protect-prod-deploy", []string{"refs/heads/prod"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signer, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(rootKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	targetsEnv, err := dsse.CreateEnvelope(targetsMetadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	targetsEnv, err = dsse.SignEnvelope(testCtx, targetsEnv, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state.TargetsEnvelope = targetsEnv
+
+	tests := map[string]struct {
+		path    string
+		refName string
+		keys    []*tuf.Key
+	}{
+		"path and ref match": {
+			path:    "file:deploy/app.yaml",
+			refName: "refs/heads/prod",
+			keys:    []*tuf.Key{gpgKey},
+		},
+		"ref matches, path does not": {
+			path:    "file:src/main.go",
+			refName: "refs/heads/prod",
+			keys:    []*tuf.Key{},
+		},
+		"path matches, ref does not": {
+			path:    "file:deploy/app.yaml",
+			refName: "refs/heads/main",
+			keys:    []*tuf.Key{},
+		},
+		"path matches, ref unknown": {
+			path: "file:deploy/app.yaml",
+			keys: []*tuf.Key{gpgKey},
+		},
+		"unrestricted rule on any ref": {
+			path:    "file:1",
+			refName: "refs/heads/feature",
+			keys:    []*tuf.Key{gpgKey},
+		},
+	}
 
 	for name, test := range tests {
-		keys, err := state.FindPublicKeysForPath(context.Background(), test.path)
+		keys, err := state.FindPublicKeysForPathOnRef(testCtx, test.path, test.refName)
 		assert.Nil(t, err, fmt.Sprintf("unexpected error in test '%s'", name))
-		assert.Equal(t, test.keys, keys, fmt.Sprintf("policy keys for path '%s' don't match expected keys in test '%s'", test.path, name))
+		assert.Equal(t, test.keys, keys, fmt.Sprintf("unexpected keys in test '%s'", name))
+
+		delegations, _, err := state.FindDelegationsForPathOnRef(testCtx, test.path, test.refName)
+		assert.Nil(t, err, fmt.Sprintf("unexpected error in test '%s'", name))
+		assert.Equal(t, len(test.keys) > 0, len(delegations) > 0, fmt.Sprintf("unexpected rules in test '%s'", name))
+	}
+}
+
+func TestStateAuthorizedKeysByRef(t *testing.T) {
+	state := createTestStateWithDenyRule(t)
+
+	gpgKey, err := gpg.LoadGPGKeyFromBytes(gpgPubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	authorizedKeys, err := state.AuthorizedKeysByRef(testCtx)
+	assert.Nil(t, err)
+
+	expectedAuthorizedKeys := map[string][]AuthorizedKeys{
+		"git:refs/heads/main": {
+			{RuleName: "protect-main", Keys: []*tuf.Key{gpgKey}},
+		},
+		"git:refs/heads/frozen": {
+			{RuleName: "deny-frozen", Deny: true, Keys: []*tuf.Key{}},
+		},
+	}
+	assert.Equal(t, expectedAuthorizedKeys, authorizedKeys)
+
+	// The report can be serialized
+	reportBytes, err := json.Marshal(authorizedKeys)
+	assert.Nil(t, err)
+	assert.Contains(t, string(reportBytes), `"rule_name":"protect-main"`)
+
+	t.Run("policy with only root", func(t *testing.T) {
+		state := createTestStateWithOnlyRoot(t)
+
+		authorizedKeys, err := state.AuthorizedKeysByRef(testCtx)
+		assert.Nil(t, err)
+		assert.Empty(t, authorizedKeys)
+	})
+}
+
+func TestStateIsRefProtected(t *testing.T) {
+	t.Run("policy with only root", func(t *testing.T) {
+		state := createTestStateWithOnlyRoot(t)
+
+		protected, err := state.IsRefProtected(testCtx, "refs/heads/main")
+		assert.Nil(t, err)
+		assert.False(t, protected)
+	})
+
+	t.Run("file rule applies to all refs", func(t *testing.T) {
+		state := createTestStateWithPolicy(t)
+
+		protected, err := state.IsRefProtected(testCtx, "refs/heads/main")
+		assert.Nil(t, err)
+		assert.True(t, protected)
+
+		protected, err = state.IsRefProtected(testCtx, "refs/heads/feature")
+		assert.Nil(t, err)
+		assert.True(t, protected)
+	})
+
+	t.Run("file rule restricted to other refs", func(t *testing.T) {
+		state := createTestStateWithPolicy(t)
+
+		targetsMetadata, err := state.GetTargetsMetadata(TargetsRoleName)
+		if err != nil {
+			t.Fatal(err)
+		}
+		targetsMetadata, err = SetRuleRefs(targetsMetadata, "protect-files-1-and-2", []string{"refs/heads/release/*"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		state.TargetsEnvelope = signTestEnvelope(t, targetsMetadata, rootKeyBytes)
+
+		protected, err := state.IsRefProtected(testCtx, "refs/heads/main")
+		assert.Nil(t, err)
+		assert.True(t, protected)
+
+		protected, err = state.IsRefProtected(testCtx, "refs/heads/release/1.0")
+		assert.Nil(t, err)
+		assert.True(t, protected)
+
+		protected, err = state.IsRefProtected(testCtx, "refs/heads/feature")
+		assert.Nil(t, err)
+		assert.False(t, protected)
+	})
+}
+
+func TestStateWithMultipleTopLevelPolicies(t *testing.T) {
+	gpgKey, err := gpg.LoadGPGKeyFromBytes(gpgPubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gpgKey2, err := gpg.LoadGPGKeyFromBytes(gpgPubKey2Bytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	securityKey, err := tuf.LoadKeyFromBytes(targets1PubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
 	}
+
+	t.Run("disjoint paths", func(t *testing.T) {
+		state := createTestStateWithMultiplePolicies(t)
+
+		err := state.Verify(testCtx)
+		assert.Nil(t, err)
+
+		roleNames, err := state.TopLevelTargetsRoleNames()
+		assert.Nil(t, err)
+		assert.Equal(t, []string{TargetsRoleName, "security"}, roleNames)
+
+		keys, err := state.FindPublicKeysForPath(testCtx, "git:refs/heads/main")
+		assert.Nil(t, err)
+		assert.Equal(t, []*tuf.Key{gpgKey}, keys)
+
+		keys, err = state.FindPublicKeysForPath(testCtx, "file:ci/build.yml")
+		assert.Nil(t, err)
+		assert.Equal(t, []*tuf.Key{gpgKey2}, keys)
+
+		keys, err = state.FindPublicKeysForPath(testCtx, "file:README.md")
+		assert.Nil(t, err)
+		assert.Empty(t, keys)
+
+		delegations, _, err := state.FindDelegationsForPath(testCtx, "file:ci/build.yml")
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(delegations))
+		assert.Equal(t, "protect-ci", delegations[0].Name)
+
+		keyIDs, err := state.FindAuthorizedSigningKeyIDs(testCtx, "security")
+		assert.Nil(t, err)
+		assert.Equal(t, []string{securityKey.KeyID}, keyIDs)
+
+		// The rules of the second policy are not treated as delegations of
+		// the first
+		keyIDs, err = state.FindAuthorizedSigningKeyIDs(testCtx, "protect-ci")
+		assert.Nil(t, err)
+		assert.Equal(t, []string{gpgKey2.KeyID}, keyIDs)
+	})
+
+	t.Run("overlapping paths", func(t *testing.T) {
+		state := createTestStateWithMultiplePolicies(t)
+
+		// The security policy declares a rule for main, which the targets
+		// policy already protects, and a rule for a ref the targets policy
+		// doesn't protect
+		securityMetadata, err := state.GetTargetsMetadata("security")
+		if err != nil {
+			t.Fatal(err)
+		}
+		securityMetadata, err = AddOrUpdateDelegation(securityMetadata, "security-main", []*tuf.Key{gpgKey2}, []string{"git:refs/heads/main"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		securityMetadata, err = AddOrUpdateDelegation(securityMetadata, "security-release", []*tuf.Key{gpgKey2}, []string{"git:refs/heads/release"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		state.DelegationEnvelopes["security"] = signTestEnvelope(t, securityMetadata, targets1KeyBytes)
+
+		err = state.Verify(testCtx)
+		assert.Nil(t, err)
+
+		// The keys of the policies are never combined: main is owned by the
+		// targets policy, which takes precedence
+		keys, err := state.FindPublicKeysForPath(testCtx, "git:refs/heads/main")
+		assert.Nil(t, err)
+		assert.Equal(t, []*tuf.Key{gpgKey}, keys)
+
+		authorizedKeys, err := state.FindAuthorizedKeysForPath(testCtx, "git:refs/heads/main", "")
+		assert.Nil(t, err)
+		assert.Equal(t, []AuthorizedKeys{{RuleName: "protect-main", Keys: []*tuf.Key{gpgKey}}}, authorizedKeys)
+
+		delegations, _, err := state.FindDelegationsForPath(testCtx, "git:refs/heads/main")
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(delegations))
+		assert.Equal(t, "protect-main", delegations[0].Name)
+
+		expression, err := state.FindAuthorizationExpressionForPath(testCtx, "git:refs/heads/main")
+		assert.Nil(t, err)
+		if assert.Len(t, expression.AnyOf, 1) {
+			assert.Equal(t, "protect-main", expression.AnyOf[0].RuleName)
+		}
+
+		// A path only the security policy protects is owned by it
+		keys, err = state.FindPublicKeysForPath(testCtx, "git:refs/heads/release")
+		assert.Nil(t, err)
+		assert.Equal(t, []*tuf.Key{gpgKey2}, keys)
+
+		// A deny rule in the security policy doesn't apply to main either
+		securityMetadata, err = AddOrUpdateDenyRule(securityMetadata, "deny-main", []string{"git:refs/heads/main"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		state.DelegationEnvelopes["security"] = signTestEnvelope(t, securityMetadata, targets1KeyBytes)
+
+		keys, err = state.FindPublicKeysForPath(testCtx, "git:refs/heads/main")
+		assert.Nil(t, err)
+		assert.Equal(t, []*tuf.Key{gpgKey}, keys)
+	})
+
+	t.Run("role not listed as a top level policy", func(t *testing.T) {
+		state := createTestStateWithMultiplePolicies(t)
+
+		// A role the root of trust declares but doesn't list as a top level
+		// policy is not one
+		rootMetadata, err := state.GetRootMetadata()
+		if err != nil {
+			t.Fatal(err)
+		}
+		rootMetadata.TopLevelPolicies = nil
+		setTestRootMetadata(t, state, rootMetadata)
+
+		roleNames, err := state.TopLevelTargetsRoleNames()
+		assert.Nil(t, err)
+		assert.Equal(t, []string{TargetsRoleName}, roleNames)
+
+		err = state.Verify(testCtx)
+		assert.ErrorIs(t, err, ErrDanglingDelegationMetadata)
+	})
+
+	t.Run("invalid top level policies", func(t *testing.T) {
+		tests := map[string][]string{
+			"undeclared role": {"security", "undeclared"},
+			"listed twice":    {"security", "security"},
+			"targets role":    {TargetsRoleName, "security"},
+			"reserved role":   {"security", RootRoleName},
+		}
+
+		for name, topLevelPolicies := range tests {
+			t.Run(name, func(t *testing.T) {
+				state := createTestStateWithMultiplePolicies(t)
+
+				rootMetadata, err := state.GetRootMetadata()
+				if err != nil {
+					t.Fatal(err)
+				}
+				rootMetadata.TopLevelPolicies = topLevelPolicies
+				setTestRootMetadata(t, state, rootMetadata)
+
+				err = state.Verify(testCtx)
+				assert.ErrorIs(t, err, ErrInvalidTopLevelPolicy)
+			})
+		}
+	})
+
+	t.Run("policy signed by untrusted key", func(t *testing.T) {
+		state := createTestStateWithMultiplePolicies(t)
+
+		securityMetadata, err := state.GetTargetsMetadata("security")
+		if err != nil {
+			t.Fatal(err)
+		}
+		state.DelegationEnvelopes["security"] = signTestEnvelope(t, securityMetadata, rootKeyBytes)
+
+		err = state.Verify(testCtx)
+		assert.NotNil(t, err)
+
+		_, err = state.FindPublicKeysForPath(testCtx, "file:ci/build.yml")
+		assert.NotNil(t, err)
+	})
+
+	t.Run("policy not declared in root", func(t *testing.T) {
+		state := createTestStateWithPolicy(t)
+
+		securityMetadata := InitializeTargetsMetadata()
+		state.DelegationEnvelopes = map[string]*sslibdsse.Envelope{"security": signTestEnvelope(t, securityMetadata, targets1KeyBytes)}
+
+		err := state.Verify(testCtx)
+		assert.ErrorIs(t, err, ErrDanglingDelegationMetadata)
+	})
+
+	t.Run("rule with the same name as a policy", func(t *testing.T) {
+		state := createTestStateWithMultiplePolicies(t)
+
+		targetsMetadata, err := state.GetTargetsMetadata(TargetsRoleName)
+		if err != nil {
+			t.Fatal(err)
+		}
+		targetsMetadata, err = AddOrUpdateDelegation(targetsMetadata, "security", []*tuf.Key{gpgKey}, []string{"file:ci/*"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		state.TargetsEnvelope = signTestEnvelope(t, targetsMetadata, rootKeyBytes)
+
+		err = state.Verify(testCtx)
+		assert.ErrorIs(t, err, ErrRuleNameConflictsWithRole)
+	})
+
+	t.Run("rule name declared in two policies", func(t *testing.T) {
+		state := createTestStateWithMultiplePolicies(t)
+
+		// A rule is only available under the policy that declares it
+		assert.Nil(t, state.CheckRuleNameIsAvailable(TargetsRoleName, "protect-main"))
+		assert.Nil(t, state.CheckRuleNameIsAvailable("protect-ci", "protect-ci-docs"))
+		err := state.CheckRuleNameIsAvailable("security", "protect-main")
+		assert.ErrorIs(t, err, ErrRuleNameInMultiplePolicies)
+		err = state.CheckRuleNameIsAvailable(TargetsRoleName, "protect-ci")
+		assert.ErrorIs(t, err, ErrRuleNameInMultiplePolicies)
+
+		// The security team declares a rule with the same name as a rule in
+		// the targets policy, so the metadata delegated by either rule would
+		// be ambiguous
+		securityMetadata, err := state.GetTargetsMetadata("security")
+		if err != nil {
+			t.Fatal(err)
+		}
+		securityMetadata, err = AddOrUpdateDelegation(securityMetadata, "protect-main", []*tuf.Key{gpgKey2}, []string{"git:refs/heads/main"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		state.DelegationEnvelopes["security"] = signTestEnvelope(t, securityMetadata, targets1KeyBytes)
+
+		err = state.Verify(testCtx)
+		assert.ErrorIs(t, err, ErrRuleNameInMultiplePolicies)
+
+		_, err = state.FindPublicKeysForPath(testCtx, "git:refs/heads/main")
+		assert.ErrorIs(t, err, ErrRuleNameInMultiplePolicies)
+	})
 }
 
 func TestGetStateForCommit(t *testing.T) {
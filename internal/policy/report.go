@@ -0,0 +1,75 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"context"
+	"sync"
+
+	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing"
+)
+
+// BreakGlassUse records an RSL entry that the policy's rules rejected but that
+// was accepted because break-glass annotations signed by a threshold of the
+// root of trust's emergency keys refer to it.
+type BreakGlassUse struct {
+	// RefName is the ref the entry is for.
+	RefName string
+
+	// EntryID is the ID of the entry.
+	EntryID plumbing.Hash
+
+	// AnnotationIDs are the IDs of the break-glass annotations that met the
+	// emergency role's threshold.
+	AnnotationIDs []plumbing.Hash
+
+	// Err is the error the policy's rules returned for the entry.
+	Err error
+}
+
+// VerificationReport collects the results of a verification that are not
+// failures but that callers must be able to act on, such as entries accepted
+// only because of break-glass access. A report is attached to the context
+// passed into verification using NewReportContext. It is safe for concurrent
+// use.
+type VerificationReport struct {
+	mu             sync.Mutex
+	breakGlassUses []*BreakGlassUse
+}
+
+// BreakGlassUses returns the break-glass uses recorded in the report, in the
+// order they were found.
+func (r *VerificationReport) BreakGlassUses() []*BreakGlassUse {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	uses := make([]*BreakGlassUse, len(r.breakGlassUses))
+	copy(uses, r.breakGlassUses)
+	return uses
+}
+
+func (r *VerificationReport) addBreakGlassUse(use *BreakGlassUse) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.breakGlassUses = append(r.breakGlassUses, use)
+}
+
+type reportKey struct{}
+
+// NewReportContext returns a copy of ctx that carries report. Verification
+// invoked with the returned context records its results in report.
+func NewReportContext(ctx context.Context, report *VerificationReport) context.Context {
+	return context.WithValue(ctx, reportKey{}, report)
+}
+
+// reportFromContext returns the report carried by ctx, or nil if ctx does not
+// carry one.
+func reportFromContext(ctx context.Context) *VerificationReport {
+	if ctx == nil {
+		return nil
+	}
+
+	report, _ := ctx.Value(reportKey{}).(*VerificationReport)
+	return report
+}
@@ -0,0 +1,36 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerificationReport(t *testing.T) {
+	assert.Nil(t, reportFromContext(context.Background()))
+
+	report := &VerificationReport{}
+	ctx := NewReportContext(context.Background(), report)
+	assert.Equal(t, report, reportFromContext(ctx))
+	assert.Empty(t, report.BreakGlassUses())
+
+	use := &BreakGlassUse{
+		RefName:       "refs/heads/main",
+		EntryID:       plumbing.NewHash("abcdef12345678"),
+		AnnotationIDs: []plumbing.Hash{plumbing.NewHash("12345678abcdef")},
+		Err:           fmt.Errorf("%w: test", ErrUnauthorizedSignature),
+	}
+	reportFromContext(ctx).addBreakGlassUse(use)
+
+	uses := report.BreakGlassUses()
+	assert.Equal(t, []*BreakGlassUse{use}, uses)
+
+	// The returned slice is a copy
+	uses[0] = nil
+	assert.Equal(t, []*BreakGlassUse{use}, report.BreakGlassUses())
+}
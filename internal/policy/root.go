@@ -4,12 +4,19 @@ package policy
 
 import (
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/gittuf/gittuf/internal/tuf"
 )
 
-var ErrCannotMeetThreshold = errors.New("removing key will drop authorized keys below threshold")
+var (
+	ErrCannotMeetThreshold = errors.New("removing key will drop authorized keys below threshold")
+	ErrReservedRoleName    = errors.New("role name is reserved in the root of trust")
+	ErrInvalidThreshold    = errors.New("threshold must be between one and the number of keys trusted by the role")
+
+	ErrEmergencyThresholdTooLow = errors.New("emergency threshold must be greater than one and at least the root threshold")
+)
 
 // InitializeRootMetadata creates a new instance of tuf.RootMetadata with
 // default values.
@@ -30,48 +37,189 @@ func InitializeRootMetadata(key *tuf.Key) *tuf.RootMetadata {
 // AddTargetsKey adds targetsKey as a trusted public key in rootMetadata for the
 // top level Targets role.
 func AddTargetsKey(rootMetadata *tuf.RootMetadata, targetsKey *tuf.Key) *tuf.RootMetadata {
-	rootMetadata.Keys[targetsKey.KeyID] = targetsKey
-	if _, ok := rootMetadata.Roles[TargetsRoleName]; !ok {
-		rootMetadata.AddRole(TargetsRoleName, tuf.Role{
-			KeyIDs:    []string{targetsKey.KeyID},
+	return addRoleKey(rootMetadata, TargetsRoleName, targetsKey)
+}
+
+// DeleteTargetsKey removes keyID from the list of trusted top level Targets
+// public keys in rootMetadata. It does not remove the key entry itself as it
+// does not check if other roles can be verified using the same key.
+func DeleteTargetsKey(rootMetadata *tuf.RootMetadata, keyID string) (*tuf.RootMetadata, error) {
+	return deleteRoleKey(rootMetadata, TargetsRoleName, keyID)
+}
+
+// AddTopLevelTargetsRoleKey adds key as a trusted public key in rootMetadata
+// for the top level policy with the specified name. If the root of trust does
+// not declare the policy yet, it is declared with a threshold of one and
+// recorded as a top level policy after the existing ones. This allows a
+// repository to have several top level policies, each owned by a different
+// set of keys. The names of the root, RSL writer, and emergency roles cannot be
+// used, nor can the name of any other role the root of trust declares that is
+// not a top level policy.
+func AddTopLevelTargetsRoleKey(rootMetadata *tuf.RootMetadata, roleName string, key *tuf.Key) (*tuf.RootMetadata, error) {
+	if isReservedRoleName(roleName) {
+		return nil, fmt.Errorf("%w: '%s'", ErrReservedRoleName, roleName)
+	}
+
+	if !isTopLevelPolicy(rootMetadata, roleName) {
+		if _, declared := rootMetadata.Roles[roleName]; declared {
+			return nil, fmt.Errorf("%w: '%s'", ErrReservedRoleName, roleName)
+		}
+		rootMetadata.TopLevelPolicies = append(rootMetadata.TopLevelPolicies, roleName)
+	}
+
+	return addRoleKey(rootMetadata, roleName, key), nil
+}
+
+// DeleteTopLevelTargetsRoleKey removes keyID from the list of trusted public
+// keys for the top level policy with the specified name. Like DeleteTargetsKey,
+// it does not remove the key entry itself.
+func DeleteTopLevelTargetsRoleKey(rootMetadata *tuf.RootMetadata, roleName, keyID string) (*tuf.RootMetadata, error) {
+	if isReservedRoleName(roleName) {
+		return nil, fmt.Errorf("%w: '%s'", ErrReservedRoleName, roleName)
+	}
+
+	if !isTopLevelPolicy(rootMetadata, roleName) {
+		if _, declared := rootMetadata.Roles[roleName]; declared {
+			return nil, fmt.Errorf("%w: '%s'", ErrReservedRoleName, roleName)
+		}
+		return rootMetadata, nil
+	}
+
+	return deleteRoleKey(rootMetadata, roleName, keyID)
+}
+
+// AddRSLWriterKey adds rslWriterKey as a trusted public key in rootMetadata for
+// the RSL writer role. Once the role is declared, every RSL entry must be
+// signed by one of its keys.
+func AddRSLWriterKey(rootMetadata *tuf.RootMetadata, rslWriterKey *tuf.Key) *tuf.RootMetadata {
+	return addRoleKey(rootMetadata, RSLWriterRoleName, rslWriterKey)
+}
+
+// DeleteRSLWriterKey removes keyID from the list of trusted RSL writer public
+// keys in rootMetadata. Like DeleteTargetsKey, it does not remove the key entry
+// itself.
+func DeleteRSLWriterKey(rootMetadata *tuf.RootMetadata, keyID string) (*tuf.RootMetadata, error) {
+	return deleteRoleKey(rootMetadata, RSLWriterRoleName, keyID)
+}
+
+// AddEmergencyKey adds emergencyKey as a trusted public key in rootMetadata for
+// the emergency role, which authorizes break-glass access. The key must not be
+// trusted by any other role in the root metadata. If the emergency role is not
+// yet declared, it is declared with the minimum emergency threshold, see
+// SetEmergencyThreshold, so break-glass access is only available once enough
+// emergency keys are added.
+func AddEmergencyKey(rootMetadata *tuf.RootMetadata, emergencyKey *tuf.Key) (*tuf.RootMetadata, error) {
+	for roleName, role := range rootMetadata.Roles {
+		if roleName == EmergencyRoleName {
+			continue
+		}
+
+		for _, keyID := range role.KeyIDs {
+			if keyID == emergencyKey.KeyID {
+				return nil, fmt.Errorf("%w: key '%s' is trusted by role '%s'", ErrEmergencyKeyNotDistinct, keyID, roleName)
+			}
+		}
+	}
+
+	_, declared := rootMetadata.Roles[EmergencyRoleName]
+	rootMetadata = addRoleKey(rootMetadata, EmergencyRoleName, emergencyKey)
+	if !declared {
+		emergencyRole := rootMetadata.Roles[EmergencyRoleName]
+		emergencyRole.Threshold = minEmergencyThreshold(rootMetadata)
+		rootMetadata.Roles[EmergencyRoleName] = emergencyRole
+	}
+
+	return rootMetadata, nil
+}
+
+// DeleteEmergencyKey removes keyID from the list of trusted emergency public
+// keys in rootMetadata. Like DeleteTargetsKey, it does not remove the key entry
+// itself.
+func DeleteEmergencyKey(rootMetadata *tuf.RootMetadata, keyID string) (*tuf.RootMetadata, error) {
+	return deleteRoleKey(rootMetadata, EmergencyRoleName, keyID)
+}
+
+// SetEmergencyThreshold sets the number of emergency keys that must authorize
+// the use of break-glass access. The threshold cannot exceed the number of
+// keys trusted by the emergency role. As break-glass access overrides every
+// rule in the policy, the threshold must also be greater than one and at least
+// the threshold of the root role, otherwise ErrEmergencyThresholdTooLow is
+// returned.
+func SetEmergencyThreshold(rootMetadata *tuf.RootMetadata, threshold int) (*tuf.RootMetadata, error) {
+	emergencyRole, has := rootMetadata.Roles[EmergencyRoleName]
+	if !has {
+		return nil, fmt.Errorf("%w: %s", ErrDelegationNotFound, EmergencyRoleName)
+	}
+
+	if threshold < 1 || threshold > len(emergencyRole.KeyIDs) {
+		return nil, ErrInvalidThreshold
+	}
+	if threshold < minEmergencyThreshold(rootMetadata) {
+		return nil, fmt.Errorf("%w: must be at least %d", ErrEmergencyThresholdTooLow, minEmergencyThreshold(rootMetadata))
+	}
+
+	emergencyRole.Threshold = threshold
+	rootMetadata.Roles[EmergencyRoleName] = emergencyRole
+
+	return rootMetadata, nil
+}
+
+// minEmergencyThreshold returns the lowest threshold the emergency role may
+// declare, which is two or the root role's threshold, whichever is higher.
+func minEmergencyThreshold(rootMetadata *tuf.RootMetadata) int {
+	threshold := 2
+	if rootRole, has := rootMetadata.Roles[RootRoleName]; has && rootRole.Threshold > threshold {
+		threshold = rootRole.Threshold
+	}
+
+	return threshold
+}
+
+// addRoleKey adds key as a trusted public key for the specified role in
+// rootMetadata, declaring the role with a threshold of one if necessary.
+func addRoleKey(rootMetadata *tuf.RootMetadata, roleName string, key *tuf.Key) *tuf.RootMetadata {
+	rootMetadata.AddKey(key)
+	if _, ok := rootMetadata.Roles[roleName]; !ok {
+		rootMetadata.AddRole(roleName, tuf.Role{
+			KeyIDs:    []string{key.KeyID},
 			Threshold: 1,
 		})
 		return rootMetadata
 	}
 
-	targetsRole := rootMetadata.Roles[TargetsRoleName]
-	for _, keyID := range targetsRole.KeyIDs {
-		if keyID == targetsKey.KeyID {
+	role := rootMetadata.Roles[roleName]
+	for _, keyID := range role.KeyIDs {
+		if keyID == key.KeyID {
 			return rootMetadata
 		}
 	}
 
-	targetsRole.KeyIDs = append(targetsRole.KeyIDs, targetsKey.KeyID)
-	rootMetadata.Roles[TargetsRoleName] = targetsRole
+	role.KeyIDs = append(role.KeyIDs, key.KeyID)
+	rootMetadata.Roles[roleName] = role
 
 	return rootMetadata
 }
 
-// DeleteTargetsKey removes keyID from the list of trusted top level Targets
-// public keys in rootMetadata. It does not remove the key entry itself as it
-// does not check if other roles can be verified using the same key.
-func DeleteTargetsKey(rootMetadata *tuf.RootMetadata, keyID string) (*tuf.RootMetadata, error) {
-	if _, ok := rootMetadata.Roles[TargetsRoleName]; !ok {
+// deleteRoleKey removes keyID from the list of trusted public keys for the
+// specified role in rootMetadata. If the role is not declared, rootMetadata is
+// returned unchanged.
+func deleteRoleKey(rootMetadata *tuf.RootMetadata, roleName, keyID string) (*tuf.RootMetadata, error) {
+	if _, ok := rootMetadata.Roles[roleName]; !ok {
 		return rootMetadata, nil
 	}
 
-	targetsRole := rootMetadata.Roles[TargetsRoleName]
+	role := rootMetadata.Roles[roleName]
 
-	if len(targetsRole.KeyIDs) <= targetsRole.Threshold {
+	if len(role.KeyIDs) <= role.Threshold {
 		return nil, ErrCannotMeetThreshold
 	}
-	for i, k := range targetsRole.KeyIDs {
+	for i, k := range role.KeyIDs {
 		if k == keyID {
-			targetsRole.KeyIDs = append(targetsRole.KeyIDs[:i], targetsRole.KeyIDs[i+1:]...)
+			role.KeyIDs = append(role.KeyIDs[:i], role.KeyIDs[i+1:]...)
 			break
 		}
 	}
-	rootMetadata.Roles[TargetsRoleName] = targetsRole
+	rootMetadata.Roles[roleName] = role
 
 	return rootMetadata, nil
 }
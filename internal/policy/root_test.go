@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/gittuf/gittuf/internal/signerverifier/gpg"
 	"github.com/gittuf/gittuf/internal/tuf"
 	"github.com/stretchr/testify/assert"
 )
@@ -104,3 +105,319 @@ func TestDeleteTargetsKey(t *testing.T) {
 	assert.ErrorIs(t, err, ErrCannotMeetThreshold)
 	assert.Nil(t, rootMetadata)
 }
+
+func TestAddRSLWriterKey(t *testing.T) {
+	keyBytes, err := os.ReadFile(filepath.Join("test-data", "root.pub"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key, err := tuf.LoadKeyFromBytes(keyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rootMetadata := InitializeRootMetadata(key)
+
+	rslWriterKeyBytes, err := os.ReadFile(filepath.Join("test-data", "targets-1.pub"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rslWriterKey, err := tuf.LoadKeyFromBytes(rslWriterKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rootMetadata = AddRSLWriterKey(rootMetadata, rslWriterKey)
+	assert.Equal(t, rslWriterKey, rootMetadata.Keys[rslWriterKey.KeyID])
+	assert.Equal(t, []string{rslWriterKey.KeyID}, rootMetadata.Roles[RSLWriterRoleName].KeyIDs)
+	assert.Equal(t, 1, rootMetadata.Roles[RSLWriterRoleName].Threshold)
+
+	// Adding the same key again is a no-op
+	rootMetadata = AddRSLWriterKey(rootMetadata, rslWriterKey)
+	assert.Equal(t, []string{rslWriterKey.KeyID}, rootMetadata.Roles[RSLWriterRoleName].KeyIDs)
+}
+
+func TestDeleteRSLWriterKey(t *testing.T) {
+	keyBytes, err := os.ReadFile(filepath.Join("test-data", "root.pub"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key, err := tuf.LoadKeyFromBytes(keyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rootMetadata := InitializeRootMetadata(key)
+
+	rslWriterKeyBytes, err := os.ReadFile(filepath.Join("test-data", "targets-1.pub"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rslWriterKey1, err := tuf.LoadKeyFromBytes(rslWriterKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rslWriterKeyBytes, err = os.ReadFile(filepath.Join("test-data", "targets-2.pub"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rslWriterKey2, err := tuf.LoadKeyFromBytes(rslWriterKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rootMetadata = AddRSLWriterKey(rootMetadata, rslWriterKey1)
+	rootMetadata = AddRSLWriterKey(rootMetadata, rslWriterKey2)
+
+	rootMetadata, err = DeleteRSLWriterKey(rootMetadata, rslWriterKey1.KeyID)
+	assert.Nil(t, err)
+	assert.Equal(t, rslWriterKey1, rootMetadata.Keys[rslWriterKey1.KeyID])
+	assert.Equal(t, []string{rslWriterKey2.KeyID}, rootMetadata.Roles[RSLWriterRoleName].KeyIDs)
+
+	rootMetadata, err = DeleteRSLWriterKey(rootMetadata, rslWriterKey2.KeyID)
+	assert.ErrorIs(t, err, ErrCannotMeetThreshold)
+	assert.Nil(t, rootMetadata)
+}
+
+func TestAddEmergencyKey(t *testing.T) {
+	key, err := tuf.LoadKeyFromBytes(rootPubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rootMetadata := InitializeRootMetadata(key)
+
+	// A key trusted by another role cannot be an emergency key
+	_, err = AddEmergencyKey(rootMetadata, key)
+	assert.ErrorIs(t, err, ErrEmergencyKeyNotDistinct)
+
+	emergencyKey, err := tuf.LoadKeyFromBytes(targets1PubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rootMetadata, err = AddEmergencyKey(rootMetadata, emergencyKey)
+	assert.Nil(t, err)
+	assert.Equal(t, emergencyKey, rootMetadata.Keys[emergencyKey.KeyID])
+	assert.Equal(t, []string{emergencyKey.KeyID}, rootMetadata.Roles[EmergencyRoleName].KeyIDs)
+	assert.Equal(t, 2, rootMetadata.Roles[EmergencyRoleName].Threshold)
+}
+
+func TestDeleteEmergencyKey(t *testing.T) {
+	key, err := tuf.LoadKeyFromBytes(rootPubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rootMetadata := InitializeRootMetadata(key)
+
+	emergencyKey1, err := tuf.LoadKeyFromBytes(targets1PubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	emergencyKeyBytes, err := os.ReadFile(filepath.Join("test-data", "targets-2.pub"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	emergencyKey2, err := tuf.LoadKeyFromBytes(emergencyKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rootMetadata, err = AddEmergencyKey(rootMetadata, emergencyKey1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootMetadata, err = AddEmergencyKey(rootMetadata, emergencyKey2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	emergencyKey3, err := gpg.LoadGPGKeyFromBytes(gpgPubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootMetadata, err = AddEmergencyKey(rootMetadata, emergencyKey3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rootMetadata, err = DeleteEmergencyKey(rootMetadata, emergencyKey1.KeyID)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{emergencyKey2.KeyID, emergencyKey3.KeyID}, rootMetadata.Roles[EmergencyRoleName].KeyIDs)
+
+	rootMetadata, err = DeleteEmergencyKey(rootMetadata, emergencyKey2.KeyID)
+	assert.ErrorIs(t, err, ErrCannotMeetThreshold)
+	assert.Nil(t, rootMetadata)
+}
+
+func TestSetEmergencyThreshold(t *testing.T) {
+	key, err := tuf.LoadKeyFromBytes(rootPubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rootMetadata := InitializeRootMetadata(key)
+
+	// The emergency role must be declared first
+	_, err = SetEmergencyThreshold(rootMetadata, 1)
+	assert.ErrorIs(t, err, ErrDelegationNotFound)
+
+	emergencyKey, err := tuf.LoadKeyFromBytes(targets1PubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rootMetadata, err = AddEmergencyKey(rootMetadata, emergencyKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = SetEmergencyThreshold(rootMetadata, 0)
+	assert.ErrorIs(t, err, ErrInvalidThreshold)
+
+	_, err = SetEmergencyThreshold(rootMetadata, 2)
+	assert.ErrorIs(t, err, ErrInvalidThreshold)
+
+	// A single emergency key cannot authorize break-glass access
+	_, err = SetEmergencyThreshold(rootMetadata, 1)
+	assert.ErrorIs(t, err, ErrEmergencyThresholdTooLow)
+
+	emergencyKey2, err := gpg.LoadGPGKeyFromBytes(gpgPubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootMetadata, err = AddEmergencyKey(rootMetadata, emergencyKey2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rootMetadata, err = SetEmergencyThreshold(rootMetadata, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, rootMetadata.Roles[EmergencyRoleName].Threshold)
+
+	// The emergency threshold cannot be lower than the root threshold
+	rootRole := rootMetadata.Roles[RootRoleName]
+	rootRole.Threshold = 3
+	rootMetadata.Roles[RootRoleName] = rootRole
+
+	_, err = SetEmergencyThreshold(rootMetadata, 2)
+	assert.ErrorIs(t, err, ErrEmergencyThresholdTooLow)
+}
+
+func TestAddTopLevelTargetsRoleKey(t *testing.T) {
+	keyBytes, err := os.ReadFile(filepath.Join("test-data", "root.pub"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key, err := tuf.LoadKeyFromBytes(keyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rootMetadata := InitializeRootMetadata(key)
+
+	targetsKeyBytes, err := os.ReadFile(filepath.Join("test-data", "targets-1.pub"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targetsKey, err := tuf.LoadKeyFromBytes(targetsKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rootMetadata, err = AddTopLevelTargetsRoleKey(rootMetadata, "security", targetsKey)
+	assert.Nil(t, err)
+	assert.Equal(t, targetsKey, rootMetadata.Keys[targetsKey.KeyID])
+	assert.Equal(t, []string{targetsKey.KeyID}, rootMetadata.Roles["security"].KeyIDs)
+	assert.Equal(t, 1, rootMetadata.Roles["security"].Threshold)
+
+	assert.Equal(t, []string{"security"}, rootMetadata.TopLevelPolicies)
+
+	// The main policy is unaffected
+	_, has := rootMetadata.Roles[TargetsRoleName]
+	assert.False(t, has)
+
+	// Policies are recorded once, in the order they are added
+	rootMetadata, err = AddTopLevelTargetsRoleKey(rootMetadata, "release", targetsKey)
+	assert.Nil(t, err)
+	rootMetadata, err = AddTopLevelTargetsRoleKey(rootMetadata, "security", key)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"security", "release"}, rootMetadata.TopLevelPolicies)
+
+	// The targets role is always a top level policy, so it isn't listed
+	rootMetadata, err = AddTopLevelTargetsRoleKey(rootMetadata, TargetsRoleName, targetsKey)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"security", "release"}, rootMetadata.TopLevelPolicies)
+
+	_, err = AddTopLevelTargetsRoleKey(rootMetadata, RootRoleName, targetsKey)
+	assert.ErrorIs(t, err, ErrReservedRoleName)
+
+	_, err = AddTopLevelTargetsRoleKey(rootMetadata, RSLWriterRoleName, targetsKey)
+	assert.ErrorIs(t, err, ErrReservedRoleName)
+
+	_, err = AddTopLevelTargetsRoleKey(rootMetadata, EmergencyRoleName, targetsKey)
+	assert.ErrorIs(t, err, ErrReservedRoleName)
+
+	// A role declared for another purpose can't become a top level policy
+	rootMetadata.AddRole("future-role", tuf.Role{KeyIDs: []string{key.KeyID}, Threshold: 1})
+	_, err = AddTopLevelTargetsRoleKey(rootMetadata, "future-role", targetsKey)
+	assert.ErrorIs(t, err, ErrReservedRoleName)
+}
+
+func TestDeleteTopLevelTargetsRoleKey(t *testing.T) {
+	keyBytes, err := os.ReadFile(filepath.Join("test-data", "root.pub"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key, err := tuf.LoadKeyFromBytes(keyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rootMetadata := InitializeRootMetadata(key)
+
+	targetsKeyBytes, err := os.ReadFile(filepath.Join("test-data", "targets-1.pub"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targetsKey, err := tuf.LoadKeyFromBytes(targetsKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rootMetadata, err = AddTopLevelTargetsRoleKey(rootMetadata, "security", targetsKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootMetadata, err = AddTopLevelTargetsRoleKey(rootMetadata, "security", key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rootMetadata, err = DeleteTopLevelTargetsRoleKey(rootMetadata, "security", targetsKey.KeyID)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{key.KeyID}, rootMetadata.Roles["security"].KeyIDs)
+
+	_, err = DeleteTopLevelTargetsRoleKey(rootMetadata, "security", key.KeyID)
+	assert.ErrorIs(t, err, ErrCannotMeetThreshold)
+
+	_, err = DeleteTopLevelTargetsRoleKey(rootMetadata, RootRoleName, key.KeyID)
+	assert.ErrorIs(t, err, ErrReservedRoleName)
+
+	rootMetadata.AddRole("future-role", tuf.Role{KeyIDs: []string{key.KeyID, targetsKey.KeyID}, Threshold: 1})
+	_, err = DeleteTopLevelTargetsRoleKey(rootMetadata, "future-role", key.KeyID)
+	assert.ErrorIs(t, err, ErrReservedRoleName)
+}
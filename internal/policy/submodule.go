@@ -0,0 +1,94 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/gittuf/gittuf/internal/third_party/go-git"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing"
+)
+
+var ErrSubmoduleNotVerified = errors.New("submodule not verified")
+
+// SubmoduleVerifier is used to verify the commit that the submodule at the
+// specified path is updated to. It must return an error wrapping
+// ErrSubmoduleNotVerified if the commit cannot be verified.
+type SubmoduleVerifier func(ctx context.Context, path string, commitID plumbing.Hash) error
+
+// VerifySubmodulesForEntry identifies the submodule updates made by the commits
+// introduced to the entry's ref and verifies each updated submodule commit
+// using the verifier. Removed submodules are not verified. If no verifier is
+// specified, every submodule update is reported as not verified. The returned
+// error aggregates the failures for all submodule updates.
+func VerifySubmodulesForEntry(ctx context.Context, repo *git.Repository, entry *rsl.ReferenceEntry, verifier SubmoduleVerifier) error {
+	commits, err := getCommits(repo, entry)
+	if err != nil {
+		return err
+	}
+
+	errs := []error{}
+	for _, commit := range commits {
+		changes, err := gitinterface.GetSubmoduleChangesByCommit(repo, commit)
+		if err != nil {
+			return err
+		}
+
+		for _, change := range changes {
+			if change.NewCommitID.IsZero() {
+				continue
+			}
+
+			var err error
+			if verifier == nil {
+				err = ErrSubmoduleNotVerified
+			} else {
+				err = verifier(ctx, change.Path, change.NewCommitID)
+			}
+			if err != nil {
+				errs = append(errs, fmt.Errorf("submodule '%s' at '%s' updated in commit '%s': %w", change.Path, change.NewCommitID.String(), commit.Hash.String(), err))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// NewSubmoduleRSLVerifier returns a SubmoduleVerifier that checks that a
+// submodule's commit is recorded in the RSL of the submodule's repository, and
+// that the RSL entry that first recorded the commit is valid as per the
+// submodule's own gittuf policy. The submodule repositories are keyed by the
+// submodule paths.
+func NewSubmoduleRSLVerifier(submodules map[string]*git.Repository) SubmoduleVerifier {
+	return func(ctx context.Context, path string, commitID plumbing.Hash) error {
+		submoduleRepo, ok := submodules[path]
+		if !ok {
+			return fmt.Errorf("%w: no repository configured for submodule", ErrSubmoduleNotVerified)
+		}
+
+		commit, err := submoduleRepo.CommitObject(commitID)
+		if err != nil {
+			return errors.Join(ErrSubmoduleNotVerified, err)
+		}
+
+		entry, annotations, err := rsl.GetFirstReferenceEntryForCommit(submoduleRepo, commit)
+		if err != nil {
+			return errors.Join(ErrSubmoduleNotVerified, err)
+		}
+
+		state, err := GetStateForCommit(ctx, submoduleRepo, commit)
+		if err != nil {
+			return errors.Join(ErrSubmoduleNotVerified, err)
+		}
+
+		if err := verifyEntry(ctx, submoduleRepo, state, entry, annotations); err != nil {
+			return errors.Join(ErrSubmoduleNotVerified, err)
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,89 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/common"
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/gittuf/gittuf/internal/third_party/go-git"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing/filemode"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing/object"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifySubmodulesForEntry(t *testing.T) {
+	refName := "refs/heads/main"
+	submodulePath := "sub"
+
+	// The submodule has its own gittuf policy and RSL
+	submoduleRepo, _ := createTestRepository(t, createTestStateWithPolicy)
+	submoduleCommitIDs := common.AddNTestCommitsToSpecifiedRef(t, submoduleRepo, refName, 2, gpgKeyName)
+	// Only the first commit is recorded in the submodule's RSL
+	common.CreateTestRSLReferenceEntryCommit(t, submoduleRepo, rsl.NewReferenceEntry(refName, submoduleCommitIDs[0]), gpgKeyName)
+
+	createEntryWithSubmodule := func(t *testing.T, submoduleCommitID plumbing.Hash) (*git.Repository, *rsl.ReferenceEntry) {
+		t.Helper()
+
+		repo, _ := createTestRepository(t, createTestStateWithPolicy)
+
+		treeHash, err := gitinterface.WriteTree(repo, []object.TreeEntry{
+			{Name: submodulePath, Mode: filemode.Submodule, Hash: submoduleCommitID},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		commitID, err := gitinterface.Commit(repo, treeHash, refName, "Add submodule", false)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		entry := rsl.NewReferenceEntry(refName, commitID)
+		entry.ID = common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyName)
+
+		return repo, entry
+	}
+
+	verifier := NewSubmoduleRSLVerifier(map[string]*git.Repository{submodulePath: submoduleRepo})
+
+	t.Run("submodule commit recorded in submodule RSL", func(t *testing.T) {
+		repo, entry := createEntryWithSubmodule(t, submoduleCommitIDs[0])
+
+		err := VerifySubmodulesForEntry(testCtx, repo, entry, verifier)
+		assert.Nil(t, err)
+	})
+
+	t.Run("submodule commit not recorded in submodule RSL", func(t *testing.T) {
+		repo, entry := createEntryWithSubmodule(t, submoduleCommitIDs[1])
+
+		err := VerifySubmodulesForEntry(testCtx, repo, entry, verifier)
+		assert.ErrorIs(t, err, ErrSubmoduleNotVerified)
+	})
+
+	t.Run("no repository configured for submodule", func(t *testing.T) {
+		repo, entry := createEntryWithSubmodule(t, submoduleCommitIDs[0])
+
+		err := VerifySubmodulesForEntry(testCtx, repo, entry, NewSubmoduleRSLVerifier(nil))
+		assert.ErrorIs(t, err, ErrSubmoduleNotVerified)
+	})
+
+	t.Run("no verifier", func(t *testing.T) {
+		repo, entry := createEntryWithSubmodule(t, submoduleCommitIDs[0])
+
+		err := VerifySubmodulesForEntry(testCtx, repo, entry, nil)
+		assert.ErrorIs(t, err, ErrSubmoduleNotVerified)
+	})
+
+	t.Run("no submodule changes", func(t *testing.T) {
+		repo, _ := createTestRepository(t, createTestStateWithPolicy)
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyName)
+		entry := rsl.NewReferenceEntry(refName, commitIDs[0])
+		entry.ID = common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyName)
+
+		err := VerifySubmodulesForEntry(testCtx, repo, entry, nil)
+		assert.Nil(t, err)
+	})
+}
@@ -4,14 +4,23 @@ package policy
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 	"time"
 
+	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing"
 	"github.com/gittuf/gittuf/internal/tuf"
 )
 
 const AllowRuleName = "gittuf-allow-rule"
 
-var ErrCannotManipulateAllowRule = errors.New("cannot change in-built gittuf-allow-rule")
+var (
+	ErrCannotManipulateAllowRule = errors.New("cannot change in-built gittuf-allow-rule")
+	ErrAllowRuleNotLast          = errors.New("in-built gittuf-allow-rule must be the last rule in policy")
+	ErrInvalidAllowedHash        = errors.New("allowed hash is not a valid Git object ID")
+	ErrInvalidRuleRef            = errors.New("rule ref pattern must be a fully qualified Git ref")
+	ErrRuleRefsRequireFileRule   = errors.New("only rules protecting file paths can be restricted to refs")
+)
 
 // InitializeTargetsMetadata creates a new instance of TargetsMetadata.
 func InitializeTargetsMetadata() *tuf.TargetsMetadata {
@@ -22,12 +31,34 @@ func InitializeTargetsMetadata() *tuf.TargetsMetadata {
 	return targetsMetadata
 }
 
+// ValidateTargetsMetadata checks that the targets metadata is well formed so
+// that it can be rejected before it is signed rather than when the policy is
+// verified. In addition to the checks performed by TargetsMetadata.Validate,
+// the in-built allow rule must be the last rule in the metadata. All the
+// validation failures that are found are returned together.
+func ValidateTargetsMetadata(targetsMetadata *tuf.TargetsMetadata) error {
+	errs := []error{}
+
+	if err := targetsMetadata.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+
+	if targetsMetadata.Delegations == nil || len(targetsMetadata.Delegations.Roles) == 0 || targetsMetadata.Delegations.Roles[len(targetsMetadata.Delegations.Roles)-1].Name != AllowRuleName {
+		errs = append(errs, ErrAllowRuleNotLast)
+	}
+
+	return errors.Join(errs...)
+}
+
 // AddOrUpdateDelegation is used to add or amend a delegation in
 // TargetsMetadata.
 func AddOrUpdateDelegation(targetsMetadata *tuf.TargetsMetadata, ruleName string, authorizedKeys []*tuf.Key, rulePatterns []string) (*tuf.TargetsMetadata, error) {
 	if ruleName == AllowRuleName {
 		return nil, ErrCannotManipulateAllowRule
 	}
+	if err := checkRuleNameIsUnique(targetsMetadata, ruleName); err != nil {
+		return nil, err
+	}
 
 	authorizedKeyIDs := []string{}
 	for _, key := range authorizedKeys {
@@ -48,6 +79,7 @@ func AddOrUpdateDelegation(targetsMetadata *tuf.TargetsMetadata, ruleName string
 			// update existing delegation
 			existingDelegation = true
 			delegation.Paths = rulePatterns
+			delegation.Deny = false
 			delegation.Role = tuf.Role{KeyIDs: authorizedKeyIDs, Threshold: 1}
 		}
 
@@ -73,6 +105,283 @@ func AddOrUpdateDelegation(targetsMetadata *tuf.TargetsMetadata, ruleName string
 	return targetsMetadata, nil
 }
 
+// AddOrUpdateDenyRule is used to add or amend a deny rule in TargetsMetadata.
+// A deny rule is a terminating delegation that trusts no keys, so that changes
+// to the matching namespaces are always rejected. As with other delegations,
+// the deny rule only takes effect if no prior terminating delegation matches
+// the namespace.
+func AddOrUpdateDenyRule(targetsMetadata *tuf.TargetsMetadata, ruleName string, rulePatterns []string) (*tuf.TargetsMetadata, error) {
+	if ruleName == AllowRuleName {
+		return nil, ErrCannotManipulateAllowRule
+	}
+	if err := checkRuleNameIsUnique(targetsMetadata, ruleName); err != nil {
+		return nil, err
+	}
+
+	allDelegations := []tuf.Delegation{}
+
+	existingDelegation := false
+	for _, delegation := range targetsMetadata.Delegations.Roles {
+		if delegation.Name == AllowRuleName {
+			break
+		}
+
+		if delegation.Name == ruleName {
+			// update existing delegation
+			existingDelegation = true
+			delegation = DenyRule(ruleName, rulePatterns)
+		}
+
+		allDelegations = append(allDelegations, delegation)
+	}
+
+	if !existingDelegation {
+		allDelegations = append(allDelegations, DenyRule(ruleName, rulePatterns))
+	}
+
+	allDelegations = append(allDelegations, AllowRule())
+
+	targetsMetadata.Delegations.Roles = allDelegations
+
+	return targetsMetadata, nil
+}
+
+// SetAllowedHashes pins the contents of the files protected by the specified
+// rule to the specified Git blob IDs. Changes to matching files that introduce
+// contents not in the allowed set are rejected during verification. Passing no
+// hashes removes the pin from the rule.
+func SetAllowedHashes(targetsMetadata *tuf.TargetsMetadata, ruleName string, allowedHashes []string) (*tuf.TargetsMetadata, error) {
+	if ruleName == AllowRuleName {
+		return nil, ErrCannotManipulateAllowRule
+	}
+
+	if err := checkRuleNameIsUnique(targetsMetadata, ruleName); err != nil {
+		return nil, err
+	}
+
+	normalizedHashes := make([]string, 0, len(allowedHashes))
+	for _, hash := range allowedHashes {
+		if !plumbing.IsHash(hash) {
+			return nil, fmt.Errorf("%w: '%s'", ErrInvalidAllowedHash, hash)
+		}
+		normalizedHashes = append(normalizedHashes, strings.ToLower(hash))
+	}
+
+	for i, delegation := range targetsMetadata.Delegations.Roles {
+		if delegation.Name == ruleName {
+			if len(allowedHashes) == 0 {
+				targetsMetadata.Delegations.Roles[i].AllowedHashes = nil
+			} else {
+				targetsMetadata.Delegations.Roles[i].AllowedHashes = normalizedHashes
+			}
+
+			return targetsMetadata, nil
+		}
+	}
+
+	return nil, ErrDelegationNotFound
+}
+
+// SetMinDistinctSigners requires the last window commits of the refs protected
+// by the specified rule to be signed by at least count distinct keys authorized
+// by the rule. Passing a count of zero removes the requirement from the rule.
+func SetMinDistinctSigners(targetsMetadata *tuf.TargetsMetadata, ruleName string, count, window int) (*tuf.TargetsMetadata, error) {
+	if ruleName == AllowRuleName {
+		return nil, ErrCannotManipulateAllowRule
+	}
+	if err := checkRuleNameIsUnique(targetsMetadata, ruleName); err != nil {
+		return nil, err
+	}
+
+	for i, delegation := range targetsMetadata.Delegations.Roles {
+		if delegation.Name == ruleName {
+			if count == 0 {
+				targetsMetadata.Delegations.Roles[i].MinDistinctSigners = nil
+			} else {
+				targetsMetadata.Delegations.Roles[i].MinDistinctSigners = &tuf.DistinctSignersRequirement{Count: count, Window: window}
+			}
+
+			return targetsMetadata, nil
+		}
+	}
+
+	return nil, ErrDelegationNotFound
+}
+
+// SetVerifyMergeCommits sets whether merge commits in the refs protected by the
+// specified rule must be verified to only combine the changes made by their
+// parents.
+func SetVerifyMergeCommits(targetsMetadata *tuf.TargetsMetadata, ruleName string, verifyMergeCommits bool) (*tuf.TargetsMetadata, error) {
+	if ruleName == AllowRuleName {
+		return nil, ErrCannotManipulateAllowRule
+	}
+	if err := checkRuleNameIsUnique(targetsMetadata, ruleName); err != nil {
+		return nil, err
+	}
+
+	for i, delegation := range targetsMetadata.Delegations.Roles {
+		if delegation.Name == ruleName {
+			targetsMetadata.Delegations.Roles[i].VerifyMergeCommits = verifyMergeCommits
+			return targetsMetadata, nil
+		}
+	}
+
+	return nil, ErrDelegationNotFound
+}
+
+// SetRequireSignedCommits sets whether every commit introduced in the refs
+// protected by the specified rule must be signed by one of the rule's
+// authorized keys, rather than just the RSL entry that records the change.
+func SetRequireSignedCommits(targetsMetadata *tuf.TargetsMetadata, ruleName string, requireSignedCommits bool) (*tuf.TargetsMetadata, error) {
+	if ruleName == AllowRuleName {
+		return nil, ErrCannotManipulateAllowRule
+	}
+	if err := checkRuleNameIsUnique(targetsMetadata, ruleName); err != nil {
+		return nil, err
+	}
+
+	for i, delegation := range targetsMetadata.Delegations.Roles {
+		if delegation.Name == ruleName {
+			targetsMetadata.Delegations.Roles[i].RequireSignedCommits = requireSignedCommits
+			return targetsMetadata, nil
+		}
+	}
+
+	return nil, ErrDelegationNotFound
+}
+
+// SetRuleRefs restricts the specified rule, which must protect file paths, to
+// changes made on the Git refs matching the patterns, such as refs/heads/prod.
+// Changes to the protected files on other refs are not subject to the rule.
+// Passing no patterns removes the restriction from the rule.
+func SetRuleRefs(targetsMetadata *tuf.TargetsMetadata, ruleName string, refPatterns []string) (*tuf.TargetsMetadata, error) {
+	if ruleName == AllowRuleName {
+		return nil, ErrCannotManipulateAllowRule
+	}
+	if err := checkRuleNameIsUnique(targetsMetadata, ruleName); err != nil {
+		return nil, err
+	}
+
+	for _, pattern := range refPatterns {
+		if !strings.HasPrefix(pattern, "refs/") {
+			return nil, fmt.Errorf("%w: '%s'", ErrInvalidRuleRef, pattern)
+		}
+	}
+
+	for i, delegation := range targetsMetadata.Delegations.Roles {
+		if delegation.Name != ruleName {
+			continue
+		}
+
+		if len(refPatterns) == 0 {
+			targetsMetadata.Delegations.Roles[i].Refs = nil
+			return targetsMetadata, nil
+		}
+
+		for _, pattern := range delegation.Paths {
+			if !strings.HasPrefix(pattern, "file:") { // FIXME: "file:" shouldn't be here
+				return nil, fmt.Errorf("%w: rule '%s' protects '%s'", ErrRuleRefsRequireFileRule, ruleName, pattern)
+			}
+		}
+
+		targetsMetadata.Delegations.Roles[i].Refs = refPatterns
+		return targetsMetadata, nil
+	}
+
+	return nil, ErrDelegationNotFound
+}
+
+// SetRuleThreshold sets the number of distinct keys authorized by the specified
+// rule that must sign a change. For rules protecting Git refs, each commit must
+// carry signatures from the threshold of keys, using co-signatures if needed.
+func SetRuleThreshold(targetsMetadata *tuf.TargetsMetadata, ruleName string, threshold int) (*tuf.TargetsMetadata, error) {
+	if ruleName == AllowRuleName {
+		return nil, ErrCannotManipulateAllowRule
+	}
+	if err := checkRuleNameIsUnique(targetsMetadata, ruleName); err != nil {
+		return nil, err
+	}
+
+	for i, delegation := range targetsMetadata.Delegations.Roles {
+		if delegation.Name == ruleName {
+			targetsMetadata.Delegations.Roles[i].Threshold = threshold
+			return targetsMetadata, nil
+		}
+	}
+
+	return nil, ErrDelegationNotFound
+}
+
+// SetRuleExpiry sets the time after which the specified rule no longer applies.
+// An expired rule does not match any namespace, so it contributes no keys and
+// does not shadow the rules after it. The namespaces it protected are only
+// protected after it expires if other rules also protect them. Passing the zero
+// time removes the rule's expiry.
+func SetRuleExpiry(targetsMetadata *tuf.TargetsMetadata, ruleName string, expires time.Time) (*tuf.TargetsMetadata, error) {
+	if ruleName == AllowRuleName {
+		return nil, ErrCannotManipulateAllowRule
+	}
+	if err := checkRuleNameIsUnique(targetsMetadata, ruleName); err != nil {
+		return nil, err
+	}
+
+	for i, delegation := range targetsMetadata.Delegations.Roles {
+		if delegation.Name != ruleName {
+			continue
+		}
+
+		if expires.IsZero() {
+			targetsMetadata.Delegations.Roles[i].Expires = nil
+			return targetsMetadata, nil
+		}
+
+		expires = expires.UTC()
+		targetsMetadata.Delegations.Roles[i].Expires = &expires
+		return targetsMetadata, nil
+	}
+
+	return nil, ErrDelegationNotFound
+}
+
+// SetKeyValidityWindow restricts the key with the specified ID, which must be
+// authorized by the metadata's rules, to signing commits created between
+// notBefore and notAfter. Either bound may be left as the zero value. If both
+// are zero, the key's validity window is removed.
+func SetKeyValidityWindow(targetsMetadata *tuf.TargetsMetadata, keyID string, notBefore, notAfter time.Time) (*tuf.TargetsMetadata, error) {
+	if _, has := targetsMetadata.Delegations.Keys[keyID]; !has {
+		return nil, fmt.Errorf("%w: '%s'", ErrKeyNotFound, keyID)
+	}
+
+	if notBefore.IsZero() && notAfter.IsZero() {
+		delete(targetsMetadata.Delegations.KeyValidity, keyID)
+		if len(targetsMetadata.Delegations.KeyValidity) == 0 {
+			targetsMetadata.Delegations.KeyValidity = nil
+		}
+		return targetsMetadata, nil
+	}
+
+	if !notBefore.IsZero() && !notAfter.IsZero() && notAfter.Before(notBefore) {
+		return nil, fmt.Errorf("%w: key '%s' would be trusted until %s, before %s", tuf.ErrInvalidKeyValidityWindow, keyID, notAfter.Format(time.RFC3339), notBefore.Format(time.RFC3339))
+	}
+
+	window := &tuf.KeyValidityWindow{}
+	if !notBefore.IsZero() {
+		notBefore = notBefore.UTC()
+		window.NotBefore = &notBefore
+	}
+	if !notAfter.IsZero() {
+		notAfter = notAfter.UTC()
+		window.NotAfter = &notAfter
+	}
+
+	if targetsMetadata.Delegations.KeyValidity == nil {
+		targetsMetadata.Delegations.KeyValidity = map[string]*tuf.KeyValidityWindow{}
+	}
+	targetsMetadata.Delegations.KeyValidity[keyID] = window
+
+	return targetsMetadata, nil
+}
+
 // RemoveDelegation deletes a delegation entry from TargetsMetadata.
 func RemoveDelegation(targetsMetadata *tuf.TargetsMetadata, ruleName string) (*tuf.TargetsMetadata, error) {
 	if ruleName == AllowRuleName {
@@ -92,6 +401,23 @@ func RemoveDelegation(targetsMetadata *tuf.TargetsMetadata, ruleName string) (*t
 	return targetsMetadata, nil
 }
 
+// checkRuleNameIsUnique returns an error if the specified rule is declared
+// more than once in the targets metadata. Such metadata can only be authored
+// manually, and updating it by rule name is ambiguous.
+func checkRuleNameIsUnique(targetsMetadata *tuf.TargetsMetadata, ruleName string) error {
+	count := 0
+	for _, delegation := range targetsMetadata.Delegations.Roles {
+		if delegation.Name == ruleName {
+			count++
+		}
+	}
+	if count > 1 {
+		return fmt.Errorf("%w: rule '%s' is declared more than once", tuf.ErrDuplicateDelegationName, ruleName)
+	}
+
+	return nil
+}
+
 // AddKeyToTargets adds public keys to the specified targets metadata.
 func AddKeyToTargets(targetsMetadata *tuf.TargetsMetadata, authorizedKeys []*tuf.Key) (*tuf.TargetsMetadata, error) {
 	for _, key := range authorizedKeys {
@@ -113,3 +439,18 @@ func AllowRule() tuf.Delegation {
 		},
 	}
 }
+
+// DenyRule returns a rule with the specified name that rejects all changes to
+// the namespaces matching the specified patterns.
+func DenyRule(ruleName string, rulePatterns []string) tuf.Delegation {
+	return tuf.Delegation{
+		Name:        ruleName,
+		Paths:       rulePatterns,
+		Terminating: true,
+		Deny:        true,
+		Role: tuf.Role{
+			KeyIDs:    []string{},
+			Threshold: 1,
+		},
+	}
+}
@@ -5,7 +5,9 @@ package policy
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/gittuf/gittuf/internal/signerverifier/gpg"
 	"github.com/gittuf/gittuf/internal/tuf"
@@ -20,6 +22,73 @@ func TestInitializeTargetsMetadata(t *testing.T) {
 	assert.Contains(t, targetsMetadata.Delegations.Roles, AllowRule())
 }
 
+func TestValidateTargetsMetadata(t *testing.T) {
+	keyBytes, err := os.ReadFile(filepath.Join("test-data", "targets-1.pub"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := tuf.LoadKeyFromBytes(keyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("valid metadata", func(t *testing.T) {
+		targetsMetadata, err := AddOrUpdateDelegation(InitializeTargetsMetadata(), "test-rule", []*tuf.Key{key}, []string{"git:refs/heads/main"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = ValidateTargetsMetadata(targetsMetadata)
+		assert.Nil(t, err)
+	})
+
+	t.Run("invalid rule", func(t *testing.T) {
+		targetsMetadata, err := AddOrUpdateDelegation(InitializeTargetsMetadata(), "test-rule", []*tuf.Key{key}, []string{"git:refs/heads/["})
+		if err != nil {
+			t.Fatal(err)
+		}
+		delete(targetsMetadata.Delegations.Keys, key.KeyID)
+
+		err = ValidateTargetsMetadata(targetsMetadata)
+		assert.ErrorIs(t, err, tuf.ErrInvalidDelegationPatterns)
+		assert.ErrorIs(t, err, tuf.ErrDelegationKeyMissing)
+	})
+
+	t.Run("allow rule is not last", func(t *testing.T) {
+		targetsMetadata := InitializeTargetsMetadata()
+		targetsMetadata.Delegations.AddDelegation(tuf.Delegation{
+			Name:  "test-rule",
+			Paths: []string{"git:refs/heads/main"},
+			Role:  tuf.Role{KeyIDs: []string{}, Threshold: 1},
+		})
+
+		err := ValidateTargetsMetadata(targetsMetadata)
+		assert.ErrorIs(t, err, ErrAllowRuleNotLast)
+	})
+
+	t.Run("duplicate rule name", func(t *testing.T) {
+		targetsMetadata, err := AddOrUpdateDelegation(InitializeTargetsMetadata(), "test-rule", []*tuf.Key{key}, []string{"git:refs/heads/main"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Manually authored metadata may declare a rule twice
+		targetsMetadata.Delegations.Roles = append([]tuf.Delegation{targetsMetadata.Delegations.Roles[0]}, targetsMetadata.Delegations.Roles...)
+
+		err = ValidateTargetsMetadata(targetsMetadata)
+		assert.ErrorIs(t, err, tuf.ErrDuplicateDelegationName)
+
+		// The ambiguous rule cannot be updated by name
+		_, err = AddOrUpdateDelegation(targetsMetadata, "test-rule", []*tuf.Key{key}, []string{"git:refs/heads/feature"})
+		assert.ErrorIs(t, err, tuf.ErrDuplicateDelegationName)
+
+		_, err = AddOrUpdateDenyRule(targetsMetadata, "test-rule", []string{"git:refs/heads/feature"})
+		assert.ErrorIs(t, err, tuf.ErrDuplicateDelegationName)
+
+		_, err = SetAllowedHashes(targetsMetadata, "test-rule", nil)
+		assert.ErrorIs(t, err, tuf.ErrDuplicateDelegationName)
+	})
+}
+
 func TestAddOrUpdateDelegation(t *testing.T) {
 	targetsMetadata := InitializeTargetsMetadata()
 
@@ -55,6 +124,350 @@ func TestAddOrUpdateDelegation(t *testing.T) {
 	}, targetsMetadata.Delegations.Roles[0])
 }
 
+func TestAddOrUpdateDenyRule(t *testing.T) {
+	targetsMetadata := InitializeTargetsMetadata()
+
+	keyBytes, err := os.ReadFile(filepath.Join("test-data", "targets-1.pub"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := tuf.LoadKeyFromBytes(keyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targetsMetadata, err = AddOrUpdateDelegation(targetsMetadata, "test-rule", []*tuf.Key{key}, []string{"test/"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targetsMetadata, err = AddOrUpdateDenyRule(targetsMetadata, "deny-rule", []string{"test/secret"})
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(targetsMetadata.Delegations.Roles))
+	assert.Equal(t, "test-rule", targetsMetadata.Delegations.Roles[0].Name)
+	assert.Equal(t, DenyRule("deny-rule", []string{"test/secret"}), targetsMetadata.Delegations.Roles[1])
+	assert.Equal(t, AllowRule(), targetsMetadata.Delegations.Roles[2])
+
+	// Convert an existing rule into a deny rule
+	targetsMetadata, err = AddOrUpdateDenyRule(targetsMetadata, "test-rule", []string{"test/"})
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(targetsMetadata.Delegations.Roles))
+	assert.Equal(t, DenyRule("test-rule", []string{"test/"}), targetsMetadata.Delegations.Roles[0])
+
+	_, err = AddOrUpdateDenyRule(targetsMetadata, AllowRuleName, []string{"*"})
+	assert.ErrorIs(t, err, ErrCannotManipulateAllowRule)
+}
+
+func TestSetAllowedHashes(t *testing.T) {
+	targetsMetadata := InitializeTargetsMetadata()
+
+	keyBytes, err := os.ReadFile(filepath.Join("test-data", "targets-1.pub"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := tuf.LoadKeyFromBytes(keyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targetsMetadata, err = AddOrUpdateDelegation(targetsMetadata, "test-rule", []*tuf.Key{key}, []string{"file:.github/workflows/*"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	allowedHash := "E69DE29BB2D1D6434B8B29AE775AD8C2E48C5391"
+
+	targetsMetadata, err = SetAllowedHashes(targetsMetadata, "test-rule", []string{allowedHash})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{strings.ToLower(allowedHash)}, targetsMetadata.Delegations.Roles[0].AllowedHashes)
+
+	// Updating the rule's patterns and keys retains the pinned hashes
+	targetsMetadata, err = AddOrUpdateDelegation(targetsMetadata, "test-rule", []*tuf.Key{key}, []string{"file:.github/*"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{strings.ToLower(allowedHash)}, targetsMetadata.Delegations.Roles[0].AllowedHashes)
+
+	targetsMetadata, err = SetAllowedHashes(targetsMetadata, "test-rule", nil)
+	assert.Nil(t, err)
+	assert.Nil(t, targetsMetadata.Delegations.Roles[0].AllowedHashes)
+
+	_, err = SetAllowedHashes(targetsMetadata, "test-rule", []string{"not-a-hash"})
+	assert.ErrorIs(t, err, ErrInvalidAllowedHash)
+
+	_, err = SetAllowedHashes(targetsMetadata, "missing-rule", []string{allowedHash})
+	assert.ErrorIs(t, err, ErrDelegationNotFound)
+
+	_, err = SetAllowedHashes(targetsMetadata, AllowRuleName, []string{allowedHash})
+	assert.ErrorIs(t, err, ErrCannotManipulateAllowRule)
+}
+
+func TestSetMinDistinctSigners(t *testing.T) {
+	keyBytes, err := os.ReadFile(filepath.Join("test-data", "targets-1.pub"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := tuf.LoadKeyFromBytes(keyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targetsMetadata, err := AddOrUpdateDelegation(InitializeTargetsMetadata(), "test-rule", []*tuf.Key{key}, []string{"git:refs/heads/main"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targetsMetadata, err = SetMinDistinctSigners(targetsMetadata, "test-rule", 1, 5)
+	assert.Nil(t, err)
+	assert.Equal(t, &tuf.DistinctSignersRequirement{Count: 1, Window: 5}, targetsMetadata.Delegations.Roles[0].MinDistinctSigners)
+	assert.Nil(t, ValidateTargetsMetadata(targetsMetadata))
+
+	// Updating the rule retains the requirement
+	targetsMetadata, err = AddOrUpdateDelegation(targetsMetadata, "test-rule", []*tuf.Key{key}, []string{"git:refs/heads/*"})
+	assert.Nil(t, err)
+	assert.NotNil(t, targetsMetadata.Delegations.Roles[0].MinDistinctSigners)
+
+	// The rule doesn't authorize enough keys
+	targetsMetadata, err = SetMinDistinctSigners(targetsMetadata, "test-rule", 2, 5)
+	assert.Nil(t, err)
+	assert.ErrorIs(t, ValidateTargetsMetadata(targetsMetadata), tuf.ErrInvalidDistinctSigners)
+
+	targetsMetadata, err = SetMinDistinctSigners(targetsMetadata, "test-rule", 0, 0)
+	assert.Nil(t, err)
+	assert.Nil(t, targetsMetadata.Delegations.Roles[0].MinDistinctSigners)
+
+	_, err = SetMinDistinctSigners(targetsMetadata, "missing-rule", 1, 5)
+	assert.ErrorIs(t, err, ErrDelegationNotFound)
+
+	_, err = SetMinDistinctSigners(targetsMetadata, AllowRuleName, 1, 5)
+	assert.ErrorIs(t, err, ErrCannotManipulateAllowRule)
+}
+
+func TestSetVerifyMergeCommits(t *testing.T) {
+	keyBytes, err := os.ReadFile(filepath.Join("test-data", "targets-1.pub"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := tuf.LoadKeyFromBytes(keyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targetsMetadata, err := AddOrUpdateDelegation(InitializeTargetsMetadata(), "test-rule", []*tuf.Key{key}, []string{"git:refs/heads/main"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targetsMetadata, err = SetVerifyMergeCommits(targetsMetadata, "test-rule", true)
+	assert.Nil(t, err)
+	assert.True(t, targetsMetadata.Delegations.Roles[0].VerifyMergeCommits)
+
+	// Updating the rule retains the requirement
+	targetsMetadata, err = AddOrUpdateDelegation(targetsMetadata, "test-rule", []*tuf.Key{key}, []string{"git:refs/heads/*"})
+	assert.Nil(t, err)
+	assert.True(t, targetsMetadata.Delegations.Roles[0].VerifyMergeCommits)
+
+	targetsMetadata, err = SetVerifyMergeCommits(targetsMetadata, "test-rule", false)
+	assert.Nil(t, err)
+	assert.False(t, targetsMetadata.Delegations.Roles[0].VerifyMergeCommits)
+
+	_, err = SetVerifyMergeCommits(targetsMetadata, "missing-rule", true)
+	assert.ErrorIs(t, err, ErrDelegationNotFound)
+
+	_, err = SetVerifyMergeCommits(targetsMetadata, AllowRuleName, true)
+	assert.ErrorIs(t, err, ErrCannotManipulateAllowRule)
+}
+
+func TestSetRequireSignedCommits(t *testing.T) {
+	keyBytes, err := os.ReadFile(filepath.Join("test-data", "targets-1.pub"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := tuf.LoadKeyFromBytes(keyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targetsMetadata, err := AddOrUpdateDelegation(InitializeTargetsMetadata(), "test-rule", []*tuf.Key{key}, []string{"git:refs/heads/main"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targetsMetadata, err = SetRequireSignedCommits(targetsMetadata, "test-rule", true)
+	assert.Nil(t, err)
+	assert.True(t, targetsMetadata.Delegations.Roles[0].RequireSignedCommits)
+
+	// Updating the rule retains the requirement
+	targetsMetadata, err = AddOrUpdateDelegation(targetsMetadata, "test-rule", []*tuf.Key{key}, []string{"git:refs/heads/*"})
+	assert.Nil(t, err)
+	assert.True(t, targetsMetadata.Delegations.Roles[0].RequireSignedCommits)
+
+	targetsMetadata, err = SetRequireSignedCommits(targetsMetadata, "test-rule", false)
+	assert.Nil(t, err)
+	assert.False(t, targetsMetadata.Delegations.Roles[0].RequireSignedCommits)
+
+	_, err = SetRequireSignedCommits(targetsMetadata, "missing-rule", true)
+	assert.ErrorIs(t, err, ErrDelegationNotFound)
+
+	_, err = SetRequireSignedCommits(targetsMetadata, AllowRuleName, true)
+	assert.ErrorIs(t, err, ErrCannotManipulateAllowRule)
+}
+
+func TestSetRuleRefs(t *testing.T) {
+	keyBytes, err := os.ReadFile(filepath.Join("test-data", "targets-1.pub"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := tuf.LoadKeyFromBytes(keyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targetsMetadata, err := AddOrUpdateDelegation(InitializeTargetsMetadata(), "file-rule", []*tuf.Key{key}, []string{"file:deploy/*.yaml"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	targetsMetadata, err = AddOrUpdateDelegation(targetsMetadata, "ref-rule", []*tuf.Key{key}, []string{"git:refs/heads/main"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targetsMetadata, err = SetRuleRefs(targetsMetadata, "file-rule", []string{"refs/heads/prod", "refs/heads/release/*"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"refs/heads/prod", "refs/heads/release/*"}, targetsMetadata.Delegations.Roles[0].Refs)
+	assert.Nil(t, ValidateTargetsMetadata(targetsMetadata))
+
+	targetsMetadata, err = SetRuleRefs(targetsMetadata, "file-rule", nil)
+	assert.Nil(t, err)
+	assert.Nil(t, targetsMetadata.Delegations.Roles[0].Refs)
+
+	_, err = SetRuleRefs(targetsMetadata, "file-rule", []string{"prod"})
+	assert.ErrorIs(t, err, ErrInvalidRuleRef)
+
+	_, err = SetRuleRefs(targetsMetadata, "ref-rule", []string{"refs/heads/prod"})
+	assert.ErrorIs(t, err, ErrRuleRefsRequireFileRule)
+
+	_, err = SetRuleRefs(targetsMetadata, "missing-rule", []string{"refs/heads/prod"})
+	assert.ErrorIs(t, err, ErrDelegationNotFound)
+
+	_, err = SetRuleRefs(targetsMetadata, AllowRuleName, []string{"refs/heads/prod"})
+	assert.ErrorIs(t, err, ErrCannotManipulateAllowRule)
+}
+
+func TestSetRuleExpiry(t *testing.T) {
+	keyBytes, err := os.ReadFile(filepath.Join("test-data", "targets-1.pub"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := tuf.LoadKeyFromBytes(keyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targetsMetadata, err := AddOrUpdateDelegation(InitializeTargetsMetadata(), "test-rule", []*tuf.Key{key}, []string{"git:refs/heads/main"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expires := time.Date(2030, time.January, 1, 0, 0, 0, 0, time.FixedZone("UTC+5", 5*60*60))
+	targetsMetadata, err = SetRuleExpiry(targetsMetadata, "test-rule", expires)
+	assert.Nil(t, err)
+	if assert.NotNil(t, targetsMetadata.Delegations.Roles[0].Expires) {
+		assert.True(t, expires.Equal(*targetsMetadata.Delegations.Roles[0].Expires))
+		assert.Equal(t, time.UTC, targetsMetadata.Delegations.Roles[0].Expires.Location())
+	}
+
+	targetsMetadata, err = SetRuleExpiry(targetsMetadata, "test-rule", time.Time{})
+	assert.Nil(t, err)
+	assert.Nil(t, targetsMetadata.Delegations.Roles[0].Expires)
+
+	_, err = SetRuleExpiry(targetsMetadata, "missing-rule", expires)
+	assert.ErrorIs(t, err, ErrDelegationNotFound)
+
+	_, err = SetRuleExpiry(targetsMetadata, AllowRuleName, expires)
+	assert.ErrorIs(t, err, ErrCannotManipulateAllowRule)
+}
+
+func TestSetKeyValidityWindow(t *testing.T) {
+	keyBytes, err := os.ReadFile(filepath.Join("test-data", "targets-1.pub"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := tuf.LoadKeyFromBytes(keyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targetsMetadata, err := AddOrUpdateDelegation(InitializeTargetsMetadata(), "protect-main", []*tuf.Key{key}, []string{"git:refs/heads/main"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	notBefore := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.FixedZone("UTC+2", 2*60*60))
+	notAfter := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	targetsMetadata, err = SetKeyValidityWindow(targetsMetadata, key.KeyID, notBefore, notAfter)
+	assert.Nil(t, err)
+	window := targetsMetadata.Delegations.KeyValidity[key.KeyID]
+	assert.True(t, notBefore.Equal(*window.NotBefore))
+	assert.Equal(t, time.UTC, window.NotBefore.Location())
+	assert.True(t, notAfter.Equal(*window.NotAfter))
+	assert.Nil(t, ValidateTargetsMetadata(targetsMetadata))
+
+	targetsMetadata, err = SetKeyValidityWindow(targetsMetadata, key.KeyID, time.Time{}, notAfter)
+	assert.Nil(t, err)
+	window = targetsMetadata.Delegations.KeyValidity[key.KeyID]
+	assert.Nil(t, window.NotBefore)
+	assert.True(t, notAfter.Equal(*window.NotAfter))
+
+	_, err = SetKeyValidityWindow(targetsMetadata, key.KeyID, notAfter, notBefore)
+	assert.ErrorIs(t, err, tuf.ErrInvalidKeyValidityWindow)
+
+	_, err = SetKeyValidityWindow(targetsMetadata, "missing-key", notBefore, notAfter)
+	assert.ErrorIs(t, err, ErrKeyNotFound)
+
+	targetsMetadata, err = SetKeyValidityWindow(targetsMetadata, key.KeyID, time.Time{}, time.Time{})
+	assert.Nil(t, err)
+	assert.Nil(t, targetsMetadata.Delegations.KeyValidity)
+}
+
+func TestSetRuleThreshold(t *testing.T) {
+	keyBytes, err := os.ReadFile(filepath.Join("test-data", "targets-1.pub"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	key1, err := tuf.LoadKeyFromBytes(keyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyBytes, err = os.ReadFile(filepath.Join("test-data", "targets-2.pub"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	key2, err := tuf.LoadKeyFromBytes(keyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targetsMetadata, err := AddOrUpdateDelegation(InitializeTargetsMetadata(), "test-rule", []*tuf.Key{key1, key2}, []string{"git:refs/heads/main"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targetsMetadata, err = SetRuleThreshold(targetsMetadata, "test-rule", 2)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, targetsMetadata.Delegations.Roles[0].Threshold)
+	assert.Nil(t, ValidateTargetsMetadata(targetsMetadata))
+
+	targetsMetadata, err = SetRuleThreshold(targetsMetadata, "test-rule", 3)
+	assert.Nil(t, err)
+	assert.ErrorIs(t, ValidateTargetsMetadata(targetsMetadata), tuf.ErrInvalidDelegationThreshold)
+
+	_, err = SetRuleThreshold(targetsMetadata, "missing-rule", 1)
+	assert.ErrorIs(t, err, ErrDelegationNotFound)
+
+	_, err = SetRuleThreshold(targetsMetadata, AllowRuleName, 1)
+	assert.ErrorIs(t, err, ErrCannotManipulateAllowRule)
+}
+
 func TestRemoveDelegation(t *testing.T) {
 	targetsMetadata := InitializeTargetsMetadata()
 
@@ -125,3 +538,12 @@ func TestAllowRule(t *testing.T) {
 	assert.Empty(t, allowRule.KeyIDs)
 	assert.Equal(t, 1, allowRule.Threshold)
 }
+
+func TestDenyRule(t *testing.T) {
+	denyRule := DenyRule("deny-rule", []string{"git:refs/heads/frozen"})
+	assert.Equal(t, "deny-rule", denyRule.Name)
+	assert.Equal(t, []string{"git:refs/heads/frozen"}, denyRule.Paths)
+	assert.True(t, denyRule.Terminating)
+	assert.True(t, denyRule.Deny)
+	assert.Empty(t, denyRule.KeyIDs)
+}
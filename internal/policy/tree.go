@@ -0,0 +1,117 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"context"
+	"time"
+
+	"github.com/gittuf/gittuf/internal/tuf"
+)
+
+// DelegationTreeNode describes a policy file or a rule in the policy. The
+// nodes at the top of the tree returned by State.DelegationTree are the top
+// level policy files, whose keys and threshold are set in the root of trust.
+// Every other node is a rule, and its children are the rules in the policy
+// file it delegates to, if any.
+type DelegationTreeNode struct {
+	Name        string                `json:"name"`
+	Paths       []string              `json:"paths,omitempty"`
+	Refs        []string              `json:"refs,omitempty"`
+	KeyIDs      []string              `json:"keyids"`
+	Threshold   int                   `json:"threshold"`
+	Terminating bool                  `json:"terminating,omitempty"`
+	Deny        bool                  `json:"deny,omitempty"`
+	Expires     *time.Time            `json:"expires,omitempty"`
+	Children    []*DelegationTreeNode `json:"delegations,omitempty"`
+}
+
+// DelegationTree returns the structure of the delegations in the policy. A node
+// is returned for each top level policy file, with the rules of the policy file
+// as its children. Rules that delegate to another policy file have the rules
+// of that policy file as their children, and so on. Rules are listed in the
+// order they are declared, which is the order in which they are considered
+// during verification. The allow rule at the end of each policy file is
+// omitted.
+func (s *State) DelegationTree(ctx context.Context) ([]*DelegationTreeNode, error) {
+	if err := s.Verify(ctx); err != nil {
+		return nil, err
+	}
+
+	rootMetadata, err := s.GetRootMetadata()
+	if err != nil {
+		return nil, err
+	}
+
+	topLevelRoleNames, err := s.TopLevelTargetsRoleNames()
+	if err != nil {
+		return nil, err
+	}
+
+	tree := make([]*DelegationTreeNode, 0, len(topLevelRoleNames))
+	for _, roleName := range topLevelRoleNames {
+		role := rootMetadata.Roles[roleName]
+		node := &DelegationTreeNode{
+			Name:      roleName,
+			KeyIDs:    role.KeyIDs,
+			Threshold: role.Threshold,
+		}
+
+		children, err := s.delegationTreeForRole(roleName, map[string]bool{roleName: true})
+		if err != nil {
+			return nil, err
+		}
+		node.Children = children
+
+		tree = append(tree, node)
+	}
+
+	return tree, nil
+}
+
+// delegationTreeForRole returns the nodes for the rules in the specified policy
+// file. The policy files being expanded along the current branch of the tree
+// are recorded in ancestors, so that a policy file that is delegated to by one
+// of its own descendants is not expanded again.
+func (s *State) delegationTreeForRole(roleName string, ancestors map[string]bool) ([]*DelegationTreeNode, error) {
+	targetsMetadata, err := s.GetTargetsMetadata(roleName)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := []*DelegationTreeNode{}
+	for _, delegation := range targetsMetadata.Delegations.Roles {
+		if delegation.Name == AllowRuleName {
+			continue
+		}
+
+		node := newDelegationTreeNode(delegation)
+		if s.HasTargetsRole(delegation.Name) && !ancestors[delegation.Name] {
+			ancestors[delegation.Name] = true
+			children, err := s.delegationTreeForRole(delegation.Name, ancestors)
+			if err != nil {
+				return nil, err
+			}
+			delete(ancestors, delegation.Name)
+
+			node.Children = children
+		}
+
+		nodes = append(nodes, node)
+	}
+
+	return nodes, nil
+}
+
+func newDelegationTreeNode(delegation tuf.Delegation) *DelegationTreeNode {
+	return &DelegationTreeNode{
+		Name:        delegation.Name,
+		Paths:       delegation.Paths,
+		Refs:        delegation.Refs,
+		KeyIDs:      delegation.KeyIDs,
+		Threshold:   delegation.Threshold,
+		Terminating: delegation.Terminating,
+		Deny:        delegation.Deny,
+		Expires:     delegation.Expires,
+	}
+}
@@ -0,0 +1,106 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/signerverifier/gpg"
+	"github.com/gittuf/gittuf/internal/tuf"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStateDelegationTree(t *testing.T) {
+	rootKey, err := tuf.LoadKeyFromBytes(rootPubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gpgKey, err := gpg.LoadGPGKeyFromBytes(gpgPubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	targets1Key := loadTestKey(t, "targets-1.pub")
+
+	t.Run("two levels of delegations", func(t *testing.T) {
+		state := createTestStateWithPolicy(t)
+
+		targetsMetadata, err := state.GetTargetsMetadata(TargetsRoleName)
+		if err != nil {
+			t.Fatal(err)
+		}
+		targetsMetadata, err = AddOrUpdateDelegation(targetsMetadata, "protect-src", []*tuf.Key{targets1Key}, []string{"file:src/*"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := range targetsMetadata.Delegations.Roles {
+			if targetsMetadata.Delegations.Roles[i].Name == "protect-src" {
+				targetsMetadata.Delegations.Roles[i].Terminating = true
+			}
+		}
+		state.TargetsEnvelope = signTestEnvelope(t, targetsMetadata, rootKeyBytes)
+
+		delegatedMetadata, err := AddOrUpdateDelegation(InitializeTargetsMetadata(), "protect-src-lib", []*tuf.Key{gpgKey}, []string{"file:src/lib*"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		state.DelegationEnvelopes = map[string]*sslibdsse.Envelope{
+			"protect-src": signTestEnvelope(t, delegatedMetadata, loadTestKeyBytes(t, "targets-1")),
+		}
+
+		tree, err := state.DelegationTree(testCtx)
+		assert.Nil(t, err)
+
+		expectedTree := []*DelegationTreeNode{
+			{
+				Name:      TargetsRoleName,
+				KeyIDs:    []string{rootKey.KeyID},
+				Threshold: 1,
+				Children: []*DelegationTreeNode{
+					{
+						Name:      "protect-main",
+						Paths:     []string{"git:refs/heads/main"},
+						KeyIDs:    []string{gpgKey.KeyID},
+						Threshold: 1,
+					},
+					{
+						Name:      "protect-files-1-and-2",
+						Paths:     []string{"file:1", "file:2"},
+						KeyIDs:    []string{gpgKey.KeyID},
+						Threshold: 1,
+					},
+					{
+						Name:        "protect-src",
+						Paths:       []string{"file:src/*"},
+						KeyIDs:      []string{targets1Key.KeyID},
+						Threshold:   1,
+						Terminating: true,
+						Children: []*DelegationTreeNode{
+							{
+								Name:      "protect-src-lib",
+								Paths:     []string{"file:src/lib*"},
+								KeyIDs:    []string{gpgKey.KeyID},
+								Threshold: 1,
+							},
+						},
+					},
+				},
+			},
+		}
+		assert.Equal(t, expectedTree, tree)
+
+		// The tree can be serialized
+		treeBytes, err := json.Marshal(tree)
+		assert.Nil(t, err)
+		assert.Contains(t, string(treeBytes), `"delegations":[{"name":"protect-src-lib"`)
+	})
+
+	t.Run("policy with only root", func(t *testing.T) {
+		state := createTestStateWithOnlyRoot(t)
+
+		tree, err := state.DelegationTree(testCtx)
+		assert.Nil(t, err)
+		assert.Empty(t, tree)
+	})
+}
@@ -6,9 +6,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"runtime"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/logging"
 	"github.com/gittuf/gittuf/internal/rsl"
 	"github.com/gittuf/gittuf/internal/signerverifier"
 	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
@@ -32,15 +36,180 @@ const (
 	goodSignatureMessageForRSLEntry   = "good signature for RSL entry"
 	badSignatureMessageForRSLEntry    = "bad signature for RSL entry"
 	noSignatureMessage                = "no signature found"
-	errorVerifyingSignatureMessageFmt = "verifying signature using key '%s:%s' failed: %s"
 	unableToFindRSLEntryMessage       = "unable to find tag's RSL entry"
 	multipleTagRSLEntriesFoundMessage = "multiple RSL entries found for tag"
 )
 
 var (
-	ErrUnauthorizedSignature = errors.New("unauthorized signature")
+	ErrUnauthorizedSignature    = errors.New("unauthorized signature")
+	ErrCommitNotProtected       = errors.New("no rules in policy protect the changes made by the commit")
+	ErrRSLTargetMismatch        = errors.New("ref's current target does not match the target recorded in the RSL")
+	ErrUnauthorizedForcePush    = errors.New("non-fast-forward ref update is not authorized by an RSL annotation")
+	ErrUnauthorizedRSLWriter    = errors.New("RSL entry is not signed by an authorized RSL writer")
+	ErrUnauthorizedPolicyChange = errors.New("policy change is not authorized by the prior policy")
+	ErrUnapprovedFileContents   = errors.New("file contents are not in the set of hashes allowed by policy")
+	ErrTooFewDistinctSigners    = errors.New("recent commits are not signed by enough distinct authorized keys")
+	ErrThresholdNotMet          = errors.New("commit is not signed by a threshold of authorized keys")
+	ErrUnattributedChanges      = errors.New("changes to protected path are not attributed to any commit")
+	ErrUnattributedMergeChanges = errors.New("merge commit introduces changes not made by any of its parents")
+	ErrUnsignedCommit           = errors.New("commit is not signed by a key authorized for the ref")
+	ErrTagTargetNotRecorded     = errors.New("commit pointed to by tag has not been recorded in the RSL")
+	ErrTagTargetNotVerified     = errors.New("commit pointed to by tag is not signed by a key trusted by the policy it was recorded under")
+	ErrTagTargetNotCommit       = errors.New("tag does not point to a commit")
 )
 
+// verifyNotesEntry verifies an RSL entry for a notes ref. Notes refs are
+// protected using rules for the "git:" namespace like other refs. In addition to
+// the RSL entry, every notes commit recorded by the entry must be signed by a key
+// trusted for the notes ref, as the notes commits carry the data, such as review
+// approvals, that is attached to other objects. If the notes ref is not
+// protected, no further verification is performed.
+func verifyNotesEntry(ctx context.Context, repo *git.Repository, policy *State, entry *rsl.ReferenceEntry, annotations []*rsl.AnnotationEntry) error {
+	namespace := fmt.Sprintf("git:%s", entry.RefName) // FIXME: "git:" shouldn't be here
+
+	// 1. Find the rules and authorized public keys for the notes ref
+	delegations, keys, err := policy.FindDelegationsForPath(ctx, namespace)
+	if err != nil {
+		return err
+	}
+	if len(delegations) == 0 {
+		return nil
+	}
+
+	ruleNames := []string{}
+	trustedKeys := []*tuf.Key{}
+	for _, delegation := range delegations {
+		ruleNames = append(ruleNames, delegation.Name)
+		for _, keyID := range delegation.KeyIDs {
+			if key, has := keys[keyID]; has {
+				trustedKeys = append(trustedKeys, key)
+			}
+		}
+	}
+
+	// 2. Verify the RSL entry's signature
+	commitObj, err := repo.CommitObject(entry.ID)
+	if err != nil {
+		return err
+	}
+	verified, err := isCommitSignedByAnyKey(ctx, policy, commitObj, trustedKeys)
+	if err != nil {
+		return err
+	}
+	if !verified {
+		return fmt.Errorf("verifying Git namespace policies failed, %w", ErrUnauthorizedSignature)
+	}
+
+	// A deletion of the notes ref introduces no notes commits
+	if entry.IsDeletion() {
+		return nil
+	}
+
+	// 3. Verify non-fast-forward updates are authorized
+	if err := verifyForcePushAuthorization(ctx, repo, policy, trustedKeys, entry, annotations); err != nil {
+		return err
+	}
+
+	// 4. Verify each notes commit recorded by the entry
+	commits, err := getCommits(repo, entry)
+	if err != nil {
+		return err
+	}
+	for _, commit := range commits {
+		verified, err := isCommitSignedByAnyKey(ctx, policy, commit, trustedKeys)
+		if err != nil {
+			return err
+		}
+		if verified {
+			continue
+		}
+
+		annotatedObjectIDs, err := gitinterface.GetNotesChangedByCommit(repo, commit)
+		if err != nil {
+			return err
+		}
+		annotatedObjects := make([]string, 0, len(annotatedObjectIDs))
+		for _, objectID := range annotatedObjectIDs {
+			annotatedObjects = append(annotatedObjects, objectID.String())
+		}
+
+		return &CommitVerificationError{
+			CommitID:  commit.Hash,
+			Namespace: namespace,
+			RuleNames: ruleNames,
+			Err:       fmt.Errorf("%w: notes commit changes notes for '%s'", ErrUnauthorizedSignature, strings.Join(annotatedObjects, ", ")),
+		}
+	}
+
+	return nil
+}
+
+// isCommitSignedByAnyKey indicates if the commit's signature is verified by one
+// of the specified keys.
+func isCommitSignedByAnyKey(ctx context.Context, policy *State, commit *object.Commit, keys []*tuf.Key) (bool, error) {
+	for _, key := range keys {
+		err := verifyCommitSignature(ctx, policy, commit, key)
+		if err == nil {
+			return true, nil
+		}
+		if errors.Is(err, gitinterface.ErrUnknownSigningMethod) {
+			continue
+		}
+		if !errors.Is(err, gitinterface.ErrIncorrectVerificationKey) {
+			return false, err
+		}
+	}
+
+	return false, nil
+}
+
+// verifyCommitSignature verifies the commit's signature using the key. If the
+// policy records a validity window for the key, the signature is only accepted
+// if the time the policy is evaluated at is within the window. This is the time
+// of the RSL entry that recorded the commit, rather than the commit's own
+// timestamps, which the signer could backdate.
+func verifyCommitSignature(ctx context.Context, policy *State, commit *object.Commit, key *tuf.Key) error {
+	notBefore, notAfter, err := policy.keyValidityWindow(key.KeyID)
+	if err != nil {
+		return err
+	}
+
+	return gitinterface.VerifyCommitSignature(ctx, commit, key, gitinterface.WithKeyValidityWindow(notBefore, notAfter), gitinterface.WithTrustedTime(policy.now()))
+}
+
+// verifyTagSignature verifies the tag's signature using the key, applying the
+// key's validity window as described for verifyCommitSignature.
+func verifyTagSignature(ctx context.Context, policy *State, tag *object.Tag, key *tuf.Key) error {
+	notBefore, notAfter, err := policy.keyValidityWindow(key.KeyID)
+	if err != nil {
+		return err
+	}
+
+	return gitinterface.VerifyTagSignature(ctx, tag, key, gitinterface.WithKeyValidityWindow(notBefore, notAfter), gitinterface.WithTrustedTime(policy.now()))
+}
+
+// CommitVerificationError identifies a commit that could not be verified. If
+// the commit's signature isn't trusted by the policy, the namespace and the
+// names of the unsatisfied rules are recorded as well.
+type CommitVerificationError struct {
+	CommitID  plumbing.Hash
+	Namespace string
+	RuleNames []string
+	Err       error
+}
+
+func (e *CommitVerificationError) Error() string {
+	if len(e.Namespace) == 0 {
+		return fmt.Sprintf("verifying commit '%s' failed: %s", e.CommitID.String(), e.Err.Error())
+	}
+
+	return fmt.Sprintf("verifying commit '%s' for '%s' failed, rules '%s' not satisfied: %s", e.CommitID.String(), e.Namespace, strings.Join(e.RuleNames, ", "), e.Err.Error())
+}
+
+func (e *CommitVerificationError) Unwrap() error {
+	return e.Err
+}
+
 // VerifyRef verifies the signature on the latest RSL entry for the target ref
 // using the latest policy.
 func VerifyRef(ctx context.Context, repo *git.Repository, target string) error {
@@ -55,12 +224,19 @@ func VerifyRef(ctx context.Context, repo *git.Repository, target string) error {
 	}
 
 	// 2. Find latest entry for target
-	latestEntry, _, err := rsl.GetLatestReferenceEntryForRef(repo, target)
+	latestEntry, annotations, err := rsl.GetLatestReferenceEntryForRef(repo, target)
 	if err != nil {
 		return err
 	}
 
-	return verifyEntry(ctx, repo, policyState, latestEntry)
+	// 3. Check that the ref matches the latest entry
+	if err := verifyRefMatchesEntry(repo, latestEntry); err != nil {
+		return err
+	}
+
+	logging.FromContext(ctx).DebugContext(ctx, logging.EventVerifyRef, "ref", target, "entry", latestEntry.ID.String(), "policy_entry", policyEntry.ID.String())
+
+	return verifyEntry(ctx, repo, policyState, latestEntry, annotations)
 }
 
 // VerifyRefFull verifies the entire RSL for the target ref from the first
@@ -78,10 +254,115 @@ func VerifyRefFull(ctx context.Context, repo *git.Repository, target string) err
 		return err
 	}
 
-	// 3. Do a relative verify from start entry to the latest entry (firstEntry here == policyEntry)
+	// 3. Check that the ref matches the latest entry
+	if err := verifyRefMatchesEntry(repo, latestEntry); err != nil {
+		return err
+	}
+
+	logging.FromContext(ctx).DebugContext(ctx, logging.EventVerifyRef, "ref", target, "entry", latestEntry.ID.String(), "first_entry", firstEntry.ID.String())
+
+	// 4. Do a relative verify from start entry to the latest entry (firstEntry here == policyEntry)
 	return VerifyRelativeForRef(ctx, repo, firstEntry, firstEntry, latestEntry, target)
 }
 
+// VerifyRefAtPolicy verifies the RSL entry that records targetID for the
+// target ref using the policy recorded in the specified policy RSL entry. Unlike
+// VerifyRef, the specified policy is also used to verify the commits introduced
+// by the entry rather than the policy applicable when each commit was first
+// recorded in the RSL. This can be used to reproduce historical verification
+// results, such as whether a commit was valid under a prior version of the
+// policy. If the target ref has more than one entry recording targetID, the
+// latest one is verified. If the policy entry is not for the policy ref,
+// rsl.ErrRSLEntryDoesNotMatchRef is returned.
+func VerifyRefAtPolicy(ctx context.Context, repo *git.Repository, target string, targetID, policyEntryID plumbing.Hash) error {
+	// 1. Load the specified policy
+	e, err := rsl.GetEntry(repo, policyEntryID)
+	if err != nil {
+		return err
+	}
+	policyEntry, ok := e.(*rsl.ReferenceEntry)
+	if !ok || policyEntry.RefName != PolicyRef {
+		return fmt.Errorf("%w: entry '%s' is not for '%s'", rsl.ErrRSLEntryDoesNotMatchRef, policyEntryID.String(), PolicyRef)
+	}
+	policyState, err := LoadStateForEntry(ctx, repo, policyEntry)
+	if err != nil {
+		return err
+	}
+
+	// 2. Find the latest entry for target that records targetID
+	targetEntry, annotations, err := getLatestReferenceEntryForTarget(repo, target, targetID)
+	if err != nil {
+		return err
+	}
+
+	logging.FromContext(ctx).DebugContext(ctx, logging.EventVerifyRef, "ref", target, "entry", targetEntry.ID.String(), "policy_entry", policyEntry.ID.String())
+
+	// 3. Verify the entry using only the specified policy
+	return verifyEntryWithPolicy(ctx, repo, policyState, targetEntry, annotations, true)
+}
+
+// VerifyRefWithState verifies the history of the target ref up to targetID
+// using the provided policy state rather than the policies recorded in the
+// repository. Every RSL entry for the target ref, from the first one up to and
+// including the entry that records targetID, is verified using the provided
+// state, which is also used to verify the commits introduced by each entry, as
+// in VerifyRefAtPolicy. This can be used to check whether the repository's
+// history would still verify if a candidate policy were adopted, before the
+// policy is committed. The state is verified first, so it must be signed as it
+// would be when committed. If the target ref has more than one entry recording
+// targetID, the history up to the latest one is verified.
+func VerifyRefWithState(ctx context.Context, repo *git.Repository, target string, targetID plumbing.Hash, state *State) error {
+	// 1. Verify the provided policy
+	if err := state.Verify(ctx); err != nil {
+		return err
+	}
+
+	// 2. Find the latest entry for target that records targetID
+	entries, annotationMap, err := rsl.GetReferenceEntriesForRef(repo, target)
+	if err != nil {
+		return err
+	}
+	lastIndex := -1
+	for i := len(entries) - 1; i >= 0; i-- {
+		if entries[i].TargetID == targetID {
+			lastIndex = i
+			break
+		}
+	}
+	if lastIndex < 0 {
+		return fmt.Errorf("%w: no entry for '%s' records '%s'", rsl.ErrRSLEntryNotFound, target, targetID.String())
+	}
+
+	logging.FromContext(ctx).DebugContext(ctx, logging.EventVerifyRef, "ref", target, "entry", entries[lastIndex].ID.String(), "first_entry", entries[0].ID.String())
+
+	// 3. Verify each entry up to the target entry using only the provided
+	// policy
+	for _, entry := range entries[:lastIndex+1] {
+		if err := verifyEntryWithPolicy(ctx, repo, state, entry, annotationMap[entry.ID], true); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// getLatestReferenceEntryForTarget returns the latest RSL entry for the target
+// ref that records targetID, along with the entry's annotations.
+func getLatestReferenceEntryForTarget(repo *git.Repository, target string, targetID plumbing.Hash) (*rsl.ReferenceEntry, []*rsl.AnnotationEntry, error) {
+	entries, annotationMap, err := rsl.GetReferenceEntriesForRef(repo, target)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for i := len(entries) - 1; i >= 0; i-- {
+		if entries[i].TargetID == targetID {
+			return entries[i], annotationMap[entries[i].ID], nil
+		}
+	}
+
+	return nil, nil, fmt.Errorf("%w: no entry for '%s' records '%s'", rsl.ErrRSLEntryNotFound, target, targetID.String())
+}
+
 // VerifyRelativeForRef verifies the RSL between specified start and end entries
 // using the provided policy entry for the first entry.
 //
@@ -97,7 +378,7 @@ func VerifyRelativeForRef(ctx context.Context, repo *git.Repository, initialPoli
 	currentPolicy = state
 
 	// 2. Enumerate RSL entries between firstEntry and lastEntry, ignoring irrelevant ones
-	entries, _, err := rsl.GetReferenceEntriesInRangeForRef(repo, firstEntry.ID, lastEntry.ID, target)
+	entries, annotationMap, err := rsl.GetReferenceEntriesInRangeForRef(repo, firstEntry.ID, lastEntry.ID, target)
 	if err != nil {
 		return err
 	}
@@ -117,13 +398,17 @@ func VerifyRelativeForRef(ctx context.Context, repo *git.Repository, initialPoli
 		// signatures. This comment is being left in until this workflow is
 		// audited against the gittuf specification.
 		if entry.RefName == PolicyRef {
+			if err := verifyRSLWriter(ctx, repo, currentPolicy, entry.ID); err != nil {
+				return err
+			}
+
 			// TODO: this is repetition if the firstEntry is for policy
 			newPolicy, err := LoadStateForEntry(ctx, repo, entry)
 			if err != nil {
 				return err
 			}
 
-			if err := currentPolicy.VerifyNewState(ctx, newPolicy); err != nil {
+			if err := VerifyPolicyTransition(ctx, currentPolicy, newPolicy); err != nil {
 				return err
 			}
 
@@ -131,7 +416,7 @@ func VerifyRelativeForRef(ctx context.Context, repo *git.Repository, initialPoli
 			continue
 		}
 
-		if err := verifyEntry(ctx, repo, currentPolicy, entry); err != nil {
+		if err := verifyEntry(ctx, repo, currentPolicy, entry, annotationMap[entry.ID]); err != nil {
 			return err
 		}
 	}
@@ -139,6 +424,172 @@ func VerifyRelativeForRef(ctx context.Context, repo *git.Repository, initialPoli
 	return nil
 }
 
+// VerifyNewEntries verifies the RSL entries recorded after previousTip up to and
+// including tip, in the order they were recorded, such as the entries received
+// in a push or a fetch. Verification starts with the policy trusted at
+// previousTip. Each policy entry must be recorded by an RSL writer trusted by
+// the current policy, and the policy it records must be a valid transition from
+// the current policy, as checked by VerifyPolicyTransition. The new policy is
+// then used to verify the entries that follow it. If previousTip is the zero
+// hash, nothing is trusted yet, and the first policy entry establishes the
+// initial policy. An unauthorized policy change is returned as an error, as
+// none of the entries that follow it can be verified. Every other entry is
+// verified using the current policy, along with the annotations for it that
+// are in the range. The first failure for each ref is returned, keyed by the
+// ref's name.
+func VerifyNewEntries(ctx context.Context, repo *git.Repository, previousTip, tip plumbing.Hash) (map[string]error, error) {
+	var currentPolicy *State
+	if !previousTip.IsZero() {
+		policyEntry, err := getLatestPolicyEntryAt(repo, previousTip)
+		if err != nil {
+			return nil, err
+		}
+		currentPolicy, err = LoadStateForEntry(ctx, repo, policyEntry)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	entries, err := rsl.GetEntriesSince(repo, previousTip, tip)
+	if err != nil {
+		return nil, err
+	}
+
+	annotationMap := map[plumbing.Hash][]*rsl.AnnotationEntry{}
+	for _, entry := range entries {
+		annotation, isAnnotation := entry.(*rsl.AnnotationEntry)
+		if !isAnnotation {
+			continue
+		}
+		for _, entryID := range annotation.RSLEntryIDs {
+			annotationMap[entryID] = append(annotationMap[entryID], annotation)
+		}
+	}
+
+	entryErrs := map[string]error{}
+	for _, e := range entries {
+		entry, isReferenceEntry := e.(*rsl.ReferenceEntry)
+		if !isReferenceEntry {
+			continue
+		}
+
+		if entry.RefName == PolicyRef {
+			newPolicy, err := LoadStateForEntry(ctx, repo, entry)
+			if err != nil {
+				return nil, err
+			}
+
+			if currentPolicy != nil {
+				if err := verifyRSLWriter(ctx, repo, currentPolicy, entry.ID); err != nil {
+					return nil, errors.Join(ErrUnauthorizedPolicyChange, err)
+				}
+				if err := VerifyPolicyTransition(ctx, currentPolicy, newPolicy); err != nil {
+					return nil, err
+				}
+			}
+
+			currentPolicy = newPolicy
+			continue
+		}
+
+		// Entries recorded before the first policy have nothing to be
+		// verified against
+		if currentPolicy == nil {
+			continue
+		}
+		if _, failed := entryErrs[entry.RefName]; failed {
+			continue
+		}
+
+		if err := verifyEntry(ctx, repo, currentPolicy, entry, annotationMap[entry.ID]); err != nil {
+			entryErrs[entry.RefName] = fmt.Errorf("verifying RSL entry '%s' failed: %w", entry.ID.String(), err)
+		}
+	}
+
+	return entryErrs, nil
+}
+
+// VerifyRSLRewindAnnotation verifies that the annotation is signed by one of
+// the RSL writer or root keys trusted in the policy in effect at trustedEntryID.
+// It is used to check that a rewind of an RSL past trustedEntryID was
+// authorized by the keys trusted before the rewind. If no policy is recorded at
+// trustedEntryID, the annotation cannot be authorized and an error wrapping
+// ErrUnauthorizedSignature is returned.
+func VerifyRSLRewindAnnotation(ctx context.Context, repo *git.Repository, annotation *rsl.AnnotationEntry, trustedEntryID plumbing.Hash) error {
+	policyEntry, err := getLatestPolicyEntryAt(repo, trustedEntryID)
+	if err != nil {
+		if errors.Is(err, rsl.ErrRSLEntryNotFound) {
+			return fmt.Errorf("%w: no policy is recorded at RSL entry '%s' to verify annotation '%s'", ErrUnauthorizedSignature, trustedEntryID.String(), annotation.ID.String())
+		}
+		return err
+	}
+	trustedPolicy, err := LoadStateForEntry(ctx, repo, policyEntry)
+	if err != nil {
+		return err
+	}
+
+	trustedKeys, err := trustedPolicy.FindRSLWriterKeys()
+	if err != nil {
+		return err
+	}
+	trustedKeys = append(trustedKeys, trustedPolicy.RootPublicKeys...)
+
+	annotationObj, err := repo.CommitObject(annotation.ID)
+	if err != nil {
+		return err
+	}
+	if len(annotationObj.PGPSignature) == 0 {
+		return fmt.Errorf("%w: annotation '%s' is not signed", ErrUnauthorizedSignature, annotation.ID.String())
+	}
+	trustedPolicy = trustedPolicy.ForEvaluation(WithEvaluationTime(annotationObj.Committer.When))
+	for _, key := range trustedKeys {
+		err := verifyCommitSignature(ctx, trustedPolicy, annotationObj, key)
+		if err == nil {
+			return nil
+		}
+		if errors.Is(err, gitinterface.ErrUnknownSigningMethod) {
+			continue
+		}
+		if !errors.Is(err, gitinterface.ErrIncorrectVerificationKey) {
+			return err
+		}
+	}
+
+	return fmt.Errorf("%w: annotation '%s' is not signed by a trusted RSL writer or root key", ErrUnauthorizedSignature, annotation.ID.String())
+}
+
+// getEntryTime returns the time the RSL entry was recorded, as indicated by
+// the committer timestamp of its commit.
+func getEntryTime(repo *git.Repository, entry rsl.Entry) (time.Time, error) {
+	entryObj, err := repo.CommitObject(entry.GetID())
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return entryObj.Committer.When, nil
+}
+
+// getLatestPolicyEntryAt returns the latest policy entry in the RSL at the
+// specified entry, including the entry itself. The RSL is walked from the
+// specified entry, so the entry need not be reachable from the local RSL.
+func getLatestPolicyEntryAt(repo *git.Repository, entryID plumbing.Hash) (*rsl.ReferenceEntry, error) {
+	entry, err := rsl.GetEntry(repo, entryID)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		if referenceEntry, isReferenceEntry := entry.(*rsl.ReferenceEntry); isReferenceEntry && referenceEntry.RefName == PolicyRef {
+			return referenceEntry, nil
+		}
+
+		entry, err = rsl.GetParentForEntry(repo, entry)
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
 // VerifyCommit verifies the signature on the specified commits (identified by
 // their hash or via a reference that is resolved). For each commit, the policy
 // applicable when the commit was first recorded (directly or indirectly) in the
@@ -147,30 +598,28 @@ func VerifyRelativeForRef(ctx context.Context, repo *git.Repository, initialPoli
 // have an entry in the returned status. The status is currently meant to be
 // consumed directly by the user, as this is used for a special, user-invoked
 // workflow. gittuf's other verification workflows are currently not expected to
-// use this function.
+// use this function. The commits' signatures are verified concurrently.
 func VerifyCommit(ctx context.Context, repo *git.Repository, ids ...string) map[string]string {
 	status := make(map[string]string, len(ids))
 	commits := make(map[string]*object.Commit, len(ids))
 
 	for _, id := range ids {
 		if gitinterface.IsTag(repo, id) {
-			// we do this because ResolveRevision returns a tag's commit object.
+			// we do this because ResolveCommit returns a tag's commit object.
 			// For tags, we want to verify the signature on the tag object
 			// rather than the underlying commit.
 			status[id] = nonCommitMessage
 			continue
 		}
 
-		rev, err := repo.ResolveRevision(plumbing.Revision(id))
-		if err != nil {
-			status[id] = unableToResolveRevisionMessage
-			continue
-		}
-		commit, err := repo.CommitObject(*rev)
+		commit, err := gitinterface.ResolveCommit(repo, id)
 		if err != nil {
-			if errors.Is(err, plumbing.ErrObjectNotFound) {
+			switch {
+			case errors.Is(err, gitinterface.ErrRevisionNotCommit):
 				status[id] = nonCommitMessage
-			} else {
+			case errors.Is(err, gitinterface.ErrRevisionNotFound):
+				status[id] = unableToResolveRevisionMessage
+			default:
 				status[id] = err.Error()
 			}
 			continue
@@ -178,14 +627,16 @@ func VerifyCommit(ctx context.Context, repo *git.Repository, ids ...string) map[
 		commits[id] = commit
 	}
 
+	// The policy for each commit is loaded serially as it requires walking the
+	// RSL using repo, which must not be shared across goroutines
+	jobs := make([]commitVerificationJob, 0, len(commits))
 	for id, commit := range commits {
-		verified := false
 		if len(commit.PGPSignature) == 0 {
 			status[id] = noSignatureMessage
 			continue
 		}
 
-		commitPolicy, err := GetStateForCommit(ctx, repo, commit)
+		commitPolicy, err := getStateEvaluatedForCommit(ctx, repo, commit)
 		if err != nil {
 			status[id] = fmt.Sprintf(unableToLoadPolicyMessageFmt, err.Error())
 			continue
@@ -204,49 +655,486 @@ func VerifyCommit(ctx context.Context, repo *git.Repository, ids ...string) map[
 			status[id] = fmt.Sprintf(unableToLoadPolicyMessageFmt, err.Error())
 			continue
 		}
-		for _, key := range keys {
-			err = gitinterface.VerifyCommitSignature(ctx, commit, key)
-			if err == nil {
-				verified = true
-				status[id] = fmt.Sprintf(goodSignatureMessageFmt, key.KeyType, key.KeyID)
-				break
-			}
 
-			if errors.Is(err, gitinterface.ErrUnknownSigningMethod) {
-				// We encounter this for key types that can be used for metadata
-				// but not Git objects
-				continue
-			}
+		jobs = append(jobs, commitVerificationJob{id: id, commitID: commit.Hash, policy: commitPolicy, keys: keys})
+	}
 
-			if !errors.Is(err, gitinterface.ErrIncorrectVerificationKey) {
-				status[id] = fmt.Sprintf(errorVerifyingSignatureMessageFmt, key.KeyType, key.KeyID, err.Error())
+	// The signatures are verified concurrently, with each worker loading the
+	// commits it verifies using a shared ObjectReader
+	reader := gitinterface.NewObjectReader(repo)
+	results := make([]string, len(jobs))
+	numWorkers := min(runtime.GOMAXPROCS(0), len(jobs))
+	next := make(chan int)
+	wg := sync.WaitGroup{}
+	for w := 0; w < numWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for i := range next {
+				results[i] = jobs[i].verify(ctx, reader)
 			}
-		}
+		}()
+	}
+	for i := range jobs {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
 
-		if !verified {
-			status[id] = noPublicKeyMessage
-		}
+	for i, job := range jobs {
+		status[job.id] = results[i]
 	}
 
 	return status
 }
 
-// VerifyTag verifies the signature on the RSL entries for the specified tags.
-// In addition, each tag object's signature is also verified using the same set
-// of trusted keys. If the tag is not protected by policy, then all keys in the
-// applicable policy are used to verify the signatures.
-func VerifyTag(ctx context.Context, repo *git.Repository, ids []string) map[string]string {
-	status := make(map[string]string, len(ids))
+// commitVerificationJob identifies a commit submitted to VerifyCommit along
+// with the policy and keys its signature must be verified with.
+type commitVerificationJob struct {
+	id       string
+	commitID plumbing.Hash
+	policy   *State
+	keys     map[string]*tuf.Key
+}
 
-	for _, id := range ids {
-		// Check if id is tag name or hash of tag obj
-		absPath, err := gitinterface.AbsoluteReference(repo, id)
-		if err == nil {
-			if !strings.HasPrefix(absPath, gitinterface.TagRefPrefix) {
-				status[id] = nonTagMessage
-				continue
-			}
-		} else {
+// verify loads the job's commit using reader and returns its verification
+// status. It's safe to call concurrently for different jobs.
+func (j commitVerificationJob) verify(ctx context.Context, reader *gitinterface.ObjectReader) string {
+	commit, err := reader.CommitObject(j.commitID)
+	if err != nil {
+		return err.Error()
+	}
+
+	for _, key := range j.keys {
+		if err := verifyCommitSignature(ctx, j.policy, commit, key); err == nil {
+			return fmt.Sprintf(goodSignatureMessageFmt, key.KeyType, key.KeyID)
+		}
+	}
+
+	return noPublicKeyMessage
+}
+
+// VerifyCommitObject verifies the signature on the specified commit object
+// using the repository's current policy. Unlike VerifyCommit, the commit does
+// not need to have been recorded in the RSL. The trusted keys are identified
+// using the paths modified by the commit and, if refHint is set, the ref the
+// commit is intended for. The names of the rules whose keys verified the commit
+// are returned. If no rules in the policy protect the commit's changes,
+// ErrCommitNotProtected is returned. If the commit's signature cannot be
+// verified using the keys trusted for a protected namespace, a
+// *CommitVerificationError wrapping ErrUnauthorizedSignature is returned.
+func VerifyCommitObject(ctx context.Context, repo *git.Repository, commit *object.Commit, refHint string) ([]string, error) {
+	policyState, err := LoadCurrentState(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	return verifyCommitWithState(ctx, repo, policyState, commit, refHint)
+}
+
+// VerifyCommitRange verifies the commits introduced to the target ref by
+// updating it from oldID to newID. This is meant to be used before the update
+// is recorded in the RSL, such as in a pre-receive hook. The commits are
+// verified oldest first, and verification stops at the first commit that
+// cannot be verified. In that case, a *CommitVerificationError identifying the
+// commit is returned. Each commit is verified using the policy returned by
+// GetStateForCommit, falling back to the repository's current policy for
+// commits that haven't been recorded in the RSL yet. Commits whose changes
+// aren't protected by the applicable policy are considered verified. If oldID
+// is zero, all the commits reachable from newID are verified.
+func VerifyCommitRange(ctx context.Context, repo *git.Repository, target string, oldID, newID plumbing.Hash) error {
+	commits, err := gitinterface.GetCommitsBetweenRangeOldestFirst(repo, newID, oldID)
+	if err != nil {
+		return err
+	}
+
+	var currentState *State
+	for _, commit := range commits {
+		commitPolicy, err := getStateEvaluatedForCommit(ctx, repo, commit)
+		if err != nil {
+			return &CommitVerificationError{CommitID: commit.Hash, Err: err}
+		}
+		if commitPolicy == nil {
+			if currentState == nil {
+				currentState, err = LoadCurrentState(ctx, repo)
+				if err != nil {
+					return err
+				}
+			}
+			commitPolicy = currentState
+		}
+
+		if _, err := verifyCommitWithState(ctx, repo, commitPolicy, commit, target); err != nil {
+			if errors.Is(err, ErrCommitNotProtected) {
+				continue
+			}
+
+			var verificationErr *CommitVerificationError
+			if errors.As(err, &verificationErr) {
+				return verificationErr
+			}
+			return &CommitVerificationError{CommitID: commit.Hash, Err: err}
+		}
+	}
+
+	return nil
+}
+
+// VerifyEntryCandidate checks that the reference entry, whose commit object has
+// been created but not yet added to the RSL, is recorded by an authorized RSL
+// writer and signed by a key trusted for its ref in the repository's current
+// policy. If the ref is protected, the entry must also fast-forward the ref from
+// the target of its latest entry, as a force push annotation cannot refer to an
+// entry that hasn't been recorded. The commits the entry introduces are not
+// verified, see VerifyCommitRange. This is meant to be used to reject an entry
+// before it's recorded.
+func VerifyEntryCandidate(ctx context.Context, repo *git.Repository, entry *rsl.ReferenceEntry) error {
+	policyState, err := LoadCurrentState(ctx, repo)
+	if err != nil {
+		return err
+	}
+	entryTime, err := getEntryTime(repo, entry)
+	if err != nil {
+		return err
+	}
+	policyState = policyState.ForEvaluation(WithEvaluationTime(entryTime))
+
+	if err := verifyRSLWriter(ctx, repo, policyState, entry.ID); err != nil {
+		return err
+	}
+
+	trustedKeys, err := policyState.FindPublicKeysForPath(ctx, fmt.Sprintf("git:%s", entry.RefName))
+	if err != nil {
+		return err
+	}
+	if len(trustedKeys) == 0 {
+		return nil
+	}
+
+	entryObj, err := repo.CommitObject(entry.ID)
+	if err != nil {
+		return err
+	}
+	entryVerified := false
+	for _, key := range trustedKeys {
+		err := verifyCommitSignature(ctx, policyState, entryObj, key)
+		if err == nil {
+			entryVerified = true
+			break
+		}
+		if errors.Is(err, gitinterface.ErrUnknownSigningMethod) {
+			continue
+		}
+		if !errors.Is(err, gitinterface.ErrIncorrectVerificationKey) {
+			return err
+		}
+	}
+	if !entryVerified {
+		return fmt.Errorf("verifying Git namespace policies failed, %w", ErrUnauthorizedSignature)
+	}
+
+	latestEntry, _, err := rsl.GetLatestReferenceEntryForRef(repo, entry.RefName)
+	if err != nil {
+		if errors.Is(err, rsl.ErrRSLEntryNotFound) {
+			return nil
+		}
+		return err
+	}
+	if latestEntry.TargetID.IsZero() || entry.TargetID.IsZero() {
+		return nil
+	}
+	latestCommit, err := repo.CommitObject(latestEntry.TargetID)
+	if err != nil {
+		return err
+	}
+	knows, err := gitinterface.KnowsCommit(repo, entry.TargetID, latestCommit)
+	if err != nil {
+		return err
+	}
+	if !knows {
+		return fmt.Errorf("%w: update of '%s' is not a fast-forward", ErrUnauthorizedForcePush, entry.RefName)
+	}
+
+	return nil
+}
+
+// VerifyRefDiff verifies the changes that updating the target ref from baseID
+// to headID would introduce, such as the changes in a pull request. Rather than
+// verifying every commit against every rule, only the paths that differ between
+// headID and its merge base with baseID are considered, as a code review gate
+// would. Paths that are changed and then reverted within the range therefore do
+// not need to be verified. For each changed path protected by the repository's
+// current policy, every commit in the range that modified the path, including
+// merge commits that introduced changes to it themselves or resolved conflicts
+// in it, must be signed by the threshold of keys of a rule that protects the
+// path. If a commit is not, a *CommitVerificationError wrapping
+// ErrUnauthorizedSignature is returned for the first such path and commit. A
+// protected path that no commit in the range can be held responsible for
+// changing fails with ErrUnattributedChanges. The contents of each changed path
+// in headID must also be allowed by the rules that pin them, see
+// ErrUnapprovedFileContents. If headID shares no history with baseID, every
+// path in headID is considered changed.
+func VerifyRefDiff(ctx context.Context, repo *git.Repository, target string, baseID, headID plumbing.Hash) error {
+	policyState, err := LoadCurrentState(ctx, repo)
+	if err != nil {
+		return err
+	}
+
+	headCommit, err := repo.CommitObject(headID)
+	if err != nil {
+		return err
+	}
+	baseCommit, err := repo.CommitObject(baseID)
+	if err != nil {
+		return err
+	}
+
+	var mergeBase *object.Commit
+	mergeBases, err := headCommit.MergeBase(baseCommit)
+	if err != nil {
+		return err
+	}
+	mergeBaseID := plumbing.ZeroHash
+	if len(mergeBases) > 0 {
+		mergeBase = mergeBases[0]
+		mergeBaseID = mergeBase.Hash
+	}
+
+	changedPaths, err := gitinterface.GetDiffFilePaths(headCommit, mergeBase)
+	if err != nil {
+		return err
+	}
+	if len(changedPaths) == 0 {
+		return nil
+	}
+	isChangedPath := make(map[string]bool, len(changedPaths))
+	for _, path := range changedPaths {
+		isChangedPath[path] = true
+	}
+
+	// Identify the commits in the range that modified each changed path
+	commits, err := gitinterface.GetCommitsBetweenRangeOldestFirst(repo, headID, mergeBaseID)
+	if err != nil {
+		return err
+	}
+	commitsForPath := map[string][]*object.Commit{}
+	for _, commit := range commits {
+		var paths []string
+		if len(commit.ParentHashes) > 1 {
+			paths, err = gitinterface.GetFilePathsChangedByMergeCommit(repo, commit)
+			if err != nil {
+				return err
+			}
+
+			// The merge commit is also responsible for the contents of the
+			// paths in which it resolves conflicts
+			resolvedPaths, err := gitinterface.GetFilePathsResolvedByMergeCommit(repo, commit)
+			if err != nil {
+				return err
+			}
+			paths = append(paths, resolvedPaths...)
+		} else {
+			paths, err = gitinterface.GetFilePathsChangedByCommit(repo, commit)
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, path := range paths {
+			if isChangedPath[path] {
+				commitsForPath[path] = append(commitsForPath[path], commit)
+			}
+		}
+	}
+
+	for _, path := range changedPaths {
+		namespace := fmt.Sprintf("file:%s", path) // FIXME: "file:" shouldn't be here
+		delegations, keys, err := policyState.FindDelegationsForPathOnRef(ctx, namespace, target)
+		if err != nil {
+			return err
+		}
+		if len(delegations) == 0 {
+			continue
+		}
+
+		ruleNames := make([]string, 0, len(delegations))
+		for _, delegation := range delegations {
+			ruleNames = append(ruleNames, delegation.Name)
+		}
+
+		if len(commitsForPath[path]) == 0 {
+			// The path's changes cannot be attributed to any commit in the
+			// range, so there's no signature that can authorize them
+			return &CommitVerificationError{
+				CommitID:  headID,
+				Namespace: namespace,
+				RuleNames: ruleNames,
+				Err:       ErrUnattributedChanges,
+			}
+		}
+
+		for _, commit := range commitsForPath[path] {
+			verified := false
+			for _, delegation := range delegations {
+				verified, err = isCommitAuthorizedByRule(ctx, policyState, commit, delegation, keys)
+				if err != nil {
+					return err
+				}
+				if verified {
+					break
+				}
+			}
+
+			if !verified {
+				return &CommitVerificationError{
+					CommitID:  commit.Hash,
+					Namespace: namespace,
+					RuleNames: ruleNames,
+					Err:       ErrUnauthorizedSignature,
+				}
+			}
+		}
+
+		if err := verifyAllowedHashes(ctx, repo, policyState, headCommit, target, []string{path}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// isCommitAuthorizedByRule checks if the commit is signed by the threshold of
+// keys authorized by the rule. Rules with a threshold of 1 are satisfied by the
+// commit's signature alone, while higher thresholds also count co-signatures,
+// see verifyCommitThreshold.
+func isCommitAuthorizedByRule(ctx context.Context, policy *State, commit *object.Commit, delegation tuf.Delegation, keys map[string]*tuf.Key) (bool, error) {
+	authorizedKeys := []*tuf.Key{}
+	for _, keyID := range delegation.KeyIDs {
+		if key, has := keys[keyID]; has {
+			authorizedKeys = append(authorizedKeys, key)
+		}
+	}
+
+	if delegation.Threshold <= 1 {
+		return isCommitSignedByAnyKey(ctx, policy, commit, authorizedKeys)
+	}
+
+	if err := verifyCommitThreshold(ctx, policy, commit, authorizedKeys, delegation.Threshold); err != nil {
+		if errors.Is(err, gitinterface.ErrSignatureThresholdNotMet) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// verifyCommitWithState verifies the signature on the specified commit using
+// the specified policy. See VerifyCommitObject for details.
+func verifyCommitWithState(ctx context.Context, repo *git.Repository, policyState *State, commit *object.Commit, refHint string) ([]string, error) {
+	topLevelRoleNames, err := policyState.TopLevelTargetsRoleNames()
+	if err != nil {
+		return nil, err
+	}
+	if len(topLevelRoleNames) == 0 {
+		return nil, ErrCommitNotProtected
+	}
+
+	paths, err := gitinterface.GetFilePathsChangedByCommit(repo, commit)
+	if err != nil {
+		return nil, err
+	}
+
+	namespaces := make([]string, 0, len(paths)+1)
+	if len(refHint) > 0 {
+		namespaces = append(namespaces, fmt.Sprintf("git:%s", refHint)) // FIXME: "git:" shouldn't be here
+	}
+	for _, path := range paths {
+		namespaces = append(namespaces, fmt.Sprintf("file:%s", path)) // FIXME: "file:" shouldn't be here
+	}
+
+	protected := false
+	matchedRules := []string{}
+	matchedRulesSet := map[string]bool{}
+	for _, namespace := range namespaces {
+		delegations, keys, err := policyState.FindDelegationsForPathOnRef(ctx, namespace, refHint)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(delegations) == 0 {
+			continue
+		}
+		protected = true
+
+		namespaceVerified := false
+		for _, delegation := range delegations {
+			for _, keyID := range delegation.KeyIDs {
+				key, ok := keys[keyID]
+				if !ok {
+					continue
+				}
+
+				err := verifyCommitSignature(ctx, policyState, commit, key)
+				if err == nil {
+					namespaceVerified = true
+					if !matchedRulesSet[delegation.Name] {
+						matchedRulesSet[delegation.Name] = true
+						matchedRules = append(matchedRules, delegation.Name)
+					}
+					break
+				}
+				if errors.Is(err, gitinterface.ErrIncorrectVerificationKey) || errors.Is(err, gitinterface.ErrUnknownSigningMethod) {
+					continue
+				}
+
+				// Unexpected error
+				return nil, err
+			}
+		}
+
+		if !namespaceVerified {
+			ruleNames := make([]string, 0, len(delegations))
+			for _, delegation := range delegations {
+				ruleNames = append(ruleNames, delegation.Name)
+			}
+
+			return nil, &CommitVerificationError{
+				CommitID:  commit.Hash,
+				Namespace: namespace,
+				RuleNames: ruleNames,
+				Err:       ErrUnauthorizedSignature,
+			}
+		}
+	}
+
+	if !protected {
+		return nil, ErrCommitNotProtected
+	}
+
+	if err := verifyAllowedHashes(ctx, repo, policyState, commit, refHint, paths); err != nil {
+		return nil, err
+	}
+
+	return matchedRules, nil
+}
+
+// VerifyTag verifies the signature on the RSL entries for the specified tags.
+// In addition, each tag object's signature is also verified using the same set
+// of trusted keys. If the tag is not protected by policy, then all keys in the
+// applicable policy are used to verify the signatures.
+func VerifyTag(ctx context.Context, repo *git.Repository, ids []string) map[string]string {
+	status := make(map[string]string, len(ids))
+
+	for _, id := range ids {
+		// Check if id is tag name or hash of tag obj
+		absPath, err := gitinterface.AbsoluteReference(repo, id)
+		if err == nil {
+			if !strings.HasPrefix(absPath, gitinterface.TagRefPrefix) {
+				status[id] = nonTagMessage
+				continue
+			}
+		} else {
 			if !errors.Is(err, gitinterface.ErrReferenceNotFound) {
 				status[id] = err.Error()
 				continue
@@ -298,7 +1186,18 @@ func VerifyTag(ctx context.Context, repo *git.Repository, ids []string) map[stri
 // VerifyNewState ensures that when a new policy is encountered, its root role
 // is signed by keys trusted in the current policy.
 func (s *State) VerifyNewState(ctx context.Context, newPolicy *State) error {
-	currentRoot, err := s.GetRootMetadata()
+	return VerifyPolicyTransition(ctx, s, newPolicy)
+}
+
+// VerifyPolicyTransition verifies that the policy change from prev to next is
+// authorized by prev, as with root metadata roll-over in TUF. The next policy's
+// root metadata must be signed by a threshold of the root keys trusted in prev,
+// and its version must not be lower than that of prev's root metadata. The
+// rest of next's metadata is verified using next's root of trust as part of
+// State.Verify. If the change is not authorized, the returned error wraps
+// ErrUnauthorizedPolicyChange.
+func VerifyPolicyTransition(ctx context.Context, prev, next *State) error {
+	currentRoot, err := prev.GetRootMetadata()
 	if err != nil {
 		return err
 	}
@@ -324,7 +1223,19 @@ func (s *State) VerifyNewState(ctx context.Context, newPolicy *State) error {
 		verifiers = append(verifiers, sv)
 	}
 
-	return dsse.VerifyEnvelope(ctx, newPolicy.RootEnvelope, verifiers, rootThreshold)
+	if err := dsse.VerifyEnvelope(ctx, next.RootEnvelope, verifiers, rootThreshold); err != nil {
+		return errors.Join(ErrUnauthorizedPolicyChange, err)
+	}
+
+	nextRoot, err := next.GetRootMetadata()
+	if err != nil {
+		return err
+	}
+	if nextRoot.Version < currentRoot.Version {
+		return fmt.Errorf("%w: root metadata version %d is lower than prior version %d", ErrUnauthorizedPolicyChange, nextRoot.Version, currentRoot.Version)
+	}
+
+	return nil
 }
 
 // verifyEntry is a helper to verify an entry's signature using the specified
@@ -332,199 +1243,935 @@ func (s *State) VerifyNewState(ctx context.Context, newPolicy *State) error {
 // commit signatures, verifyEntry checks when the commit was first introduced
 // via the RSL across all refs. Then, it uses the policy applicable at the
 // commit's first entry into the repository. If the commit is brand new to the
-// repository, the specified policy is used.
-func verifyEntry(ctx context.Context, repo *git.Repository, policy *State, entry *rsl.ReferenceEntry) error {
-	// TODO: discuss how / if we want to verify RSL entry signatures for the policy namespace
-	if entry.RefName == PolicyRef {
+// repository, the specified policy is used. If the entry records the deletion
+// of its ref, only the entry's signature is verified, as no commits are
+// introduced. An entry rejected by the policy's rules is accepted if break-glass
+// annotations signed by a threshold of the root of trust's emergency keys refer
+// to it.
+func verifyEntry(ctx context.Context, repo *git.Repository, policy *State, entry *rsl.ReferenceEntry, annotations []*rsl.AnnotationEntry) error {
+	return verifyEntryWithPolicy(ctx, repo, policy, entry, annotations, false)
+}
+
+// verifyEntryWithPolicy verifies an entry's signature as described for
+// verifyEntry. If fixedPolicy is set, the specified policy is also used for the
+// commit signatures instead of the policy applicable at each commit's first
+// entry into the repository.
+func verifyEntryWithPolicy(ctx context.Context, repo *git.Repository, policy *State, entry *rsl.ReferenceEntry, annotations []*rsl.AnnotationEntry, fixedPolicy bool) error {
+	// Rules that expire are evaluated as of when the entry was recorded, so
+	// that verifying the entry later has the same result
+	entryTime, err := getEntryTime(repo, entry)
+	if err != nil {
+		return err
+	}
+	policy = policy.ForEvaluation(WithEvaluationTime(entryTime))
+
+	// The entry and its annotations must be recorded by authorized RSL writers
+	if err := verifyRSLWriter(ctx, repo, policy, entry.ID); err != nil {
+		return err
+	}
+	for _, annotation := range annotations {
+		if err := verifyRSLWriter(ctx, repo, policy, annotation.ID); err != nil {
+			return err
+		}
+	}
+
+	err = verifyEntryRules(ctx, repo, policy, entry, annotations, fixedPolicy)
+	if err == nil {
 		return nil
 	}
 
-	if strings.HasPrefix(entry.RefName, gitinterface.TagRefPrefix) {
-		return verifyTagEntry(ctx, repo, policy, entry)
+	// In an emergency, the emergency role of the root of trust may authorize
+	// an entry that the policy's rules reject. Such entries are recorded in the
+	// verification report carried by ctx, if any
+	breakGlassAnnotationIDs, breakGlassErr := verifyBreakGlassAuthorization(ctx, repo, policy, entry, annotations)
+	if breakGlassErr != nil {
+		return errors.Join(err, breakGlassErr)
+	}
+	if len(breakGlassAnnotationIDs) == 0 {
+		return err
 	}
 
-	var (
-		trustedKeys           []*tuf.Key
-		err                   error
+	annotationIDs := make([]string, 0, len(breakGlassAnnotationIDs))
+	for _, id := range breakGlassAnnotationIDs {
+		annotationIDs = append(annotationIDs, id.String())
+	}
+	logging.FromContext(ctx).WarnContext(ctx, logging.EventBreakGlassUsed, "ref", entry.RefName, "entry", entry.ID.String(), "annotations", annotationIDs, "error", err.Error())
+
+	if report := reportFromContext(ctx); report != nil {
+		report.addBreakGlassUse(&BreakGlassUse{
+			RefName:       entry.RefName,
+			EntryID:       entry.ID,
+			AnnotationIDs: breakGlassAnnotationIDs,
+			Err:           err,
+		})
+	}
+
+	return nil
+}
+
+// verifyEntryRules verifies the entry against the rules of the policy, as
+// described for verifyEntry. The entry and its annotations are expected to have
+// been verified as recorded by authorized RSL writers already.
+func verifyEntryRules(ctx context.Context, repo *git.Repository, policy *State, entry *rsl.ReferenceEntry, annotations []*rsl.AnnotationEntry, fixedPolicy bool) error {
+	// TODO: discuss how / if we want to verify RSL entry signatures for the policy namespace
+	if entry.RefName == PolicyRef {
+		return nil
+	}
+
+	if strings.HasPrefix(entry.RefName, gitinterface.TagRefPrefix) {
+		return verifyTagEntry(ctx, repo, policy, entry)
+	}
+
+	if strings.HasPrefix(entry.RefName, gitinterface.NotesRefPrefix) {
+		return verifyNotesEntry(ctx, repo, policy, entry, annotations)
+	}
+
+	var (
+		trustedKeys           []*tuf.Key
+		err                   error
 		gitNamespaceVerified  = false
 		pathNamespaceVerified = true // Assume paths are verified until we find out otherwise
 	)
 
-	// 1. Find authorized public keys for entry's ref
-	trustedKeys, err = policy.FindPublicKeysForPath(ctx, fmt.Sprintf("git:%s", entry.RefName)) // FIXME: "git:" shouldn't be here
+	// 1. Find authorized public keys for entry's ref
+	trustedKeys, err = policy.FindPublicKeysForPath(ctx, fmt.Sprintf("git:%s", entry.RefName)) // FIXME: "git:" shouldn't be here
+	if err != nil {
+		return err
+	}
+
+	// No trusted keys => allow any key's signature for the git namespace
+	if len(trustedKeys) == 0 {
+		gitNamespaceVerified = true
+	}
+
+	// 2. Find commit object for the RSL entry
+	commitObj, err := repo.CommitObject(entry.ID)
+	if err != nil {
+		return err
+	}
+
+	// 3. Use each trusted key to verify signature
+	logger := logging.FromContext(ctx)
+	for _, key := range trustedKeys {
+		err := verifyCommitSignature(ctx, policy, commitObj, key)
+		if err == nil {
+			// Signature verification succeeded
+			logger.DebugContext(ctx, logging.EventKeyMatched, "key_id", key.KeyID, "namespace", fmt.Sprintf("git:%s", entry.RefName), "entry", entry.ID.String())
+			gitNamespaceVerified = true
+			break
+		}
+		if errors.Is(err, gitinterface.ErrUnknownSigningMethod) {
+			continue
+		}
+		if !errors.Is(err, gitinterface.ErrIncorrectVerificationKey) {
+			// Unexpected error
+			return err
+		}
+		// Haven't found a valid key, continue with next key
+	}
+
+	if !gitNamespaceVerified {
+		return fmt.Errorf("verifying Git namespace policies failed, %w", ErrUnauthorizedSignature)
+	}
+
+	// A deletion of the ref introduces no commits, so it only has to be
+	// recorded by a key trusted for the ref
+	if entry.IsDeletion() {
+		return nil
+	}
+
+	// 4. Verify non-fast-forward updates are authorized
+	if err := verifyForcePushAuthorization(ctx, repo, policy, trustedKeys, entry, annotations); err != nil {
+		return err
+	}
+
+	// 5. Verify new commits meet the threshold of rules requiring co-signers
+	if err := verifyCommitThresholds(ctx, repo, policy, entry); err != nil {
+		return err
+	}
+
+	// 6. Verify the ref's recent history has enough distinct signers
+	if err := verifyDistinctSigners(ctx, repo, policy, entry); err != nil {
+		return err
+	}
+
+	// 7. Verify merge commits do not introduce changes of their own
+	if err := verifyMergeCommits(ctx, repo, policy, entry); err != nil {
+		return err
+	}
+
+	// 8. Verify every new commit is signed, if required
+	if err := verifySignedCommits(ctx, repo, policy, entry); err != nil {
+		return err
+	}
+
+	// 9. Verify modified files
+
+	// First, get all commits between the current and last entry for the ref.
+	commits, err := getCommits(repo, entry) // note: this is ordered by commit ID
+	if err != nil {
+		return err
+	}
+
+	commitsVerified := make([]bool, len(commits))
+	for i, commit := range commits {
+		// Assume the commit's paths are verified, if a path is left unverified,
+		// we flip this later.
+		commitsVerified[i] = true
+
+		paths, err := gitinterface.GetFilePathsChangedByCommit(repo, commit)
+		if err != nil {
+			return err
+		}
+
+		// TODO: evaluate if this can be done once for the earliest commit in
+		// the set being verified if we had them ordered.
+		var commitPolicy *State
+		if !fixedPolicy {
+			commitPolicy, err = getStateEvaluatedForCommit(ctx, repo, commit)
+			if err != nil {
+				return err
+			}
+		}
+		if commitPolicy == nil {
+			// the commit hasn't been seen in any refs in the repository or the
+			// policy is fixed, use specified policy
+			commitPolicy = policy
+		}
+
+		pathsVerified := make([]bool, len(paths))
+		verifiedKeyID := "" // this will be set after one successful verification of the commit to avoid repeated signature verification
+		for j, path := range paths {
+			trustedKeys, err := commitPolicy.FindPublicKeysForPathOnRef(ctx, fmt.Sprintf("file:%s", path), entry.RefName) // FIXME: "file:" shouldn't be here
+			if err != nil {
+				return err
+			}
+
+			if len(trustedKeys) == 0 {
+				pathsVerified[j] = true
+				continue
+			}
+
+			if len(verifiedKeyID) > 0 {
+				// We've already verified and identified commit signature's key
+				// ID, we can just check if that key ID is trusted for the new
+				// path.
+				// If not found, we don't make any assumptions about it being a
+				// failure in case of key ID mismatches. So, the signature check
+				// proceeds as usual.
+				for _, key := range trustedKeys {
+					if key.KeyID == verifiedKeyID {
+						pathsVerified[j] = true
+						break
+					}
+				}
+			}
+
+			if pathsVerified[j] {
+				continue
+			}
+
+			for _, key := range trustedKeys {
+				err := verifyCommitSignature(ctx, commitPolicy, commit, key)
+				if err == nil {
+					// Signature verification succeeded
+					logger.DebugContext(ctx, logging.EventKeyMatched, "key_id", key.KeyID, "namespace", fmt.Sprintf("file:%s", path), "commit", commit.Hash.String())
+					pathsVerified[j] = true
+					verifiedKeyID = key.KeyID
+					break
+				}
+				if errors.Is(err, gitinterface.ErrUnknownSigningMethod) {
+					continue
+				}
+				if !errors.Is(err, gitinterface.ErrIncorrectVerificationKey) {
+					// Unexpected error
+					return err
+				}
+				// Haven't found a valid key, continue with next key
+			}
+		}
+
+		for _, p := range pathsVerified {
+			if !p {
+				// Flip earlier assumption that commit paths are verified as we
+				// find that at least one path wasn't verified successfully
+				commitsVerified[i] = false
+				break
+			}
+		}
+
+		if err := verifyAllowedHashes(ctx, repo, commitPolicy, commit, entry.RefName, paths); err != nil {
+			return err
+		}
+	}
+
+	for _, c := range commitsVerified {
+		if !c {
+			// Set path namespace verified to false as at least one commit's
+			// paths weren't verified successfully
+			pathNamespaceVerified = false
+			break
+		}
+	}
+
+	if !pathNamespaceVerified {
+		return fmt.Errorf("verifying file namespace policies failed, %w", ErrUnauthorizedSignature)
+	}
+
+	return nil
+}
+
+func verifyTagEntry(ctx context.Context, repo *git.Repository, policy *State, entry *rsl.ReferenceEntry) error {
+	// 1. Find authorized public keys for tag's RSL entry
+	trustedKeys, err := policy.FindPublicKeysForPath(ctx, fmt.Sprintf("git:%s", entry.RefName))
+	if err != nil {
+		return err
+	}
+
+	if len(trustedKeys) == 0 {
+		allKeys, err := policy.PublicKeys()
+		if err != nil {
+			return err
+		}
+
+		// FIXME: decide if we want to pass around map or slice for these APIs
+		for _, key := range allKeys {
+			trustedKeys = append(trustedKeys, key)
+		}
+	}
+
+	// 2. Find commit object for the RSL entry
+	commitObj, err := repo.CommitObject(entry.ID)
+	if err != nil {
+		return err
+	}
+
+	// 3. Use each trusted key to verify signature
+	rslEntryVerified := false
+	for _, key := range trustedKeys {
+		err := verifyCommitSignature(ctx, policy, commitObj, key)
+		if err == nil {
+			// Signature verification succeeded
+			rslEntryVerified = true
+			break
+		}
+		if errors.Is(err, gitinterface.ErrUnknownSigningMethod) {
+			continue
+		}
+		if !errors.Is(err, gitinterface.ErrIncorrectVerificationKey) {
+			// Unexpected error
+			return err
+		}
+		// Haven't found a valid key, continue with next key
+	}
+
+	if !rslEntryVerified {
+		return fmt.Errorf("verifying RSL entry failed, %w", ErrUnauthorizedSignature)
+	}
+
+	// There's no tag object to verify if the tag was deleted
+	if entry.IsDeletion() {
+		return nil
+	}
+
+	// 4. Verify tag object
+	tagObjVerified := false
+	tagObj, err := repo.TagObject(entry.TargetID)
+	if err != nil {
+		// Likely indicates the ref is not pointing to a tag object
+		// What about lightweight tags?
+		return err
+	}
+
+	if len(tagObj.PGPSignature) == 0 {
+		return fmt.Errorf(noSignatureMessage)
+	}
+
+	for _, key := range trustedKeys {
+		err := verifyTagSignature(ctx, policy, tagObj, key)
+		if err == nil {
+			// Signature verification succeeded
+			tagObjVerified = true
+			break
+		}
+		if errors.Is(err, gitinterface.ErrUnknownSigningMethod) {
+			continue
+		}
+		if !errors.Is(err, gitinterface.ErrIncorrectVerificationKey) {
+			// Unexpected error
+			return err
+		}
+		// Haven't found a valid key, continue with next key
+	}
+
+	if !tagObjVerified {
+		return fmt.Errorf("verifying tag object's signature failed, %w", ErrUnauthorizedSignature)
+	}
+
+	// 5. Verify the commit the tag points to
+	return verifyTaggedCommit(ctx, repo, entry, tagObj)
+}
+
+// verifyTaggedCommit checks that the commit pointed to by the tag was recorded
+// in the RSL before the tag's entry, and that the commit is signed by a key
+// trusted for the ref it was first recorded on by the policy in place at that
+// time. If no rules protect the ref, any key in that policy is trusted, as is
+// the case for tags. This ensures a tag cannot be used to vouch for a commit
+// that was never verified. Tags that point to other types of objects, such as
+// trees or other tags, are rejected as they cannot be attributed to a verified
+// commit.
+func verifyTaggedCommit(ctx context.Context, repo *git.Repository, tagEntry *rsl.ReferenceEntry, tagObj *object.Tag) error {
+	if tagObj.TargetType != plumbing.CommitObject {
+		return fmt.Errorf("%w: tag points to %s '%s'", ErrTagTargetNotCommit, tagObj.TargetType.String(), tagObj.Target.String())
+	}
+
+	commit, err := repo.CommitObject(tagObj.Target)
+	if err != nil {
+		return err
+	}
+
+	recordingEntry, err := getFirstReferenceEntryForCommitBefore(repo, commit, tagEntry)
+	if err != nil {
+		return err
+	}
+	if recordingEntry == nil {
+		return fmt.Errorf("%w: '%s'", ErrTagTargetNotRecorded, commit.Hash.String())
+	}
+
+	commitPolicyEntry, _, err := rsl.GetLatestReferenceEntryForRefBefore(repo, PolicyRef, recordingEntry.ID)
+	if err != nil {
+		return err
+	}
+	commitPolicy, err := LoadStateForEntry(ctx, repo, commitPolicyEntry)
+	if err != nil {
+		return err
+	}
+	recordingTime, err := getEntryTime(repo, recordingEntry)
+	if err != nil {
+		return err
+	}
+	commitPolicy = commitPolicy.ForEvaluation(WithEvaluationTime(recordingTime))
+
+	if len(commit.PGPSignature) == 0 {
+		return fmt.Errorf("%w: '%s' is not signed", ErrTagTargetNotVerified, commit.Hash.String())
+	}
+
+	trustedKeys, err := commitPolicy.FindPublicKeysForPath(ctx, fmt.Sprintf("git:%s", recordingEntry.RefName))
+	if err != nil {
+		return err
+	}
+	if len(trustedKeys) == 0 {
+		allKeys, err := commitPolicy.PublicKeys()
+		if err != nil {
+			return err
+		}
+		for _, key := range allKeys {
+			trustedKeys = append(trustedKeys, key)
+		}
+	}
+
+	verified, err := isCommitSignedByAnyKey(ctx, commitPolicy, commit, trustedKeys)
+	if err != nil {
+		return err
+	}
+	if !verified {
+		return fmt.Errorf("%w: '%s' was recorded on '%s'", ErrTagTargetNotVerified, commit.Hash.String(), recordingEntry.RefName)
+	}
+
+	return nil
+}
+
+// getFirstReferenceEntryForCommitBefore returns the first reference entry
+// recorded before the anchor entry that points a ref to the commit or one of
+// its descendants. Entries for gittuf namespaces, entries for deleted refs, and
+// entries that don't point to commits are not considered. If no such entry
+// exists, nil is returned.
+func getFirstReferenceEntryForCommitBefore(repo *git.Repository, commit *object.Commit, anchor *rsl.ReferenceEntry) (*rsl.ReferenceEntry, error) {
+	entries, err := rsl.GetEntriesSince(repo, plumbing.ZeroHash, anchor.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		if entry.GetID() == anchor.ID {
+			break
+		}
+
+		referenceEntry, isReferenceEntry := entry.(*rsl.ReferenceEntry)
+		if !isReferenceEntry || referenceEntry.IsDeletion() || strings.HasPrefix(referenceEntry.RefName, rsl.GittufNamespacePrefix) {
+			continue
+		}
+
+		targetObj, err := repo.Storer.EncodedObject(plumbing.AnyObject, referenceEntry.TargetID)
+		if err != nil {
+			return nil, err
+		}
+		if targetObj.Type() != plumbing.CommitObject {
+			continue
+		}
+
+		knowsCommit, err := gitinterface.KnowsCommit(repo, referenceEntry.TargetID, commit)
+		if err != nil {
+			return nil, err
+		}
+		if knowsCommit {
+			return referenceEntry, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// verifyAllowedHashes checks that the contents of the specified paths in the
+// commit's tree are allowed by every rule that matches the path on the ref and
+// pins its contents. Paths that don't exist in the commit's tree, i.e., files deleted by
+// the commit, are not checked. If a path's contents are not allowed, a
+// *CommitVerificationError wrapping ErrUnapprovedFileContents is returned.
+func verifyAllowedHashes(ctx context.Context, repo *git.Repository, policy *State, commit *object.Commit, refName string, paths []string) error {
+	for _, path := range paths {
+		namespace := fmt.Sprintf("file:%s", path) // FIXME: "file:" shouldn't be here
+
+		delegations, _, err := policy.FindDelegationsForPathOnRef(ctx, namespace, refName)
+		if err != nil {
+			return err
+		}
+
+		pinningRules := []tuf.Delegation{}
+		for _, delegation := range delegations {
+			if len(delegation.AllowedHashes) > 0 {
+				pinningRules = append(pinningRules, delegation)
+			}
+		}
+		if len(pinningRules) == 0 {
+			continue
+		}
+
+		blobID, err := gitinterface.GetPathIDInTree(repo, commit.TreeHash, path)
+		if err != nil {
+			if errors.Is(err, gitinterface.ErrTreeDoesNotHavePath) {
+				continue
+			}
+			return err
+		}
+
+		for _, rule := range pinningRules {
+			allowed := false
+			for _, hash := range rule.AllowedHashes {
+				if hash == blobID.String() {
+					allowed = true
+					break
+				}
+			}
+
+			if !allowed {
+				return &CommitVerificationError{
+					CommitID:  commit.Hash,
+					Namespace: namespace,
+					RuleNames: []string{rule.Name},
+					Err:       fmt.Errorf("%w: '%s'", ErrUnapprovedFileContents, blobID.String()),
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// verifyCommitThresholds checks that every commit recorded by the entry is
+// signed by the threshold of keys authorized by each rule that protects the
+// entry's ref. Signatures are counted using the commit's signature and its
+// co-signatures, see verifyCommitThreshold. Rules with a threshold of 1
+// are satisfied by the RSL entry's signature and are not checked here. If a
+// commit is not signed by enough keys, a *CommitVerificationError wrapping
+// ErrThresholdNotMet is returned.
+func verifyCommitThresholds(ctx context.Context, repo *git.Repository, policy *State, entry *rsl.ReferenceEntry) error {
+	namespace := fmt.Sprintf("git:%s", entry.RefName) // FIXME: "git:" shouldn't be here
+	delegations, keys, err := policy.FindDelegationsForPath(ctx, namespace)
+	if err != nil {
+		return err
+	}
+
+	thresholdRules := []tuf.Delegation{}
+	for _, delegation := range delegations {
+		if delegation.Threshold > 1 {
+			thresholdRules = append(thresholdRules, delegation)
+		}
+	}
+	if len(thresholdRules) == 0 {
+		return nil
+	}
+
+	commits, err := getCommits(repo, entry)
+	if err != nil {
+		return err
+	}
+
+	for _, delegation := range thresholdRules {
+		authorizedKeys := []*tuf.Key{}
+		for _, keyID := range delegation.KeyIDs {
+			if key, has := keys[keyID]; has {
+				authorizedKeys = append(authorizedKeys, key)
+			}
+		}
+
+		for _, commit := range commits {
+			err := verifyCommitThreshold(ctx, policy, commit, authorizedKeys, delegation.Threshold)
+			logging.FromContext(ctx).DebugContext(ctx, logging.EventThresholdProgress, "rule", delegation.Name, "commit", commit.Hash.String(), "threshold", delegation.Threshold, "met", err == nil)
+			if err != nil {
+				if !errors.Is(err, gitinterface.ErrSignatureThresholdNotMet) {
+					return err
+				}
+
+				return &CommitVerificationError{
+					CommitID:  commit.Hash,
+					Namespace: namespace,
+					RuleNames: []string{delegation.Name},
+					Err:       errors.Join(ErrThresholdNotMet, err),
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// verifyCommitThreshold checks that the commit is signed by threshold of the
+// keys, using the commit's signature and its co-signatures, see
+// gitinterface.VerifyCommitSignatureWithKeys. Keys are only counted if the
+// policy's evaluation time is within their validity windows.
+func verifyCommitThreshold(ctx context.Context, policy *State, commit *object.Commit, keys []*tuf.Key, threshold int) error {
+	validKeys := []*tuf.Key{}
+	for _, key := range keys {
+		notBefore, notAfter, err := policy.keyValidityWindow(key.KeyID)
+		if err != nil {
+			return err
+		}
+		if !notBefore.IsZero() && policy.now().Before(notBefore) {
+			continue
+		}
+		if !notAfter.IsZero() && policy.now().After(notAfter) {
+			continue
+		}
+		validKeys = append(validKeys, key)
+	}
+
+	return gitinterface.VerifyCommitSignatureWithKeys(ctx, commit, validKeys, threshold, gitinterface.WithTrustedTime(policy.now()))
+}
+
+// verifyDistinctSigners checks the distinct signers requirement of every rule
+// that protects the entry's ref. The ref's history is walked from the entry's
+// target along first parents, and each commit is attributed to the rule's
+// authorized key that verifies its signature, if any. If the history is shorter
+// than the rule's window, the requirement is capped at the number of commits
+// walked. If too few distinct keys signed the commits, a
+// *CommitVerificationError wrapping ErrTooFewDistinctSigners is returned.
+func verifyDistinctSigners(ctx context.Context, repo *git.Repository, policy *State, entry *rsl.ReferenceEntry) error {
+	if entry.TargetID.IsZero() {
+		return nil
+	}
+
+	namespace := fmt.Sprintf("git:%s", entry.RefName) // FIXME: "git:" shouldn't be here
+	delegations, keys, err := policy.FindDelegationsForPath(ctx, namespace)
+	if err != nil {
+		return err
+	}
+
+	for _, delegation := range delegations {
+		req := delegation.MinDistinctSigners
+		if req == nil {
+			continue
+		}
+
+		signers := map[string]bool{}
+		commitsWalked := 0
+		commitID := entry.TargetID
+		for commitsWalked < req.Window {
+			commit, err := repo.CommitObject(commitID)
+			if err != nil {
+				return err
+			}
+			commitsWalked++
+
+			for _, keyID := range delegation.KeyIDs {
+				key, has := keys[keyID]
+				if !has {
+					continue
+				}
+
+				err := verifyCommitSignature(ctx, policy, commit, key)
+				if err == nil {
+					signers[keyID] = true
+					break
+				}
+				if errors.Is(err, gitinterface.ErrUnknownSigningMethod) {
+					continue
+				}
+				if !errors.Is(err, gitinterface.ErrIncorrectVerificationKey) {
+					return err
+				}
+			}
+
+			if len(commit.ParentHashes) == 0 {
+				break
+			}
+			commitID = commit.ParentHashes[0]
+		}
+
+		required := req.Count
+		if commitsWalked < required {
+			required = commitsWalked
+		}
+
+		if len(signers) < required {
+			return &CommitVerificationError{
+				CommitID:  entry.TargetID,
+				Namespace: namespace,
+				RuleNames: []string{delegation.Name},
+				Err:       fmt.Errorf("%w: found %d, require %d in last %d commits", ErrTooFewDistinctSigners, len(signers), required, commitsWalked),
+			}
+		}
+	}
+
+	return nil
+}
+
+// verifyMergeCommits checks that the merge commits introduced by the entry only
+// combine the changes made by their parents, if any rule protecting the entry's
+// ref requires it. The changes made by a merge commit itself are not otherwise
+// verified, as file path rules are only checked for the paths changed by
+// regular commits. If a merge commit introduces other changes, a
+// *CommitVerificationError wrapping ErrUnattributedMergeChanges is returned.
+// Paths in which a merge commit resolves conflicts between its parents are
+// verified against the merge commit's signature, see verifyMergeResolutions.
+func verifyMergeCommits(ctx context.Context, repo *git.Repository, policy *State, entry *rsl.ReferenceEntry) error {
+	namespace := fmt.Sprintf("git:%s", entry.RefName) // FIXME: "git:" shouldn't be here
+	delegations, _, err := policy.FindDelegationsForPath(ctx, namespace)
 	if err != nil {
 		return err
 	}
 
-	// No trusted keys => allow any key's signature for the git namespace
-	if len(trustedKeys) == 0 {
-		gitNamespaceVerified = true
+	ruleNames := []string{}
+	for _, delegation := range delegations {
+		if delegation.VerifyMergeCommits {
+			ruleNames = append(ruleNames, delegation.Name)
+		}
+	}
+	if len(ruleNames) == 0 {
+		return nil
 	}
 
-	// 2. Find commit object for the RSL entry
-	commitObj, err := repo.CommitObject(entry.ID)
+	commits, err := getCommits(repo, entry)
 	if err != nil {
 		return err
 	}
 
-	// 3. Use each trusted key to verify signature
-	for _, key := range trustedKeys {
-		err := gitinterface.VerifyCommitSignature(ctx, commitObj, key)
-		if err == nil {
-			// Signature verification succeeded
-			gitNamespaceVerified = true
-			break
+	for _, commit := range commits {
+		if len(commit.ParentHashes) < 2 {
+			continue
 		}
-		if !errors.Is(err, gitinterface.ErrIncorrectVerificationKey) {
-			// Unexpected error
+
+		paths, err := gitinterface.GetFilePathsChangedByMergeCommit(repo, commit)
+		if err != nil {
 			return err
 		}
-		// Haven't found a valid key, continue with next key
-	}
 
-	if !gitNamespaceVerified {
-		return fmt.Errorf("verifying Git namespace policies failed, %w", ErrUnauthorizedSignature)
+		if len(paths) > 0 {
+			return &CommitVerificationError{
+				CommitID:  commit.Hash,
+				Namespace: namespace,
+				RuleNames: ruleNames,
+				Err:       fmt.Errorf("%w: %s", ErrUnattributedMergeChanges, strings.Join(paths, ", ")),
+			}
+		}
+
+		if err := verifyMergeResolutions(ctx, repo, policy, commit, entry.RefName); err != nil {
+			return err
+		}
 	}
 
-	// 4. Verify modified files
+	return nil
+}
 
-	// First, get all commits between the current and last entry for the ref.
-	commits, err := getCommits(repo, entry) // note: this is ordered by commit ID
+// verifyMergeResolutions checks that the merge commit is trusted to change the
+// paths in which it resolves conflicts between its parents, as the contents of
+// these paths are chosen by the merge commit's signer rather than either
+// parent. Each such path that is protected by file path rules must be signed by
+// the threshold of keys of one of the rules, and its contents must be allowed
+// by the rules that pin them. If the merge commit is not trusted for a path, a
+// *CommitVerificationError wrapping ErrUnauthorizedSignature is returned.
+func verifyMergeResolutions(ctx context.Context, repo *git.Repository, policy *State, commit *object.Commit, refName string) error {
+	paths, err := gitinterface.GetFilePathsResolvedByMergeCommit(repo, commit)
 	if err != nil {
 		return err
 	}
 
-	commitsVerified := make([]bool, len(commits))
-	for i, commit := range commits {
-		// Assume the commit's paths are verified, if a path is left unverified,
-		// we flip this later.
-		commitsVerified[i] = true
-
-		paths, err := gitinterface.GetFilePathsChangedByCommit(repo, commit)
-		if err != nil {
-			return err
-		}
-
-		// TODO: evaluate if this can be done once for the earliest commit in
-		// the set being verified if we had them ordered.
-		var commitPolicy *State
-		commitPolicy, err = GetStateForCommit(ctx, repo, commit)
+	for _, path := range paths {
+		namespace := fmt.Sprintf("file:%s", path) // FIXME: "file:" shouldn't be here
+		delegations, keys, err := policy.FindDelegationsForPathOnRef(ctx, namespace, refName)
 		if err != nil {
 			return err
 		}
-		if commitPolicy == nil {
-			// the commit hasn't been seen in any refs in the repository, use
-			// specified policy
-			commitPolicy = policy
+		if len(delegations) == 0 {
+			continue
 		}
 
-		pathsVerified := make([]bool, len(paths))
-		verifiedKeyID := "" // this will be set after one successful verification of the commit to avoid repeated signature verification
-		for j, path := range paths {
-			trustedKeys, err := commitPolicy.FindPublicKeysForPath(ctx, fmt.Sprintf("file:%s", path)) // FIXME: "file:" shouldn't be here
+		verified := false
+		ruleNames := make([]string, 0, len(delegations))
+		for _, delegation := range delegations {
+			ruleNames = append(ruleNames, delegation.Name)
+			if verified {
+				continue
+			}
+
+			verified, err = isCommitAuthorizedByRule(ctx, policy, commit, delegation, keys)
 			if err != nil {
 				return err
 			}
+		}
 
-			if len(trustedKeys) == 0 {
-				pathsVerified[j] = true
-				continue
+		if !verified {
+			return &CommitVerificationError{
+				CommitID:  commit.Hash,
+				Namespace: namespace,
+				RuleNames: ruleNames,
+				Err:       fmt.Errorf("%w: merge commit resolves conflicts in '%s'", ErrUnauthorizedSignature, path),
 			}
+		}
+	}
 
-			if len(verifiedKeyID) > 0 {
-				// We've already verified and identified commit signature's key
-				// ID, we can just check if that key ID is trusted for the new
-				// path.
-				// If not found, we don't make any assumptions about it being a
-				// failure in case of key ID mismatches. So, the signature check
-				// proceeds as usual.
-				for _, key := range trustedKeys {
-					if key.KeyID == verifiedKeyID {
-						pathsVerified[j] = true
-						break
-					}
-				}
-			}
+	return verifyAllowedHashes(ctx, repo, policy, commit, refName, paths)
+}
 
-			if pathsVerified[j] {
-				continue
+// verifySignedCommits checks that every commit introduced by the entry is
+// signed by one of the authorized keys of each rule that protects the entry's
+// ref and requires signed commits. Verifying only the RSL entry or the ref's tip
+// would allow unsigned commits to be introduced in the same push. If a commit
+// is not signed by an authorized key, a *CommitVerificationError wrapping
+// ErrUnsignedCommit is returned.
+func verifySignedCommits(ctx context.Context, repo *git.Repository, policy *State, entry *rsl.ReferenceEntry) error {
+	namespace := fmt.Sprintf("git:%s", entry.RefName) // FIXME: "git:" shouldn't be here
+	delegations, keys, err := policy.FindDelegationsForPath(ctx, namespace)
+	if err != nil {
+		return err
+	}
+
+	requiringDelegations := []tuf.Delegation{}
+	for _, delegation := range delegations {
+		if delegation.RequireSignedCommits {
+			requiringDelegations = append(requiringDelegations, delegation)
+		}
+	}
+	if len(requiringDelegations) == 0 {
+		return nil
+	}
+
+	introduced, err := rsl.CommitsIntroducedByEntry(repo, entry)
+	if err != nil {
+		return err
+	}
+
+	for _, delegation := range requiringDelegations {
+		trustedKeys := []*tuf.Key{}
+		for _, keyID := range delegation.KeyIDs {
+			if key, has := keys[keyID]; has {
+				trustedKeys = append(trustedKeys, key)
 			}
+		}
 
-			for _, key := range trustedKeys {
-				err := gitinterface.VerifyCommitSignature(ctx, commit, key)
-				if err == nil {
-					// Signature verification succeeded
-					pathsVerified[j] = true
-					verifiedKeyID = key.KeyID
-					break
-				}
-				if !errors.Is(err, gitinterface.ErrIncorrectVerificationKey) {
-					// Unexpected error
-					return err
+		for _, commit := range introduced.Commits {
+			verified, err := isCommitSignedByAnyKey(ctx, policy, commit, trustedKeys)
+			if err != nil {
+				return err
+			}
+			if !verified {
+				return &CommitVerificationError{
+					CommitID:  commit.Hash,
+					Namespace: namespace,
+					RuleNames: []string{delegation.Name},
+					Err:       ErrUnsignedCommit,
 				}
-				// Haven't found a valid key, continue with next key
 			}
 		}
+	}
 
-		for _, p := range pathsVerified {
-			if !p {
-				// Flip earlier assumption that commit paths are verified as we
-				// find that at least one path wasn't verified successfully
-				commitsVerified[i] = false
-				break
-			}
+	return nil
+}
+
+// verifyRefMatchesEntry checks that the ref recorded in the RSL entry points to
+// the entry's target, or to a commit that descends from it, in the repository.
+// A descendant is accepted as the ref may have been updated locally with
+// commits that haven't been recorded yet, but the recorded target is still part
+// of its history. This detects cases where the ref and the RSL are out of sync,
+// such as when a ref is rewritten without a corresponding RSL entry. If the ref
+// does not exist in the repository, there is nothing to compare and the check
+// is skipped.
+func verifyRefMatchesEntry(repo *git.Repository, entry *rsl.ReferenceEntry) error {
+	tip, err := gitinterface.GetTip(repo, entry.RefName)
+	if err != nil {
+		if errors.Is(err, plumbing.ErrReferenceNotFound) {
+			return nil
 		}
+		return err
 	}
 
-	for _, c := range commitsVerified {
-		if !c {
-			// Set path namespace verified to false as at least one commit's
-			// paths weren't verified successfully
-			pathNamespaceVerified = false
-			break
+	if tip == entry.TargetID {
+		return nil
+	}
+
+	mismatchErr := fmt.Errorf("%w: '%s' points to '%s', RSL entry '%s' records '%s'", ErrRSLTargetMismatch, entry.RefName, tip.String(), entry.ID.String(), entry.TargetID.String())
+	if entry.TargetID.IsZero() {
+		return mismatchErr
+	}
+
+	// Only commits can have descendants, so other objects such as tags must
+	// match exactly
+	targetCommit, err := repo.CommitObject(entry.TargetID)
+	if err != nil {
+		if errors.Is(err, plumbing.ErrObjectNotFound) || errors.Is(err, object.ErrUnsupportedObject) {
+			return mismatchErr
+		}
+		return err
+	}
+	if _, err := repo.CommitObject(tip); err != nil {
+		if errors.Is(err, plumbing.ErrObjectNotFound) || errors.Is(err, object.ErrUnsupportedObject) {
+			return mismatchErr
 		}
+		return err
 	}
 
-	if !pathNamespaceVerified {
-		return fmt.Errorf("verifying file namespace policies failed, %w", ErrUnauthorizedSignature)
+	knows, err := gitinterface.KnowsCommit(repo, tip, targetCommit)
+	if err != nil {
+		return err
+	}
+	if !knows {
+		return mismatchErr
 	}
 
 	return nil
 }
 
-func verifyTagEntry(ctx context.Context, repo *git.Repository, policy *State, entry *rsl.ReferenceEntry) error {
-	// 1. Find authorized public keys for tag's RSL entry
-	trustedKeys, err := policy.FindPublicKeysForPath(ctx, fmt.Sprintf("git:%s", entry.RefName))
+// verifyRSLWriter checks that the RSL entry commit is signed by one of the keys
+// trusted for the RSL writer role. If the policy does not declare the role, RSL
+// entries may be signed by any key.
+func verifyRSLWriter(ctx context.Context, repo *git.Repository, policy *State, entryID plumbing.Hash) error {
+	rslWriterKeys, err := policy.FindRSLWriterKeys()
 	if err != nil {
 		return err
 	}
-
-	if len(trustedKeys) == 0 {
-		allKeys, err := policy.PublicKeys()
-		if err != nil {
-			return err
-		}
-
-		// FIXME: decide if we want to pass around map or slice for these APIs
-		for _, key := range allKeys {
-			trustedKeys = append(trustedKeys, key)
-		}
+	if len(rslWriterKeys) == 0 {
+		return nil
 	}
 
-	// 2. Find commit object for the RSL entry
-	commitObj, err := repo.CommitObject(entry.ID)
+	entryObj, err := repo.CommitObject(entryID)
 	if err != nil {
 		return err
 	}
 
-	// 3. Use each trusted key to verify signature
-	rslEntryVerified := false
-	for _, key := range trustedKeys {
-		err := gitinterface.VerifyCommitSignature(ctx, commitObj, key)
+	for _, key := range rslWriterKeys {
+		err := verifyCommitSignature(ctx, policy, entryObj, key)
 		if err == nil {
 			// Signature verification succeeded
-			rslEntryVerified = true
-			break
+			return nil
 		}
 		if errors.Is(err, gitinterface.ErrUnknownSigningMethod) {
 			continue
@@ -536,45 +2183,151 @@ func verifyTagEntry(ctx context.Context, repo *git.Repository, policy *State, en
 		// Haven't found a valid key, continue with next key
 	}
 
-	if !rslEntryVerified {
-		return fmt.Errorf("verifying RSL entry failed, %w", ErrUnauthorizedSignature)
+	return fmt.Errorf("%w: RSL entry '%s'", ErrUnauthorizedRSLWriter, entryID.String())
+}
+
+// verifyForcePushAuthorization checks if the entry records a non-fast-forward
+// update of a protected ref. Such an update must be authorized by a force push
+// annotation that refers to the entry and is signed by one of the ref's trusted
+// keys. Refs without trusted keys may be updated freely.
+func verifyForcePushAuthorization(ctx context.Context, repo *git.Repository, policy *State, trustedKeys []*tuf.Key, entry *rsl.ReferenceEntry, annotations []*rsl.AnnotationEntry) error {
+	if len(trustedKeys) == 0 {
+		return nil
 	}
 
-	// 4. Verify tag object
-	tagObjVerified := false
-	tagObj, err := repo.TagObject(entry.TargetID)
+	isForcePush, err := isNonFastForwardEntry(repo, entry)
 	if err != nil {
-		// Likely indicates the ref is not pointing to a tag object
-		// What about lightweight tags?
 		return err
 	}
-
-	if len(tagObj.PGPSignature) == 0 {
-		return fmt.Errorf(noSignatureMessage)
+	if !isForcePush {
+		return nil
 	}
 
-	for _, key := range trustedKeys {
-		err := gitinterface.VerifyTagSignature(ctx, tagObj, key)
-		if err == nil {
-			// Signature verification succeeded
-			tagObjVerified = true
-			break
-		}
-		if errors.Is(err, gitinterface.ErrUnknownSigningMethod) {
+	for _, annotation := range annotations {
+		if !annotation.ForcePush || !annotation.RefersTo(entry.ID) {
 			continue
 		}
-		if !errors.Is(err, gitinterface.ErrIncorrectVerificationKey) {
-			// Unexpected error
+
+		annotationObj, err := repo.CommitObject(annotation.ID)
+		if err != nil {
 			return err
 		}
-		// Haven't found a valid key, continue with next key
+
+		for _, key := range trustedKeys {
+			err := verifyCommitSignature(ctx, policy, annotationObj, key)
+			if err == nil {
+				// Signature verification succeeded
+				return nil
+			}
+			if errors.Is(err, gitinterface.ErrUnknownSigningMethod) {
+				continue
+			}
+			if !errors.Is(err, gitinterface.ErrIncorrectVerificationKey) {
+				// Unexpected error
+				return err
+			}
+			// Haven't found a valid key, continue with next key
+		}
 	}
 
-	if !tagObjVerified {
-		return fmt.Errorf("verifying tag object's signature failed, %w", ErrUnauthorizedSignature)
+	return fmt.Errorf("%w: RSL entry '%s' for '%s'", ErrUnauthorizedForcePush, entry.ID.String(), entry.RefName)
+}
+
+// verifyBreakGlassAuthorization checks if the entry is authorized by break-glass
+// annotations that refer to it and are signed by a threshold of the keys of the
+// root of trust's emergency role. Each key is counted once, even if it signs
+// more than one annotation. If the threshold is met, the IDs of the
+// annotations that contributed to it are returned. If the emergency role is not
+// declared or the threshold is not met, no IDs are returned.
+func verifyBreakGlassAuthorization(ctx context.Context, repo *git.Repository, policy *State, entry *rsl.ReferenceEntry, annotations []*rsl.AnnotationEntry) ([]plumbing.Hash, error) {
+	emergencyKeys, threshold, err := policy.FindEmergencyKeys()
+	if err != nil {
+		return nil, err
+	}
+	if len(emergencyKeys) == 0 || threshold < 1 {
+		return nil, nil
 	}
 
-	return nil
+	signers := map[string]bool{}
+	annotationIDs := []plumbing.Hash{}
+	for _, annotation := range annotations {
+		if !annotation.BreakGlass || !annotation.RefersTo(entry.ID) {
+			continue
+		}
+
+		annotationObj, err := repo.CommitObject(annotation.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, key := range emergencyKeys {
+			if signers[key.KeyID] {
+				continue
+			}
+
+			err := verifyCommitSignature(ctx, policy, annotationObj, key)
+			if err == nil {
+				// Signature verification succeeded
+				signers[key.KeyID] = true
+				annotationIDs = append(annotationIDs, annotation.ID)
+				break
+			}
+			if errors.Is(err, gitinterface.ErrUnknownSigningMethod) {
+				continue
+			}
+			if !errors.Is(err, gitinterface.ErrIncorrectVerificationKey) {
+				// Unexpected error
+				return nil, err
+			}
+			// Haven't found a valid key, continue with next key
+		}
+	}
+
+	logging.FromContext(ctx).DebugContext(ctx, logging.EventThresholdProgress, "rule", EmergencyRoleName, "entry", entry.ID.String(), "signers", len(signers), "threshold", threshold)
+
+	if len(signers) < threshold {
+		return nil, nil
+	}
+
+	return annotationIDs, nil
+}
+
+// isNonFastForwardEntry indicates if the entry's target does not descend from
+// the target of the prior RSL entry for the same ref. The creation and deletion
+// of a ref are not considered to be non-fast-forward updates.
+func isNonFastForwardEntry(repo *git.Repository, entry *rsl.ReferenceEntry) (bool, error) {
+	if entry.TargetID.IsZero() {
+		return false, nil
+	}
+
+	priorRefEntry, _, err := rsl.GetLatestReferenceEntryForRefBefore(repo, entry.RefName, entry.ID)
+	if err != nil {
+		if errors.Is(err, rsl.ErrRSLEntryNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	if priorRefEntry.TargetID.IsZero() {
+		return false, nil
+	}
+
+	priorCommit, err := repo.CommitObject(priorRefEntry.TargetID)
+	if err != nil {
+		if errors.Is(err, plumbing.ErrObjectNotFound) {
+			// The prior target was rewritten away, so we can't establish the
+			// update was a fast-forward
+			return true, nil
+		}
+		return false, err
+	}
+
+	knows, err := gitinterface.KnowsCommit(repo, entry.TargetID, priorCommit)
+	if err != nil {
+		return false, err
+	}
+
+	return !knows, nil
 }
 
 // getCommits identifies the commits introduced to the entry's ref since the
@@ -3,20 +3,27 @@
 package policy
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"sort"
 	"testing"
+	"time"
 
 	"github.com/gittuf/gittuf/internal/common"
 	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/logging"
 	"github.com/gittuf/gittuf/internal/rsl"
 	"github.com/gittuf/gittuf/internal/signerverifier"
 	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
 	"github.com/gittuf/gittuf/internal/signerverifier/gpg"
+	"github.com/gittuf/gittuf/internal/third_party/go-git"
 	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing/filemode"
 	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing/object"
 	"github.com/gittuf/gittuf/internal/tuf"
 	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
@@ -27,71 +34,898 @@ import (
 // broadly, we need to rework the test setup here starting with
 // createTestRepository and the state creation helpers.
 
-const gpgKeyName = "gpg-privkey.asc"
+const (
+	gpgKeyName          = "gpg-privkey.asc"
+	untrustedGPGKeyName = "gpg-privkey-2.asc"
+)
 
 func TestVerifyRef(t *testing.T) {
 	repo, _ := createTestRepository(t, createTestStateWithPolicy)
 	refName := "refs/heads/main"
 
-	if err := repo.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(refName), plumbing.ZeroHash)); err != nil {
+	if err := repo.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(refName), plumbing.ZeroHash)); err != nil {
+		t.Fatal(err)
+	}
+
+	commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyName)
+	entry := rsl.NewReferenceEntry(refName, commitIDs[0])
+	common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyName)
+
+	err := VerifyRef(context.Background(), repo, refName)
+	assert.Nil(t, err)
+
+	// Advance the ref without recording an RSL entry, the recorded target is
+	// still in the ref's history
+	common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyName)
+
+	err = VerifyRef(context.Background(), repo, refName)
+	assert.Nil(t, err)
+
+	// Rewrite the ref without recording an RSL entry
+	if err := repo.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(refName), createTestUnrelatedCommit(t, repo))); err != nil {
+		t.Fatal(err)
+	}
+
+	err = VerifyRef(context.Background(), repo, refName)
+	assert.ErrorIs(t, err, ErrRSLTargetMismatch)
+}
+
+func TestVerifyRefDeletion(t *testing.T) {
+	refName := "refs/heads/main"
+
+	tests := map[string]struct {
+		keyName     string
+		expectedErr error
+	}{
+		"deletion recorded by trusted key": {
+			keyName: gpgKeyName,
+		},
+		"deletion recorded by untrusted key": {
+			keyName:     untrustedGPGKeyName,
+			expectedErr: ErrUnauthorizedSignature,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			repo, _ := createTestRepository(t, createTestStateWithPolicy)
+
+			if err := repo.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(refName), plumbing.ZeroHash)); err != nil {
+				t.Fatal(err)
+			}
+
+			commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyName)
+			common.CreateTestRSLReferenceEntryCommit(t, repo, rsl.NewReferenceEntry(refName, commitIDs[0]), gpgKeyName)
+
+			// The protected branch is deleted
+			if err := repo.Storer.RemoveReference(plumbing.ReferenceName(refName)); err != nil {
+				t.Fatal(err)
+			}
+			common.CreateTestRSLReferenceEntryCommit(t, repo, rsl.NewDeletionEntry(refName), test.keyName)
+
+			err := VerifyRef(testCtx, repo, refName)
+			if test.expectedErr == nil {
+				assert.Nil(t, err)
+			} else {
+				assert.ErrorIs(t, err, test.expectedErr)
+			}
+		})
+	}
+
+	t.Run("ref exists despite recorded deletion", func(t *testing.T) {
+		repo, _ := createTestRepository(t, createTestStateWithPolicy)
+
+		if err := repo.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(refName), plumbing.ZeroHash)); err != nil {
+			t.Fatal(err)
+		}
+
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyName)
+		common.CreateTestRSLReferenceEntryCommit(t, repo, rsl.NewReferenceEntry(refName, commitIDs[0]), gpgKeyName)
+		common.CreateTestRSLReferenceEntryCommit(t, repo, rsl.NewDeletionEntry(refName), gpgKeyName)
+
+		err := VerifyRef(testCtx, repo, refName)
+		assert.ErrorIs(t, err, ErrRSLTargetMismatch)
+	})
+}
+
+func TestVerifyRefWithKeyValidityWindow(t *testing.T) {
+	refName := "refs/heads/main"
+	commitTime := time.Date(1995, time.October, 26, 9, 0, 0, 0, time.UTC)
+
+	tests := map[string]struct {
+		notBefore   time.Time
+		notAfter    time.Time
+		expectedErr error
+	}{
+		"commit created within window": {
+			notBefore: commitTime.Add(-time.Hour),
+			notAfter:  commitTime.Add(time.Hour),
+		},
+		"commit created before window": {
+			notBefore:   commitTime.Add(time.Hour),
+			expectedErr: ErrUnauthorizedSignature,
+		},
+		"commit created after window": {
+			notAfter:    commitTime.Add(-time.Hour),
+			expectedErr: ErrUnauthorizedSignature,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			repo, _ := createTestRepository(t, func(t testing.TB) *State {
+				return createTestStateWithKeyValidityWindow(t, test.notBefore, test.notAfter)
+			})
+
+			if err := repo.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(refName), plumbing.ZeroHash)); err != nil {
+				t.Fatal(err)
+			}
+
+			commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyName)
+			entry := rsl.NewReferenceEntry(refName, commitIDs[0])
+			common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyName)
+
+			err := VerifyRef(testCtx, repo, refName)
+			if test.expectedErr == nil {
+				assert.Nil(t, err)
+			} else {
+				assert.ErrorIs(t, err, test.expectedErr)
+			}
+		})
+	}
+}
+
+func TestVerifyCommitSignatureWithKeyValidityWindow(t *testing.T) {
+	refName := "refs/heads/main"
+	commitTime := time.Date(1995, time.October, 26, 9, 0, 0, 0, time.UTC)
+	notAfter := commitTime.Add(time.Hour)
+
+	repo, state := createTestRepository(t, func(t testing.TB) *State {
+		return createTestStateWithKeyValidityWindow(t, time.Time{}, notAfter)
+	})
+
+	commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyName)
+	commit, err := repo.CommitObject(commitIDs[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gpgKey, err := gpg.LoadGPGKeyFromBytes(gpgPubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The commit's own timestamp is within the window, but the commit was
+	// only recorded after the window ended
+	err = verifyCommitSignature(testCtx, state.ForEvaluation(WithEvaluationTime(notAfter.Add(time.Hour))), commit, gpgKey)
+	assert.ErrorIs(t, err, gitinterface.ErrOutsideKeyValidityWindow)
+
+	err = verifyCommitSignature(testCtx, state.ForEvaluation(WithEvaluationTime(commitTime)), commit, gpgKey)
+	assert.Nil(t, err)
+}
+
+func TestVerifyRefWithExpiringRule(t *testing.T) {
+	refName := "refs/heads/main"
+	entryTime := time.Date(1995, time.October, 26, 9, 0, 0, 0, time.UTC)
+
+	// The rule's expiry is evaluated as of when the entry was recorded,
+	// rather than when the verification is performed
+	tests := map[string]struct {
+		expires     time.Time
+		expectedErr error
+	}{
+		"rule not expired when entry was recorded": {
+			expires: entryTime.Add(time.Hour),
+		},
+		"rule expired when entry was recorded": {
+			expires:     entryTime.Add(-time.Hour),
+			expectedErr: ErrUnauthorizedSignature,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			repo, _ := createTestRepository(t, func(t testing.TB) *State {
+				return createTestStateWithExpiringRule(t, test.expires)
+			})
+
+			if err := repo.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(refName), plumbing.ZeroHash)); err != nil {
+				t.Fatal(err)
+			}
+
+			// The second GPG key is only trusted by the expiring rule
+			commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, untrustedGPGKeyName)
+			entry := rsl.NewReferenceEntry(refName, commitIDs[0])
+			common.CreateTestRSLReferenceEntryCommit(t, repo, entry, untrustedGPGKeyName)
+
+			err := VerifyRef(testCtx, repo, refName)
+			if test.expectedErr == nil {
+				assert.Nil(t, err)
+			} else {
+				assert.ErrorIs(t, err, test.expectedErr)
+			}
+		})
+	}
+}
+
+func TestVerifyRefEmitsEvents(t *testing.T) {
+	repo, _ := createTestRepository(t, createTestStateWithPolicy)
+	refName := "refs/heads/main"
+
+	if err := repo.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(refName), plumbing.ZeroHash)); err != nil {
+		t.Fatal(err)
+	}
+
+	commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyName)
+	entry := rsl.NewReferenceEntry(refName, commitIDs[0])
+	common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyName)
+
+	buffer := &bytes.Buffer{}
+	logger := slog.New(slog.NewJSONHandler(buffer, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	ctx := logging.NewContext(context.Background(), logger)
+
+	err := VerifyRef(ctx, repo, refName)
+	assert.Nil(t, err)
+
+	events := map[string][]map[string]any{}
+	decoder := json.NewDecoder(buffer)
+	for decoder.More() {
+		event := map[string]any{}
+		if err := decoder.Decode(&event); err != nil {
+			t.Fatal(err)
+		}
+		msg := event["msg"].(string)
+		events[msg] = append(events[msg], event)
+	}
+
+	assert.Len(t, events[logging.EventVerifyRef], 1)
+	assert.Equal(t, refName, events[logging.EventVerifyRef][0]["ref"])
+
+	assert.NotEmpty(t, events[logging.EventMetadataVerified])
+
+	visitedRules := []string{}
+	for _, event := range events[logging.EventDelegationVisited] {
+		if event["path"] == "git:"+refName && event["matched"] == true {
+			visitedRules = append(visitedRules, event["rule"].(string))
+		}
+	}
+	assert.Contains(t, visitedRules, "protect-main")
+
+	assert.NotEmpty(t, events[logging.EventKeyMatched])
+	assert.Equal(t, "git:"+refName, events[logging.EventKeyMatched][0]["namespace"])
+}
+
+func TestVerifyRefFull(t *testing.T) {
+	// FIXME: currently this test is identical to the one for VerifyRef.
+	// This is because it's not trivial to create a bunch of test policy / RSL
+	// states cleanly. We need something that is easy to maintain and add cases
+	// to.
+	repo, _ := createTestRepository(t, createTestStateWithPolicy)
+	refName := "refs/heads/main"
+
+	if err := repo.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(refName), plumbing.ZeroHash)); err != nil {
+		t.Fatal(err)
+	}
+
+	commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyName)
+	entry := rsl.NewReferenceEntry(refName, commitIDs[0])
+	common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyName)
+
+	err := VerifyRefFull(context.Background(), repo, refName)
+	assert.Nil(t, err)
+
+	// Rewrite the ref without recording an RSL entry
+	if err := repo.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(refName), createTestUnrelatedCommit(t, repo))); err != nil {
+		t.Fatal(err)
+	}
+
+	err = VerifyRefFull(context.Background(), repo, refName)
+	assert.ErrorIs(t, err, ErrRSLTargetMismatch)
+}
+
+func TestVerifyRefMatchesEntry(t *testing.T) {
+	repo, _ := createTestRepository(t, createTestStateWithPolicy)
+	refName := "refs/heads/main"
+
+	commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 2, gpgKeyName)
+
+	// The ref points to the recorded target
+	entry := rsl.NewReferenceEntry(refName, commitIDs[1])
+	err := verifyRefMatchesEntry(repo, entry)
+	assert.Nil(t, err)
+
+	// The ref descends from the recorded target
+	entry = rsl.NewReferenceEntry(refName, commitIDs[0])
+	err = verifyRefMatchesEntry(repo, entry)
+	assert.Nil(t, err)
+
+	// The recorded target is not in the ref's history
+	entry = rsl.NewReferenceEntry(refName, createTestUnrelatedCommit(t, repo))
+	err = verifyRefMatchesEntry(repo, entry)
+	assert.ErrorIs(t, err, ErrRSLTargetMismatch)
+
+	// The ref is behind the recorded target
+	if err := repo.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(refName), commitIDs[0])); err != nil {
+		t.Fatal(err)
+	}
+	entry = rsl.NewReferenceEntry(refName, commitIDs[1])
+	err = verifyRefMatchesEntry(repo, entry)
+	assert.ErrorIs(t, err, ErrRSLTargetMismatch)
+
+	// Refs not present locally are not checked
+	entry = rsl.NewReferenceEntry("refs/heads/feature", commitIDs[0])
+	err = verifyRefMatchesEntry(repo, entry)
+	assert.Nil(t, err)
+}
+
+func TestVerifyForcePushAuthorization(t *testing.T) {
+	repo, _ := createTestRepository(t, createTestStateWithPolicy)
+	refName := "refs/heads/main"
+
+	commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 2, gpgKeyName)
+	entry := rsl.NewReferenceEntry(refName, commitIDs[1])
+	common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyName)
+
+	err := VerifyRef(context.Background(), repo, refName)
+	assert.Nil(t, err)
+
+	// Rewind the ref, which is a non-fast-forward update
+	if err := repo.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(refName), commitIDs[0])); err != nil {
+		t.Fatal(err)
+	}
+	entry = rsl.NewReferenceEntry(refName, commitIDs[0])
+	entryID := common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyName)
+
+	err = VerifyRef(context.Background(), repo, refName)
+	assert.ErrorIs(t, err, ErrUnauthorizedForcePush)
+
+	// An annotation that doesn't authorize a force push doesn't help
+	annotation := rsl.NewAnnotationEntry([]plumbing.Hash{entryID}, false, "not a force push")
+	common.CreateTestRSLAnnotationEntryCommit(t, repo, annotation, gpgKeyName)
+
+	err = VerifyRef(context.Background(), repo, refName)
+	assert.ErrorIs(t, err, ErrUnauthorizedForcePush)
+
+	// Authorize the force push
+	annotation = rsl.NewForcePushAnnotationEntry([]plumbing.Hash{entryID}, "rewind main")
+	common.CreateTestRSLAnnotationEntryCommit(t, repo, annotation, gpgKeyName)
+
+	err = VerifyRef(context.Background(), repo, refName)
+	assert.Nil(t, err)
+
+	err = VerifyRefFull(context.Background(), repo, refName)
+	assert.Nil(t, err)
+
+	// Unprotected refs can be force pushed without an annotation
+	unprotectedRefName := "refs/heads/feature"
+	if err := repo.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(unprotectedRefName), commitIDs[1])); err != nil {
+		t.Fatal(err)
+	}
+	common.CreateTestRSLReferenceEntryCommit(t, repo, rsl.NewReferenceEntry(unprotectedRefName, commitIDs[1]), gpgKeyName)
+	if err := repo.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(unprotectedRefName), commitIDs[0])); err != nil {
+		t.Fatal(err)
+	}
+	common.CreateTestRSLReferenceEntryCommit(t, repo, rsl.NewReferenceEntry(unprotectedRefName, commitIDs[0]), gpgKeyName)
+
+	err = VerifyRef(context.Background(), repo, unprotectedRefName)
+	assert.Nil(t, err)
+}
+
+func TestVerifyRSLWriter(t *testing.T) {
+	repo, _ := createTestRepository(t, createTestStateWithPolicy)
+
+	// Declare the RSL writer role in a new policy state
+	state := createTestStateWithRSLWriter(t)
+	if err := state.Commit(testCtx, repo, "Add RSL writer", false); err != nil {
+		t.Fatal(err)
+	}
+
+	refName := "refs/heads/main"
+	unprotectedRefName := "refs/heads/feature"
+
+	t.Run("entry signed by RSL writer", func(t *testing.T) {
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyName)
+		common.CreateTestRSLReferenceEntryCommit(t, repo, rsl.NewReferenceEntry(refName, commitIDs[0]), gpgKeyName)
+
+		err := VerifyRef(testCtx, repo, refName)
+		assert.Nil(t, err)
+	})
+
+	t.Run("entry for unprotected ref signed by untrusted key", func(t *testing.T) {
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, unprotectedRefName, 1, gpgKeyName)
+		common.CreateTestRSLReferenceEntryCommit(t, repo, rsl.NewReferenceEntry(unprotectedRefName, commitIDs[0]), untrustedGPGKeyName)
+
+		err := VerifyRef(testCtx, repo, unprotectedRefName)
+		assert.ErrorIs(t, err, ErrUnauthorizedRSLWriter)
+	})
+
+	t.Run("unsigned entry", func(t *testing.T) {
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, unprotectedRefName, 1, gpgKeyName)
+		if err := rsl.NewReferenceEntry(unprotectedRefName, commitIDs[0]).Commit(repo, false); err != nil {
+			t.Fatal(err)
+		}
+
+		err := VerifyRef(testCtx, repo, unprotectedRefName)
+		assert.ErrorIs(t, err, ErrUnauthorizedRSLWriter)
+	})
+
+	t.Run("annotation signed by untrusted key", func(t *testing.T) {
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyName)
+		entryID := common.CreateTestRSLReferenceEntryCommit(t, repo, rsl.NewReferenceEntry(refName, commitIDs[0]), gpgKeyName)
+		common.CreateTestRSLAnnotationEntryCommit(t, repo, rsl.NewAnnotationEntry([]plumbing.Hash{entryID}, false, "injected"), untrustedGPGKeyName)
+
+		err := VerifyRef(testCtx, repo, refName)
+		assert.ErrorIs(t, err, ErrUnauthorizedRSLWriter)
+	})
+}
+
+func TestVerifyRSLWriterWithUnknownSigningMethod(t *testing.T) {
+	repo, state := createTestRepository(t, createTestStateWithRSLWriter)
+	refName := "refs/heads/main"
+
+	commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyName)
+	entryID := common.CreateTestRSLReferenceEntryCommit(t, repo, rsl.NewReferenceEntry(refName, commitIDs[0]), gpgKeyName)
+
+	// The RSL writer key that can't be used to verify Git signatures is
+	// skipped rather than failing verification
+	unknownKey := *state.RootPublicKeys[0]
+	unknownKey.KeyID = "unknown-key"
+	unknownKey.KeyType = "unregistered"
+	rootMetadata, err := state.GetRootMetadata()
+	if err != nil {
+		t.Fatal(err)
+	}
+	role := rootMetadata.Roles[RSLWriterRoleName]
+	role.KeyIDs = append([]string{unknownKey.KeyID}, role.KeyIDs...)
+	rootMetadata.Roles[RSLWriterRoleName] = role
+	rootMetadata.AddKey(&unknownKey)
+	setTestRootMetadata(t, state, rootMetadata)
+
+	err = verifyRSLWriter(testCtx, repo, state, entryID)
+	assert.Nil(t, err)
+
+	// Without any other RSL writer keys, the entry is unauthorized
+	role.KeyIDs = []string{unknownKey.KeyID}
+	rootMetadata.Roles[RSLWriterRoleName] = role
+	setTestRootMetadata(t, state, rootMetadata)
+
+	err = verifyRSLWriter(testCtx, repo, state, entryID)
+	assert.ErrorIs(t, err, ErrUnauthorizedRSLWriter)
+}
+
+func TestVerifyRefWithDenyRule(t *testing.T) {
+	repo, _ := createTestRepository(t, createTestStateWithDenyRule)
+	refName := "refs/heads/frozen"
+
+	commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyName)
+	common.CreateTestRSLReferenceEntryCommit(t, repo, rsl.NewReferenceEntry(refName, commitIDs[0]), gpgKeyName)
+
+	err := VerifyRef(testCtx, repo, refName)
+	assert.ErrorIs(t, err, ErrPathDenied)
+}
+
+func TestVerifyRefWithAllowedHashes(t *testing.T) {
+	repo, _ := createTestRepository(t, createTestStateWithAllowedHashes)
+	refName := "refs/heads/main"
+
+	// Files 1 and 2 are created with the allowed, empty contents
+	commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 2, gpgKeyName)
+	common.CreateTestRSLReferenceEntryCommit(t, repo, rsl.NewReferenceEntry(refName, commitIDs[1]), gpgKeyName)
+
+	err := VerifyRef(testCtx, repo, refName)
+	assert.Nil(t, err)
+
+	// Change file 1 to contents that are not allowed
+	unapprovedBlobID, err := gitinterface.WriteBlob(repo, []byte("unapproved contents"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	treeID, err := gitinterface.WriteTree(repo, []object.TreeEntry{
+		{Name: "1", Mode: filemode.Regular, Hash: unapprovedBlobID},
+		{Name: "2", Mode: filemode.Regular, Hash: gitinterface.EmptyBlob()},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	parentCommit, err := repo.CommitObject(commitIDs[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	commit := &object.Commit{
+		Author:       parentCommit.Author,
+		Committer:    parentCommit.Committer,
+		TreeHash:     treeID,
+		ParentHashes: []plumbing.Hash{commitIDs[1]},
+		Message:      "Change file 1",
+	}
+	commit = common.SignTestCommit(t, repo, commit, gpgKeyName)
+	ref, err := repo.Reference(plumbing.ReferenceName(refName), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	commitID, err := gitinterface.ApplyCommit(repo, commit, ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	common.CreateTestRSLReferenceEntryCommit(t, repo, rsl.NewReferenceEntry(refName, commitID), gpgKeyName)
+
+	err = VerifyRef(testCtx, repo, refName)
+	assert.ErrorIs(t, err, ErrUnapprovedFileContents)
+
+	var verificationErr *CommitVerificationError
+	if assert.ErrorAs(t, err, &verificationErr) {
+		assert.Equal(t, commitID, verificationErr.CommitID)
+		assert.Equal(t, "file:1", verificationErr.Namespace)
+		assert.Equal(t, []string{"protect-files-1-and-2"}, verificationErr.RuleNames)
+	}
+
+	commit, err = repo.CommitObject(commitID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = VerifyCommitObject(testCtx, repo, commit, refName)
+	assert.ErrorIs(t, err, ErrUnapprovedFileContents)
+}
+
+func TestVerifyRefWithMergeCommits(t *testing.T) {
+	refName := "refs/heads/main"
+
+	// createMerge adds a commit on a side branch of the ref, and then a merge
+	// commit with the specified tree to the ref. mergeTree maps file names to
+	// their contents.
+	createMerge := func(t *testing.T, repo *git.Repository, mergeTree map[string]string) plumbing.Hash {
+		t.Helper()
+
+		ref, err := repo.Reference(plumbing.ReferenceName(refName), true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		baseCommit, err := repo.CommitObject(ref.Hash())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// The side branch adds a new file
+		sideTreeID, err := gitinterface.WriteTree(repo, []object.TreeEntry{
+			{Name: "1", Mode: filemode.Regular, Hash: gitinterface.EmptyBlob()},
+			{Name: "side", Mode: filemode.Regular, Hash: gitinterface.EmptyBlob()},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		sideCommit := &object.Commit{
+			Author:       baseCommit.Author,
+			Committer:    baseCommit.Committer,
+			TreeHash:     sideTreeID,
+			ParentHashes: []plumbing.Hash{baseCommit.Hash},
+			Message:      "Add side file",
+		}
+		sideCommit = common.SignTestCommit(t, repo, sideCommit, gpgKeyName)
+		sideCommitID, err := gitinterface.WriteCommit(repo, sideCommit)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		mergeEntries := []object.TreeEntry{}
+		for _, name := range []string{"1", "side"} {
+			blobID, err := gitinterface.WriteBlob(repo, []byte(mergeTree[name]))
+			if err != nil {
+				t.Fatal(err)
+			}
+			mergeEntries = append(mergeEntries, object.TreeEntry{Name: name, Mode: filemode.Regular, Hash: blobID})
+		}
+		mergeTreeID, err := gitinterface.WriteTree(repo, mergeEntries)
+		if err != nil {
+			t.Fatal(err)
+		}
+		mergeCommit := &object.Commit{
+			Author:       baseCommit.Author,
+			Committer:    baseCommit.Committer,
+			TreeHash:     mergeTreeID,
+			ParentHashes: []plumbing.Hash{baseCommit.Hash, sideCommitID},
+			Message:      "Merge side branch",
+		}
+		mergeCommit = common.SignTestCommit(t, repo, mergeCommit, gpgKeyName)
+		mergeCommitID, err := gitinterface.ApplyCommit(repo, mergeCommit, ref)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		common.CreateTestRSLReferenceEntryCommit(t, repo, rsl.NewReferenceEntry(refName, mergeCommitID), gpgKeyName)
+		return mergeCommitID
+	}
+
+	t.Run("clean merge", func(t *testing.T) {
+		repo, _ := createTestRepository(t, createTestStateWithMergeCommitVerification)
+
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyName)
+		common.CreateTestRSLReferenceEntryCommit(t, repo, rsl.NewReferenceEntry(refName, commitIDs[0]), gpgKeyName)
+
+		createMerge(t, repo, map[string]string{"1": "", "side": ""})
+
+		err := VerifyRef(testCtx, repo, refName)
+		assert.Nil(t, err)
+	})
+
+	t.Run("sneaky merge", func(t *testing.T) {
+		repo, _ := createTestRepository(t, createTestStateWithMergeCommitVerification)
+
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyName)
+		common.CreateTestRSLReferenceEntryCommit(t, repo, rsl.NewReferenceEntry(refName, commitIDs[0]), gpgKeyName)
+
+		// The merge commit modifies file 1, which neither side changed
+		mergeCommitID := createMerge(t, repo, map[string]string{"1": "malicious", "side": ""})
+
+		err := VerifyRef(testCtx, repo, refName)
+		assert.ErrorIs(t, err, ErrUnattributedMergeChanges)
+
+		var verificationErr *CommitVerificationError
+		if assert.ErrorAs(t, err, &verificationErr) {
+			assert.Equal(t, mergeCommitID, verificationErr.CommitID)
+			assert.Equal(t, "git:"+refName, verificationErr.Namespace)
+			assert.Equal(t, []string{"protect-main"}, verificationErr.RuleNames)
+		}
+	})
+
+	t.Run("conflict resolution", func(t *testing.T) {
+		repo, _ := createTestRepository(t, createTestStateWithMergeCommitVerification)
+
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyName)
+		common.CreateTestRSLReferenceEntryCommit(t, repo, rsl.NewReferenceEntry(refName, commitIDs[0]), gpgKeyName)
+
+		// Both the ref and the side branch change file 1
+		mainID := createTestCommitWithTree(t, repo, []plumbing.Hash{commitIDs[0]}, map[string]string{"1": "main"}, gpgKeyName)
+		sideID := createTestCommitWithTree(t, repo, []plumbing.Hash{commitIDs[0]}, map[string]string{"1": "side"}, gpgKeyName)
+		if err := repo.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(refName), mainID)); err != nil {
+			t.Fatal(err)
+		}
+		common.CreateTestRSLReferenceEntryCommit(t, repo, rsl.NewReferenceEntry(refName, mainID), gpgKeyName)
+
+		// The merge commit resolving the conflict in file 1 is signed by a key
+		// that isn't trusted for file 1
+		mergeID := createTestCommitWithTree(t, repo, []plumbing.Hash{mainID, sideID}, map[string]string{"1": "malicious"}, untrustedGPGKeyName)
+		if err := repo.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(refName), mergeID)); err != nil {
+			t.Fatal(err)
+		}
+		common.CreateTestRSLReferenceEntryCommit(t, repo, rsl.NewReferenceEntry(refName, mergeID), gpgKeyName)
+
+		err := VerifyRef(testCtx, repo, refName)
+		assert.ErrorIs(t, err, ErrUnauthorizedSignature)
+
+		var verificationErr *CommitVerificationError
+		if assert.ErrorAs(t, err, &verificationErr) {
+			assert.Equal(t, mergeID, verificationErr.CommitID)
+			assert.Equal(t, "file:1", verificationErr.Namespace)
+			assert.Equal(t, []string{"protect-files-1-and-2"}, verificationErr.RuleNames)
+		}
+	})
+
+	t.Run("sneaky merge without merge commit verification", func(t *testing.T) {
+		repo, _ := createTestRepository(t, createTestStateWithPolicy)
+
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyName)
+		common.CreateTestRSLReferenceEntryCommit(t, repo, rsl.NewReferenceEntry(refName, commitIDs[0]), gpgKeyName)
+
+		createMerge(t, repo, map[string]string{"1": "malicious", "side": ""})
+
+		err := VerifyRef(testCtx, repo, refName)
+		assert.Nil(t, err)
+	})
+}
+
+// createTestCommitWithTree writes a commit with the specified parents and tree,
+// signed using the specified key. tree maps file names to their contents.
+func createTestCommitWithTree(t *testing.T, repo *git.Repository, parentIDs []plumbing.Hash, tree map[string]string, keyName string) plumbing.Hash {
+	t.Helper()
+
+	parentCommit, err := repo.CommitObject(parentIDs[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entries := []object.TreeEntry{}
+	for name, contents := range tree {
+		blobID, err := gitinterface.WriteBlob(repo, []byte(contents))
+		if err != nil {
+			t.Fatal(err)
+		}
+		entries = append(entries, object.TreeEntry{Name: name, Mode: filemode.Regular, Hash: blobID})
+	}
+	treeID, err := gitinterface.WriteTree(repo, entries)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	commit := &object.Commit{
+		Author:       parentCommit.Author,
+		Committer:    parentCommit.Committer,
+		TreeHash:     treeID,
+		ParentHashes: parentIDs,
+		Message:      "Test commit",
+	}
+	commit = common.SignTestCommit(t, repo, commit, keyName)
+	commitID, err := gitinterface.WriteCommit(repo, commit)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return commitID
+}
+
+// createTestUnrelatedCommit writes a root commit that shares no history with
+// the commits created by common.AddNTestCommitsToSpecifiedRef, as if a ref's
+// history was rewritten.
+func createTestUnrelatedCommit(t *testing.T, repo *git.Repository) plumbing.Hash {
+	t.Helper()
+
+	treeID, err := gitinterface.WriteTree(repo, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	commitID, err := gitinterface.WriteCommit(repo, &object.Commit{
+		TreeHash: treeID,
+		Message:  "Unrelated commit",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return commitID
+}
+
+func TestVerifyRelativeForRef(t *testing.T) {
+	// FIXME: currently this test is nearly identical to the one for VerifyRef.
+	// This is because it's not trivial to create a bunch of test policy / RSL
+	// states cleanly. We need something that is easy to maintain and add cases
+	// to.
+	repo, _ := createTestRepository(t, createTestStateWithPolicy)
+	refName := "refs/heads/main"
+
+	if err := repo.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(refName), plumbing.ZeroHash)); err != nil {
+		t.Fatal(err)
+	}
+
+	policyEntry, _, err := rsl.GetLatestReferenceEntryForRef(repo, PolicyRef)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyName)
+	entry := rsl.NewReferenceEntry(refName, commitIDs[0])
+	entryID := common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyName)
+	entry.ID = entryID
+
+	err = VerifyRelativeForRef(context.Background(), repo, policyEntry, policyEntry, entry, refName)
+	assert.Nil(t, err)
+
+	err = VerifyRelativeForRef(context.Background(), repo, policyEntry, entry, policyEntry, refName)
+	assert.ErrorIs(t, err, rsl.ErrRSLEntryNotFound)
+}
+
+func TestVerifyRefAtPolicy(t *testing.T) {
+	repo, state := createTestRepository(t, createTestStateWithPolicy)
+	refName := "refs/heads/main"
+
+	initialPolicyEntry, _, err := rsl.GetLatestReferenceEntryForRef(repo, PolicyRef)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The commit adds file 1
+	commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyName)
+	entry := rsl.NewReferenceEntry(refName, commitIDs[0])
+	entryID := common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyName)
+
+	// Update the policy to deny changes to file 1
+	targetsMetadata, err := state.GetTargetsMetadata(TargetsRoleName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	targetsMetadata, err = AddOrUpdateDenyRule(targetsMetadata, "deny-file-1", []string{"file:1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(rootKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	targetsEnv, err := dsse.CreateEnvelope(targetsMetadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	targetsEnv, err = dsse.SignEnvelope(testCtx, targetsEnv, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state.TargetsEnvelope = targetsEnv
+	if err := state.Commit(testCtx, repo, "Deny file 1", false); err != nil {
+		t.Fatal(err)
+	}
+
+	updatedPolicyEntry, _, err := rsl.GetLatestReferenceEntryForRef(repo, PolicyRef)
+	if err != nil {
 		t.Fatal(err)
 	}
 
-	commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyName)
-	entry := rsl.NewReferenceEntry(refName, commitIDs[0])
-	common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyName)
+	t.Run("verify using prior policy", func(t *testing.T) {
+		err := VerifyRefAtPolicy(testCtx, repo, refName, commitIDs[0], initialPolicyEntry.ID)
+		assert.Nil(t, err)
+	})
 
-	err := VerifyRef(context.Background(), repo, refName)
-	assert.Nil(t, err)
+	t.Run("verify using updated policy", func(t *testing.T) {
+		// The commit was recorded under the prior policy, so it's verified
+		// using that policy by default
+		err := VerifyRef(testCtx, repo, refName)
+		assert.Nil(t, err)
+
+		err = VerifyRefAtPolicy(testCtx, repo, refName, commitIDs[0], updatedPolicyEntry.ID)
+		assert.ErrorIs(t, err, ErrPathDenied)
+	})
+
+	t.Run("entry is not for policy", func(t *testing.T) {
+		err := VerifyRefAtPolicy(testCtx, repo, refName, commitIDs[0], entryID)
+		assert.ErrorIs(t, err, rsl.ErrRSLEntryDoesNotMatchRef)
+	})
+
+	t.Run("target is not recorded for ref", func(t *testing.T) {
+		err := VerifyRefAtPolicy(testCtx, repo, refName, gitinterface.EmptyTree(), initialPolicyEntry.ID)
+		assert.ErrorIs(t, err, rsl.ErrRSLEntryNotFound)
+	})
 }
 
-func TestVerifyRefFull(t *testing.T) {
-	// FIXME: currently this test is identical to the one for VerifyRef.
-	// This is because it's not trivial to create a bunch of test policy / RSL
-	// states cleanly. We need something that is easy to maintain and add cases
-	// to.
-	repo, _ := createTestRepository(t, createTestStateWithPolicy)
+func TestVerifyRefWithState(t *testing.T) {
+	repo, _ := createTestRepository(t, createTestStateWithOnlyRoot)
 	refName := "refs/heads/main"
 
-	if err := repo.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(refName), plumbing.ZeroHash)); err != nil {
-		t.Fatal(err)
-	}
+	trustedCommitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyName)
+	common.CreateTestRSLReferenceEntryCommit(t, repo, rsl.NewReferenceEntry(refName, trustedCommitIDs[0]), gpgKeyName)
 
-	commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyName)
-	entry := rsl.NewReferenceEntry(refName, commitIDs[0])
-	common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyName)
+	untrustedCommitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, untrustedGPGKeyName)
+	common.CreateTestRSLReferenceEntryCommit(t, repo, rsl.NewReferenceEntry(refName, untrustedCommitIDs[0]), untrustedGPGKeyName)
 
-	err := VerifyRefFull(context.Background(), repo, refName)
+	laterTrustedCommitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyName)
+	common.CreateTestRSLReferenceEntryCommit(t, repo, rsl.NewReferenceEntry(refName, laterTrustedCommitIDs[0]), gpgKeyName)
+
+	// The repository's policy has no rules, so the history verifies
+	err := VerifyRef(testCtx, repo, refName)
 	assert.Nil(t, err)
-}
 
-func TestVerifyRelativeForRef(t *testing.T) {
-	// FIXME: currently this test is nearly identical to the one for VerifyRef.
-	// This is because it's not trivial to create a bunch of test policy / RSL
-	// states cleanly. We need something that is easy to maintain and add cases
-	// to.
-	repo, _ := createTestRepository(t, createTestStateWithPolicy)
-	refName := "refs/heads/main"
+	// The candidate policy protects the ref using the trusted key
+	candidateState := createTestStateWithPolicy(t)
 
-	if err := repo.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(refName), plumbing.ZeroHash)); err != nil {
-		t.Fatal(err)
-	}
+	t.Run("history verifies using candidate policy", func(t *testing.T) {
+		err := VerifyRefWithState(testCtx, repo, refName, trustedCommitIDs[0], candidateState)
+		assert.Nil(t, err)
+	})
 
-	policyEntry, _, err := rsl.GetLatestReferenceEntryForRef(repo, PolicyRef)
-	if err != nil {
-		t.Fatal(err)
-	}
+	t.Run("history does not verify using candidate policy", func(t *testing.T) {
+		err := VerifyRefWithState(testCtx, repo, refName, untrustedCommitIDs[0], candidateState)
+		assert.ErrorIs(t, err, ErrUnauthorizedSignature)
+	})
 
-	commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyName)
-	entry := rsl.NewReferenceEntry(refName, commitIDs[0])
-	entryID := common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyName)
-	entry.ID = entryID
+	t.Run("earlier history does not verify using candidate policy", func(t *testing.T) {
+		// The target's entry is valid, but an earlier entry for the ref is not
+		err := VerifyRefWithState(testCtx, repo, refName, laterTrustedCommitIDs[0], candidateState)
+		assert.ErrorIs(t, err, ErrUnauthorizedSignature)
+	})
 
-	err = VerifyRelativeForRef(context.Background(), repo, policyEntry, policyEntry, entry, refName)
-	assert.Nil(t, err)
+	t.Run("candidate policy is not signed", func(t *testing.T) {
+		unsignedState := createTestStateWithPolicy(t)
+		unsignedState.RootEnvelope.Signatures = []sslibdsse.Signature{}
 
-	err = VerifyRelativeForRef(context.Background(), repo, policyEntry, entry, policyEntry, refName)
-	assert.ErrorIs(t, err, rsl.ErrRSLEntryNotFound)
+		err := VerifyRefWithState(testCtx, repo, refName, trustedCommitIDs[0], unsignedState)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("target is not recorded for ref", func(t *testing.T) {
+		err := VerifyRefWithState(testCtx, repo, refName, gitinterface.EmptyTree(), candidateState)
+		assert.ErrorIs(t, err, rsl.ErrRSLEntryNotFound)
+	})
+
+	// The repository's policy is unchanged
+	err = VerifyRef(testCtx, repo, refName)
+	assert.Nil(t, err)
 }
 
 func TestVerifyCommit(t *testing.T) {
@@ -152,6 +986,386 @@ func TestVerifyCommit(t *testing.T) {
 	assert.Equal(t, expectedStatus, status)
 }
 
+func TestVerifyCommitConcurrently(t *testing.T) {
+	// This test is most useful when run with the race detector enabled
+	repo, _ := createTestRepository(t, createTestStateWithPolicy)
+	refName := "refs/heads/main"
+	gpgKeyBytes, err := os.ReadFile(filepath.Join("test-data", "gpg-pubkey.asc"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	gpgKey, err := gpg.LoadGPGKeyFromBytes(gpgKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 20, gpgKeyName)
+	entry := rsl.NewReferenceEntry(refName, commitIDs[len(commitIDs)-1])
+	common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyName)
+
+	// Commits not recorded in the RSL are interleaved with the recorded ones
+	unrecordedCommitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 5, gpgKeyName)
+
+	expectedStatus := map[string]string{refName: unableToFindPolicyMessage}
+	ids := []string{refName}
+	for i, commitID := range commitIDs {
+		ids = append(ids, commitID.String())
+		expectedStatus[commitID.String()] = fmt.Sprintf(goodSignatureMessageFmt, gpgKey.KeyType, gpgKey.KeyID)
+
+		if i < len(unrecordedCommitIDs) {
+			ids = append(ids, unrecordedCommitIDs[i].String())
+			expectedStatus[unrecordedCommitIDs[i].String()] = unableToFindPolicyMessage
+		}
+	}
+
+	status := VerifyCommit(testCtx, repo, ids...)
+	assert.Equal(t, expectedStatus, status)
+}
+
+func TestVerifyCommitObject(t *testing.T) {
+	repo, _ := createTestRepository(t, createTestStateWithPolicy)
+	refName := "refs/heads/main"
+
+	commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 3, gpgKeyName)
+	commits := make([]*object.Commit, 0, len(commitIDs))
+	for _, commitID := range commitIDs {
+		commit, err := repo.CommitObject(commitID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		commits = append(commits, commit)
+	}
+
+	t.Run("protected file", func(t *testing.T) {
+		// The first commit adds file 1
+		rules, err := VerifyCommitObject(testCtx, repo, commits[0], "")
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"protect-files-1-and-2"}, rules)
+	})
+
+	t.Run("protected ref and file", func(t *testing.T) {
+		// The second commit adds file 2
+		rules, err := VerifyCommitObject(testCtx, repo, commits[1], refName)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"protect-main", "protect-files-1-and-2"}, rules)
+	})
+
+	t.Run("unprotected changes", func(t *testing.T) {
+		// The third commit adds file 3
+		rules, err := VerifyCommitObject(testCtx, repo, commits[2], "")
+		assert.ErrorIs(t, err, ErrCommitNotProtected)
+		assert.Nil(t, rules)
+	})
+
+	t.Run("unsigned commit for protected file", func(t *testing.T) {
+		unsignedCommit := &object.Commit{
+			TreeHash: commits[0].TreeHash,
+			Message:  "Unsigned commit",
+		}
+		unsignedCommitID, err := gitinterface.WriteCommit(repo, unsignedCommit)
+		if err != nil {
+			t.Fatal(err)
+		}
+		unsignedCommit, err = repo.CommitObject(unsignedCommitID)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rules, err := VerifyCommitObject(testCtx, repo, unsignedCommit, "")
+		assert.ErrorIs(t, err, ErrUnauthorizedSignature)
+		assert.Nil(t, rules)
+	})
+}
+
+func TestVerifyCommitRange(t *testing.T) {
+	repo, _ := createTestRepository(t, createTestStateWithPolicy)
+	refName := "refs/heads/main"
+
+	commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 3, gpgKeyName)
+
+	// The unsigned commit has only file 1, removing files 2 and 3
+	firstCommit, err := repo.CommitObject(commitIDs[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	unsignedCommit := &object.Commit{
+		TreeHash:     firstCommit.TreeHash,
+		ParentHashes: []plumbing.Hash{commitIDs[2]},
+		Message:      "Unsigned commit",
+	}
+	unsignedCommitID, err := gitinterface.WriteCommit(repo, unsignedCommit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(refName), unsignedCommitID)); err != nil {
+		t.Fatal(err)
+	}
+	tipCommitID := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyName)[0]
+
+	t.Run("all commits verified", func(t *testing.T) {
+		err := VerifyCommitRange(testCtx, repo, refName, plumbing.ZeroHash, commitIDs[2])
+		assert.Nil(t, err)
+	})
+
+	t.Run("unprotected changes", func(t *testing.T) {
+		// The third commit adds file 3 which is not protected
+		err := VerifyCommitRange(testCtx, repo, "refs/heads/feature", commitIDs[1], commitIDs[2])
+		assert.Nil(t, err)
+	})
+
+	t.Run("stop at unsigned commit for protected ref", func(t *testing.T) {
+		err := VerifyCommitRange(testCtx, repo, refName, commitIDs[2], tipCommitID)
+		assert.ErrorIs(t, err, ErrUnauthorizedSignature)
+
+		var verificationErr *CommitVerificationError
+		if assert.ErrorAs(t, err, &verificationErr) {
+			assert.Equal(t, unsignedCommitID, verificationErr.CommitID)
+			assert.Equal(t, "git:"+refName, verificationErr.Namespace)
+			assert.Equal(t, []string{"protect-main"}, verificationErr.RuleNames)
+		}
+	})
+
+	t.Run("stop at unsigned commit for protected file", func(t *testing.T) {
+		err := VerifyCommitRange(testCtx, repo, "refs/heads/feature", commitIDs[2], tipCommitID)
+		assert.ErrorIs(t, err, ErrUnauthorizedSignature)
+
+		var verificationErr *CommitVerificationError
+		if assert.ErrorAs(t, err, &verificationErr) {
+			assert.Equal(t, unsignedCommitID, verificationErr.CommitID)
+			assert.Equal(t, "file:2", verificationErr.Namespace)
+			assert.Equal(t, []string{"protect-files-1-and-2"}, verificationErr.RuleNames)
+		}
+	})
+}
+
+func BenchmarkVerifyCommitRange(b *testing.B) {
+	repo, _ := createTestRepository(b, createTestStateWithPolicy)
+	refName := "refs/heads/main"
+
+	numCommits := 500
+	commitIDs := common.AddNTestCommitsToSpecifiedRef(b, repo, refName, numCommits, gpgKeyName)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := VerifyCommitRange(testCtx, repo, refName, plumbing.ZeroHash, commitIDs[numCommits-1]); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestVerifyEntryCandidate(t *testing.T) {
+	refName := "refs/heads/main"
+
+	// createCandidate creates the commit object of an entry for the ref's
+	// current tip without adding it to the RSL
+	createCandidate := func(t *testing.T, repo *git.Repository, refName string, keyName string) *rsl.ReferenceEntry {
+		t.Helper()
+
+		tip, err := gitinterface.GetTip(repo, refName)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rslTip, err := gitinterface.GetTip(repo, rsl.Ref)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		entry := rsl.NewReferenceEntry(refName, tip)
+		if len(keyName) == 0 {
+			err = entry.CreateCommitObject(repo, false)
+		} else {
+			err = entry.CreateCommitObject(repo, true, gitinterface.WithSigner(common.NewTestGPGSigner(t, keyName)))
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		currentRSLTip, err := gitinterface.GetTip(repo, rsl.Ref)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, rslTip, currentRSLTip)
+
+		return entry
+	}
+
+	t.Run("entry signed by trusted key", func(t *testing.T) {
+		repo, _ := createTestRepository(t, createTestStateWithPolicy)
+		common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyName)
+
+		err := VerifyEntryCandidate(testCtx, repo, createCandidate(t, repo, refName, gpgKeyName))
+		assert.Nil(t, err)
+	})
+
+	t.Run("entry signed by untrusted key", func(t *testing.T) {
+		repo, _ := createTestRepository(t, createTestStateWithPolicy)
+		common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyName)
+
+		err := VerifyEntryCandidate(testCtx, repo, createCandidate(t, repo, refName, untrustedGPGKeyName))
+		assert.ErrorIs(t, err, ErrUnauthorizedSignature)
+	})
+
+	t.Run("unsigned entry for unprotected ref", func(t *testing.T) {
+		repo, _ := createTestRepository(t, createTestStateWithPolicy)
+		common.AddNTestCommitsToSpecifiedRef(t, repo, "refs/heads/feature", 1, gpgKeyName)
+
+		err := VerifyEntryCandidate(testCtx, repo, createCandidate(t, repo, "refs/heads/feature", ""))
+		assert.Nil(t, err)
+	})
+
+	t.Run("entry does not fast-forward protected ref", func(t *testing.T) {
+		repo, _ := createTestRepository(t, createTestStateWithPolicy)
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 2, gpgKeyName)
+		common.CreateTestRSLReferenceEntryCommit(t, repo, rsl.NewReferenceEntry(refName, commitIDs[1]), gpgKeyName)
+
+		if err := repo.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(refName), commitIDs[0])); err != nil {
+			t.Fatal(err)
+		}
+
+		err := VerifyEntryCandidate(testCtx, repo, createCandidate(t, repo, refName, gpgKeyName))
+		assert.ErrorIs(t, err, ErrUnauthorizedForcePush)
+	})
+}
+
+func TestVerifyRefDiff(t *testing.T) {
+	refName := "refs/heads/main"
+
+	addCommit := func(t *testing.T, repo *git.Repository, parentID plumbing.Hash, tree map[string]string, keyName string) plumbing.Hash {
+		t.Helper()
+
+		return createTestCommitWithTree(t, repo, []plumbing.Hash{parentID}, tree, keyName)
+	}
+
+	// createBase creates main with empty files 1 and 2 using the trusted key.
+	// The base tree is written with the same helper as the subtests' commits
+	// so unchanged entries are identical, including their file modes.
+	createBase := func(t *testing.T) (*git.Repository, plumbing.Hash) {
+		t.Helper()
+
+		repo, _ := createTestRepository(t, createTestStateWithPolicy)
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyName)
+		return repo, addCommit(t, repo, commitIDs[0], map[string]string{"1": "", "2": ""}, gpgKeyName)
+	}
+
+	t.Run("modified file signed by trusted key", func(t *testing.T) {
+		repo, baseID := createBase(t)
+		headID := addCommit(t, repo, baseID, map[string]string{"1": "modified", "2": ""}, gpgKeyName)
+
+		err := VerifyRefDiff(testCtx, repo, refName, baseID, headID)
+		assert.Nil(t, err)
+	})
+
+	t.Run("modified file signed by untrusted key", func(t *testing.T) {
+		repo, baseID := createBase(t)
+		headID := addCommit(t, repo, baseID, map[string]string{"1": "modified", "2": ""}, untrustedGPGKeyName)
+
+		err := VerifyRefDiff(testCtx, repo, refName, baseID, headID)
+		assert.ErrorIs(t, err, ErrUnauthorizedSignature)
+
+		var verificationErr *CommitVerificationError
+		if assert.ErrorAs(t, err, &verificationErr) {
+			assert.Equal(t, headID, verificationErr.CommitID)
+			assert.Equal(t, "file:1", verificationErr.Namespace)
+			assert.Equal(t, []string{"protect-files-1-and-2"}, verificationErr.RuleNames)
+		}
+	})
+
+	t.Run("deleted file signed by untrusted key", func(t *testing.T) {
+		repo, baseID := createBase(t)
+		headID := addCommit(t, repo, baseID, map[string]string{"1": ""}, untrustedGPGKeyName)
+
+		err := VerifyRefDiff(testCtx, repo, refName, baseID, headID)
+		assert.ErrorIs(t, err, ErrUnauthorizedSignature)
+
+		var verificationErr *CommitVerificationError
+		if assert.ErrorAs(t, err, &verificationErr) {
+			assert.Equal(t, "file:2", verificationErr.Namespace)
+		}
+	})
+
+	t.Run("added unprotected file signed by untrusted key", func(t *testing.T) {
+		repo, baseID := createBase(t)
+		headID := addCommit(t, repo, baseID, map[string]string{"1": "", "2": "", "3": "added"}, untrustedGPGKeyName)
+
+		err := VerifyRefDiff(testCtx, repo, refName, baseID, headID)
+		assert.Nil(t, err)
+	})
+
+	t.Run("file changed and reverted by untrusted key", func(t *testing.T) {
+		repo, baseID := createBase(t)
+		changeID := addCommit(t, repo, baseID, map[string]string{"1": "modified", "2": ""}, untrustedGPGKeyName)
+		revertID := addCommit(t, repo, changeID, map[string]string{"1": "", "2": ""}, untrustedGPGKeyName)
+		headID := addCommit(t, repo, revertID, map[string]string{"1": "", "2": "", "3": "added"}, untrustedGPGKeyName)
+
+		err := VerifyRefDiff(testCtx, repo, refName, baseID, headID)
+		assert.Nil(t, err)
+	})
+
+	t.Run("base has moved on", func(t *testing.T) {
+		repo, baseID := createBase(t)
+		headID := addCommit(t, repo, baseID, map[string]string{"1": "", "2": "", "3": "added"}, untrustedGPGKeyName)
+
+		// Changes to the base after the head branched off are not verified
+		// as part of the diff
+		newBaseID := addCommit(t, repo, baseID, map[string]string{"1": "modified", "2": ""}, untrustedGPGKeyName)
+
+		err := VerifyRefDiff(testCtx, repo, refName, newBaseID, headID)
+		assert.Nil(t, err)
+	})
+
+	t.Run("file rule threshold met", func(t *testing.T) {
+		repo, _ := createTestRepository(t, createTestStateWithFileCoSigners)
+		baseID := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 2, gpgKeyName)[1]
+
+		// The co-signed commit deletes files 1 and 2
+		headID := common.AddTestCoSignedCommitToSpecifiedRef(t, repo, refName, []string{untrustedGPGKeyName}, gpgKeyName)
+
+		err := VerifyRefDiff(testCtx, repo, refName, baseID, headID)
+		assert.Nil(t, err)
+	})
+
+	t.Run("file rule threshold not met", func(t *testing.T) {
+		repo, _ := createTestRepository(t, createTestStateWithFileCoSigners)
+		baseID := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 2, gpgKeyName)[1]
+		headID := common.AddTestCoSignedCommitToSpecifiedRef(t, repo, refName, nil, gpgKeyName)
+
+		err := VerifyRefDiff(testCtx, repo, refName, baseID, headID)
+		assert.ErrorIs(t, err, ErrUnauthorizedSignature)
+	})
+
+	t.Run("file contents not allowed", func(t *testing.T) {
+		repo, _ := createTestRepository(t, createTestStateWithAllowedHashes)
+		baseID := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 2, gpgKeyName)[1]
+		headID := addCommit(t, repo, baseID, map[string]string{"1": "modified", "2": ""}, gpgKeyName)
+
+		err := VerifyRefDiff(testCtx, repo, refName, baseID, headID)
+		assert.ErrorIs(t, err, ErrUnapprovedFileContents)
+	})
+
+	t.Run("conflict resolved by untrusted key", func(t *testing.T) {
+		repo, forkID := createBase(t)
+		baseID := addCommit(t, repo, forkID, map[string]string{"1": "base", "2": ""}, gpgKeyName)
+		sideID := addCommit(t, repo, forkID, map[string]string{"1": "side", "2": ""}, gpgKeyName)
+
+		// Both parents changed file 1, so the merge commit picks its contents
+		headID := createTestCommitWithTree(t, repo, []plumbing.Hash{baseID, sideID}, map[string]string{"1": "malicious", "2": ""}, untrustedGPGKeyName)
+
+		err := VerifyRefDiff(testCtx, repo, refName, baseID, headID)
+		assert.ErrorIs(t, err, ErrUnauthorizedSignature)
+
+		var verificationErr *CommitVerificationError
+		if assert.ErrorAs(t, err, &verificationErr) {
+			assert.Equal(t, headID, verificationErr.CommitID)
+			assert.Equal(t, "file:1", verificationErr.Namespace)
+		}
+
+		headID = createTestCommitWithTree(t, repo, []plumbing.Hash{baseID, sideID}, map[string]string{"1": "resolved", "2": ""}, gpgKeyName)
+
+		err = VerifyRefDiff(testCtx, repo, refName, baseID, headID)
+		assert.Nil(t, err)
+	})
+}
+
 func TestVerifyTag(t *testing.T) {
 	repo, _ := createTestRepository(t, createTestStateWithPolicy)
 	refName := "refs/heads/main"
@@ -201,7 +1415,7 @@ func TestVerifyEntry(t *testing.T) {
 	entryID := common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyName)
 	entry.ID = entryID
 
-	err := verifyEntry(context.Background(), repo, state, entry)
+	err := verifyEntry(context.Background(), repo, state, entry, nil)
 	assert.Nil(t, err)
 
 	// FIXME: test for file policy passing for situations where a commit is seen
@@ -212,6 +1426,343 @@ func TestVerifyEntry(t *testing.T) {
 	// signature, unseen by the RSL.
 }
 
+func TestVerifyEntryWithDistinctSigners(t *testing.T) {
+	refName := "refs/heads/main"
+
+	t.Run("chain signed by one key", func(t *testing.T) {
+		repo, state := createTestRepository(t, createTestStateWithDistinctSigners)
+
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 3, gpgKeyName)
+		entry := rsl.NewReferenceEntry(refName, commitIDs[len(commitIDs)-1])
+		entry.ID = common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyName)
+
+		err := verifyEntry(testCtx, repo, state, entry, nil)
+		assert.ErrorIs(t, err, ErrTooFewDistinctSigners)
+
+		var verificationErr *CommitVerificationError
+		if assert.ErrorAs(t, err, &verificationErr) {
+			assert.Equal(t, []string{"protect-main"}, verificationErr.RuleNames)
+		}
+	})
+
+	t.Run("chain signed by two keys", func(t *testing.T) {
+		repo, state := createTestRepository(t, createTestStateWithDistinctSigners)
+
+		common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 2, gpgKeyName)
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, untrustedGPGKeyName)
+		entry := rsl.NewReferenceEntry(refName, commitIDs[0])
+		entry.ID = common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyName)
+
+		err := verifyEntry(testCtx, repo, state, entry, nil)
+		assert.Nil(t, err)
+	})
+
+	t.Run("history shorter than required signers", func(t *testing.T) {
+		repo, state := createTestRepository(t, createTestStateWithDistinctSigners)
+
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyName)
+		entry := rsl.NewReferenceEntry(refName, commitIDs[0])
+		entry.ID = common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyName)
+
+		err := verifyEntry(testCtx, repo, state, entry, nil)
+		assert.Nil(t, err)
+	})
+}
+
+func TestVerifyEntryWithSignedCommitsRequirement(t *testing.T) {
+	refName := "refs/heads/main"
+
+	// addUnsignedCommit adds an unsigned commit to the ref that does not
+	// change any files, so that it is not subject to file rules
+	addUnsignedCommit := func(t *testing.T, repo *git.Repository) plumbing.Hash {
+		t.Helper()
+
+		ref, err := repo.Reference(plumbing.ReferenceName(refName), true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		parent, err := repo.CommitObject(ref.Hash())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		commit := &object.Commit{
+			Author:       parent.Author,
+			Committer:    parent.Committer,
+			Message:      "Unsigned commit",
+			TreeHash:     parent.TreeHash,
+			ParentHashes: []plumbing.Hash{parent.Hash},
+		}
+		commitID, err := gitinterface.ApplyCommit(repo, commit, ref)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return commitID
+	}
+
+	t.Run("all commits signed", func(t *testing.T) {
+		repo, state := createTestRepository(t, createTestStateWithSignedCommitsRequirement)
+
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 3, gpgKeyName)
+		entry := rsl.NewReferenceEntry(refName, commitIDs[len(commitIDs)-1])
+		entry.ID = common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyName)
+
+		err := verifyEntry(testCtx, repo, state, entry, nil)
+		assert.Nil(t, err)
+	})
+
+	t.Run("signed tip, unsigned ancestor", func(t *testing.T) {
+		repo, state := createTestRepository(t, createTestStateWithSignedCommitsRequirement)
+
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyName)
+		entry := rsl.NewReferenceEntry(refName, commitIDs[0])
+		entry.ID = common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyName)
+
+		// The unsigned commit and the signed tip are introduced by the same
+		// entry
+		unsignedCommitID := addUnsignedCommit(t, repo)
+		commitIDs = common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyName)
+		entry = rsl.NewReferenceEntry(refName, commitIDs[0])
+		entry.ID = common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyName)
+
+		err := verifyEntry(testCtx, repo, state, entry, nil)
+		assert.ErrorIs(t, err, ErrUnsignedCommit)
+
+		var verificationErr *CommitVerificationError
+		if assert.ErrorAs(t, err, &verificationErr) {
+			assert.Equal(t, unsignedCommitID, verificationErr.CommitID)
+			assert.Equal(t, []string{"protect-main"}, verificationErr.RuleNames)
+		}
+
+		// Without the requirement, only the RSL entry's signature is
+		// verified for the ref
+		err = verifyEntry(testCtx, repo, createTestStateWithPolicy(t), entry, nil)
+		assert.Nil(t, err)
+	})
+
+	t.Run("ancestor signed by untrusted key", func(t *testing.T) {
+		repo, state := createTestRepository(t, createTestStateWithSignedCommitsRequirement)
+
+		untrustedCommitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, untrustedGPGKeyName)
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyName)
+		entry := rsl.NewReferenceEntry(refName, commitIDs[0])
+		entry.ID = common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyName)
+
+		err := verifyEntry(testCtx, repo, state, entry, nil)
+		assert.ErrorIs(t, err, ErrUnsignedCommit)
+
+		var verificationErr *CommitVerificationError
+		if assert.ErrorAs(t, err, &verificationErr) {
+			assert.Equal(t, untrustedCommitIDs[0], verificationErr.CommitID)
+		}
+	})
+}
+
+func TestVerifyEntryWithCoSigners(t *testing.T) {
+	refName := "refs/heads/main"
+
+	tests := map[string]struct {
+		coSignerKeyNames []string
+		expectedError    error
+	}{
+		"no co-signatures": {
+			expectedError: ErrThresholdNotMet,
+		},
+		"co-signed by the same key": {
+			coSignerKeyNames: []string{gpgKeyName},
+			expectedError:    ErrThresholdNotMet,
+		},
+		"one valid co-signature": {
+			coSignerKeyNames: []string{untrustedGPGKeyName},
+		},
+		"two valid co-signatures": {
+			coSignerKeyNames: []string{gpgKeyName, untrustedGPGKeyName},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			repo, state := createTestRepository(t, createTestStateWithCoSigners)
+
+			commitID := common.AddTestCoSignedCommitToSpecifiedRef(t, repo, refName, test.coSignerKeyNames, gpgKeyName)
+			entry := rsl.NewReferenceEntry(refName, commitID)
+			entry.ID = common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyName)
+
+			err := verifyEntry(testCtx, repo, state, entry, nil)
+			if test.expectedError == nil {
+				assert.Nil(t, err)
+			} else {
+				assert.ErrorIs(t, err, test.expectedError)
+			}
+		})
+	}
+}
+
+func TestVerifyEntryWithBreakGlass(t *testing.T) {
+	repo, state := createTestRepository(t, createTestStateWithEmergencyRole)
+	refName := "refs/heads/main"
+
+	// The commit is signed by a key the rule for main does not trust
+	commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, untrustedGPGKeyName)
+	entry := rsl.NewReferenceEntry(refName, commitIDs[0])
+	entry.ID = common.CreateTestRSLReferenceEntryCommit(t, repo, entry, untrustedGPGKeyName)
+
+	err := verifyEntry(testCtx, repo, state, entry, nil)
+	assert.ErrorIs(t, err, ErrUnauthorizedSignature)
+
+	// An annotation that isn't for break-glass access doesn't count
+	annotations := []*rsl.AnnotationEntry{}
+	annotation := rsl.NewAnnotationEntry([]plumbing.Hash{entry.ID}, false, "not break glass")
+	annotation.ID = common.CreateTestRSLAnnotationEntryCommit(t, repo, annotation, gpgKeyName)
+	annotations = append(annotations, annotation)
+
+	annotation = rsl.NewAnnotationEntry([]plumbing.Hash{entry.ID}, false, "not break glass")
+	annotation.ID = common.CreateTestRSLAnnotationEntryCommit(t, repo, annotation, untrustedGPGKeyName)
+	annotations = append(annotations, annotation)
+
+	err = verifyEntry(testCtx, repo, state, entry, annotations)
+	assert.ErrorIs(t, err, ErrUnauthorizedSignature)
+
+	// One emergency key doesn't meet the threshold, even if it signs more
+	// than one annotation
+	annotation = rsl.NewBreakGlassAnnotationEntry([]plumbing.Hash{entry.ID}, "outage")
+	annotation.ID = common.CreateTestRSLAnnotationEntryCommit(t, repo, annotation, gpgKeyName)
+	annotations = append(annotations, annotation)
+
+	annotation = rsl.NewBreakGlassAnnotationEntry([]plumbing.Hash{entry.ID}, "outage")
+	annotation.ID = common.CreateTestRSLAnnotationEntryCommit(t, repo, annotation, gpgKeyName)
+	annotations = append(annotations, annotation)
+
+	err = verifyEntry(testCtx, repo, state, entry, annotations)
+	assert.ErrorIs(t, err, ErrUnauthorizedSignature)
+
+	// Both emergency keys authorize the entry, and the use of break-glass
+	// access is recorded in the verification report
+	annotation = rsl.NewBreakGlassAnnotationEntry([]plumbing.Hash{entry.ID}, "outage")
+	annotation.ID = common.CreateTestRSLAnnotationEntryCommit(t, repo, annotation, untrustedGPGKeyName)
+	annotations = append(annotations, annotation)
+
+	report := &VerificationReport{}
+	err = verifyEntry(NewReportContext(testCtx, report), repo, state, entry, annotations)
+	assert.Nil(t, err)
+
+	uses := report.BreakGlassUses()
+	if assert.Len(t, uses, 1) {
+		assert.Equal(t, refName, uses[0].RefName)
+		assert.Equal(t, entry.ID, uses[0].EntryID)
+		assert.Equal(t, []plumbing.Hash{annotations[2].ID, annotations[4].ID}, uses[0].AnnotationIDs)
+		assert.ErrorIs(t, uses[0].Err, ErrUnauthorizedSignature)
+	}
+
+	// Verifying the ref also reports the break-glass use for its latest entry
+	report = &VerificationReport{}
+	err = VerifyRef(NewReportContext(testCtx, report), repo, refName)
+	assert.Nil(t, err)
+	assert.Len(t, report.BreakGlassUses(), 1)
+
+	// An entry that the rules accept is not reported
+	commitIDs = common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyName)
+	trustedEntry := rsl.NewReferenceEntry(refName, commitIDs[0])
+	trustedEntry.ID = common.CreateTestRSLReferenceEntryCommit(t, repo, trustedEntry, gpgKeyName)
+
+	report = &VerificationReport{}
+	err = verifyEntry(NewReportContext(testCtx, report), repo, state, trustedEntry, nil)
+	assert.Nil(t, err)
+	assert.Empty(t, report.BreakGlassUses())
+
+	// Emergency keys that can't be used to verify Git signatures are skipped
+	unknownKey := *state.RootPublicKeys[0]
+	unknownKey.KeyID = "unknown-key"
+	unknownKey.KeyType = "unregistered"
+	rootMetadata, err := state.GetRootMetadata()
+	if err != nil {
+		t.Fatal(err)
+	}
+	role := rootMetadata.Roles[EmergencyRoleName]
+	role.KeyIDs = append([]string{unknownKey.KeyID}, role.KeyIDs...)
+	rootMetadata.Roles[EmergencyRoleName] = role
+	rootMetadata.AddKey(&unknownKey)
+	setTestRootMetadata(t, state, rootMetadata)
+
+	err = verifyEntry(testCtx, repo, state, entry, annotations)
+	assert.Nil(t, err)
+
+	// Break-glass access is unavailable without the emergency role
+	repo, state = createTestRepository(t, createTestStateWithPolicy)
+	commitIDs = common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, untrustedGPGKeyName)
+	entry = rsl.NewReferenceEntry(refName, commitIDs[0])
+	entry.ID = common.CreateTestRSLReferenceEntryCommit(t, repo, entry, untrustedGPGKeyName)
+
+	annotations = []*rsl.AnnotationEntry{}
+	for _, keyName := range []string{gpgKeyName, untrustedGPGKeyName} {
+		annotation := rsl.NewBreakGlassAnnotationEntry([]plumbing.Hash{entry.ID}, "outage")
+		annotation.ID = common.CreateTestRSLAnnotationEntryCommit(t, repo, annotation, keyName)
+		annotations = append(annotations, annotation)
+	}
+
+	err = verifyEntry(testCtx, repo, state, entry, annotations)
+	assert.ErrorIs(t, err, ErrUnauthorizedSignature)
+}
+
+func TestVerifyNotesEntry(t *testing.T) {
+	refName := "refs/heads/main"
+	notesRefName := "refs/notes/commits"
+
+	t.Run("note signed by trusted key", func(t *testing.T) {
+		repo, state := createTestRepository(t, createTestStateWithNotesPolicy)
+
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyName)
+		noteCommitID := common.AddTestNoteToSpecifiedRef(t, repo, notesRefName, commitIDs[0], []byte("Reviewed-by: Jane Doe"), gpgKeyName)
+		entry := rsl.NewReferenceEntry(notesRefName, noteCommitID)
+		entry.ID = common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyName)
+
+		err := verifyEntry(testCtx, repo, state, entry, nil)
+		assert.Nil(t, err)
+
+		err = VerifyRefFull(testCtx, repo, notesRefName)
+		assert.Nil(t, err)
+	})
+
+	t.Run("note signed by untrusted key", func(t *testing.T) {
+		repo, state := createTestRepository(t, createTestStateWithNotesPolicy)
+
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 2, gpgKeyName)
+		firstNoteCommitID := common.AddTestNoteToSpecifiedRef(t, repo, notesRefName, commitIDs[0], []byte("Reviewed-by: Jane Doe"), gpgKeyName)
+		entry := rsl.NewReferenceEntry(notesRefName, firstNoteCommitID)
+		entry.ID = common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyName)
+
+		// The notes commit is signed by an untrusted key, but the RSL entry
+		// recording it is signed by a trusted key
+		secondNoteCommitID := common.AddTestNoteToSpecifiedRef(t, repo, notesRefName, commitIDs[1], []byte("Reviewed-by: Mallory"), untrustedGPGKeyName)
+		entry = rsl.NewReferenceEntry(notesRefName, secondNoteCommitID)
+		entry.ID = common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyName)
+
+		err := verifyEntry(testCtx, repo, state, entry, nil)
+		assert.ErrorIs(t, err, ErrUnauthorizedSignature)
+
+		var verificationErr *CommitVerificationError
+		if assert.ErrorAs(t, err, &verificationErr) {
+			assert.Equal(t, secondNoteCommitID, verificationErr.CommitID)
+			assert.Equal(t, []string{"protect-notes"}, verificationErr.RuleNames)
+			assert.Contains(t, verificationErr.Error(), commitIDs[1].String())
+			assert.NotContains(t, verificationErr.Error(), commitIDs[0].String())
+		}
+	})
+
+	t.Run("unprotected notes ref", func(t *testing.T) {
+		repo, state := createTestRepository(t, createTestStateWithPolicy)
+
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyName)
+		noteCommitID := common.AddTestNoteToSpecifiedRef(t, repo, notesRefName, commitIDs[0], []byte("Reviewed-by: Mallory"), untrustedGPGKeyName)
+		entry := rsl.NewReferenceEntry(notesRefName, noteCommitID)
+		entry.ID = common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyName)
+
+		err := verifyEntry(testCtx, repo, state, entry, nil)
+		assert.Nil(t, err)
+	})
+}
+
 func TestVerifyTagEntry(t *testing.T) {
 	t.Run("no tag specific policy", func(t *testing.T) {
 		repo, policy := createTestRepository(t, createTestStateWithPolicy)
@@ -272,6 +1823,117 @@ func TestVerifyTagEntry(t *testing.T) {
 		err := verifyTagEntry(context.Background(), repo, policy, entry)
 		assert.ErrorIs(t, err, ErrUnauthorizedSignature)
 	})
+
+	t.Run("tag points to verified commit", func(t *testing.T) {
+		repo, policy := createTestRepository(t, createTestStateWithPolicy)
+		refName := "refs/heads/main"
+
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 3, gpgKeyName)
+		entry := rsl.NewReferenceEntry(refName, commitIDs[len(commitIDs)-1])
+		entryID := common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyName)
+		entry.ID = entryID
+
+		// The tag doesn't have to point to the latest commit recorded
+		tagName := "v1"
+		tagID := common.CreateTestSignedTag(t, repo, tagName, commitIDs[0], gpgKeyName)
+
+		entry = rsl.NewReferenceEntry(string(plumbing.NewTagReferenceName(tagName)), tagID)
+		entryID = common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyName)
+		entry.ID = entryID
+
+		err := verifyTagEntry(context.Background(), repo, policy, entry)
+		assert.Nil(t, err)
+	})
+
+	t.Run("tag points to commit not recorded in RSL", func(t *testing.T) {
+		repo, policy := createTestRepository(t, createTestStateWithPolicy)
+		refName := "refs/heads/main"
+
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 3, gpgKeyName)
+		entry := rsl.NewReferenceEntry(refName, commitIDs[len(commitIDs)-1])
+		entryID := common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyName)
+		entry.ID = entryID
+
+		// The feature commit builds on main with its own contents, so it is
+		// distinct from every recorded commit, and it is never recorded in
+		// the RSL
+		featureCommitID := createTestCommitWithTree(t, repo, []plumbing.Hash{commitIDs[len(commitIDs)-1]}, map[string]string{"feature": "unrecorded"}, gpgKeyName)
+
+		tagName := "v1"
+		tagID := common.CreateTestSignedTag(t, repo, tagName, featureCommitID, gpgKeyName)
+
+		entry = rsl.NewReferenceEntry(string(plumbing.NewTagReferenceName(tagName)), tagID)
+		entryID = common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyName)
+		entry.ID = entryID
+
+		err := verifyTagEntry(context.Background(), repo, policy, entry)
+		assert.ErrorIs(t, err, ErrTagTargetNotRecorded)
+	})
+
+	t.Run("tag points to commit signed by untrusted key", func(t *testing.T) {
+		repo, policy := createTestRepository(t, createTestStateWithPolicy)
+		refName := "refs/heads/main"
+
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, untrustedGPGKeyName)
+		entry := rsl.NewReferenceEntry(refName, commitIDs[0])
+		entryID := common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyName)
+		entry.ID = entryID
+
+		tagName := "v1"
+		tagID := common.CreateTestSignedTag(t, repo, tagName, commitIDs[0], gpgKeyName)
+
+		entry = rsl.NewReferenceEntry(string(plumbing.NewTagReferenceName(tagName)), tagID)
+		entryID = common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyName)
+		entry.ID = entryID
+
+		err := verifyTagEntry(context.Background(), repo, policy, entry)
+		assert.ErrorIs(t, err, ErrTagTargetNotVerified)
+	})
+
+	t.Run("tag points to commit recorded after the tag", func(t *testing.T) {
+		repo, policy := createTestRepository(t, createTestStateWithPolicy)
+		refName := "refs/heads/main"
+
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyName)
+
+		tagName := "v1"
+		tagID := common.CreateTestSignedTag(t, repo, tagName, commitIDs[0], gpgKeyName)
+
+		tagEntry := rsl.NewReferenceEntry(string(plumbing.NewTagReferenceName(tagName)), tagID)
+		tagEntryID := common.CreateTestRSLReferenceEntryCommit(t, repo, tagEntry, gpgKeyName)
+		tagEntry.ID = tagEntryID
+
+		// The commit is only recorded once the tag has been recorded
+		entry := rsl.NewReferenceEntry(refName, commitIDs[0])
+		common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyName)
+
+		err := verifyTagEntry(context.Background(), repo, policy, tagEntry)
+		assert.ErrorIs(t, err, ErrTagTargetNotRecorded)
+	})
+
+	t.Run("tag points to tree", func(t *testing.T) {
+		repo, policy := createTestRepository(t, createTestStateWithPolicy)
+		refName := "refs/heads/main"
+
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyName)
+		entry := rsl.NewReferenceEntry(refName, commitIDs[0])
+		common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyName)
+
+		commit, err := repo.CommitObject(commitIDs[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		tagName := "v1"
+		tagID := common.CreateTestSignedTag(t, repo, tagName, commit.TreeHash, gpgKeyName)
+
+		entry = rsl.NewReferenceEntry(string(plumbing.NewTagReferenceName(tagName)), tagID)
+		entryID := common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyName)
+		entry.ID = entryID
+
+		err = verifyTagEntry(context.Background(), repo, policy, entry)
+		assert.ErrorIs(t, err, ErrTagTargetNotCommit)
+	})
 }
 
 func TestGetCommits(t *testing.T) {
@@ -363,43 +2025,113 @@ func TestStateVerifyNewState(t *testing.T) {
 
 	t.Run("invalid policy transition", func(t *testing.T) {
 		currentPolicy := createTestStateWithOnlyRoot(t)
+		newPolicy := createTestStateWithUntrustedRoot(t)
+
+		err := currentPolicy.VerifyNewState(context.Background(), newPolicy)
+		assert.ErrorContains(t, err, "do not match threshold")
+	})
+}
+
+func TestVerifyPolicyTransition(t *testing.T) {
+	t.Run("authorized transition", func(t *testing.T) {
+		prevPolicy := createTestStateWithOnlyRoot(t)
+		nextPolicy := createTestStateWithOnlyRoot(t)
 
-		// Create invalid state
-		signingKeyBytes, err := os.ReadFile(filepath.Join("test-data", "targets-1"))
+		err := VerifyPolicyTransition(testCtx, prevPolicy, nextPolicy)
+		assert.Nil(t, err)
+	})
+
+	t.Run("unauthorized root change", func(t *testing.T) {
+		prevPolicy := createTestStateWithOnlyRoot(t)
+		nextPolicy := createTestStateWithUntrustedRoot(t)
+
+		err := VerifyPolicyTransition(testCtx, prevPolicy, nextPolicy)
+		assert.ErrorIs(t, err, ErrUnauthorizedPolicyChange)
+	})
+
+	t.Run("root version rollback", func(t *testing.T) {
+		prevPolicy := createTestStateWithOnlyRoot(t)
+
+		rootMetadata, err := prevPolicy.GetRootMetadata()
 		if err != nil {
 			t.Fatal(err)
 		}
-		signer, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(signingKeyBytes)
+		rootMetadata.SetVersion(2)
+
+		signer, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(rootKeyBytes)
 		if err != nil {
 			t.Fatal(err)
 		}
-
-		keyBytes, err := os.ReadFile(filepath.Join("test-data", "targets-1.pub"))
+		rootEnv, err := dsse.CreateEnvelope(rootMetadata)
 		if err != nil {
 			t.Fatal(err)
 		}
-		key, err := tuf.LoadKeyFromBytes(keyBytes)
+		rootEnv, err = dsse.SignEnvelope(testCtx, rootEnv, signer)
 		if err != nil {
 			t.Fatal(err)
 		}
+		prevPolicy.RootEnvelope = rootEnv
 
-		rootMetadata := InitializeRootMetadata(key)
+		nextPolicy := createTestStateWithOnlyRoot(t)
 
-		rootEnv, err := dsse.CreateEnvelope(rootMetadata)
-		if err != nil {
-			t.Fatal(err)
-		}
-		rootEnv, err = dsse.SignEnvelope(context.Background(), rootEnv, signer)
-		if err != nil {
+		err = VerifyPolicyTransition(testCtx, prevPolicy, nextPolicy)
+		assert.ErrorIs(t, err, ErrUnauthorizedPolicyChange)
+	})
+
+	t.Run("unauthorized root change recorded in RSL", func(t *testing.T) {
+		repo, _ := createTestRepository(t, createTestStateWithPolicy)
+		refName := "refs/heads/main"
+
+		if err := createTestStateWithUntrustedRoot(t).Commit(testCtx, repo, "Unauthorized root change", false); err != nil {
 			t.Fatal(err)
 		}
-		newPolicy := &State{
-			RootPublicKeys:      []*tuf.Key{key},
-			RootEnvelope:        rootEnv,
-			DelegationEnvelopes: map[string]*sslibdsse.Envelope{},
-		}
 
-		err = currentPolicy.VerifyNewState(context.Background(), newPolicy)
-		assert.ErrorContains(t, err, "do not match threshold")
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyName)
+		entry := rsl.NewReferenceEntry(refName, commitIDs[0])
+		common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyName)
+
+		err := VerifyRefFull(testCtx, repo, refName)
+		assert.ErrorIs(t, err, ErrUnauthorizedPolicyChange)
 	})
 }
+
+// createTestStateWithUntrustedRoot returns a valid policy state whose root
+// metadata is signed by a key that is not trusted by the other test states.
+func createTestStateWithUntrustedRoot(t *testing.T) *State {
+	t.Helper()
+
+	signingKeyBytes, err := os.ReadFile(filepath.Join("test-data", "targets-1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(signingKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyBytes, err := os.ReadFile(filepath.Join("test-data", "targets-1.pub"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := tuf.LoadKeyFromBytes(keyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rootMetadata := InitializeRootMetadata(key)
+
+	rootEnv, err := dsse.CreateEnvelope(rootMetadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootEnv, err = dsse.SignEnvelope(context.Background(), rootEnv, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &State{
+		RootPublicKeys:      []*tuf.Key{key},
+		RootEnvelope:        rootEnv,
+		DelegationEnvelopes: map[string]*sslibdsse.Envelope{},
+	}
+}
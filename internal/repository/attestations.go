@@ -0,0 +1,63 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/gittuf/gittuf/internal/attestations"
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/gittuf/gittuf/internal/signerverifier"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+)
+
+// VerifyRefAndAttest verifies the target ref in the same way as VerifyRef, and
+// if the verification succeeds, issues a SLSA verification summary attestation
+// (VSA) recording that the ref's tip passed verification against the current
+// policy. The VSA is signed using signingKeyBytes and returned so that it can
+// be consumed by downstream tooling. If store is true, the VSA is also recorded
+// in the repository's attestations namespace. No VSA is issued if the
+// verification fails.
+func (r *Repository) VerifyRefAndAttest(ctx context.Context, target string, full bool, signingKeyBytes []byte, store, signCommit bool, opts ...VerifyRefOption) (*sslibdsse.Envelope, error) {
+	signer, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(signingKeyBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.VerifyRef(ctx, target, full, opts...); err != nil {
+		return nil, err
+	}
+
+	target, err = gitinterface.AbsoluteReference(r.r, target)
+	if err != nil {
+		return nil, err
+	}
+
+	// VerifyRef checks that the ref's tip matches its latest RSL entry and
+	// uses the policy recorded in the latest policy entry
+	targetEntry, _, err := rsl.GetLatestReferenceEntryForRef(r.r, target)
+	if err != nil {
+		return nil, err
+	}
+	policyEntry, _, err := rsl.GetLatestReferenceEntryForRef(r.r, policy.PolicyRef)
+	if err != nil {
+		return nil, err
+	}
+
+	statement := attestations.NewVerificationSummary(target, targetEntry.TargetID, policy.PolicyRef, policyEntry.TargetID, time.Now())
+	env, err := attestations.SignVerificationSummary(ctx, statement, signer)
+	if err != nil {
+		return nil, err
+	}
+
+	if store {
+		if err := attestations.StoreVerificationSummary(r.r, env, signCommit); err != nil {
+			return nil, err
+		}
+	}
+
+	return env, nil
+}
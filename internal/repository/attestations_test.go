@@ -0,0 +1,104 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/attestations"
+	"github.com/gittuf/gittuf/internal/common"
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/gittuf/gittuf/internal/signerverifier"
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifyRefAndAttest(t *testing.T) {
+	refName := "refs/heads/main"
+
+	signingKeyBytes, err := os.ReadFile(filepath.Join("test-data", "targets"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	verifier, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(signingKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("successful verification", func(t *testing.T) {
+		repo := createTestRepositoryWithPolicy(t, "")
+
+		if err := repo.r.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(refName), plumbing.ZeroHash)); err != nil {
+			t.Fatal(err)
+		}
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo.r, refName, 1, gpgKeyName)
+		common.CreateTestRSLReferenceEntryCommit(t, repo.r, rsl.NewReferenceEntry(refName, commitIDs[0]), gpgKeyName)
+
+		policyTip, err := gitinterface.GetTip(repo.r, policy.PolicyRef)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		env, err := repo.VerifyRefAndAttest(context.Background(), "main", false, signingKeyBytes, true, false)
+		assert.Nil(t, err)
+
+		err = dsse.VerifyEnvelope(context.Background(), env, []sslibdsse.Verifier{verifier}, 1)
+		assert.Nil(t, err)
+
+		statement, err := attestations.GetVerificationSummary(env)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, refName, statement.Subject[0].Name)
+		assert.Equal(t, commitIDs[0].String(), statement.Subject[0].Digest[attestations.DigestAlgorithmGitCommit])
+		assert.Equal(t, policyTip.String(), statement.Predicate.Policy.Digest[attestations.DigestAlgorithmGitCommit])
+		assert.Equal(t, attestations.VerificationResultPassed, statement.Predicate.VerificationResult)
+
+		storedEnv, err := attestations.LoadVerificationSummary(context.Background(), repo.r, refName, commitIDs[0])
+		assert.Nil(t, err)
+		assert.Equal(t, env, storedEnv)
+	})
+
+	t.Run("verification summary not stored", func(t *testing.T) {
+		repo := createTestRepositoryWithPolicy(t, "")
+
+		if err := repo.r.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(refName), plumbing.ZeroHash)); err != nil {
+			t.Fatal(err)
+		}
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo.r, refName, 1, gpgKeyName)
+		common.CreateTestRSLReferenceEntryCommit(t, repo.r, rsl.NewReferenceEntry(refName, commitIDs[0]), gpgKeyName)
+
+		env, err := repo.VerifyRefAndAttest(context.Background(), refName, false, signingKeyBytes, false, false)
+		assert.Nil(t, err)
+		assert.NotNil(t, env)
+
+		_, err = attestations.LoadVerificationSummary(context.Background(), repo.r, refName, commitIDs[0])
+		assert.ErrorIs(t, err, attestations.ErrAttestationNotFound)
+	})
+
+	t.Run("unsuccessful verification", func(t *testing.T) {
+		repo := createTestRepositoryWithPolicy(t, "")
+
+		if err := repo.r.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(refName), plumbing.ZeroHash)); err != nil {
+			t.Fatal(err)
+		}
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo.r, refName, 1, gpgKeyName)
+		if err := rsl.NewReferenceEntry(refName, commitIDs[0]).Commit(repo.r, false); err != nil {
+			t.Fatal(err)
+		}
+
+		env, err := repo.VerifyRefAndAttest(context.Background(), refName, false, signingKeyBytes, true, false)
+		assert.ErrorIs(t, err, policy.ErrUnauthorizedSignature)
+		assert.Nil(t, env)
+
+		_, err = attestations.LoadVerificationSummary(context.Background(), repo.r, refName, commitIDs[0])
+		assert.ErrorIs(t, err, attestations.ErrAttestationNotFound)
+	})
+}
@@ -0,0 +1,109 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing"
+	"github.com/gittuf/gittuf/internal/tuf"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+)
+
+// InitializeGittuf bootstraps gittuf in the repository in a single call. It
+// creates the RSL and policy namespaces, creates root metadata that trusts
+// rootKey for both the root and top level targets roles, creates empty targets
+// metadata, and commits the resulting policy along with its RSL entry. Both
+// metadata files are signed using signer, which must correspond to rootKey. If
+// any step fails, the gittuf namespaces are restored to their prior state. The
+// initialized State is returned.
+func (r *Repository) InitializeGittuf(ctx context.Context, rootKey *tuf.Key, signer sslibdsse.SignerVerifier, signCommit bool) (*policy.State, error) {
+	signerKeyID, err := signer.KeyID()
+	if err != nil {
+		return nil, err
+	}
+	if signerKeyID != rootKey.KeyID {
+		return nil, ErrUnauthorizedKey
+	}
+
+	originalRefs := map[string]*plumbing.Reference{}
+	for _, refName := range []string{rsl.Ref, policy.PolicyRef} {
+		ref, err := r.r.Reference(plumbing.ReferenceName(refName), true)
+		if err != nil {
+			if !errors.Is(err, plumbing.ErrReferenceNotFound) {
+				return nil, err
+			}
+			ref = nil
+		}
+		originalRefs[refName] = ref
+	}
+
+	state, err := r.initializeGittuf(ctx, rootKey, signer, signCommit)
+	if err != nil {
+		return nil, r.restoreRefsDueToError(err, originalRefs)
+	}
+
+	return state, nil
+}
+
+func (r *Repository) initializeGittuf(ctx context.Context, rootKey *tuf.Key, signer sslibdsse.SignerVerifier, signCommit bool) (*policy.State, error) {
+	if err := r.InitializeNamespaces(); err != nil {
+		return nil, err
+	}
+
+	rootMetadata := policy.InitializeRootMetadata(rootKey)
+	rootMetadata = policy.AddTargetsKey(rootMetadata, rootKey)
+
+	rootEnv, err := dsse.CreateEnvelope(rootMetadata)
+	if err != nil {
+		return nil, err
+	}
+	rootEnv, err = dsse.SignEnvelope(ctx, rootEnv, signer)
+	if err != nil {
+		return nil, err
+	}
+
+	targetsEnv, err := dsse.CreateEnvelope(policy.InitializeTargetsMetadata())
+	if err != nil {
+		return nil, err
+	}
+	targetsEnv, err = dsse.SignEnvelope(ctx, targetsEnv, signer)
+	if err != nil {
+		return nil, err
+	}
+
+	state := &policy.State{
+		RootEnvelope:    rootEnv,
+		TargetsEnvelope: targetsEnv,
+		RootPublicKeys:  []*tuf.Key{rootKey},
+	}
+
+	if err := state.Commit(ctx, r.r, "Initialize gittuf", signCommit); err != nil {
+		return nil, err
+	}
+
+	return policy.LoadCurrentState(ctx, r.r)
+}
+
+// restoreRefsDueToError resets the specified refs to their original values,
+// removing the refs that did not exist before. The cause is returned, wrapped
+// with any error encountered while restoring the refs.
+func (r *Repository) restoreRefsDueToError(cause error, originalRefs map[string]*plumbing.Reference) error {
+	for refName, ref := range originalRefs {
+		var err error
+		if ref == nil {
+			err = r.r.Storer.RemoveReference(plumbing.ReferenceName(refName))
+		} else {
+			err = r.r.Storer.SetReference(ref)
+		}
+		if err != nil {
+			return errors.Join(cause, err)
+		}
+	}
+
+	return cause
+}
@@ -0,0 +1,123 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/gittuf/gittuf/internal/signerverifier"
+	"github.com/gittuf/gittuf/internal/third_party/go-git"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/storage/memory"
+	"github.com/gittuf/gittuf/internal/tuf"
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInitializeGittuf(t *testing.T) {
+	rootKeyBytes, err := os.ReadFile(filepath.Join("test-data", "root"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootKey, err := tuf.LoadKeyFromBytes(rootKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootSigner, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(rootKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targetsKeyBytes, err := os.ReadFile(filepath.Join("test-data", "targets"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	targetsSigner, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(targetsKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newRepository := func(t *testing.T) *Repository {
+		t.Helper()
+
+		repo, err := git.Init(memory.NewStorage(), memfs.New())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &Repository{r: repo}
+	}
+
+	t.Run("successful bootstrap", func(t *testing.T) {
+		r := newRepository(t)
+
+		state, err := r.InitializeGittuf(context.Background(), rootKey, rootSigner, false)
+		assert.Nil(t, err)
+		assert.NotNil(t, state.RootEnvelope)
+		assert.NotNil(t, state.TargetsEnvelope)
+
+		rootMetadata, err := state.GetRootMetadata()
+		assert.Nil(t, err)
+		assert.Equal(t, []string{rootKey.KeyID}, rootMetadata.Roles[policy.RootRoleName].KeyIDs)
+		assert.Equal(t, []string{rootKey.KeyID}, rootMetadata.Roles[policy.TargetsRoleName].KeyIDs)
+
+		targetsMetadata, err := state.GetTargetsMetadata(policy.TargetsRoleName)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(targetsMetadata.Delegations.Roles)) // only the allow rule
+
+		policyTip, err := r.r.Reference(plumbing.ReferenceName(policy.PolicyRef), true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		latestEntry, err := rsl.GetLatestEntry(r.r)
+		assert.Nil(t, err)
+		assert.Equal(t, policy.PolicyRef, latestEntry.(*rsl.ReferenceEntry).RefName)
+		assert.Equal(t, policyTip.Hash(), latestEntry.(*rsl.ReferenceEntry).TargetID)
+
+		// The repository is now usable with existing gittuf metadata
+		_, err = New(r.r)
+		assert.Nil(t, err)
+
+		err = r.InitializeTargets(context.Background(), rootKeyBytes, policy.TargetsRoleName, false)
+		assert.ErrorIs(t, err, ErrCannotReinitialize)
+	})
+
+	t.Run("signer does not match root key", func(t *testing.T) {
+		r := newRepository(t)
+
+		_, err := r.InitializeGittuf(context.Background(), rootKey, targetsSigner, false)
+		assert.ErrorIs(t, err, ErrUnauthorizedKey)
+
+		_, err = r.r.Reference(plumbing.ReferenceName(rsl.Ref), true)
+		assert.ErrorIs(t, err, plumbing.ErrReferenceNotFound)
+		_, err = r.r.Reference(plumbing.ReferenceName(policy.PolicyRef), true)
+		assert.ErrorIs(t, err, plumbing.ErrReferenceNotFound)
+	})
+
+	t.Run("already initialized", func(t *testing.T) {
+		r, _ := createTestRepositoryWithRoot(t, "")
+
+		rslTip, err := r.r.Reference(plumbing.ReferenceName(rsl.Ref), true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		policyTip, err := r.r.Reference(plumbing.ReferenceName(policy.PolicyRef), true)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = r.InitializeGittuf(context.Background(), rootKey, rootSigner, false)
+		assert.ErrorIs(t, err, rsl.ErrRSLExists)
+
+		ref, err := r.r.Reference(plumbing.ReferenceName(rsl.Ref), true)
+		assert.Nil(t, err)
+		assert.Equal(t, rslTip.Hash(), ref.Hash())
+		ref, err = r.r.Reference(plumbing.ReferenceName(policy.PolicyRef), true)
+		assert.Nil(t, err)
+		assert.Equal(t, policyTip.Hash(), ref.Hash())
+	})
+}
@@ -0,0 +1,167 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/gittuf/gittuf/internal/third_party/go-git"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing"
+)
+
+var (
+	ErrBundleMissingGittufRefs  = errors.New("bundle does not contain the RSL and policy refs")
+	ErrBundleVerificationFailed = errors.New("verification of bundle failed")
+	ErrBundleRSLNotFastForward  = errors.New("bundle's RSL is not a fast-forward of the local RSL")
+	ErrBundleRefNotFastForward  = errors.New("bundle's ref is not a fast-forward of the local ref")
+)
+
+// CreateBundle writes a git bundle to w containing the gittuf refs and the
+// specified refs. The bundle can be imported into another copy of the
+// repository using ImportBundle, allowing the gittuf state to be propagated
+// without network access between the repositories.
+func (r *Repository) CreateBundle(refs []string, w io.Writer) error {
+	refNames := []string{rsl.Ref, policy.PolicyRef}
+	for _, ref := range refs {
+		refName, err := gitinterface.AbsoluteReference(r.r, ref)
+		if err != nil {
+			return err
+		}
+
+		refNames = append(refNames, refName)
+	}
+
+	return gitinterface.CreateBundle(r.r, refNames, w)
+}
+
+// ImportBundle reads a git bundle containing the gittuf refs into a temporary
+// in-memory repository and verifies it before importing it into the
+// repository. The bundle's RSL must be a fast-forward of the local RSL, and the
+// policy and every other ref in the bundle are verified from the start of the
+// bundle's RSL. Every ref in the bundle must also be a fast-forward of the
+// corresponding local ref, if it exists. Only if all the verifications succeed
+// are the bundle's objects and refs imported. If any verification fails, the
+// repository is unchanged. If a ref cannot be updated, the refs that were
+// already updated are reset to their previous tips.
+func (r *Repository) ImportBundle(ctx context.Context, bundle io.Reader) error {
+	bundleRepo, err := gitinterface.ReadBundleToMemory(bundle)
+	if err != nil {
+		return err
+	}
+
+	refsIter, err := bundleRepo.References()
+	if err != nil {
+		return err
+	}
+	refs := map[string]plumbing.Hash{}
+	refNames := []string{}
+	if err := refsIter.ForEach(func(ref *plumbing.Reference) error {
+		if ref.Type() != plumbing.HashReference {
+			return nil
+		}
+
+		refs[ref.Name().String()] = ref.Hash()
+		if ref.Name().String() != rsl.Ref && ref.Name().String() != policy.PolicyRef {
+			refNames = append(refNames, ref.Name().String())
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	if _, has := refs[rsl.Ref]; !has {
+		return ErrBundleMissingGittufRefs
+	}
+	if _, has := refs[policy.PolicyRef]; !has {
+		return ErrBundleMissingGittufRefs
+	}
+
+	// The checked out branch is updated last as it also resets the worktree
+	head, err := r.r.Reference(plumbing.HEAD, false)
+	if err != nil {
+		return err
+	}
+	updateOrder := []string{rsl.Ref, policy.PolicyRef}
+	checkedOutRef := ""
+	for _, refName := range refNames {
+		if head.Type() == plumbing.SymbolicReference && head.Target().String() == refName {
+			checkedOutRef = refName
+			continue
+		}
+		updateOrder = append(updateOrder, refName)
+	}
+	if checkedOutRef != "" {
+		updateOrder = append(updateOrder, checkedOutRef)
+	}
+
+	localTips := map[string]plumbing.Hash{}
+	for _, refName := range updateOrder {
+		localTip, err := gitinterface.GetTip(r.r, refName)
+		if err != nil {
+			if !errors.Is(err, gitinterface.ErrReferenceNotFound) {
+				return err
+			}
+
+			localTip = plumbing.ZeroHash
+		}
+		localTips[refName] = localTip
+
+		isFastForward, err := isBundleRefFastForward(bundleRepo, refs[refName], localTip)
+		if err != nil {
+			return err
+		}
+		if !isFastForward {
+			if refName == rsl.Ref {
+				return ErrBundleRSLNotFastForward
+			}
+			return fmt.Errorf("%w: '%s'", ErrBundleRefNotFastForward, refName)
+		}
+	}
+
+	for _, refName := range append([]string{policy.PolicyRef}, refNames...) {
+		if err := policy.VerifyRefFull(ctx, bundleRepo, refName); err != nil {
+			return errors.Join(ErrBundleVerificationFailed, fmt.Errorf("unable to verify '%s': %w", refName, err))
+		}
+	}
+
+	if err := gitinterface.CopyObjects(bundleRepo, r.r); err != nil {
+		return err
+	}
+
+	updatedTips := map[string]plumbing.Hash{}
+	for _, refName := range updateOrder {
+		updatedTips[refName] = localTips[refName]
+		if err := r.updateRef(refName, refs[refName]); err != nil {
+			return r.resetRefsDueToError(err, updatedTips)
+		}
+	}
+
+	return nil
+}
+
+// isBundleRefFastForward checks if the bundle's tip of a ref is a fast-forward
+// of the ref's local tip. A ref that does not exist locally can always be
+// fast-forwarded.
+func isBundleRefFastForward(bundleRepo *git.Repository, bundleTip, localTip plumbing.Hash) (bool, error) {
+	if localTip.IsZero() || localTip == bundleTip {
+		return true, nil
+	}
+
+	// As the bundle is self-contained, the local tip is only available in the
+	// bundle if the bundle's ref extends it
+	localTipCommit, err := bundleRepo.CommitObject(localTip)
+	if err != nil {
+		if errors.Is(err, plumbing.ErrObjectNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return gitinterface.KnowsCommit(bundleRepo, bundleTip, localTipCommit)
+}
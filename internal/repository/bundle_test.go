@@ -0,0 +1,167 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/common"
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/gittuf/gittuf/internal/third_party/go-git"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/storage/memory"
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImportBundle(t *testing.T) {
+	refName := "refs/heads/main"
+
+	createTargetRepository := func(t *testing.T) *Repository {
+		t.Helper()
+
+		r, err := git.Init(memory.NewStorage(), memfs.New())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &Repository{r: r}
+	}
+
+	t.Run("verified bundle", func(t *testing.T) {
+		source := createTestRepositoryWithPolicy(t, "")
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, source.r, refName, 2, gpgKeyName)
+		common.CreateTestRSLReferenceEntryCommit(t, source.r, rsl.NewReferenceEntry(refName, commitIDs[1]), gpgKeyName)
+
+		bundle := &bytes.Buffer{}
+		if err := source.CreateBundle([]string{refName}, bundle); err != nil {
+			t.Fatal(err)
+		}
+
+		target := createTargetRepository(t)
+		err := target.ImportBundle(context.Background(), bytes.NewReader(bundle.Bytes()))
+		assert.Nil(t, err)
+
+		for _, name := range []string{rsl.Ref, policy.PolicyRef, refName} {
+			assertLocalAndRemoteRefsMatch(t, target.r, source.r, name)
+		}
+		err = target.VerifyRef(context.Background(), refName, true)
+		assert.Nil(t, err)
+
+		// The source's later changes can be imported using a new bundle
+		commitIDs = common.AddNTestCommitsToSpecifiedRef(t, source.r, refName, 1, gpgKeyName)
+		common.CreateTestRSLReferenceEntryCommit(t, source.r, rsl.NewReferenceEntry(refName, commitIDs[0]), gpgKeyName)
+
+		bundle = &bytes.Buffer{}
+		if err := source.CreateBundle([]string{refName}, bundle); err != nil {
+			t.Fatal(err)
+		}
+
+		err = target.ImportBundle(context.Background(), bytes.NewReader(bundle.Bytes()))
+		assert.Nil(t, err)
+		assertLocalAndRemoteRefsMatch(t, target.r, source.r, refName)
+	})
+
+	t.Run("tampered bundle", func(t *testing.T) {
+		source := createTestRepositoryWithPolicy(t, "")
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, source.r, refName, 1, gpgKeyName)
+		common.CreateTestRSLReferenceEntryCommit(t, source.r, rsl.NewReferenceEntry(refName, commitIDs[0]), gpgKeyName)
+
+		bundle := &bytes.Buffer{}
+		if err := source.CreateBundle([]string{refName}, bundle); err != nil {
+			t.Fatal(err)
+		}
+		target := createTargetRepository(t)
+		if err := target.ImportBundle(context.Background(), bytes.NewReader(bundle.Bytes())); err != nil {
+			t.Fatal(err)
+		}
+		previousRSLTip, err := gitinterface.GetTip(target.r, rsl.Ref)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// The RSL entry for the new commit is not signed by an authorized key
+		commitIDs = common.AddNTestCommitsToSpecifiedRef(t, source.r, refName, 1, gpgKeyName)
+		if err := rsl.NewReferenceEntry(refName, commitIDs[0]).Commit(source.r, false); err != nil {
+			t.Fatal(err)
+		}
+
+		bundle = &bytes.Buffer{}
+		if err := source.CreateBundle([]string{refName}, bundle); err != nil {
+			t.Fatal(err)
+		}
+
+		err = target.ImportBundle(context.Background(), bytes.NewReader(bundle.Bytes()))
+		assert.ErrorIs(t, err, ErrBundleVerificationFailed)
+		assert.ErrorIs(t, err, policy.ErrUnauthorizedSignature)
+
+		// The target is unchanged
+		rslTip, err := gitinterface.GetTip(target.r, rsl.Ref)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, previousRSLTip, rslTip)
+		_, err = target.r.CommitObject(commitIDs[0])
+		assert.ErrorIs(t, err, plumbing.ErrObjectNotFound)
+	})
+
+	t.Run("bundle rewriting local RSL", func(t *testing.T) {
+		source := createTestRepositoryWithPolicy(t, "")
+		bundle := &bytes.Buffer{}
+		if err := source.CreateBundle(nil, bundle); err != nil {
+			t.Fatal(err)
+		}
+
+		// The target's RSL has an entry that is not in the bundle
+		target := createTestRepositoryWithPolicy(t, "")
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, target.r, refName, 1, gpgKeyName)
+		common.CreateTestRSLReferenceEntryCommit(t, target.r, rsl.NewReferenceEntry(refName, commitIDs[0]), gpgKeyName)
+
+		err := target.ImportBundle(context.Background(), bytes.NewReader(bundle.Bytes()))
+		assert.ErrorIs(t, err, ErrBundleRSLNotFastForward)
+	})
+
+	t.Run("bundle rewriting local ref", func(t *testing.T) {
+		source := createTestRepositoryWithPolicy(t, "")
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, source.r, refName, 1, gpgKeyName)
+		common.CreateTestRSLReferenceEntryCommit(t, source.r, rsl.NewReferenceEntry(refName, commitIDs[0]), gpgKeyName)
+
+		bundle := &bytes.Buffer{}
+		if err := source.CreateBundle([]string{refName}, bundle); err != nil {
+			t.Fatal(err)
+		}
+
+		// The target's ref has a commit that is not in the bundle
+		target := createTargetRepository(t)
+		localCommitIDs := common.AddNTestCommitsToSpecifiedRef(t, target.r, refName, 2, gpgKeyName)
+
+		err := target.ImportBundle(context.Background(), bytes.NewReader(bundle.Bytes()))
+		assert.ErrorIs(t, err, ErrBundleRefNotFastForward)
+
+		// The target is unchanged
+		tip, err := gitinterface.GetTip(target.r, refName)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, localCommitIDs[1], tip)
+		_, err = gitinterface.GetTip(target.r, rsl.Ref)
+		assert.ErrorIs(t, err, gitinterface.ErrReferenceNotFound)
+	})
+
+	t.Run("bundle without gittuf refs", func(t *testing.T) {
+		source := createTestRepositoryWithPolicy(t, "")
+		common.AddNTestCommitsToSpecifiedRef(t, source.r, refName, 1, gpgKeyName)
+
+		bundle := &bytes.Buffer{}
+		if err := gitinterface.CreateBundle(source.r, []string{refName}, bundle); err != nil {
+			t.Fatal(err)
+		}
+
+		target := createTargetRepository(t)
+		err := target.ImportBundle(context.Background(), bytes.NewReader(bundle.Bytes()))
+		assert.ErrorIs(t, err, ErrBundleMissingGittufRefs)
+	})
+}
@@ -3,10 +3,17 @@
 package repository
 
 import (
+	"context"
+	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/signerverifier"
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
 	"github.com/gittuf/gittuf/internal/third_party/go-git"
 	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing"
+	"github.com/gittuf/gittuf/internal/tuf"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -27,3 +34,35 @@ func assertLocalAndRemoteRefsMatch(t *testing.T, localRepo, remoteRepo *git.Repo
 
 	assert.Equal(t, localRef.Hash(), remoteRef.Hash())
 }
+
+// replaceTestRootOfTrust records a new policy in the repository whose root of
+// trust is the targets key rather than the root key. As the new root metadata
+// is not signed by the previous root key, it is not a valid policy transition.
+func replaceTestRootOfTrust(t *testing.T, repo *Repository) {
+	t.Helper()
+
+	keyBytes, err := os.ReadFile(filepath.Join("test-data", "targets"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := tuf.LoadKeyFromBytes(keyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(keyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	env, err := dsse.CreateEnvelope(policy.InitializeRootMetadata(key))
+	if err != nil {
+		t.Fatal(err)
+	}
+	env, err = dsse.SignEnvelope(context.Background(), env, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state := &policy.State{RootPublicKeys: []*tuf.Key{key}, RootEnvelope: env}
+	if err := state.Commit(context.Background(), repo.r, "Replace root of trust", false); err != nil {
+		t.Fatal(err)
+	}
+}
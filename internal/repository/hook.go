@@ -0,0 +1,321 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/gittuf/gittuf/internal/third_party/go-git"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing/storer"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/storage"
+)
+
+var (
+	ErrInvalidHookInput     = errors.New("invalid pre-receive hook input")
+	ErrRSLUpdateRejected    = errors.New("update to RSL rejected")
+	ErrRefDeletionNotInRSL  = errors.New("deletion of ref is not recorded in the RSL")
+	ErrRSLDeletionForbidden = errors.New("RSL cannot be deleted")
+)
+
+// RefUpdate describes a proposed update to a ref, as presented to a
+// pre-receive hook. OldID is the zero hash when the ref is created, and NewID
+// is the zero hash when the ref is deleted.
+type RefUpdate struct {
+	RefName string
+	OldID   plumbing.Hash
+	NewID   plumbing.Hash
+}
+
+// IsDeletion indicates if the update deletes the ref.
+func (u *RefUpdate) IsDeletion() bool {
+	return u.NewID.IsZero()
+}
+
+// RefUpdateResult records whether a proposed ref update was accepted. If the
+// update was rejected, Reason explains why.
+type RefUpdateResult struct {
+	*RefUpdate
+	Accepted bool
+	Reason   string
+}
+
+// PreReceiveOptions contains the optional parameters of VerifyRefUpdates.
+type PreReceiveOptions struct {
+	Cache *policy.VerificationCache
+}
+
+// PreReceiveOption is used to configure VerifyRefUpdates.
+type PreReceiveOption func(*PreReceiveOptions)
+
+// WithVerificationCache configures VerifyRefUpdates to use the specified cache
+// when verifying refs, so that a long running server can skip verifying RSL
+// entries it has already verified for previous pushes.
+func WithVerificationCache(cache *policy.VerificationCache) PreReceiveOption {
+	return func(o *PreReceiveOptions) {
+		o.Cache = cache
+	}
+}
+
+// ParseRefUpdates parses the input of a pre-receive hook. Each line of the
+// input contains the old object ID, the new object ID, and the name of the ref
+// being updated, separated by spaces.
+func ParseRefUpdates(input io.Reader) ([]*RefUpdate, error) {
+	updates := []*RefUpdate{}
+
+	scanner := bufio.NewScanner(input)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) == 0 {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("%w: expected '<old-id> <new-id> <ref>', got '%s'", ErrInvalidHookInput, line)
+		}
+		if !plumbing.IsHash(fields[0]) || !plumbing.IsHash(fields[1]) {
+			return nil, fmt.Errorf("%w: invalid object ID in '%s'", ErrInvalidHookInput, line)
+		}
+		if !strings.HasPrefix(fields[2], gitinterface.RefPrefix) {
+			return nil, fmt.Errorf("%w: '%s' is not a fully qualified ref", ErrInvalidHookInput, fields[2])
+		}
+
+		updates = append(updates, &RefUpdate{
+			RefName: fields[2],
+			OldID:   plumbing.NewHash(fields[0]),
+			NewID:   plumbing.NewHash(fields[1]),
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return updates, nil
+}
+
+// VerifyPreReceive parses the input of a pre-receive hook and verifies each of
+// the proposed ref updates. See VerifyRefUpdates for details.
+func (r *Repository) VerifyPreReceive(ctx context.Context, input io.Reader, opts ...PreReceiveOption) ([]*RefUpdateResult, error) {
+	updates, err := ParseRefUpdates(input)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.VerifyRefUpdates(ctx, updates, opts...)
+}
+
+// VerifyRefUpdates verifies the proposed ref updates against the policy and
+// the RSL as they would be if all the updates were applied. It is meant to be
+// invoked by a pre-receive hook, when the pushed objects are available in the
+// repository but the refs have not been updated yet. An update to the RSL must
+// be a fast-forward, and every new RSL entry is verified in order starting
+// from the policy the server currently trusts. Pushed policy entries must be
+// valid transitions from that policy, so a push cannot replace the root of
+// trust to approve its own changes. Every other update must be recorded by the
+// latest RSL entry for the ref, and that entry must be verified by the latest
+// policy.
+//
+// A result is returned for each update in the order they were specified. As
+// the updates are verified against the proposed RSL, every update is rejected
+// without further verification if the update to the RSL or a pushed policy is
+// rejected. An update is also rejected if any new RSL entry for its ref fails
+// verification. The returned error is only set when the verification could not
+// be performed.
+func (r *Repository) VerifyRefUpdates(ctx context.Context, updates []*RefUpdate, opts ...PreReceiveOption) ([]*RefUpdateResult, error) {
+	options := &PreReceiveOptions{}
+	for _, fn := range opts {
+		fn(options)
+	}
+	if options.Cache == nil {
+		options.Cache = policy.NewVerificationCache()
+	}
+
+	results := make([]*RefUpdateResult, 0, len(updates))
+
+	proposedRefs := map[plumbing.ReferenceName]plumbing.Hash{}
+	for _, update := range updates {
+		proposedRefs[plumbing.ReferenceName(update.RefName)] = update.NewID
+	}
+
+	// rejectAll rejects every update, as all of them are verified against
+	// the proposed RSL and policy
+	rejectAll := func(err error) []*RefUpdateResult {
+		for _, update := range updates {
+			results = append(results, &RefUpdateResult{RefUpdate: update, Reason: err.Error()})
+		}
+		return results
+	}
+
+	var rslUpdate *RefUpdate
+	for _, update := range updates {
+		if update.RefName != rsl.Ref {
+			continue
+		}
+
+		if err := r.verifyRSLUpdate(update); err != nil {
+			return rejectAll(err), nil
+		}
+		rslUpdate = update
+	}
+
+	proposedRepo, err := git.Open(&proposedRefsStorer{Storer: r.r.Storer, refs: proposedRefs}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	entryErrs := map[string]error{}
+	if rslUpdate != nil {
+		entryErrs, err = policy.VerifyNewEntries(ctx, proposedRepo, rslUpdate.OldID, rslUpdate.NewID)
+		if err != nil {
+			return rejectAll(fmt.Errorf("%w: %w", ErrRSLUpdateRejected, err)), nil
+		}
+	}
+
+	for _, update := range updates {
+		if update.RefName == rsl.Ref {
+			results = append(results, &RefUpdateResult{RefUpdate: update, Accepted: true})
+			continue
+		}
+
+		if err, failed := entryErrs[update.RefName]; failed {
+			results = append(results, &RefUpdateResult{RefUpdate: update, Reason: err.Error()})
+			continue
+		}
+
+		if err := verifyRefUpdate(ctx, proposedRepo, update, options.Cache); err != nil {
+			results = append(results, &RefUpdateResult{RefUpdate: update, Reason: err.Error()})
+			continue
+		}
+
+		results = append(results, &RefUpdateResult{RefUpdate: update, Accepted: true})
+	}
+
+	return results, nil
+}
+
+// verifyRSLUpdate checks that the proposed update to the RSL does not delete
+// it or rewrite any of its existing entries.
+func (r *Repository) verifyRSLUpdate(update *RefUpdate) error {
+	if update.IsDeletion() {
+		return ErrRSLDeletionForbidden
+	}
+	if update.OldID.IsZero() {
+		return nil
+	}
+
+	oldTip, err := r.r.CommitObject(update.OldID)
+	if err != nil {
+		return err
+	}
+	knows, err := gitinterface.KnowsCommit(r.r, update.NewID, oldTip)
+	if err != nil {
+		return err
+	}
+	if !knows {
+		return fmt.Errorf("%w: '%s' is not a descendant of '%s'", ErrRSLUpdateRejected, update.NewID.String(), update.OldID.String())
+	}
+
+	return nil
+}
+
+// verifyRefUpdate verifies a proposed update to a ref other than the RSL using
+// the repository with the proposed refs.
+func verifyRefUpdate(ctx context.Context, proposedRepo *git.Repository, update *RefUpdate, cache *policy.VerificationCache) error {
+	if update.IsDeletion() {
+		latestEntry, _, err := rsl.GetLatestReferenceEntryForRef(proposedRepo, update.RefName)
+		if err != nil {
+			return err
+		}
+		if !latestEntry.IsDeletion() {
+			return fmt.Errorf("%w: '%s'", ErrRefDeletionNotInRSL, update.RefName)
+		}
+	}
+
+	return policy.VerifyRefWithCache(ctx, proposedRepo, update.RefName, cache)
+}
+
+// proposedRefsStorer overlays the proposed values of refs on the repository's
+// storage, so that the repository can be verified as it would be after the
+// refs are updated without modifying it. A zero hash in the overlay indicates
+// the ref is deleted. Changes made to refs through the storer are recorded in
+// the overlay rather than in the repository's storage.
+type proposedRefsStorer struct {
+	storage.Storer
+	refs map[plumbing.ReferenceName]plumbing.Hash
+}
+
+var errProposedSymbolicRef = errors.New("symbolic refs cannot be updated in the proposed view of the repository")
+
+func (s *proposedRefsStorer) Reference(name plumbing.ReferenceName) (*plumbing.Reference, error) {
+	hash, has := s.refs[name]
+	if !has {
+		return s.Storer.Reference(name)
+	}
+	if hash.IsZero() {
+		return nil, plumbing.ErrReferenceNotFound
+	}
+
+	return plumbing.NewHashReference(name, hash), nil
+}
+
+func (s *proposedRefsStorer) IterReferences() (storer.ReferenceIter, error) {
+	iter, err := s.Storer.IterReferences()
+	if err != nil {
+		return nil, err
+	}
+
+	refs := []*plumbing.Reference{}
+	if err := iter.ForEach(func(ref *plumbing.Reference) error {
+		if _, has := s.refs[ref.Name()]; !has {
+			refs = append(refs, ref)
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	for name, hash := range s.refs {
+		if !hash.IsZero() {
+			refs = append(refs, plumbing.NewHashReference(name, hash))
+		}
+	}
+
+	return storer.NewReferenceSliceIter(refs), nil
+}
+
+func (s *proposedRefsStorer) SetReference(ref *plumbing.Reference) error {
+	if ref.Type() != plumbing.HashReference {
+		return errProposedSymbolicRef
+	}
+
+	s.refs[ref.Name()] = ref.Hash()
+	return nil
+}
+
+func (s *proposedRefsStorer) CheckAndSetReference(newRef, oldRef *plumbing.Reference) error {
+	if oldRef != nil {
+		currentRef, err := s.Reference(oldRef.Name())
+		if err != nil {
+			return err
+		}
+		if currentRef.Hash() != oldRef.Hash() {
+			return storage.ErrReferenceHasChanged
+		}
+	}
+
+	return s.SetReference(newRef)
+}
+
+func (s *proposedRefsStorer) RemoveReference(name plumbing.ReferenceName) error {
+	s.refs[name] = plumbing.ZeroHash
+	return nil
+}
@@ -0,0 +1,348 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/common"
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRefUpdates(t *testing.T) {
+	oldID := "1111111111111111111111111111111111111111"
+	newID := "2222222222222222222222222222222222222222"
+
+	t.Run("valid input", func(t *testing.T) {
+		input := fmt.Sprintf("%s %s refs/heads/main\n\n%s %s %s\n", oldID, newID, plumbing.ZeroHash.String(), newID, rsl.Ref)
+
+		updates, err := ParseRefUpdates(strings.NewReader(input))
+		assert.Nil(t, err)
+		assert.Equal(t, []*RefUpdate{
+			{RefName: "refs/heads/main", OldID: plumbing.NewHash(oldID), NewID: plumbing.NewHash(newID)},
+			{RefName: rsl.Ref, OldID: plumbing.ZeroHash, NewID: plumbing.NewHash(newID)},
+		}, updates)
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		inputs := []string{
+			fmt.Sprintf("%s refs/heads/main", oldID),
+			fmt.Sprintf("%s abcd refs/heads/main", oldID),
+			fmt.Sprintf("%s %s main", oldID, newID),
+		}
+		for _, input := range inputs {
+			_, err := ParseRefUpdates(strings.NewReader(input))
+			assert.ErrorIs(t, err, ErrInvalidHookInput)
+		}
+	})
+}
+
+func TestVerifyPreReceive(t *testing.T) {
+	refName := "refs/heads/main"
+
+	// receive simulates the state of the repository during a push: the
+	// objects created by push are available, but the refs are restored to
+	// their values before push was invoked. The hook input is returned.
+	receive := func(t *testing.T, repo *Repository, refNames []string, push func()) string {
+		t.Helper()
+
+		oldIDs := map[string]plumbing.Hash{}
+		for _, name := range refNames {
+			ref, err := repo.r.Reference(plumbing.ReferenceName(name), true)
+			if err == nil {
+				oldIDs[name] = ref.Hash()
+			}
+		}
+
+		push()
+
+		lines := []string{}
+		for _, name := range refNames {
+			newID := plumbing.ZeroHash
+			ref, err := repo.r.Reference(plumbing.ReferenceName(name), true)
+			if err == nil {
+				newID = ref.Hash()
+			}
+			lines = append(lines, fmt.Sprintf("%s %s %s", oldIDs[name].String(), newID.String(), name))
+
+			if oldID, has := oldIDs[name]; has {
+				if err := repo.r.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(name), oldID)); err != nil {
+					t.Fatal(err)
+				}
+			} else if err := repo.r.Storer.RemoveReference(plumbing.ReferenceName(name)); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		return strings.Join(lines, "\n") + "\n"
+	}
+
+	// apply updates the refs as the server would after the hook accepts the
+	// push
+	apply := func(t *testing.T, repo *Repository, results []*RefUpdateResult) {
+		t.Helper()
+
+		for _, result := range results {
+			var err error
+			if result.IsDeletion() {
+				err = repo.r.Storer.RemoveReference(plumbing.ReferenceName(result.RefName))
+			} else {
+				err = repo.r.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(result.RefName), result.NewID))
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	// pushMain creates a commit in main and an RSL entry for it signed with
+	// the trusted key
+	pushMain := func(t *testing.T, repo *Repository) func() {
+		return func() {
+			if err := repo.r.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(refName), plumbing.ZeroHash)); err != nil {
+				t.Fatal(err)
+			}
+			commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo.r, refName, 1, gpgKeyName)
+			common.CreateTestRSLReferenceEntryCommit(t, repo.r, rsl.NewReferenceEntry(refName, commitIDs[0]), gpgKeyName)
+		}
+	}
+
+	t.Run("successful push", func(t *testing.T) {
+		repo := createTestRepositoryWithPolicy(t, "")
+
+		input := receive(t, repo, []string{refName, rsl.Ref}, pushMain(t, repo))
+
+		results, err := repo.VerifyPreReceive(context.Background(), strings.NewReader(input))
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(results))
+		for _, result := range results {
+			assert.True(t, result.Accepted, result.Reason)
+		}
+
+		// The repository's refs are unchanged by the verification
+		_, err = repo.r.Reference(plumbing.ReferenceName(refName), true)
+		assert.ErrorIs(t, err, plumbing.ErrReferenceNotFound)
+
+		// The verification cache can be shared across pushes
+		cache := policy.NewVerificationCache()
+		results, err = repo.VerifyPreReceive(context.Background(), strings.NewReader(input), WithVerificationCache(cache))
+		assert.Nil(t, err)
+		assert.True(t, results[0].Accepted, results[0].Reason)
+	})
+
+	t.Run("push without RSL entry", func(t *testing.T) {
+		repo := createTestRepositoryWithPolicy(t, "")
+
+		input := receive(t, repo, []string{refName}, func() {
+			if err := repo.r.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(refName), plumbing.ZeroHash)); err != nil {
+				t.Fatal(err)
+			}
+			common.AddNTestCommitsToSpecifiedRef(t, repo.r, refName, 1, gpgKeyName)
+		})
+
+		results, err := repo.VerifyPreReceive(context.Background(), strings.NewReader(input))
+		assert.Nil(t, err)
+		assert.False(t, results[0].Accepted)
+		assert.Contains(t, results[0].Reason, rsl.ErrRSLEntryNotFound.Error())
+	})
+
+	t.Run("push with unauthorized RSL entry", func(t *testing.T) {
+		repo := createTestRepositoryWithPolicy(t, "")
+
+		input := receive(t, repo, []string{refName, rsl.Ref}, func() {
+			if err := repo.r.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(refName), plumbing.ZeroHash)); err != nil {
+				t.Fatal(err)
+			}
+			commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo.r, refName, 1, gpgKeyName)
+			if err := rsl.NewReferenceEntry(refName, commitIDs[0]).Commit(repo.r, false); err != nil {
+				t.Fatal(err)
+			}
+		})
+
+		results, err := repo.VerifyPreReceive(context.Background(), strings.NewReader(input))
+		assert.Nil(t, err)
+		assert.False(t, results[0].Accepted)
+		assert.Contains(t, results[0].Reason, policy.ErrUnauthorizedSignature.Error())
+		assert.True(t, results[1].Accepted, results[1].Reason)
+	})
+
+	t.Run("push with unauthorized entry followed by authorized entry", func(t *testing.T) {
+		repo := createTestRepositoryWithPolicy(t, "")
+
+		input := receive(t, repo, []string{refName, rsl.Ref}, func() {
+			if err := repo.r.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(refName), plumbing.ZeroHash)); err != nil {
+				t.Fatal(err)
+			}
+			commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo.r, refName, 1, gpgKeyName)
+			if err := rsl.NewReferenceEntry(refName, commitIDs[0]).Commit(repo.r, false); err != nil {
+				t.Fatal(err)
+			}
+			common.CreateTestRSLReferenceEntryCommit(t, repo.r, rsl.NewReferenceEntry(refName, commitIDs[0]), gpgKeyName)
+		})
+
+		results, err := repo.VerifyPreReceive(context.Background(), strings.NewReader(input))
+		assert.Nil(t, err)
+		assert.False(t, results[0].Accepted)
+		assert.Contains(t, results[0].Reason, policy.ErrUnauthorizedSignature.Error())
+	})
+
+	t.Run("push replacing root of trust", func(t *testing.T) {
+		repo := createTestRepositoryWithPolicy(t, "")
+
+		input := receive(t, repo, []string{refName, policy.PolicyRef, rsl.Ref}, func() {
+			// The pusher replaces the root of trust with one they control,
+			// which does not protect main
+			replaceTestRootOfTrust(t, repo)
+
+			if err := repo.r.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(refName), plumbing.ZeroHash)); err != nil {
+				t.Fatal(err)
+			}
+			commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo.r, refName, 1, gpgKeyName)
+			if err := rsl.NewReferenceEntry(refName, commitIDs[0]).Commit(repo.r, false); err != nil {
+				t.Fatal(err)
+			}
+		})
+
+		results, err := repo.VerifyPreReceive(context.Background(), strings.NewReader(input))
+		assert.Nil(t, err)
+		assert.Equal(t, 3, len(results))
+		for _, result := range results {
+			assert.False(t, result.Accepted)
+			assert.Contains(t, result.Reason, policy.ErrUnauthorizedPolicyChange.Error())
+		}
+	})
+
+	t.Run("push rewriting RSL", func(t *testing.T) {
+		repo := createTestRepositoryWithPolicy(t, "")
+
+		input := receive(t, repo, []string{refName, rsl.Ref}, pushMain(t, repo))
+		results, err := repo.VerifyPreReceive(context.Background(), strings.NewReader(input))
+		if err != nil {
+			t.Fatal(err)
+		}
+		apply(t, repo, results)
+
+		// Push main along with an RSL that drops the latest entry
+		latestEntry, err := rsl.GetLatestEntry(repo.r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		latestEntryCommit, err := repo.r.CommitObject(latestEntry.GetID())
+		if err != nil {
+			t.Fatal(err)
+		}
+		rewrittenTip := latestEntryCommit.ParentHashes[0]
+		mainTip, err := repo.r.Reference(plumbing.ReferenceName(refName), true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		input = fmt.Sprintf("%s %s %s\n%s %s %s\n", mainTip.Hash().String(), mainTip.Hash().String(), refName, latestEntry.GetID().String(), rewrittenTip.String(), rsl.Ref)
+
+		results, err = repo.VerifyPreReceive(context.Background(), strings.NewReader(input))
+		assert.Nil(t, err)
+		for _, result := range results {
+			assert.False(t, result.Accepted)
+			assert.Contains(t, result.Reason, ErrRSLUpdateRejected.Error())
+		}
+	})
+
+	t.Run("deletion", func(t *testing.T) {
+		repo := createTestRepositoryWithPolicy(t, "")
+
+		input := receive(t, repo, []string{refName, rsl.Ref}, pushMain(t, repo))
+		results, err := repo.VerifyPreReceive(context.Background(), strings.NewReader(input))
+		if err != nil {
+			t.Fatal(err)
+		}
+		apply(t, repo, results)
+
+		// Deleting main without recording it in the RSL is rejected
+		input = receive(t, repo, []string{refName}, func() {
+			if err := repo.r.Storer.RemoveReference(plumbing.ReferenceName(refName)); err != nil {
+				t.Fatal(err)
+			}
+		})
+		results, err = repo.VerifyPreReceive(context.Background(), strings.NewReader(input))
+		assert.Nil(t, err)
+		assert.False(t, results[0].Accepted)
+		assert.Contains(t, results[0].Reason, ErrRefDeletionNotInRSL.Error())
+
+		// Deleting main with a deletion entry signed by the trusted key is
+		// accepted
+		input = receive(t, repo, []string{refName, rsl.Ref}, func() {
+			if err := repo.r.Storer.RemoveReference(plumbing.ReferenceName(refName)); err != nil {
+				t.Fatal(err)
+			}
+			common.CreateTestRSLReferenceEntryCommit(t, repo.r, rsl.NewDeletionEntry(refName), gpgKeyName)
+		})
+		results, err = repo.VerifyPreReceive(context.Background(), strings.NewReader(input))
+		assert.Nil(t, err)
+		for _, result := range results {
+			assert.True(t, result.Accepted, result.Reason)
+		}
+	})
+}
+
+func TestProposedRefsStorer(t *testing.T) {
+	repo := createTestRepositoryWithPolicy(t, "")
+
+	mainRef := plumbing.ReferenceName("refs/heads/main")
+	featureRef := plumbing.ReferenceName("refs/heads/feature")
+	commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo.r, string(mainRef), 2, gpgKeyName)
+
+	proposed := &proposedRefsStorer{
+		Storer: repo.r.Storer,
+		refs: map[plumbing.ReferenceName]plumbing.Hash{
+			mainRef:    plumbing.ZeroHash,
+			featureRef: commitIDs[0],
+		},
+	}
+
+	// Deleted refs are not found and proposed refs are added
+	_, err := proposed.Reference(mainRef)
+	assert.ErrorIs(t, err, plumbing.ErrReferenceNotFound)
+	ref, err := proposed.Reference(featureRef)
+	assert.Nil(t, err)
+	assert.Equal(t, commitIDs[0], ref.Hash())
+
+	iter, err := proposed.IterReferences()
+	if err != nil {
+		t.Fatal(err)
+	}
+	refs := map[plumbing.ReferenceName]plumbing.Hash{}
+	if err := iter.ForEach(func(ref *plumbing.Reference) error {
+		refs[ref.Name()] = ref.Hash()
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	assert.NotContains(t, refs, mainRef)
+	assert.Equal(t, commitIDs[0], refs[featureRef])
+	assert.Contains(t, refs, plumbing.ReferenceName(rsl.Ref))
+
+	// Updates are only applied to the proposed view
+	err = proposed.CheckAndSetReference(plumbing.NewHashReference(featureRef, commitIDs[1]), plumbing.NewHashReference(featureRef, commitIDs[1]))
+	assert.ErrorIs(t, err, storage.ErrReferenceHasChanged)
+	err = proposed.CheckAndSetReference(plumbing.NewHashReference(featureRef, commitIDs[1]), plumbing.NewHashReference(featureRef, commitIDs[0]))
+	assert.Nil(t, err)
+	ref, err = proposed.Reference(featureRef)
+	assert.Nil(t, err)
+	assert.Equal(t, commitIDs[1], ref.Hash())
+
+	assert.Nil(t, proposed.RemoveReference(featureRef))
+	_, err = proposed.Reference(featureRef)
+	assert.ErrorIs(t, err, plumbing.ErrReferenceNotFound)
+
+	_, err = repo.r.Reference(featureRef, true)
+	assert.ErrorIs(t, err, plumbing.ErrReferenceNotFound)
+	ref, err = repo.r.Reference(mainRef, true)
+	assert.Nil(t, err)
+	assert.Equal(t, commitIDs[1], ref.Hash())
+}
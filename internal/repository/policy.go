@@ -4,23 +4,33 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 
 	"github.com/gittuf/gittuf/internal/gitinterface"
 	"github.com/gittuf/gittuf/internal/policy"
 	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
 )
 
 var (
-	ErrPushingPolicy = errors.New("unable to push policy")
-	ErrPullingPolicy = errors.New("unable to pull policy")
+	ErrPushingPolicy       = errors.New("unable to push policy")
+	ErrPullingPolicy       = errors.New("unable to pull policy")
+	ErrNoMetadataEnvelopes = errors.New("no signed metadata envelopes specified")
 )
 
-// PushPolicy pushes the local gittuf policy to the specified remote. As this
-// push defaults to fast-forward only, divergent policy states are detected.
-// Note that this also pushes the RSL as the policy cannot change without an
-// update to the RSL.
+// PushPolicy pushes the local gittuf policy to the specified remote. Before
+// pushing, the remote's gittuf refs are checked using CheckRemoteForPush so
+// that divergent policy states are detected rather than clobbered. Note that
+// this also pushes the RSL as the policy cannot change without an update to the
+// RSL.
 func (r *Repository) PushPolicy(ctx context.Context, remoteName string) error {
+	if err := r.CheckRemoteForPush(ctx, remoteName); err != nil {
+		return errors.Join(ErrPushingPolicy, err)
+	}
+
 	if err := gitinterface.Push(ctx, r.r, remoteName, []string{policy.PolicyRef, rsl.Ref}); err != nil {
 		return errors.Join(ErrPushingPolicy, err)
 	}
@@ -38,3 +48,50 @@ func (r *Repository) PullPolicy(ctx context.Context, remoteName string) error {
 
 	return nil
 }
+
+// ApplySignedPolicyMetadata records the metadata for the specified role in the
+// policy after merging the signatures in each of the envelopes. This supports
+// offline co-signing ceremonies: the unsigned or partially signed envelope is
+// exported and each signer independently signs their copy using
+// dsse.SignEnvelope. The signed copies are then merged and applied here. The
+// policy is only updated if the merged metadata meets its threshold.
+func (r *Repository) ApplySignedPolicyMetadata(ctx context.Context, roleName string, envelopesBytes [][]byte, signCommit bool) error {
+	if len(envelopesBytes) == 0 {
+		return ErrNoMetadataEnvelopes
+	}
+
+	envelopes := make([]*sslibdsse.Envelope, 0, len(envelopesBytes))
+	for _, envBytes := range envelopesBytes {
+		env := &sslibdsse.Envelope{}
+		if err := json.Unmarshal(envBytes, env); err != nil {
+			return err
+		}
+		envelopes = append(envelopes, env)
+	}
+
+	env, err := dsse.MergeEnvelopes(envelopes[0], envelopes[1:]...)
+	if err != nil {
+		return err
+	}
+
+	state, err := policy.LoadCurrentState(ctx, r.r)
+	if err != nil {
+		return err
+	}
+
+	switch roleName {
+	case policy.RootRoleName:
+		state.RootEnvelope = env
+	case policy.TargetsRoleName:
+		state.TargetsEnvelope = env
+	default:
+		if state.DelegationEnvelopes == nil {
+			state.DelegationEnvelopes = map[string]*sslibdsse.Envelope{}
+		}
+		state.DelegationEnvelopes[roleName] = env
+	}
+
+	commitMessage := fmt.Sprintf("Apply co-signed metadata for '%s'", roleName)
+
+	return state.Commit(ctx, r.r, commitMessage, signCommit)
+}
@@ -4,15 +4,20 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
 	"testing"
 
 	"github.com/gittuf/gittuf/internal/policy"
 	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/gittuf/gittuf/internal/signerverifier"
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
 	"github.com/gittuf/gittuf/internal/third_party/go-git"
 	"github.com/gittuf/gittuf/internal/third_party/go-git/config"
 	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing"
 	"github.com/gittuf/gittuf/internal/third_party/go-git/storage/memory"
+	"github.com/gittuf/gittuf/internal/tuf"
 	"github.com/go-git/go-billy/v5/memfs"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -134,3 +139,86 @@ func TestPullPolicy(t *testing.T) {
 		assert.ErrorIs(t, err, ErrPullingPolicy)
 	})
 }
+
+func TestApplySignedPolicyMetadata(t *testing.T) {
+	r, targetsKeyBytes := createTestRepositoryWithTargets(t)
+
+	targetsKey, err := tuf.LoadKeyFromBytes(targetsKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// exportUnsignedTargets returns the exported, unsigned targets metadata
+	// with a new rule
+	exportUnsignedTargets := func(t *testing.T) []byte {
+		t.Helper()
+
+		state, err := policy.LoadCurrentState(context.Background(), r.r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		targetsMetadata, err := state.GetTargetsMetadata(policy.TargetsRoleName)
+		if err != nil {
+			t.Fatal(err)
+		}
+		targetsMetadata, err = policy.AddOrUpdateDelegation(targetsMetadata, "protect-main", []*tuf.Key{targetsKey}, []string{"git:refs/heads/main"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		targetsMetadata.SetVersion(targetsMetadata.Version + 1)
+
+		env, err := dsse.CreateEnvelope(targetsMetadata)
+		if err != nil {
+			t.Fatal(err)
+		}
+		envBytes, err := json.Marshal(env)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return envBytes
+	}
+
+	t.Run("unsigned metadata", func(t *testing.T) {
+		err := r.ApplySignedPolicyMetadata(context.Background(), policy.TargetsRoleName, [][]byte{exportUnsignedTargets(t)}, false)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("no envelopes", func(t *testing.T) {
+		err := r.ApplySignedPolicyMetadata(context.Background(), policy.TargetsRoleName, nil, false)
+		assert.ErrorIs(t, err, ErrNoMetadataEnvelopes)
+	})
+
+	t.Run("signed offline", func(t *testing.T) {
+		unsignedBytes := exportUnsignedTargets(t)
+
+		// The signer signs their copy of the envelope offline
+		env := &sslibdsse.Envelope{}
+		if err := json.Unmarshal(unsignedBytes, env); err != nil {
+			t.Fatal(err)
+		}
+		signer, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(targetsKeyBytes)
+		if err != nil {
+			t.Fatal(err)
+		}
+		env, err = dsse.SignEnvelope(context.Background(), env, signer)
+		if err != nil {
+			t.Fatal(err)
+		}
+		signedBytes, err := json.Marshal(env)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = r.ApplySignedPolicyMetadata(context.Background(), policy.TargetsRoleName, [][]byte{unsignedBytes, signedBytes}, false)
+		assert.Nil(t, err)
+
+		state, err := policy.LoadCurrentState(context.Background(), r.r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		targetsMetadata, err := state.GetTargetsMetadata(policy.TargetsRoleName)
+		assert.Nil(t, err)
+		assert.Equal(t, "protect-main", targetsMetadata.Delegations.Roles[0].Name)
+	})
+}
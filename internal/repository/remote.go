@@ -0,0 +1,59 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"os"
+)
+
+const (
+	// DefaultRemoteName is the remote used for gittuf's refs when no other
+	// remote is configured.
+	DefaultRemoteName = "origin"
+
+	// RemoteEnvKey is the environment variable that can be used to override
+	// the remote configured for gittuf's refs.
+	RemoteEnvKey = "GITTUF_REMOTE"
+
+	remoteConfigSection = "gittuf"
+	remoteConfigOption  = "remote"
+)
+
+// SetGittufRemote records the remote that hosts gittuf's refs, such as the RSL
+// and policy, in the repository's Git config as `gittuf.remote`. The remote
+// must already be configured for the repository.
+func (r *Repository) SetGittufRemote(remoteName string) error {
+	if _, err := r.r.Remote(remoteName); err != nil {
+		return err
+	}
+
+	config, err := r.r.Config()
+	if err != nil {
+		return err
+	}
+
+	config.Raw.Section(remoteConfigSection).SetOption(remoteConfigOption, remoteName)
+
+	return r.r.SetConfig(config)
+}
+
+// GetGittufRemote returns the remote that hosts gittuf's refs. The remote set
+// using the GITTUF_REMOTE environment variable takes precedence over the one
+// recorded in the repository's Git config as `gittuf.remote`. If neither is
+// set, the default remote, origin, is returned.
+func (r *Repository) GetGittufRemote() (string, error) {
+	if remoteName := os.Getenv(RemoteEnvKey); len(remoteName) > 0 {
+		return remoteName, nil
+	}
+
+	config, err := r.r.Config()
+	if err != nil {
+		return "", err
+	}
+
+	if remoteName := config.Raw.Section(remoteConfigSection).Option(remoteConfigOption); len(remoteName) > 0 {
+		return remoteName, nil
+	}
+
+	return DefaultRemoteName, nil
+}
@@ -0,0 +1,75 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package repository
+
+import (
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/third_party/go-git"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/config"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/storage/memory"
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGittufRemote(t *testing.T) {
+	createTestRepositoryWithRemote := func(t *testing.T) *Repository {
+		t.Helper()
+
+		repo, err := git.Init(memory.NewStorage(), memfs.New())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := repo.CreateRemote(&config.RemoteConfig{Name: "upstream", URLs: []string{"https://example.com/repo.git"}}); err != nil {
+			t.Fatal(err)
+		}
+
+		return &Repository{r: repo}
+	}
+
+	t.Run("missing config falls back to default", func(t *testing.T) {
+		t.Setenv(RemoteEnvKey, "")
+		r := createTestRepositoryWithRemote(t)
+
+		remoteName, err := r.GetGittufRemote()
+		assert.Nil(t, err)
+		assert.Equal(t, DefaultRemoteName, remoteName)
+	})
+
+	t.Run("remote set in config", func(t *testing.T) {
+		t.Setenv(RemoteEnvKey, "")
+		r := createTestRepositoryWithRemote(t)
+
+		err := r.SetGittufRemote("upstream")
+		assert.Nil(t, err)
+
+		remoteName, err := r.GetGittufRemote()
+		assert.Nil(t, err)
+		assert.Equal(t, "upstream", remoteName)
+
+		// The remote's own config is retained
+		remote, err := r.r.Remote("upstream")
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"https://example.com/repo.git"}, remote.Config().URLs)
+	})
+
+	t.Run("environment variable overrides config", func(t *testing.T) {
+		t.Setenv(RemoteEnvKey, "mirror")
+		r := createTestRepositoryWithRemote(t)
+
+		if err := r.SetGittufRemote("upstream"); err != nil {
+			t.Fatal(err)
+		}
+
+		remoteName, err := r.GetGittufRemote()
+		assert.Nil(t, err)
+		assert.Equal(t, "mirror", remoteName)
+	})
+
+	t.Run("set unknown remote", func(t *testing.T) {
+		r := createTestRepositoryWithRemote(t)
+
+		err := r.SetGittufRemote("unknown")
+		assert.ErrorIs(t, err, git.ErrRemoteNotFound)
+	})
+}
@@ -4,27 +4,63 @@ package repository
 
 import (
 	"errors"
+	"fmt"
+	"path"
 
+	"github.com/gittuf/gittuf/internal/gitinterface"
 	"github.com/gittuf/gittuf/internal/policy"
 	"github.com/gittuf/gittuf/internal/rsl"
 	"github.com/gittuf/gittuf/internal/third_party/go-git"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing"
 )
 
 var (
 	ErrUnauthorizedKey    = errors.New("unauthorized key presented when updating gittuf metadata")
 	ErrCannotReinitialize = errors.New("cannot reinitialize metadata, it exists already")
+	ErrNotInitialized     = errors.New("gittuf namespaces are not initialized in the repository")
 )
 
 type Repository struct {
 	r *git.Repository
 }
 
-func LoadRepository() (*Repository, error) {
-	repo, err := git.PlainOpenWithOptions(".", &git.PlainOpenOptions{DetectDotGit: true})
+// New returns a Repository that performs gittuf operations on the specified
+// Git repository, allowing gittuf to be used with a repository that was already
+// opened. As the returned Repository is expected to be used with existing
+// gittuf metadata, New returns ErrNotInitialized if the repository does not
+// contain the RSL and policy refs. Repositories whose object format gittuf
+// was not built for are rejected with gitinterface.ErrUnsupportedObjectFormat.
+func New(repo *git.Repository) (*Repository, error) {
+	if err := gitinterface.CheckObjectFormat(repo); err != nil {
+		return nil, err
+	}
+
+	for _, refName := range []string{rsl.Ref, policy.PolicyRef} {
+		if _, err := repo.Reference(plumbing.ReferenceName(refName), true); err != nil {
+			if errors.Is(err, plumbing.ErrReferenceNotFound) {
+				return nil, fmt.Errorf("%w: '%s' not found", ErrNotInitialized, refName)
+			}
+			return nil, err
+		}
+	}
+
+	return &Repository{r: repo}, nil
+}
+
+// LoadRepository opens the Git repository at the specified path. The path may
+// be any directory within the repository's worktree. Unlike New, the gittuf
+// namespaces are not required to exist so that the returned Repository can be
+// used to initialize them.
+func LoadRepository(path string) (*Repository, error) {
+	repo, err := git.PlainOpenWithOptions(path, &git.PlainOpenOptions{DetectDotGit: true})
 	if err != nil {
 		return nil, err
 	}
 
+	if err := gitinterface.CheckObjectFormat(repo); err != nil {
+		return nil, err
+	}
+
 	return &Repository{
 		r: repo,
 	}, nil
@@ -38,6 +74,24 @@ func (r *Repository) InitializeNamespaces() error {
 	return policy.InitializeNamespace(r.r)
 }
 
+// CompactRefs packs the loose refs in the gittuf namespace, such as the RSL and
+// policy refs, and the gittuf refs tracking each configured remote into the
+// repository's packed-refs file. Refs that are being updated concurrently are
+// left loose. Other refs in the repository are not packed.
+func (r *Repository) CompactRefs() error {
+	remotes, err := r.r.Remotes()
+	if err != nil {
+		return err
+	}
+
+	prefixes := []string{rsl.GittufNamespacePrefix}
+	for _, remote := range remotes {
+		prefixes = append(prefixes, path.Dir(rsl.RemoteTrackerRef(remote.Config().Name))+"/")
+	}
+
+	return gitinterface.PackRefs(r.r, prefixes...)
+}
+
 func isKeyAuthorized(authorizedKeyIDs []string, keyID string) bool {
 	for _, k := range authorizedKeyIDs {
 		if k == keyID {
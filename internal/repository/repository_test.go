@@ -9,18 +9,64 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/gittuf/gittuf/internal/gitinterface"
 	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/rsl"
 	"github.com/gittuf/gittuf/internal/signerverifier/gpg"
 	"github.com/gittuf/gittuf/internal/third_party/go-git"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/config"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing"
 	"github.com/gittuf/gittuf/internal/third_party/go-git/storage/memory"
 	"github.com/go-git/go-billy/v5/memfs"
 	"github.com/stretchr/testify/assert"
 )
 
 func TestLoadRepository(t *testing.T) {
-	repository, err := LoadRepository()
+	repository, err := LoadRepository(".")
 	assert.Nil(t, err)
 	assert.NotNil(t, repository.r)
+
+	t.Run("specified path", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		if _, err := git.PlainInit(tmpDir, false); err != nil {
+			t.Fatal(err)
+		}
+
+		repository, err := LoadRepository(tmpDir)
+		assert.Nil(t, err)
+		assert.NotNil(t, repository.r)
+	})
+
+	t.Run("no repository at path", func(t *testing.T) {
+		_, err := LoadRepository(t.TempDir())
+		assert.ErrorIs(t, err, git.ErrRepositoryNotExists)
+	})
+}
+
+func TestNew(t *testing.T) {
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = New(repo)
+	assert.ErrorIs(t, err, ErrNotInitialized)
+
+	if err := rsl.InitializeNamespace(repo); err != nil {
+		t.Fatal(err)
+	}
+
+	// Policy namespace is still missing
+	_, err = New(repo)
+	assert.ErrorIs(t, err, ErrNotInitialized)
+
+	if err := policy.InitializeNamespace(repo); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := New(repo)
+	assert.Nil(t, err)
+	assert.Equal(t, repo, r.r)
 }
 
 func TestInitializeNamespaces(t *testing.T) {
@@ -34,6 +80,61 @@ func TestInitializeNamespaces(t *testing.T) {
 	assert.Nil(t, err)
 }
 
+func TestCompactRefs(t *testing.T) {
+	t.Run("in-memory repository", func(t *testing.T) {
+		r := createTestRepositoryWithPolicy(t, "")
+
+		err := r.CompactRefs()
+		assert.Nil(t, err)
+	})
+
+	t.Run("on-disk repository", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		r := createTestRepositoryWithPolicy(t, tmpDir)
+
+		rslTip, err := gitinterface.GetTip(r.r, rsl.Ref)
+		if err != nil {
+			t.Fatal(err)
+		}
+		policyTip, err := gitinterface.GetTip(r.r, policy.PolicyRef)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// The RSL is tracked for a remote
+		remoteName := "origin"
+		if _, err := r.r.CreateRemote(&config.RemoteConfig{
+			Name: remoteName,
+			URLs: []string{t.TempDir()},
+		}); err != nil {
+			t.Fatal(err)
+		}
+		trackerRef := rsl.RemoteTrackerRef(remoteName)
+		if err := r.r.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(trackerRef), rslTip)); err != nil {
+			t.Fatal(err)
+		}
+
+		err = r.CompactRefs()
+		assert.Nil(t, err)
+
+		assert.NoFileExists(t, filepath.Join(tmpDir, rsl.Ref))
+		assert.NoFileExists(t, filepath.Join(tmpDir, policy.PolicyRef))
+		assert.NoFileExists(t, filepath.Join(tmpDir, trackerRef))
+
+		tip, err := gitinterface.GetTip(r.r, rsl.Ref)
+		assert.Nil(t, err)
+		assert.Equal(t, rslTip, tip)
+
+		tip, err = gitinterface.GetTip(r.r, policy.PolicyRef)
+		assert.Nil(t, err)
+		assert.Equal(t, policyTip, tip)
+
+		tip, err = gitinterface.GetTip(r.r, trackerRef)
+		assert.Nil(t, err)
+		assert.Equal(t, rslTip, tip)
+	})
+}
+
 func TestUnauthorizedKey(t *testing.T) {
 	repo, err := git.Init(memory.NewStorage(), memfs.New())
 	if err != nil {
@@ -6,6 +6,7 @@ import (
 	"context"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 
 	"github.com/gittuf/gittuf/internal/policy"
@@ -15,6 +16,8 @@ import (
 	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
 )
 
+var ErrKeyNotTrustedForRole = errors.New("key is not trusted for the role in the root of trust")
+
 // InitializeRoot is the interface for the user to create the repository's root
 // of trust.
 func (r *Repository) InitializeRoot(ctx context.Context, rootKeyBytes []byte, signCommit bool) error {
@@ -22,7 +25,7 @@ func (r *Repository) InitializeRoot(ctx context.Context, rootKeyBytes []byte, si
 		return err
 	}
 
-	publicKey, err := tuf.LoadKeyFromBytes(rootKeyBytes)
+	publicKey, err := signerverifier.LoadKeyFromBytes(rootKeyBytes)
 	if err != nil {
 		return err
 	}
@@ -56,6 +59,13 @@ func (r *Repository) InitializeRoot(ctx context.Context, rootKeyBytes []byte, si
 // AddTopLevelTargetsKey is the interface for the user to add an authorized key
 // for the top level Targets role / policy file.
 func (r *Repository) AddTopLevelTargetsKey(ctx context.Context, rootKeyBytes, targetsKeyBytes []byte, signCommit bool) error {
+	return r.AddTopLevelPolicyKey(ctx, rootKeyBytes, policy.TargetsRoleName, targetsKeyBytes, signCommit)
+}
+
+// AddTopLevelPolicyKey is the interface for the user to add an authorized key
+// for the specified top level policy file. If the root of trust does not
+// declare the policy yet, it is declared with the key.
+func (r *Repository) AddTopLevelPolicyKey(ctx context.Context, rootKeyBytes []byte, policyName string, targetsKeyBytes []byte, signCommit bool) error {
 	sv, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(rootKeyBytes)
 	if err != nil {
 		return err
@@ -84,7 +94,10 @@ func (r *Repository) AddTopLevelTargetsKey(ctx context.Context, rootKeyBytes, ta
 		return err
 	}
 
-	rootMetadata = policy.AddTargetsKey(rootMetadata, targetsKey)
+	rootMetadata, err = policy.AddTopLevelTargetsRoleKey(rootMetadata, policyName, targetsKey)
+	if err != nil {
+		return err
+	}
 
 	rootMetadata.SetVersion(rootMetadata.Version + 1)
 	rootMetadataBytes, err := json.Marshal(rootMetadata)
@@ -104,6 +117,9 @@ func (r *Repository) AddTopLevelTargetsKey(ctx context.Context, rootKeyBytes, ta
 	state.RootEnvelope = env
 
 	commitMessage := fmt.Sprintf("Add policy key '%s' to root", targetsKey.KeyID)
+	if policyName != policy.TargetsRoleName {
+		commitMessage = fmt.Sprintf("Add key '%s' for policy '%s' to root", targetsKey.KeyID, policyName)
+	}
 
 	return state.Commit(ctx, r.r, commitMessage, signCommit)
 }
@@ -111,6 +127,12 @@ func (r *Repository) AddTopLevelTargetsKey(ctx context.Context, rootKeyBytes, ta
 // RemoveTopLevelTargetsKey is the interface for the user to de-authorize a key
 // trusted to sign the top level Targets role / policy file.
 func (r *Repository) RemoveTopLevelTargetsKey(ctx context.Context, rootKeyBytes []byte, targetsKeyID string, signCommit bool) error {
+	return r.RemoveTopLevelPolicyKey(ctx, rootKeyBytes, policy.TargetsRoleName, targetsKeyID, signCommit)
+}
+
+// RemoveTopLevelPolicyKey is the interface for the user to de-authorize a key
+// trusted to sign the specified top level policy file.
+func (r *Repository) RemoveTopLevelPolicyKey(ctx context.Context, rootKeyBytes []byte, policyName, targetsKeyID string, signCommit bool) error {
 	sv, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(rootKeyBytes)
 	if err != nil {
 		return err
@@ -134,7 +156,7 @@ func (r *Repository) RemoveTopLevelTargetsKey(ctx context.Context, rootKeyBytes
 		return ErrUnauthorizedKey
 	}
 
-	rootMetadata, err = policy.DeleteTargetsKey(rootMetadata, targetsKeyID)
+	rootMetadata, err = policy.DeleteTopLevelTargetsRoleKey(rootMetadata, policyName, targetsKeyID)
 	if err != nil {
 		return err
 	}
@@ -157,6 +179,388 @@ func (r *Repository) RemoveTopLevelTargetsKey(ctx context.Context, rootKeyBytes
 	state.RootEnvelope = env
 
 	commitMessage := fmt.Sprintf("Remove policy key '%s' from root", targetsKeyID)
+	if policyName != policy.TargetsRoleName {
+		commitMessage = fmt.Sprintf("Remove key '%s' for policy '%s' from root", targetsKeyID, policyName)
+	}
+
+	return state.Commit(ctx, r.r, commitMessage, signCommit)
+}
+
+// RotateTargetsSigningKey is the interface for the user to replace a key
+// trusted to sign the top level Targets role / policy file. The new key is
+// trusted in place of the old key in the root of trust, and the Targets
+// metadata is re-signed using the new key in place of the old key's signature.
+// Both changes are made in a single policy commit, so the policy is never left
+// with Targets metadata signed by a key the root of trust no longer trusts. The
+// rotated policy is verified before it is committed, and the repository is
+// unchanged if the rotation fails.
+func (r *Repository) RotateTargetsSigningKey(ctx context.Context, rootKeyBytes []byte, oldTargetsKeyID string, newTargetsKeyBytes []byte, signCommit bool) error {
+	rootSigner, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(rootKeyBytes)
+	if err != nil {
+		return err
+	}
+	rootKeyID, err := rootSigner.KeyID()
+	if err != nil {
+		return err
+	}
+
+	newTargetsKey, err := signerverifier.LoadKeyFromBytes(newTargetsKeyBytes)
+	if err != nil {
+		return err
+	}
+	targetsSigner, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(newTargetsKeyBytes)
+	if err != nil {
+		return err
+	}
+
+	currentState, err := policy.LoadCurrentState(ctx, r.r)
+	if err != nil {
+		return err
+	}
+
+	// The rotated state is loaded separately as its envelopes are modified
+	state, err := policy.LoadCurrentState(ctx, r.r)
+	if err != nil {
+		return err
+	}
+
+	rootMetadata, err := state.GetRootMetadata()
+	if err != nil {
+		return err
+	}
+
+	if !isKeyAuthorized(rootMetadata.Roles[policy.RootRoleName].KeyIDs, rootKeyID) {
+		return ErrUnauthorizedKey
+	}
+
+	if !isKeyAuthorized(rootMetadata.Roles[policy.TargetsRoleName].KeyIDs, oldTargetsKeyID) {
+		return fmt.Errorf("%w: '%s' is not trusted for '%s'", ErrKeyNotTrustedForRole, oldTargetsKeyID, policy.TargetsRoleName)
+	}
+
+	rootMetadata = policy.AddTargetsKey(rootMetadata, newTargetsKey)
+	rootMetadata, err = policy.DeleteTargetsKey(rootMetadata, oldTargetsKeyID)
+	if err != nil {
+		return err
+	}
+
+	rootMetadata.SetVersion(rootMetadata.Version + 1)
+	rootMetadataBytes, err := json.Marshal(rootMetadata)
+	if err != nil {
+		return err
+	}
+
+	rootEnv := state.RootEnvelope
+	rootEnv.Signatures = []sslibdsse.Signature{}
+	rootEnv.Payload = base64.StdEncoding.EncodeToString(rootMetadataBytes)
+
+	rootEnv, err = dsse.SignEnvelope(ctx, rootEnv, rootSigner)
+	if err != nil {
+		return err
+	}
+	state.RootEnvelope = rootEnv
+
+	if state.TargetsEnvelope != nil {
+		// Signatures from the other trusted keys remain valid as the Targets
+		// metadata itself is unchanged
+		targetsEnv := state.TargetsEnvelope
+		signatures := []sslibdsse.Signature{}
+		for _, signature := range targetsEnv.Signatures {
+			if signature.KeyID == oldTargetsKeyID || signature.KeyID == newTargetsKey.KeyID {
+				continue
+			}
+			signatures = append(signatures, signature)
+		}
+		targetsEnv.Signatures = signatures
+
+		targetsEnv, err = dsse.SignEnvelope(ctx, targetsEnv, targetsSigner)
+		if err != nil {
+			return err
+		}
+		state.TargetsEnvelope = targetsEnv
+	}
+
+	if err := state.Verify(ctx); err != nil {
+		return err
+	}
+	if err := policy.VerifyPolicyTransition(ctx, currentState, state); err != nil {
+		return err
+	}
+
+	commitMessage := fmt.Sprintf("Rotate policy key '%s' to '%s'", oldTargetsKeyID, newTargetsKey.KeyID)
+
+	return state.Commit(ctx, r.r, commitMessage, signCommit)
+}
+
+// AddRSLWriterKey is the interface for the user to add a key trusted to sign
+// RSL entries. Once the first such key is added, every RSL entry must be signed
+// by an authorized RSL writer.
+func (r *Repository) AddRSLWriterKey(ctx context.Context, rootKeyBytes, rslWriterKeyBytes []byte, signCommit bool) error {
+	sv, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(rootKeyBytes)
+	if err != nil {
+		return err
+	}
+	rootKeyID, err := sv.KeyID()
+	if err != nil {
+		return err
+	}
+
+	state, err := policy.LoadCurrentState(ctx, r.r)
+	if err != nil {
+		return err
+	}
+
+	rootMetadata, err := state.GetRootMetadata()
+	if err != nil {
+		return err
+	}
+
+	if !isKeyAuthorized(rootMetadata.Roles[policy.RootRoleName].KeyIDs, rootKeyID) {
+		return ErrUnauthorizedKey
+	}
+
+	rslWriterKey, err := tuf.LoadKeyFromBytes(rslWriterKeyBytes)
+	if err != nil {
+		return err
+	}
+
+	rootMetadata = policy.AddRSLWriterKey(rootMetadata, rslWriterKey)
+
+	rootMetadata.SetVersion(rootMetadata.Version + 1)
+	rootMetadataBytes, err := json.Marshal(rootMetadata)
+	if err != nil {
+		return err
+	}
+
+	env := state.RootEnvelope
+	env.Signatures = []sslibdsse.Signature{}
+	env.Payload = base64.StdEncoding.EncodeToString(rootMetadataBytes)
+
+	env, err = dsse.SignEnvelope(ctx, env, sv)
+	if err != nil {
+		return err
+	}
+
+	state.RootEnvelope = env
+
+	commitMessage := fmt.Sprintf("Add RSL writer key '%s' to root", rslWriterKey.KeyID)
+
+	return state.Commit(ctx, r.r, commitMessage, signCommit)
+}
+
+// RemoveRSLWriterKey is the interface for the user to de-authorize a key
+// trusted to sign RSL entries.
+func (r *Repository) RemoveRSLWriterKey(ctx context.Context, rootKeyBytes []byte, rslWriterKeyID string, signCommit bool) error {
+	sv, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(rootKeyBytes)
+	if err != nil {
+		return err
+	}
+	rootKeyID, err := sv.KeyID()
+	if err != nil {
+		return err
+	}
+
+	state, err := policy.LoadCurrentState(ctx, r.r)
+	if err != nil {
+		return err
+	}
+
+	rootMetadata, err := state.GetRootMetadata()
+	if err != nil {
+		return err
+	}
+
+	if !isKeyAuthorized(rootMetadata.Roles[policy.RootRoleName].KeyIDs, rootKeyID) {
+		return ErrUnauthorizedKey
+	}
+
+	rootMetadata, err = policy.DeleteRSLWriterKey(rootMetadata, rslWriterKeyID)
+	if err != nil {
+		return err
+	}
+
+	rootMetadata.SetVersion(rootMetadata.Version + 1)
+	rootMetadataBytes, err := json.Marshal(rootMetadata)
+	if err != nil {
+		return err
+	}
+
+	env := state.RootEnvelope
+	env.Signatures = []sslibdsse.Signature{}
+	env.Payload = base64.StdEncoding.EncodeToString(rootMetadataBytes)
+
+	env, err = dsse.SignEnvelope(ctx, env, sv)
+	if err != nil {
+		return err
+	}
+
+	state.RootEnvelope = env
+
+	commitMessage := fmt.Sprintf("Remove RSL writer key '%s' from root", rslWriterKeyID)
+
+	return state.Commit(ctx, r.r, commitMessage, signCommit)
+}
+
+// AddEmergencyKey is the interface for the user to add a key trusted to
+// authorize break-glass access. The key must not be trusted by any other role
+// in the root of trust.
+func (r *Repository) AddEmergencyKey(ctx context.Context, rootKeyBytes []byte, emergencyKeyBytes []byte, signCommit bool) error {
+	sv, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(rootKeyBytes)
+	if err != nil {
+		return err
+	}
+	rootKeyID, err := sv.KeyID()
+	if err != nil {
+		return err
+	}
+
+	state, err := policy.LoadCurrentState(ctx, r.r)
+	if err != nil {
+		return err
+	}
+
+	rootMetadata, err := state.GetRootMetadata()
+	if err != nil {
+		return err
+	}
+
+	if !isKeyAuthorized(rootMetadata.Roles[policy.RootRoleName].KeyIDs, rootKeyID) {
+		return ErrUnauthorizedKey
+	}
+
+	emergencyKey, err := tuf.LoadKeyFromBytes(emergencyKeyBytes)
+	if err != nil {
+		return err
+	}
+
+	rootMetadata, err = policy.AddEmergencyKey(rootMetadata, emergencyKey)
+	if err != nil {
+		return err
+	}
+
+	rootMetadata.SetVersion(rootMetadata.Version + 1)
+	rootMetadataBytes, err := json.Marshal(rootMetadata)
+	if err != nil {
+		return err
+	}
+
+	env := state.RootEnvelope
+	env.Signatures = []sslibdsse.Signature{}
+	env.Payload = base64.StdEncoding.EncodeToString(rootMetadataBytes)
+
+	env, err = dsse.SignEnvelope(ctx, env, sv)
+	if err != nil {
+		return err
+	}
+
+	state.RootEnvelope = env
+
+	commitMessage := fmt.Sprintf("Add emergency key '%s' to root", emergencyKey.KeyID)
+
+	return state.Commit(ctx, r.r, commitMessage, signCommit)
+}
+
+// RemoveEmergencyKey is the interface for the user to de-authorize a key
+// trusted to authorize break-glass access.
+func (r *Repository) RemoveEmergencyKey(ctx context.Context, rootKeyBytes []byte, emergencyKeyID string, signCommit bool) error {
+	sv, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(rootKeyBytes)
+	if err != nil {
+		return err
+	}
+	rootKeyID, err := sv.KeyID()
+	if err != nil {
+		return err
+	}
+
+	state, err := policy.LoadCurrentState(ctx, r.r)
+	if err != nil {
+		return err
+	}
+
+	rootMetadata, err := state.GetRootMetadata()
+	if err != nil {
+		return err
+	}
+
+	if !isKeyAuthorized(rootMetadata.Roles[policy.RootRoleName].KeyIDs, rootKeyID) {
+		return ErrUnauthorizedKey
+	}
+
+	rootMetadata, err = policy.DeleteEmergencyKey(rootMetadata, emergencyKeyID)
+	if err != nil {
+		return err
+	}
+
+	rootMetadata.SetVersion(rootMetadata.Version + 1)
+	rootMetadataBytes, err := json.Marshal(rootMetadata)
+	if err != nil {
+		return err
+	}
+
+	env := state.RootEnvelope
+	env.Signatures = []sslibdsse.Signature{}
+	env.Payload = base64.StdEncoding.EncodeToString(rootMetadataBytes)
+
+	env, err = dsse.SignEnvelope(ctx, env, sv)
+	if err != nil {
+		return err
+	}
+
+	state.RootEnvelope = env
+
+	commitMessage := fmt.Sprintf("Remove emergency key '%s' from root", emergencyKeyID)
+
+	return state.Commit(ctx, r.r, commitMessage, signCommit)
+}
+
+// SetEmergencyThreshold is the interface for the user to set the number of
+// emergency keys that must authorize the use of break-glass access.
+func (r *Repository) SetEmergencyThreshold(ctx context.Context, rootKeyBytes []byte, threshold int, signCommit bool) error {
+	sv, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(rootKeyBytes)
+	if err != nil {
+		return err
+	}
+	rootKeyID, err := sv.KeyID()
+	if err != nil {
+		return err
+	}
+
+	state, err := policy.LoadCurrentState(ctx, r.r)
+	if err != nil {
+		return err
+	}
+
+	rootMetadata, err := state.GetRootMetadata()
+	if err != nil {
+		return err
+	}
+
+	if !isKeyAuthorized(rootMetadata.Roles[policy.RootRoleName].KeyIDs, rootKeyID) {
+		return ErrUnauthorizedKey
+	}
+
+	rootMetadata, err = policy.SetEmergencyThreshold(rootMetadata, threshold)
+	if err != nil {
+		return err
+	}
+
+	rootMetadata.SetVersion(rootMetadata.Version + 1)
+	rootMetadataBytes, err := json.Marshal(rootMetadata)
+	if err != nil {
+		return err
+	}
+
+	env := state.RootEnvelope
+	env.Signatures = []sslibdsse.Signature{}
+	env.Payload = base64.StdEncoding.EncodeToString(rootMetadataBytes)
+
+	env, err = dsse.SignEnvelope(ctx, env, sv)
+	if err != nil {
+		return err
+	}
+
+	state.RootEnvelope = env
+
+	commitMessage := fmt.Sprintf("Set emergency threshold to %d in root", threshold)
 
 	return state.Commit(ctx, r.r, commitMessage, signCommit)
 }
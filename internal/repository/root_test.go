@@ -4,14 +4,21 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
 	"os"
 	"path/filepath"
 	"testing"
 
+	"github.com/gittuf/gittuf/internal/gitinterface"
 	"github.com/gittuf/gittuf/internal/policy"
 	"github.com/gittuf/gittuf/internal/signerverifier"
+	"github.com/gittuf/gittuf/internal/signerverifier/age"
 	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	"github.com/gittuf/gittuf/internal/signerverifier/gpg"
+	"github.com/gittuf/gittuf/internal/third_party/go-git"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/storage/memory"
 	"github.com/gittuf/gittuf/internal/tuf"
+	"github.com/go-git/go-billy/v5/memfs"
 	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
 	"github.com/stretchr/testify/assert"
 )
@@ -41,6 +48,41 @@ func TestInitializeRoot(t *testing.T) {
 
 	err = dsse.VerifyEnvelope(context.Background(), state.RootEnvelope, []sslibdsse.Verifier{sv}, 1)
 	assert.Nil(t, err)
+
+	t.Run("age identity", func(t *testing.T) {
+		repo, err := git.Init(memory.NewStorage(), memfs.New())
+		if err != nil {
+			t.Fatal(err)
+		}
+		r := &Repository{r: repo}
+
+		identityBytes, err := os.ReadFile(filepath.Join("test-data", "age-identity.txt"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = r.InitializeRoot(context.Background(), identityBytes, false)
+		assert.Nil(t, err)
+
+		key, err := age.LoadPublicKey(identityBytes)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		state, err := policy.LoadCurrentState(context.Background(), r.r)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rootMetadata, err := state.GetRootMetadata()
+		assert.Nil(t, err)
+		assert.Equal(t, key.KeyID, rootMetadata.Roles[policy.RootRoleName].KeyIDs[0])
+		assert.Equal(t, key.KeyID, state.RootEnvelope.Signatures[0].KeyID)
+
+		// The root is verified like any other key
+		err = state.Verify(context.Background())
+		assert.Nil(t, err)
+	})
 }
 
 func TestAddTopLevelTargetsKey(t *testing.T) {
@@ -141,3 +183,312 @@ func TestRemoveTopLevelTargetsKey(t *testing.T) {
 	err = dsse.VerifyEnvelope(context.Background(), state.RootEnvelope, []sslibdsse.Verifier{sv}, 1)
 	assert.Nil(t, err)
 }
+
+func TestAddTopLevelPolicyKey(t *testing.T) {
+	r := createTestRepositoryWithPolicy(t, "")
+
+	rootKeyBytes, err := os.ReadFile(filepath.Join("test-data", "root"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	targetsKeyBytes, err := os.ReadFile(filepath.Join("test-data", "targets"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootKey, err := tuf.LoadKeyFromBytes(rootKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gpgKeyBytes, err := os.ReadFile(filepath.Join("test-data", "gpg-pubkey.asc"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	gpgKey, err := gpg.LoadGPGKeyFromBytes(gpgKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	kb, err := json.Marshal(gpgKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = r.AddTopLevelPolicyKey(context.Background(), rootKeyBytes, policy.RootRoleName, rootKeyBytes, false)
+	assert.ErrorIs(t, err, policy.ErrReservedRoleName)
+
+	// The second policy is owned by a different key than the main policy
+	err = r.AddTopLevelPolicyKey(context.Background(), rootKeyBytes, "security", rootKeyBytes, false)
+	assert.Nil(t, err)
+
+	err = r.InitializeTargets(context.Background(), targetsKeyBytes, "security", false)
+	assert.ErrorIs(t, err, ErrUnauthorizedKey)
+
+	err = r.InitializeTargets(context.Background(), rootKeyBytes, "security", false)
+	assert.Nil(t, err)
+
+	err = r.AddDelegation(context.Background(), rootKeyBytes, "security", "protect-ci", [][]byte{kb}, []string{"file:ci/*"}, false)
+	assert.Nil(t, err)
+
+	err = r.SetRuleThreshold(context.Background(), rootKeyBytes, "security", "protect-ci", 1, false)
+	assert.Nil(t, err)
+
+	state, err := policy.LoadCurrentState(context.Background(), r.r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The rule is updated in the second policy's metadata
+	securityMetadata, err := state.GetTargetsMetadata("security")
+	assert.Nil(t, err)
+	assert.Equal(t, 3, securityMetadata.Version)
+	assert.Equal(t, "protect-ci", securityMetadata.Delegations.Roles[0].Name)
+	assert.False(t, state.HasTargetsRole("protect-ci"))
+
+	rootMetadata, err := state.GetRootMetadata()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []string{rootKey.KeyID}, rootMetadata.Roles["security"].KeyIDs)
+
+	roleNames, err := state.TopLevelTargetsRoleNames()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{policy.TargetsRoleName, "security"}, roleNames)
+
+	// Both policies are used to find the keys for a path
+	for _, path := range []string{"git:refs/heads/main", "file:ci/build.yml"} {
+		keys, err := state.FindPublicKeysForPath(context.Background(), path)
+		assert.Nil(t, err)
+		assert.Equal(t, []*tuf.Key{gpgKey}, keys)
+	}
+
+	err = r.RemoveTopLevelPolicyKey(context.Background(), rootKeyBytes, "security", rootKey.KeyID, false)
+	assert.ErrorIs(t, err, policy.ErrCannotMeetThreshold)
+}
+
+func TestRotateTargetsSigningKey(t *testing.T) {
+	rootKeyBytes, err := os.ReadFile(filepath.Join("test-data", "root"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootKey, err := tuf.LoadKeyFromBytes(rootKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	targetsPrivKeyBytes, err := os.ReadFile(filepath.Join("test-data", "targets"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	targetsKey, err := tuf.LoadKeyFromBytes(targetsPrivKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gpgKeyBytes, err := os.ReadFile(filepath.Join("test-data", "gpg-pubkey.asc"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	gpgKey, err := gpg.LoadGPGKeyFromBytes(gpgKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gpgKeyJSON, err := json.Marshal(gpgKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("successful rotation", func(t *testing.T) {
+		r := createTestRepositoryWithPolicy(t, "")
+
+		currentState, err := policy.LoadCurrentState(context.Background(), r.r)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// The root key is trusted for the policy in place of the targets key
+		err = r.RotateTargetsSigningKey(context.Background(), rootKeyBytes, targetsKey.KeyID, rootKeyBytes, false)
+		assert.Nil(t, err)
+
+		state, err := policy.LoadCurrentState(context.Background(), r.r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Nil(t, state.Verify(context.Background()))
+		assert.Nil(t, policy.VerifyPolicyTransition(context.Background(), currentState, state))
+
+		rootMetadata, err := state.GetRootMetadata()
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, []string{rootKey.KeyID}, rootMetadata.Roles[policy.TargetsRoleName].KeyIDs)
+
+		if assert.Len(t, state.TargetsEnvelope.Signatures, 1) {
+			assert.Equal(t, rootKey.KeyID, state.TargetsEnvelope.Signatures[0].KeyID)
+		}
+
+		// The rules in the policy are unchanged
+		targetsMetadata, err := state.GetTargetsMetadata(policy.TargetsRoleName)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, "protect-main", targetsMetadata.Delegations.Roles[0].Name)
+
+		// The policy can only be updated using the new key
+		err = r.AddDelegation(context.Background(), targetsPrivKeyBytes, policy.TargetsRoleName, "protect-feature", [][]byte{gpgKeyJSON}, []string{"git:refs/heads/feature"}, false)
+		assert.ErrorIs(t, err, ErrUnauthorizedKey)
+
+		err = r.AddDelegation(context.Background(), rootKeyBytes, policy.TargetsRoleName, "protect-feature", [][]byte{gpgKeyJSON}, []string{"git:refs/heads/feature"}, false)
+		assert.Nil(t, err)
+	})
+
+	t.Run("old key not trusted", func(t *testing.T) {
+		r := createTestRepositoryWithPolicy(t, "")
+
+		policyTip, err := gitinterface.GetTip(r.r, policy.PolicyRef)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = r.RotateTargetsSigningKey(context.Background(), rootKeyBytes, gpgKey.KeyID, rootKeyBytes, false)
+		assert.ErrorIs(t, err, ErrKeyNotTrustedForRole)
+
+		newPolicyTip, err := gitinterface.GetTip(r.r, policy.PolicyRef)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, policyTip, newPolicyTip)
+	})
+
+	t.Run("unauthorized root key", func(t *testing.T) {
+		r := createTestRepositoryWithPolicy(t, "")
+
+		policyTip, err := gitinterface.GetTip(r.r, policy.PolicyRef)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = r.RotateTargetsSigningKey(context.Background(), targetsPrivKeyBytes, targetsKey.KeyID, rootKeyBytes, false)
+		assert.ErrorIs(t, err, ErrUnauthorizedKey)
+
+		newPolicyTip, err := gitinterface.GetTip(r.r, policy.PolicyRef)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, policyTip, newPolicyTip)
+	})
+}
+
+func TestAddRSLWriterKey(t *testing.T) {
+	r, keyBytes := createTestRepositoryWithRoot(t, "")
+
+	rslWriterKeyBytes, err := os.ReadFile(filepath.Join("test-data", "targets.pub"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rslWriterKey, err := tuf.LoadKeyFromBytes(rslWriterKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = r.AddRSLWriterKey(context.Background(), keyBytes, rslWriterKeyBytes, false)
+	assert.Nil(t, err)
+
+	state, err := policy.LoadCurrentState(context.Background(), r.r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rootMetadata, err := state.GetRootMetadata()
+	assert.Nil(t, err)
+	assert.Equal(t, 2, rootMetadata.Version)
+	assert.Equal(t, []string{rslWriterKey.KeyID}, rootMetadata.Roles[policy.RSLWriterRoleName].KeyIDs)
+
+	targetsKeyBytes, err := os.ReadFile(filepath.Join("test-data", "targets"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = r.AddRSLWriterKey(context.Background(), targetsKeyBytes, rslWriterKeyBytes, false)
+	assert.ErrorIs(t, err, ErrUnauthorizedKey)
+}
+
+func TestRemoveRSLWriterKey(t *testing.T) {
+	r, keyBytes := createTestRepositoryWithRoot(t, "")
+
+	rootKey, err := tuf.LoadKeyFromBytes(keyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rslWriterKeyBytes, err := os.ReadFile(filepath.Join("test-data", "targets.pub"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rslWriterKey, err := tuf.LoadKeyFromBytes(rslWriterKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := r.AddRSLWriterKey(context.Background(), keyBytes, keyBytes, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.AddRSLWriterKey(context.Background(), keyBytes, rslWriterKeyBytes, false); err != nil {
+		t.Fatal(err)
+	}
+
+	err = r.RemoveRSLWriterKey(context.Background(), keyBytes, rootKey.KeyID, false)
+	assert.Nil(t, err)
+
+	state, err := policy.LoadCurrentState(context.Background(), r.r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rootMetadata, err := state.GetRootMetadata()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, 4, rootMetadata.Version)
+	assert.Equal(t, []string{rslWriterKey.KeyID}, rootMetadata.Roles[policy.RSLWriterRoleName].KeyIDs)
+
+	err = r.RemoveRSLWriterKey(context.Background(), keyBytes, rslWriterKey.KeyID, false)
+	assert.ErrorIs(t, err, policy.ErrCannotMeetThreshold)
+}
+
+func TestAddEmergencyKey(t *testing.T) {
+	r, keyBytes := createTestRepositoryWithRoot(t, "")
+
+	emergencyKeyBytes, err := os.ReadFile(filepath.Join("test-data", "targets.pub"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	emergencyKey, err := tuf.LoadKeyFromBytes(emergencyKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The root key cannot be an emergency key
+	err = r.AddEmergencyKey(context.Background(), keyBytes, keyBytes, false)
+	assert.ErrorIs(t, err, policy.ErrEmergencyKeyNotDistinct)
+
+	err = r.AddEmergencyKey(context.Background(), keyBytes, emergencyKeyBytes, false)
+	assert.Nil(t, err)
+
+	state, err := policy.LoadCurrentState(context.Background(), r.r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rootMetadata, err := state.GetRootMetadata()
+	assert.Nil(t, err)
+	assert.Equal(t, 2, rootMetadata.Version)
+	assert.Equal(t, []string{emergencyKey.KeyID}, rootMetadata.Roles[policy.EmergencyRoleName].KeyIDs)
+
+	assert.Equal(t, 2, rootMetadata.Roles[policy.EmergencyRoleName].Threshold)
+
+	err = r.SetEmergencyThreshold(context.Background(), keyBytes, 3, false)
+	assert.ErrorIs(t, err, policy.ErrInvalidThreshold)
+
+	err = r.SetEmergencyThreshold(context.Background(), keyBytes, 1, false)
+	assert.ErrorIs(t, err, policy.ErrEmergencyThresholdTooLow)
+
+	err = r.RemoveEmergencyKey(context.Background(), keyBytes, emergencyKey.KeyID, false)
+	assert.ErrorIs(t, err, policy.ErrCannotMeetThreshold)
+}
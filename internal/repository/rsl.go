@@ -6,9 +6,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/policy"
 	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/gittuf/gittuf/internal/third_party/go-git"
 	"github.com/gittuf/gittuf/internal/third_party/go-git/config"
 	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing"
 	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing/transport"
@@ -18,6 +21,12 @@ var (
 	ErrCommitNotInRef = errors.New("specified commit is not in ref")
 	ErrPushingRSL     = errors.New("unable to push RSL")
 	ErrPullingRSL     = errors.New("unable to pull RSL")
+	ErrRSLRewound     = errors.New("remote RSL was rewound without an authorizing annotation")
+	ErrRefNotDeleted  = errors.New("ref cannot be recorded as deleted as it still exists")
+	ErrRefNotAbsolute = errors.New("deleted ref must be specified using its fully qualified name")
+
+	ErrAuthoritativeRSLNotFetched = errors.New("RSL has not been fetched from the authoritative remote")
+	ErrRefUpdateNotVerified       = errors.New("latest state of ref does not pass policy verification, RSL entry was not recorded")
 )
 
 // RecordRSLEntryForReference is the interface for the user to add an RSL entry
@@ -36,7 +45,68 @@ func (r *Repository) RecordRSLEntryForReference(refName string, signCommit bool)
 	// TODO: once policy verification is in place, the signing key used by
 	// signCommit must be verified for the refName in the delegation tree.
 
-	return rsl.NewReferenceEntry(absRefName, ref.Hash()).Commit(r.r, signCommit)
+	entry := rsl.NewReferenceEntry(absRefName, ref.Hash())
+	if err := entry.SetMergeBase(r.r); err != nil {
+		return err
+	}
+	rsl.SetProvenance(entry, signCommit)
+
+	return entry.Commit(r.r, signCommit)
+}
+
+// RecordAndVerify adds an RSL entry for the current tip of the specified Git
+// reference only if the update passes verification using the repository's
+// current policy. Before the entry is created, the commits introduced since the
+// ref's previous entry are verified using policy.VerifyCommitRange. The entry's
+// commit is then created without updating the RSL and checked using
+// policy.VerifyEntryCandidate, and it's only added to the RSL if it passes. All
+// of this happens while holding the repository's gittuf lock. If verification
+// fails, nothing is recorded and ErrRefUpdateNotVerified is returned along with
+// the reason verification failed. The options are passed through to
+// gitinterface.Commit when the entry is created.
+func (r *Repository) RecordAndVerify(ctx context.Context, refName string, signCommit bool, opts ...gitinterface.CommitOption) error {
+	absRefName, err := gitinterface.AbsoluteReference(r.r, refName)
+	if err != nil {
+		return err
+	}
+
+	ref, err := r.r.Reference(plumbing.ReferenceName(absRefName), true)
+	if err != nil {
+		return err
+	}
+
+	entry := rsl.NewReferenceEntry(absRefName, ref.Hash())
+	if err := entry.SetMergeBase(r.r); err != nil {
+		return err
+	}
+	rsl.SetProvenance(entry, signCommit)
+
+	lock, err := gitinterface.LockRepository(r.r, gitinterface.DefaultLockTimeout)
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock() //nolint:errcheck
+
+	previousTarget := plumbing.ZeroHash
+	previousEntry, _, err := rsl.GetLatestReferenceEntryForRef(r.r, absRefName)
+	if err == nil {
+		previousTarget = previousEntry.TargetID
+	} else if !errors.Is(err, rsl.ErrRSLEntryNotFound) {
+		return err
+	}
+
+	if err := policy.VerifyCommitRange(ctx, r.r, absRefName, previousTarget, entry.TargetID); err != nil {
+		return fmt.Errorf("%w: %w", ErrRefUpdateNotVerified, err)
+	}
+
+	if err := entry.CreateCommitObject(r.r, signCommit, opts...); err != nil {
+		return err
+	}
+	if err := policy.VerifyEntryCandidate(ctx, r.r, entry); err != nil {
+		return fmt.Errorf("%w: %w", ErrRefUpdateNotVerified, err)
+	}
+
+	return entry.RecordWhileLocked(r.r)
 }
 
 // RecordRSLEntryForReferenceAtCommit is a special version of
@@ -71,7 +141,61 @@ func (r *Repository) RecordRSLEntryForReferenceAtCommit(refName string, commitID
 	// TODO: once policy verification is in place, the signing key used by
 	// signCommit must be verified for the refName in the delegation tree.
 
-	return rsl.NewReferenceEntry(absRefName, plumbing.NewHash(commitID)).Commit(r.r, signCommit)
+	entry := rsl.NewReferenceEntry(absRefName, plumbing.NewHash(commitID))
+	if err := entry.SetMergeBase(r.r); err != nil {
+		return err
+	}
+	rsl.SetProvenance(entry, signCommit)
+
+	return entry.Commit(r.r, signCommit)
+}
+
+// RecordRSLEntryForDeletion is the interface for the user to record the
+// deletion of the specified Git reference in the RSL. As the ref no longer
+// exists, it must be specified using its fully qualified name. If the ref is
+// protected by gittuf policy, the entry must be signed by a key trusted for the
+// ref for it to be verified.
+func (r *Repository) RecordRSLEntryForDeletion(refName string, signCommit bool) error {
+	if !strings.HasPrefix(refName, gitinterface.RefPrefix) {
+		return fmt.Errorf("%w: '%s'", ErrRefNotAbsolute, refName)
+	}
+
+	if _, err := r.r.Reference(plumbing.ReferenceName(refName), false); err == nil {
+		return fmt.Errorf("%w: '%s'", ErrRefNotDeleted, refName)
+	} else if !errors.Is(err, plumbing.ErrReferenceNotFound) {
+		return err
+	}
+
+	entry := rsl.NewDeletionEntry(refName)
+	rsl.SetProvenance(entry, signCommit)
+
+	return entry.Commit(r.r, signCommit)
+}
+
+// AmendLatestRSLEntry is the interface for the user to replace the latest RSL
+// entry with one that records the latest state of the specified Git reference.
+// The latest entry can only be amended if it has not been pushed to the remote
+// that hosts gittuf's refs.
+func (r *Repository) AmendLatestRSLEntry(refName string, signCommit bool) error {
+	absRefName, err := gitinterface.AbsoluteReference(r.r, refName)
+	if err != nil {
+		return err
+	}
+
+	ref, err := r.r.Reference(plumbing.ReferenceName(absRefName), true)
+	if err != nil {
+		return err
+	}
+
+	remoteName, err := r.GetGittufRemote()
+	if err != nil {
+		return err
+	}
+
+	entry := rsl.NewReferenceEntry(absRefName, ref.Hash())
+	rsl.SetProvenance(entry, signCommit)
+
+	return rsl.AmendLatestEntry(r.r, remoteName, entry, signCommit)
 }
 
 // RecordRSLAnnotation is the interface for the user to add an RSL annotation
@@ -85,7 +209,40 @@ func (r *Repository) RecordRSLAnnotation(rslEntryIDs []string, skip bool, messag
 	// TODO: once policy verification is in place, the signing key used by
 	// signCommit must be verified for the refNames of the rslEntryIDs.
 
-	return rsl.NewAnnotationEntry(rslEntryHashes, skip, message).Commit(r.r, signCommit)
+	annotation := rsl.NewAnnotationEntry(rslEntryHashes, skip, message)
+	rsl.SetProvenance(annotation, signCommit)
+
+	return annotation.Commit(r.r, signCommit)
+}
+
+// RecordRSLForcePushAnnotation is the interface for the user to authorize the
+// non-fast-forward ref updates recorded in one or more prior RSL entries.
+func (r *Repository) RecordRSLForcePushAnnotation(rslEntryIDs []string, message string, signCommit bool) error {
+	rslEntryHashes := []plumbing.Hash{}
+	for _, id := range rslEntryIDs {
+		rslEntryHashes = append(rslEntryHashes, plumbing.NewHash(id))
+	}
+
+	annotation := rsl.NewForcePushAnnotationEntry(rslEntryHashes, message)
+	rsl.SetProvenance(annotation, signCommit)
+
+	return annotation.Commit(r.r, signCommit)
+}
+
+// RecordRSLBreakGlassAnnotation is the interface for a holder of an emergency
+// key to authorize the ref updates recorded in one or more prior RSL entries
+// that the policy's rules reject. The annotation counts towards the threshold
+// of the emergency role only if it is signed by one of the role's keys.
+func (r *Repository) RecordRSLBreakGlassAnnotation(rslEntryIDs []string, message string, signCommit bool) error {
+	rslEntryHashes := []plumbing.Hash{}
+	for _, id := range rslEntryIDs {
+		rslEntryHashes = append(rslEntryHashes, plumbing.NewHash(id))
+	}
+
+	annotation := rsl.NewBreakGlassAnnotationEntry(rslEntryHashes, message)
+	rsl.SetProvenance(annotation, signCommit)
+
+	return annotation.Commit(r.r, signCommit)
 }
 
 // CheckRemoteRSLForUpdates checks if the RSL at the specified remote remote
@@ -116,24 +273,32 @@ func (r *Repository) CheckRemoteRSLForUpdates(ctx context.Context, remoteName st
 		return false, false, err
 	}
 
+	return r.compareLocalAndRemoteTips(localRefState.Hash(), remoteRefState.Hash())
+}
+
+// compareLocalAndRemoteTips checks if the remote tip of a ref has updates that
+// are not present in the local tip. If it does, it also checks if the local
+// tip has updates that are not present in the remote tip, i.e., if the two have
+// diverged. A zero local tip indicates the ref does not exist locally.
+func (r *Repository) compareLocalAndRemoteTips(localTip, remoteTip plumbing.Hash) (bool, bool, error) {
 	// Check if local is nil and exit appropriately
-	if localRefState.Hash().IsZero() {
-		// Local RSL has not been populated but remote is not zero
+	if localTip.IsZero() {
+		// Local ref has not been populated but remote is not zero
 		// So there are updates the local can pull
 		return true, false, nil
 	}
 
 	// Check if equal and exit early if true
-	if remoteRefState.Hash() == localRefState.Hash() {
+	if remoteTip == localTip {
 		return false, false, nil
 	}
 
 	// Next, check if remote is ahead of local
-	remoteCommit, err := r.r.CommitObject(remoteRefState.Hash())
+	remoteCommit, err := r.r.CommitObject(remoteTip)
 	if err != nil {
 		return false, false, err
 	}
-	localCommit, err := r.r.CommitObject(localRefState.Hash())
+	localCommit, err := r.r.CommitObject(localTip)
 	if err != nil {
 		return false, false, err
 	}
@@ -159,9 +324,14 @@ func (r *Repository) CheckRemoteRSLForUpdates(ctx context.Context, remoteName st
 	return true, true, nil
 }
 
-// PushRSL pushes the local RSL to the specified remote. As this push defaults
-// to fast-forward only, divergent RSL states are detected.
+// PushRSL pushes the local RSL to the specified remote. Before pushing, the
+// remote's gittuf refs are checked using CheckRemoteForPush so that divergent
+// RSL states are detected rather than clobbered.
 func (r *Repository) PushRSL(ctx context.Context, remoteName string) error {
+	if err := r.CheckRemoteForPush(ctx, remoteName); err != nil {
+		return errors.Join(ErrPushingRSL, err)
+	}
+
 	if err := gitinterface.Push(ctx, r.r, remoteName, []string{rsl.Ref}); err != nil {
 		return errors.Join(ErrPushingRSL, err)
 	}
@@ -178,3 +348,269 @@ func (r *Repository) PullRSL(ctx context.Context, remoteName string) error {
 
 	return nil
 }
+
+// FetchRSL fetches the RSL from the specified remote to the remote's RSL
+// tracker, which records the tip of the RSL as of the last fetch. As the
+// previously fetched entries are already available locally, only the entries
+// added at the remote since the last fetch are transferred. FetchRSL then checks
+// that the new entries chain onto the previously fetched tip. If the remote RSL
+// no longer contains the previously fetched tip, the fetch is rejected unless
+// the remote RSL contains a force push annotation that refers to the previous
+// tip. When the fetch is rejected, the RSL tracker is reset to the previous tip.
+// Finally, if the local RSL has not diverged from the remote RSL, it is
+// fast-forwarded to the fetched tip.
+func (r *Repository) FetchRSL(ctx context.Context, remoteName string) error {
+	currentTip, err := r.fetchRSLTracker(ctx, remoteName)
+	if err != nil || currentTip.IsZero() {
+		return err
+	}
+
+	return r.fastForwardLocalRSL(currentTip)
+}
+
+// FetchRSLFromRemotes fetches the RSL from each of the specified remotes to the
+// remote's RSL tracker, checking the fetched entries the same way as FetchRSL.
+// This supports repositories that are mirrored to several remotes. Only the RSL
+// fetched from the authoritative remote, i.e., the remote that hosts gittuf's
+// refs as returned by GetGittufRemote, is used to fast-forward the local RSL
+// that verification relies on. The RSLs fetched from the other remotes are only
+// recorded in their trackers, and they can be compared with the authoritative
+// remote's RSL using CompareRSLTrackers.
+//
+// A failure to fetch from one remote does not abort the fetches from the other
+// remotes. The returned map records the result of the fetch for each remote,
+// with a nil value indicating the fetch succeeded. If the fetch from any remote
+// fails, an error aggregating all the failures is also returned.
+func (r *Repository) FetchRSLFromRemotes(ctx context.Context, remoteNames []string) (map[string]error, error) {
+	authoritativeRemote, err := r.GetGittufRemote()
+	if err != nil {
+		return nil, err
+	}
+
+	results := make(map[string]error, len(remoteNames))
+	errs := []error{}
+	for _, remoteName := range remoteNames {
+		var err error
+		if remoteName == authoritativeRemote {
+			err = r.FetchRSL(ctx, remoteName)
+		} else {
+			_, err = r.fetchRSLTracker(ctx, remoteName)
+		}
+
+		results[remoteName] = err
+		if err != nil {
+			errs = append(errs, fmt.Errorf("unable to fetch RSL from remote '%s': %w", remoteName, err))
+		}
+	}
+
+	return results, errors.Join(errs...)
+}
+
+// RSLTrackerStatus describes the RSL fetched from a remote in comparison with
+// the RSL fetched from the authoritative remote.
+type RSLTrackerStatus struct {
+	// Tip is the tip of the remote's RSL tracker.
+	Tip plumbing.Hash
+
+	// HasUpdates indicates the remote's RSL has entries that are not in the
+	// authoritative remote's RSL.
+	HasUpdates bool
+
+	// HasDiverged indicates the remote's RSL has entries that are not in the
+	// authoritative remote's RSL, and is also missing some of the
+	// authoritative remote's entries.
+	HasDiverged bool
+
+	// IsBehind indicates the remote's RSL is missing some of the authoritative
+	// remote's entries, but has no entries of its own.
+	IsBehind bool
+}
+
+// GetAuthoritativeRSLTip returns the authoritative remote, i.e., the remote
+// that hosts gittuf's refs as returned by GetGittufRemote, and the tip of its
+// RSL tracker. If the RSL has not been fetched from the authoritative remote,
+// ErrAuthoritativeRSLNotFetched is returned.
+func (r *Repository) GetAuthoritativeRSLTip() (string, plumbing.Hash, error) {
+	remoteName, err := r.GetGittufRemote()
+	if err != nil {
+		return "", plumbing.ZeroHash, err
+	}
+
+	tip, err := gitinterface.GetTip(r.r, rsl.RemoteTrackerRef(remoteName))
+	if err != nil {
+		if errors.Is(err, plumbing.ErrReferenceNotFound) {
+			return "", plumbing.ZeroHash, fmt.Errorf("%w: '%s'", ErrAuthoritativeRSLNotFetched, remoteName)
+		}
+		return "", plumbing.ZeroHash, err
+	}
+
+	return remoteName, tip, nil
+}
+
+// CompareRSLTrackers compares the RSL tracker of each of the specified remotes
+// with the authoritative remote's RSL tracker, see GetAuthoritativeRSLTip. The
+// RSL trackers are not updated, FetchRSLFromRemotes must be used to fetch them
+// first. Remotes whose RSL has not been fetched are omitted from the returned
+// map.
+func (r *Repository) CompareRSLTrackers(remoteNames []string) (map[string]*RSLTrackerStatus, error) {
+	_, authoritativeTip, err := r.GetAuthoritativeRSLTip()
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make(map[string]*RSLTrackerStatus, len(remoteNames))
+	for _, remoteName := range remoteNames {
+		tip, err := gitinterface.GetTip(r.r, rsl.RemoteTrackerRef(remoteName))
+		if err != nil {
+			if errors.Is(err, plumbing.ErrReferenceNotFound) {
+				continue
+			}
+			return nil, err
+		}
+
+		hasUpdates, hasDiverged, err := r.compareLocalAndRemoteTips(authoritativeTip, tip)
+		if err != nil {
+			return nil, err
+		}
+
+		statuses[remoteName] = &RSLTrackerStatus{
+			Tip:         tip,
+			HasUpdates:  hasUpdates,
+			HasDiverged: hasDiverged,
+			IsBehind:    !hasUpdates && tip != authoritativeTip,
+		}
+	}
+
+	return statuses, nil
+}
+
+// fetchRSLTracker fetches the RSL from the specified remote to the remote's RSL
+// tracker and checks the fetched entries as described in FetchRSL. The local
+// RSL is not updated. The tip of the RSL tracker is returned, or the zero hash
+// if the remote has no RSL.
+func (r *Repository) fetchRSLTracker(ctx context.Context, remoteName string) (plumbing.Hash, error) {
+	trackerRef := rsl.RemoteTrackerRef(remoteName)
+
+	previousTip, err := gitinterface.GetTip(r.r, trackerRef)
+	if err != nil {
+		if !errors.Is(err, plumbing.ErrReferenceNotFound) {
+			return plumbing.ZeroHash, err
+		}
+		previousTip = plumbing.ZeroHash
+	}
+
+	// The refspec is forced so that a rewound remote RSL is fetched and
+	// inspected rather than rejected outright
+	rslRemoteRefSpec := []config.RefSpec{config.RefSpec(fmt.Sprintf("+%s:%s", rsl.Ref, trackerRef))}
+	if err := gitinterface.FetchRefSpec(ctx, r.r, remoteName, rslRemoteRefSpec); err != nil {
+		return plumbing.ZeroHash, errors.Join(ErrPullingRSL, err)
+	}
+
+	// The RSL's policy entries refer to policy commits that are not reachable
+	// from the RSL itself, so the policy is fetched to its tracker as well.
+	// This makes the policy in effect at any fetched entry available locally
+	// to verify a rewind of the RSL.
+	policyRemoteRefSpec := []config.RefSpec{config.RefSpec(fmt.Sprintf("+%s:%s", policy.PolicyRef, policy.RemoteTrackerRef(remoteName)))}
+	if err := gitinterface.FetchRefSpec(ctx, r.r, remoteName, policyRemoteRefSpec); err != nil && !errors.Is(err, git.NoMatchingRefSpecError{}) {
+		return plumbing.ZeroHash, errors.Join(ErrPullingRSL, err)
+	}
+
+	currentTip, err := gitinterface.GetTip(r.r, trackerRef)
+	if err != nil {
+		if errors.Is(err, plumbing.ErrReferenceNotFound) {
+			// The remote RSL is empty
+			return plumbing.ZeroHash, nil
+		}
+		return plumbing.ZeroHash, err
+	}
+
+	if currentTip == previousTip {
+		return currentTip, nil
+	}
+
+	if err := r.verifyFetchedRSL(ctx, previousTip, currentTip); err != nil {
+		return plumbing.ZeroHash, r.resetRefsDueToError(err, map[string]plumbing.Hash{trackerRef: previousTip})
+	}
+
+	return currentTip, nil
+}
+
+// ReconcileRSL fetches the RSL from the specified remote and merges it with the
+// local RSL if the two have diverged. The local entries that are not present
+// at the remote are re-created on top of the remote's entries, which is only
+// possible if the local and remote entries record updates for different refs.
+// See rsl.Reconcile for more details.
+func (r *Repository) ReconcileRSL(ctx context.Context, remoteName string, signCommit bool) error {
+	if err := r.FetchRSL(ctx, remoteName); err != nil {
+		return err
+	}
+
+	return rsl.Reconcile(ctx, r.r, remoteName, signCommit)
+}
+
+// verifyFetchedRSL checks that the fetched RSL entries chain onto the
+// previously fetched tip. If they don't, the fetched RSL must contain a force
+// push annotation that authorizes the rewind of the previous tip. The
+// annotation must be signed by an RSL writer or root key trusted in the policy
+// in effect at the previous tip.
+func (r *Repository) verifyFetchedRSL(ctx context.Context, previousTip, currentTip plumbing.Hash) error {
+	_, err := rsl.GetEntriesSince(r.r, previousTip, currentTip)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, rsl.ErrRSLEntryNotInChain) {
+		return errors.Join(ErrPullingRSL, err)
+	}
+
+	// The remote RSL was rewound, look for an authorizing annotation
+	entries, err := rsl.GetEntriesSince(r.r, plumbing.ZeroHash, currentTip)
+	if err != nil {
+		return errors.Join(ErrPullingRSL, err)
+	}
+	var annotationErr error
+	for _, entry := range entries {
+		annotation, isAnnotation := entry.(*rsl.AnnotationEntry)
+		if !isAnnotation || !annotation.ForcePush || !annotation.RefersTo(previousTip) {
+			continue
+		}
+
+		if err := policy.VerifyRSLRewindAnnotation(ctx, r.r, annotation, previousTip); err != nil {
+			annotationErr = errors.Join(annotationErr, err)
+			continue
+		}
+		return nil
+	}
+
+	if annotationErr != nil {
+		return fmt.Errorf("%w: rewind of previously fetched entry '%s' is not authorized: %w", ErrRSLRewound, previousTip.String(), annotationErr)
+	}
+	return fmt.Errorf("%w: previously fetched entry '%s' is not in remote RSL", ErrRSLRewound, previousTip.String())
+}
+
+// fastForwardLocalRSL updates the local RSL to the specified tip if the tip
+// descends from the local RSL's tip. Otherwise, the local RSL is left as is.
+func (r *Repository) fastForwardLocalRSL(tip plumbing.Hash) error {
+	localTip, err := gitinterface.GetTip(r.r, rsl.Ref)
+	if err != nil {
+		if !errors.Is(err, plumbing.ErrReferenceNotFound) {
+			return err
+		}
+		localTip = plumbing.ZeroHash
+	}
+
+	if !localTip.IsZero() {
+		localCommit, err := r.r.CommitObject(localTip)
+		if err != nil {
+			return err
+		}
+		knows, err := gitinterface.KnowsCommit(r.r, tip, localCommit)
+		if err != nil {
+			return err
+		}
+		if !knows {
+			return nil
+		}
+	}
+
+	return r.r.Storer.SetReference(plumbing.NewHashReference(rsl.Ref, tip))
+}
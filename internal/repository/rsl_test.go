@@ -4,17 +4,22 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/gittuf/gittuf/internal/common"
 	"github.com/gittuf/gittuf/internal/gitinterface"
 	"github.com/gittuf/gittuf/internal/policy"
 	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/gittuf/gittuf/internal/signerverifier/gpg"
 	"github.com/gittuf/gittuf/internal/third_party/go-git"
 	"github.com/gittuf/gittuf/internal/third_party/go-git/config"
 	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing"
 	"github.com/gittuf/gittuf/internal/third_party/go-git/storage/memory"
+	"github.com/gittuf/gittuf/internal/version"
 	"github.com/go-git/go-billy/v5/memfs"
 	"github.com/stretchr/testify/assert"
 )
@@ -57,6 +62,8 @@ func TestRecordRSLEntryForReference(t *testing.T) {
 	}
 	assert.Equal(t, "refs/heads/main", entry.RefName)
 	assert.Equal(t, plumbing.ZeroHash, entry.TargetID)
+	assert.Equal(t, version.GetVersion(), entry.GittufVersion)
+	assert.Empty(t, entry.SigningMethod)
 
 	testHash := plumbing.NewHash("abcdef1234567890")
 
@@ -87,6 +94,126 @@ func TestRecordRSLEntryForReference(t *testing.T) {
 	assert.Equal(t, testHash, entry.TargetID)
 }
 
+func TestRecordAndVerify(t *testing.T) {
+	repo := createTestRepositoryWithPolicy(t, "")
+
+	// The policy protects main using the GPG key
+	refName := "refs/heads/main"
+	if err := repo.r.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(refName), plumbing.ZeroHash)); err != nil {
+		t.Fatal(err)
+	}
+	signer := common.NewTestGPGSigner(t, gpgKeyName)
+
+	commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo.r, refName, 1, gpgKeyName)
+
+	t.Run("tip passes verification", func(t *testing.T) {
+		err := repo.RecordAndVerify(context.Background(), "main", true, gitinterface.WithSigner(signer))
+		assert.Nil(t, err)
+
+		entry, _, err := rsl.GetLatestReferenceEntryForRef(repo.r, refName)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, commitIDs[0], entry.TargetID)
+
+		latestEntry, err := rsl.GetLatestEntry(repo.r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, entry.ID, latestEntry.GetID())
+	})
+
+	t.Run("entry is not signed by an authorized key", func(t *testing.T) {
+		rslTip, err := gitinterface.GetTip(repo.r, rsl.Ref)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = repo.RecordAndVerify(context.Background(), "main", false)
+		assert.ErrorIs(t, err, ErrRefUpdateNotVerified)
+
+		// No entry is recorded
+		currentRSLTip, err := gitinterface.GetTip(repo.r, rsl.Ref)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, rslTip, currentRSLTip)
+	})
+
+	t.Run("tip fails verification", func(t *testing.T) {
+		rslTip, err := gitinterface.GetTip(repo.r, rsl.Ref)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// The new tip is not signed by the GPG key
+		unsignedCommitID, err := gitinterface.Commit(repo.r, gitinterface.EmptyTree(), refName, "Unsigned commit", false)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = repo.RecordAndVerify(context.Background(), "main", true, gitinterface.WithSigner(signer))
+		assert.ErrorIs(t, err, ErrRefUpdateNotVerified)
+
+		// No entry is recorded, so the unsigned commit is not recorded
+		currentRSLTip, err := gitinterface.GetTip(repo.r, rsl.Ref)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, rslTip, currentRSLTip)
+
+		entry, _, err := rsl.GetLatestReferenceEntryForRef(repo.r, refName)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, commitIDs[0], entry.TargetID)
+		assert.NotEqual(t, unsignedCommitID, entry.TargetID)
+	})
+}
+
+func TestRecordRSLEntryForDeletion(t *testing.T) {
+	r, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repo := &Repository{r: r}
+
+	if err := rsl.InitializeNamespace(repo.r); err != nil {
+		t.Fatal(err)
+	}
+
+	refName := "refs/heads/main"
+	ref := plumbing.NewHashReference(plumbing.ReferenceName(refName), plumbing.NewHash("abcdef1234567890"))
+	if err := repo.r.Storer.SetReference(ref); err != nil {
+		t.Fatal(err)
+	}
+
+	err = repo.RecordRSLEntryForDeletion(refName, false)
+	assert.ErrorIs(t, err, ErrRefNotDeleted)
+
+	if err := repo.r.Storer.RemoveReference(plumbing.ReferenceName(refName)); err != nil {
+		t.Fatal(err)
+	}
+
+	err = repo.RecordRSLEntryForDeletion("main", false)
+	assert.ErrorIs(t, err, ErrRefNotAbsolute)
+
+	err = repo.RecordRSLEntryForDeletion(refName, false)
+	assert.Nil(t, err)
+
+	latestEntry, err := rsl.GetLatestEntry(repo.r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry, ok := latestEntry.(*rsl.ReferenceEntry)
+	if !ok {
+		t.Fatal(fmt.Errorf("invalid entry type"))
+	}
+	assert.Equal(t, refName, entry.RefName)
+	assert.True(t, entry.IsDeletion())
+}
+
 func TestRecordRSLEntryForReferenceAtCommit(t *testing.T) {
 	r, err := git.Init(memory.NewStorage(), memfs.New())
 	if err != nil {
@@ -137,6 +264,7 @@ func TestRecordRSLEntryForReferenceAtCommit(t *testing.T) {
 	assert.Nil(t, err)
 
 	// Finally, let's record a couple more commits and use the older of the two
+	previousCommitID := commitID
 	commitID, err = gitinterface.Commit(repo.r, emptyTreeHash, refName, "Another commit", false)
 	if err != nil {
 		t.Fatal(err)
@@ -148,6 +276,66 @@ func TestRecordRSLEntryForReferenceAtCommit(t *testing.T) {
 
 	err = repo.RecordRSLEntryForReferenceAtCommit(refName, commitID.String(), false)
 	assert.Nil(t, err)
+
+	// The entry records where the new commits branched off the previous entry
+	// for the ref
+	latestEntry, err = rsl.GetLatestEntry(repo.r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, commitID, latestEntry.(*rsl.ReferenceEntry).TargetID)
+	assert.Equal(t, previousCommitID, latestEntry.(*rsl.ReferenceEntry).MergeBase)
+}
+
+func TestAmendLatestRSLEntry(t *testing.T) {
+	r, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repo := &Repository{r: r}
+
+	if err := rsl.InitializeNamespace(repo.r); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := repo.r.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName("refs/heads/main"), plumbing.ZeroHash)); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.RecordRSLEntryForReference("main", false); err != nil {
+		t.Fatal(err)
+	}
+
+	testHash := plumbing.NewHash("abcdef1234567890")
+	if err := repo.r.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName("refs/heads/main"), testHash)); err != nil {
+		t.Fatal(err)
+	}
+
+	err = repo.AmendLatestRSLEntry("main", false)
+	assert.Nil(t, err)
+
+	latestEntry, err := rsl.GetLatestEntry(repo.r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry, ok := latestEntry.(*rsl.ReferenceEntry)
+	if !ok {
+		t.Fatal(fmt.Errorf("invalid entry type"))
+	}
+	assert.Equal(t, "refs/heads/main", entry.RefName)
+	assert.Equal(t, testHash, entry.TargetID)
+
+	// The amended entry replaced the original entry
+	_, err = rsl.GetParentForEntry(repo.r, latestEntry)
+	assert.ErrorIs(t, err, rsl.ErrRSLEntryNotFound)
+
+	// Once the entry is on the remote, it cannot be amended
+	if err := repo.r.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(rsl.RemoteTrackerRef(DefaultRemoteName)), latestEntry.GetID())); err != nil {
+		t.Fatal(err)
+	}
+
+	err = repo.AmendLatestRSLEntry("main", false)
+	assert.ErrorIs(t, err, rsl.ErrCannotAmendPushedEntry)
 }
 
 func TestRecordRSLAnnotation(t *testing.T) {
@@ -210,6 +398,101 @@ func TestRecordRSLAnnotation(t *testing.T) {
 	assert.True(t, annotation.Skip)
 }
 
+func TestRecordRSLForcePushAnnotation(t *testing.T) {
+	r, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repo := &Repository{r: r}
+
+	if err := rsl.InitializeNamespace(repo.r); err != nil {
+		t.Fatal(err)
+	}
+
+	ref := plumbing.NewHashReference(plumbing.ReferenceName("refs/heads/main"), plumbing.ZeroHash)
+
+	if err := repo.r.Storer.SetReference(ref); err != nil {
+		t.Fatal(err)
+	}
+
+	err = repo.RecordRSLForcePushAnnotation([]string{plumbing.ZeroHash.String()}, "force push annotation", false)
+	assert.ErrorIs(t, err, rsl.ErrRSLEntryNotFound)
+
+	if err := repo.RecordRSLEntryForReference("refs/heads/main", false); err != nil {
+		t.Fatal(err)
+	}
+
+	latestEntry, err := rsl.GetLatestEntry(repo.r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entryID := latestEntry.GetID()
+
+	err = repo.RecordRSLForcePushAnnotation([]string{entryID.String()}, "force push annotation", false)
+	assert.Nil(t, err)
+
+	latestEntry, err = rsl.GetLatestEntry(repo.r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.IsType(t, &rsl.AnnotationEntry{}, latestEntry)
+
+	annotation := latestEntry.(*rsl.AnnotationEntry)
+	assert.Equal(t, "force push annotation", annotation.Message)
+	assert.Equal(t, []plumbing.Hash{entryID}, annotation.RSLEntryIDs)
+	assert.False(t, annotation.Skip)
+	assert.True(t, annotation.ForcePush)
+}
+
+func TestRecordRSLBreakGlassAnnotation(t *testing.T) {
+	r, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repo := &Repository{r: r}
+
+	if err := rsl.InitializeNamespace(repo.r); err != nil {
+		t.Fatal(err)
+	}
+
+	ref := plumbing.NewHashReference(plumbing.ReferenceName("refs/heads/main"), plumbing.ZeroHash)
+
+	if err := repo.r.Storer.SetReference(ref); err != nil {
+		t.Fatal(err)
+	}
+
+	err = repo.RecordRSLBreakGlassAnnotation([]string{plumbing.ZeroHash.String()}, "break glass annotation", false)
+	assert.ErrorIs(t, err, rsl.ErrRSLEntryNotFound)
+
+	if err := repo.RecordRSLEntryForReference("refs/heads/main", false); err != nil {
+		t.Fatal(err)
+	}
+
+	latestEntry, err := rsl.GetLatestEntry(repo.r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entryID := latestEntry.GetID()
+
+	err = repo.RecordRSLBreakGlassAnnotation([]string{entryID.String()}, "break glass annotation", false)
+	assert.Nil(t, err)
+
+	latestEntry, err = rsl.GetLatestEntry(repo.r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.IsType(t, &rsl.AnnotationEntry{}, latestEntry)
+
+	annotation := latestEntry.(*rsl.AnnotationEntry)
+	assert.Equal(t, "break glass annotation", annotation.Message)
+	assert.Equal(t, []plumbing.Hash{entryID}, annotation.RSLEntryIDs)
+	assert.False(t, annotation.Skip)
+	assert.False(t, annotation.ForcePush)
+	assert.True(t, annotation.BreakGlass)
+}
+
 func TestCheckRemoteRSLForUpdates(t *testing.T) {
 	remoteName := "origin"
 	refName := "refs/heads/main"
@@ -533,3 +816,332 @@ func TestPullRSL(t *testing.T) {
 		assert.ErrorIs(t, err, ErrPullingRSL)
 	})
 }
+
+func TestFetchRSL(t *testing.T) {
+	remoteName := "origin"
+	trackerRef := rsl.RemoteTrackerRef(remoteName)
+
+	remoteTmpDir := t.TempDir()
+	remoteRepo := createTestRepositoryWithPolicy(t, remoteTmpDir)
+
+	// Rewinds of the RSL must be authorized by an RSL writer
+	rootKeyBytes, err := os.ReadFile(filepath.Join("test-data", "root"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	gpgKeyBytes, err := os.ReadFile(filepath.Join("test-data", "gpg-pubkey.asc"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	gpgKey, err := gpg.LoadGPGKeyFromBytes(gpgKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rslWriterKeyBytes, err := json.Marshal(gpgKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := remoteRepo.AddRSLWriterKey(context.Background(), rootKeyBytes, rslWriterKeyBytes, false); err != nil {
+		t.Fatal(err)
+	}
+
+	localRepoR, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+	localRepo := &Repository{r: localRepoR}
+	if _, err := localRepo.r.CreateRemote(&config.RemoteConfig{
+		Name: remoteName,
+		URLs: []string{remoteTmpDir},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	assertTrackerMatchesRemote := func(t *testing.T) {
+		t.Helper()
+
+		remoteTip, err := gitinterface.GetTip(remoteRepo.r, rsl.Ref)
+		if err != nil {
+			t.Fatal(err)
+		}
+		trackerTip, err := gitinterface.GetTip(localRepo.r, trackerRef)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, remoteTip, trackerTip)
+	}
+
+	// Initial fetch
+	err = localRepo.FetchRSL(context.Background(), remoteName)
+	assert.Nil(t, err)
+	assertTrackerMatchesRemote(t)
+	assertLocalAndRemoteRefsMatch(t, localRepo.r, remoteRepo.r, rsl.Ref)
+
+	// No updates
+	err = localRepo.FetchRSL(context.Background(), remoteName)
+	assert.Nil(t, err)
+	assertTrackerMatchesRemote(t)
+
+	// Incremental fetch of a new entry
+	if err := rsl.NewReferenceEntry("refs/heads/main", plumbing.ZeroHash).Commit(remoteRepo.r, false); err != nil {
+		t.Fatal(err)
+	}
+	err = localRepo.FetchRSL(context.Background(), remoteName)
+	assert.Nil(t, err)
+	assertTrackerMatchesRemote(t)
+	assertLocalAndRemoteRefsMatch(t, localRepo.r, remoteRepo.r, rsl.Ref)
+
+	// Rewind the remote RSL
+	previousTip, err := gitinterface.GetTip(remoteRepo.r, rsl.Ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	previousTipCommit, err := remoteRepo.r.CommitObject(previousTip)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := remoteRepo.r.Storer.SetReference(plumbing.NewHashReference(rsl.Ref, previousTipCommit.ParentHashes[0])); err != nil {
+		t.Fatal(err)
+	}
+	if err := rsl.NewReferenceEntry("refs/heads/feature", plumbing.ZeroHash).Commit(remoteRepo.r, false); err != nil {
+		t.Fatal(err)
+	}
+
+	err = localRepo.FetchRSL(context.Background(), remoteName)
+	assert.ErrorIs(t, err, ErrRSLRewound)
+
+	trackerTip, err := gitinterface.GetTip(localRepo.r, trackerRef)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, previousTip, trackerTip)
+
+	// An unsigned annotation does not authorize the rewind
+	if err := rsl.NewForcePushAnnotationEntry([]plumbing.Hash{previousTip}, "rewind RSL").Commit(remoteRepo.r, false); err != nil {
+		t.Fatal(err)
+	}
+
+	err = localRepo.FetchRSL(context.Background(), remoteName)
+	assert.ErrorIs(t, err, ErrRSLRewound)
+	assert.ErrorIs(t, err, policy.ErrUnauthorizedSignature)
+
+	trackerTip, err = gitinterface.GetTip(localRepo.r, trackerRef)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, previousTip, trackerTip)
+
+	// Authorize the rewind using the trusted RSL writer key
+	common.CreateTestRSLAnnotationEntryCommit(t, remoteRepo.r, rsl.NewForcePushAnnotationEntry([]plumbing.Hash{previousTip}, "rewind RSL"), gpgKeyName)
+
+	err = localRepo.FetchRSL(context.Background(), remoteName)
+	assert.Nil(t, err)
+	assertTrackerMatchesRemote(t)
+
+	// The local RSL has diverged from the remote, so it's left as is
+	localTip, err := gitinterface.GetTip(localRepo.r, rsl.Ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, previousTip, localTip)
+}
+
+func TestFetchRSLFromRemotes(t *testing.T) {
+	t.Setenv(RemoteEnvKey, "")
+
+	upstreamName := "origin"
+	mirrorName := "mirror"
+
+	upstreamTmpDir := t.TempDir()
+	upstreamRepo := createTestRepositoryWithPolicy(t, upstreamTmpDir)
+
+	// The mirror has the upstream's RSL as of its creation
+	mirrorTmpDir := t.TempDir()
+	mirrorR, err := git.PlainInit(mirrorTmpDir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := mirrorR.CreateRemote(&config.RemoteConfig{Name: upstreamName, URLs: []string{upstreamTmpDir}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := gitinterface.FetchRefSpec(context.Background(), mirrorR, upstreamName, []config.RefSpec{config.RefSpec(fmt.Sprintf("%s:%s", rsl.Ref, rsl.Ref))}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The upstream moves on
+	if err := rsl.NewReferenceEntry("refs/heads/main", plumbing.ZeroHash).Commit(upstreamRepo.r, false); err != nil {
+		t.Fatal(err)
+	}
+	upstreamTip, err := gitinterface.GetTip(upstreamRepo.r, rsl.Ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mirrorTip, err := gitinterface.GetTip(mirrorR, rsl.Ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	localRepoR, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+	localRepo := &Repository{r: localRepoR}
+	for name, url := range map[string]string{upstreamName: upstreamTmpDir, mirrorName: mirrorTmpDir} {
+		if _, err := localRepo.r.CreateRemote(&config.RemoteConfig{Name: name, URLs: []string{url}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	remoteNames := []string{upstreamName, mirrorName}
+
+	// Nothing has been fetched yet
+	_, _, err = localRepo.GetAuthoritativeRSLTip()
+	assert.ErrorIs(t, err, ErrAuthoritativeRSLNotFetched)
+
+	results, err := localRepo.FetchRSLFromRemotes(context.Background(), remoteNames)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]error{upstreamName: nil, mirrorName: nil}, results)
+
+	// Each remote's RSL is stored in its own tracker
+	trackerTip, err := gitinterface.GetTip(localRepo.r, rsl.RemoteTrackerRef(upstreamName))
+	assert.Nil(t, err)
+	assert.Equal(t, upstreamTip, trackerTip)
+	trackerTip, err = gitinterface.GetTip(localRepo.r, rsl.RemoteTrackerRef(mirrorName))
+	assert.Nil(t, err)
+	assert.Equal(t, mirrorTip, trackerTip)
+
+	t.Run("default remote is authoritative", func(t *testing.T) {
+		remoteName, tip, err := localRepo.GetAuthoritativeRSLTip()
+		assert.Nil(t, err)
+		assert.Equal(t, upstreamName, remoteName)
+		assert.Equal(t, upstreamTip, tip)
+
+		// The local RSL follows the authoritative remote
+		localTip, err := gitinterface.GetTip(localRepo.r, rsl.Ref)
+		assert.Nil(t, err)
+		assert.Equal(t, upstreamTip, localTip)
+
+		statuses, err := localRepo.CompareRSLTrackers(remoteNames)
+		assert.Nil(t, err)
+		assert.Equal(t, map[string]*RSLTrackerStatus{
+			upstreamName: {Tip: upstreamTip},
+			mirrorName:   {Tip: mirrorTip, IsBehind: true},
+		}, statuses)
+	})
+
+	t.Run("mirror is authoritative", func(t *testing.T) {
+		if err := localRepo.SetGittufRemote(mirrorName); err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() {
+			if err := localRepo.SetGittufRemote(upstreamName); err != nil {
+				t.Fatal(err)
+			}
+		})
+
+		remoteName, tip, err := localRepo.GetAuthoritativeRSLTip()
+		assert.Nil(t, err)
+		assert.Equal(t, mirrorName, remoteName)
+		assert.Equal(t, mirrorTip, tip)
+
+		statuses, err := localRepo.CompareRSLTrackers(remoteNames)
+		assert.Nil(t, err)
+		assert.Equal(t, map[string]*RSLTrackerStatus{
+			upstreamName: {Tip: upstreamTip, HasUpdates: true},
+			mirrorName:   {Tip: mirrorTip},
+		}, statuses)
+	})
+
+	t.Run("remote with diverged RSL", func(t *testing.T) {
+		if err := rsl.NewReferenceEntry("refs/heads/feature", plumbing.ZeroHash).Commit(mirrorR, false); err != nil {
+			t.Fatal(err)
+		}
+		divergedTip, err := gitinterface.GetTip(mirrorR, rsl.Ref)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		results, err := localRepo.FetchRSLFromRemotes(context.Background(), remoteNames)
+		assert.Nil(t, err)
+		assert.Equal(t, map[string]error{upstreamName: nil, mirrorName: nil}, results)
+
+		statuses, err := localRepo.CompareRSLTrackers(remoteNames)
+		assert.Nil(t, err)
+		assert.Equal(t, map[string]*RSLTrackerStatus{
+			upstreamName: {Tip: upstreamTip},
+			mirrorName:   {Tip: divergedTip, HasUpdates: true, HasDiverged: true},
+		}, statuses)
+
+		// The local RSL is not updated using the non-authoritative remote
+		localTip, err := gitinterface.GetTip(localRepo.r, rsl.Ref)
+		assert.Nil(t, err)
+		assert.Equal(t, upstreamTip, localTip)
+	})
+
+	t.Run("remote that cannot be fetched", func(t *testing.T) {
+		results, err := localRepo.FetchRSLFromRemotes(context.Background(), []string{upstreamName, "missing"})
+		assert.NotNil(t, err)
+		assert.Nil(t, results[upstreamName])
+		assert.NotNil(t, results["missing"])
+	})
+}
+
+func TestReconcileRSL(t *testing.T) {
+	remoteName := "origin"
+
+	remoteTmpDir := t.TempDir()
+	remoteRepo := createTestRepositoryWithPolicy(t, remoteTmpDir)
+
+	localRepoR, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+	localRepo := &Repository{r: localRepoR}
+	if _, err := localRepo.r.CreateRemote(&config.RemoteConfig{
+		Name: remoteName,
+		URLs: []string{remoteTmpDir},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := localRepo.FetchRSL(context.Background(), remoteName); err != nil {
+		t.Fatal(err)
+	}
+
+	// The RSLs diverge with entries for different refs
+	if err := rsl.NewReferenceEntry("refs/heads/feature", plumbing.ZeroHash).Commit(remoteRepo.r, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := rsl.NewReferenceEntry("refs/heads/fix", plumbing.ZeroHash).Commit(localRepo.r, false); err != nil {
+		t.Fatal(err)
+	}
+
+	err = localRepo.ReconcileRSL(context.Background(), remoteName, false)
+	assert.Nil(t, err)
+
+	remoteTip, err := gitinterface.GetTip(remoteRepo.r, rsl.Ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	latestEntry, err := rsl.GetLatestEntry(localRepo.r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "refs/heads/fix", latestEntry.(*rsl.ReferenceEntry).RefName)
+
+	parentEntry, err := rsl.GetParentForEntry(localRepo.r, latestEntry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, remoteTip, parentEntry.GetID())
+
+	// The RSLs diverge with entries for the same ref
+	if err := rsl.NewReferenceEntry("refs/heads/main", plumbing.ZeroHash).Commit(remoteRepo.r, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := rsl.NewReferenceEntry("refs/heads/main", plumbing.ZeroHash).Commit(localRepo.r, false); err != nil {
+		t.Fatal(err)
+	}
+
+	err = localRepo.ReconcileRSL(context.Background(), remoteName, false)
+	assert.ErrorIs(t, err, rsl.ErrRSLForksConflict)
+}
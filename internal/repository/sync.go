@@ -5,55 +5,718 @@ package repository
 import (
 	"context"
 	"errors"
+	"fmt"
 	"os"
 	"strings"
 
 	"github.com/gittuf/gittuf/internal/gitinterface"
 	"github.com/gittuf/gittuf/internal/policy"
 	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/gittuf/gittuf/internal/third_party/go-git"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/config"
 	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing/object"
 )
 
 var (
 	ErrCloningRepository = errors.New("unable to clone repository")
 	ErrDirExists         = errors.New("directory exists")
+	ErrCloneIncomplete   = errors.New("clone is incomplete, it can be resumed or aborted")
+	ErrNotCloneDir       = errors.New("directory does not contain an incomplete clone")
+
+	ErrResumingPartialClone = errors.New("partial clones cannot be resumed")
+
+	ErrFetchVerificationFailed = errors.New("verification of fetched refs failed")
+	ErrCommitNotFetched        = errors.New("commit was not found in the refs fetched for its verification")
+	ErrPullNotFastForward      = errors.New("remote ref is not a fast-forward of the local ref and the update is not authorized by a force push annotation")
+	ErrPushNotFastForward      = errors.New("local gittuf ref is not a fast-forward of the remote ref")
+	ErrPullGittufRefRewound    = errors.New("remote gittuf ref is not a fast-forward of the local ref")
 )
 
+// PushNotFastForwardError is returned by CheckRemoteForPush when pushing a
+// gittuf ref would rewind the remote's copy of the ref. It wraps
+// ErrPushNotFastForward.
+type PushNotFastForwardError struct {
+	RefName   string
+	LocalTip  plumbing.Hash
+	RemoteTip plumbing.Hash
+
+	// Diverged indicates that the local and remote refs each contain changes
+	// that the other does not. Otherwise, the local ref is behind the remote
+	// ref.
+	Diverged bool
+}
+
+func (e *PushNotFastForwardError) Error() string {
+	if e.Diverged {
+		return fmt.Sprintf("%s: local '%s' at '%s' has diverged from remote at '%s'", ErrPushNotFastForward.Error(), e.RefName, e.LocalTip.String(), e.RemoteTip.String())
+	}
+
+	return fmt.Sprintf("%s: local '%s' at '%s' is behind remote at '%s'", ErrPushNotFastForward.Error(), e.RefName, e.LocalTip.String(), e.RemoteTip.String())
+}
+
+func (e *PushNotFastForwardError) Unwrap() error {
+	return ErrPushNotFastForward
+}
+
+// CloneOptions contains the optional parameters of Clone.
+type CloneOptions struct {
+	// PartialCloneFilter is the object filter used to perform a partial clone.
+	PartialCloneFilter string
+
+	// PolicyPins are the out of band trust anchors the cloned policy must
+	// match.
+	PolicyPins *policy.PolicyPins
+
+	// Resumable indicates that the progress of the clone must be kept if it
+	// fails, so that it can be resumed.
+	Resumable bool
+
+	// SyncOptions configure the fetches performed by a resumable clone.
+	SyncOptions []gitinterface.SyncOption
+}
+
+// CloneOption is used to configure Clone.
+type CloneOption func(*CloneOptions)
+
+// WithPartialClone configures Clone to perform a partial clone using the
+// specified object filter, such as "blob:none". The gittuf refs are always
+// fetched in full.
+func WithPartialClone(filter string) CloneOption {
+	return func(o *CloneOptions) {
+		o.PartialCloneFilter = filter
+	}
+}
+
+// WithExpectedPolicy configures Clone to only accept the cloned repository if
+// its policy matches the pins, which are obtained out of band. If the policy
+// does not match, the cloned repository is removed. This provides a trust on
+// first use bootstrap of the repository's root of trust, rather than trusting
+// whichever policy is cloned.
+func WithExpectedPolicy(pins *policy.PolicyPins) CloneOption {
+	return func(o *CloneOptions) {
+		o.PolicyPins = pins
+	}
+}
+
+// WithResumableClone configures Clone to keep the progress of the clone if it
+// fails, for example due to a network interruption. Invoking Clone with this
+// option again for the same directory resumes the clone, fetching only the
+// refs and objects that are missing. An incomplete clone can be discarded
+// using AbortClone. The fetches are configured using opts, e.g., to retry
+// transient failures.
+func WithResumableClone(opts ...gitinterface.SyncOption) CloneOption {
+	return func(o *CloneOptions) {
+		o.Resumable = true
+		o.SyncOptions = opts
+	}
+}
+
 // Clone wraps a typical git clone invocation, fetching gittuf refs in addition
 // to the standard refs. It performs a verification of the RSL against the
-// specified HEAD after cloning the repository.
-// TODO: resolve how root keys are trusted / bootstrapped.
-func Clone(ctx context.Context, remoteURL, dir, initialBranch string) (*Repository, error) {
-	if dir == "" {
-		// FIXME: my understanding is backslashes are not used in URLs but I haven't dived into the RFCs to check yet
-		split := strings.Split(strings.TrimSpace(strings.ReplaceAll(remoteURL, "\\", "/")), "/")
-		dir = strings.TrimSuffix(split[len(split)-1], ".git")
+// specified HEAD after cloning the repository, see VerifyHEAD. Unless the
+// policy is pinned using WithExpectedPolicy, the cloned policy is trusted on
+// first use.
+//
+// If the repository is cloned but its gittuf refs cannot be fetched, the
+// cloned repository is kept and an error wrapping ErrCloneIncomplete is
+// returned. Other failures remove the directory, unless the clone is made
+// resumable using WithResumableClone.
+func Clone(ctx context.Context, remoteURL, dir, initialBranch string, opts ...CloneOption) (*Repository, error) {
+	options := &CloneOptions{}
+	for _, fn := range opts {
+		fn(options)
 	}
+
+	if options.Resumable && len(options.PartialCloneFilter) > 0 {
+		return nil, errors.Join(ErrCloningRepository, ErrResumingPartialClone)
+	}
+
+	dir = getCloneDir(remoteURL, dir)
 	_, err := os.Stat(dir)
 	if err == nil {
-		return nil, errors.Join(ErrCloningRepository, ErrDirExists)
+		if !options.Resumable {
+			return nil, errors.Join(ErrCloningRepository, ErrDirExists)
+		}
+
+		// Only a previously interrupted clone of the same remote can be
+		// resumed
+		r, err := git.PlainOpen(dir)
+		if err != nil {
+			return nil, errors.Join(ErrCloningRepository, ErrDirExists, err)
+		}
+		if err := gitinterface.CheckIncompleteClone(r, remoteURL); err != nil {
+			return nil, errors.Join(ErrCloningRepository, ErrDirExists, err)
+		}
 	} else if !os.IsNotExist(err) {
 		return nil, errors.Join(ErrCloningRepository, err)
-	}
-
-	if err := os.Mkdir(dir, 0755); err != nil {
+	} else if err := os.Mkdir(dir, 0755); err != nil {
 		return nil, errors.Join(ErrCloningRepository, err)
 	}
 
 	refs := []string{rsl.Ref, policy.PolicyRef}
 
-	r, err := gitinterface.CloneAndFetch(ctx, remoteURL, dir, initialBranch, refs)
-	if err != nil {
+	var r *git.Repository
+	if options.Resumable {
+		r, err = gitinterface.ResumableCloneAndFetch(ctx, remoteURL, dir, initialBranch, refs, options.SyncOptions...)
+		if err != nil {
+			return nil, errors.Join(ErrCloningRepository, ErrCloneIncomplete, err)
+		}
+	} else {
+		cloneOpts := []gitinterface.CloneOption{}
+		if len(options.PartialCloneFilter) > 0 {
+			cloneOpts = append(cloneOpts, gitinterface.WithPartialClone(options.PartialCloneFilter))
+		}
+
+		r, err = gitinterface.CloneAndFetch(ctx, remoteURL, dir, initialBranch, refs, cloneOpts...)
+		if err != nil {
+			if errors.Is(err, gitinterface.ErrFetchingRefsAfterClone) {
+				// The clone is kept so that it can be resumed or aborted
+				if e := markCloneIncomplete(dir); e != nil {
+					return nil, errors.Join(ErrCloningRepository, ErrCloneIncomplete, err, e)
+				}
+				return nil, errors.Join(ErrCloningRepository, ErrCloneIncomplete, err)
+			}
+
+			if e := os.RemoveAll(dir); e != nil {
+				return nil, errors.Join(ErrCloningRepository, err, e)
+			}
+			return nil, errors.Join(ErrCloningRepository, err)
+		}
+	}
+	if err := policy.CheckPolicyPins(ctx, r, options.PolicyPins); err != nil {
 		if e := os.RemoveAll(dir); e != nil {
 			return nil, errors.Join(ErrCloningRepository, err, e)
 		}
 		return nil, errors.Join(ErrCloningRepository, err)
 	}
-	head, err := r.Reference(plumbing.HEAD, false)
+
+	repository := &Repository{r: r}
+	_, err = repository.VerifyHEAD(ctx, true)
+	return repository, err
+}
+
+// AbortClone removes the directory of an incomplete clone. The directory is
+// determined the same way as in Clone. To guard against removing unrelated
+// directories, the directory must contain a repository that is marked as an
+// incomplete clone of remoteURL, see gitinterface.CheckIncompleteClone.
+func AbortClone(remoteURL, dir string) error {
+	dir = getCloneDir(remoteURL, dir)
+	r, err := git.PlainOpen(dir)
 	if err != nil {
-		return nil, errors.Join(ErrCloningRepository, err)
+		return errors.Join(ErrNotCloneDir, err)
+	}
+	if err := gitinterface.CheckIncompleteClone(r, remoteURL); err != nil {
+		return errors.Join(ErrNotCloneDir, err)
 	}
 
-	repository := &Repository{r: r}
-	return repository, repository.VerifyRef(ctx, head.Target().String(), true)
+	return os.RemoveAll(dir)
+}
+
+// markCloneIncomplete marks the repository in dir as an incomplete clone.
+func markCloneIncomplete(dir string) error {
+	r, err := git.PlainOpen(dir)
+	if err != nil {
+		return err
+	}
+
+	return gitinterface.MarkCloneIncomplete(r)
+}
+
+func getCloneDir(remoteURL, dir string) string {
+	if dir != "" {
+		return dir
+	}
+
+	// FIXME: my understanding is backslashes are not used in URLs but I haven't dived into the RFCs to check yet
+	split := strings.Split(strings.TrimSpace(strings.ReplaceAll(remoteURL, "\\", "/")), "/")
+	return strings.TrimSuffix(split[len(split)-1], ".git")
+}
+
+// Fetch wraps a typical git fetch invocation for the specified refs, fetching
+// the gittuf refs in addition to the requested refs. If verify is set, the
+// fetched RSL entries are verified in order starting from the locally trusted
+// policy, so that a fetched policy is only trusted if it is a valid transition
+// from the local policy, and each of the requested refs is verified against the
+// fetched RSL and policy. If the verification fails, the local RSL, policy, and
+// requested refs are reset to their states prior to the fetch, and the remote's
+// changes are only available via the remote tracker refs.
+//
+// If no local policy exists prior to the fetch, the fetched policy is trusted
+// for the verification, matching the behavior of Clone.
+// TODO: resolve how root keys are trusted / bootstrapped.
+func (r *Repository) Fetch(ctx context.Context, remoteName string, refs []string, verify bool) error {
+	refNames := []string{rsl.Ref, policy.PolicyRef}
+	for _, ref := range refs {
+		refName, err := gitinterface.AbsoluteReference(r.r, ref)
+		if err != nil {
+			if !errors.Is(err, gitinterface.ErrReferenceNotFound) {
+				return err
+			}
+
+			// The ref doesn't exist locally yet, assume it's a branch
+			refName = string(plumbing.NewBranchReferenceName(ref))
+		}
+
+		refNames = append(refNames, refName)
+	}
+
+	if !verify {
+		return gitinterface.Fetch(ctx, r.r, remoteName, refNames, true)
+	}
+
+	previousTips := make(map[string]plumbing.Hash, len(refNames))
+	for _, refName := range refNames {
+		tip, err := gitinterface.GetTip(r.r, refName)
+		if err != nil {
+			if !errors.Is(err, gitinterface.ErrReferenceNotFound) {
+				return err
+			}
+
+			tip = plumbing.ZeroHash
+		}
+
+		previousTips[refName] = tip
+	}
+
+	if err := gitinterface.Fetch(ctx, r.r, remoteName, refNames, true); err != nil {
+		return err
+	}
+
+	// The first two refs are the gittuf refs
+	if err := verifyFetchedEntries(ctx, r.r, previousTips[rsl.Ref], previousTips[policy.PolicyRef], refNames[2:]); err != nil {
+		return r.resetRefsDueToError(errors.Join(ErrFetchVerificationFailed, err), previousTips)
+	}
+	for _, refName := range refNames[2:] {
+		if err := policy.VerifyRef(ctx, r.r, refName); err != nil {
+			return r.resetRefsDueToError(errors.Join(ErrFetchVerificationFailed, err), previousTips)
+		}
+	}
+
+	return nil
+}
+
+// PullRef fetches the specified ref and the gittuf refs from the remote into
+// the remote tracker refs, and verifies the remote's state of the ref against
+// the fetched RSL and policy. The fetched RSL entries are verified in order
+// starting from the locally trusted policy, so that a fetched policy is only
+// trusted if it is a valid transition from the local policy. Verification uses
+// a view of the repository in which the local RSL, policy, and ref are at their
+// fetched states, and only once it succeeds are the local refs updated. The
+// local ref is only updated if the remote's state is a fast-forward of the
+// local state, or if the latest RSL entry for the ref is accompanied by a force
+// push annotation. If the ref is checked out, the worktree is updated as well.
+//
+// If the verification fails or the update is refused, the local RSL, policy,
+// and ref are unchanged. The remote's changes are only available via the
+// remote tracker refs.
+func (r *Repository) PullRef(ctx context.Context, remoteName, refName string) error {
+	absRefName, err := gitinterface.AbsoluteReference(r.r, refName)
+	if err != nil {
+		if !errors.Is(err, gitinterface.ErrReferenceNotFound) {
+			return err
+		}
+
+		// The ref doesn't exist locally yet, assume it's a branch
+		absRefName = string(plumbing.NewBranchReferenceName(refName))
+	}
+
+	refNames := []string{rsl.Ref, policy.PolicyRef, absRefName}
+	localTips := make(map[string]plumbing.Hash, len(refNames))
+	for _, name := range refNames {
+		tip, err := gitinterface.GetTip(r.r, name)
+		if err != nil {
+			if !errors.Is(err, plumbing.ErrReferenceNotFound) {
+				return err
+			}
+
+			tip = plumbing.ZeroHash
+		}
+
+		localTips[name] = tip
+	}
+
+	// The refs are only fetched into their remote trackers until they are
+	// verified
+	refSpecs := make([]config.RefSpec, 0, len(refNames))
+	for _, name := range refNames {
+		refSpec, err := gitinterface.RefSpec(r.r, name, remoteName, false)
+		if err != nil {
+			return err
+		}
+		refSpecs = append(refSpecs, refSpec)
+	}
+	if err := gitinterface.FetchRefSpec(ctx, r.r, remoteName, refSpecs); err != nil {
+		return err
+	}
+
+	remoteTips := make(map[string]plumbing.Hash, len(refNames))
+	for _, name := range refNames {
+		tip, err := gitinterface.GetTip(r.r, gitinterface.RemoteRef(name, remoteName))
+		if err != nil {
+			return err
+		}
+
+		remoteTips[name] = tip
+	}
+
+	// The gittuf refs must not be rewound by the remote
+	for _, name := range []string{rsl.Ref, policy.PolicyRef} {
+		if localTips[name].IsZero() || localTips[name] == remoteTips[name] {
+			continue
+		}
+
+		localCommit, err := r.r.CommitObject(localTips[name])
+		if err != nil {
+			return err
+		}
+		isFastForward, err := gitinterface.KnowsCommit(r.r, remoteTips[name], localCommit)
+		if err != nil {
+			return err
+		}
+		if !isFastForward {
+			return fmt.Errorf("%w: '%s'", ErrPullGittufRefRewound, name)
+		}
+	}
+
+	// Verify the refs as they would be after the update
+	proposedRefs := make(map[plumbing.ReferenceName]plumbing.Hash, len(refNames))
+	for _, name := range refNames {
+		proposedRefs[plumbing.ReferenceName(name)] = remoteTips[name]
+	}
+	proposedRepo, err := git.Open(&proposedRefsStorer{Storer: r.r.Storer, refs: proposedRefs}, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := verifyFetchedEntries(ctx, proposedRepo, localTips[rsl.Ref], localTips[policy.PolicyRef], []string{absRefName}); err != nil {
+		return errors.Join(ErrFetchVerificationFailed, err)
+	}
+
+	localTip, remoteTip := localTips[absRefName], remoteTips[absRefName]
+	if remoteTip != localTip {
+		if err := policy.VerifyRef(ctx, proposedRepo, absRefName); err != nil {
+			return errors.Join(ErrFetchVerificationFailed, err)
+		}
+
+		if !localTip.IsZero() {
+			localCommit, err := r.r.CommitObject(localTip)
+			if err != nil {
+				return err
+			}
+			isFastForward, err := gitinterface.KnowsCommit(r.r, remoteTip, localCommit)
+			if err != nil {
+				return err
+			}
+
+			if !isFastForward {
+				latestEntry, annotations, err := rsl.GetLatestReferenceEntryForRef(proposedRepo, absRefName)
+				if err != nil {
+					return err
+				}
+
+				authorized := false
+				for _, annotation := range annotations {
+					if annotation.ForcePush && annotation.RefersTo(latestEntry.ID) {
+						authorized = true
+						break
+					}
+				}
+				if !authorized {
+					return fmt.Errorf("%w: '%s'", ErrPullNotFastForward, absRefName)
+				}
+			}
+		}
+	}
+
+	for _, name := range []string{rsl.Ref, policy.PolicyRef} {
+		if remoteTips[name] == localTips[name] {
+			continue
+		}
+		if err := r.r.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(name), remoteTips[name])); err != nil {
+			return err
+		}
+	}
+
+	if remoteTip == localTip {
+		return nil
+	}
+
+	return r.updateRef(absRefName, remoteTip)
+}
+
+// CheckRemoteForPush checks that pushing the local RSL and policy to the
+// specified remote will not rewind the remote's copies of them. The remote tips
+// are fetched to the local repository's remote tracker refs, and each local tip
+// must descend from the corresponding remote tip. If a local ref is behind or
+// has diverged from the remote ref, a *PushNotFastForwardError is returned for
+// it, and the local ref must be reconciled with the remote before it is pushed.
+// Refs that do not exist at the remote are not checked.
+func (r *Repository) CheckRemoteForPush(ctx context.Context, remoteName string) error {
+	trackerRefs := map[string]string{
+		rsl.Ref:          rsl.RemoteTrackerRef(remoteName),
+		policy.PolicyRef: policy.RemoteTrackerRef(remoteName),
+	}
+
+	errs := []error{}
+	for _, refName := range []string{rsl.Ref, policy.PolicyRef} {
+		trackerRef := trackerRefs[refName]
+
+		refSpec := config.RefSpec(fmt.Sprintf("%s:%s", refName, trackerRef))
+		if err := gitinterface.FetchRefSpec(ctx, r.r, remoteName, []config.RefSpec{refSpec}); err != nil {
+			if errors.Is(err, git.NoMatchingRefSpecError{}) {
+				// The ref doesn't exist at the remote
+				continue
+			}
+			return err
+		}
+
+		remoteTip, err := gitinterface.GetTip(r.r, trackerRef)
+		if err != nil {
+			if errors.Is(err, plumbing.ErrReferenceNotFound) {
+				// The remote is empty
+				continue
+			}
+			return err
+		}
+
+		localTip, err := gitinterface.GetTip(r.r, refName)
+		if err != nil {
+			if !errors.Is(err, plumbing.ErrReferenceNotFound) {
+				return err
+			}
+			localTip = plumbing.ZeroHash
+		}
+
+		hasUpdates, hasDiverged, err := r.compareLocalAndRemoteTips(localTip, remoteTip)
+		if err != nil {
+			return err
+		}
+		if hasUpdates {
+			errs = append(errs, &PushNotFastForwardError{
+				RefName:   refName,
+				LocalTip:  localTip,
+				RemoteTip: remoteTip,
+				Diverged:  hasDiverged,
+			})
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// updateRef sets the ref to the specified target. If the ref is checked out,
+// the worktree is updated to match the target, retaining local changes that
+// do not conflict with the update.
+func (r *Repository) updateRef(refName string, target plumbing.Hash) error {
+	head, err := r.r.Reference(plumbing.HEAD, false)
+	if err != nil {
+		return err
+	}
+
+	if head.Type() == plumbing.SymbolicReference && head.Target() == plumbing.ReferenceName(refName) {
+		worktree, err := r.r.Worktree()
+		if err == nil {
+			return worktree.Reset(&git.ResetOptions{Commit: target, Mode: git.MergeReset})
+		}
+		if !errors.Is(err, git.ErrIsBareRepository) {
+			return err
+		}
+	}
+
+	return r.r.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(refName), target))
+}
+
+// CommitVerificationFetchPlan records the refs that must be fetched from a
+// remote to verify a single commit.
+type CommitVerificationFetchPlan struct {
+	// CommitID is the commit to be verified.
+	CommitID plumbing.Hash
+
+	// Refs are the refs whose histories are needed to verify the commit.
+	// These are the refs recorded in the RSL whose recorded targets are not
+	// available locally, and the ref the commit was requested from, if any.
+	// The RSL and policy refs are not included as they are always fetched in
+	// full.
+	Refs []string
+}
+
+// PlanFetchForCommitVerification determines the minimal set of refs that must
+// be fetched from the remote to verify the specified commit. Rather than
+// fetching every branch and tag, which is expensive in large repositories,
+// the RSL is used to bound the refs to those that the policy lookup performed
+// by policy.GetStateForCommit may inspect.
+//
+// The RSL and policy refs are fetched before the plan is created as the RSL
+// is needed to identify the relevant refs. If commitRef is set, it is a ref on
+// the remote that points to the commit or one of its descendants, such as a
+// pull request's head ref, and is included in the plan if the commit is not
+// available locally.
+func (r *Repository) PlanFetchForCommitVerification(ctx context.Context, remoteName string, commitID plumbing.Hash, commitRef string) (*CommitVerificationFetchPlan, error) {
+	if err := gitinterface.Fetch(ctx, r.r, remoteName, []string{rsl.Ref, policy.PolicyRef}, true); err != nil {
+		return nil, err
+	}
+
+	plan := &CommitVerificationFetchPlan{CommitID: commitID}
+	refsInPlan := map[string]bool{}
+	addRef := func(refName string) {
+		if !refsInPlan[refName] {
+			refsInPlan[refName] = true
+			plan.Refs = append(plan.Refs, refName)
+		}
+	}
+
+	commit, err := r.r.CommitObject(commitID)
+	if err != nil {
+		if !errors.Is(err, plumbing.ErrObjectNotFound) {
+			return nil, err
+		}
+
+		// The commit must be fetched as well
+		if len(commitRef) > 0 {
+			if !strings.HasPrefix(commitRef, gitinterface.RefPrefix) {
+				commitRef = string(plumbing.NewBranchReferenceName(commitRef))
+			}
+			addRef(commitRef)
+		}
+	}
+
+	// deletedRefs tracks refs whose latest entry records their deletion, as
+	// they can't be fetched from the remote
+	deletedRefs := map[string]bool{}
+	seenRefs := map[string]bool{}
+
+	entry, _, err := rsl.GetLatestNonGittufReferenceEntry(r.r)
+	for err == nil {
+		if !seenRefs[entry.RefName] {
+			seenRefs[entry.RefName] = true
+			deletedRefs[entry.RefName] = entry.TargetID.IsZero()
+		}
+
+		if !entry.TargetID.IsZero() && !deletedRefs[entry.RefName] {
+			available, knowsCommit := r.checkEntryTargetKnowsCommit(entry.TargetID, commit)
+			if !available {
+				addRef(entry.RefName)
+			} else if commit != nil && !knowsCommit {
+				// The lookup for the first entry to record the commit
+				// stops here, older entries are not inspected
+				break
+			}
+		}
+
+		entry, _, err = rsl.GetNonGittufParentReferenceEntryForEntry(r.r, entry)
+	}
+	if err != nil && !errors.Is(err, rsl.ErrRSLEntryNotFound) {
+		return nil, err
+	}
+
+	return plan, nil
+}
+
+// FetchForCommitVerification fetches only the refs identified by
+// PlanFetchForCommitVerification, in addition to the RSL and policy refs, so
+// that the specified commit can be verified. The refs are fetched into their
+// remote tracker refs so that local branches are not modified. If the commit is
+// not available after the fetch, ErrCommitNotFetched is returned.
+func (r *Repository) FetchForCommitVerification(ctx context.Context, remoteName string, commitID plumbing.Hash, commitRef string) (*CommitVerificationFetchPlan, error) {
+	plan, err := r.PlanFetchForCommitVerification(ctx, remoteName, commitID, commitRef)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(plan.Refs) > 0 {
+		refSpecs := make([]config.RefSpec, 0, len(plan.Refs))
+		for _, refName := range plan.Refs {
+			refSpec, err := gitinterface.RefSpec(r.r, refName, remoteName, false)
+			if err != nil {
+				return nil, err
+			}
+			refSpecs = append(refSpecs, refSpec)
+		}
+
+		if err := gitinterface.FetchRefSpec(ctx, r.r, remoteName, refSpecs); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := r.r.CommitObject(commitID); err != nil {
+		if errors.Is(err, plumbing.ErrObjectNotFound) {
+			return nil, fmt.Errorf("%w: %s", ErrCommitNotFetched, commitID.String())
+		}
+		return nil, err
+	}
+
+	return plan, nil
+}
+
+// checkEntryTargetKnowsCommit indicates if the target of an RSL entry and its
+// history are available locally and, if so, whether the target knows the
+// commit. If commit is nil, only the availability of the target is checked.
+func (r *Repository) checkEntryTargetKnowsCommit(targetID plumbing.Hash, commit *object.Commit) (bool, bool) {
+	if _, err := r.r.CommitObject(targetID); err != nil {
+		return false, false
+	}
+	if commit == nil {
+		return true, false
+	}
+
+	knowsCommit, err := gitinterface.KnowsCommit(r.r, targetID, commit)
+	if err != nil {
+		// Parts of the target's history are missing
+		return false, false
+	}
+
+	return true, knowsCommit
+}
+
+// verifyFetchedEntries verifies the RSL entries recorded in repo after
+// previousRSLTip in order using policy.VerifyNewEntries. This ensures that a
+// fetched policy is only trusted if it is a valid transition from the policy
+// trusted locally before the fetch. If no policy existed locally, the fetched
+// policy is trusted, matching the behavior of Clone. Failures of entries for
+// the specified refs are returned, while entries for other refs are verified
+// when those refs are.
+func verifyFetchedEntries(ctx context.Context, repo *git.Repository, previousRSLTip, previousPolicyTip plumbing.Hash, refNames []string) error {
+	if previousPolicyTip.IsZero() {
+		return nil
+	}
+
+	currentRSLTip, err := gitinterface.GetTip(repo, rsl.Ref)
+	if err != nil {
+		return err
+	}
+	if currentRSLTip == previousRSLTip {
+		return nil
+	}
+
+	entryErrs, err := policy.VerifyNewEntries(ctx, repo, previousRSLTip, currentRSLTip)
+	if err != nil {
+		return err
+	}
+	for _, refName := range refNames {
+		if err, failed := entryErrs[refName]; failed {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// resetRefsDueToError resets each of the specified refs to the corresponding
+// hash, deleting refs that are set to the zero hash. This is used to reverse
+// the changes made by a fetch that could not be verified.
+func (r *Repository) resetRefsDueToError(cause error, tips map[string]plumbing.Hash) error {
+	for refName, tip := range tips {
+		var err error
+		if tip.IsZero() {
+			err = r.r.Storer.RemoveReference(plumbing.ReferenceName(refName))
+		} else {
+			err = r.r.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(refName), tip))
+		}
+		if err != nil {
+			return fmt.Errorf("unable to reset %s to %s, caused by following error: %w", refName, tip.String(), cause)
+		}
+	}
+
+	return cause
 }
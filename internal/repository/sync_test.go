@@ -4,6 +4,7 @@ package repository
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
@@ -12,7 +13,11 @@ import (
 	"github.com/gittuf/gittuf/internal/policy"
 	"github.com/gittuf/gittuf/internal/rsl"
 	"github.com/gittuf/gittuf/internal/third_party/go-git"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/config"
 	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing/filemode"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing/object"
+	"github.com/gittuf/gittuf/internal/tuf"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -172,6 +177,98 @@ func TestClone(t *testing.T) {
 		assert.Equal(t, remotePolicyRef.Hash(), localPolicyRef.Hash())
 	})
 
+	t.Run("successful blobless clone", func(t *testing.T) {
+		localTmpDir := t.TempDir()
+
+		if err := os.Chdir(localTmpDir); err != nil {
+			t.Fatal(err)
+		}
+		defer os.Chdir(currentDir) //nolint:errcheck
+
+		remoteConfig, err := remoteRepo.r.Config()
+		if err != nil {
+			t.Fatal(err)
+		}
+		remoteConfig.Raw.Section("uploadpack").SetOption("allowFilter", "true")
+		if err := remoteRepo.r.SetConfig(remoteConfig); err != nil {
+			t.Fatal(err)
+		}
+
+		// The policy is verified after the clone, so the gittuf refs must be
+		// available in full
+		repo, err := Clone(context.Background(), remoteTmpDir, "", "", WithPartialClone(gitinterface.BloblessFilter))
+		assert.Nil(t, err)
+		head, err := repo.r.Head()
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, commitID, head.Hash())
+
+		localRSLRef, err := repo.r.Reference(plumbing.ReferenceName(rsl.Ref), true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, remoteRSLRef.Hash(), localRSLRef.Hash())
+		localPolicyRef, err := repo.r.Reference(plumbing.ReferenceName(policy.PolicyRef), true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, remotePolicyRef.Hash(), localPolicyRef.Hash())
+
+		_, err = policy.LoadCurrentState(context.Background(), repo.r)
+		assert.Nil(t, err)
+	})
+
+	t.Run("successful clone with matching policy pins", func(t *testing.T) {
+		localTmpDir := t.TempDir()
+		dirName := filepath.Join(localTmpDir, "myRepo")
+
+		rootPubKeyBytes, err := os.ReadFile(filepath.Join("test-data", "root.pub"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		rootKey, err := tuf.LoadKeyFromBytes(rootPubKeyBytes)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		pins := &policy.PolicyPins{
+			PolicyCommitID: remotePolicyRef.Hash().String(),
+			RootKeyIDs:     []string{rootKey.KeyID},
+		}
+
+		repo, err := Clone(context.Background(), remoteTmpDir, dirName, "", WithExpectedPolicy(pins))
+		if err != nil {
+			t.Fatal(err)
+		}
+		head, err := repo.r.Head()
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, commitID, head.Hash())
+	})
+
+	t.Run("unsuccessful clone with mismatched policy pins", func(t *testing.T) {
+		localTmpDir := t.TempDir()
+		dirName := filepath.Join(localTmpDir, "myRepo")
+
+		targetsKey, err := tuf.LoadKeyFromBytes(targetsPubKeyBytes)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		pins := &policy.PolicyPins{
+			RootKeyIDs: []string{targetsKey.KeyID},
+		}
+
+		_, err = Clone(context.Background(), remoteTmpDir, dirName, "", WithExpectedPolicy(pins))
+		assert.ErrorIs(t, err, ErrCloningRepository)
+		assert.ErrorIs(t, err, policy.ErrPolicyPinMismatch)
+
+		_, err = os.Stat(dirName)
+		assert.True(t, os.IsNotExist(err))
+	})
+
 	t.Run("unsuccessful clone when unspecified dir already exists", func(t *testing.T) {
 		localTmpDir := t.TempDir()
 
@@ -202,4 +299,698 @@ func TestClone(t *testing.T) {
 		_, err = Clone(context.Background(), remoteTmpDir, dirName, "")
 		assert.ErrorIs(t, err, ErrDirExists)
 	})
+
+	t.Run("incomplete clone when gittuf refs are missing", func(t *testing.T) {
+		otherRemoteTmpDir := t.TempDir()
+		otherRemoteR, err := git.PlainInit(otherRemoteTmpDir, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		emptyTreeHash, err := gitinterface.WriteTree(otherRemoteR, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := gitinterface.Commit(otherRemoteR, emptyTreeHash, refName, "Initial commit", false); err != nil {
+			t.Fatal(err)
+		}
+		if err := otherRemoteR.Storer.SetReference(plumbing.NewSymbolicReference(plumbing.HEAD, plumbing.ReferenceName(refName))); err != nil {
+			t.Fatal(err)
+		}
+
+		dirName := filepath.Join(t.TempDir(), "myRepo")
+
+		_, err = Clone(context.Background(), otherRemoteTmpDir, dirName, "")
+		assert.ErrorIs(t, err, ErrCloneIncomplete)
+		assert.ErrorIs(t, err, gitinterface.ErrFetchingRefsAfterClone)
+
+		// The cloned repository is kept
+		_, err = git.PlainOpen(dirName)
+		assert.Nil(t, err)
+
+		err = AbortClone(otherRemoteTmpDir, dirName)
+		assert.Nil(t, err)
+		_, err = os.Stat(dirName)
+		assert.True(t, os.IsNotExist(err))
+	})
+
+	t.Run("resume interrupted clone", func(t *testing.T) {
+		dirName := filepath.Join(t.TempDir(), "myRepo")
+
+		// Simulate a clone that was interrupted before any refs were fetched
+		localR, err := git.PlainInit(dirName, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := gitinterface.MarkCloneIncomplete(localR); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := localR.CreateRemote(&config.RemoteConfig{
+			Name:  gitinterface.DefaultRemoteName,
+			URLs:  []string{remoteTmpDir},
+			Fetch: []config.RefSpec{config.RefSpec(fmt.Sprintf(config.DefaultFetchRefSpec, gitinterface.DefaultRemoteName))},
+		}); err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = Clone(context.Background(), remoteTmpDir, dirName, "")
+		assert.ErrorIs(t, err, ErrDirExists)
+
+		repo, err := Clone(context.Background(), remoteTmpDir, dirName, "", WithResumableClone())
+		assert.Nil(t, err)
+		head, err := repo.r.Head()
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, commitID, head.Hash())
+
+		localRSLRef, err := repo.r.Reference(plumbing.ReferenceName(rsl.Ref), true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, remoteRSLRef.Hash(), localRSLRef.Hash())
+
+		// The clone is complete, so it can no longer be resumed or aborted
+		_, err = Clone(context.Background(), remoteTmpDir, dirName, "", WithResumableClone())
+		assert.ErrorIs(t, err, gitinterface.ErrNotIncompleteClone)
+
+		err = AbortClone(remoteTmpDir, dirName)
+		assert.ErrorIs(t, err, ErrNotCloneDir)
+		_, err = os.Stat(dirName)
+		assert.Nil(t, err)
+	})
+
+	t.Run("unsuccessful abort of repository that is not a clone", func(t *testing.T) {
+		dirName := filepath.Join(t.TempDir(), "myRepo")
+		if _, err := git.PlainInit(dirName, false); err != nil {
+			t.Fatal(err)
+		}
+
+		err := AbortClone(remoteTmpDir, dirName)
+		assert.ErrorIs(t, err, gitinterface.ErrNotIncompleteClone)
+		_, err = os.Stat(dirName)
+		assert.Nil(t, err)
+	})
+
+	t.Run("unsuccessful resumable clone when dir is not a repository", func(t *testing.T) {
+		dirName := filepath.Join(t.TempDir(), "myRepo")
+		if err := os.Mkdir(dirName, 0755); err != nil {
+			t.Fatal(err)
+		}
+
+		_, err := Clone(context.Background(), remoteTmpDir, dirName, "", WithResumableClone())
+		assert.ErrorIs(t, err, ErrDirExists)
+
+		err = AbortClone(remoteTmpDir, dirName)
+		assert.ErrorIs(t, err, ErrNotCloneDir)
+		_, err = os.Stat(dirName)
+		assert.Nil(t, err)
+	})
+
+	t.Run("unsuccessful resumable partial clone", func(t *testing.T) {
+		dirName := filepath.Join(t.TempDir(), "myRepo")
+
+		_, err := Clone(context.Background(), remoteTmpDir, dirName, "", WithResumableClone(), WithPartialClone(gitinterface.BloblessFilter))
+		assert.ErrorIs(t, err, ErrResumingPartialClone)
+	})
+}
+
+func TestFetch(t *testing.T) {
+	remoteName := "origin"
+	protectedRefName := "refs/heads/main"
+	unprotectedRefName := "refs/heads/feature"
+
+	remoteTmpDir := t.TempDir()
+	remoteRepo := createTestRepositoryWithPolicy(t, remoteTmpDir)
+
+	emptyTreeHash, err := gitinterface.WriteTree(remoteRepo.r, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, refName := range []string{protectedRefName, unprotectedRefName} {
+		// The RSL entries are unsigned, so only the unprotected ref verifies
+		if _, err := gitinterface.Commit(remoteRepo.r, emptyTreeHash, refName, "Initial commit", false); err != nil {
+			t.Fatal(err)
+		}
+		if err := remoteRepo.RecordRSLEntryForReference(refName, false); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	createLocalRepository := func(t *testing.T) *Repository {
+		t.Helper()
+
+		localR, err := git.PlainInit(t.TempDir(), true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := localR.CreateRemote(&config.RemoteConfig{
+			Name: remoteName,
+			URLs: []string{remoteTmpDir},
+		}); err != nil {
+			t.Fatal(err)
+		}
+
+		return &Repository{r: localR}
+	}
+
+	t.Run("successful fetch with verification", func(t *testing.T) {
+		localRepo := createLocalRepository(t)
+
+		err := localRepo.Fetch(context.Background(), remoteName, []string{unprotectedRefName}, true)
+		assert.Nil(t, err)
+
+		assertLocalAndRemoteRefsMatch(t, localRepo.r, remoteRepo.r, rsl.Ref)
+		assertLocalAndRemoteRefsMatch(t, localRepo.r, remoteRepo.r, policy.PolicyRef)
+		assertLocalAndRemoteRefsMatch(t, localRepo.r, remoteRepo.r, unprotectedRefName)
+	})
+
+	t.Run("unsuccessful fetch with verification", func(t *testing.T) {
+		localRepo := createLocalRepository(t)
+
+		err := localRepo.Fetch(context.Background(), remoteName, []string{protectedRefName}, true)
+		assert.ErrorIs(t, err, ErrFetchVerificationFailed)
+
+		// No local policy existed before, so none of the refs must exist now
+		for _, refName := range []string{rsl.Ref, policy.PolicyRef, protectedRefName} {
+			_, err := localRepo.r.Reference(plumbing.ReferenceName(refName), true)
+			assert.ErrorIs(t, err, plumbing.ErrReferenceNotFound)
+		}
+
+		// The remote tracker is still updated
+		remoteRSLTip, err := gitinterface.GetTip(remoteRepo.r, rsl.Ref)
+		if err != nil {
+			t.Fatal(err)
+		}
+		trackerRSLTip, err := gitinterface.GetTip(localRepo.r, rsl.RemoteTrackerRef(remoteName))
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, remoteRSLTip, trackerRSLTip)
+	})
+
+	t.Run("successful fetch without verification", func(t *testing.T) {
+		localRepo := createLocalRepository(t)
+
+		err := localRepo.Fetch(context.Background(), remoteName, []string{protectedRefName}, false)
+		assert.Nil(t, err)
+
+		assertLocalAndRemoteRefsMatch(t, localRepo.r, remoteRepo.r, rsl.Ref)
+		assertLocalAndRemoteRefsMatch(t, localRepo.r, remoteRepo.r, policy.PolicyRef)
+		assertLocalAndRemoteRefsMatch(t, localRepo.r, remoteRepo.r, protectedRefName)
+	})
+
+	t.Run("unsuccessful fetch with unauthorized policy change", func(t *testing.T) {
+		localRepo := createLocalRepository(t)
+
+		err := localRepo.Fetch(context.Background(), remoteName, []string{unprotectedRefName}, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		localRSLTip, err := gitinterface.GetTip(localRepo.r, rsl.Ref)
+		if err != nil {
+			t.Fatal(err)
+		}
+		localPolicyTip, err := gitinterface.GetTip(localRepo.r, policy.PolicyRef)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// The remote's root of trust is replaced with one that isn't
+		// trusted by the local policy
+		replaceTestRootOfTrust(t, remoteRepo)
+		if _, err := gitinterface.Commit(remoteRepo.r, emptyTreeHash, unprotectedRefName, "Second commit", false); err != nil {
+			t.Fatal(err)
+		}
+		if err := remoteRepo.RecordRSLEntryForReference(unprotectedRefName, false); err != nil {
+			t.Fatal(err)
+		}
+
+		err = localRepo.Fetch(context.Background(), remoteName, []string{unprotectedRefName}, true)
+		assert.ErrorIs(t, err, ErrFetchVerificationFailed)
+		assert.ErrorIs(t, err, policy.ErrUnauthorizedPolicyChange)
+
+		currentRSLTip, err := gitinterface.GetTip(localRepo.r, rsl.Ref)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, localRSLTip, currentRSLTip)
+		currentPolicyTip, err := gitinterface.GetTip(localRepo.r, policy.PolicyRef)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, localPolicyTip, currentPolicyTip)
+	})
+}
+
+func TestPullRef(t *testing.T) {
+	remoteName := "origin"
+	protectedRefName := "refs/heads/main"
+	unprotectedRefName := "refs/heads/feature"
+
+	remoteTmpDir := t.TempDir()
+	remoteRepo := createTestRepositoryWithPolicy(t, remoteTmpDir)
+
+	emptyTreeHash, err := gitinterface.WriteTree(remoteRepo.r, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// commitOnRemote adds a commit to the ref on the remote and records it
+	// in the remote's RSL. The RSL entries are unsigned, so only the
+	// unprotected ref verifies.
+	commitOnRemote := func(t *testing.T, refName, message string) plumbing.Hash {
+		t.Helper()
+
+		commitID, err := gitinterface.Commit(remoteRepo.r, emptyTreeHash, refName, message, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := remoteRepo.RecordRSLEntryForReference(refName, false); err != nil {
+			t.Fatal(err)
+		}
+
+		return commitID
+	}
+
+	baseCommitID := commitOnRemote(t, protectedRefName, "Initial commit")
+	commitOnRemote(t, unprotectedRefName, "Initial commit")
+
+	localR, err := git.PlainInit(t.TempDir(), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := localR.CreateRemote(&config.RemoteConfig{
+		Name: remoteName,
+		URLs: []string{remoteTmpDir},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	localRepo := &Repository{r: localR}
+
+	t.Run("clean fast-forward", func(t *testing.T) {
+		err := localRepo.PullRef(context.Background(), remoteName, unprotectedRefName)
+		assert.Nil(t, err)
+		assertLocalAndRemoteRefsMatch(t, localRepo.r, remoteRepo.r, unprotectedRefName)
+		assertLocalAndRemoteRefsMatch(t, localRepo.r, remoteRepo.r, rsl.Ref)
+
+		commitOnRemote(t, unprotectedRefName, "Second commit")
+
+		err = localRepo.PullRef(context.Background(), remoteName, "feature")
+		assert.Nil(t, err)
+		assertLocalAndRemoteRefsMatch(t, localRepo.r, remoteRepo.r, unprotectedRefName)
+		assertLocalAndRemoteRefsMatch(t, localRepo.r, remoteRepo.r, rsl.Ref)
+	})
+
+	t.Run("verification failure", func(t *testing.T) {
+		localRSLTip, err := gitinterface.GetTip(localRepo.r, rsl.Ref)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = localRepo.PullRef(context.Background(), remoteName, protectedRefName)
+		assert.ErrorIs(t, err, ErrFetchVerificationFailed)
+
+		// The local ref is not created and the RSL is reset
+		_, err = localRepo.r.Reference(plumbing.ReferenceName(protectedRefName), true)
+		assert.ErrorIs(t, err, plumbing.ErrReferenceNotFound)
+		currentRSLTip, err := gitinterface.GetTip(localRepo.r, rsl.Ref)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, localRSLTip, currentRSLTip)
+
+		// The remote tracker is still updated
+		trackerTip, err := gitinterface.GetTip(localRepo.r, gitinterface.RemoteRef(protectedRefName, remoteName))
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, baseCommitID, trackerTip)
+	})
+
+	t.Run("divergence", func(t *testing.T) {
+		localTip, err := gitinterface.GetTip(localRepo.r, unprotectedRefName)
+		if err != nil {
+			t.Fatal(err)
+		}
+		localCommit, err := localRepo.r.CommitObject(localTip)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Rewrite the remote ref so that it no longer descends from the
+		// local ref
+		if err := remoteRepo.r.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(unprotectedRefName), localCommit.ParentHashes[0])); err != nil {
+			t.Fatal(err)
+		}
+		commitOnRemote(t, unprotectedRefName, "Diverged commit")
+
+		err = localRepo.PullRef(context.Background(), remoteName, unprotectedRefName)
+		assert.ErrorIs(t, err, ErrPullNotFastForward)
+
+		currentTip, err := gitinterface.GetTip(localRepo.r, unprotectedRefName)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, localTip, currentTip)
+
+		// A force push annotation authorizes the update
+		latestEntry, _, err := rsl.GetLatestReferenceEntryForRef(remoteRepo.r, unprotectedRefName)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := remoteRepo.RecordRSLForcePushAnnotation([]string{latestEntry.ID.String()}, "Rewrite feature", false); err != nil {
+			t.Fatal(err)
+		}
+
+		err = localRepo.PullRef(context.Background(), remoteName, unprotectedRefName)
+		assert.Nil(t, err)
+		assertLocalAndRemoteRefsMatch(t, localRepo.r, remoteRepo.r, unprotectedRefName)
+	})
+
+	t.Run("unauthorized policy change", func(t *testing.T) {
+		localTips := map[string]plumbing.Hash{}
+		for _, refName := range []string{rsl.Ref, policy.PolicyRef, unprotectedRefName} {
+			tip, err := gitinterface.GetTip(localRepo.r, refName)
+			if err != nil {
+				t.Fatal(err)
+			}
+			localTips[refName] = tip
+		}
+
+		// The remote's root of trust is replaced with one that isn't
+		// trusted by the local policy
+		replaceTestRootOfTrust(t, remoteRepo)
+		commitOnRemote(t, unprotectedRefName, "Third commit")
+
+		err := localRepo.PullRef(context.Background(), remoteName, unprotectedRefName)
+		assert.ErrorIs(t, err, ErrFetchVerificationFailed)
+		assert.ErrorIs(t, err, policy.ErrUnauthorizedPolicyChange)
+
+		// None of the local refs are updated
+		for refName, localTip := range localTips {
+			currentTip, err := gitinterface.GetTip(localRepo.r, refName)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, localTip, currentTip)
+		}
+
+		// The remote tracker is still updated
+		remoteRSLTip, err := gitinterface.GetTip(remoteRepo.r, rsl.Ref)
+		if err != nil {
+			t.Fatal(err)
+		}
+		trackerRSLTip, err := gitinterface.GetTip(localRepo.r, rsl.RemoteTrackerRef(remoteName))
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, remoteRSLTip, trackerRSLTip)
+	})
+}
+
+func TestCheckRemoteForPush(t *testing.T) {
+	remoteName := "origin"
+	refName := "refs/heads/main"
+
+	// createRepositories creates a remote with a policy and a local
+	// repository that has fetched the remote's gittuf refs
+	createRepositories := func(t *testing.T) (*Repository, *Repository) {
+		t.Helper()
+
+		remoteTmpDir := t.TempDir()
+		remoteRepo := createTestRepositoryWithPolicy(t, remoteTmpDir)
+
+		localR, err := git.PlainInit(t.TempDir(), true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := localR.CreateRemote(&config.RemoteConfig{
+			Name: remoteName,
+			URLs: []string{remoteTmpDir},
+		}); err != nil {
+			t.Fatal(err)
+		}
+		localRepo := &Repository{r: localR}
+
+		if err := gitinterface.Fetch(context.Background(), localRepo.r, remoteName, []string{rsl.Ref, policy.PolicyRef}, true); err != nil {
+			t.Fatal(err)
+		}
+
+		return localRepo, remoteRepo
+	}
+
+	// addRSLEntry adds a commit to main and records it in the RSL
+	// addRSLEntry records a new commit with the specified message in the RSL.
+	// Repositories that must diverge need distinct messages, as their commits
+	// and RSL entries are otherwise identical.
+	addRSLEntry := func(t *testing.T, repo *Repository, message string) {
+		t.Helper()
+
+		emptyTreeHash, err := gitinterface.WriteTree(repo.r, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := gitinterface.Commit(repo.r, emptyTreeHash, refName, message, false); err != nil {
+			t.Fatal(err)
+		}
+		if err := repo.RecordRSLEntryForReference(refName, false); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Run("up to date", func(t *testing.T) {
+		localRepo, _ := createRepositories(t)
+
+		err := localRepo.CheckRemoteForPush(context.Background(), remoteName)
+		assert.Nil(t, err)
+	})
+
+	t.Run("local is ahead", func(t *testing.T) {
+		localRepo, remoteRepo := createRepositories(t)
+		addRSLEntry(t, localRepo, "Test commit")
+
+		err := localRepo.CheckRemoteForPush(context.Background(), remoteName)
+		assert.Nil(t, err)
+
+		err = localRepo.PushRSL(context.Background(), remoteName)
+		assert.Nil(t, err)
+		assertLocalAndRemoteRefsMatch(t, localRepo.r, remoteRepo.r, rsl.Ref)
+	})
+
+	t.Run("local is behind", func(t *testing.T) {
+		localRepo, remoteRepo := createRepositories(t)
+		addRSLEntry(t, remoteRepo, "Test commit")
+
+		localTip, err := gitinterface.GetTip(localRepo.r, rsl.Ref)
+		if err != nil {
+			t.Fatal(err)
+		}
+		remoteTip, err := gitinterface.GetTip(remoteRepo.r, rsl.Ref)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = localRepo.CheckRemoteForPush(context.Background(), remoteName)
+		assert.ErrorIs(t, err, ErrPushNotFastForward)
+
+		var pushErr *PushNotFastForwardError
+		if assert.ErrorAs(t, err, &pushErr) {
+			assert.Equal(t, rsl.Ref, pushErr.RefName)
+			assert.Equal(t, localTip, pushErr.LocalTip)
+			assert.Equal(t, remoteTip, pushErr.RemoteTip)
+			assert.False(t, pushErr.Diverged)
+		}
+
+		err = localRepo.PushPolicy(context.Background(), remoteName)
+		assert.ErrorIs(t, err, ErrPushingPolicy)
+		assert.ErrorIs(t, err, ErrPushNotFastForward)
+
+		newRemoteTip, err := gitinterface.GetTip(remoteRepo.r, rsl.Ref)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, remoteTip, newRemoteTip)
+	})
+
+	t.Run("local and remote have diverged", func(t *testing.T) {
+		localRepo, remoteRepo := createRepositories(t)
+		addRSLEntry(t, localRepo, "Local commit")
+		addRSLEntry(t, remoteRepo, "Remote commit")
+
+		err := localRepo.CheckRemoteForPush(context.Background(), remoteName)
+		assert.ErrorIs(t, err, ErrPushNotFastForward)
+
+		var pushErr *PushNotFastForwardError
+		if assert.ErrorAs(t, err, &pushErr) {
+			assert.Equal(t, rsl.Ref, pushErr.RefName)
+			assert.True(t, pushErr.Diverged)
+		}
+
+		err = localRepo.PushRSL(context.Background(), remoteName)
+		assert.ErrorIs(t, err, ErrPushingRSL)
+		assert.ErrorIs(t, err, ErrPushNotFastForward)
+	})
+
+	t.Run("empty remote", func(t *testing.T) {
+		remoteTmpDir := t.TempDir()
+		if _, err := git.PlainInit(remoteTmpDir, true); err != nil {
+			t.Fatal(err)
+		}
+
+		localRepo := createTestRepositoryWithPolicy(t, "")
+		if _, err := localRepo.r.CreateRemote(&config.RemoteConfig{
+			Name: remoteName,
+			URLs: []string{remoteTmpDir},
+		}); err != nil {
+			t.Fatal(err)
+		}
+
+		err := localRepo.CheckRemoteForPush(context.Background(), remoteName)
+		assert.Nil(t, err)
+	})
+}
+
+func TestFetchForCommitVerification(t *testing.T) {
+	remoteName := "origin"
+	mainRefName := "refs/heads/main"
+	featureRefName := "refs/heads/feature"
+	docsRefName := "refs/heads/docs"
+	deletedRefName := "refs/heads/deleted"
+	unrecordedRefName := "refs/heads/unrecorded"
+	pullRefName := "refs/pull/1/head"
+
+	remoteTmpDir := t.TempDir()
+	remoteRepo := createTestRepositoryWithPolicy(t, remoteTmpDir)
+
+	commitIDs := map[string]plumbing.Hash{}
+	createCommit := func(t *testing.T, refName, message string, record bool) plumbing.Hash {
+		t.Helper()
+
+		blobID, err := gitinterface.WriteBlob(remoteRepo.r, []byte(message))
+		if err != nil {
+			t.Fatal(err)
+		}
+		treeID, err := gitinterface.WriteTree(remoteRepo.r, []object.TreeEntry{{Name: "file", Mode: filemode.Regular, Hash: blobID}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		commitID, err := gitinterface.Commit(remoteRepo.r, treeID, refName, message, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if record {
+			if err := remoteRepo.RecordRSLEntryForReference(refName, false); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		return commitID
+	}
+
+	// The RSL records feature, main, deleted, deleted's removal, docs, and
+	// main again, in that order
+	commitIDs[featureRefName] = createCommit(t, featureRefName, "feature", true)
+	firstMainCommitID := createCommit(t, mainRefName, "main 1", true)
+	commitIDs[deletedRefName] = createCommit(t, deletedRefName, "deleted", true)
+	if err := remoteRepo.r.Storer.RemoveReference(plumbing.ReferenceName(deletedRefName)); err != nil {
+		t.Fatal(err)
+	}
+	if err := rsl.NewReferenceEntry(deletedRefName, plumbing.ZeroHash).Commit(remoteRepo.r, false); err != nil {
+		t.Fatal(err)
+	}
+	commitIDs[docsRefName] = createCommit(t, docsRefName, "docs", true)
+	commitIDs[mainRefName] = createCommit(t, mainRefName, "main 2", true)
+	commitIDs[unrecordedRefName] = createCommit(t, unrecordedRefName, "unrecorded", false)
+	commitIDs[pullRefName] = createCommit(t, pullRefName, "pull", false)
+
+	createLocalRepository := func(t *testing.T) *Repository {
+		t.Helper()
+
+		localR, err := git.PlainInit(t.TempDir(), true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := localR.CreateRemote(&config.RemoteConfig{
+			Name: remoteName,
+			URLs: []string{remoteTmpDir},
+		}); err != nil {
+			t.Fatal(err)
+		}
+
+		return &Repository{r: localR}
+	}
+
+	t.Run("commit recorded in RSL", func(t *testing.T) {
+		localRepo := createLocalRepository(t)
+
+		plan, err := localRepo.FetchForCommitVerification(context.Background(), remoteName, firstMainCommitID, "")
+		assert.Nil(t, err)
+		assert.Equal(t, firstMainCommitID, plan.CommitID)
+		assert.Equal(t, []string{mainRefName, docsRefName, featureRefName}, plan.Refs)
+
+		assertLocalAndRemoteRefsMatch(t, localRepo.r, remoteRepo.r, rsl.Ref)
+		assertLocalAndRemoteRefsMatch(t, localRepo.r, remoteRepo.r, policy.PolicyRef)
+
+		// The refs are only fetched into their remote trackers
+		for _, refName := range plan.Refs {
+			trackerTip, err := gitinterface.GetTip(localRepo.r, gitinterface.RemoteRef(refName, remoteName))
+			assert.Nil(t, err)
+			assert.Equal(t, commitIDs[refName], trackerTip)
+
+			_, err = localRepo.r.Reference(plumbing.ReferenceName(refName), true)
+			assert.ErrorIs(t, err, plumbing.ErrReferenceNotFound)
+		}
+
+		// Refs that are unrecorded or deleted are not fetched
+		for _, refName := range []string{deletedRefName, unrecordedRefName, pullRefName} {
+			_, err := localRepo.r.CommitObject(commitIDs[refName])
+			assert.ErrorIs(t, err, plumbing.ErrObjectNotFound)
+		}
+
+		commit, err := localRepo.r.CommitObject(firstMainCommitID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		state, err := policy.GetStateForCommit(context.Background(), localRepo.r, commit)
+		assert.Nil(t, err)
+		assert.NotNil(t, state)
+
+		// Everything needed is now available locally
+		plan, err = localRepo.PlanFetchForCommitVerification(context.Background(), remoteName, firstMainCommitID, "")
+		assert.Nil(t, err)
+		assert.Empty(t, plan.Refs)
+	})
+
+	t.Run("lookup bounded by RSL", func(t *testing.T) {
+		localRepo := createLocalRepository(t)
+
+		if err := gitinterface.Fetch(context.Background(), localRepo.r, remoteName, []string{mainRefName, docsRefName}, true); err != nil {
+			t.Fatal(err)
+		}
+
+		// The docs entry does not know the latest main commit, so the feature
+		// entry recorded before it is not needed
+		plan, err := localRepo.PlanFetchForCommitVerification(context.Background(), remoteName, commitIDs[mainRefName], "")
+		assert.Nil(t, err)
+		assert.Empty(t, plan.Refs)
+	})
+
+	t.Run("commit requested from unrecorded ref", func(t *testing.T) {
+		localRepo := createLocalRepository(t)
+
+		plan, err := localRepo.FetchForCommitVerification(context.Background(), remoteName, commitIDs[pullRefName], pullRefName)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{pullRefName, mainRefName, docsRefName, featureRefName}, plan.Refs)
+
+		trackerTip, err := gitinterface.GetTip(localRepo.r, gitinterface.RemoteRef(pullRefName, remoteName))
+		assert.Nil(t, err)
+		assert.Equal(t, commitIDs[pullRefName], trackerTip)
+	})
+
+	t.Run("commit not found", func(t *testing.T) {
+		localRepo := createLocalRepository(t)
+
+		_, err := localRepo.FetchForCommitVerification(context.Background(), remoteName, commitIDs[pullRefName], "")
+		assert.ErrorIs(t, err, ErrCommitNotFetched)
+	})
 }
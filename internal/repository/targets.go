@@ -5,6 +5,7 @@ package repository
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/gittuf/gittuf/internal/policy"
 	"github.com/gittuf/gittuf/internal/signerverifier"
@@ -54,10 +55,8 @@ func (r *Repository) InitializeTargets(ctx context.Context, targetsKeyBytes []by
 		return nil
 	}
 
-	if targetsRoleName == policy.TargetsRoleName {
-		state.TargetsEnvelope = env
-	} else {
-		state.DelegationEnvelopes[targetsRoleName] = env
+	if err := state.SetTargetsEnvelope(targetsRoleName, env); err != nil {
+		return err
 	}
 
 	commitMessage := fmt.Sprintf("Initialize policy '%s'", targetsRoleName)
@@ -103,6 +102,10 @@ func (r *Repository) AddDelegation(ctx context.Context, signingKeyBytes []byte,
 		authorizedKeys = append(authorizedKeys, key)
 	}
 
+	if err := state.CheckRuleNameIsAvailable(targetsRoleName, ruleName); err != nil {
+		return err
+	}
+
 	targetsMetadata, err := state.GetTargetsMetadata(targetsRoleName)
 	if err != nil {
 		return err
@@ -113,6 +116,10 @@ func (r *Repository) AddDelegation(ctx context.Context, signingKeyBytes []byte,
 		return err
 	}
 
+	if err := policy.ValidateTargetsMetadata(targetsMetadata); err != nil {
+		return err
+	}
+
 	targetsMetadata.SetVersion(targetsMetadata.Version + 1)
 
 	env, err := dsse.CreateEnvelope(targetsMetadata)
@@ -125,10 +132,8 @@ func (r *Repository) AddDelegation(ctx context.Context, signingKeyBytes []byte,
 		return nil
 	}
 
-	if targetsRoleName == policy.TargetsRoleName {
-		state.TargetsEnvelope = env
-	} else {
-		state.DelegationEnvelopes[targetsRoleName] = env
+	if err := state.SetTargetsEnvelope(targetsRoleName, env); err != nil {
+		return err
 	}
 
 	commitMessage := fmt.Sprintf("Add rule '%s' to policy '%s'", ruleName, targetsRoleName)
@@ -136,6 +141,53 @@ func (r *Repository) AddDelegation(ctx context.Context, signingKeyBytes []byte,
 	return state.Commit(ctx, r.r, commitMessage, signCommit)
 }
 
+// AddDenyRule is the interface for a user to add a rule to gittuf policy that
+// rejects all changes to the matching namespaces.
+func (r *Repository) AddDenyRule(ctx context.Context, signingKeyBytes []byte, targetsRoleName string, ruleName string, rulePatterns []string, signCommit bool) error {
+	commitMessage := fmt.Sprintf("Add deny rule '%s' to policy '%s'", ruleName, targetsRoleName)
+
+	return r.updateTargetsMetadata(ctx, signingKeyBytes, targetsRoleName, func(state *policy.State, targetsMetadata *tuf.TargetsMetadata) (*tuf.TargetsMetadata, error) {
+		if err := state.CheckRuleNameIsAvailable(targetsRoleName, ruleName); err != nil {
+			return nil, err
+		}
+		return policy.AddOrUpdateDenyRule(targetsMetadata, ruleName, rulePatterns)
+	}, commitMessage, signCommit)
+}
+
+// SetAllowedHashes is the interface for a user to pin the contents of the files
+// protected by a rule in gittuf policy to the specified Git blob IDs. Passing no
+// hashes removes the pin from the rule.
+func (r *Repository) SetAllowedHashes(ctx context.Context, signingKeyBytes []byte, targetsRoleName string, ruleName string, allowedHashes []string, signCommit bool) error {
+	commitMessage := fmt.Sprintf("Set allowed hashes for rule '%s' in policy '%s'", ruleName, targetsRoleName)
+
+	return r.updateTargetsMetadata(ctx, signingKeyBytes, targetsRoleName, func(_ *policy.State, targetsMetadata *tuf.TargetsMetadata) (*tuf.TargetsMetadata, error) {
+		return policy.SetAllowedHashes(targetsMetadata, ruleName, allowedHashes)
+	}, commitMessage, signCommit)
+}
+
+// SetMinDistinctSigners is the interface for a user to require the last window
+// commits of the refs protected by a rule in gittuf policy to be signed by at
+// least count distinct keys authorized by the rule. Passing a count of zero
+// removes the requirement from the rule.
+func (r *Repository) SetMinDistinctSigners(ctx context.Context, signingKeyBytes []byte, targetsRoleName string, ruleName string, count, window int, signCommit bool) error {
+	commitMessage := fmt.Sprintf("Set minimum distinct signers for rule '%s' in policy '%s'", ruleName, targetsRoleName)
+
+	return r.updateTargetsMetadata(ctx, signingKeyBytes, targetsRoleName, func(_ *policy.State, targetsMetadata *tuf.TargetsMetadata) (*tuf.TargetsMetadata, error) {
+		return policy.SetMinDistinctSigners(targetsMetadata, ruleName, count, window)
+	}, commitMessage, signCommit)
+}
+
+// SetRuleThreshold is the interface for a user to set the number of distinct
+// keys authorized by a rule in gittuf policy that must sign a change. For rules
+// protecting Git refs, the keys may sign each commit using co-signatures.
+func (r *Repository) SetRuleThreshold(ctx context.Context, signingKeyBytes []byte, targetsRoleName string, ruleName string, threshold int, signCommit bool) error {
+	commitMessage := fmt.Sprintf("Set threshold for rule '%s' in policy '%s'", ruleName, targetsRoleName)
+
+	return r.updateTargetsMetadata(ctx, signingKeyBytes, targetsRoleName, func(_ *policy.State, targetsMetadata *tuf.TargetsMetadata) (*tuf.TargetsMetadata, error) {
+		return policy.SetRuleThreshold(targetsMetadata, ruleName, threshold)
+	}, commitMessage, signCommit)
+}
+
 // RemoveDelegation is the interface for a user to remove a rule from gittuf
 // policy.
 func (r *Repository) RemoveDelegation(ctx context.Context, signingKeyBytes []byte, targetsRoleName string, ruleName string, signCommit bool) error {
@@ -174,6 +226,10 @@ func (r *Repository) RemoveDelegation(ctx context.Context, signingKeyBytes []byt
 		return err
 	}
 
+	if err := policy.ValidateTargetsMetadata(targetsMetadata); err != nil {
+		return err
+	}
+
 	targetsMetadata.SetVersion(targetsMetadata.Version + 1)
 
 	env, err := dsse.CreateEnvelope(targetsMetadata)
@@ -186,10 +242,8 @@ func (r *Repository) RemoveDelegation(ctx context.Context, signingKeyBytes []byt
 		return nil
 	}
 
-	if targetsRoleName == policy.TargetsRoleName {
-		state.TargetsEnvelope = env
-	} else {
-		state.DelegationEnvelopes[targetsRoleName] = env
+	if err := state.SetTargetsEnvelope(targetsRoleName, env); err != nil {
+		return err
 	}
 
 	commitMessage := fmt.Sprintf("Remove rule '%s' from policy '%s'", ruleName, targetsRoleName)
@@ -247,6 +301,10 @@ func (r *Repository) AddKeyToTargets(ctx context.Context, signingKeyBytes []byte
 		return err
 	}
 
+	if err := policy.ValidateTargetsMetadata(targetsMetadata); err != nil {
+		return err
+	}
+
 	targetsMetadata.SetVersion(targetsMetadata.Version + 1)
 
 	env, err := dsse.CreateEnvelope(targetsMetadata)
@@ -259,13 +317,142 @@ func (r *Repository) AddKeyToTargets(ctx context.Context, signingKeyBytes []byte
 		return nil
 	}
 
-	if targetsRoleName == policy.TargetsRoleName {
-		state.TargetsEnvelope = env
-	} else {
-		state.DelegationEnvelopes[targetsRoleName] = env
+	if err := state.SetTargetsEnvelope(targetsRoleName, env); err != nil {
+		return err
 	}
 
 	commitMessage := fmt.Sprintf("Add keys to policy '%s'\n%s", targetsRoleName, keyIDs)
 
 	return state.Commit(ctx, r.r, commitMessage, signCommit)
 }
+
+// SetVerifyMergeCommits is the interface for a user to require that merge
+// commits in the refs protected by a rule in gittuf policy only combine the
+// changes made by their parents.
+func (r *Repository) SetVerifyMergeCommits(ctx context.Context, signingKeyBytes []byte, targetsRoleName string, ruleName string, verifyMergeCommits bool, signCommit bool) error {
+	commitMessage := fmt.Sprintf("Set merge commit verification for rule '%s' in policy '%s'", ruleName, targetsRoleName)
+
+	return r.updateTargetsMetadata(ctx, signingKeyBytes, targetsRoleName, func(_ *policy.State, targetsMetadata *tuf.TargetsMetadata) (*tuf.TargetsMetadata, error) {
+		return policy.SetVerifyMergeCommits(targetsMetadata, ruleName, verifyMergeCommits)
+	}, commitMessage, signCommit)
+}
+
+// SetRequireSignedCommits is the interface for a user to require that every
+// commit introduced in the refs protected by a rule in gittuf policy is signed
+// by one of the rule's authorized keys.
+func (r *Repository) SetRequireSignedCommits(ctx context.Context, signingKeyBytes []byte, targetsRoleName string, ruleName string, requireSignedCommits bool, signCommit bool) error {
+	commitMessage := fmt.Sprintf("Set signed commits requirement for rule '%s' in policy '%s'", ruleName, targetsRoleName)
+
+	return r.updateTargetsMetadata(ctx, signingKeyBytes, targetsRoleName, func(_ *policy.State, targetsMetadata *tuf.TargetsMetadata) (*tuf.TargetsMetadata, error) {
+		return policy.SetRequireSignedCommits(targetsMetadata, ruleName, requireSignedCommits)
+	}, commitMessage, signCommit)
+}
+
+// SetRuleRefs is the interface for a user to restrict a rule in gittuf policy
+// that protects file paths to changes made on the Git refs matching the
+// specified patterns.
+func (r *Repository) SetRuleRefs(ctx context.Context, signingKeyBytes []byte, targetsRoleName string, ruleName string, refPatterns []string, signCommit bool) error {
+	commitMessage := fmt.Sprintf("Set refs for rule '%s' in policy '%s'", ruleName, targetsRoleName)
+
+	return r.updateTargetsMetadata(ctx, signingKeyBytes, targetsRoleName, func(_ *policy.State, targetsMetadata *tuf.TargetsMetadata) (*tuf.TargetsMetadata, error) {
+		return policy.SetRuleRefs(targetsMetadata, ruleName, refPatterns)
+	}, commitMessage, signCommit)
+}
+
+// SetRuleExpiry is the interface for a user to set the time after which a rule
+// in gittuf policy no longer applies. If expires is zero, the rule's expiry is
+// removed.
+func (r *Repository) SetRuleExpiry(ctx context.Context, signingKeyBytes []byte, targetsRoleName string, ruleName string, expires time.Time, signCommit bool) error {
+	commitMessage := fmt.Sprintf("Set expiry for rule '%s' in policy '%s'", ruleName, targetsRoleName)
+
+	return r.updateTargetsMetadata(ctx, signingKeyBytes, targetsRoleName, func(_ *policy.State, targetsMetadata *tuf.TargetsMetadata) (*tuf.TargetsMetadata, error) {
+		return policy.SetRuleExpiry(targetsMetadata, ruleName, expires)
+	}, commitMessage, signCommit)
+}
+
+// SetKeyValidityWindow is the interface for a user to restrict a key trusted by
+// the rules in gittuf policy to signing commits created within the specified
+// period. If both notBefore and notAfter are zero, the key's validity window is
+// removed.
+func (r *Repository) SetKeyValidityWindow(ctx context.Context, signingKeyBytes []byte, targetsRoleName string, keyID string, notBefore, notAfter time.Time, signCommit bool) error {
+	commitMessage := fmt.Sprintf("Set validity window for key '%s' in policy '%s'", keyID, targetsRoleName)
+
+	return r.updateTargetsMetadata(ctx, signingKeyBytes, targetsRoleName, func(_ *policy.State, targetsMetadata *tuf.TargetsMetadata) (*tuf.TargetsMetadata, error) {
+		return policy.SetKeyValidityWindow(targetsMetadata, keyID, notBefore, notAfter)
+	}, commitMessage, signCommit)
+}
+
+// updateTargetsMetadata loads the current policy, checks that the signing key
+// is authorized to sign the specified policy file, and applies update to the
+// policy file's metadata. The updated metadata is validated, signed, and
+// committed to the policy namespace with the specified message. The current
+// policy state is passed to update for checks that span policy files.
+func (r *Repository) updateTargetsMetadata(ctx context.Context, signingKeyBytes []byte, targetsRoleName string, update func(*policy.State, *tuf.TargetsMetadata) (*tuf.TargetsMetadata, error), commitMessage string, signCommit bool) error {
+	sv, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(signingKeyBytes)
+	if err != nil {
+		return err
+	}
+	keyID, err := sv.KeyID()
+	if err != nil {
+		return err
+	}
+
+	state, err := policy.LoadCurrentState(ctx, r.r)
+	if err != nil {
+		return err
+	}
+	if !state.HasTargetsRole(targetsRoleName) {
+		return policy.ErrMetadataNotFound
+	}
+
+	authorizedKeyIDsForRole, err := state.FindAuthorizedSigningKeyIDs(ctx, targetsRoleName)
+	if err != nil {
+		return err
+	}
+	if !isKeyAuthorized(authorizedKeyIDsForRole, keyID) {
+		return ErrUnauthorizedKey
+	}
+
+	targetsMetadata, err := state.GetTargetsMetadata(targetsRoleName)
+	if err != nil {
+		return err
+	}
+
+	targetsMetadata, err = update(state, targetsMetadata)
+	if err != nil {
+		return err
+	}
+
+	if err := policy.ValidateTargetsMetadata(targetsMetadata); err != nil {
+		return err
+	}
+
+	targetsMetadata.SetVersion(targetsMetadata.Version + 1)
+
+	env, err := dsse.CreateEnvelope(targetsMetadata)
+	if err != nil {
+		return err
+	}
+
+	env, err = dsse.SignEnvelope(ctx, env, sv)
+	if err != nil {
+		return err
+	}
+
+	if err := state.SetTargetsEnvelope(targetsRoleName, env); err != nil {
+		return err
+	}
+
+	return state.Commit(ctx, r.r, commitMessage, signCommit)
+}
+
+// ListRules returns the structure of the delegations in the repository's
+// current policy. See policy.State.DelegationTree for details.
+func (r *Repository) ListRules(ctx context.Context) ([]*policy.DelegationTreeNode, error) {
+	state, err := policy.LoadCurrentState(ctx, r.r)
+	if err != nil {
+		return nil, err
+	}
+
+	return state.DelegationTree(ctx)
+}
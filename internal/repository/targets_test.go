@@ -9,7 +9,9 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/gittuf/gittuf/internal/gitinterface"
 	"github.com/gittuf/gittuf/internal/policy"
 	"github.com/gittuf/gittuf/internal/signerverifier/gpg"
 	"github.com/gittuf/gittuf/internal/tuf"
@@ -76,6 +78,137 @@ func TestAddDelegation(t *testing.T) {
 		Role:        tuf.Role{KeyIDs: []string{targetsKey.KeyID}, Threshold: 1},
 	})
 	assert.Contains(t, targetsMetadata.Delegations.Roles, policy.AllowRule())
+
+	t.Run("malformed rule pattern", func(t *testing.T) {
+		err := r.AddDelegation(context.Background(), targetsKeyBytes, policy.TargetsRoleName, "malformed-rule", authorizedKeyBytes, []string{"git:refs/heads/["}, false)
+		assert.ErrorIs(t, err, tuf.ErrInvalidDelegationPatterns)
+	})
+}
+
+func TestAddDenyRule(t *testing.T) {
+	r, targetsKeyBytes := createTestRepositoryWithTargets(t)
+
+	ruleName := "deny-rule"
+	rulePatterns := []string{"git:refs/heads/frozen"}
+
+	err := r.AddDenyRule(context.Background(), targetsKeyBytes, policy.TargetsRoleName, ruleName, rulePatterns, false)
+	assert.Nil(t, err)
+
+	state, err := policy.LoadCurrentState(context.Background(), r.r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targetsMetadata, err := state.GetTargetsMetadata(policy.TargetsRoleName)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(targetsMetadata.Delegations.Roles))
+	assert.Equal(t, policy.DenyRule(ruleName, rulePatterns), targetsMetadata.Delegations.Roles[0])
+	assert.Equal(t, policy.AllowRule(), targetsMetadata.Delegations.Roles[1])
+
+	_, err = state.FindPublicKeysForPath(context.Background(), "git:refs/heads/frozen")
+	assert.ErrorIs(t, err, policy.ErrPathDenied)
+}
+
+func TestSetAllowedHashes(t *testing.T) {
+	r, targetsKeyBytes := createTestRepositoryWithTargets(t)
+
+	ruleName := "protect-ci-config"
+	rulePatterns := []string{"file:.github/workflows/*"}
+	allowedHashes := []string{gitinterface.EmptyBlob().String()}
+
+	err := r.AddDelegation(context.Background(), targetsKeyBytes, policy.TargetsRoleName, ruleName, [][]byte{targetsKeyBytes}, rulePatterns, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = r.SetAllowedHashes(context.Background(), targetsKeyBytes, policy.TargetsRoleName, ruleName, allowedHashes, false)
+	assert.Nil(t, err)
+
+	state, err := policy.LoadCurrentState(context.Background(), r.r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targetsMetadata, err := state.GetTargetsMetadata(policy.TargetsRoleName)
+	assert.Nil(t, err)
+	assert.Equal(t, ruleName, targetsMetadata.Delegations.Roles[0].Name)
+	assert.Equal(t, allowedHashes, targetsMetadata.Delegations.Roles[0].AllowedHashes)
+
+	err = r.SetAllowedHashes(context.Background(), targetsKeyBytes, policy.TargetsRoleName, "missing-rule", allowedHashes, false)
+	assert.ErrorIs(t, err, policy.ErrDelegationNotFound)
+}
+
+func TestSetMinDistinctSigners(t *testing.T) {
+	r, targetsKeyBytes := createTestRepositoryWithTargets(t)
+
+	ruleName := "protect-main"
+	rulePatterns := []string{"git:refs/heads/main"}
+
+	err := r.AddDelegation(context.Background(), targetsKeyBytes, policy.TargetsRoleName, ruleName, [][]byte{targetsKeyBytes}, rulePatterns, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = r.SetMinDistinctSigners(context.Background(), targetsKeyBytes, policy.TargetsRoleName, ruleName, 1, 10, false)
+	assert.Nil(t, err)
+
+	state, err := policy.LoadCurrentState(context.Background(), r.r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targetsMetadata, err := state.GetTargetsMetadata(policy.TargetsRoleName)
+	assert.Nil(t, err)
+	assert.Equal(t, ruleName, targetsMetadata.Delegations.Roles[0].Name)
+	assert.Equal(t, &tuf.DistinctSignersRequirement{Count: 1, Window: 10}, targetsMetadata.Delegations.Roles[0].MinDistinctSigners)
+
+	// The rule only authorizes one key
+	err = r.SetMinDistinctSigners(context.Background(), targetsKeyBytes, policy.TargetsRoleName, ruleName, 2, 10, false)
+	assert.ErrorIs(t, err, tuf.ErrInvalidDistinctSigners)
+
+	err = r.SetMinDistinctSigners(context.Background(), targetsKeyBytes, policy.TargetsRoleName, "missing-rule", 1, 10, false)
+	assert.ErrorIs(t, err, policy.ErrDelegationNotFound)
+}
+
+func TestSetRuleThreshold(t *testing.T) {
+	r, targetsKeyBytes := createTestRepositoryWithTargets(t)
+
+	ruleName := "protect-main"
+	rulePatterns := []string{"git:refs/heads/main"}
+
+	gpgKey, err := gpg.LoadGPGKeyFromBytes(gpgPubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gpgKeyBytes, err := json.Marshal(gpgKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = r.AddDelegation(context.Background(), targetsKeyBytes, policy.TargetsRoleName, ruleName, [][]byte{targetsKeyBytes, gpgKeyBytes}, rulePatterns, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = r.SetRuleThreshold(context.Background(), targetsKeyBytes, policy.TargetsRoleName, ruleName, 2, false)
+	assert.Nil(t, err)
+
+	state, err := policy.LoadCurrentState(context.Background(), r.r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targetsMetadata, err := state.GetTargetsMetadata(policy.TargetsRoleName)
+	assert.Nil(t, err)
+	assert.Equal(t, ruleName, targetsMetadata.Delegations.Roles[0].Name)
+	assert.Equal(t, 2, targetsMetadata.Delegations.Roles[0].Threshold)
+
+	// The rule only authorizes two keys
+	err = r.SetRuleThreshold(context.Background(), targetsKeyBytes, policy.TargetsRoleName, ruleName, 3, false)
+	assert.ErrorIs(t, err, tuf.ErrInvalidDelegationThreshold)
+
+	err = r.SetRuleThreshold(context.Background(), targetsKeyBytes, policy.TargetsRoleName, "missing-rule", 1, false)
+	assert.ErrorIs(t, err, policy.ErrDelegationNotFound)
 }
 
 func TestRemoveDelegation(t *testing.T) {
@@ -183,3 +316,196 @@ func createTestRepositoryWithTargets(t *testing.T) (*Repository, []byte) {
 
 	return r, targetsKeyBytes
 }
+
+func TestSetVerifyMergeCommits(t *testing.T) {
+	r, targetsKeyBytes := createTestRepositoryWithTargets(t)
+
+	ruleName := "protect-main"
+	rulePatterns := []string{"git:refs/heads/main"}
+
+	err := r.AddDelegation(context.Background(), targetsKeyBytes, policy.TargetsRoleName, ruleName, [][]byte{targetsKeyBytes}, rulePatterns, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = r.SetVerifyMergeCommits(context.Background(), targetsKeyBytes, policy.TargetsRoleName, ruleName, true, false)
+	assert.Nil(t, err)
+
+	state, err := policy.LoadCurrentState(context.Background(), r.r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targetsMetadata, err := state.GetTargetsMetadata(policy.TargetsRoleName)
+	assert.Nil(t, err)
+	assert.Equal(t, ruleName, targetsMetadata.Delegations.Roles[0].Name)
+	assert.True(t, targetsMetadata.Delegations.Roles[0].VerifyMergeCommits)
+
+	err = r.SetVerifyMergeCommits(context.Background(), targetsKeyBytes, policy.TargetsRoleName, "missing-rule", true, false)
+	assert.ErrorIs(t, err, policy.ErrDelegationNotFound)
+}
+
+func TestSetRequireSignedCommits(t *testing.T) {
+	r, targetsKeyBytes := createTestRepositoryWithTargets(t)
+
+	ruleName := "protect-main"
+	rulePatterns := []string{"git:refs/heads/main"}
+
+	err := r.AddDelegation(context.Background(), targetsKeyBytes, policy.TargetsRoleName, ruleName, [][]byte{targetsKeyBytes}, rulePatterns, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = r.SetRequireSignedCommits(context.Background(), targetsKeyBytes, policy.TargetsRoleName, ruleName, true, false)
+	assert.Nil(t, err)
+
+	state, err := policy.LoadCurrentState(context.Background(), r.r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targetsMetadata, err := state.GetTargetsMetadata(policy.TargetsRoleName)
+	assert.Nil(t, err)
+	assert.Equal(t, ruleName, targetsMetadata.Delegations.Roles[0].Name)
+	assert.True(t, targetsMetadata.Delegations.Roles[0].RequireSignedCommits)
+
+	err = r.SetRequireSignedCommits(context.Background(), targetsKeyBytes, policy.TargetsRoleName, "missing-rule", true, false)
+	assert.ErrorIs(t, err, policy.ErrDelegationNotFound)
+}
+
+func TestSetKeyValidityWindow(t *testing.T) {
+	r, targetsKeyBytes := createTestRepositoryWithTargets(t)
+
+	ruleName := "protect-main"
+	rulePatterns := []string{"git:refs/heads/main"}
+
+	err := r.AddDelegation(context.Background(), targetsKeyBytes, policy.TargetsRoleName, ruleName, [][]byte{targetsKeyBytes}, rulePatterns, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key, err := tuf.LoadKeyFromBytes(targetsKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	notBefore := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	err = r.SetKeyValidityWindow(context.Background(), targetsKeyBytes, policy.TargetsRoleName, key.KeyID, notBefore, time.Time{}, false)
+	assert.Nil(t, err)
+
+	state, err := policy.LoadCurrentState(context.Background(), r.r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targetsMetadata, err := state.GetTargetsMetadata(policy.TargetsRoleName)
+	assert.Nil(t, err)
+	assert.True(t, notBefore.Equal(*targetsMetadata.Delegations.KeyValidity[key.KeyID].NotBefore))
+	assert.Nil(t, targetsMetadata.Delegations.KeyValidity[key.KeyID].NotAfter)
+
+	err = r.SetKeyValidityWindow(context.Background(), targetsKeyBytes, policy.TargetsRoleName, "missing-key", notBefore, time.Time{}, false)
+	assert.ErrorIs(t, err, policy.ErrKeyNotFound)
+}
+
+func TestSetRuleRefs(t *testing.T) {
+	r, targetsKeyBytes := createTestRepositoryWithTargets(t)
+
+	ruleName := "protect-deploy"
+	rulePatterns := []string{"file:deploy/*.yaml"}
+
+	err := r.AddDelegation(context.Background(), targetsKeyBytes, policy.TargetsRoleName, ruleName, [][]byte{targetsKeyBytes}, rulePatterns, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = r.SetRuleRefs(context.Background(), targetsKeyBytes, policy.TargetsRoleName, ruleName, []string{"refs/heads/prod"}, false)
+	assert.Nil(t, err)
+
+	state, err := policy.LoadCurrentState(context.Background(), r.r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targetsMetadata, err := state.GetTargetsMetadata(policy.TargetsRoleName)
+	assert.Nil(t, err)
+	assert.Equal(t, ruleName, targetsMetadata.Delegations.Roles[0].Name)
+	assert.Equal(t, []string{"refs/heads/prod"}, targetsMetadata.Delegations.Roles[0].Refs)
+
+	err = r.SetRuleRefs(context.Background(), targetsKeyBytes, policy.TargetsRoleName, ruleName, []string{"prod"}, false)
+	assert.ErrorIs(t, err, policy.ErrInvalidRuleRef)
+
+	err = r.SetRuleRefs(context.Background(), targetsKeyBytes, policy.TargetsRoleName, "missing-rule", []string{"refs/heads/prod"}, false)
+	assert.ErrorIs(t, err, policy.ErrDelegationNotFound)
+}
+
+func TestSetRuleExpiry(t *testing.T) {
+	r, targetsKeyBytes := createTestRepositoryWithTargets(t)
+
+	ruleName := "temporary-access"
+	rulePatterns := []string{"git:refs/heads/main"}
+
+	err := r.AddDelegation(context.Background(), targetsKeyBytes, policy.TargetsRoleName, ruleName, [][]byte{targetsKeyBytes}, rulePatterns, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expires := time.Date(2030, time.January, 1, 0, 0, 0, 0, time.UTC)
+	err = r.SetRuleExpiry(context.Background(), targetsKeyBytes, policy.TargetsRoleName, ruleName, expires, false)
+	assert.Nil(t, err)
+
+	state, err := policy.LoadCurrentState(context.Background(), r.r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targetsMetadata, err := state.GetTargetsMetadata(policy.TargetsRoleName)
+	assert.Nil(t, err)
+	assert.Equal(t, ruleName, targetsMetadata.Delegations.Roles[0].Name)
+	if assert.NotNil(t, targetsMetadata.Delegations.Roles[0].Expires) {
+		assert.True(t, expires.Equal(*targetsMetadata.Delegations.Roles[0].Expires))
+	}
+
+	err = r.SetRuleExpiry(context.Background(), targetsKeyBytes, policy.TargetsRoleName, ruleName, time.Time{}, false)
+	assert.Nil(t, err)
+
+	state, err = policy.LoadCurrentState(context.Background(), r.r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targetsMetadata, err = state.GetTargetsMetadata(policy.TargetsRoleName)
+	assert.Nil(t, err)
+	assert.Nil(t, targetsMetadata.Delegations.Roles[0].Expires)
+
+	err = r.SetRuleExpiry(context.Background(), targetsKeyBytes, policy.TargetsRoleName, "missing-rule", expires, false)
+	assert.ErrorIs(t, err, policy.ErrDelegationNotFound)
+}
+
+func TestListRules(t *testing.T) {
+	r, targetsKeyBytes := createTestRepositoryWithTargets(t)
+
+	targetsKey, err := tuf.LoadKeyFromBytes(targetsKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = r.AddDelegation(context.Background(), targetsKeyBytes, policy.TargetsRoleName, "protect-main", [][]byte{targetsKeyBytes}, []string{"git:refs/heads/main"}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tree, err := r.ListRules(context.Background())
+	assert.Nil(t, err)
+	if assert.Equal(t, 1, len(tree)) {
+		assert.Equal(t, policy.TargetsRoleName, tree[0].Name)
+		assert.Equal(t, []string{targetsKey.KeyID}, tree[0].KeyIDs)
+		assert.Equal(t, []*policy.DelegationTreeNode{
+			{
+				Name:      "protect-main",
+				Paths:     []string{"git:refs/heads/main"},
+				KeyIDs:    []string{targetsKey.KeyID},
+				Threshold: 1,
+			},
+		}, tree[0].Children)
+	}
+}
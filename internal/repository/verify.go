@@ -4,17 +4,53 @@ package repository
 
 import (
 	"context"
+	"errors"
 
 	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/logging"
 	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing/object"
 )
 
-func (r *Repository) VerifyRef(ctx context.Context, target string, full bool) error {
+var ErrHEADNotSymbolicRef = errors.New("HEAD does not refer to a ref")
+
+// VerifyRefOptions contains the optional parameters of VerifyRef.
+type VerifyRefOptions struct {
+	// PolicyPins are the out of band trust anchors the policy must match.
+	PolicyPins *policy.PolicyPins
+}
+
+// VerifyRefOption is used to configure VerifyRef.
+type VerifyRefOption func(*VerifyRefOptions)
+
+// WithPolicyPins configures VerifyRef to refuse to verify the ref if the
+// repository's current policy does not match the pins. See
+// policy.CheckPolicyPins for details.
+func WithPolicyPins(pins *policy.PolicyPins) VerifyRefOption {
+	return func(o *VerifyRefOptions) {
+		o.PolicyPins = pins
+	}
+}
+
+// VerifyRef verifies the target ref against the policy. If full is set, the
+// RSL is verified from its first entry.
+func (r *Repository) VerifyRef(ctx context.Context, target string, full bool, opts ...VerifyRefOption) error {
+	options := &VerifyRefOptions{}
+	for _, fn := range opts {
+		fn(options)
+	}
+
 	target, err := gitinterface.AbsoluteReference(r.r, target)
 	if err != nil {
 		return err
 	}
 
+	if err := policy.CheckPolicyPins(ctx, r.r, options.PolicyPins); err != nil {
+		return err
+	}
+
 	if full {
 		return policy.VerifyRefFull(ctx, r.r, target)
 	}
@@ -22,10 +58,157 @@ func (r *Repository) VerifyRef(ctx context.Context, target string, full bool) er
 	return policy.VerifyRef(ctx, r.r, target)
 }
 
+// VerifyHEAD verifies the ref that HEAD refers to, as VerifyRef does. As
+// verifying a ref that no rule in the policy applies to trivially succeeds,
+// VerifyHEAD also checks if the ref is protected by the repository's current
+// policy and returns the result. If the ref is not protected, a warning is
+// emitted to the logger carried by ctx. If HEAD is detached,
+// ErrHEADNotSymbolicRef is returned.
+func (r *Repository) VerifyHEAD(ctx context.Context, full bool, opts ...VerifyRefOption) (bool, error) {
+	head, err := r.r.Reference(plumbing.HEAD, false)
+	if err != nil {
+		return false, err
+	}
+	if head.Type() != plumbing.SymbolicReference {
+		return false, ErrHEADNotSymbolicRef
+	}
+	target := head.Target().String()
+
+	if err := r.VerifyRef(ctx, target, full, opts...); err != nil {
+		return false, err
+	}
+
+	state, err := policy.LoadCurrentState(ctx, r.r)
+	if err != nil {
+		return false, err
+	}
+	protected, err := state.IsRefProtected(ctx, target)
+	if err != nil {
+		return false, err
+	}
+	if !protected {
+		logging.FromContext(ctx).WarnContext(ctx, logging.EventRefNotProtected, "ref", target)
+	}
+
+	return protected, nil
+}
+
+// VerifyRefAtPolicy verifies the RSL entry that records targetID for the
+// target ref using the policy recorded in the specified policy RSL entry, rather
+// than the policy that would be selected automatically. If targetID is empty,
+// the ref's current tip is used. See policy.VerifyRefAtPolicy for details.
+func (r *Repository) VerifyRefAtPolicy(ctx context.Context, target, targetID, policyEntryID string) error {
+	target, err := gitinterface.AbsoluteReference(r.r, target)
+	if err != nil {
+		return err
+	}
+
+	var targetHash plumbing.Hash
+	if len(targetID) == 0 {
+		targetHash, err = gitinterface.GetTip(r.r, target)
+		if err != nil {
+			return err
+		}
+	} else {
+		targetHash = plumbing.NewHash(targetID)
+	}
+
+	return policy.VerifyRefAtPolicy(ctx, r.r, target, targetHash, plumbing.NewHash(policyEntryID))
+}
+
+// VerifyRefDiff verifies the changes that updating the target ref from baseID
+// to headID would introduce, such as the changes in a pull request, using the
+// repository's current policy. Only the paths that differ between headID and
+// its merge base with baseID are verified. See policy.VerifyRefDiff for
+// details.
+func (r *Repository) VerifyRefDiff(ctx context.Context, target, baseID, headID string) error {
+	absTarget, err := gitinterface.AbsoluteReference(r.r, target)
+	switch {
+	case err == nil:
+		target = absTarget
+	case errors.Is(err, gitinterface.ErrReferenceNotFound):
+		target = string(plumbing.NewBranchReferenceName(target))
+	default:
+		return err
+	}
+
+	return policy.VerifyRefDiff(ctx, r.r, target, plumbing.NewHash(baseID), plumbing.NewHash(headID))
+}
+
 func (r *Repository) VerifyCommit(ctx context.Context, ids ...string) map[string]string {
 	return policy.VerifyCommit(ctx, r.r, ids...)
 }
 
+// VerifyCommitObject verifies the signature on a commit that may not yet be
+// recorded in the RSL using the current policy. The refHint identifies the ref
+// the commit is intended for, and may be left empty. If the ref does not exist
+// in the repository yet, refHint is treated as a branch name. The names of the
+// rules that the commit satisfied are returned.
+func (r *Repository) VerifyCommitObject(ctx context.Context, commit *object.Commit, refHint string) ([]string, error) {
+	if len(refHint) > 0 {
+		absRefHint, err := gitinterface.AbsoluteReference(r.r, refHint)
+		switch {
+		case err == nil:
+			refHint = absRefHint
+		case errors.Is(err, gitinterface.ErrReferenceNotFound):
+			refHint = string(plumbing.NewBranchReferenceName(refHint))
+		default:
+			return nil, err
+		}
+	}
+
+	return policy.VerifyCommitObject(ctx, r.r, commit, refHint)
+}
+
 func (r *Repository) VerifyTag(ctx context.Context, ids []string) map[string]string {
 	return policy.VerifyTag(ctx, r.r, ids)
 }
+
+// GetReflogOnlyCommits returns the commits recorded in the reflogs of the RSL,
+// the policy ref, and the branches protected by the current policy that are no
+// longer reachable from any ref, keyed by the ref whose reflog records them.
+// Such commits are typically left behind when a ref is rewound or force pushed,
+// and can be verified using VerifyCommit during incident response. Refs whose
+// reflogs have no such commits are omitted. See
+// gitinterface.GetReflogOnlyCommits for details.
+func (r *Repository) GetReflogOnlyCommits(ctx context.Context) (map[string][]string, error) {
+	refNames := []string{rsl.Ref, policy.PolicyRef}
+
+	state, err := policy.LoadCurrentState(ctx, r.r)
+	if err != nil {
+		return nil, err
+	}
+	branches, err := r.r.Branches()
+	if err != nil {
+		return nil, err
+	}
+	if err := branches.ForEach(func(ref *plumbing.Reference) error {
+		protected, err := state.IsRefProtected(ctx, ref.Name().String())
+		if err != nil {
+			return err
+		}
+		if protected {
+			refNames = append(refNames, ref.Name().String())
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	commits := map[string][]string{}
+	for _, refName := range refNames {
+		commitIDs, err := gitinterface.GetReflogOnlyCommits(r.r, refName)
+		if err != nil {
+			return nil, err
+		}
+		if len(commitIDs) == 0 {
+			continue
+		}
+
+		for _, commitID := range commitIDs {
+			commits[refName] = append(commits[refName], commitID.String())
+		}
+	}
+
+	return commits, nil
+}
@@ -3,11 +3,17 @@
 package repository
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/gittuf/gittuf/internal/common"
+	"github.com/gittuf/gittuf/internal/logging"
+	"github.com/gittuf/gittuf/internal/policy"
 	"github.com/gittuf/gittuf/internal/rsl"
 	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing"
 	"github.com/stretchr/testify/assert"
@@ -65,3 +71,232 @@ func TestVerifyRef(t *testing.T) {
 		}
 	}
 }
+
+func TestVerifyHEAD(t *testing.T) {
+	repo := createTestRepositoryWithPolicy(t, "")
+
+	// The policy only protects main
+	protectedRefName := "refs/heads/main"
+	unprotectedRefName := "refs/heads/feature"
+	for _, refName := range []string{protectedRefName, unprotectedRefName} {
+		if err := repo.r.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(refName), plumbing.ZeroHash)); err != nil {
+			t.Fatal(err)
+		}
+
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo.r, refName, 1, gpgKeyName)
+		entry := rsl.NewReferenceEntry(refName, commitIDs[0])
+		common.CreateTestRSLReferenceEntryCommit(t, repo.r, entry, gpgKeyName)
+	}
+
+	t.Run("HEAD refers to protected ref", func(t *testing.T) {
+		if err := repo.r.Storer.SetReference(plumbing.NewSymbolicReference(plumbing.HEAD, plumbing.ReferenceName(protectedRefName))); err != nil {
+			t.Fatal(err)
+		}
+
+		buffer := &bytes.Buffer{}
+		ctx := logging.NewContext(context.Background(), slog.New(slog.NewTextHandler(buffer, nil)))
+
+		protected, err := repo.VerifyHEAD(ctx, true)
+		assert.Nil(t, err)
+		assert.True(t, protected)
+		assert.Empty(t, buffer.String())
+	})
+
+	t.Run("HEAD refers to unprotected ref", func(t *testing.T) {
+		if err := repo.r.Storer.SetReference(plumbing.NewSymbolicReference(plumbing.HEAD, plumbing.ReferenceName(unprotectedRefName))); err != nil {
+			t.Fatal(err)
+		}
+
+		buffer := &bytes.Buffer{}
+		ctx := logging.NewContext(context.Background(), slog.New(slog.NewTextHandler(buffer, nil)))
+
+		// Verification succeeds, but the ref is reported as unprotected
+		protected, err := repo.VerifyHEAD(ctx, true)
+		assert.Nil(t, err)
+		assert.False(t, protected)
+		assert.Contains(t, buffer.String(), logging.EventRefNotProtected)
+		assert.Contains(t, buffer.String(), unprotectedRefName)
+	})
+
+	t.Run("HEAD is detached", func(t *testing.T) {
+		tip, err := repo.r.Reference(plumbing.ReferenceName(protectedRefName), true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := repo.r.Storer.SetReference(plumbing.NewHashReference(plumbing.HEAD, tip.Hash())); err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = repo.VerifyHEAD(context.Background(), true)
+		assert.ErrorIs(t, err, ErrHEADNotSymbolicRef)
+	})
+}
+
+func TestVerifyRefAtPolicy(t *testing.T) {
+	repo := createTestRepositoryWithPolicy(t, "")
+
+	refName := "refs/heads/main"
+	if err := repo.r.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(refName), plumbing.ZeroHash)); err != nil {
+		t.Fatal(err)
+	}
+
+	policyEntry, _, err := rsl.GetLatestReferenceEntryForRef(repo.r, policy.PolicyRef)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo.r, refName, 2, gpgKeyName)
+	firstEntryID := common.CreateTestRSLReferenceEntryCommit(t, repo.r, rsl.NewReferenceEntry(refName, commitIDs[0]), gpgKeyName)
+	common.CreateTestRSLReferenceEntryCommit(t, repo.r, rsl.NewReferenceEntry(refName, commitIDs[1]), gpgKeyName)
+
+	t.Run("current tip", func(t *testing.T) {
+		err := repo.VerifyRefAtPolicy(context.Background(), "main", "", policyEntry.ID.String())
+		assert.Nil(t, err)
+	})
+
+	t.Run("prior target", func(t *testing.T) {
+		err := repo.VerifyRefAtPolicy(context.Background(), refName, commitIDs[0].String(), policyEntry.ID.String())
+		assert.Nil(t, err)
+	})
+
+	t.Run("entry is not for policy", func(t *testing.T) {
+		err := repo.VerifyRefAtPolicy(context.Background(), refName, "", firstEntryID.String())
+		assert.ErrorIs(t, err, rsl.ErrRSLEntryDoesNotMatchRef)
+	})
+}
+
+func TestVerifyRefWithPolicyPins(t *testing.T) {
+	repo := createTestRepositoryWithPolicy(t, "")
+
+	refName := "refs/heads/main"
+	if err := repo.r.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(refName), plumbing.ZeroHash)); err != nil {
+		t.Fatal(err)
+	}
+
+	commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo.r, refName, 1, gpgKeyName)
+	entry := rsl.NewReferenceEntry(refName, commitIDs[0])
+	common.CreateTestRSLReferenceEntryCommit(t, repo.r, entry, gpgKeyName)
+
+	policyRef, err := repo.r.Reference(plumbing.ReferenceName(policy.PolicyRef), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("matching pin", func(t *testing.T) {
+		err := repo.VerifyRef(context.Background(), refName, true, WithPolicyPins(&policy.PolicyPins{PolicyCommitID: policyRef.Hash().String()}))
+		assert.Nil(t, err)
+	})
+
+	t.Run("mismatched pin", func(t *testing.T) {
+		err := repo.VerifyRef(context.Background(), refName, true, WithPolicyPins(&policy.PolicyPins{PolicyCommitID: commitIDs[0].String()}))
+		assert.ErrorIs(t, err, policy.ErrPolicyPinMismatch)
+	})
+}
+
+func TestVerifyCommitObject(t *testing.T) {
+	repo := createTestRepositoryWithPolicy(t, "")
+
+	refName := "refs/heads/main"
+	if err := repo.r.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(refName), plumbing.ZeroHash)); err != nil {
+		t.Fatal(err)
+	}
+
+	commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo.r, refName, 1, gpgKeyName)
+	commit, err := repo.r.CommitObject(commitIDs[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		refHint string
+		rules   []string
+		err     error
+	}{
+		"absolute ref": {
+			refHint: "refs/heads/main",
+			rules:   []string{"protect-main"},
+		},
+		"relative ref": {
+			refHint: "main",
+			rules:   []string{"protect-main"},
+		},
+		"ref not in repository": {
+			refHint: "feature",
+			err:     policy.ErrCommitNotProtected,
+		},
+		"no ref hint": {
+			refHint: "",
+			err:     policy.ErrCommitNotProtected,
+		},
+	}
+
+	for name, test := range tests {
+		rules, err := repo.VerifyCommitObject(context.Background(), commit, test.refHint)
+		if test.err != nil {
+			assert.ErrorIs(t, err, test.err, fmt.Sprintf("unexpected error in test '%s'", name))
+		} else {
+			assert.Nil(t, err, fmt.Sprintf("unexpected error in test '%s'", name))
+			assert.Equal(t, test.rules, rules, fmt.Sprintf("unexpected rules in test '%s'", name))
+		}
+	}
+}
+
+func TestVerifyRefDiff(t *testing.T) {
+	repo := createTestRepositoryWithPolicy(t, "")
+
+	refName := "refs/heads/main"
+	if err := repo.r.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(refName), plumbing.ZeroHash)); err != nil {
+		t.Fatal(err)
+	}
+
+	commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo.r, refName, 3, gpgKeyName)
+
+	err := repo.VerifyRefDiff(context.Background(), refName, commitIDs[0].String(), commitIDs[2].String())
+	assert.Nil(t, err)
+
+	err = repo.VerifyRefDiff(context.Background(), "main", commitIDs[0].String(), commitIDs[2].String())
+	assert.Nil(t, err)
+
+	err = repo.VerifyRefDiff(context.Background(), refName, plumbing.ZeroHash.String(), commitIDs[2].String())
+	assert.ErrorIs(t, err, plumbing.ErrObjectNotFound)
+}
+
+func TestGetReflogOnlyCommits(t *testing.T) {
+	tmpDir := t.TempDir()
+	repo := createTestRepositoryWithPolicy(t, tmpDir)
+
+	// The policy only protects main
+	protectedRefName := "refs/heads/main"
+	unprotectedRefName := "refs/heads/feature"
+	lostCommitIDs := map[string]plumbing.Hash{}
+	for _, refName := range []string{protectedRefName, unprotectedRefName} {
+		if err := repo.r.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(refName), plumbing.ZeroHash)); err != nil {
+			t.Fatal(err)
+		}
+
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo.r, refName, 2, gpgKeyName)
+
+		// The ref is rewound, leaving the second commit in the reflog alone
+		if err := repo.r.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(refName), commitIDs[0])); err != nil {
+			t.Fatal(err)
+		}
+		reflog := fmt.Sprintf("%s %s Jane Doe <jane.doe@example.com> 814694400 +0000\tcommit (initial): first\n", plumbing.ZeroHash, commitIDs[0])
+		reflog += fmt.Sprintf("%s %s Jane Doe <jane.doe@example.com> 814698000 +0000\tcommit: second\n", commitIDs[0], commitIDs[1])
+		reflog += fmt.Sprintf("%s %s Jane Doe <jane.doe@example.com> 814701600 +0000\treset: moving to HEAD~1\n", commitIDs[1], commitIDs[0])
+
+		reflogPath := filepath.Join(tmpDir, "logs", filepath.FromSlash(refName))
+		if err := os.MkdirAll(filepath.Dir(reflogPath), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(reflogPath, []byte(reflog), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		lostCommitIDs[refName] = commitIDs[1]
+	}
+
+	// Only the reflog of the protected branch is inspected
+	commits, err := repo.GetReflogOnlyCommits(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, map[string][]string{protectedRefName: {lostCommitIDs[protectedRefName].String()}}, commits)
+}
@@ -0,0 +1,172 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package rsl
+
+import (
+	"errors"
+	"io"
+
+	"github.com/gittuf/gittuf/internal/third_party/go-git"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing/storer"
+)
+
+// IteratorOptions contains the optional parameters of Iterator.
+type IteratorOptions struct {
+	OldestFirst bool
+}
+
+// IteratorOption is used to configure Iterator.
+type IteratorOption func(*IteratorOptions)
+
+// WithOldestFirst configures the iterator to return the RSL's entries from the
+// oldest to the newest. By default, entries are returned from the newest to the
+// oldest.
+func WithOldestFirst() IteratorOption {
+	return func(o *IteratorOptions) {
+		o.OldestFirst = true
+	}
+}
+
+// EntryIterator returns the entries in the RSL one at a time. Entries are
+// loaded as they are returned, so the RSL is never loaded into memory in its
+// entirety. Each entry is either a *ReferenceEntry or an *AnnotationEntry.
+type EntryIterator struct {
+	repo *git.Repository
+
+	// nextID is the ID of the next entry when iterating from the newest entry.
+	nextID plumbing.Hash
+
+	// entryIDs contains the IDs of the remaining entries when iterating from
+	// the oldest entry.
+	entryIDs    []plumbing.Hash
+	oldestFirst bool
+
+	closed bool
+}
+
+// Iterator returns an iterator over the entries in the local RSL, starting at
+// the RSL's current tip. When the entries are returned from the oldest to the
+// newest, the IDs of all the entries are walked and recorded upfront, but the
+// entries themselves are still loaded as they are returned.
+func Iterator(repo *git.Repository, opts ...IteratorOption) (*EntryIterator, error) {
+	options := &IteratorOptions{}
+	for _, fn := range opts {
+		fn(options)
+	}
+
+	ref, err := repo.Reference(plumbing.ReferenceName(Ref), true)
+	if err != nil {
+		return nil, err
+	}
+
+	iter := &EntryIterator{repo: repo, oldestFirst: options.OldestFirst}
+	if !options.OldestFirst {
+		iter.nextID = ref.Hash()
+		return iter, nil
+	}
+
+	entryIDs := []plumbing.Hash{}
+	for iteratorID := ref.Hash(); !iteratorID.IsZero(); {
+		entryIDs = append(entryIDs, iteratorID)
+
+		parentID, err := getParentID(repo, iteratorID)
+		if err != nil {
+			return nil, err
+		}
+		iteratorID = parentID
+	}
+
+	// Reverse entryIDs so that they're in order of occurrence rather than in
+	// order of walking back the RSL
+	for i, j := 0, len(entryIDs)-1; i < j; i, j = i+1, j-1 {
+		entryIDs[i], entryIDs[j] = entryIDs[j], entryIDs[i]
+	}
+	iter.entryIDs = entryIDs
+
+	return iter, nil
+}
+
+// Next returns the next entry in the RSL. When there are no more entries, or if
+// the iterator has been closed, io.EOF is returned.
+func (i *EntryIterator) Next() (Entry, error) {
+	if i.closed {
+		return nil, io.EOF
+	}
+
+	if i.oldestFirst {
+		if len(i.entryIDs) == 0 {
+			return nil, io.EOF
+		}
+
+		entryID := i.entryIDs[0]
+		i.entryIDs = i.entryIDs[1:]
+
+		return GetEntry(i.repo, entryID)
+	}
+
+	if i.nextID.IsZero() {
+		return nil, io.EOF
+	}
+
+	entry, err := GetEntry(i.repo, i.nextID)
+	if err != nil {
+		return nil, err
+	}
+
+	parentID, err := getParentID(i.repo, i.nextID)
+	if err != nil {
+		return nil, err
+	}
+	i.nextID = parentID
+
+	return entry, nil
+}
+
+// ForEach calls cb for each remaining entry in the RSL. If cb returns
+// storer.ErrStop, the iteration stops and nil is returned. The iterator is
+// closed when ForEach returns.
+func (i *EntryIterator) ForEach(cb func(Entry) error) error {
+	defer i.Close()
+
+	for {
+		entry, err := i.Next()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+
+		if err := cb(entry); err != nil {
+			if errors.Is(err, storer.ErrStop) {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+// Close stops the iteration. Subsequent calls to Next return io.EOF.
+func (i *EntryIterator) Close() {
+	i.closed = true
+	i.entryIDs = nil
+}
+
+// getParentID returns the ID of the parent of the specified RSL entry. If the
+// entry is the first entry in the RSL, the zero hash is returned.
+func getParentID(repo *git.Repository, entryID plumbing.Hash) (plumbing.Hash, error) {
+	commitObj, err := repo.CommitObject(entryID)
+	if err != nil {
+		return plumbing.ZeroHash, ErrRSLEntryNotFound
+	}
+
+	if len(commitObj.ParentHashes) > 1 {
+		return plumbing.ZeroHash, ErrRSLBranchDetected
+	}
+	if len(commitObj.ParentHashes) == 0 {
+		return plumbing.ZeroHash, nil
+	}
+
+	return commitObj.ParentHashes[0], nil
+}
@@ -0,0 +1,128 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package rsl
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/third_party/go-git"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing/storer"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/storage/memory"
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIterator(t *testing.T) {
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := InitializeNamespace(repo); err != nil {
+		t.Fatal(err)
+	}
+
+	// An empty RSL has no entries
+	iter, err := Iterator(repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = iter.Next()
+	assert.ErrorIs(t, err, io.EOF)
+
+	if err := NewReferenceEntry("refs/heads/main", plumbing.ZeroHash).Commit(repo, false); err != nil {
+		t.Fatal(err)
+	}
+	mainEntry, err := GetLatestEntry(repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := NewAnnotationEntry([]plumbing.Hash{mainEntry.GetID()}, true, annotationMessage).Commit(repo, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := NewReferenceEntry("refs/heads/feature", plumbing.ZeroHash).Commit(repo, false); err != nil {
+		t.Fatal(err)
+	}
+
+	describe := func(entry Entry) string {
+		switch entry := entry.(type) {
+		case *ReferenceEntry:
+			return entry.RefName
+		case *AnnotationEntry:
+			return "annotation"
+		}
+		return ""
+	}
+
+	t.Run("newest first", func(t *testing.T) {
+		iter, err := Iterator(repo)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		entries := []string{}
+		err = iter.ForEach(func(entry Entry) error {
+			entries = append(entries, describe(entry))
+			return nil
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"refs/heads/feature", "annotation", "refs/heads/main"}, entries)
+	})
+
+	t.Run("oldest first", func(t *testing.T) {
+		iter, err := Iterator(repo, WithOldestFirst())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		entries := []string{}
+		for {
+			entry, err := iter.Next()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			entries = append(entries, describe(entry))
+
+			if annotation, isAnnotation := entry.(*AnnotationEntry); isAnnotation {
+				assert.True(t, annotation.RefersTo(mainEntry.GetID()))
+				assert.True(t, annotation.Skip)
+			}
+		}
+		assert.Equal(t, []string{"refs/heads/main", "annotation", "refs/heads/feature"}, entries)
+	})
+
+	t.Run("stop early", func(t *testing.T) {
+		iter, err := Iterator(repo)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		entry, err := iter.Next()
+		assert.Nil(t, err)
+		assert.Equal(t, "refs/heads/feature", describe(entry))
+
+		iter.Close()
+		_, err = iter.Next()
+		assert.ErrorIs(t, err, io.EOF)
+
+		iter, err = Iterator(repo, WithOldestFirst())
+		if err != nil {
+			t.Fatal(err)
+		}
+		entries := []string{}
+		err = iter.ForEach(func(entry Entry) error {
+			entries = append(entries, describe(entry))
+			if _, isAnnotation := entry.(*AnnotationEntry); isAnnotation {
+				return storer.ErrStop
+			}
+			return nil
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"refs/heads/main", "annotation"}, entries)
+	})
+}
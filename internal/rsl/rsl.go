@@ -3,15 +3,19 @@
 package rsl
 
 import (
+	"context"
 	"encoding/pem"
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/logging"
 	"github.com/gittuf/gittuf/internal/third_party/go-git"
 	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing"
 	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing/object"
+	"github.com/gittuf/gittuf/internal/version"
 )
 
 const (
@@ -20,12 +24,17 @@ const (
 	ReferenceEntryHeader       = "RSL Reference Entry"
 	RefKey                     = "ref"
 	TargetIDKey                = "targetID"
+	MergeBaseKey               = "mergeBase"
 	AnnotationEntryHeader      = "RSL Annotation Entry"
 	AnnotationMessageBlockType = "MESSAGE"
 	BeginMessage               = "-----BEGIN MESSAGE-----"
 	EndMessage                 = "-----END MESSAGE-----"
 	EntryIDKey                 = "entryID"
 	SkipKey                    = "skip"
+	ForcePushKey               = "forcePush"
+	BreakGlassKey              = "breakGlass"
+	GittufVersionKey           = "gittufVersion"
+	SigningMethodKey           = "signingMethod"
 
 	remoteTrackerRef = "refs/remotes/%s/gittuf/reference-state-log"
 )
@@ -37,6 +46,10 @@ var (
 	ErrInvalidRSLEntry         = errors.New("RSL entry has invalid format or is of unexpected type")
 	ErrRSLEntryDoesNotMatchRef = errors.New("RSL entry does not match requested ref")
 	ErrNoRecordOfCommit        = errors.New("commit has not been encountered before")
+	ErrRSLEntryNotInChain      = errors.New("RSL does not descend from the specified entry")
+	ErrCannotAmendPushedEntry  = errors.New("cannot amend RSL entry that is present on the remote")
+	ErrRSLForksConflict        = errors.New("local and remote RSLs have diverged with entries for the same refs")
+	ErrRSLChainBroken          = errors.New("RSL chain is broken")
 )
 
 // InitializeNamespace creates a git ref for the reference state log. Initially,
@@ -69,7 +82,8 @@ func RemoteTrackerRef(remote string) string {
 // Entry is the abstract representation of an object in the RSL.
 type Entry interface {
 	GetID() plumbing.Hash
-	Commit(*git.Repository, bool) error
+	Commit(*git.Repository, bool, ...gitinterface.CommitOption) error
+	CommitWhileLocked(*git.Repository, bool, ...gitinterface.CommitOption) error
 	createCommitMessage() (string, error)
 }
 
@@ -84,6 +98,19 @@ type ReferenceEntry struct {
 
 	// TargetID contains the Git hash for the object expected at RefName.
 	TargetID plumbing.Hash
+
+	// MergeBase optionally contains the merge base of TargetID and the target
+	// of the previous entry for RefName. Together with the previous entry's
+	// target, it identifies the range of commits the entry introduced. It is
+	// zero if it was not recorded.
+	MergeBase plumbing.Hash
+
+	// GittufVersion and SigningMethod optionally record the version of gittuf
+	// and the Git signing method used to create the entry. They are meant to
+	// be surfaced by tooling for provenance, and are not used during
+	// verification.
+	GittufVersion string
+	SigningMethod string
 }
 
 // NewReferenceEntry returns a ReferenceEntry object for a normal RSL entry.
@@ -91,18 +118,141 @@ func NewReferenceEntry(refName string, targetID plumbing.Hash) *ReferenceEntry {
 	return &ReferenceEntry{RefName: refName, TargetID: targetID}
 }
 
+// NewDeletionEntry returns a ReferenceEntry object that records the deletion of
+// the ref. Deletions are recorded using the zero hash as the entry's target.
+func NewDeletionEntry(refName string) *ReferenceEntry {
+	return &ReferenceEntry{RefName: refName, TargetID: plumbing.ZeroHash}
+}
+
 func (e *ReferenceEntry) GetID() plumbing.Hash {
 	return e.ID
 }
 
-// Commit creates a commit object in the RSL for the ReferenceEntry.
-func (e *ReferenceEntry) Commit(repo *git.Repository, sign bool) error {
+// IsDeletion indicates if the entry records the deletion of its ref.
+func (e *ReferenceEntry) IsDeletion() bool {
+	return e.TargetID.IsZero()
+}
+
+// SetMergeBase records the merge base of the entry's target and the target of
+// the latest entry for the same ref in the RSL. The merge base is left unset
+// if there is no earlier entry for the ref, or if either target is not a
+// commit available locally.
+func (e *ReferenceEntry) SetMergeBase(repo *git.Repository) error {
+	if e.TargetID.IsZero() {
+		return nil
+	}
+
+	previousEntry, _, err := GetLatestReferenceEntryForRef(repo, e.RefName)
+	if err != nil {
+		if errors.Is(err, ErrRSLEntryNotFound) {
+			return nil
+		}
+		return err
+	}
+	if previousEntry.TargetID.IsZero() {
+		return nil
+	}
+
+	targetCommit, err := repo.CommitObject(e.TargetID)
+	if err != nil {
+		if errors.Is(err, plumbing.ErrObjectNotFound) {
+			return nil
+		}
+		return err
+	}
+	previousCommit, err := repo.CommitObject(previousEntry.TargetID)
+	if err != nil {
+		if errors.Is(err, plumbing.ErrObjectNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	mergeBases, err := targetCommit.MergeBase(previousCommit)
+	if err != nil {
+		return err
+	}
+	if len(mergeBases) == 0 {
+		// The histories are unrelated, such as after a force push
+		return nil
+	}
+
+	e.MergeBase = mergeBases[0].Hash
+	return nil
+}
+
+// Commit creates a commit object in the RSL for the ReferenceEntry. The
+// options are passed through to gitinterface.Commit, and can be used to set the
+// committer identity of the RSL entry. The repository's gittuf lock is held
+// while the entry is created so that concurrent gittuf processes do not fork
+// the RSL.
+func (e *ReferenceEntry) Commit(repo *git.Repository, sign bool, opts ...gitinterface.CommitOption) error {
+	lock, err := gitinterface.LockRepository(repo, gitinterface.DefaultLockTimeout)
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock() //nolint:errcheck
+
+	return e.CommitWhileLocked(repo, sign, opts...)
+}
+
+// CommitWhileLocked creates a commit object in the RSL for the ReferenceEntry
+// without acquiring the repository's gittuf lock. It must only be used by
+// callers that already hold the lock, such as when the entry records a change
+// made as part of the same locked operation.
+func (e *ReferenceEntry) CommitWhileLocked(repo *git.Repository, sign bool, opts ...gitinterface.CommitOption) error {
 	message, _ := e.createCommitMessage() // we have an error return for annotations, always nil here
 
-	_, err := gitinterface.Commit(repo, gitinterface.EmptyTree(), Ref, message, sign)
+	_, err := gitinterface.Commit(repo, gitinterface.EmptyTree(), Ref, message, sign, opts...)
 	return err
 }
 
+// CreateCommitObject writes a commit object for the ReferenceEntry that extends
+// the current RSL, signed if sign is set, and sets the entry's ID to the
+// commit's ID. Unlike Commit, the RSL is not updated, so the entry can be
+// inspected, such as to verify it against the policy, before it's recorded
+// using RecordWhileLocked. Callers must hold the repository's gittuf lock until
+// the entry is recorded.
+func (e *ReferenceEntry) CreateCommitObject(repo *git.Repository, sign bool, opts ...gitinterface.CommitOption) error {
+	message, _ := e.createCommitMessage() // we have an error return for annotations, always nil here
+
+	commit, err := gitinterface.CreateCommitObjectForRef(repo, gitinterface.EmptyTree(), Ref, message, opts...)
+	if err != nil {
+		return err
+	}
+	if sign {
+		if err := gitinterface.SignCommit(commit, opts...); err != nil {
+			return err
+		}
+	}
+
+	commitID, err := gitinterface.WriteCommit(repo, commit)
+	if err != nil {
+		return err
+	}
+	e.ID = commitID
+
+	return nil
+}
+
+// RecordWhileLocked adds the ReferenceEntry, whose commit object was created
+// using CreateCommitObject, to the RSL without acquiring the repository's gittuf
+// lock. If the RSL has moved since the commit object was created, the entry is
+// not recorded and an error is returned.
+func (e *ReferenceEntry) RecordWhileLocked(repo *git.Repository) error {
+	commit, err := repo.CommitObject(e.ID)
+	if err != nil {
+		return err
+	}
+
+	parentID := plumbing.ZeroHash
+	if len(commit.ParentHashes) > 0 {
+		parentID = commit.ParentHashes[0]
+	}
+
+	return repo.Storer.CheckAndSetReference(plumbing.NewHashReference(Ref, e.ID), plumbing.NewHashReference(Ref, parentID))
+}
+
 func (e *ReferenceEntry) createCommitMessage() (string, error) {
 	lines := []string{
 		ReferenceEntryHeader,
@@ -110,6 +260,10 @@ func (e *ReferenceEntry) createCommitMessage() (string, error) {
 		fmt.Sprintf("%s: %s", RefKey, e.RefName),
 		fmt.Sprintf("%s: %s", TargetIDKey, e.TargetID.String()),
 	}
+	if !e.MergeBase.IsZero() {
+		lines = append(lines, fmt.Sprintf("%s: %s", MergeBaseKey, e.MergeBase.String()))
+	}
+	lines = append(lines, createProvenanceLines(e.GittufVersion, e.SigningMethod)...)
 	return strings.Join(lines, "\n"), nil
 }
 
@@ -127,6 +281,21 @@ type AnnotationEntry struct {
 	// Skip indicates if the RSLEntryIDs must be skipped during gittuf workflows.
 	Skip bool
 
+	// ForcePush indicates if the annotation authorizes the non-fast-forward
+	// ref updates recorded in RSLEntryIDs.
+	ForcePush bool
+
+	// BreakGlass indicates if the annotation is an emergency authorization of
+	// the ref updates recorded in RSLEntryIDs that overrides the policy's
+	// rules. It must be signed by keys of the root of trust's emergency role.
+	BreakGlass bool
+
+	// GittufVersion and SigningMethod optionally record the version of gittuf
+	// and the Git signing method used to create the annotation. Like for
+	// ReferenceEntry, they are not used during verification.
+	GittufVersion string
+	SigningMethod string
+
 	// Message contains any messages or notes added by a user for the annotation.
 	Message string
 }
@@ -137,12 +306,40 @@ func NewAnnotationEntry(rslEntryIDs []plumbing.Hash, skip bool, message string)
 	return &AnnotationEntry{RSLEntryIDs: rslEntryIDs, Skip: skip, Message: message}
 }
 
+// NewForcePushAnnotationEntry returns an Annotation object that authorizes the
+// non-fast-forward ref updates recorded in one or more prior RSL entries.
+func NewForcePushAnnotationEntry(rslEntryIDs []plumbing.Hash, message string) *AnnotationEntry {
+	return &AnnotationEntry{RSLEntryIDs: rslEntryIDs, ForcePush: true, Message: message}
+}
+
+// NewBreakGlassAnnotationEntry returns an Annotation object that records the
+// emergency authorization of the ref updates in one or more prior RSL entries.
+// The message is expected to justify the use of break-glass access.
+func NewBreakGlassAnnotationEntry(rslEntryIDs []plumbing.Hash, message string) *AnnotationEntry {
+	return &AnnotationEntry{RSLEntryIDs: rslEntryIDs, BreakGlass: true, Message: message}
+}
+
 func (a *AnnotationEntry) GetID() plumbing.Hash {
 	return a.ID
 }
 
-// Commit creates a commit object in the RSL for the Annotation.
-func (a *AnnotationEntry) Commit(repo *git.Repository, sign bool) error {
+// Commit creates a commit object in the RSL for the Annotation. The options
+// are passed through to gitinterface.Commit. The repository's gittuf lock is
+// held while the annotation is created.
+func (a *AnnotationEntry) Commit(repo *git.Repository, sign bool, opts ...gitinterface.CommitOption) error {
+	lock, err := gitinterface.LockRepository(repo, gitinterface.DefaultLockTimeout)
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock() //nolint:errcheck
+
+	return a.CommitWhileLocked(repo, sign, opts...)
+}
+
+// CommitWhileLocked creates a commit object in the RSL for the Annotation
+// without acquiring the repository's gittuf lock. It must only be used by
+// callers that already hold the lock.
+func (a *AnnotationEntry) CommitWhileLocked(repo *git.Repository, sign bool, opts ...gitinterface.CommitOption) error {
 	// Check if referred entries exist in the RSL namespace.
 	for _, id := range a.RSLEntryIDs {
 		if _, err := GetEntry(repo, id); err != nil {
@@ -155,7 +352,7 @@ func (a *AnnotationEntry) Commit(repo *git.Repository, sign bool) error {
 		return err
 	}
 
-	_, err = gitinterface.Commit(repo, gitinterface.EmptyTree(), Ref, message, sign)
+	_, err = gitinterface.Commit(repo, gitinterface.EmptyTree(), Ref, message, sign, opts...)
 	return err
 }
 
@@ -187,6 +384,16 @@ func (a *AnnotationEntry) createCommitMessage() (string, error) {
 		lines = append(lines, fmt.Sprintf("%s: false", SkipKey))
 	}
 
+	if a.ForcePush {
+		lines = append(lines, fmt.Sprintf("%s: true", ForcePushKey))
+	}
+
+	if a.BreakGlass {
+		lines = append(lines, fmt.Sprintf("%s: true", BreakGlassKey))
+	}
+
+	lines = append(lines, createProvenanceLines(a.GittufVersion, a.SigningMethod)...)
+
 	if len(a.Message) != 0 {
 		var message strings.Builder
 		messageBlock := pem.Block{
@@ -202,6 +409,44 @@ func (a *AnnotationEntry) createCommitMessage() (string, error) {
 	return strings.Join(lines, "\n"), nil
 }
 
+// SetProvenance records the running version of gittuf in the entry, along with
+// the signing method configured in the user's Git config if the entry is to be
+// signed. The provenance is informational, so the signing method is left unset
+// if it cannot be determined.
+func SetProvenance(entry Entry, sign bool) {
+	gittufVersion := version.GetVersion()
+
+	signingMethod := ""
+	if sign {
+		if method, err := gitinterface.GetSigningMethod(); err == nil {
+			signingMethod = method.String()
+		}
+	}
+
+	switch entry := entry.(type) {
+	case *ReferenceEntry:
+		entry.GittufVersion = gittufVersion
+		entry.SigningMethod = signingMethod
+	case *AnnotationEntry:
+		entry.GittufVersion = gittufVersion
+		entry.SigningMethod = signingMethod
+	}
+}
+
+// createProvenanceLines returns the lines of an entry's commit message that
+// record its provenance. Unset fields are omitted so that entries without
+// provenance are unchanged.
+func createProvenanceLines(gittufVersion, signingMethod string) []string {
+	lines := []string{}
+	if len(gittufVersion) != 0 {
+		lines = append(lines, fmt.Sprintf("%s: %s", GittufVersionKey, gittufVersion))
+	}
+	if len(signingMethod) != 0 {
+		lines = append(lines, fmt.Sprintf("%s: %s", SigningMethodKey, signingMethod))
+	}
+	return lines
+}
+
 // GetEntry returns the entry corresponding to entryID.
 func GetEntry(repo *git.Repository, entryID plumbing.Hash) (Entry, error) {
 	commitObj, err := repo.CommitObject(entryID)
@@ -288,6 +533,254 @@ func GetNonGittufParentReferenceEntryForEntry(repo *git.Repository, entry Entry)
 	return targetEntry, annotations, nil
 }
 
+// AmendLatestEntry replaces the latest entry in the local RSL with the
+// specified entry. This is only permitted if the latest entry has not been
+// pushed, i.e., it is not known to the specified remote's RSL tracker.
+// Otherwise, ErrCannotAmendPushedEntry is returned as the entry may already have
+// been seen by other users, and the entry must be revoked using an annotation
+// instead. If the remote's RSL has never been fetched, no entries are
+// considered to be pushed. The options are passed through to
+// gitinterface.Commit.
+func AmendLatestEntry(repo *git.Repository, remoteName string, entry Entry, sign bool, opts ...gitinterface.CommitOption) error {
+	lock, err := gitinterface.LockRepository(repo, gitinterface.DefaultLockTimeout)
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock() //nolint:errcheck
+
+	ref, err := repo.Reference(plumbing.ReferenceName(Ref), true)
+	if err != nil {
+		return err
+	}
+	latestCommit, err := repo.CommitObject(ref.Hash())
+	if err != nil {
+		return ErrRSLEntryNotFound
+	}
+
+	remoteTip, err := gitinterface.GetTip(repo, RemoteTrackerRef(remoteName))
+	if err != nil {
+		if !errors.Is(err, plumbing.ErrReferenceNotFound) {
+			return err
+		}
+	} else if !remoteTip.IsZero() {
+		knows, err := gitinterface.KnowsCommit(repo, remoteTip, latestCommit)
+		if err != nil {
+			return err
+		}
+		if knows {
+			return ErrCannotAmendPushedEntry
+		}
+	}
+
+	if len(latestCommit.ParentHashes) > 1 {
+		return ErrRSLBranchDetected
+	}
+	parentID := plumbing.ZeroHash
+	if len(latestCommit.ParentHashes) == 1 {
+		parentID = latestCommit.ParentHashes[0]
+	}
+
+	// The new entry is committed on top of the latest entry's parent, and the
+	// latest entry is restored if that fails
+	if err := repo.Storer.CheckAndSetReference(plumbing.NewHashReference(plumbing.ReferenceName(Ref), parentID), ref); err != nil {
+		return err
+	}
+
+	if err := entry.CommitWhileLocked(repo, sign, opts...); err != nil {
+		if resetErr := repo.Storer.SetReference(ref); resetErr != nil {
+			return errors.Join(err, resetErr)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// Reconcile merges the local RSL with the RSL fetched from the specified remote
+// into its RSL tracker when the two have diverged. The remote's entries may
+// already have been seen by other users, so they are kept as is, and the local
+// entries recorded since the last entry the RSLs share are re-created on top of
+// the remote's tip in their original order. This preserves the order of the
+// entries for each ref as long as the two forks record entries for different
+// refs. If both forks record entries for the same ref, including annotations
+// for entries of that ref, ErrRSLForksConflict is returned and the RSL must be
+// reconciled manually. Annotations that refer to re-created entries are updated
+// to refer to their new IDs. The re-created entries are committed by the current
+// user, and the sign flag and options are passed through to gitinterface.Commit.
+// If the local RSL is behind the remote RSL, it is fast-forwarded, and if it is
+// ahead, it is left as is.
+func Reconcile(ctx context.Context, repo *git.Repository, remoteName string, sign bool, opts ...gitinterface.CommitOption) error {
+	lock, err := gitinterface.LockRepository(repo, gitinterface.DefaultLockTimeout)
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock() //nolint:errcheck
+
+	localRef, err := repo.Reference(plumbing.ReferenceName(Ref), true)
+	if err != nil {
+		return err
+	}
+	localTip := localRef.Hash()
+
+	remoteTip, err := gitinterface.GetTip(repo, RemoteTrackerRef(remoteName))
+	if err != nil {
+		return err
+	}
+
+	if remoteTip == localTip || remoteTip.IsZero() {
+		return nil
+	}
+
+	remoteEntryIDs := map[plumbing.Hash]bool{}
+	for iteratorID := remoteTip; !iteratorID.IsZero(); {
+		remoteEntryIDs[iteratorID] = true
+
+		commitObj, err := repo.CommitObject(iteratorID)
+		if err != nil {
+			return err
+		}
+		if len(commitObj.ParentHashes) > 1 {
+			return ErrRSLBranchDetected
+		}
+		if len(commitObj.ParentHashes) == 0 {
+			break
+		}
+		iteratorID = commitObj.ParentHashes[0]
+	}
+
+	if localTip.IsZero() || remoteEntryIDs[localTip] {
+		// The local RSL is behind the remote RSL
+		return repo.Storer.CheckAndSetReference(plumbing.NewHashReference(plumbing.ReferenceName(Ref), remoteTip), localRef)
+	}
+
+	// Find the latest entry the two RSLs share
+	baseID := plumbing.ZeroHash
+	for iteratorID := localTip; !iteratorID.IsZero(); {
+		if remoteEntryIDs[iteratorID] {
+			baseID = iteratorID
+			break
+		}
+
+		commitObj, err := repo.CommitObject(iteratorID)
+		if err != nil {
+			return err
+		}
+		if len(commitObj.ParentHashes) > 1 {
+			return ErrRSLBranchDetected
+		}
+		if len(commitObj.ParentHashes) == 0 {
+			break
+		}
+		iteratorID = commitObj.ParentHashes[0]
+	}
+
+	if baseID == remoteTip {
+		// The local RSL is ahead of the remote RSL
+		return nil
+	}
+	if baseID.IsZero() {
+		return fmt.Errorf("%w: local and remote RSLs do not share any entries", ErrRSLEntryNotInChain)
+	}
+
+	localEntries, err := GetEntriesSince(repo, baseID, localTip)
+	if err != nil {
+		return err
+	}
+	remoteEntries, err := GetEntriesSince(repo, baseID, remoteTip)
+	if err != nil {
+		return err
+	}
+
+	localRefs, err := getRefsForEntries(repo, localEntries)
+	if err != nil {
+		return err
+	}
+	remoteRefs, err := getRefsForEntries(repo, remoteEntries)
+	if err != nil {
+		return err
+	}
+	conflictingRefs := []string{}
+	for refName := range localRefs {
+		if remoteRefs[refName] {
+			conflictingRefs = append(conflictingRefs, refName)
+		}
+	}
+	if len(conflictingRefs) > 0 {
+		sort.Strings(conflictingRefs)
+		return fmt.Errorf("%w: %s", ErrRSLForksConflict, strings.Join(conflictingRefs, ", "))
+	}
+
+	// The local entries are re-created on top of the remote's tip, and the
+	// local RSL is restored if that fails
+	if err := repo.Storer.CheckAndSetReference(plumbing.NewHashReference(plumbing.ReferenceName(Ref), remoteTip), localRef); err != nil {
+		return err
+	}
+
+	logger := logging.FromContext(ctx)
+	newEntryIDs := map[plumbing.Hash]plumbing.Hash{}
+	for _, entry := range localEntries {
+		if annotation, isAnnotation := entry.(*AnnotationEntry); isAnnotation {
+			rslEntryIDs := make([]plumbing.Hash, 0, len(annotation.RSLEntryIDs))
+			for _, id := range annotation.RSLEntryIDs {
+				if newID, has := newEntryIDs[id]; has {
+					id = newID
+				}
+				rslEntryIDs = append(rslEntryIDs, id)
+			}
+			annotation.RSLEntryIDs = rslEntryIDs
+		}
+
+		if err := entry.CommitWhileLocked(repo, sign, opts...); err != nil {
+			if resetErr := repo.Storer.SetReference(localRef); resetErr != nil {
+				return errors.Join(err, resetErr)
+			}
+			return err
+		}
+
+		newID, err := gitinterface.GetTip(repo, Ref)
+		if err != nil {
+			return err
+		}
+		newEntryIDs[entry.GetID()] = newID
+
+		logger.DebugContext(ctx, logging.EventRSLEntryReconciled, "remote", remoteName, "entry", entry.GetID().String(), "new_entry", newID.String())
+	}
+
+	return nil
+}
+
+// getRefsForEntries returns the refs that the entries record updates for. For
+// annotations, the refs of the reference entries they refer to are returned.
+func getRefsForEntries(repo *git.Repository, entries []Entry) (map[string]bool, error) {
+	refNames := map[string]bool{}
+	referenceEntries := map[plumbing.Hash]*ReferenceEntry{}
+
+	for _, entry := range entries {
+		switch entry := entry.(type) {
+		case *ReferenceEntry:
+			refNames[entry.RefName] = true
+			referenceEntries[entry.ID] = entry
+		case *AnnotationEntry:
+			for _, id := range entry.RSLEntryIDs {
+				referenceEntry, has := referenceEntries[id]
+				if !has {
+					annotatedEntry, err := GetEntry(repo, id)
+					if err != nil {
+						return nil, err
+					}
+					referenceEntry, has = annotatedEntry.(*ReferenceEntry)
+					if !has {
+						continue
+					}
+				}
+				refNames[referenceEntry.RefName] = true
+			}
+		}
+	}
+
+	return refNames, nil
+}
+
 // GetLatestEntry returns the latest entry available locally in the RSL.
 func GetLatestEntry(repo *git.Repository) (Entry, error) {
 	ref, err := repo.Reference(plumbing.ReferenceName(Ref), true)
@@ -454,11 +947,69 @@ func GetFirstEntry(repo *git.Repository) (*ReferenceEntry, []*AnnotationEntry, e
 	return firstEntry, annotations, nil
 }
 
+// VerifyChainIntegrity walks the RSL from its tip to its first entry and checks
+// that the entries form a single, unbroken chain: each entry's commit must have
+// exactly one parent that is itself an RSL entry, except the first entry which
+// must have no parent. The first entry must also be a reference entry. Only the
+// structure of the RSL is checked, the entries' signatures are not verified.
+// If the chain is broken, ErrRSLChainBroken is returned identifying the break
+// closest to the tip. An RSL with no entries is considered intact.
+func VerifyChainIntegrity(repo *git.Repository) error {
+	ref, err := repo.Reference(plumbing.ReferenceName(Ref), true)
+	if err != nil {
+		return err
+	}
+
+	emptyTreeID := gitinterface.EmptyTree()
+
+	// childID is the entry whose parent link led to entryID
+	childID := plumbing.ZeroHash
+	for entryID := ref.Hash(); !entryID.IsZero(); {
+		commitObj, err := repo.CommitObject(entryID)
+		if err != nil {
+			if childID.IsZero() {
+				return fmt.Errorf("%w: RSL tip '%s' is not a commit", ErrRSLChainBroken, entryID.String())
+			}
+			return fmt.Errorf("%w: parent '%s' of entry '%s' is not a commit", ErrRSLChainBroken, entryID.String(), childID.String())
+		}
+
+		if commitObj.TreeHash != emptyTreeID {
+			return fmt.Errorf("%w: '%s' is not an RSL entry as it has a non-empty tree", ErrRSLChainBroken, entryID.String())
+		}
+
+		message := strings.TrimSpace(commitObj.Message)
+		isReferenceEntry := strings.HasPrefix(message, ReferenceEntryHeader)
+		if !isReferenceEntry && !strings.HasPrefix(message, AnnotationEntryHeader) {
+			return fmt.Errorf("%w: '%s' is not an RSL entry", ErrRSLChainBroken, entryID.String())
+		}
+		if _, err := parseRSLEntryText(entryID, commitObj.Message); err != nil {
+			return errors.Join(fmt.Errorf("%w: entry '%s' is malformed", ErrRSLChainBroken, entryID.String()), err)
+		}
+
+		switch len(commitObj.ParentHashes) {
+		case 0:
+			if !isReferenceEntry {
+				return fmt.Errorf("%w: first entry '%s' is an annotation", ErrRSLChainBroken, entryID.String())
+			}
+			return nil
+		case 1:
+			childID = entryID
+			entryID = commitObj.ParentHashes[0]
+		default:
+			return errors.Join(fmt.Errorf("%w: entry '%s' has %d parents", ErrRSLChainBroken, entryID.String(), len(commitObj.ParentHashes)), ErrRSLBranchDetected)
+		}
+	}
+
+	return nil
+}
+
 // GetFirstReferenceEntryForCommit returns the first reference entry in the RSL
 // that either records the commit itself or a descendent of the commit. This
 // establishes the first time a commit was seen in the repository, irrespective
 // of the ref it was associated with, and we can infer things like the active
-// developers who could have signed the commit.
+// developers who could have signed the commit. Entries that don't point a ref
+// to a commit, such as entries for deleted refs and annotated tags, are not
+// considered.
 func GetFirstReferenceEntryForCommit(repo *git.Repository, commit *object.Commit) (*ReferenceEntry, []*AnnotationEntry, error) {
 	// We check entries in pairs. In the initial case, we have the latest entry
 	// and its parent. At all times, the parent in the pair is being tested.
@@ -467,6 +1018,9 @@ func GetFirstReferenceEntryForCommit(repo *git.Repository, commit *object.Commit
 	// descended from the target commit, we return the other entry in the pair.
 
 	firstEntry, firstAnnotations, err := GetLatestNonGittufReferenceEntry(repo)
+	if err == nil {
+		firstEntry, firstAnnotations, err = skipReferenceEntriesWithoutCommitTarget(repo, firstEntry, firstAnnotations)
+	}
 	if err != nil {
 		if errors.Is(err, ErrRSLEntryNotFound) {
 			return nil, nil, ErrNoRecordOfCommit
@@ -484,6 +1038,9 @@ func GetFirstReferenceEntryForCommit(repo *git.Repository, commit *object.Commit
 
 	for {
 		iteratorEntry, iteratorAnnotations, err := GetNonGittufParentReferenceEntryForEntry(repo, firstEntry)
+		if err == nil {
+			iteratorEntry, iteratorAnnotations, err = skipReferenceEntriesWithoutCommitTarget(repo, iteratorEntry, iteratorAnnotations)
+		}
 		if err != nil {
 			if errors.Is(err, ErrRSLEntryNotFound) {
 				return firstEntry, firstAnnotations, nil
@@ -504,6 +1061,89 @@ func GetFirstReferenceEntryForCommit(repo *git.Repository, commit *object.Commit
 	}
 }
 
+// skipReferenceEntriesWithoutCommitTarget returns the specified entry if its
+// target is a commit. Otherwise, the closest non-gittuf parent entry whose
+// target is a commit is returned.
+func skipReferenceEntriesWithoutCommitTarget(repo *git.Repository, entry *ReferenceEntry, annotations []*AnnotationEntry) (*ReferenceEntry, []*AnnotationEntry, error) {
+	for {
+		if !entry.IsDeletion() {
+			targetObj, err := repo.Storer.EncodedObject(plumbing.AnyObject, entry.TargetID)
+			if err != nil {
+				return nil, nil, err
+			}
+			if targetObj.Type() == plumbing.CommitObject {
+				return entry, annotations, nil
+			}
+		}
+
+		var err error
+		entry, annotations, err = GetNonGittufParentReferenceEntryForEntry(repo, entry)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+}
+
+// GetReferenceEntriesForRef returns a list of all the reference entries for
+// the ref in the order they were recorded in the RSL and a map of annotations
+// that refer to each reference entry. The annotations map is keyed by the ID of
+// the reference entry, with the value being a list of annotations that apply to
+// that reference entry.
+func GetReferenceEntriesForRef(repo *git.Repository, refName string) ([]*ReferenceEntry, map[plumbing.Hash][]*AnnotationEntry, error) {
+	iterator, err := GetLatestEntry(repo)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	entryStack := []*ReferenceEntry{}
+	allAnnotations := []*AnnotationEntry{}
+	for {
+		switch it := iterator.(type) {
+		case *ReferenceEntry:
+			if it.RefName == refName {
+				entryStack = append(entryStack, it)
+			}
+		case *AnnotationEntry:
+			allAnnotations = append(allAnnotations, it)
+		}
+
+		parent, err := GetParentForEntry(repo, iterator)
+		if err != nil {
+			if errors.Is(err, ErrRSLEntryNotFound) {
+				// We've reached the first entry
+				break
+			}
+			return nil, nil, err
+		}
+		iterator = parent
+	}
+
+	if len(entryStack) == 0 {
+		return nil, nil, ErrRSLEntryNotFound
+	}
+
+	// Process annotations in reverse order so that annotations are listed in
+	// order of occurrence in the map
+	annotationMap := map[plumbing.Hash][]*AnnotationEntry{}
+	for i := len(allAnnotations) - 1; i >= 0; i-- {
+		annotation := allAnnotations[i]
+		for _, entry := range entryStack {
+			if annotation.RefersTo(entry.ID) {
+				annotationMap[entry.ID] = append(annotationMap[entry.ID], annotation)
+			}
+		}
+	}
+
+	// Reverse entryStack so that it's in order of occurrence rather than in
+	// order of walking back the RSL
+	allEntries := make([]*ReferenceEntry, 0, len(entryStack))
+	for i := len(entryStack) - 1; i >= 0; i-- {
+		allEntries = append(allEntries, entryStack[i])
+	}
+
+	return allEntries, annotationMap, nil
+}
+
 // GetReferenceEntriesInRange returns a list of reference entries between the
 // specified range and a map of annotations that refer to each reference entry
 // in the range. The annotations map is keyed by the ID of the reference entry,
@@ -610,6 +1250,131 @@ func GetReferenceEntriesInRangeForRef(repo *git.Repository, firstID, lastID plum
 	return allEntries, annotationMap, nil
 }
 
+// GetEntriesSince returns the RSL entries recorded after the entry baseID up to
+// and including the entry tipID, ordered from oldest to newest. It checks the
+// continuity of this segment of the RSL: every entry must be a valid RSL entry
+// with a single parent, and the chain of parents must lead back to baseID. If
+// baseID is the zero hash, all the entries reachable from tipID are returned.
+// If the chain of parents does not lead to baseID, ErrRSLEntryNotInChain is
+// returned.
+func GetEntriesSince(repo *git.Repository, baseID, tipID plumbing.Hash) ([]Entry, error) {
+	entryStack := []Entry{}
+
+	for iteratorID := tipID; iteratorID != baseID; {
+		commitObj, err := repo.CommitObject(iteratorID)
+		if err != nil {
+			return nil, err
+		}
+
+		entry, err := parseRSLEntryText(iteratorID, commitObj.Message)
+		if err != nil {
+			return nil, err
+		}
+		entryStack = append(entryStack, entry)
+
+		if len(commitObj.ParentHashes) > 1 {
+			return nil, ErrRSLBranchDetected
+		}
+		if len(commitObj.ParentHashes) == 0 {
+			if !baseID.IsZero() {
+				return nil, ErrRSLEntryNotInChain
+			}
+			break
+		}
+
+		iteratorID = commitObj.ParentHashes[0]
+	}
+
+	// Reverse entryStack so that it's in order of occurrence rather than in
+	// order of walking back the RSL
+	allEntries := make([]Entry, 0, len(entryStack))
+	for i := len(entryStack) - 1; i >= 0; i-- {
+		allEntries = append(allEntries, entryStack[i])
+	}
+
+	return allEntries, nil
+}
+
+// IntroducedCommits describes the commits recorded for a ref by a reference
+// entry that were not recorded by the previous reference entry for the ref.
+type IntroducedCommits struct {
+	// PreviousEntry is the previous reference entry for the ref. It is nil
+	// if the entry is the first entry for the ref.
+	PreviousEntry *ReferenceEntry
+
+	// Commits are the commits reachable from the entry's target that are
+	// not reachable from the previous entry's target, ordered such that
+	// every commit appears after its parents. If the entry is the first
+	// entry for the ref, all the commits reachable from its target are
+	// included.
+	Commits []*object.Commit
+
+	// Rewritten indicates that the previous entry's target is not an
+	// ancestor of the entry's target, i.e., the entry records a force push.
+	Rewritten bool
+
+	// DroppedCommits are the commits reachable from the previous entry's
+	// target that are no longer reachable from the entry's target. It is
+	// only set when Rewritten is true.
+	DroppedCommits []*object.Commit
+}
+
+// CommitsIntroducedByEntry identifies the commits newly recorded for the
+// entry's ref by the entry, relative to the previous reference entry for the
+// same ref. If the entry records a deletion, no commits are introduced. If the
+// previous entry records a deletion, the ref is treated as newly created and
+// all the commits reachable from the entry's target are introduced.
+func CommitsIntroducedByEntry(repo *git.Repository, entry *ReferenceEntry) (*IntroducedCommits, error) {
+	introduced := &IntroducedCommits{}
+
+	previousEntry, _, err := GetLatestReferenceEntryForRefBefore(repo, entry.RefName, entry.ID)
+	if err != nil {
+		if !errors.Is(err, ErrRSLEntryNotFound) {
+			return nil, err
+		}
+	} else {
+		introduced.PreviousEntry = previousEntry
+	}
+
+	if entry.IsDeletion() {
+		return introduced, nil
+	}
+
+	previousTargetID := plumbing.ZeroHash
+	if previousEntry != nil {
+		previousTargetID = previousEntry.TargetID
+	}
+
+	introduced.Commits, err = gitinterface.GetCommitsBetweenRangeOldestFirst(repo, entry.TargetID, previousTargetID)
+	if err != nil {
+		return nil, err
+	}
+
+	if previousTargetID.IsZero() {
+		return introduced, nil
+	}
+
+	previousTarget, err := repo.CommitObject(previousTargetID)
+	if err != nil {
+		return nil, err
+	}
+	knows, err := gitinterface.KnowsCommit(repo, entry.TargetID, previousTarget)
+	if err != nil {
+		return nil, err
+	}
+	if knows {
+		return introduced, nil
+	}
+
+	introduced.Rewritten = true
+	introduced.DroppedCommits, err = gitinterface.GetCommitsBetweenRangeOldestFirst(repo, previousTargetID, entry.TargetID)
+	if err != nil {
+		return nil, err
+	}
+
+	return introduced, nil
+}
+
 func parseRSLEntryText(id plumbing.Hash, text string) (Entry, error) {
 	text = strings.TrimSpace(text)
 	if strings.HasPrefix(text, AnnotationEntryHeader) {
@@ -638,7 +1403,21 @@ func parseReferenceEntryText(id plumbing.Hash, text string) (*ReferenceEntry, er
 		case RefKey:
 			entry.RefName = strings.TrimSpace(ls[1])
 		case TargetIDKey:
-			entry.TargetID = plumbing.NewHash(strings.TrimSpace(ls[1]))
+			targetID, err := gitinterface.ParseHash(strings.TrimSpace(ls[1]))
+			if err != nil {
+				return nil, errors.Join(ErrInvalidRSLEntry, err)
+			}
+			entry.TargetID = targetID
+		case MergeBaseKey:
+			mergeBase, err := gitinterface.ParseHash(strings.TrimSpace(ls[1]))
+			if err != nil {
+				return nil, errors.Join(ErrInvalidRSLEntry, err)
+			}
+			entry.MergeBase = mergeBase
+		case GittufVersionKey:
+			entry.GittufVersion = parseValue(l)
+		case SigningMethodKey:
+			entry.SigningMethod = parseValue(l)
 		}
 	}
 
@@ -675,19 +1454,39 @@ func parseAnnotationEntryText(id plumbing.Hash, text string) (*AnnotationEntry,
 
 		switch strings.TrimSpace(ls[0]) {
 		case EntryIDKey:
-			annotation.RSLEntryIDs = append(annotation.RSLEntryIDs, plumbing.NewHash(strings.TrimSpace(ls[1])))
+			entryID, err := gitinterface.ParseHash(strings.TrimSpace(ls[1]))
+			if err != nil {
+				return nil, errors.Join(ErrInvalidRSLEntry, err)
+			}
+			annotation.RSLEntryIDs = append(annotation.RSLEntryIDs, entryID)
 		case SkipKey:
 			if strings.TrimSpace(ls[1]) == "true" {
 				annotation.Skip = true
 			} else {
 				annotation.Skip = false
 			}
+		case ForcePushKey:
+			annotation.ForcePush = strings.TrimSpace(ls[1]) == "true"
+		case BreakGlassKey:
+			annotation.BreakGlass = strings.TrimSpace(ls[1]) == "true"
+		case GittufVersionKey:
+			annotation.GittufVersion = parseValue(l)
+		case SigningMethodKey:
+			annotation.SigningMethod = parseValue(l)
 		}
 	}
 
 	return annotation, nil
 }
 
+// parseValue returns the value of a "key: value" line in an entry's commit
+// message. Unlike the values of the other keys, free-form values such as
+// versions may themselves contain colons.
+func parseValue(line string) string {
+	_, value, _ := strings.Cut(line, ":")
+	return strings.TrimSpace(value)
+}
+
 func filterAnnotationsForRelevantAnnotations(allAnnotations []*AnnotationEntry, entryID plumbing.Hash) []*AnnotationEntry {
 	annotations := []*AnnotationEntry{}
 	for _, annotation := range allAnnotations {
@@ -3,20 +3,30 @@
 package rsl
 
 import (
+	"context"
 	"encoding/base64"
 	"fmt"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/gittuf/gittuf/internal/gitinterface"
 	"github.com/gittuf/gittuf/internal/third_party/go-git"
 	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing/hash"
+	"github.com/gittuf/gittuf/internal/third_party/go-git/plumbing/object"
 	"github.com/gittuf/gittuf/internal/third_party/go-git/storage/memory"
+	"github.com/gittuf/gittuf/internal/version"
 	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/jonboulle/clockwork"
 	"github.com/stretchr/testify/assert"
 )
 
 const annotationMessage = "test annotation"
 
+var testCtx = context.Background()
+
 func TestInitializeNamespace(t *testing.T) {
 	t.Run("clean repository", func(t *testing.T) {
 		repo, err := git.Init(memory.NewStorage(), memfs.New())
@@ -102,6 +112,234 @@ func TestNewReferenceEntry(t *testing.T) {
 	expectedMessage = fmt.Sprintf("%s\n\n%s: %s\n%s: %s", ReferenceEntryHeader, RefKey, "main", TargetIDKey, plumbing.NewHash("abcdef1234567890"))
 	assert.Equal(t, expectedMessage, commitObj.Message)
 	assert.Contains(t, commitObj.ParentHashes, originalRefHash)
+
+	// Use a dedicated committer identity for the entry
+	if err := NewReferenceEntry("main", plumbing.ZeroHash).Commit(repo, false, gitinterface.WithCommitter("gittuf", "gittuf@example.com")); err != nil {
+		t.Error(err)
+	}
+
+	ref, err = repo.Reference(plumbing.ReferenceName(Ref), true)
+	if err != nil {
+		t.Error(err)
+	}
+
+	commitObj, err = repo.CommitObject(ref.Hash())
+	if err != nil {
+		t.Error(err)
+	}
+	assert.Equal(t, "gittuf", commitObj.Committer.Name)
+	assert.Equal(t, "gittuf@example.com", commitObj.Committer.Email)
+
+	// Use a fixed clock for the entry
+	fixedClock := clockwork.NewFakeClockAt(time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC))
+	if err := NewReferenceEntry("main", plumbing.ZeroHash).Commit(repo, false, gitinterface.WithClock(fixedClock)); err != nil {
+		t.Error(err)
+	}
+
+	ref, err = repo.Reference(plumbing.ReferenceName(Ref), true)
+	if err != nil {
+		t.Error(err)
+	}
+
+	commitObj, err = repo.CommitObject(ref.Hash())
+	if err != nil {
+		t.Error(err)
+	}
+	assert.True(t, fixedClock.Now().Equal(commitObj.Committer.When))
+}
+
+func TestNewDeletionEntry(t *testing.T) {
+	entry := NewDeletionEntry("refs/heads/main")
+	assert.Equal(t, "refs/heads/main", entry.RefName)
+	assert.True(t, entry.IsDeletion())
+
+	entry = NewReferenceEntry("refs/heads/main", plumbing.NewHash("abcdef1234567890"))
+	assert.False(t, entry.IsDeletion())
+}
+
+func TestReferenceEntrySetMergeBase(t *testing.T) {
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := InitializeNamespace(repo); err != nil {
+		t.Fatal(err)
+	}
+
+	refName := "refs/heads/main"
+	anotherRefName := "refs/heads/feature"
+	emptyTreeHash, err := gitinterface.WriteTree(repo, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	baseCommitID, err := gitinterface.Commit(repo, emptyTreeHash, refName, "Base commit", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// No earlier entry for the ref
+	entry := NewReferenceEntry(refName, baseCommitID)
+	assert.Nil(t, entry.SetMergeBase(repo))
+	assert.Equal(t, plumbing.ZeroHash, entry.MergeBase)
+	if err := entry.Commit(repo, false); err != nil {
+		t.Fatal(err)
+	}
+
+	// Fast forward
+	commitID, err := gitinterface.Commit(repo, emptyTreeHash, refName, "Second commit", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry = NewReferenceEntry(refName, commitID)
+	assert.Nil(t, entry.SetMergeBase(repo))
+	assert.Equal(t, baseCommitID, entry.MergeBase)
+	if err := entry.Commit(repo, false); err != nil {
+		t.Fatal(err)
+	}
+
+	latestEntry, err := GetLatestEntry(repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, baseCommitID, latestEntry.(*ReferenceEntry).MergeBase)
+
+	// Diverged history
+	if err := repo.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(anotherRefName), baseCommitID)); err != nil {
+		t.Fatal(err)
+	}
+	divergedCommitID, err := gitinterface.Commit(repo, emptyTreeHash, anotherRefName, "Diverged commit", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry = NewReferenceEntry(refName, divergedCommitID)
+	assert.Nil(t, entry.SetMergeBase(repo))
+	assert.Equal(t, baseCommitID, entry.MergeBase)
+
+	// Target is not available locally
+	entry = NewReferenceEntry(refName, plumbing.NewHash("abcdef12345678900987654321fedcbaabcdef12"))
+	assert.Nil(t, entry.SetMergeBase(repo))
+	assert.Equal(t, plumbing.ZeroHash, entry.MergeBase)
+}
+
+func TestReferenceEntryCreateCommitObject(t *testing.T) {
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := InitializeNamespace(repo); err != nil {
+		t.Fatal(err)
+	}
+
+	refName := "refs/heads/main"
+	initialTip, err := gitinterface.GetTip(repo, Ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entry := NewReferenceEntry(refName, plumbing.ZeroHash)
+	err = entry.CreateCommitObject(repo, false)
+	assert.Nil(t, err)
+
+	// The RSL is not updated until the entry is recorded
+	tip, err := gitinterface.GetTip(repo, Ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, initialTip, tip)
+
+	createdEntry, err := GetEntry(repo, entry.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, refName, createdEntry.(*ReferenceEntry).RefName)
+
+	err = entry.RecordWhileLocked(repo)
+	assert.Nil(t, err)
+
+	latestEntry, err := GetLatestEntry(repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, entry.ID, latestEntry.GetID())
+
+	// The entry is not recorded if the RSL has moved since it was created
+	staleEntry := NewReferenceEntry(refName, plumbing.ZeroHash)
+	if err := staleEntry.CreateCommitObject(repo, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := NewReferenceEntry("refs/heads/feature", plumbing.ZeroHash).Commit(repo, false); err != nil {
+		t.Fatal(err)
+	}
+	rslTip, err := gitinterface.GetTip(repo, Ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = staleEntry.RecordWhileLocked(repo)
+	assert.NotNil(t, err)
+
+	tip, err = gitinterface.GetTip(repo, Ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, rslTip, tip)
+}
+
+func TestConcurrentReferenceEntryCommits(t *testing.T) {
+	tmpDir := t.TempDir()
+	repo, err := git.PlainInit(tmpDir, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := InitializeNamespace(repo); err != nil {
+		t.Fatal(err)
+	}
+
+	numWriters := 2
+	numEntries := 10
+
+	// Each writer uses its own handle for the repository, like separate
+	// gittuf processes would
+	var wg sync.WaitGroup
+	errs := make(chan error, numWriters*numEntries)
+	for i := 0; i < numWriters; i++ {
+		writerRepo, err := git.PlainOpen(tmpDir)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		wg.Add(1)
+		go func(writer int) {
+			defer wg.Done()
+
+			refName := fmt.Sprintf("refs/heads/writer-%d", writer)
+			for j := 0; j < numEntries; j++ {
+				errs <- NewReferenceEntry(refName, plumbing.ZeroHash).Commit(writerRepo, false)
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		assert.Nil(t, err)
+	}
+
+	// All entries must be in a single linear RSL
+	entry, err := GetLatestEntry(repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	count := 1
+	for {
+		entry, err = GetParentForEntry(repo, entry)
+		if err != nil {
+			assert.ErrorIs(t, err, ErrRSLEntryNotFound)
+			break
+		}
+		count++
+	}
+	assert.Equal(t, numWriters*numEntries, count)
 }
 
 func TestGetLatestEntry(t *testing.T) {
@@ -157,6 +395,282 @@ func TestGetLatestEntry(t *testing.T) {
 	}
 }
 
+func TestAmendLatestEntry(t *testing.T) {
+	remoteName := "origin"
+
+	t.Run("unpushed entry", func(t *testing.T) {
+		repo, err := git.Init(memory.NewStorage(), memfs.New())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := InitializeNamespace(repo); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := NewReferenceEntry("refs/heads/main", plumbing.ZeroHash).Commit(repo, false); err != nil {
+			t.Fatal(err)
+		}
+		firstEntry, err := GetLatestEntry(repo)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := NewReferenceEntry("refs/heads/feature", plumbing.ZeroHash).Commit(repo, false); err != nil {
+			t.Fatal(err)
+		}
+
+		// The remote has only seen the first entry
+		if err := repo.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(RemoteTrackerRef(remoteName)), firstEntry.GetID())); err != nil {
+			t.Fatal(err)
+		}
+
+		err = AmendLatestEntry(repo, remoteName, NewReferenceEntry("refs/heads/fix", plumbing.NewHash("abcdef1234567890")), false)
+		assert.Nil(t, err)
+
+		latestEntry, err := GetLatestEntry(repo)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, "refs/heads/fix", latestEntry.(*ReferenceEntry).RefName)
+		assert.Equal(t, plumbing.NewHash("abcdef1234567890"), latestEntry.(*ReferenceEntry).TargetID)
+
+		parentEntry, err := GetParentForEntry(repo, latestEntry)
+		assert.Nil(t, err)
+		assert.Equal(t, firstEntry.GetID(), parentEntry.GetID())
+	})
+
+	t.Run("only entry with no remote tracker", func(t *testing.T) {
+		repo, err := git.Init(memory.NewStorage(), memfs.New())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := InitializeNamespace(repo); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := NewReferenceEntry("refs/heads/main", plumbing.ZeroHash).Commit(repo, false); err != nil {
+			t.Fatal(err)
+		}
+
+		err = AmendLatestEntry(repo, remoteName, NewReferenceEntry("refs/heads/fix", plumbing.ZeroHash), false)
+		assert.Nil(t, err)
+
+		latestEntry, err := GetLatestEntry(repo)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, "refs/heads/fix", latestEntry.(*ReferenceEntry).RefName)
+
+		_, err = GetParentForEntry(repo, latestEntry)
+		assert.ErrorIs(t, err, ErrRSLEntryNotFound)
+	})
+
+	t.Run("pushed entry", func(t *testing.T) {
+		repo, err := git.Init(memory.NewStorage(), memfs.New())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := InitializeNamespace(repo); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := NewReferenceEntry("refs/heads/main", plumbing.ZeroHash).Commit(repo, false); err != nil {
+			t.Fatal(err)
+		}
+		latestEntry, err := GetLatestEntry(repo)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := repo.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(RemoteTrackerRef(remoteName)), latestEntry.GetID())); err != nil {
+			t.Fatal(err)
+		}
+
+		err = AmendLatestEntry(repo, remoteName, NewReferenceEntry("refs/heads/fix", plumbing.ZeroHash), false)
+		assert.ErrorIs(t, err, ErrCannotAmendPushedEntry)
+
+		// The RSL is unchanged
+		currentEntry, err := GetLatestEntry(repo)
+		assert.Nil(t, err)
+		assert.Equal(t, latestEntry.GetID(), currentEntry.GetID())
+	})
+
+	t.Run("empty RSL", func(t *testing.T) {
+		repo, err := git.Init(memory.NewStorage(), memfs.New())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := InitializeNamespace(repo); err != nil {
+			t.Fatal(err)
+		}
+
+		err = AmendLatestEntry(repo, remoteName, NewReferenceEntry("refs/heads/fix", plumbing.ZeroHash), false)
+		assert.ErrorIs(t, err, ErrRSLEntryNotFound)
+	})
+}
+
+func TestReconcile(t *testing.T) {
+	remoteName := "origin"
+
+	// createForks records an entry for main that both RSLs share, and then
+	// records the remote's entries and the local entries after it. The remote's
+	// entries are recorded in the RSL tracker.
+	createForks := func(t *testing.T, remoteEntries, localEntries []Entry) (*git.Repository, plumbing.Hash, plumbing.Hash) {
+		t.Helper()
+
+		repo, err := git.Init(memory.NewStorage(), memfs.New())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := InitializeNamespace(repo); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := NewReferenceEntry("refs/heads/main", plumbing.NewHash("abcdef1234567890")).Commit(repo, false); err != nil {
+			t.Fatal(err)
+		}
+		baseEntry, err := GetLatestEntry(repo)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, entry := range remoteEntries {
+			if err := entry.Commit(repo, false); err != nil {
+				t.Fatal(err)
+			}
+		}
+		remoteTip, err := gitinterface.GetTip(repo, Ref)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := repo.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(RemoteTrackerRef(remoteName)), remoteTip)); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := repo.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(Ref), baseEntry.GetID())); err != nil {
+			t.Fatal(err)
+		}
+		for _, entry := range localEntries {
+			if err := entry.Commit(repo, false); err != nil {
+				t.Fatal(err)
+			}
+		}
+		localTip, err := gitinterface.GetTip(repo, Ref)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return repo, baseEntry.GetID(), localTip
+	}
+
+	t.Run("forks for different refs", func(t *testing.T) {
+		repo, baseID, _ := createForks(t,
+			[]Entry{NewReferenceEntry("refs/heads/feature", plumbing.NewHash("1111111111111111"))},
+			[]Entry{NewReferenceEntry("refs/heads/fix", plumbing.NewHash("2222222222222222"))},
+		)
+
+		// The local annotation refers to the local entry, which is re-created
+		localEntry, err := GetLatestEntry(repo)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := NewAnnotationEntry([]plumbing.Hash{localEntry.GetID()}, true, annotationMessage).Commit(repo, false); err != nil {
+			t.Fatal(err)
+		}
+		remoteTip, err := gitinterface.GetTip(repo, RemoteTrackerRef(remoteName))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = Reconcile(testCtx, repo, remoteName, false)
+		assert.Nil(t, err)
+
+		entries, err := GetEntriesSince(repo, baseID, mustGetTip(t, repo, Ref))
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, 3, len(entries))
+
+		// The remote's entry is kept as is
+		assert.Equal(t, remoteTip, entries[0].GetID())
+
+		fixEntry := entries[1].(*ReferenceEntry)
+		assert.Equal(t, "refs/heads/fix", fixEntry.RefName)
+		assert.Equal(t, plumbing.NewHash("2222222222222222"), fixEntry.TargetID)
+		assert.NotEqual(t, localEntry.GetID(), fixEntry.ID)
+
+		annotation := entries[2].(*AnnotationEntry)
+		assert.Equal(t, []plumbing.Hash{fixEntry.ID}, annotation.RSLEntryIDs)
+		assert.True(t, annotation.Skip)
+		assert.Equal(t, annotationMessage, annotation.Message)
+	})
+
+	t.Run("forks for the same ref", func(t *testing.T) {
+		repo, _, localTip := createForks(t,
+			[]Entry{NewReferenceEntry("refs/heads/main", plumbing.NewHash("1111111111111111"))},
+			[]Entry{NewReferenceEntry("refs/heads/fix", plumbing.NewHash("2222222222222222")), NewReferenceEntry("refs/heads/main", plumbing.NewHash("3333333333333333"))},
+		)
+
+		err := Reconcile(testCtx, repo, remoteName, false)
+		assert.ErrorIs(t, err, ErrRSLForksConflict)
+		assert.Contains(t, err.Error(), "refs/heads/main")
+		assert.NotContains(t, err.Error(), "refs/heads/fix")
+
+		// The local RSL is left as is
+		assert.Equal(t, localTip, mustGetTip(t, repo, Ref))
+	})
+
+	t.Run("annotation for an entry of a ref updated by the remote", func(t *testing.T) {
+		repo, baseID, _ := createForks(t,
+			[]Entry{NewReferenceEntry("refs/heads/main", plumbing.NewHash("1111111111111111"))},
+			nil,
+		)
+		if err := NewAnnotationEntry([]plumbing.Hash{baseID}, true, annotationMessage).Commit(repo, false); err != nil {
+			t.Fatal(err)
+		}
+
+		err := Reconcile(testCtx, repo, remoteName, false)
+		assert.ErrorIs(t, err, ErrRSLForksConflict)
+	})
+
+	t.Run("local RSL is behind", func(t *testing.T) {
+		repo, _, _ := createForks(t,
+			[]Entry{NewReferenceEntry("refs/heads/feature", plumbing.NewHash("1111111111111111"))},
+			nil,
+		)
+
+		err := Reconcile(testCtx, repo, remoteName, false)
+		assert.Nil(t, err)
+		assert.Equal(t, mustGetTip(t, repo, RemoteTrackerRef(remoteName)), mustGetTip(t, repo, Ref))
+	})
+
+	t.Run("local RSL is ahead", func(t *testing.T) {
+		repo, _, localTip := createForks(t,
+			nil,
+			[]Entry{NewReferenceEntry("refs/heads/fix", plumbing.NewHash("2222222222222222"))},
+		)
+
+		err := Reconcile(testCtx, repo, remoteName, false)
+		assert.Nil(t, err)
+		assert.Equal(t, localTip, mustGetTip(t, repo, Ref))
+	})
+}
+
+func mustGetTip(t *testing.T, repo *git.Repository, refName string) plumbing.Hash {
+	t.Helper()
+
+	tip, err := gitinterface.GetTip(repo, refName)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return tip
+}
+
 func TestGetLatestNonGittufReferenceEntry(t *testing.T) {
 	t.Run("mix of gittuf and non gittuf entries", func(t *testing.T) {
 		repo, err := git.Init(memory.NewStorage(), memfs.New())
@@ -656,13 +1170,170 @@ func TestGetFirstEntry(t *testing.T) {
 		if err := NewAnnotationEntry([]plumbing.Hash{firstEntry.ID}, false, annotationMessage).Commit(repo, false); err != nil {
 			t.Fatal(err)
 		}
-	}
+	}
+
+	testEntry, annotations, err = GetFirstEntry(repo)
+	assert.Nil(t, err)
+	assert.Equal(t, firstEntry, testEntry)
+	assert.Equal(t, 5, len(annotations))
+	assertAnnotationsReferToEntry(t, firstEntry, annotations)
+}
+
+func TestVerifyChainIntegrity(t *testing.T) {
+	createRepository := func(t *testing.T) *git.Repository {
+		t.Helper()
+
+		repo, err := git.Init(memory.NewStorage(), memfs.New())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := InitializeNamespace(repo); err != nil {
+			t.Fatal(err)
+		}
+
+		return repo
+	}
+
+	// writeEntryCommit writes a commit for the entry with the specified tree
+	// and parents, bypassing the checks made when entries are committed, and
+	// sets the RSL's tip to the commit
+	writeEntryCommit := func(t *testing.T, repo *git.Repository, entry Entry, treeID plumbing.Hash, parentIDs ...plumbing.Hash) plumbing.Hash {
+		t.Helper()
+
+		message, err := entry.createCommitMessage()
+		if err != nil {
+			t.Fatal(err)
+		}
+		signature := object.Signature{Name: "Jane Doe", Email: "jane.doe@example.com", When: time.Unix(0, 0)}
+		commit := &object.Commit{
+			Author:       signature,
+			Committer:    signature,
+			Message:      message,
+			TreeHash:     treeID,
+			ParentHashes: parentIDs,
+		}
+
+		obj := repo.Storer.NewEncodedObject()
+		if err := commit.Encode(obj); err != nil {
+			t.Fatal(err)
+		}
+		commitID, err := repo.Storer.SetEncodedObject(obj)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := repo.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(Ref), commitID)); err != nil {
+			t.Fatal(err)
+		}
+
+		return commitID
+	}
+
+	t.Run("empty RSL", func(t *testing.T) {
+		repo := createRepository(t)
+
+		err := VerifyChainIntegrity(repo)
+		assert.Nil(t, err)
+	})
+
+	t.Run("intact chain", func(t *testing.T) {
+		repo := createRepository(t)
+
+		if err := NewReferenceEntry("refs/heads/main", plumbing.ZeroHash).Commit(repo, false); err != nil {
+			t.Fatal(err)
+		}
+		firstEntry, err := GetLatestEntry(repo)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := NewAnnotationEntry([]plumbing.Hash{firstEntry.GetID()}, false, annotationMessage).Commit(repo, false); err != nil {
+			t.Fatal(err)
+		}
+		if err := NewReferenceEntry("refs/heads/feature", plumbing.ZeroHash).Commit(repo, false); err != nil {
+			t.Fatal(err)
+		}
+
+		err = VerifyChainIntegrity(repo)
+		assert.Nil(t, err)
+	})
+
+	t.Run("parent is not an RSL entry", func(t *testing.T) {
+		repo := createRepository(t)
+
+		blobID, err := gitinterface.WriteBlob(repo, []byte("file contents"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		treeID, err := gitinterface.WriteTree(repo, []object.TreeEntry{{Name: "file", Hash: blobID}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		nonRSLCommitID, err := gitinterface.Commit(repo, treeID, "refs/heads/main", "Not an RSL entry", false)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		writeEntryCommit(t, repo, NewReferenceEntry("refs/heads/main", nonRSLCommitID), gitinterface.EmptyTree(), nonRSLCommitID)
+
+		err = VerifyChainIntegrity(repo)
+		assert.ErrorIs(t, err, ErrRSLChainBroken)
+		assert.Contains(t, err.Error(), nonRSLCommitID.String())
+	})
+
+	t.Run("parent is missing", func(t *testing.T) {
+		repo := createRepository(t)
+
+		missingID := plumbing.NewHash(strings.Repeat("ab", hash.Size))
+		entryID := writeEntryCommit(t, repo, NewReferenceEntry("refs/heads/main", plumbing.ZeroHash), gitinterface.EmptyTree(), missingID)
+
+		err := VerifyChainIntegrity(repo)
+		assert.ErrorIs(t, err, ErrRSLChainBroken)
+		assert.Contains(t, err.Error(), missingID.String())
+		assert.Contains(t, err.Error(), entryID.String())
+	})
+
+	t.Run("entry has multiple parents", func(t *testing.T) {
+		repo := createRepository(t)
+
+		firstID := writeEntryCommit(t, repo, NewReferenceEntry("refs/heads/main", plumbing.ZeroHash), gitinterface.EmptyTree())
+		secondID := writeEntryCommit(t, repo, NewReferenceEntry("refs/heads/feature", plumbing.ZeroHash), gitinterface.EmptyTree())
+		writeEntryCommit(t, repo, NewReferenceEntry("refs/heads/main", plumbing.ZeroHash), gitinterface.EmptyTree(), firstID, secondID)
+
+		err := VerifyChainIntegrity(repo)
+		assert.ErrorIs(t, err, ErrRSLChainBroken)
+		assert.ErrorIs(t, err, ErrRSLBranchDetected)
+	})
+
+	t.Run("first entry is an annotation", func(t *testing.T) {
+		repo := createRepository(t)
+
+		annotationID := writeEntryCommit(t, repo, NewAnnotationEntry([]plumbing.Hash{gitinterface.EmptyTree()}, false, annotationMessage), gitinterface.EmptyTree())
+		writeEntryCommit(t, repo, NewReferenceEntry("refs/heads/main", plumbing.ZeroHash), gitinterface.EmptyTree(), annotationID)
+
+		err := VerifyChainIntegrity(repo)
+		assert.ErrorIs(t, err, ErrRSLChainBroken)
+		assert.Contains(t, err.Error(), annotationID.String())
+	})
+
+	t.Run("break is reported closest to the tip", func(t *testing.T) {
+		repo := createRepository(t)
+
+		missingID := plumbing.NewHash(strings.Repeat("ab", hash.Size))
+		writeEntryCommit(t, repo, NewReferenceEntry("refs/heads/main", plumbing.ZeroHash), gitinterface.EmptyTree(), missingID)
+		if err := NewReferenceEntry("refs/heads/feature", plumbing.ZeroHash).Commit(repo, false); err != nil {
+			t.Fatal(err)
+		}
+		nonRSLCommitID, err := gitinterface.Commit(repo, gitinterface.EmptyTree(), "refs/heads/main", "Not an RSL entry", false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		writeEntryCommit(t, repo, NewReferenceEntry("refs/heads/main", nonRSLCommitID), gitinterface.EmptyTree(), nonRSLCommitID)
 
-	testEntry, annotations, err = GetFirstEntry(repo)
-	assert.Nil(t, err)
-	assert.Equal(t, firstEntry, testEntry)
-	assert.Equal(t, 5, len(annotations))
-	assertAnnotationsReferToEntry(t, firstEntry, annotations)
+		err = VerifyChainIntegrity(repo)
+		assert.ErrorIs(t, err, ErrRSLChainBroken)
+		assert.Contains(t, err.Error(), nonRSLCommitID.String())
+		assert.NotContains(t, err.Error(), missingID.String())
+	})
 }
 
 func TestGetFirstReferenceEntryForCommit(t *testing.T) {
@@ -828,6 +1499,30 @@ func TestGetFirstReferenceEntryForCommit(t *testing.T) {
 		assert.Equal(t, latestEntryT, entry)
 		assertAnnotationsReferToEntry(t, latestEntry, annotations)
 	}
+
+	// Tag the latest feature commit and delete the feature branch. These
+	// entries don't point a ref to a commit, so they're skipped.
+	tagID, err := gitinterface.Tag(repo, featureTargetIDs[len(featureTargetIDs)-1], "v1", "v1", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := NewReferenceEntry(string(plumbing.NewTagReferenceName("v1")), tagID).Commit(repo, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := NewReferenceEntry(featureRef, plumbing.ZeroHash).Commit(repo, false); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, commitID := range featureTargetIDs {
+		commit, err := repo.CommitObject(commitID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		entry, annotations, err := GetFirstReferenceEntryForCommit(repo, commit)
+		assert.Nil(t, err)
+		assert.Equal(t, latestEntryT, entry)
+		assertAnnotationsReferToEntry(t, latestEntry, annotations)
+	}
 }
 
 func TestGetReferenceEntriesInRange(t *testing.T) {
@@ -1042,6 +1737,259 @@ func TestGetReferenceEntriesInRangeForRef(t *testing.T) {
 	assert.Equal(t, expectedAnnotationMap, annotationMap)
 }
 
+func TestGetReferenceEntriesForRef(t *testing.T) {
+	refName := "refs/heads/main"
+	anotherRefName := "refs/heads/feature"
+
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := InitializeNamespace(repo); err != nil {
+		t.Fatal(err)
+	}
+
+	expectedEntries := []*ReferenceEntry{}
+	expectedAnnotationMap := map[plumbing.Hash][]*AnnotationEntry{}
+
+	// Interleave entries for main and feature
+	for i := 0; i < 3; i++ {
+		if err := NewReferenceEntry(refName, plumbing.ZeroHash).Commit(repo, false); err != nil {
+			t.Fatal(err)
+		}
+
+		entry, err := GetLatestEntry(repo)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expectedEntries = append(expectedEntries, entry.(*ReferenceEntry))
+
+		if err := NewReferenceEntry(anotherRefName, plumbing.ZeroHash).Commit(repo, false); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	entries, annotationMap, err := GetReferenceEntriesForRef(repo, refName)
+	assert.Nil(t, err)
+	assert.Equal(t, expectedEntries, entries)
+	assert.Equal(t, expectedAnnotationMap, annotationMap)
+
+	// Add an annotation for the first entry
+	if err := NewAnnotationEntry([]plumbing.Hash{expectedEntries[0].ID}, false, annotationMessage).Commit(repo, false); err != nil {
+		t.Fatal(err)
+	}
+	annotation, err := GetLatestEntry(repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedAnnotationMap[expectedEntries[0].ID] = []*AnnotationEntry{annotation.(*AnnotationEntry)}
+
+	entries, annotationMap, err = GetReferenceEntriesForRef(repo, refName)
+	assert.Nil(t, err)
+	assert.Equal(t, expectedEntries, entries)
+	assert.Equal(t, expectedAnnotationMap, annotationMap)
+
+	// No entries for an unknown ref
+	_, _, err = GetReferenceEntriesForRef(repo, "refs/heads/unknown")
+	assert.ErrorIs(t, err, ErrRSLEntryNotFound)
+}
+
+func TestGetEntriesSince(t *testing.T) {
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := InitializeNamespace(repo); err != nil {
+		t.Fatal(err)
+	}
+
+	entryIDs := []plumbing.Hash{}
+	for i := 0; i < 3; i++ {
+		if err := NewReferenceEntry("main", plumbing.ZeroHash).Commit(repo, false); err != nil {
+			t.Fatal(err)
+		}
+
+		entry, err := GetLatestEntry(repo)
+		if err != nil {
+			t.Fatal(err)
+		}
+		entryIDs = append(entryIDs, entry.GetID())
+	}
+
+	getIDs := func(entries []Entry) []plumbing.Hash {
+		ids := []plumbing.Hash{}
+		for _, entry := range entries {
+			ids = append(ids, entry.GetID())
+		}
+		return ids
+	}
+
+	entries, err := GetEntriesSince(repo, plumbing.ZeroHash, entryIDs[2])
+	assert.Nil(t, err)
+	assert.Equal(t, entryIDs, getIDs(entries))
+
+	entries, err = GetEntriesSince(repo, entryIDs[0], entryIDs[2])
+	assert.Nil(t, err)
+	assert.Equal(t, entryIDs[1:], getIDs(entries))
+
+	entries, err = GetEntriesSince(repo, entryIDs[2], entryIDs[2])
+	assert.Nil(t, err)
+	assert.Empty(t, entries)
+
+	_, err = GetEntriesSince(repo, entryIDs[2], entryIDs[1])
+	assert.ErrorIs(t, err, ErrRSLEntryNotInChain)
+}
+
+func BenchmarkGetEntriesSince(b *testing.B) {
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	if err := InitializeNamespace(repo); err != nil {
+		b.Fatal(err)
+	}
+
+	numEntries := 10000
+	entryIDs := make([]plumbing.Hash, 0, numEntries)
+	for i := 0; i < numEntries; i++ {
+		if err := NewReferenceEntry("main", plumbing.ZeroHash).Commit(repo, false); err != nil {
+			b.Fatal(err)
+		}
+
+		entry, err := GetLatestEntry(repo)
+		if err != nil {
+			b.Fatal(err)
+		}
+		entryIDs = append(entryIDs, entry.GetID())
+	}
+	tipID := entryIDs[numEntries-1]
+
+	b.Run("incremental", func(b *testing.B) {
+		baseID := entryIDs[numEntries-11]
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if _, err := GetEntriesSince(repo, baseID, tipID); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("full", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := GetEntriesSince(repo, plumbing.ZeroHash, tipID); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func TestCommitsIntroducedByEntry(t *testing.T) {
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := InitializeNamespace(repo); err != nil {
+		t.Fatal(err)
+	}
+
+	refName := "refs/heads/main"
+	anotherRefName := "refs/heads/feature"
+	emptyTreeHash, err := gitinterface.WriteTree(repo, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	commit := func(refName, message string) plumbing.Hash {
+		t.Helper()
+
+		commitID, err := gitinterface.Commit(repo, emptyTreeHash, refName, message, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return commitID
+	}
+
+	record := func(entry *ReferenceEntry) *ReferenceEntry {
+		t.Helper()
+
+		if err := entry.Commit(repo, false); err != nil {
+			t.Fatal(err)
+		}
+		latestEntry, err := GetLatestEntry(repo)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return latestEntry.(*ReferenceEntry)
+	}
+
+	commitIDs := func(commits []*object.Commit) []plumbing.Hash {
+		ids := []plumbing.Hash{}
+		for _, commit := range commits {
+			ids = append(ids, commit.Hash)
+		}
+		return ids
+	}
+
+	// First entry for the ref introduces all of its commits
+	firstCommitID := commit(refName, "First commit")
+	secondCommitID := commit(refName, "Second commit")
+	firstEntry := record(NewReferenceEntry(refName, secondCommitID))
+
+	introduced, err := CommitsIntroducedByEntry(repo, firstEntry)
+	assert.Nil(t, err)
+	assert.Nil(t, introduced.PreviousEntry)
+	assert.False(t, introduced.Rewritten)
+	assert.Equal(t, []plumbing.Hash{firstCommitID, secondCommitID}, commitIDs(introduced.Commits))
+
+	// Entries for other refs are ignored
+	if err := repo.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(anotherRefName), firstCommitID)); err != nil {
+		t.Fatal(err)
+	}
+	divergedCommitID := commit(anotherRefName, "Diverged commit")
+	record(NewReferenceEntry(anotherRefName, divergedCommitID))
+
+	// Linear history
+	thirdCommitID := commit(refName, "Third commit")
+	fourthCommitID := commit(refName, "Fourth commit")
+	linearEntry := record(NewReferenceEntry(refName, fourthCommitID))
+
+	introduced, err = CommitsIntroducedByEntry(repo, linearEntry)
+	assert.Nil(t, err)
+	assert.Equal(t, firstEntry.ID, introduced.PreviousEntry.ID)
+	assert.False(t, introduced.Rewritten)
+	assert.Empty(t, introduced.DroppedCommits)
+	assert.Equal(t, []plumbing.Hash{thirdCommitID, fourthCommitID}, commitIDs(introduced.Commits))
+
+	// Non-linear history, main is force pushed to the diverged commit
+	forcePushEntry := record(NewReferenceEntry(refName, divergedCommitID))
+
+	introduced, err = CommitsIntroducedByEntry(repo, forcePushEntry)
+	assert.Nil(t, err)
+	assert.Equal(t, linearEntry.ID, introduced.PreviousEntry.ID)
+	assert.True(t, introduced.Rewritten)
+	assert.Equal(t, []plumbing.Hash{divergedCommitID}, commitIDs(introduced.Commits))
+	assert.Equal(t, []plumbing.Hash{secondCommitID, thirdCommitID, fourthCommitID}, commitIDs(introduced.DroppedCommits))
+
+	// Deletion introduces no commits
+	deletionEntry := record(NewDeletionEntry(refName))
+
+	introduced, err = CommitsIntroducedByEntry(repo, deletionEntry)
+	assert.Nil(t, err)
+	assert.Equal(t, forcePushEntry.ID, introduced.PreviousEntry.ID)
+	assert.Empty(t, introduced.Commits)
+
+	// Recreating the ref introduces all of its commits
+	recreateEntry := record(NewReferenceEntry(refName, secondCommitID))
+
+	introduced, err = CommitsIntroducedByEntry(repo, recreateEntry)
+	assert.Nil(t, err)
+	assert.Equal(t, deletionEntry.ID, introduced.PreviousEntry.ID)
+	assert.False(t, introduced.Rewritten)
+	assert.Equal(t, []plumbing.Hash{firstCommitID, secondCommitID}, commitIDs(introduced.Commits))
+}
+
 func TestAnnotationEntryRefersTo(t *testing.T) {
 	// We use these as stand-ins for actual RSL IDs that have the same data type
 	emptyBlobID := gitinterface.EmptyBlob()
@@ -1080,6 +2028,54 @@ func TestAnnotationEntryRefersTo(t *testing.T) {
 	}
 }
 
+func TestEntryProvenance(t *testing.T) {
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := InitializeNamespace(repo); err != nil {
+		t.Fatal(err)
+	}
+
+	entry := NewReferenceEntry("refs/heads/main", plumbing.ZeroHash)
+	SetProvenance(entry, false)
+	assert.Equal(t, version.GetVersion(), entry.GittufVersion)
+	assert.Empty(t, entry.SigningMethod)
+
+	entry.SigningMethod = "ssh"
+	if err := entry.Commit(repo, false); err != nil {
+		t.Fatal(err)
+	}
+
+	latestEntry, err := GetLatestEntry(repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	referenceEntry := latestEntry.(*ReferenceEntry)
+	assert.Equal(t, version.GetVersion(), referenceEntry.GittufVersion)
+	assert.Equal(t, "ssh", referenceEntry.SigningMethod)
+
+	annotation := NewAnnotationEntry([]plumbing.Hash{referenceEntry.ID}, false, annotationMessage)
+	SetProvenance(annotation, false)
+	if err := annotation.Commit(repo, false); err != nil {
+		t.Fatal(err)
+	}
+
+	latestEntry, err = GetLatestEntry(repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	annotationEntry := latestEntry.(*AnnotationEntry)
+	assert.Equal(t, version.GetVersion(), annotationEntry.GittufVersion)
+	assert.Empty(t, annotationEntry.SigningMethod)
+	assert.Equal(t, annotationMessage, annotationEntry.Message)
+
+	// Entries without provenance are unchanged
+	entry = NewReferenceEntry("refs/heads/main", plumbing.ZeroHash)
+	message, _ := entry.createCommitMessage()
+	assert.NotContains(t, message, GittufVersionKey)
+}
+
 func TestReferenceEntryCreateCommitMessage(t *testing.T) {
 	tests := map[string]struct {
 		entry           *ReferenceEntry
@@ -1099,6 +2095,23 @@ func TestReferenceEntryCreateCommitMessage(t *testing.T) {
 			},
 			expectedMessage: fmt.Sprintf("%s\n\n%s: %s\n%s: %s", ReferenceEntryHeader, RefKey, "refs/heads/main", TargetIDKey, "abcdef12345678900987654321fedcbaabcdef12"),
 		},
+		"entry, with merge base": {
+			entry: &ReferenceEntry{
+				RefName:   "refs/heads/main",
+				TargetID:  plumbing.NewHash("abcdef12345678900987654321fedcbaabcdef12"),
+				MergeBase: plumbing.NewHash("1234567890abcdef1234567890abcdef12345678"),
+			},
+			expectedMessage: fmt.Sprintf("%s\n\n%s: %s\n%s: %s\n%s: %s", ReferenceEntryHeader, RefKey, "refs/heads/main", TargetIDKey, "abcdef12345678900987654321fedcbaabcdef12", MergeBaseKey, "1234567890abcdef1234567890abcdef12345678"),
+		},
+		"entry, with provenance": {
+			entry: &ReferenceEntry{
+				RefName:       "refs/heads/main",
+				TargetID:      plumbing.NewHash("abcdef12345678900987654321fedcbaabcdef12"),
+				GittufVersion: "v0.5.0",
+				SigningMethod: "ssh",
+			},
+			expectedMessage: fmt.Sprintf("%s\n\n%s: %s\n%s: %s\n%s: %s\n%s: %s", ReferenceEntryHeader, RefKey, "refs/heads/main", TargetIDKey, "abcdef12345678900987654321fedcbaabcdef12", GittufVersionKey, "v0.5.0", SigningMethodKey, "ssh"),
+		},
 	}
 
 	for name, test := range tests {
@@ -1156,6 +2169,33 @@ func TestAnnotationEntryCreateCommitMessage(t *testing.T) {
 			},
 			expectedMessage: fmt.Sprintf("%s\n\n%s: %s\n%s: %s\n%s: %s", AnnotationEntryHeader, EntryIDKey, plumbing.ZeroHash.String(), EntryIDKey, plumbing.ZeroHash.String(), SkipKey, "false"),
 		},
+		"annotation, force push, with message": {
+			entry: &AnnotationEntry{
+				RSLEntryIDs: []plumbing.Hash{plumbing.ZeroHash},
+				Skip:        false,
+				ForcePush:   true,
+				Message:     "message",
+			},
+			expectedMessage: fmt.Sprintf("%s\n\n%s: %s\n%s: %s\n%s: %s\n%s\n%s\n%s", AnnotationEntryHeader, EntryIDKey, plumbing.ZeroHash.String(), SkipKey, "false", ForcePushKey, "true", BeginMessage, base64.StdEncoding.EncodeToString([]byte("message")), EndMessage),
+		},
+		"annotation, break glass, with message": {
+			entry: &AnnotationEntry{
+				RSLEntryIDs: []plumbing.Hash{plumbing.ZeroHash},
+				Skip:        false,
+				BreakGlass:  true,
+				Message:     "message",
+			},
+			expectedMessage: fmt.Sprintf("%s\n\n%s: %s\n%s: %s\n%s: %s\n%s\n%s\n%s", AnnotationEntryHeader, EntryIDKey, plumbing.ZeroHash.String(), SkipKey, "false", BreakGlassKey, "true", BeginMessage, base64.StdEncoding.EncodeToString([]byte("message")), EndMessage),
+		},
+		"annotation, with provenance, with message": {
+			entry: &AnnotationEntry{
+				RSLEntryIDs:   []plumbing.Hash{plumbing.ZeroHash},
+				Skip:          true,
+				GittufVersion: "v0.5.0",
+				Message:       "message",
+			},
+			expectedMessage: fmt.Sprintf("%s\n\n%s: %s\n%s: %s\n%s: %s\n%s\n%s\n%s", AnnotationEntryHeader, EntryIDKey, plumbing.ZeroHash.String(), SkipKey, "true", GittufVersionKey, "v0.5.0", BeginMessage, base64.StdEncoding.EncodeToString([]byte("message")), EndMessage),
+		},
 	}
 
 	for name, test := range tests {
@@ -1193,6 +2233,25 @@ func TestParseRSLEntryText(t *testing.T) {
 			},
 			message: fmt.Sprintf("%s\n\n%s: %s\n%s: %s", ReferenceEntryHeader, RefKey, "refs/heads/main", TargetIDKey, "abcdef12345678900987654321fedcbaabcdef12"),
 		},
+		"entry, with merge base": {
+			expectedEntry: &ReferenceEntry{
+				ID:        plumbing.ZeroHash,
+				RefName:   "refs/heads/main",
+				TargetID:  plumbing.NewHash("abcdef12345678900987654321fedcbaabcdef12"),
+				MergeBase: plumbing.NewHash("1234567890abcdef1234567890abcdef12345678"),
+			},
+			message: fmt.Sprintf("%s\n\n%s: %s\n%s: %s\n%s: %s", ReferenceEntryHeader, RefKey, "refs/heads/main", TargetIDKey, "abcdef12345678900987654321fedcbaabcdef12", MergeBaseKey, "1234567890abcdef1234567890abcdef12345678"),
+		},
+		"entry, with provenance": {
+			expectedEntry: &ReferenceEntry{
+				ID:            plumbing.ZeroHash,
+				RefName:       "refs/heads/main",
+				TargetID:      plumbing.NewHash("abcdef12345678900987654321fedcbaabcdef12"),
+				GittufVersion: "v0.5.1-0.20240601000000-abcdef123456",
+				SigningMethod: "gpg",
+			},
+			message: fmt.Sprintf("%s\n\n%s: %s\n%s: %s\n%s: %s\n%s: %s", ReferenceEntryHeader, RefKey, "refs/heads/main", TargetIDKey, "abcdef12345678900987654321fedcbaabcdef12", GittufVersionKey, "v0.5.1-0.20240601000000-abcdef123456", SigningMethodKey, "gpg"),
+		},
 		"entry, missing header": {
 			expectedError: ErrInvalidRSLEntry,
 			message:       fmt.Sprintf("%s: %s\n%s: %s", RefKey, "refs/heads/main", TargetIDKey, plumbing.ZeroHash.String()),
@@ -1201,6 +2260,15 @@ func TestParseRSLEntryText(t *testing.T) {
 			expectedError: ErrInvalidRSLEntry,
 			message:       fmt.Sprintf("%s\n\n%s: %s", ReferenceEntryHeader, RefKey, "refs/heads/main"),
 		},
+		"entry, truncated target ID": {
+			expectedError: gitinterface.ErrInvalidObjectID,
+			message:       fmt.Sprintf("%s\n\n%s: %s\n%s: %s", ReferenceEntryHeader, RefKey, "refs/heads/main", TargetIDKey, "abcdef12345678900987654321fedcba"),
+		},
+		"entry, target ID of other object format": {
+			// SHA-1 and SHA-256 IDs are 20 and 32 bytes wide respectively
+			expectedError: gitinterface.ErrInvalidObjectID,
+			message:       fmt.Sprintf("%s\n\n%s: %s\n%s: %s", ReferenceEntryHeader, RefKey, "refs/heads/main", TargetIDKey, strings.Repeat("ab", 52-hash.Size)),
+		},
 		"annotation, no message": {
 			expectedEntry: &AnnotationEntry{
 				ID:          plumbing.ZeroHash,
@@ -1246,10 +2314,45 @@ func TestParseRSLEntryText(t *testing.T) {
 			},
 			message: fmt.Sprintf("%s\n\n%s: %s\n%s: %s\n%s: %s", AnnotationEntryHeader, EntryIDKey, plumbing.ZeroHash.String(), EntryIDKey, plumbing.ZeroHash.String(), SkipKey, "false"),
 		},
+		"annotation, force push, with message": {
+			expectedEntry: &AnnotationEntry{
+				ID:          plumbing.ZeroHash,
+				RSLEntryIDs: []plumbing.Hash{plumbing.ZeroHash},
+				Skip:        false,
+				ForcePush:   true,
+				Message:     "message",
+			},
+			message: fmt.Sprintf("%s\n\n%s: %s\n%s: %s\n%s: %s\n%s\n%s\n%s", AnnotationEntryHeader, EntryIDKey, plumbing.ZeroHash.String(), SkipKey, "false", ForcePushKey, "true", BeginMessage, base64.StdEncoding.EncodeToString([]byte("message")), EndMessage),
+		},
+		"annotation, break glass, with message": {
+			expectedEntry: &AnnotationEntry{
+				ID:          plumbing.ZeroHash,
+				RSLEntryIDs: []plumbing.Hash{plumbing.ZeroHash},
+				Skip:        false,
+				BreakGlass:  true,
+				Message:     "message",
+			},
+			message: fmt.Sprintf("%s\n\n%s: %s\n%s: %s\n%s: %s\n%s\n%s\n%s", AnnotationEntryHeader, EntryIDKey, plumbing.ZeroHash.String(), SkipKey, "false", BreakGlassKey, "true", BeginMessage, base64.StdEncoding.EncodeToString([]byte("message")), EndMessage),
+		},
+		"annotation, with provenance, with message": {
+			expectedEntry: &AnnotationEntry{
+				ID:            plumbing.ZeroHash,
+				RSLEntryIDs:   []plumbing.Hash{plumbing.ZeroHash},
+				Skip:          true,
+				GittufVersion: "v0.5.0",
+				SigningMethod: "x509",
+				Message:       "message",
+			},
+			message: fmt.Sprintf("%s\n\n%s: %s\n%s: %s\n%s: %s\n%s: %s\n%s\n%s\n%s", AnnotationEntryHeader, EntryIDKey, plumbing.ZeroHash.String(), SkipKey, "true", GittufVersionKey, "v0.5.0", SigningMethodKey, "x509", BeginMessage, base64.StdEncoding.EncodeToString([]byte("message")), EndMessage),
+		},
 		"annotation, missing header": {
 			expectedError: ErrInvalidRSLEntry,
 			message:       fmt.Sprintf("%s: %s\n%s: %s\n%s\n%s\n%s", EntryIDKey, plumbing.ZeroHash.String(), SkipKey, "true", BeginMessage, base64.StdEncoding.EncodeToString([]byte("message")), EndMessage),
 		},
+		"annotation, invalid entry ID": {
+			expectedError: ErrInvalidRSLEntry,
+			message:       fmt.Sprintf("%s\n\n%s: %s\n%s: %s", AnnotationEntryHeader, EntryIDKey, "not-an-id", SkipKey, "true"),
+		},
 		"annotation, missing information": {
 			expectedError: ErrInvalidRSLEntry,
 			message:       fmt.Sprintf("%s\n\n%s: %s", AnnotationEntryHeader, EntryIDKey, plumbing.ZeroHash.String()),
@@ -0,0 +1,241 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package age
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gittuf/gittuf/internal/tuf"
+	sslibsv "github.com/secure-systems-lab/go-securesystemslib/signerverifier"
+)
+
+const (
+	identityPrefix = "AGE-SECRET-KEY-1"
+	identityHRP    = "age-secret-key-"
+	ED25519Scheme  = "ed25519"
+
+	// signingKeyDerivationContext is used to derive the Ed25519 signing key
+	// from an age identity, so that the derived key is not used for any
+	// purpose other than signing gittuf metadata.
+	signingKeyDerivationContext = "gittuf age ed25519 signing key v1"
+
+	bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+)
+
+var (
+	ErrNoAgeIdentityFound = errors.New("no age identity found")
+	ErrInvalidAgeIdentity = errors.New("invalid age identity")
+)
+
+// IsIdentity indicates if the contents contain an age identity, as created by
+// age-keygen.
+func IsIdentity(contents []byte) bool {
+	_, err := findIdentity(contents)
+	return err == nil
+}
+
+// LoadIdentity returns a tuf.Key for signing with the age identity in the
+// contents. age identities are X25519 keys that can only be used for key
+// agreement. So, an Ed25519 key is derived deterministically from the identity
+// and used for signing. The returned key includes the derived private key, and
+// its key ID is calculated the same way as for keys loaded via
+// tuf.LoadKeyFromBytes. As the derived public key is recorded in gittuf
+// policy, signatures are verified like any other Ed25519 key.
+func LoadIdentity(contents []byte) (*tuf.Key, error) {
+	identity, err := findIdentity(contents)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := decodeIdentity(identity)
+	if err != nil {
+		return nil, err
+	}
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(signingKeyDerivationContext))
+	privateKey := ed25519.NewKeyFromSeed(mac.Sum(nil))
+
+	key := &tuf.Key{
+		KeyType:             sslibsv.ED25519KeyType,
+		Scheme:              ED25519Scheme,
+		KeyIDHashAlgorithms: []string{"sha256", "sha512"},
+		KeyVal: sslibsv.KeyVal{
+			Public:  hex.EncodeToString(privateKey.Public().(ed25519.PublicKey)),
+			Private: hex.EncodeToString(privateKey.Seed()),
+		},
+	}
+
+	// We round trip via tuf.LoadKeyFromBytes so that the key ID is calculated
+	// the same way as for keys loaded from disk.
+	keyBytes, err := json.Marshal(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return tuf.LoadKeyFromBytes(keyBytes)
+}
+
+// LoadPublicKey returns the public tuf.Key corresponding to the age identity in
+// the contents. See LoadIdentity for details.
+func LoadPublicKey(contents []byte) (*tuf.Key, error) {
+	key, err := LoadIdentity(contents)
+	if err != nil {
+		return nil, err
+	}
+
+	key.KeyVal.Private = ""
+	return key, nil
+}
+
+// NewSignerVerifierFromIdentity returns a signer verifier for DSSE envelopes
+// using the Ed25519 key derived from the age identity in the contents.
+func NewSignerVerifierFromIdentity(contents []byte) (*sslibsv.ED25519SignerVerifier, error) {
+	key, err := LoadIdentity(contents)
+	if err != nil {
+		return nil, err
+	}
+
+	return sslibsv.NewED25519SignerVerifierFromSSLibKey(key)
+}
+
+// findIdentity returns the first age identity in the contents. Empty lines and
+// comments, such as those added by age-keygen, are ignored.
+func findIdentity(contents []byte) (string, error) {
+	s := bufio.NewScanner(bytes.NewReader(contents))
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		if len(line) == 0 || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if strings.HasPrefix(line, identityPrefix) {
+			return line, nil
+		}
+
+		// The contents are not an age identity file
+		return "", ErrNoAgeIdentityFound
+	}
+	if err := s.Err(); err != nil {
+		return "", err
+	}
+
+	return "", ErrNoAgeIdentityFound
+}
+
+// decodeIdentity returns the X25519 secret encoded in the Bech32 identity.
+func decodeIdentity(identity string) ([]byte, error) {
+	hrp, data, err := bech32Decode(identity)
+	if err != nil {
+		return nil, err
+	}
+	if hrp != identityHRP {
+		return nil, fmt.Errorf("%w: unexpected prefix '%s'", ErrInvalidAgeIdentity, hrp)
+	}
+
+	secret, err := convertBits(data, 5, 8)
+	if err != nil {
+		return nil, err
+	}
+	if len(secret) != 32 {
+		return nil, fmt.Errorf("%w: unexpected key length %d", ErrInvalidAgeIdentity, len(secret))
+	}
+
+	return secret, nil
+}
+
+// bech32Decode decodes a Bech32 string as specified in BIP 173, returning the
+// human readable part and the data without the checksum. Like age, we don't
+// enforce the 90 character limit.
+func bech32Decode(s string) (string, []byte, error) {
+	if strings.ToLower(s) != s && strings.ToUpper(s) != s {
+		return "", nil, fmt.Errorf("%w: mixed case", ErrInvalidAgeIdentity)
+	}
+	s = strings.ToLower(s)
+
+	separator := strings.LastIndex(s, "1")
+	if separator < 1 || separator+7 > len(s) {
+		return "", nil, fmt.Errorf("%w: invalid separator position", ErrInvalidAgeIdentity)
+	}
+
+	hrp := s[:separator]
+	data := make([]byte, 0, len(s)-separator-1)
+	for _, c := range s[separator+1:] {
+		value := strings.IndexRune(bech32Charset, c)
+		if value == -1 {
+			return "", nil, fmt.Errorf("%w: invalid character '%c'", ErrInvalidAgeIdentity, c)
+		}
+		data = append(data, byte(value))
+	}
+
+	if bech32Polymod(append(bech32ExpandHRP(hrp), data...)) != 1 {
+		return "", nil, fmt.Errorf("%w: invalid checksum", ErrInvalidAgeIdentity)
+	}
+
+	return hrp, data[:len(data)-6], nil
+}
+
+func bech32Polymod(values []byte) uint32 {
+	generator := []uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+
+	checksum := uint32(1)
+	for _, value := range values {
+		top := checksum >> 25
+		checksum = (checksum&0x1ffffff)<<5 ^ uint32(value)
+		for i := 0; i < 5; i++ {
+			if (top>>i)&1 == 1 {
+				checksum ^= generator[i]
+			}
+		}
+	}
+
+	return checksum
+}
+
+func bech32ExpandHRP(hrp string) []byte {
+	expanded := make([]byte, 0, len(hrp)*2+1)
+	for i := 0; i < len(hrp); i++ {
+		expanded = append(expanded, hrp[i]>>5)
+	}
+	expanded = append(expanded, 0)
+	for i := 0; i < len(hrp); i++ {
+		expanded = append(expanded, hrp[i]&31)
+	}
+
+	return expanded
+}
+
+// convertBits regroups the data from groups of fromBits into groups of toBits.
+// Padding is not allowed, so any leftover bits must be zero.
+func convertBits(data []byte, fromBits, toBits uint) ([]byte, error) {
+	var (
+		accumulator uint32
+		bits        uint
+		converted   []byte
+	)
+	maxValue := uint32(1<<toBits) - 1
+
+	for _, value := range data {
+		accumulator = accumulator<<fromBits | uint32(value)
+		bits += fromBits
+		for bits >= toBits {
+			bits -= toBits
+			converted = append(converted, byte(accumulator>>bits&maxValue))
+		}
+	}
+
+	if bits >= fromBits || (accumulator<<(toBits-bits))&maxValue != 0 {
+		return nil, fmt.Errorf("%w: invalid padding", ErrInvalidAgeIdentity)
+	}
+
+	return converted, nil
+}
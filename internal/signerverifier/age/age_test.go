@@ -0,0 +1,104 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package age
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	sslibsv "github.com/secure-systems-lab/go-securesystemslib/signerverifier"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadIdentity(t *testing.T) {
+	identityBytes, err := os.ReadFile(filepath.Join("test-data", "age-identity.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("valid identity", func(t *testing.T) {
+		assert.True(t, IsIdentity(identityBytes))
+
+		key, err := LoadIdentity(identityBytes)
+		assert.Nil(t, err)
+		assert.Equal(t, sslibsv.ED25519KeyType, key.KeyType)
+		assert.Equal(t, ED25519Scheme, key.Scheme)
+		assert.Len(t, key.KeyVal.Public, 64)
+		assert.NotEmpty(t, key.KeyVal.Private)
+		assert.NotEmpty(t, key.KeyID)
+
+		// The key ID must be stable
+		keyAgain, err := LoadIdentity(identityBytes)
+		assert.Nil(t, err)
+		assert.Equal(t, key.KeyID, keyAgain.KeyID)
+		assert.Equal(t, key.KeyVal.Public, keyAgain.KeyVal.Public)
+
+		publicKey, err := LoadPublicKey(identityBytes)
+		assert.Nil(t, err)
+		assert.Equal(t, key.KeyID, publicKey.KeyID)
+		assert.Equal(t, key.KeyVal.Public, publicKey.KeyVal.Public)
+		assert.Empty(t, publicKey.KeyVal.Private)
+	})
+
+	t.Run("invalid checksum", func(t *testing.T) {
+		contents := strings.TrimSpace(string(identityBytes))
+		last := contents[len(contents)-1]
+		replacement := "Q"
+		if last == 'Q' {
+			replacement = "P"
+		}
+		contents = contents[:len(contents)-1] + replacement
+
+		_, err := LoadIdentity([]byte(contents))
+		assert.ErrorIs(t, err, ErrInvalidAgeIdentity)
+	})
+
+	t.Run("no identity", func(t *testing.T) {
+		assert.False(t, IsIdentity([]byte("# just a comment\n")))
+
+		_, err := LoadIdentity([]byte("# just a comment\n"))
+		assert.ErrorIs(t, err, ErrNoAgeIdentityFound)
+	})
+}
+
+func TestSignAndVerify(t *testing.T) {
+	identityBytes, err := os.ReadFile(filepath.Join("test-data", "age-identity.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signer, err := NewSignerVerifierFromIdentity(identityBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	publicKey, err := LoadPublicKey(identityBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	verifier, err := sslibsv.NewED25519SignerVerifierFromSSLibKey(publicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signerKeyID, err := signer.KeyID()
+	assert.Nil(t, err)
+	verifierKeyID, err := verifier.KeyID()
+	assert.Nil(t, err)
+	assert.Equal(t, signerKeyID, verifierKeyID)
+
+	data := []byte("gittuf")
+	sig, err := signer.Sign(context.Background(), data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = verifier.Verify(context.Background(), data, sig)
+	assert.Nil(t, err)
+
+	err = verifier.Verify(context.Background(), []byte("not gittuf"), sig)
+	assert.NotNil(t, err)
+}
@@ -6,6 +6,8 @@ import (
 	"context"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
+	"fmt"
 
 	"github.com/gittuf/gittuf/internal/signerverifier/common"
 	"github.com/secure-systems-lab/go-securesystemslib/dsse"
@@ -13,6 +15,11 @@ import (
 
 const PayloadType = "application/vnd.gittuf+json"
 
+var (
+	ErrDuplicateSignature = errors.New("envelope is already signed by key")
+	ErrEnvelopeMismatch   = errors.New("envelopes have different payloads")
+)
+
 // CreateEnvelope is an opinionated interface to create a DSSE envelope. It
 // accepts instances of tuf.RootMetadata, tuf.TargetsMetadata, etc. and marshals
 // the input prior to storing it as the envelope's payload.
@@ -31,13 +38,20 @@ func CreateEnvelope(v any) (*dsse.Envelope, error) {
 
 // SignEnvelope is an opinionated API to sign TUF metadata. It's opinionated
 // because it assumes the payload is Base 64 encoded, which is the expectation
-// for TUF metadata generated by gittuf.
+// for TUF metadata generated by gittuf. The signature is appended to any
+// existing signatures in the envelope, so an envelope can be signed by each of
+// its signers in turn. ErrDuplicateSignature is returned if the envelope is
+// already signed by the signer's key.
 func SignEnvelope(ctx context.Context, envelope *dsse.Envelope, signer dsse.Signer) (*dsse.Envelope, error) {
 	keyID, err := signer.KeyID()
 	if err != nil {
 		return nil, err
 	}
 
+	if hasSignatureFromKey(envelope, keyID) {
+		return nil, fmt.Errorf("%w '%s'", ErrDuplicateSignature, keyID)
+	}
+
 	payload, err := base64.StdEncoding.DecodeString(envelope.Payload)
 	if err != nil {
 		return nil, err
@@ -57,15 +71,54 @@ func SignEnvelope(ctx context.Context, envelope *dsse.Envelope, signer dsse.Sign
 	return envelope, nil
 }
 
+// MergeEnvelopes adds the signatures in each of the other envelopes to the
+// envelope. This supports offline co-signing, where copies of an unsigned or
+// partially signed envelope are signed independently by each signer and their
+// signatures are then combined to meet the threshold. All envelopes must have
+// the same payload, and each key may only contribute one signature. Identical
+// signatures that are present in multiple envelopes, such as those that were
+// already in the envelope before copies were distributed, are merged.
+func MergeEnvelopes(envelope *dsse.Envelope, others ...*dsse.Envelope) (*dsse.Envelope, error) {
+	merged := &dsse.Envelope{
+		PayloadType: envelope.PayloadType,
+		Payload:     envelope.Payload,
+		Signatures:  append([]dsse.Signature{}, envelope.Signatures...),
+	}
+
+	for _, other := range others {
+		if other.PayloadType != merged.PayloadType || other.Payload != merged.Payload {
+			return nil, ErrEnvelopeMismatch
+		}
+
+		for _, signature := range other.Signatures {
+			existing, has := findSignatureFromKey(merged, signature.KeyID)
+			if !has {
+				merged.Signatures = append(merged.Signatures, signature)
+				continue
+			}
+
+			if existing.Sig != signature.Sig {
+				return nil, fmt.Errorf("%w '%s'", ErrDuplicateSignature, signature.KeyID)
+			}
+		}
+	}
+
+	return merged, nil
+}
+
 // VerifyEnvelope verifies a DSSE envelope against an expected threshold using
 // a slice of verifiers passed into it. Threshold indicates the number of
-// providers that must validate the envelope.
+// providers that must validate the envelope. The verifiers slice is not
+// modified, so it can be reused across calls.
 func VerifyEnvelope(ctx context.Context, envelope *dsse.Envelope, verifiers []dsse.Verifier, threshold int) error {
 	if threshold < 1 || threshold > len(verifiers) {
 		return common.ErrInvalidThreshold
 	}
 
-	ev, err := dsse.NewMultiEnvelopeVerifier(threshold, verifiers...)
+	// The envelope verifier removes verifiers that accept a signature from the
+	// slice it is given in place, so it must not share the caller's slice
+	verifiersCopy := append([]dsse.Verifier{}, verifiers...)
+	ev, err := dsse.NewMultiEnvelopeVerifier(threshold, verifiersCopy...)
 	if err != nil {
 		return err
 	}
@@ -73,3 +126,18 @@ func VerifyEnvelope(ctx context.Context, envelope *dsse.Envelope, verifiers []ds
 	_, err = ev.Verify(ctx, envelope)
 	return err
 }
+
+func hasSignatureFromKey(envelope *dsse.Envelope, keyID string) bool {
+	_, has := findSignatureFromKey(envelope, keyID)
+	return has
+}
+
+func findSignatureFromKey(envelope *dsse.Envelope, keyID string) (dsse.Signature, bool) {
+	for _, signature := range envelope.Signatures {
+		if signature.KeyID == keyID {
+			return signature, true
+		}
+	}
+
+	return dsse.Signature{}, false
+}
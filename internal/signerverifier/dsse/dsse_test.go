@@ -33,6 +33,87 @@ func TestSignEnvelope(t *testing.T) {
 	assert.Equal(t, "a0xAMWnJ3Hzf8j2zLFmniyUxV58m2lUprgzDPkJIRUORR4aKlX23WB3teaVMjXLuRKrD5GAMN8NSCR1vaetxBA==", env.Signatures[0].Sig)
 }
 
+func TestSignEnvelopeDuplicateSignature(t *testing.T) {
+	env, err := createSignedEnvelope()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signer := loadTestSigner(t, "test-key")
+
+	_, err = SignEnvelope(context.Background(), env, signer)
+	assert.ErrorIs(t, err, ErrDuplicateSignature)
+	assert.Equal(t, 1, len(env.Signatures))
+}
+
+func TestMergeEnvelopes(t *testing.T) {
+	signer1 := loadTestSigner(t, "test-key")
+	signer2 := loadTestSigner(t, "test-key-2")
+	verifiers := []sslibdsse.Verifier{loadTestVerifier(t, "test-key.pub"), loadTestVerifier(t, "test-key-2.pub")}
+
+	rootMetadata := tuf.NewRootMetadata()
+
+	// unsignedEnvelope returns a copy of the exported, unsigned envelope
+	unsignedEnvelope := func(t *testing.T) *sslibdsse.Envelope {
+		t.Helper()
+
+		env, err := CreateEnvelope(rootMetadata)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return env
+	}
+
+	env1, err := SignEnvelope(context.Background(), unsignedEnvelope(t), signer1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	env2, err := SignEnvelope(context.Background(), unsignedEnvelope(t), signer2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("independent signatures meet threshold", func(t *testing.T) {
+		// Neither signature meets the threshold on its own
+		assert.NotNil(t, VerifyEnvelope(context.Background(), env1, verifiers, 2))
+		assert.NotNil(t, VerifyEnvelope(context.Background(), env2, verifiers, 2))
+
+		merged, err := MergeEnvelopes(unsignedEnvelope(t), env1, env2)
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(merged.Signatures))
+		assert.Nil(t, VerifyEnvelope(context.Background(), merged, verifiers, 2))
+	})
+
+	t.Run("identical signatures are merged", func(t *testing.T) {
+		merged, err := MergeEnvelopes(env1, env1, env2)
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(merged.Signatures))
+
+		// The original envelope is not modified
+		assert.Equal(t, 1, len(env1.Signatures))
+	})
+
+	t.Run("conflicting signatures from same key", func(t *testing.T) {
+		forged := unsignedEnvelope(t)
+		forged.Signatures = append(forged.Signatures, sslibdsse.Signature{KeyID: env1.Signatures[0].KeyID, Sig: env2.Signatures[0].Sig})
+
+		_, err := MergeEnvelopes(env1, forged)
+		assert.ErrorIs(t, err, ErrDuplicateSignature)
+	})
+
+	t.Run("different payloads", func(t *testing.T) {
+		otherMetadata := tuf.NewRootMetadata()
+		otherMetadata.SetVersion(2)
+		otherEnv, err := CreateEnvelope(otherMetadata)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = MergeEnvelopes(env1, otherEnv)
+		assert.ErrorIs(t, err, ErrEnvelopeMismatch)
+	})
+}
+
 func TestVerifyEnvelope(t *testing.T) {
 	env, err := createSignedEnvelope()
 	if err != nil {
@@ -57,6 +138,23 @@ func TestVerifyEnvelope(t *testing.T) {
 	assert.Nil(t, VerifyEnvelope(context.Background(), env, []sslibdsse.Verifier{verifier}, 1))
 }
 
+func TestVerifyEnvelopeReusesVerifiers(t *testing.T) {
+	env, err := createSignedEnvelope()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifier1 := loadTestVerifier(t, "test-key.pub")
+	verifier2 := loadTestVerifier(t, "test-key-2.pub")
+	verifiers := []sslibdsse.Verifier{verifier1, verifier2}
+
+	assert.Nil(t, VerifyEnvelope(context.Background(), env, verifiers, 1))
+	assert.Equal(t, []sslibdsse.Verifier{verifier1, verifier2}, verifiers)
+
+	// The first verifier is still available to a later verification
+	assert.Nil(t, VerifyEnvelope(context.Background(), env, verifiers, 1))
+}
+
 func createSignedEnvelope() (*sslibdsse.Envelope, error) {
 	privateKeyPath := filepath.Join("test-data", "test-key")
 	privateKeyBytes, err := os.ReadFile(privateKeyPath)
@@ -85,3 +183,25 @@ func createSignedEnvelope() (*sslibdsse.Envelope, error) {
 
 	return env, nil
 }
+
+func loadTestSigner(t *testing.T, keyName string) sslibdsse.SignerVerifier {
+	t.Helper()
+
+	keyBytes, err := os.ReadFile(filepath.Join("test-data", keyName))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signer, err := signerverifier.NewSignerVerifierFromSecureSystemsLibFormat(keyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return signer
+}
+
+func loadTestVerifier(t *testing.T, keyName string) sslibdsse.Verifier {
+	t.Helper()
+
+	return loadTestSigner(t, keyName)
+}
@@ -0,0 +1,135 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package external
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// waitDelay bounds how long the signing program's output is waited on after
+// the program is stopped, in case it has started children that hold on to its
+// stdout or stderr.
+const waitDelay = time.Second
+
+var (
+	ErrSigningProgramNotSpecified = errors.New("signing program not specified")
+	ErrSigningProgramFailed       = errors.New("signing program failed")
+	ErrNoSignature                = errors.New("signing program did not return a signature")
+)
+
+// SignerOptions contains the optional parameters of NewSigner.
+type SignerOptions struct {
+	Args    []string
+	Timeout time.Duration
+}
+
+// SignerOption is used to configure NewSigner.
+type SignerOption func(*SignerOptions)
+
+// WithArgs sets the arguments the signing program is invoked with, such as the
+// arguments that identify the key to sign with.
+func WithArgs(args ...string) SignerOption {
+	return func(o *SignerOptions) {
+		o.Args = args
+	}
+}
+
+// WithTimeout bounds how long the signing program may run for each signature.
+// The program is stopped if it runs longer, such as when it's waiting on input
+// that will never arrive. By default, the program is only stopped if the
+// context passed to Sign is done.
+func WithTimeout(timeout time.Duration) SignerOption {
+	return func(o *SignerOptions) {
+		o.Timeout = timeout
+	}
+}
+
+// Signer signs data using an external program, in the style of Git's
+// gpg.program option. This allows signing with keys that gittuf cannot access
+// directly, such as keys held by an agent or a hardware token. The data is
+// written to the program's stdin and the signature is read from its stdout.
+// Signer implements the DSSE Signer interface, and it can also be used to sign
+// Git objects if the program returns signatures in a format Git understands.
+type Signer struct {
+	program string
+	args    []string
+	keyID   string
+	timeout time.Duration
+}
+
+// NewSigner returns a Signer that invokes program to create signatures. The
+// keyID identifies the key the program signs with, and is recorded in the
+// signatures of DSSE envelopes.
+func NewSigner(program, keyID string, opts ...SignerOption) (*Signer, error) {
+	if len(program) == 0 {
+		return nil, ErrSigningProgramNotSpecified
+	}
+
+	options := &SignerOptions{}
+	for _, fn := range opts {
+		fn(options)
+	}
+
+	return &Signer{
+		program: program,
+		args:    options.Args,
+		keyID:   keyID,
+		timeout: options.Timeout,
+	}, nil
+}
+
+// Sign invokes the signing program with data on its stdin and returns what the
+// program writes to its stdout. Programs may terminate their output with a
+// newline, as is conventional for command line tools, so a single trailing
+// newline is not considered part of the signature and is removed. If the
+// program fails, the returned error includes what the program wrote to its
+// stderr. The program is stopped if ctx is done or the configured timeout
+// expires before it exits.
+func (s *Signer) Sign(ctx context.Context, data []byte) ([]byte, error) {
+	if s.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.timeout)
+		defer cancel()
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, s.program, s.args...)
+	cmd.Stdin = bytes.NewReader(data)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	cmd.WaitDelay = waitDelay
+
+	if err := cmd.Run(); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			err = ctxErr
+		}
+		return nil, newSigningProgramError(s.program, err, stderr.Bytes())
+	}
+
+	signature := bytes.TrimSuffix(stdout.Bytes(), []byte("\n"))
+	if len(signature) == 0 {
+		return nil, fmt.Errorf("%w: '%s'", ErrNoSignature, s.program)
+	}
+
+	return signature, nil
+}
+
+// KeyID returns the ID of the key the signing program signs with.
+func (s *Signer) KeyID() (string, error) {
+	return s.keyID, nil
+}
+
+func newSigningProgramError(program string, err error, stderr []byte) error {
+	diagnostics := strings.TrimSpace(string(stderr))
+	if len(diagnostics) == 0 {
+		return fmt.Errorf("%w: '%s': %w", ErrSigningProgramFailed, program, err)
+	}
+
+	return fmt.Errorf("%w: '%s': %w: %s", ErrSigningProgramFailed, program, err, diagnostics)
+}
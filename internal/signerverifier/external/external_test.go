@@ -0,0 +1,145 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package external
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSigner(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake signing programs are shell scripts")
+	}
+
+	// The fake signing program returns its arguments followed by its input
+	echoProgram := createFakeSigningProgram(t, `printf '%s:' "$@"; cat`)
+
+	t.Run("successful signature", func(t *testing.T) {
+		signer, err := NewSigner(echoProgram, "test-key", WithArgs("-u", "test-key"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		signature, err := signer.Sign(context.Background(), []byte("payload"))
+		assert.Nil(t, err)
+		assert.Equal(t, []byte("-u:test-key:payload"), signature)
+
+		keyID, err := signer.KeyID()
+		assert.Nil(t, err)
+		assert.Equal(t, "test-key", keyID)
+	})
+
+	t.Run("one trailing newline is removed", func(t *testing.T) {
+		program := createFakeSigningProgram(t, `cat > /dev/null; printf 'signature\n\n'`)
+		signer, err := NewSigner(program, "test-key")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		signature, err := signer.Sign(context.Background(), []byte("payload"))
+		assert.Nil(t, err)
+		assert.Equal(t, []byte("signature\n"), signature)
+	})
+
+	t.Run("sign DSSE envelope", func(t *testing.T) {
+		signer, err := NewSigner(echoProgram, "test-key")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		env, err := dsse.CreateEnvelope(map[string]string{"hello": "world"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		env, err = dsse.SignEnvelope(context.Background(), env, signer)
+		assert.Nil(t, err)
+		if assert.Equal(t, 1, len(env.Signatures)) {
+			assert.Equal(t, "test-key", env.Signatures[0].KeyID)
+		}
+	})
+
+	t.Run("program fails", func(t *testing.T) {
+		program := createFakeSigningProgram(t, `echo "no secret key" >&2; exit 2`)
+		signer, err := NewSigner(program, "test-key")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = signer.Sign(context.Background(), []byte("payload"))
+		assert.ErrorIs(t, err, ErrSigningProgramFailed)
+		assert.Contains(t, err.Error(), "no secret key")
+	})
+
+	t.Run("program returns no signature", func(t *testing.T) {
+		program := createFakeSigningProgram(t, `cat > /dev/null`)
+		signer, err := NewSigner(program, "test-key")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = signer.Sign(context.Background(), []byte("payload"))
+		assert.ErrorIs(t, err, ErrNoSignature)
+	})
+
+	t.Run("program returns only a newline", func(t *testing.T) {
+		program := createFakeSigningProgram(t, `cat > /dev/null; echo`)
+		signer, err := NewSigner(program, "test-key")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = signer.Sign(context.Background(), []byte("payload"))
+		assert.ErrorIs(t, err, ErrNoSignature)
+	})
+
+	t.Run("program times out", func(t *testing.T) {
+		program := createFakeSigningProgram(t, `sleep 10`)
+		signer, err := NewSigner(program, "test-key", WithTimeout(100*time.Millisecond))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = signer.Sign(context.Background(), []byte("payload"))
+		assert.ErrorIs(t, err, ErrSigningProgramFailed)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("context is canceled", func(t *testing.T) {
+		program := createFakeSigningProgram(t, `sleep 10`)
+		signer, err := NewSigner(program, "test-key")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err = signer.Sign(ctx, []byte("payload"))
+		assert.ErrorIs(t, err, ErrSigningProgramFailed)
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("program not specified", func(t *testing.T) {
+		_, err := NewSigner("", "test-key")
+		assert.ErrorIs(t, err, ErrSigningProgramNotSpecified)
+	})
+}
+
+func createFakeSigningProgram(t *testing.T, script string) string {
+	t.Helper()
+
+	program := filepath.Join(t.TempDir(), "signer")
+	if err := os.WriteFile(program, []byte("#!/bin/sh\n"+script+"\n"), 0o755); err != nil { //nolint:gosec
+		t.Fatal(err)
+	}
+
+	return program
+}
@@ -5,7 +5,9 @@ package gpg
 import (
 	"bytes"
 	"fmt"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/ProtonMail/go-crypto/openpgp"
 	"github.com/gittuf/gittuf/internal/signerverifier"
@@ -15,7 +17,9 @@ import (
 
 // LoadGPGKeyFromBytes returns a tuf.Key for a GPG / PGP key passed in as
 // armored bytes. The returned tuf.Key uses the primary key's fingerprint as the
-// key ID.
+// key ID. The armored key is retained in its entirety, including all of its
+// user IDs and subkeys, so signatures are verified regardless of which user ID
+// the signer used. The user IDs can be retrieved using GetUserIDs.
 func LoadGPGKeyFromBytes(contents []byte) (*tuf.Key, error) {
 	keyring, err := openpgp.ReadArmoredKeyRing(bytes.NewReader(contents))
 	if err != nil {
@@ -37,3 +41,48 @@ func LoadGPGKeyFromBytes(contents []byte) (*tuf.Key, error) {
 
 	return gpgKey, nil
 }
+
+// GetUserIDs returns the user IDs of the primary key in the GPG / PGP tuf.Key,
+// such as "Jane Doe <jane.doe@example.com>", sorted lexicographically. Keys
+// used by teams often have several user IDs, and an identity check may match
+// any of them.
+func GetUserIDs(key *tuf.Key) ([]string, error) {
+	keyring, err := openpgp.ReadArmoredKeyRing(strings.NewReader(key.KeyVal.Public))
+	if err != nil {
+		return nil, err
+	}
+
+	userIDs := make([]string, 0, len(keyring[0].Identities))
+	for _, identity := range keyring[0].Identities {
+		if identity.Revoked(time.Now()) {
+			continue
+		}
+
+		userIDs = append(userIDs, identity.Name)
+	}
+	sort.Strings(userIDs)
+
+	return userIDs, nil
+}
+
+// GetEmails returns the email addresses of the user IDs of the primary key in
+// the GPG / PGP tuf.Key, sorted lexicographically. User IDs without an email
+// address are skipped.
+func GetEmails(key *tuf.Key) ([]string, error) {
+	keyring, err := openpgp.ReadArmoredKeyRing(strings.NewReader(key.KeyVal.Public))
+	if err != nil {
+		return nil, err
+	}
+
+	emails := []string{}
+	for _, identity := range keyring[0].Identities {
+		if identity.Revoked(time.Now()) || identity.UserId == nil || len(identity.UserId.Email) == 0 {
+			continue
+		}
+
+		emails = append(emails, identity.UserId.Email)
+	}
+	sort.Strings(emails)
+
+	return emails, nil
+}
@@ -23,3 +23,24 @@ func TestLoadGPGKeyFromBytes(t *testing.T) {
 	assert.Equal(t, signerverifier.GPGKeyType, key.Scheme)
 	assert.Equal(t, "157507bbe151e378ce8126c1dcfe043cdd2db96e", key.KeyID)
 }
+
+func TestGetUserIDs(t *testing.T) {
+	keyBytes, err := os.ReadFile(filepath.Join("test-data", "gpg-multiple-uids-pubkey.asc"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key, err := LoadGPGKeyFromBytes(keyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "6c122c904c3f29c0703bfcb016a6cd80d7d1ba95", key.KeyID)
+
+	userIDs, err := GetUserIDs(key)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"Jane Doe <jane.doe@example.com>", "Jane Doe <jane@example.org>"}, userIDs)
+
+	emails, err := GetEmails(key)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"jane.doe@example.com", "jane@example.org"}, emails)
+}
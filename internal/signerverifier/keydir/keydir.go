@@ -0,0 +1,125 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package keydir
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/gittuf/gittuf/internal/signerverifier/gpg"
+	"github.com/gittuf/gittuf/internal/signerverifier/minisign"
+	"github.com/gittuf/gittuf/internal/signerverifier/ssh"
+	"github.com/gittuf/gittuf/internal/tuf"
+)
+
+var (
+	ErrDuplicateKeyID = errors.New("multiple files contain keys with the same key ID")
+	ErrNotKeyFile     = errors.New("file does not contain a supported public key")
+	ErrPrivateKeyFile = errors.New("file contains private key material")
+)
+
+const (
+	pgpPublicKeyBlockHeader    = "-----BEGIN PGP PUBLIC KEY BLOCK-----"
+	publicKeyPEMBlockHeader    = "-----BEGIN PUBLIC KEY-----"
+	rsaPublicKeyPEMBlockHeader = "-----BEGIN RSA PUBLIC KEY-----"
+)
+
+// LoadKeysFromDir returns the public keys stored in the files of the specified
+// directory. Keys may use the securesystemslib format, or be armored GPG / PGP
+// keys, PEM encoded public keys, OpenSSH public keys, or minisign / signify
+// public keys. Files that do not contain a supported public key, including
+// files with private keys, are skipped and a warning is returned for each.
+// Subdirectories are not searched. Files are read in lexical order, and if
+// more than one file contains a key with the same ID, ErrDuplicateKeyID is
+// returned.
+func LoadKeysFromDir(dir string) ([]*tuf.Key, []string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, nil, err
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name() < entries[j].Name()
+	})
+
+	keys := []*tuf.Key{}
+	warnings := []string{}
+	keyFiles := map[string]string{}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		path := filepath.Join(dir, entry.Name())
+		contents, err := os.ReadFile(path)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		key, err := loadPublicKey(contents)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("skipping '%s': %s", path, err.Error()))
+			continue
+		}
+
+		if existingPath, has := keyFiles[key.KeyID]; has {
+			return nil, nil, fmt.Errorf("%w: '%s' in '%s' and '%s'", ErrDuplicateKeyID, key.KeyID, existingPath, path)
+		}
+		keyFiles[key.KeyID] = path
+
+		keys = append(keys, key)
+	}
+
+	return keys, warnings, nil
+}
+
+// loadPublicKey returns the public key in contents, detecting the format the
+// key is stored in.
+func loadPublicKey(contents []byte) (*tuf.Key, error) {
+	contents = bytes.TrimSpace(contents)
+
+	switch {
+	case len(contents) == 0:
+		return nil, ErrNotKeyFile
+	case bytes.HasPrefix(contents, []byte("{")):
+		key, err := tuf.LoadKeyFromBytes(contents)
+		if err != nil {
+			return nil, errors.Join(ErrNotKeyFile, err)
+		}
+		if len(key.KeyVal.Private) > 0 {
+			return nil, ErrPrivateKeyFile
+		}
+		if len(key.KeyType) == 0 || (len(key.KeyVal.Public) == 0 && len(key.KeyVal.Identity) == 0) {
+			return nil, ErrNotKeyFile
+		}
+		return key, nil
+	case bytes.HasPrefix(contents, []byte(pgpPublicKeyBlockHeader)):
+		key, err := gpg.LoadGPGKeyFromBytes(contents)
+		if err != nil {
+			return nil, errors.Join(ErrNotKeyFile, err)
+		}
+		return key, nil
+	case bytes.HasPrefix(contents, []byte(publicKeyPEMBlockHeader)), bytes.HasPrefix(contents, []byte(rsaPublicKeyPEMBlockHeader)):
+		key, err := tuf.LoadKeyFromBytes(contents)
+		if err != nil {
+			return nil, errors.Join(ErrNotKeyFile, err)
+		}
+		return key, nil
+	case bytes.HasPrefix(contents, []byte("-----BEGIN")):
+		// Other PEM blocks, such as private keys, are not supported
+		return nil, ErrNotKeyFile
+	}
+
+	if key, err := ssh.LoadSSHPublicKeyFromBytes(contents); err == nil {
+		return key, nil
+	}
+
+	if key, err := minisign.LoadMinisignPublicKeyFromBytes(contents); err == nil {
+		return key, nil
+	}
+
+	return nil, ErrNotKeyFile
+}
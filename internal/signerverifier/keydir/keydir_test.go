@@ -0,0 +1,125 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package keydir
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/signerverifier"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadKeysFromDir(t *testing.T) {
+	t.Run("mixed file types", func(t *testing.T) {
+		dir := t.TempDir()
+		copyTestData(t, dir, "test-key.pub", "ed25519.pub", "gpg-pubkey.asc", "minisign.pub")
+		writeFile(t, filepath.Join(dir, "README.md"), []byte("# Keys\n\nPublic keys for the repository.\n"))
+		writeFile(t, filepath.Join(dir, "empty.pub"), nil)
+		writeFile(t, filepath.Join(dir, "private.json"), []byte(`{"keytype": "ed25519", "scheme": "ed25519", "keyval": {"public": "abcd", "private": "efgh"}}`))
+		if err := os.Mkdir(filepath.Join(dir, "nested"), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		copyTestData(t, filepath.Join(dir, "nested"), "test-key.pub")
+
+		keys, warnings, err := LoadKeysFromDir(dir)
+		assert.Nil(t, err)
+
+		keyTypes := []string{}
+		for _, key := range keys {
+			keyTypes = append(keyTypes, key.KeyType)
+		}
+		// Keys are returned in the lexical order of their files
+		assert.Equal(t, []string{signerverifier.ED25519KeyType, signerverifier.GPGKeyType, signerverifier.MinisignKeyType, signerverifier.ED25519KeyType}, keyTypes)
+		assert.Equal(t, "d3d1bb09c11d3f7ba2ba29088973175b077bee1d2a56c9372ff82e72cd496469", keys[2].KeyID)
+		assert.Equal(t, "52e3b8e73279d6ebdd62a5016e2725ff284f569665eb92ccb145d83817a02997", keys[3].KeyID)
+
+		assert.Equal(t, 3, len(warnings))
+		assert.True(t, strings.Contains(warnings[0], "README.md"))
+		assert.True(t, strings.Contains(warnings[1], "empty.pub"))
+		assert.True(t, strings.Contains(warnings[2], "private.json"))
+		assert.True(t, strings.Contains(warnings[2], ErrPrivateKeyFile.Error()))
+	})
+
+	t.Run("PEM encoded keys", func(t *testing.T) {
+		privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		publicKeyBytes, err := x509.MarshalPKIXPublicKey(privateKey.Public())
+		if err != nil {
+			t.Fatal(err)
+		}
+		privateKeyBytes, err := x509.MarshalPKCS8PrivateKey(privateKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		dir := t.TempDir()
+		writeFile(t, filepath.Join(dir, "ecdsa.pem"), pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicKeyBytes}))
+		writeFile(t, filepath.Join(dir, "ecdsa-private.pem"), pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateKeyBytes}))
+
+		keys, warnings, err := LoadKeysFromDir(dir)
+		assert.Nil(t, err)
+		if assert.Equal(t, 1, len(keys)) {
+			assert.Equal(t, signerverifier.ECDSAKeyType, keys[0].KeyType)
+		}
+		if assert.Equal(t, 1, len(warnings)) {
+			assert.True(t, strings.Contains(warnings[0], "ecdsa-private.pem"))
+		}
+	})
+
+	t.Run("duplicate key IDs", func(t *testing.T) {
+		dir := t.TempDir()
+		copyTestData(t, dir, "test-key.pub", "minisign.pub")
+
+		contents, err := os.ReadFile(filepath.Join("test-data", "test-key.pub"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		writeFile(t, filepath.Join(dir, "test-key-copy.pub"), contents)
+
+		_, _, err = LoadKeysFromDir(dir)
+		assert.ErrorIs(t, err, ErrDuplicateKeyID)
+		assert.Contains(t, err.Error(), "test-key-copy.pub")
+	})
+
+	t.Run("empty directory", func(t *testing.T) {
+		keys, warnings, err := LoadKeysFromDir(t.TempDir())
+		assert.Nil(t, err)
+		assert.Empty(t, keys)
+		assert.Empty(t, warnings)
+	})
+
+	t.Run("directory does not exist", func(t *testing.T) {
+		_, _, err := LoadKeysFromDir(filepath.Join(t.TempDir(), "missing"))
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
+
+func copyTestData(t *testing.T, dir string, names ...string) {
+	t.Helper()
+
+	for _, name := range names {
+		contents, err := os.ReadFile(filepath.Join("test-data", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		writeFile(t, filepath.Join(dir, name), contents)
+	}
+}
+
+func writeFile(t *testing.T, path string, contents []byte) {
+	t.Helper()
+
+	if err := os.WriteFile(path, contents, 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
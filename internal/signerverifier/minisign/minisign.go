@@ -0,0 +1,212 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package minisign
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gittuf/gittuf/internal/signerverifier"
+	"github.com/gittuf/gittuf/internal/signerverifier/common"
+	"github.com/gittuf/gittuf/internal/tuf"
+	sslibsv "github.com/secure-systems-lab/go-securesystemslib/signerverifier"
+	"golang.org/x/crypto/blake2b"
+)
+
+const (
+	ED25519Scheme = "ed25519"
+
+	// UntrustedCommentPrefix is the prefix of the first line of minisign and
+	// signify public keys and signatures.
+	UntrustedCommentPrefix = "untrusted comment:"
+	trustedCommentPrefix   = "trusted comment:"
+
+	// legacyAlgorithm signs the data directly, and is the only algorithm
+	// supported by signify. hashedAlgorithm signs the BLAKE2b-512 digest of
+	// the data and is the default for minisign.
+	legacyAlgorithm = "Ed"
+	hashedAlgorithm = "ED"
+
+	keyNumLength    = 8
+	publicKeyLength = 2 + keyNumLength + ed25519.PublicKeySize
+	signatureLength = 2 + keyNumLength + ed25519.SignatureSize
+)
+
+var (
+	ErrInvalidMinisignPublicKey = errors.New("invalid minisign or signify public key")
+	ErrInvalidMinisignSignature = errors.New("invalid minisign or signify signature")
+	ErrKeyMismatch              = errors.New("signature was not created by the minisign or signify key")
+)
+
+// LoadMinisignPublicKeyFromBytes returns a tuf.Key for a minisign or signify
+// public key. The contents may be a public key file, whose first line is an
+// untrusted comment, or just the base64 encoded key as printed by minisign -R.
+// The key ID of the returned tuf.Key is calculated from the public key like
+// for other keys. The key ID assigned by minisign or signify is not used as it
+// is chosen by the key's creator, so it may collide with that of another key.
+func LoadMinisignPublicKeyFromBytes(contents []byte) (*tuf.Key, error) {
+	lines, err := readLines(contents)
+	if err != nil {
+		return nil, errors.Join(ErrInvalidMinisignPublicKey, err)
+	}
+	if len(lines) > 0 && strings.HasPrefix(lines[0], UntrustedCommentPrefix) {
+		lines = lines[1:]
+	}
+	if len(lines) != 1 {
+		return nil, fmt.Errorf("%w: expected a single encoded key", ErrInvalidMinisignPublicKey)
+	}
+
+	if _, _, err := decodePublicKey(lines[0]); err != nil {
+		return nil, err
+	}
+
+	key := &tuf.Key{
+		KeyType:             signerverifier.MinisignKeyType,
+		Scheme:              ED25519Scheme,
+		KeyIDHashAlgorithms: []string{"sha256", "sha512"},
+		KeyVal: sslibsv.KeyVal{
+			Public: lines[0],
+		},
+	}
+
+	// We round trip via tuf.LoadKeyFromBytes so that the key ID is calculated
+	// the same way as for keys loaded from disk.
+	keyBytes, err := json.Marshal(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return tuf.LoadKeyFromBytes(keyBytes)
+}
+
+// IsSignature indicates if the signature uses the minisign or signify format.
+// It does not check that the signature is well formed.
+func IsSignature(signature []byte) bool {
+	return bytes.HasPrefix(bytes.TrimSpace(signature), []byte(UntrustedCommentPrefix))
+}
+
+// Verify verifies the minisign or signify signature over data using the key.
+// Signatures that are prehashed using BLAKE2b-512, the minisign default, are
+// supported in addition to signatures over the data itself. If the signature
+// includes a trusted comment, as is the case for all minisign signatures, the
+// global signature over the trusted comment is also verified. Malformed
+// signatures result in ErrInvalidMinisignSignature, while signatures created
+// using a different key result in ErrKeyMismatch.
+func Verify(key *tuf.Key, data, signature []byte) error {
+	if key.KeyType != signerverifier.MinisignKeyType {
+		return common.ErrUnknownKeyType
+	}
+
+	keyNum, publicKey, err := decodePublicKey(key.KeyVal.Public)
+	if err != nil {
+		return err
+	}
+
+	lines, err := readLines(signature)
+	if err != nil {
+		return errors.Join(ErrInvalidMinisignSignature, err)
+	}
+	if len(lines) != 2 && len(lines) != 4 {
+		return fmt.Errorf("%w: unexpected number of lines", ErrInvalidMinisignSignature)
+	}
+	if !strings.HasPrefix(lines[0], UntrustedCommentPrefix) {
+		return fmt.Errorf("%w: missing untrusted comment", ErrInvalidMinisignSignature)
+	}
+
+	sigBytes, err := base64.StdEncoding.DecodeString(lines[1])
+	if err != nil {
+		return errors.Join(ErrInvalidMinisignSignature, err)
+	}
+	if len(sigBytes) != signatureLength {
+		return fmt.Errorf("%w: unexpected signature length %d", ErrInvalidMinisignSignature, len(sigBytes))
+	}
+
+	algorithm := string(sigBytes[:2])
+	sigKeyNum := sigBytes[2 : 2+keyNumLength]
+	sig := sigBytes[2+keyNumLength:]
+
+	if !bytes.Equal(sigKeyNum, keyNum) {
+		return ErrKeyMismatch
+	}
+
+	switch algorithm {
+	case legacyAlgorithm:
+	case hashedAlgorithm:
+		digest := blake2b.Sum512(data)
+		data = digest[:]
+	default:
+		return fmt.Errorf("%w: unsupported algorithm '%s'", ErrInvalidMinisignSignature, algorithm)
+	}
+
+	if !ed25519.Verify(publicKey, data, sig) {
+		return common.ErrSignatureVerificationFailed
+	}
+
+	if len(lines) == 2 {
+		// signify signatures don't have a trusted comment
+		return nil
+	}
+
+	if !strings.HasPrefix(lines[2], trustedCommentPrefix) {
+		return fmt.Errorf("%w: missing trusted comment", ErrInvalidMinisignSignature)
+	}
+	trustedComment := strings.TrimPrefix(strings.TrimPrefix(lines[2], trustedCommentPrefix), " ")
+
+	globalSig, err := base64.StdEncoding.DecodeString(lines[3])
+	if err != nil {
+		return errors.Join(ErrInvalidMinisignSignature, err)
+	}
+	if len(globalSig) != ed25519.SignatureSize {
+		return fmt.Errorf("%w: unexpected global signature length %d", ErrInvalidMinisignSignature, len(globalSig))
+	}
+
+	if !ed25519.Verify(publicKey, append(append([]byte{}, sig...), []byte(trustedComment)...), globalSig) {
+		return common.ErrSignatureVerificationFailed
+	}
+
+	return nil
+}
+
+// decodePublicKey returns the key number and the Ed25519 public key encoded in
+// a minisign or signify public key.
+func decodePublicKey(encoded string) ([]byte, ed25519.PublicKey, error) {
+	keyBytes, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, nil, errors.Join(ErrInvalidMinisignPublicKey, err)
+	}
+	if len(keyBytes) != publicKeyLength {
+		return nil, nil, fmt.Errorf("%w: unexpected key length %d", ErrInvalidMinisignPublicKey, len(keyBytes))
+	}
+	// Public keys always use the legacy algorithm identifier, irrespective of
+	// whether signatures are prehashed
+	if algorithm := string(keyBytes[:2]); algorithm != legacyAlgorithm {
+		return nil, nil, fmt.Errorf("%w: unsupported algorithm '%s'", ErrInvalidMinisignPublicKey, algorithm)
+	}
+
+	return keyBytes[2 : 2+keyNumLength], ed25519.PublicKey(keyBytes[2+keyNumLength:]), nil
+}
+
+// readLines returns the non-empty lines in the contents.
+func readLines(contents []byte) ([]string, error) {
+	lines := []string{}
+
+	s := bufio.NewScanner(bytes.NewReader(contents))
+	for s.Scan() {
+		line := strings.TrimRight(s.Text(), "\r")
+		if len(strings.TrimSpace(line)) == 0 {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+
+	return lines, nil
+}
@@ -0,0 +1,194 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package minisign
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/signerverifier"
+	"github.com/gittuf/gittuf/internal/signerverifier/common"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/blake2b"
+)
+
+func TestLoadMinisignPublicKeyFromBytes(t *testing.T) {
+	t.Run("public key file", func(t *testing.T) {
+		keyBytes, err := os.ReadFile(filepath.Join("test-data", "minisign.pub"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		key, err := LoadMinisignPublicKeyFromBytes(keyBytes)
+		assert.Nil(t, err)
+		assert.Equal(t, "d3d1bb09c11d3f7ba2ba29088973175b077bee1d2a56c9372ff82e72cd496469", key.KeyID)
+		assert.Equal(t, signerverifier.MinisignKeyType, key.KeyType)
+		assert.Equal(t, ED25519Scheme, key.Scheme)
+		assert.Equal(t, strings.Split(strings.TrimSpace(string(keyBytes)), "\n")[1], key.KeyVal.Public)
+	})
+
+	t.Run("encoded key only", func(t *testing.T) {
+		_, _, publicKey := createTestKey(t)
+
+		key, err := LoadMinisignPublicKeyFromBytes([]byte(publicKey + "\n"))
+		assert.Nil(t, err)
+		assert.Equal(t, publicKey, key.KeyVal.Public)
+	})
+
+	t.Run("keys with the same key number", func(t *testing.T) {
+		_, keyNum, publicKey := createTestKey(t)
+		otherPublicKey, _, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		otherKeyBytes := append([]byte(legacyAlgorithm), keyNum...)
+		otherKeyBytes = append(otherKeyBytes, otherPublicKey...)
+
+		key, err := LoadMinisignPublicKeyFromBytes([]byte(publicKey))
+		assert.Nil(t, err)
+		otherKey, err := LoadMinisignPublicKeyFromBytes([]byte(base64.StdEncoding.EncodeToString(otherKeyBytes)))
+		assert.Nil(t, err)
+
+		// The key number is chosen by the key's creator, so it is not used
+		// to identify the key
+		assert.NotEqual(t, key.KeyID, otherKey.KeyID)
+	})
+
+	t.Run("invalid keys", func(t *testing.T) {
+		_, err := LoadMinisignPublicKeyFromBytes([]byte("untrusted comment: minisign public key\nnot base64!\n"))
+		assert.ErrorIs(t, err, ErrInvalidMinisignPublicKey)
+
+		_, err = LoadMinisignPublicKeyFromBytes([]byte(base64.StdEncoding.EncodeToString([]byte("too short"))))
+		assert.ErrorIs(t, err, ErrInvalidMinisignPublicKey)
+
+		_, err = LoadMinisignPublicKeyFromBytes([]byte("untrusted comment: minisign public key\n"))
+		assert.ErrorIs(t, err, ErrInvalidMinisignPublicKey)
+	})
+}
+
+func TestVerify(t *testing.T) {
+	privateKey, keyNum, publicKey := createTestKey(t)
+	key, err := LoadMinisignPublicKeyFromBytes([]byte(publicKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data := []byte("test data")
+
+	t.Run("minisign signature", func(t *testing.T) {
+		signature := createTestSignature(privateKey, keyNum, data, hashedAlgorithm, "timestamp:1700000000")
+
+		assert.True(t, IsSignature([]byte(signature)))
+		assert.Nil(t, Verify(key, data, []byte(signature)))
+	})
+
+	t.Run("legacy minisign signature", func(t *testing.T) {
+		signature := createTestSignature(privateKey, keyNum, data, legacyAlgorithm, "timestamp:1700000000")
+
+		assert.Nil(t, Verify(key, data, []byte(signature)))
+	})
+
+	t.Run("signify signature", func(t *testing.T) {
+		signature := createTestSignature(privateKey, keyNum, data, legacyAlgorithm, "")
+
+		assert.Nil(t, Verify(key, data, []byte(signature)))
+	})
+
+	t.Run("modified data", func(t *testing.T) {
+		signature := createTestSignature(privateKey, keyNum, data, hashedAlgorithm, "timestamp:1700000000")
+
+		err := Verify(key, []byte("modified data"), []byte(signature))
+		assert.ErrorIs(t, err, common.ErrSignatureVerificationFailed)
+	})
+
+	t.Run("modified trusted comment", func(t *testing.T) {
+		signature := createTestSignature(privateKey, keyNum, data, hashedAlgorithm, "timestamp:1700000000")
+		signature = strings.Replace(signature, "timestamp:1700000000", "timestamp:1800000000", 1)
+
+		err := Verify(key, data, []byte(signature))
+		assert.ErrorIs(t, err, common.ErrSignatureVerificationFailed)
+	})
+
+	t.Run("different key", func(t *testing.T) {
+		otherPrivateKey, otherKeyNum, _ := createTestKey(t)
+		signature := createTestSignature(otherPrivateKey, otherKeyNum, data, hashedAlgorithm, "timestamp:1700000000")
+
+		err := Verify(key, data, []byte(signature))
+		assert.ErrorIs(t, err, ErrKeyMismatch)
+	})
+
+	t.Run("malformed signatures", func(t *testing.T) {
+		signature := createTestSignature(privateKey, keyNum, data, hashedAlgorithm, "timestamp:1700000000")
+		lines := strings.Split(strings.TrimSpace(signature), "\n")
+
+		malformedSignatures := map[string]string{
+			"missing lines":             strings.Join(lines[:3], "\n"),
+			"missing untrusted comment": strings.Join(append([]string{"comment"}, lines[1:]...), "\n"),
+			"invalid encoding":          strings.Join([]string{lines[0], "not base64!", lines[2], lines[3]}, "\n"),
+			"invalid length":            strings.Join([]string{lines[0], base64.StdEncoding.EncodeToString([]byte("short")), lines[2], lines[3]}, "\n"),
+			"missing trusted comment":   strings.Join([]string{lines[0], lines[1], "timestamp:1700000000", lines[3]}, "\n"),
+			"unsupported algorithm":     createTestSignature(privateKey, keyNum, data, "EX", "timestamp:1700000000"),
+		}
+
+		for name, malformedSignature := range malformedSignatures {
+			err := Verify(key, data, []byte(malformedSignature))
+			assert.ErrorIs(t, err, ErrInvalidMinisignSignature, name)
+		}
+	})
+
+	t.Run("GPG signature", func(t *testing.T) {
+		signature := "-----BEGIN PGP SIGNATURE-----\n\nabcd\n-----END PGP SIGNATURE-----\n"
+
+		assert.False(t, IsSignature([]byte(signature)))
+		assert.ErrorIs(t, Verify(key, data, []byte(signature)), ErrInvalidMinisignSignature)
+	})
+}
+
+// createTestKey returns a new Ed25519 private key, its random key number, and
+// the corresponding encoded minisign public key.
+func createTestKey(t *testing.T) (ed25519.PrivateKey, []byte, string) {
+	t.Helper()
+
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyNum := make([]byte, keyNumLength)
+	if _, err := rand.Read(keyNum); err != nil {
+		t.Fatal(err)
+	}
+
+	keyBytes := append([]byte(legacyAlgorithm), keyNum...)
+	keyBytes = append(keyBytes, publicKey...)
+
+	return privateKey, keyNum, base64.StdEncoding.EncodeToString(keyBytes)
+}
+
+// createTestSignature returns a signature over data in the minisign format. If
+// the trusted comment is empty, the signature is in the signify format.
+func createTestSignature(privateKey ed25519.PrivateKey, keyNum, data []byte, algorithm, trustedComment string) string {
+	message := data
+	if algorithm == hashedAlgorithm {
+		digest := blake2b.Sum512(data)
+		message = digest[:]
+	}
+
+	sig := ed25519.Sign(privateKey, message)
+	sigBytes := append([]byte(algorithm), keyNum...)
+	sigBytes = append(sigBytes, sig...)
+
+	signature := fmt.Sprintf("untrusted comment: signature from test key\n%s\n", base64.StdEncoding.EncodeToString(sigBytes))
+	if len(trustedComment) == 0 {
+		return signature
+	}
+
+	globalSig := ed25519.Sign(privateKey, append(sig, []byte(trustedComment)...))
+	return signature + fmt.Sprintf("trusted comment: %s\n%s\n", trustedComment, base64.StdEncoding.EncodeToString(globalSig))
+}
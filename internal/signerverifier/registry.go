@@ -0,0 +1,79 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package signerverifier
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/gittuf/gittuf/internal/tuf"
+	"github.com/secure-systems-lab/go-securesystemslib/dsse"
+)
+
+var (
+	ErrKeyTypeAlreadyRegistered = errors.New("key type is already registered")
+	ErrInvalidKeyTypeFactory    = errors.New("key type must be named and have a factory")
+)
+
+// KeyTypeFactory returns a signer verifier for a key of a custom key type. The
+// returned verifier is used to verify DSSE envelopes as well as signatures on
+// Git commits and tags created with the key.
+type KeyTypeFactory func(key *tuf.Key) (dsse.SignerVerifier, error)
+
+var (
+	keyTypeRegistry     = map[string]KeyTypeFactory{}
+	keyTypeRegistryLock sync.RWMutex
+)
+
+// builtinKeyTypes contains the key types gittuf supports natively, which cannot
+// be overridden using RegisterKeyType.
+var builtinKeyTypes = map[string]bool{
+	ED25519KeyType:  true,
+	ECDSAKeyType:    true,
+	RSAKeyType:      true,
+	GPGKeyType:      true,
+	FulcioKeyType:   true,
+	MinisignKeyType: true,
+}
+
+// RegisterKeyType registers the factory used to create signer verifiers for
+// keys of the specified key type. This allows external code to add support for
+// key types gittuf does not support natively. NewSignerVerifierFromTUFKey and
+// the verification of Git signatures consult the registered factories for key
+// types they do not recognize. Built-in key types cannot be overridden, and
+// each key type can be registered only once.
+func RegisterKeyType(keyType string, factory KeyTypeFactory) error {
+	if len(keyType) == 0 || factory == nil {
+		return ErrInvalidKeyTypeFactory
+	}
+
+	if builtinKeyTypes[keyType] {
+		return fmt.Errorf("%w: '%s' is a built-in key type", ErrKeyTypeAlreadyRegistered, keyType)
+	}
+
+	keyTypeRegistryLock.Lock()
+	defer keyTypeRegistryLock.Unlock()
+
+	if _, has := keyTypeRegistry[keyType]; has {
+		return fmt.Errorf("%w: '%s'", ErrKeyTypeAlreadyRegistered, keyType)
+	}
+	keyTypeRegistry[keyType] = factory
+
+	return nil
+}
+
+// IsRegisteredKeyType returns true if a factory has been registered for the
+// key type using RegisterKeyType.
+func IsRegisteredKeyType(keyType string) bool {
+	_, has := getKeyTypeFactory(keyType)
+	return has
+}
+
+func getKeyTypeFactory(keyType string) (KeyTypeFactory, bool) {
+	keyTypeRegistryLock.RLock()
+	defer keyTypeRegistryLock.RUnlock()
+
+	factory, has := keyTypeRegistry[keyType]
+	return factory, has
+}
@@ -0,0 +1,131 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package signerverifier
+
+import (
+	"context"
+	"crypto"
+	"crypto/hmac"
+	"crypto/sha256"
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/signerverifier/common"
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	"github.com/gittuf/gittuf/internal/tuf"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+	sslibsv "github.com/secure-systems-lab/go-securesystemslib/signerverifier"
+	"github.com/stretchr/testify/assert"
+)
+
+const toyKeyType = "toy-hmac"
+
+// toySignerVerifier implements a toy signing scheme where the signature is an
+// HMAC of the data keyed using the key's public value.
+type toySignerVerifier struct {
+	key *tuf.Key
+}
+
+func (sv *toySignerVerifier) Sign(_ context.Context, data []byte) ([]byte, error) {
+	mac := hmac.New(sha256.New, []byte(sv.key.KeyVal.Public))
+	mac.Write(data)
+	return mac.Sum(nil), nil
+}
+
+func (sv *toySignerVerifier) Verify(ctx context.Context, data, sig []byte) error {
+	expectedSig, err := sv.Sign(ctx, data)
+	if err != nil {
+		return err
+	}
+	if !hmac.Equal(expectedSig, sig) {
+		return common.ErrSignatureVerificationFailed
+	}
+	return nil
+}
+
+func (sv *toySignerVerifier) KeyID() (string, error) {
+	return sv.key.KeyID, nil
+}
+
+func (sv *toySignerVerifier) Public() crypto.PublicKey {
+	return []byte(sv.key.KeyVal.Public)
+}
+
+func newToySignerVerifier(key *tuf.Key) (sslibdsse.SignerVerifier, error) {
+	return &toySignerVerifier{key: key}, nil
+}
+
+func TestRegisterKeyType(t *testing.T) {
+	t.Cleanup(func() {
+		keyTypeRegistryLock.Lock()
+		defer keyTypeRegistryLock.Unlock()
+		delete(keyTypeRegistry, toyKeyType)
+	})
+
+	key := &tuf.Key{
+		KeyType: toyKeyType,
+		Scheme:  toyKeyType,
+		KeyID:   "toy-key",
+		KeyVal:  sslibsv.KeyVal{Public: "toy-secret"},
+	}
+
+	t.Run("unregistered key type", func(t *testing.T) {
+		assert.False(t, IsRegisteredKeyType(toyKeyType))
+
+		_, err := NewSignerVerifierFromTUFKey(key)
+		assert.ErrorIs(t, err, common.ErrUnknownKeyType)
+	})
+
+	t.Run("invalid registrations", func(t *testing.T) {
+		err := RegisterKeyType("", newToySignerVerifier)
+		assert.ErrorIs(t, err, ErrInvalidKeyTypeFactory)
+
+		err = RegisterKeyType(toyKeyType, nil)
+		assert.ErrorIs(t, err, ErrInvalidKeyTypeFactory)
+
+		err = RegisterKeyType(ED25519KeyType, newToySignerVerifier)
+		assert.ErrorIs(t, err, ErrKeyTypeAlreadyRegistered)
+
+		err = RegisterKeyType(GPGKeyType, newToySignerVerifier)
+		assert.ErrorIs(t, err, ErrKeyTypeAlreadyRegistered)
+	})
+
+	t.Run("register and verify envelope", func(t *testing.T) {
+		err := RegisterKeyType(toyKeyType, newToySignerVerifier)
+		assert.Nil(t, err)
+		assert.True(t, IsRegisteredKeyType(toyKeyType))
+
+		err = RegisterKeyType(toyKeyType, newToySignerVerifier)
+		assert.ErrorIs(t, err, ErrKeyTypeAlreadyRegistered)
+
+		sv, err := NewSignerVerifierFromTUFKey(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		env, err := dsse.CreateEnvelope(map[string]string{"hello": "world"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		env, err = dsse.SignEnvelope(context.Background(), env, sv)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = dsse.VerifyEnvelope(context.Background(), env, []sslibdsse.Verifier{sv}, 1)
+		assert.Nil(t, err)
+
+		// A key of the same type with a different value must not verify the
+		// envelope
+		otherSV, err := NewSignerVerifierFromTUFKey(&tuf.Key{
+			KeyType: toyKeyType,
+			Scheme:  toyKeyType,
+			KeyID:   "toy-key",
+			KeyVal:  sslibsv.KeyVal{Public: "other-secret"},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = dsse.VerifyEnvelope(context.Background(), env, []sslibdsse.Verifier{otherSV}, 1)
+		assert.NotNil(t, err)
+	})
+}
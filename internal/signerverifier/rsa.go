@@ -0,0 +1,131 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package signerverifier
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/gittuf/gittuf/internal/tuf"
+	"github.com/secure-systems-lab/go-securesystemslib/dsse"
+	sslibsv "github.com/secure-systems-lab/go-securesystemslib/signerverifier"
+)
+
+// DefaultMinimumRSAKeySize is the smallest RSA key, in bits, accepted unless
+// configured otherwise using SetMinimumRSAKeySize.
+const DefaultMinimumRSAKeySize = 2048
+
+var (
+	ErrRSAKeyTooSmall           = errors.New("RSA key is smaller than the minimum accepted size")
+	ErrInvalidRSAKey            = errors.New("key is not a valid RSA key")
+	ErrInvalidMinimumRSAKeySize = errors.New("minimum RSA key size must be positive")
+)
+
+var (
+	minimumRSAKeySize     = DefaultMinimumRSAKeySize
+	minimumRSAKeySizeLock sync.RWMutex
+)
+
+// SetMinimumRSAKeySize configures the smallest RSA key, in bits, that is
+// accepted for signing and verification. This applies to RSA keys in the
+// securesystemslib format as well as RSA GPG keys used to sign Git objects.
+func SetMinimumRSAKeySize(bits int) error {
+	if bits <= 0 {
+		return ErrInvalidMinimumRSAKeySize
+	}
+
+	minimumRSAKeySizeLock.Lock()
+	defer minimumRSAKeySizeLock.Unlock()
+
+	minimumRSAKeySize = bits
+	return nil
+}
+
+// GetMinimumRSAKeySize returns the smallest RSA key, in bits, that is accepted
+// for signing and verification.
+func GetMinimumRSAKeySize() int {
+	minimumRSAKeySizeLock.RLock()
+	defer minimumRSAKeySizeLock.RUnlock()
+
+	return minimumRSAKeySize
+}
+
+// CheckRSAKeySize returns ErrRSAKeyTooSmall if an RSA key of the specified
+// size, in bits, must not be accepted.
+func CheckRSAKeySize(bits int) error {
+	if minimum := GetMinimumRSAKeySize(); bits < minimum {
+		return fmt.Errorf("%w: key has %d bits, minimum is %d bits", ErrRSAKeyTooSmall, bits, minimum)
+	}
+
+	return nil
+}
+
+// newRSASignerVerifier returns an RSA-PSS signer verifier for the key after
+// checking that the key's public and private portions, if present, are RSA
+// keys of an acceptable size.
+func newRSASignerVerifier(key *tuf.Key) (dsse.SignerVerifier, error) {
+	publicKey, err := parseRSAPublicKey(key.KeyVal.Public)
+	if err != nil {
+		return nil, err
+	}
+	if err := CheckRSAKeySize(publicKey.N.BitLen()); err != nil {
+		return nil, err
+	}
+
+	if len(key.KeyVal.Private) > 0 {
+		if err := checkRSAPrivateKey(key.KeyVal.Private); err != nil {
+			return nil, err
+		}
+	}
+
+	return sslibsv.NewRSAPSSSignerVerifierFromSSLibKey(key)
+}
+
+func parseRSAPublicKey(contents string) (*rsa.PublicKey, error) {
+	block, _ := pem.Decode([]byte(contents))
+	if block == nil {
+		return nil, fmt.Errorf("%w: public key is not PEM encoded", ErrInvalidRSAKey)
+	}
+
+	if publicKey, err := x509.ParsePKCS1PublicKey(block.Bytes); err == nil {
+		return publicKey, nil
+	}
+
+	publicKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, errors.Join(ErrInvalidRSAKey, err)
+	}
+
+	rsaPublicKey, ok := publicKey.(*rsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("%w: public key is of type %T", ErrInvalidRSAKey, publicKey)
+	}
+
+	return rsaPublicKey, nil
+}
+
+func checkRSAPrivateKey(contents string) error {
+	block, _ := pem.Decode([]byte(contents))
+	if block == nil {
+		return fmt.Errorf("%w: private key is not PEM encoded", ErrInvalidRSAKey)
+	}
+
+	if _, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return nil
+	}
+
+	privateKey, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return errors.Join(ErrInvalidRSAKey, err)
+	}
+
+	if _, ok := privateKey.(*rsa.PrivateKey); !ok {
+		return fmt.Errorf("%w: private key is of type %T", ErrInvalidRSAKey, privateKey)
+	}
+
+	return nil
+}
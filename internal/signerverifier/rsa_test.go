@@ -0,0 +1,147 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package signerverifier
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	"github.com/gittuf/gittuf/internal/tuf"
+	sslibdsse "github.com/secure-systems-lab/go-securesystemslib/dsse"
+	sslibsv "github.com/secure-systems-lab/go-securesystemslib/signerverifier"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewSignerVerifierFromTUFKeyWithRSAKey(t *testing.T) {
+	for _, bits := range []int{2048, 3072, 4096} {
+		t.Run(fmt.Sprintf("%d bits", bits), func(t *testing.T) {
+			privateKey, err := rsa.GenerateKey(rand.Reader, bits)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			signer, err := NewSignerVerifierFromTUFKey(createTestRSAKey(t, privateKey, true))
+			if err != nil {
+				t.Fatal(err)
+			}
+			verifier, err := NewSignerVerifierFromTUFKey(createTestRSAKey(t, privateKey, false))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			env, err := dsse.CreateEnvelope(map[string]string{"hello": "world"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			env, err = dsse.SignEnvelope(context.Background(), env, signer)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = dsse.VerifyEnvelope(context.Background(), env, []sslibdsse.Verifier{verifier}, 1)
+			assert.Nil(t, err)
+		})
+	}
+
+	t.Run("key below minimum size", func(t *testing.T) {
+		privateKey, err := rsa.GenerateKey(rand.Reader, 1024)
+		if err != nil {
+			t.Fatal(err)
+		}
+		key := createTestRSAKey(t, privateKey, false)
+
+		_, err = NewSignerVerifierFromTUFKey(key)
+		assert.ErrorIs(t, err, ErrRSAKeyTooSmall)
+
+		if err := SetMinimumRSAKeySize(1024); err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() {
+			SetMinimumRSAKeySize(DefaultMinimumRSAKeySize) //nolint:errcheck
+		})
+
+		_, err = NewSignerVerifierFromTUFKey(key)
+		assert.Nil(t, err)
+	})
+
+	t.Run("non-RSA key with RSA key type", func(t *testing.T) {
+		privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		publicKeyBytes, err := x509.MarshalPKIXPublicKey(privateKey.Public())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = NewSignerVerifierFromTUFKey(&tuf.Key{
+			KeyType: RSAKeyType,
+			Scheme:  sslibsv.RSAKeyScheme,
+			KeyVal: sslibsv.KeyVal{
+				Public: string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicKeyBytes})),
+			},
+		})
+		assert.ErrorIs(t, err, ErrInvalidRSAKey)
+	})
+}
+
+func TestSetMinimumRSAKeySize(t *testing.T) {
+	assert.Equal(t, DefaultMinimumRSAKeySize, GetMinimumRSAKeySize())
+
+	err := SetMinimumRSAKeySize(0)
+	assert.ErrorIs(t, err, ErrInvalidMinimumRSAKeySize)
+	assert.Equal(t, DefaultMinimumRSAKeySize, GetMinimumRSAKeySize())
+
+	err = SetMinimumRSAKeySize(3072)
+	assert.Nil(t, err)
+	t.Cleanup(func() {
+		SetMinimumRSAKeySize(DefaultMinimumRSAKeySize) //nolint:errcheck
+	})
+
+	assert.ErrorIs(t, CheckRSAKeySize(2048), ErrRSAKeyTooSmall)
+	assert.Nil(t, CheckRSAKeySize(3072))
+	assert.Nil(t, CheckRSAKeySize(4096))
+}
+
+// createTestRSAKey returns the key in the securesystemslib format, loaded using
+// tuf.LoadKeyFromBytes as a key stored on disk would be.
+func createTestRSAKey(t *testing.T, privateKey *rsa.PrivateKey, includePrivate bool) *tuf.Key {
+	t.Helper()
+
+	publicKeyBytes, err := x509.MarshalPKIXPublicKey(privateKey.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key := &tuf.Key{
+		KeyType:             RSAKeyType,
+		Scheme:              sslibsv.RSAKeyScheme,
+		KeyIDHashAlgorithms: []string{"sha256", "sha512"},
+		KeyVal: sslibsv.KeyVal{
+			Public: string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicKeyBytes})),
+		},
+	}
+	if includePrivate {
+		key.KeyVal.Private = string(pem.EncodeToMemory(&pem.Block{Type: sslibsv.RSAPrivateKeyPEM, Bytes: x509.MarshalPKCS1PrivateKey(privateKey)}))
+	}
+
+	keyBytes, err := json.Marshal(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	loadedKey, err := tuf.LoadKeyFromBytes(keyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return loadedKey
+}
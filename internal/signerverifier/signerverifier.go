@@ -3,6 +3,7 @@
 package signerverifier
 
 import (
+	"github.com/gittuf/gittuf/internal/signerverifier/age"
 	"github.com/gittuf/gittuf/internal/signerverifier/common"
 	"github.com/gittuf/gittuf/internal/tuf"
 	"github.com/secure-systems-lab/go-securesystemslib/dsse"
@@ -10,14 +11,19 @@ import (
 )
 
 const (
-	ED25519KeyType = sslibsv.ED25519KeyType
-	ECDSAKeyType   = sslibsv.ECDSAKeyType
-	RSAKeyType     = sslibsv.RSAKeyType
-	GPGKeyType     = "gpg"
-	FulcioKeyType  = "sigstore-oidc"
-	RekorServer    = "https://rekor.sigstore.dev"
+	ED25519KeyType  = sslibsv.ED25519KeyType
+	ECDSAKeyType    = sslibsv.ECDSAKeyType
+	RSAKeyType      = sslibsv.RSAKeyType
+	GPGKeyType      = "gpg"
+	FulcioKeyType   = "sigstore-oidc"
+	MinisignKeyType = "minisign"
+	RekorServer     = "https://rekor.sigstore.dev"
 )
 
+// NewSignerVerifierFromTUFKey returns a signer verifier for the key. Key types
+// that are not supported natively are looked up in the factories registered
+// using RegisterKeyType. RSA keys smaller than the configured minimum size are
+// rejected with ErrRSAKeyTooSmall, see SetMinimumRSAKeySize.
 func NewSignerVerifierFromTUFKey(key *tuf.Key) (dsse.SignerVerifier, error) {
 	switch key.KeyType {
 	case ED25519KeyType:
@@ -25,16 +31,32 @@ func NewSignerVerifierFromTUFKey(key *tuf.Key) (dsse.SignerVerifier, error) {
 	case ECDSAKeyType:
 		return sslibsv.NewECDSASignerVerifierFromSSLibKey(key)
 	case RSAKeyType:
-		return sslibsv.NewRSAPSSSignerVerifierFromSSLibKey(key)
+		return newRSASignerVerifier(key)
 	}
+
+	if factory, has := getKeyTypeFactory(key.KeyType); has {
+		return factory(key)
+	}
+
 	return nil, common.ErrUnknownKeyType
 }
 
 func NewSignerVerifierFromSecureSystemsLibFormat(keyContents []byte) (dsse.SignerVerifier, error) {
-	key, err := tuf.LoadKeyFromBytes(keyContents)
+	key, err := LoadKeyFromBytes(keyContents)
 	if err != nil {
 		return nil, err
 	}
 
 	return NewSignerVerifierFromTUFKey(key)
 }
+
+// LoadKeyFromBytes is similar to tuf.LoadKeyFromBytes, but it also supports
+// age identities. For an age identity, the returned key is the Ed25519 key
+// derived from the identity for signing.
+func LoadKeyFromBytes(keyContents []byte) (*tuf.Key, error) {
+	if age.IsIdentity(keyContents) {
+		return age.LoadIdentity(keyContents)
+	}
+
+	return tuf.LoadKeyFromBytes(keyContents)
+}
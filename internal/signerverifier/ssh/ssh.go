@@ -0,0 +1,171 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ssh
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"strings"
+
+	"github.com/gittuf/gittuf/internal/signerverifier"
+	"github.com/gittuf/gittuf/internal/signerverifier/common"
+	"github.com/gittuf/gittuf/internal/tuf"
+	sslibsv "github.com/secure-systems-lab/go-securesystemslib/signerverifier"
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	ED25519Scheme         = "ed25519"
+	ECDSAP256Scheme       = "ecdsa-sha2-nistp256"
+	ECDSAP384Scheme       = "ecdsa-sha2-nistp384"
+	RSAScheme             = "rsassa-pss-sha256"
+	publicKeyPEMBlockType = "PUBLIC KEY"
+)
+
+var ErrNoSSHKeysFound = errors.New("no SSH public keys found")
+
+// LoadSSHPublicKeyFromBytes returns a tuf.Key for an SSH public key passed in
+// using the OpenSSH authorized keys format, i.e., a line such as
+// "ssh-ed25519 AAAA... comment". The returned tuf.Key uses the same key ID as a
+// key loaded via tuf.LoadKeyFromBytes.
+func LoadSSHPublicKeyFromBytes(contents []byte) (*tuf.Key, error) {
+	keys, err := LoadSSHAllowedSigners(contents)
+	if err != nil {
+		return nil, err
+	}
+
+	return keys[0], nil
+}
+
+// LoadSSHAllowedSigners returns a tuf.Key for each SSH public key in the
+// contents. The contents may be an OpenSSH allowed signers file, where each
+// line has the form "principals [options] keytype key [comment]", or a set of
+// OpenSSH public key lines. Empty lines and comments are ignored.
+func LoadSSHAllowedSigners(contents []byte) ([]*tuf.Key, error) {
+	keys := []*tuf.Key{}
+
+	s := bufio.NewScanner(bytes.NewReader(contents))
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		if len(line) == 0 || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		sshKey, err := parseAllowedSignersLine(line)
+		if err != nil {
+			return nil, err
+		}
+
+		key, err := newKeyFromSSHPublicKey(sshKey)
+		if err != nil {
+			return nil, err
+		}
+
+		keys = append(keys, key)
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(keys) == 0 {
+		return nil, ErrNoSSHKeysFound
+	}
+
+	return keys, nil
+}
+
+// parseAllowedSignersLine identifies the public key in a single line of an
+// allowed signers file. As the principals and options fields are optional, we
+// skip fields until the remainder of the line parses as an OpenSSH public key.
+func parseAllowedSignersLine(line string) (ssh.PublicKey, error) {
+	fields := strings.Fields(line)
+
+	var err error
+	for i := range fields {
+		var sshKey ssh.PublicKey
+		sshKey, _, _, _, err = ssh.ParseAuthorizedKey([]byte(strings.Join(fields[i:], " ")))
+		if err == nil {
+			return sshKey, nil
+		}
+	}
+
+	return nil, err
+}
+
+// newKeyFromSSHPublicKey converts an SSH public key into the custom
+// securesystemslib format used for tuf.Key.
+func newKeyFromSSHPublicKey(sshKey ssh.PublicKey) (*tuf.Key, error) {
+	cryptoPublicKey, ok := sshKey.(ssh.CryptoPublicKey)
+	if !ok {
+		return nil, common.ErrUnknownKeyType
+	}
+
+	var (
+		keyType string
+		scheme  string
+		public  string
+		err     error
+	)
+	switch k := cryptoPublicKey.CryptoPublicKey().(type) {
+	case ed25519.PublicKey:
+		keyType = signerverifier.ED25519KeyType
+		scheme = ED25519Scheme
+		public = hex.EncodeToString(k)
+	case *ecdsa.PublicKey:
+		switch k.Curve {
+		case elliptic.P256():
+			scheme = ECDSAP256Scheme
+		case elliptic.P384():
+			scheme = ECDSAP384Scheme
+		default:
+			return nil, common.ErrUnknownKeyType
+		}
+		keyType = signerverifier.ECDSAKeyType
+		public, err = encodePublicKeyAsPEM(k)
+	case *rsa.PublicKey:
+		keyType = signerverifier.RSAKeyType
+		scheme = RSAScheme
+		public, err = encodePublicKeyAsPEM(k)
+	default:
+		return nil, common.ErrUnknownKeyType
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	key := &tuf.Key{
+		KeyType:             keyType,
+		Scheme:              scheme,
+		KeyIDHashAlgorithms: []string{"sha256", "sha512"},
+		KeyVal: sslibsv.KeyVal{
+			Public: public,
+		},
+	}
+
+	// We round trip via tuf.LoadKeyFromBytes so that the key ID is calculated
+	// the same way as for keys loaded from disk.
+	keyBytes, err := json.Marshal(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return tuf.LoadKeyFromBytes(keyBytes)
+}
+
+func encodePublicKeyAsPEM(publicKey any) (string, error) {
+	der, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(pem.EncodeToMemory(&pem.Block{Type: publicKeyPEMBlockType, Bytes: der}))), nil
+}
@@ -0,0 +1,92 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ssh
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/signerverifier"
+	"github.com/stretchr/testify/assert"
+)
+
+const ed25519PublicKeyHex = "bb589c90f2dd205d39bb544d4360f7a562b3414f3a96b1c03786a225086be7a9"
+
+func TestLoadSSHPublicKeyFromBytes(t *testing.T) {
+	t.Run("ed25519 key", func(t *testing.T) {
+		keyBytes, err := os.ReadFile(filepath.Join("test-data", "ed25519.pub"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		key, err := LoadSSHPublicKeyFromBytes(keyBytes)
+		assert.Nil(t, err)
+		assert.Equal(t, signerverifier.ED25519KeyType, key.KeyType)
+		assert.Equal(t, ED25519Scheme, key.Scheme)
+		assert.Equal(t, ed25519PublicKeyHex, key.KeyVal.Public)
+		assert.NotEmpty(t, key.KeyID)
+
+		// The key ID must be stable
+		keyAgain, err := LoadSSHPublicKeyFromBytes(keyBytes)
+		assert.Nil(t, err)
+		assert.Equal(t, key.KeyID, keyAgain.KeyID)
+	})
+
+	t.Run("ecdsa key", func(t *testing.T) {
+		keyBytes, err := os.ReadFile(filepath.Join("test-data", "ecdsa.pub"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		key, err := LoadSSHPublicKeyFromBytes(keyBytes)
+		assert.Nil(t, err)
+		assert.Equal(t, signerverifier.ECDSAKeyType, key.KeyType)
+		assert.Equal(t, ECDSAP256Scheme, key.Scheme)
+		assert.True(t, strings.HasPrefix(key.KeyVal.Public, "-----BEGIN PUBLIC KEY-----"))
+		assert.NotEmpty(t, key.KeyID)
+	})
+
+	t.Run("invalid key", func(t *testing.T) {
+		_, err := LoadSSHPublicKeyFromBytes([]byte("ssh-ed25519 not-a-key"))
+		assert.NotNil(t, err)
+	})
+
+	t.Run("no key", func(t *testing.T) {
+		_, err := LoadSSHPublicKeyFromBytes([]byte("# just a comment\n"))
+		assert.ErrorIs(t, err, ErrNoSSHKeysFound)
+	})
+}
+
+func TestLoadSSHAllowedSigners(t *testing.T) {
+	allowedSignersBytes, err := os.ReadFile(filepath.Join("test-data", "allowed_signers"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ed25519KeyBytes, err := os.ReadFile(filepath.Join("test-data", "ed25519.pub"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecdsaKeyBytes, err := os.ReadFile(filepath.Join("test-data", "ecdsa.pub"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ed25519Key, err := LoadSSHPublicKeyFromBytes(ed25519KeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecdsaKey, err := LoadSSHPublicKeyFromBytes(ecdsaKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keys, err := LoadSSHAllowedSigners(allowedSignersBytes)
+	assert.Nil(t, err)
+	if assert.Len(t, keys, 2) {
+		assert.Equal(t, ed25519Key, keys[0])
+		assert.Equal(t, ecdsaKey, keys[1])
+		assert.NotEqual(t, keys[0].KeyID, keys[1].KeyID)
+	}
+}
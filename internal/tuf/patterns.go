@@ -0,0 +1,165 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package tuf
+
+import (
+	"path"
+)
+
+// CoversPattern checks if every target matched by the pattern is also matched
+// by one of the delegation's path patterns. Patterns use the syntax of
+// path.Match. The check is conservative: if it cannot be determined that the
+// pattern is covered, e.g., because the pattern uses a character class that
+// does not appear in the delegation's patterns, false is returned.
+func (d *Delegation) CoversPattern(pattern string) bool {
+	return patternsCover(d.Paths, pattern)
+}
+
+// CoversRefPattern checks if every ref matched by the ref pattern is also
+// matched by one of the delegation's ref patterns. A delegation that is not
+// restricted to certain refs covers all ref patterns.
+func (d *Delegation) CoversRefPattern(pattern string) bool {
+	if len(d.Refs) == 0 {
+		return true
+	}
+	return patternsCover(d.Refs, pattern)
+}
+
+// patternsCover checks if one of the patterns covers the pattern.
+func patternsCover(patterns []string, pattern string) bool {
+	subTokens, ok := tokenizePattern(pattern)
+	if !ok {
+		return false
+	}
+
+	for _, candidate := range patterns {
+		tokens, ok := tokenizePattern(candidate)
+		if !ok {
+			continue
+		}
+
+		if tokensCover(tokens, subTokens) {
+			return true
+		}
+	}
+
+	return false
+}
+
+const (
+	literalToken = iota
+	anyCharToken
+	anyStringToken
+	charClassToken
+)
+
+// patternToken is a single element of a path.Match pattern. A literal token
+// records the character it matches, while a character class token records
+// the class as written in the pattern.
+type patternToken struct {
+	kind  int
+	value string
+}
+
+// tokenizePattern splits the pattern into tokens. If the pattern is malformed,
+// false is returned.
+func tokenizePattern(pattern string) ([]patternToken, bool) {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, false
+	}
+
+	chars := []rune(pattern)
+	tokens := []patternToken{}
+	for i := 0; i < len(chars); i++ {
+		switch chars[i] {
+		case '*':
+			tokens = append(tokens, patternToken{kind: anyStringToken})
+		case '?':
+			tokens = append(tokens, patternToken{kind: anyCharToken})
+		case '\\':
+			if i+1 >= len(chars) {
+				return nil, false
+			}
+			i++
+			tokens = append(tokens, patternToken{kind: literalToken, value: string(chars[i])})
+		case '[':
+			end := i + 1
+			for end < len(chars) && chars[end] != ']' {
+				if chars[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(chars) {
+				return nil, false
+			}
+			tokens = append(tokens, patternToken{kind: charClassToken, value: string(chars[i : end+1])})
+			i = end
+		default:
+			tokens = append(tokens, patternToken{kind: literalToken, value: string(chars[i])})
+		}
+	}
+
+	return tokens, true
+}
+
+// tokensCover checks if every string matched by the tokens of the sub-pattern
+// is also matched by the tokens of the pattern.
+func tokensCover(tokens, subTokens []patternToken) bool {
+	seen := map[[2]int]bool{}
+
+	var covers func(i, j int) bool
+	covers = func(i, j int) bool {
+		if i == len(tokens) {
+			return j == len(subTokens)
+		}
+
+		key := [2]int{i, j}
+		if covered, has := seen[key]; has {
+			return covered
+		}
+
+		covered := false
+		token := tokens[i]
+		switch token.kind {
+		case anyStringToken:
+			// The wildcard matches nothing, or absorbs the next element of
+			// the sub-pattern, which must not match a separator
+			covered = covers(i+1, j) || (j < len(subTokens) && !mayMatchSeparator(subTokens[j]) && covers(i, j+1))
+		case anyCharToken:
+			// The sub-pattern's element must match exactly one character
+			// other than a separator
+			covered = j < len(subTokens) && subTokens[j].kind != anyStringToken && !mayMatchSeparator(subTokens[j]) && covers(i+1, j+1)
+		case literalToken:
+			covered = j < len(subTokens) && subTokens[j] == token && covers(i+1, j+1)
+		case charClassToken:
+			if j < len(subTokens) {
+				subToken := subTokens[j]
+				matches := subToken == token
+				if subToken.kind == literalToken {
+					matches, _ = path.Match(token.value, subToken.value)
+				}
+				covered = matches && covers(i+1, j+1)
+			}
+		}
+
+		seen[key] = covered
+		return covered
+	}
+
+	return covers(0, 0)
+}
+
+// mayMatchSeparator checks if the token may match a path separator. Unlike
+// wildcards, character classes such as [^a] can match a separator.
+func mayMatchSeparator(token patternToken) bool {
+	switch token.kind {
+	case literalToken:
+		return token.value == "/"
+	case charClassToken:
+		matches, _ := path.Match(token.value, "/")
+		return matches
+	default:
+		return false
+	}
+}
@@ -0,0 +1,120 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package tuf
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDelegationCoversPattern(t *testing.T) {
+	tests := map[string]struct {
+		paths    []string
+		pattern  string
+		expected bool
+	}{
+		"identical pattern": {
+			paths:    []string{"file:src/*"},
+			pattern:  "file:src/*",
+			expected: true,
+		},
+		"literal path": {
+			paths:    []string{"file:src/*"},
+			pattern:  "file:src/main.go",
+			expected: true,
+		},
+		"narrower wildcard": {
+			paths:    []string{"file:src/*"},
+			pattern:  "file:src/*.go",
+			expected: true,
+		},
+		"single character wildcard": {
+			paths:    []string{"file:src/*"},
+			pattern:  "file:src/?.go",
+			expected: true,
+		},
+		"character class": {
+			paths:    []string{"file:src/[a-c]*"},
+			pattern:  "file:src/b.go",
+			expected: true,
+		},
+		"identical character class": {
+			paths:    []string{"file:src/[a-c]*"},
+			pattern:  "file:src/[a-c].go",
+			expected: true,
+		},
+		"covered by second pattern": {
+			paths:    []string{"file:docs/*", "file:src/*/*"},
+			pattern:  "file:src/pkg/*.go",
+			expected: true,
+		},
+		"ref pattern": {
+			paths:    []string{"git:refs/heads/*"},
+			pattern:  "git:refs/heads/main",
+			expected: true,
+		},
+		"different path": {
+			paths:    []string{"file:src/*"},
+			pattern:  "file:docs/*",
+			expected: false,
+		},
+		"broader wildcard": {
+			paths:    []string{"file:src/*.go"},
+			pattern:  "file:src/*",
+			expected: false,
+		},
+		"wildcard does not cross separator": {
+			paths:    []string{"file:src/*"},
+			pattern:  "file:src/pkg/main.go",
+			expected: false,
+		},
+		"single character wildcard does not cover wildcard": {
+			paths:    []string{"file:src/?"},
+			pattern:  "file:src/*",
+			expected: false,
+		},
+		"character class outside class": {
+			paths:    []string{"file:src/[a-c]*"},
+			pattern:  "file:src/d.go",
+			expected: false,
+		},
+		"different character class": {
+			paths:    []string{"file:src/[a-c]*"},
+			pattern:  "file:src/[a-d].go",
+			expected: false,
+		},
+		"character class matching separator": {
+			paths:    []string{"file:src*"},
+			pattern:  "file:src[^a]main.go",
+			expected: false,
+		},
+		"malformed pattern": {
+			paths:    []string{"file:src/*"},
+			pattern:  "file:src/[a",
+			expected: false,
+		},
+		"different namespace": {
+			paths:    []string{"file:*"},
+			pattern:  "git:refs/heads/main",
+			expected: false,
+		},
+	}
+
+	for name, test := range tests {
+		delegation := &Delegation{Paths: test.paths}
+		assert.Equal(t, test.expected, delegation.CoversPattern(test.pattern), fmt.Sprintf("unexpected result in test '%s'", name))
+	}
+}
+
+func TestDelegationCoversRefPattern(t *testing.T) {
+	delegation := &Delegation{Paths: []string{"file:src/*"}}
+	assert.True(t, delegation.CoversRefPattern("refs/heads/*"))
+
+	delegation.Refs = []string{"refs/heads/main", "refs/heads/release/*"}
+	assert.True(t, delegation.CoversRefPattern("refs/heads/main"))
+	assert.True(t, delegation.CoversRefPattern("refs/heads/release/v1"))
+	assert.False(t, delegation.CoversRefPattern("refs/heads/*"))
+	assert.False(t, delegation.CoversRefPattern("refs/heads/feature"))
+}
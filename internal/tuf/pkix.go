@@ -0,0 +1,133 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package tuf
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/secure-systems-lab/go-securesystemslib/signerverifier"
+)
+
+const (
+	ed25519Scheme   = "ed25519"
+	ecdsaP256Scheme = "ecdsa-sha2-nistp256"
+	ecdsaP384Scheme = "ecdsa-sha2-nistp384"
+
+	publicKeyPEMBlockType    = "PUBLIC KEY"
+	rsaPublicKeyPEMBlockType = "RSA PUBLIC KEY"
+)
+
+var (
+	ErrUnsupportedPublicKey = errors.New("public key is not an Ed25519, ECDSA (P-256 or P-384), or RSA key")
+	ErrInvalidPublicKey     = errors.New("contents are not a PEM or DER encoded public key")
+)
+
+// NewKeyFromPublicKey returns a Key in the custom securesystemslib format for
+// the Ed25519, ECDSA, or RSA public key. The key's public portion is encoded
+// the same way irrespective of how the key was originally encoded, so the same
+// key always has the same key ID.
+func NewKeyFromPublicKey(publicKey crypto.PublicKey) (*Key, error) {
+	var (
+		keyType string
+		scheme  string
+		public  string
+		err     error
+	)
+	switch k := publicKey.(type) {
+	case ed25519.PublicKey:
+		keyType = signerverifier.ED25519KeyType
+		scheme = ed25519Scheme
+		public = hex.EncodeToString(k)
+	case *ecdsa.PublicKey:
+		switch k.Curve {
+		case elliptic.P256():
+			scheme = ecdsaP256Scheme
+		case elliptic.P384():
+			scheme = ecdsaP384Scheme
+		default:
+			return nil, fmt.Errorf("%w: unsupported curve '%s'", ErrUnsupportedPublicKey, k.Curve.Params().Name)
+		}
+		keyType = signerverifier.ECDSAKeyType
+		public, err = encodePublicKeyAsPEM(k)
+	case *rsa.PublicKey:
+		keyType = signerverifier.RSAKeyType
+		scheme = signerverifier.RSAKeyScheme
+		public, err = encodePublicKeyAsPEM(k)
+	default:
+		return nil, fmt.Errorf("%w: key is of type %T", ErrUnsupportedPublicKey, publicKey)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	key := &Key{
+		KeyType:             keyType,
+		Scheme:              scheme,
+		KeyIDHashAlgorithms: []string{"sha256", "sha512"},
+		KeyVal: signerverifier.KeyVal{
+			Public: public,
+		},
+	}
+
+	keyID, err := calculateKeyID(key)
+	if err != nil {
+		return nil, err
+	}
+	key.KeyID = keyID
+
+	return key, nil
+}
+
+// loadKeyFromPEMOrDER returns a Key for a public key encoded as a PEM block or
+// as raw DER. Both SubjectPublicKeyInfo and PKCS #1 RSA public keys are
+// supported.
+func loadKeyFromPEMOrDER(contents []byte) (*Key, error) {
+	der := contents
+	if block, _ := pem.Decode(contents); block != nil {
+		switch block.Type {
+		case publicKeyPEMBlockType, rsaPublicKeyPEMBlockType:
+			der = block.Bytes
+		default:
+			return nil, fmt.Errorf("%w: unexpected PEM block '%s'", ErrInvalidPublicKey, block.Type)
+		}
+	}
+
+	publicKey, err := parsePublicKeyDER(der)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewKeyFromPublicKey(publicKey)
+}
+
+func parsePublicKeyDER(der []byte) (crypto.PublicKey, error) {
+	publicKey, err := x509.ParsePKIXPublicKey(der)
+	if err == nil {
+		return publicKey, nil
+	}
+
+	if rsaPublicKey, rsaErr := x509.ParsePKCS1PublicKey(der); rsaErr == nil {
+		return rsaPublicKey, nil
+	}
+
+	return nil, errors.Join(ErrInvalidPublicKey, err)
+}
+
+func encodePublicKeyAsPEM(publicKey crypto.PublicKey) (string, error) {
+	der, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(pem.EncodeToMemory(&pem.Block{Type: publicKeyPEMBlockType, Bytes: der}))), nil
+}
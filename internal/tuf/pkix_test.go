@@ -0,0 +1,168 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package tuf
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/secure-systems-lab/go-securesystemslib/signerverifier"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadKeyFromBytesWithPEMAndDER(t *testing.T) {
+	t.Run("Ed25519 key matches securesystemslib format", func(t *testing.T) {
+		sslibKeyBytes, err := os.ReadFile(filepath.Join("test-data", "test-key.pub"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		sslibKey, err := LoadKeyFromBytes(sslibKeyBytes)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		publicKey, err := hex.DecodeString(sslibKey.KeyVal.Public)
+		if err != nil {
+			t.Fatal(err)
+		}
+		der := marshalTestPublicKey(t, ed25519.PublicKey(publicKey))
+
+		for name, contents := range map[string][]byte{
+			"PEM": pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}),
+			"DER": der,
+		} {
+			key, err := LoadKeyFromBytes(contents)
+			assert.Nil(t, err, name)
+			assert.Equal(t, sslibKey, key, name)
+		}
+	})
+
+	t.Run("ECDSA keys", func(t *testing.T) {
+		tests := map[string]struct {
+			curve  elliptic.Curve
+			scheme string
+		}{
+			"P-256": {curve: elliptic.P256(), scheme: ecdsaP256Scheme},
+			"P-384": {curve: elliptic.P384(), scheme: ecdsaP384Scheme},
+		}
+
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				privateKey, err := ecdsa.GenerateKey(test.curve, rand.Reader)
+				if err != nil {
+					t.Fatal(err)
+				}
+				der := marshalTestPublicKey(t, privateKey.Public())
+
+				pemKey, err := LoadKeyFromBytes(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+				assert.Nil(t, err)
+				assert.Equal(t, signerverifier.ECDSAKeyType, pemKey.KeyType)
+				assert.Equal(t, test.scheme, pemKey.Scheme)
+
+				derKey, err := LoadKeyFromBytes(der)
+				assert.Nil(t, err)
+				assert.Equal(t, pemKey, derKey)
+
+				// The converted key loads to the same key ID from the
+				// securesystemslib format
+				assertSameKeyAsSecureSystemsLibFormat(t, pemKey)
+			})
+		}
+	})
+
+	t.Run("RSA key", func(t *testing.T) {
+		privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			t.Fatal(err)
+		}
+		der := marshalTestPublicKey(t, privateKey.Public())
+		pkcs1DER := x509.MarshalPKCS1PublicKey(&privateKey.PublicKey)
+
+		pemKey, err := LoadKeyFromBytes(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+		assert.Nil(t, err)
+		assert.Equal(t, signerverifier.RSAKeyType, pemKey.KeyType)
+		assert.Equal(t, signerverifier.RSAKeyScheme, pemKey.Scheme)
+		assertSameKeyAsSecureSystemsLibFormat(t, pemKey)
+
+		// The key ID doesn't depend on how the key is encoded
+		for name, contents := range map[string][]byte{
+			"DER":         der,
+			"PKCS #1":     pem.EncodeToMemory(&pem.Block{Type: "RSA PUBLIC KEY", Bytes: pkcs1DER}),
+			"PKCS #1 DER": pkcs1DER,
+		} {
+			key, err := LoadKeyFromBytes(contents)
+			assert.Nil(t, err, name)
+			assert.Equal(t, pemKey, key, name)
+		}
+	})
+
+	t.Run("unsupported curve", func(t *testing.T) {
+		privateKey, err := ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = LoadKeyFromBytes(marshalTestPublicKey(t, privateKey.Public()))
+		assert.ErrorIs(t, err, ErrUnsupportedPublicKey)
+	})
+
+	t.Run("private key", func(t *testing.T) {
+		_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = LoadKeyFromBytes(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+		assert.ErrorIs(t, err, ErrInvalidPublicKey)
+
+		_, err = LoadKeyFromBytes(der)
+		assert.ErrorIs(t, err, ErrInvalidPublicKey)
+	})
+
+	t.Run("not a key", func(t *testing.T) {
+		_, err := LoadKeyFromBytes([]byte("not a key"))
+		assert.ErrorIs(t, err, ErrInvalidPublicKey)
+	})
+}
+
+func marshalTestPublicKey(t *testing.T, publicKey any) []byte {
+	t.Helper()
+
+	der, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return der
+}
+
+func assertSameKeyAsSecureSystemsLibFormat(t *testing.T, key *Key) {
+	t.Helper()
+
+	keyWithoutID := *key
+	keyWithoutID.KeyID = ""
+	keyBytes, err := json.Marshal(&keyWithoutID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sslibKey, err := LoadKeyFromBytes(keyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, key, sslibKey)
+}
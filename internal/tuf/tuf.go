@@ -7,11 +7,17 @@ package tuf
 // however, is inspired by or cloned from the go-tuf implementation.
 
 import (
+	"bytes"
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"path"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/secure-systems-lab/go-securesystemslib/cjson"
 	"github.com/secure-systems-lab/go-securesystemslib/signerverifier"
@@ -19,8 +25,22 @@ import (
 
 const specVersion = "1.0"
 
+// supportedSpecMajorVersion is the major version of the metadata specification
+// this version of gittuf understands. Metadata with a higher minor version is
+// backwards compatible and is accepted, while metadata with a higher major
+// version may carry semantics that gittuf cannot enforce.
+const supportedSpecMajorVersion = 1
+
 var (
-	ErrTargetsNotEmpty = errors.New("`targets` field in gittuf Targets metadata must be empty")
+	ErrUnsupportedSpecVersion     = errors.New("metadata uses a newer specification version than supported by this version of gittuf, upgrade gittuf to verify it")
+	ErrInvalidSpecVersion         = errors.New("metadata specification version is not of the form <major>.<minor>")
+	ErrTargetsNotEmpty            = errors.New("`targets` field in gittuf Targets metadata must be empty")
+	ErrDuplicateDelegationName    = errors.New("delegation names must be unique")
+	ErrDelegationKeyMissing       = errors.New("delegation authorizes key that is not present in delegation keys")
+	ErrInvalidDelegationThreshold = errors.New("delegation threshold is either less than 1 or greater than number of authorized keys")
+	ErrInvalidDelegationPatterns  = errors.New("delegation must specify well-formed patterns")
+	ErrInvalidKeyValidityWindow   = errors.New("key validity window must be for a delegation key and must not end before it starts")
+	ErrInvalidDistinctSigners     = errors.New("delegation's distinct signers requirement must have a count of at least 1 that is no greater than its window or the number of authorized keys")
 )
 
 // Key defines the structure for how public keys are stored in TUF metadata.
@@ -28,10 +48,14 @@ type Key = signerverifier.SSLibKey
 
 // LoadKeyFromBytes returns a pointer to a Key instance created from the
 // contents of the bytes. The key contents are expected to be in the custom
-// securesystemslib format.
+// securesystemslib format, or to be an Ed25519, ECDSA, or RSA public key
+// encoded as a PEM block or as raw DER. Keys loaded from PEM or DER have the
+// same key ID as the same key loaded from the securesystemslib format.
 func LoadKeyFromBytes(contents []byte) (*Key, error) {
-	// FIXME: this assumes keys are stored in securesystemslib format.
-	// RSA keys are stored in PEM format.
+	if !bytes.HasPrefix(bytes.TrimSpace(contents), []byte("{")) {
+		return loadKeyFromPEMOrDER(contents)
+	}
+
 	var key *Key
 	if err := json.Unmarshal(contents, &key); err != nil {
 		return nil, err
@@ -48,6 +72,20 @@ func LoadKeyFromBytes(contents []byte) (*Key, error) {
 	return key, nil
 }
 
+// IsSameKey indicates if the two keys have the same public contents,
+// irrespective of the key IDs they are listed under.
+func IsSameKey(a, b *Key) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return a.KeyType == b.KeyType &&
+		a.Scheme == b.Scheme &&
+		a.KeyVal.Public == b.KeyVal.Public &&
+		a.KeyVal.Identity == b.KeyVal.Identity &&
+		a.KeyVal.Issuer == b.KeyVal.Issuer
+}
+
 func calculateKeyID(k *Key) (string, error) {
 	key := map[string]any{
 		"keytype":               k.KeyType,
@@ -81,6 +119,19 @@ type RootMetadata struct {
 	Expires            string          `json:"expires"`
 	Keys               map[string]*Key `json:"keys"`
 	Roles              map[string]Role `json:"roles"`
+
+	// TopLevelPolicies lists the roles in Roles, other than the targets role,
+	// that are top level policies, in the order they take precedence.
+	TopLevelPolicies []string `json:"top_level_policies,omitempty"`
+
+	// UnrecognizedFields holds fields in the metadata that are not known to
+	// this version of gittuf, so that they are preserved when the metadata
+	// is updated and signed again.
+	UnrecognizedFields map[string]json.RawMessage `json:"-"`
+
+	// nestedUnrecognizedFields holds the unrecognized fields of the keys and
+	// roles in the metadata.
+	nestedUnrecognizedFields map[string]*unrecognizedFields
 }
 
 // NewRootMetadata returns a new instance of RootMetadata.
@@ -102,6 +153,38 @@ func (r *RootMetadata) SetExpires(expires string) {
 	r.Expires = expires
 }
 
+// CheckSpecVersion returns an error if the RootMetadata was written for a
+// specification version this version of gittuf does not support.
+func (r *RootMetadata) CheckSpecVersion() error {
+	return checkSpecVersion(r.SpecVersion)
+}
+
+// MarshalJSON returns the JSON encoding of the RootMetadata, including any
+// unrecognized fields it was loaded with.
+func (r RootMetadata) MarshalJSON() ([]byte, error) {
+	type alias RootMetadata
+	return marshalWithUnrecognizedFields(alias(r), &unrecognizedFields{fields: r.UnrecognizedFields, nested: r.nestedUnrecognizedFields})
+}
+
+// UnmarshalJSON loads the RootMetadata from its JSON encoding, recording
+// fields that are not known to this version of gittuf in UnrecognizedFields.
+func (r *RootMetadata) UnmarshalJSON(data []byte) error {
+	type alias RootMetadata
+	metadata := alias{}
+	if err := json.Unmarshal(data, &metadata); err != nil {
+		return err
+	}
+
+	unrecognized, err := getUnrecognizedFields(data, reflect.TypeOf(metadata))
+	if err != nil {
+		return err
+	}
+
+	*r = RootMetadata(metadata)
+	r.UnrecognizedFields, r.nestedUnrecognizedFields = unrecognized.split()
+	return nil
+}
+
 // AddKey adds a key to the RootMetadata instance.
 func (r *RootMetadata) AddKey(key *Key) {
 	if r.Keys == nil {
@@ -133,6 +216,16 @@ type TargetsMetadata struct {
 	Expires     string         `json:"expires"`
 	Targets     map[string]any `json:"targets"`
 	Delegations *Delegations   `json:"delegations"`
+
+	// UnrecognizedFields holds fields in the metadata that are not known to
+	// this version of gittuf, so that they are preserved when the metadata
+	// is updated and signed again.
+	UnrecognizedFields map[string]json.RawMessage `json:"-"`
+
+	// nestedUnrecognizedFields holds the unrecognized fields of the
+	// delegations and keys in the metadata. Rules record their own
+	// unrecognized fields.
+	nestedUnrecognizedFields map[string]*unrecognizedFields
 }
 
 // NewTargetsMetadata returns a new instance of TargetsMetadata.
@@ -155,19 +248,70 @@ func (t *TargetsMetadata) SetExpires(expires string) {
 	t.Expires = expires
 }
 
+// CheckSpecVersion returns an error if the TargetsMetadata was written for a
+// specification version this version of gittuf does not support.
+func (t *TargetsMetadata) CheckSpecVersion() error {
+	return checkSpecVersion(t.SpecVersion)
+}
+
+// MarshalJSON returns the JSON encoding of the TargetsMetadata, including any
+// unrecognized fields it was loaded with.
+func (t TargetsMetadata) MarshalJSON() ([]byte, error) {
+	type alias TargetsMetadata
+	return marshalWithUnrecognizedFields(alias(t), &unrecognizedFields{fields: t.UnrecognizedFields, nested: t.nestedUnrecognizedFields})
+}
+
+// UnmarshalJSON loads the TargetsMetadata from its JSON encoding, recording
+// fields that are not known to this version of gittuf in UnrecognizedFields.
+func (t *TargetsMetadata) UnmarshalJSON(data []byte) error {
+	type alias TargetsMetadata
+	metadata := alias{}
+	if err := json.Unmarshal(data, &metadata); err != nil {
+		return err
+	}
+
+	unrecognized, err := getUnrecognizedFields(data, reflect.TypeOf(metadata))
+	if err != nil {
+		return err
+	}
+
+	*t = TargetsMetadata(metadata)
+	t.UnrecognizedFields, t.nestedUnrecognizedFields = unrecognized.split()
+	return nil
+}
+
 // Validate ensures the instance of TargetsMetadata matches gittuf expectations.
+// All the validation failures that are found are returned together.
 func (t *TargetsMetadata) Validate() error {
+	errs := []error{}
+
 	if len(t.Targets) != 0 {
-		return ErrTargetsNotEmpty
+		errs = append(errs, ErrTargetsNotEmpty)
 	}
-	return nil
+
+	if t.Delegations != nil {
+		if err := t.Delegations.Validate(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
 }
 
 // Delegations defines the schema for specifying delegations in TUF's Targets
-// metadata.
+// metadata. As Key is the securesystemslib key type, the periods during which
+// keys are trusted are recorded separately in `key_validity`, keyed by key ID.
 type Delegations struct {
-	Keys  map[string]*Key `json:"keys"`
-	Roles []Delegation    `json:"roles"`
+	Keys        map[string]*Key               `json:"keys"`
+	Roles       []Delegation                  `json:"roles"`
+	KeyValidity map[string]*KeyValidityWindow `json:"key_validity,omitempty"`
+}
+
+// KeyValidityWindow records the period during which a key is trusted to sign
+// commits. A nil bound leaves the period unbounded in that direction.
+type KeyValidityWindow struct {
+	NotBefore *time.Time `json:"not_before,omitempty"`
+	NotAfter  *time.Time `json:"not_after,omitempty"`
 }
 
 // AddKey adds a delegations key.
@@ -183,6 +327,67 @@ func (d *Delegations) AddKey(key *Key) {
 	d.Keys[key.KeyID] = key
 }
 
+// Validate checks that each delegation has a unique name and at least one
+// well-formed pattern, that every key it authorizes is present in the
+// delegation keys, and that its threshold can be met by its authorized keys.
+// Delegations that authorize no keys, such as gittuf's allow rule, are only
+// required to have a threshold of at least 1. Key validity windows must be for
+// delegation keys and must not end before they start. All the validation
+// failures that are found are returned together.
+func (d *Delegations) Validate() error {
+	errs := []error{}
+
+	seenNames := map[string]bool{}
+	for _, delegation := range d.Roles {
+		if seenNames[delegation.Name] {
+			errs = append(errs, fmt.Errorf("%w: rule '%s' is declared more than once", ErrDuplicateDelegationName, delegation.Name))
+		}
+		seenNames[delegation.Name] = true
+
+		if len(delegation.Paths) == 0 {
+			errs = append(errs, fmt.Errorf("%w: rule '%s' has no patterns", ErrInvalidDelegationPatterns, delegation.Name))
+		}
+		for _, pattern := range delegation.Paths {
+			if _, err := path.Match(pattern, ""); err != nil {
+				errs = append(errs, fmt.Errorf("%w: rule '%s' has malformed pattern '%s'", ErrInvalidDelegationPatterns, delegation.Name, pattern))
+			}
+		}
+		for _, pattern := range delegation.Refs {
+			if _, err := path.Match(pattern, ""); err != nil {
+				errs = append(errs, fmt.Errorf("%w: rule '%s' has malformed ref pattern '%s'", ErrInvalidDelegationPatterns, delegation.Name, pattern))
+			}
+		}
+
+		for _, keyID := range delegation.KeyIDs {
+			if _, has := d.Keys[keyID]; !has {
+				errs = append(errs, fmt.Errorf("%w: rule '%s' authorizes key '%s'", ErrDelegationKeyMissing, delegation.Name, keyID))
+			}
+		}
+
+		if delegation.Threshold < 1 || (len(delegation.KeyIDs) > 0 && delegation.Threshold > len(delegation.KeyIDs)) {
+			errs = append(errs, fmt.Errorf("%w: rule '%s' has threshold %d with %d authorized keys", ErrInvalidDelegationThreshold, delegation.Name, delegation.Threshold, len(delegation.KeyIDs)))
+		}
+
+		if req := delegation.MinDistinctSigners; req != nil {
+			if req.Count < 1 || req.Count > req.Window || req.Count > len(delegation.KeyIDs) {
+				errs = append(errs, fmt.Errorf("%w: rule '%s' requires %d distinct signers over %d commits with %d authorized keys", ErrInvalidDistinctSigners, delegation.Name, req.Count, req.Window, len(delegation.KeyIDs)))
+			}
+		}
+	}
+
+	for keyID, window := range d.KeyValidity {
+		if _, has := d.Keys[keyID]; !has {
+			errs = append(errs, fmt.Errorf("%w: key '%s' is not present in delegation keys", ErrInvalidKeyValidityWindow, keyID))
+			continue
+		}
+		if window != nil && window.NotBefore != nil && window.NotAfter != nil && window.NotAfter.Before(*window.NotBefore) {
+			errs = append(errs, fmt.Errorf("%w: key '%s' is trusted until %s, before %s", ErrInvalidKeyValidityWindow, keyID, window.NotAfter.Format(time.RFC3339), window.NotBefore.Format(time.RFC3339)))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
 // AddDelegation adds a new delegation.
 func (d *Delegations) AddDelegation(delegation Delegation) {
 	if d.Roles == nil {
@@ -202,13 +407,304 @@ func (d *Delegation) Matches(target string) bool {
 	return false
 }
 
+// IsExpired checks if the delegation records an expiry that is before now. A
+// delegation without an expiry never expires.
+func (d *Delegation) IsExpired(now time.Time) bool {
+	return d.Expires != nil && now.After(*d.Expires)
+}
+
+// MatchesOnRef checks if any of the delegation's patterns match the target and,
+// if the delegation is restricted to certain refs, that one of its ref patterns
+// matches refName. If refName is empty, the ref being changed is not known and
+// only the target is checked, so that the delegation is not ignored.
+func (d *Delegation) MatchesOnRef(target, refName string) bool {
+	if !d.Matches(target) {
+		return false
+	}
+
+	return d.AppliesToRef(refName)
+}
+
+// AppliesToRef checks if the delegation applies to changes made on refName,
+// i.e., if the delegation is not restricted to certain refs or one of its ref
+// patterns matches refName. If refName is empty, the delegation applies.
+func (d *Delegation) AppliesToRef(refName string) bool {
+	if len(d.Refs) == 0 || len(refName) == 0 {
+		return true
+	}
+
+	for _, pattern := range d.Refs {
+		if ok, _ := path.Match(pattern, refName); ok {
+			return true
+		}
+	}
+	return false
+}
+
 // Delegation defines the schema for a single delegation entry. It differs from
 // the standard TUF schema by allowing a `custom` field to record details
-// pertaining to the delegation.
+// pertaining to the delegation. Additionally, a delegation may be marked as a
+// `deny` rule, which indicates no key is trusted for the matching namespaces,
+// may pin the `allowed_hashes` that files matching the delegation may have, and
+// may require `min_distinct_signers` over the recent history of matching refs.
+// A delegation protecting file paths may also be restricted to changes made on
+// the Git refs matching its `refs` patterns. A delegation protecting Git refs
+// may set `verify_merge_commits`, which requires merge commits in matching refs
+// to only combine their parents' changes, with any conflict resolutions signed
+// by keys trusted for the resolved paths, and `require_signed_commits`, which
+// requires every commit introduced in matching refs to be signed by one of the
+// delegation's keys rather than only the RSL entry recording it.
 type Delegation struct {
-	Name        string           `json:"name"`
-	Paths       []string         `json:"paths"`
-	Terminating bool             `json:"terminating"`
-	Custom      *json.RawMessage `json:"custom,omitempty"`
+	Name                 string                      `json:"name"`
+	Paths                []string                    `json:"paths"`
+	Refs                 []string                    `json:"refs,omitempty"`
+	Terminating          bool                        `json:"terminating"`
+	Deny                 bool                        `json:"deny,omitempty"`
+	AllowedHashes        []string                    `json:"allowed_hashes,omitempty"`
+	MinDistinctSigners   *DistinctSignersRequirement `json:"min_distinct_signers,omitempty"`
+	VerifyMergeCommits   bool                        `json:"verify_merge_commits,omitempty"`
+	RequireSignedCommits bool                        `json:"require_signed_commits,omitempty"`
+	Expires              *time.Time                  `json:"expires,omitempty"`
+	Custom               *json.RawMessage            `json:"custom,omitempty"`
 	Role
+
+	// UnrecognizedFields holds fields in the rule that are not known to this
+	// version of gittuf, so that they are preserved when the metadata is
+	// updated and signed again.
+	UnrecognizedFields map[string]json.RawMessage `json:"-"`
+
+	// nestedUnrecognizedFields holds the unrecognized fields of the objects
+	// in the rule, such as its distinct signers requirement.
+	nestedUnrecognizedFields map[string]*unrecognizedFields
+}
+
+// MarshalJSON returns the JSON encoding of the Delegation, including any
+// unrecognized fields it was loaded with.
+func (d Delegation) MarshalJSON() ([]byte, error) {
+	type alias Delegation
+	return marshalWithUnrecognizedFields(alias(d), &unrecognizedFields{fields: d.UnrecognizedFields, nested: d.nestedUnrecognizedFields})
+}
+
+// UnmarshalJSON loads the Delegation from its JSON encoding, recording fields
+// that are not known to this version of gittuf in UnrecognizedFields.
+func (d *Delegation) UnmarshalJSON(data []byte) error {
+	type alias Delegation
+	delegation := alias{}
+	if err := json.Unmarshal(data, &delegation); err != nil {
+		return err
+	}
+
+	unrecognized, err := getUnrecognizedFields(data, reflect.TypeOf(delegation))
+	if err != nil {
+		return err
+	}
+
+	*d = Delegation(delegation)
+	d.UnrecognizedFields, d.nestedUnrecognizedFields = unrecognized.split()
+	return nil
+}
+
+// DistinctSignersRequirement records that the last Window commits of a ref must
+// be signed by at least Count distinct keys authorized by the delegation.
+type DistinctSignersRequirement struct {
+	Count  int `json:"count"`
+	Window int `json:"window"`
+}
+
+func checkSpecVersion(version string) error {
+	majorVersion, minorVersion, found := strings.Cut(version, ".")
+	if !found {
+		return fmt.Errorf("%w: '%s'", ErrInvalidSpecVersion, version)
+	}
+
+	major, err := strconv.Atoi(majorVersion)
+	if err != nil || major < 0 {
+		return fmt.Errorf("%w: '%s'", ErrInvalidSpecVersion, version)
+	}
+	if minor, err := strconv.Atoi(minorVersion); err != nil || minor < 0 {
+		return fmt.Errorf("%w: '%s'", ErrInvalidSpecVersion, version)
+	}
+
+	if major > supportedSpecMajorVersion {
+		return fmt.Errorf("%w: metadata has version '%s', gittuf supports up to '%d.x'", ErrUnsupportedSpecVersion, version, supportedSpecMajorVersion)
+	}
+
+	return nil
+}
+
+// unrecognizedFields records the fields in a JSON object that are not known to
+// this version of gittuf, along with those of the objects nested in it. Nested
+// objects are keyed by the name of the field or the map key they are stored
+// under.
+type unrecognizedFields struct {
+	fields map[string]json.RawMessage
+	nested map[string]*unrecognizedFields
+}
+
+// split returns the fields and nested objects, either of which is nil if
+// empty.
+func (u *unrecognizedFields) split() (map[string]json.RawMessage, map[string]*unrecognizedFields) {
+	if u == nil {
+		return nil, nil
+	}
+
+	return u.fields, u.nested
+}
+
+var jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// getUnrecognizedFields returns the fields in the JSON encoding data of a
+// value of knownType that don't correspond to a field of knownType, including
+// those in nested structs and maps of structs. Types that implement
+// json.Unmarshaler record their own unrecognized fields, and are not inspected.
+// Slices are not inspected either, so their elements must record their own
+// unrecognized fields. As with encoding/json, field names are matched case
+// insensitively. If there are no unrecognized fields, nil is returned.
+func getUnrecognizedFields(data []byte, knownType reflect.Type) (*unrecognizedFields, error) {
+	for knownType.Kind() == reflect.Pointer {
+		knownType = knownType.Elem()
+	}
+	if reflect.PointerTo(knownType).Implements(jsonUnmarshalerType) {
+		return nil, nil
+	}
+
+	objects := map[string]json.RawMessage{}
+	switch knownType.Kind() {
+	case reflect.Struct, reflect.Map:
+		if err := json.Unmarshal(data, &objects); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, nil
+	}
+	if knownType.Kind() == reflect.Map && knownType.Key().Kind() != reflect.String {
+		return nil, nil
+	}
+
+	unrecognized := &unrecognizedFields{}
+	knownFields := getJSONFields(knownType)
+	for name, value := range objects {
+		var valueType reflect.Type
+		if knownType.Kind() == reflect.Map {
+			valueType = knownType.Elem()
+		} else {
+			fieldType, isKnown := lookupJSONField(knownFields, name)
+			if !isKnown {
+				if unrecognized.fields == nil {
+					unrecognized.fields = map[string]json.RawMessage{}
+				}
+				unrecognized.fields[name] = value
+				continue
+			}
+			valueType = fieldType
+		}
+
+		nested, err := getUnrecognizedFields(value, valueType)
+		if err != nil {
+			return nil, err
+		}
+		if nested != nil {
+			if unrecognized.nested == nil {
+				unrecognized.nested = map[string]*unrecognizedFields{}
+			}
+			unrecognized.nested[name] = nested
+		}
+	}
+
+	if unrecognized.fields == nil && unrecognized.nested == nil {
+		return nil, nil
+	}
+	return unrecognized, nil
+}
+
+// getJSONFields returns the types of the exported fields of the struct type,
+// keyed by their JSON names. The fields of embedded structs are included as
+// encoding/json flattens them. Non-struct types have no fields.
+func getJSONFields(structType reflect.Type) map[string]reflect.Type {
+	fields := map[string]reflect.Type{}
+	if structType.Kind() != reflect.Struct {
+		return fields
+	}
+
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			for embeddedName, embeddedType := range getJSONFields(field.Type) {
+				fields[embeddedName] = embeddedType
+			}
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields[name] = field.Type
+	}
+
+	return fields
+}
+
+func lookupJSONField(fields map[string]reflect.Type, name string) (reflect.Type, bool) {
+	if fieldType, has := fields[name]; has {
+		return fieldType, true
+	}
+	for fieldName, fieldType := range fields {
+		if strings.EqualFold(fieldName, name) {
+			return fieldType, true
+		}
+	}
+
+	return nil, false
+}
+
+// marshalWithUnrecognizedFields returns the JSON encoding of v with the
+// unrecognized fields added back, including those of nested objects that are
+// still present. Fields known to v take precedence.
+func marshalWithUnrecognizedFields(v any, unrecognized *unrecognizedFields) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return addUnrecognizedFields(data, unrecognized)
+}
+
+func addUnrecognizedFields(data []byte, unrecognized *unrecognizedFields) ([]byte, error) {
+	if unrecognized == nil || (len(unrecognized.fields) == 0 && len(unrecognized.nested) == 0) {
+		return data, nil
+	}
+
+	objects := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &objects); err != nil {
+		return nil, err
+	}
+	if objects == nil {
+		// The object was encoded as null
+		return data, nil
+	}
+
+	for name, value := range unrecognized.fields {
+		if _, has := objects[name]; !has {
+			objects[name] = value
+		}
+	}
+	for name, nested := range unrecognized.nested {
+		value, has := objects[name]
+		if !has {
+			continue
+		}
+		updatedValue, err := addUnrecognizedFields(value, nested)
+		if err != nil {
+			return nil, err
+		}
+		objects[name] = updatedValue
+	}
+
+	return json.Marshal(objects)
 }
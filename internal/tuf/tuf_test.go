@@ -3,6 +3,8 @@
 package tuf
 
 import (
+	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
@@ -26,6 +28,31 @@ func TestLoadKeyFromBytes(t *testing.T) {
 	assert.Equal(t, "52e3b8e73279d6ebdd62a5016e2725ff284f569665eb92ccb145d83817a02997", key.KeyID)
 }
 
+func TestIsSameKey(t *testing.T) {
+	publicKeyBytes, err := os.ReadFile(filepath.Join("test-data", "test-key.pub"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := LoadKeyFromBytes(publicKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.True(t, IsSameKey(key, key))
+
+	// The key ID is not part of the key's public contents
+	renamedKey := *key
+	renamedKey.KeyID = "renamed-key"
+	assert.True(t, IsSameKey(key, &renamedKey))
+
+	otherKey := *key
+	otherKey.KeyVal.Public = "other-public"
+	assert.False(t, IsSameKey(key, &otherKey))
+
+	assert.False(t, IsSameKey(key, nil))
+	assert.True(t, IsSameKey(nil, nil))
+}
+
 func TestRootMetadata(t *testing.T) {
 	rootMetadata := NewRootMetadata()
 
@@ -130,4 +157,383 @@ func TestTargetsMetadataAndDelegations(t *testing.T) {
 		delegations.AddDelegation(d)
 		assert.Contains(t, delegations.Roles, d)
 	})
+
+	t.Run("test Delegations Validate", func(t *testing.T) {
+		validDelegation := Delegation{
+			Name:  "valid",
+			Paths: []string{"git:refs/heads/*"},
+			Role:  Role{KeyIDs: []string{key.KeyID}, Threshold: 1},
+		}
+		allowDelegation := Delegation{
+			Name:        "allow",
+			Paths:       []string{"*"},
+			Terminating: true,
+			Role:        Role{KeyIDs: []string{}, Threshold: 1},
+		}
+
+		notBefore := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+		notAfter := notBefore.AddDate(1, 0, 0)
+
+		tests := map[string]struct {
+			roles         []Delegation
+			keyValidity   map[string]*KeyValidityWindow
+			expectedError []error
+		}{
+			"valid delegations": {
+				roles: []Delegation{validDelegation, allowDelegation},
+			},
+			"duplicate delegation names": {
+				roles:         []Delegation{validDelegation, validDelegation, allowDelegation},
+				expectedError: []error{ErrDuplicateDelegationName},
+			},
+			"key missing from delegation keys": {
+				roles: []Delegation{{
+					Name:  "missing-key",
+					Paths: []string{"git:refs/heads/main"},
+					Role:  Role{KeyIDs: []string{"unknown-key"}, Threshold: 1},
+				}},
+				expectedError: []error{ErrDelegationKeyMissing},
+			},
+			"threshold exceeds number of keys": {
+				roles: []Delegation{{
+					Name:  "high-threshold",
+					Paths: []string{"git:refs/heads/main"},
+					Role:  Role{KeyIDs: []string{key.KeyID}, Threshold: 2},
+				}},
+				expectedError: []error{ErrInvalidDelegationThreshold},
+			},
+			"threshold less than 1": {
+				roles: []Delegation{{
+					Name:  "zero-threshold",
+					Paths: []string{"git:refs/heads/main"},
+					Role:  Role{KeyIDs: []string{key.KeyID}, Threshold: 0},
+				}},
+				expectedError: []error{ErrInvalidDelegationThreshold},
+			},
+			"malformed and missing patterns": {
+				roles: []Delegation{
+					{Name: "malformed", Paths: []string{"git:refs/heads/["}, Role: Role{KeyIDs: []string{key.KeyID}, Threshold: 1}},
+					{Name: "no-patterns", Role: Role{KeyIDs: []string{key.KeyID}, Threshold: 1}},
+				},
+				expectedError: []error{ErrInvalidDelegationPatterns},
+			},
+			"valid distinct signers requirement": {
+				roles: []Delegation{{
+					Name:               "distinct-signers",
+					Paths:              []string{"git:refs/heads/main"},
+					MinDistinctSigners: &DistinctSignersRequirement{Count: 1, Window: 5},
+					Role:               Role{KeyIDs: []string{key.KeyID}, Threshold: 1},
+				}},
+			},
+			"invalid distinct signers requirement": {
+				roles: []Delegation{
+					{
+						Name:               "too-few-keys",
+						Paths:              []string{"git:refs/heads/main"},
+						MinDistinctSigners: &DistinctSignersRequirement{Count: 2, Window: 5},
+						Role:               Role{KeyIDs: []string{key.KeyID}, Threshold: 1},
+					},
+					{
+						Name:               "window-too-small",
+						Paths:              []string{"git:refs/heads/main"},
+						MinDistinctSigners: &DistinctSignersRequirement{Count: 1, Window: 0},
+						Role:               Role{KeyIDs: []string{key.KeyID}, Threshold: 1},
+					},
+				},
+				expectedError: []error{ErrInvalidDistinctSigners},
+			},
+			"malformed ref pattern": {
+				roles: []Delegation{{
+					Name:  "malformed-ref",
+					Paths: []string{"file:deploy/*"},
+					Refs:  []string{"refs/heads/["},
+					Role:  Role{KeyIDs: []string{key.KeyID}, Threshold: 1},
+				}},
+				expectedError: []error{ErrInvalidDelegationPatterns},
+			},
+			"valid key validity windows": {
+				roles:       []Delegation{validDelegation, allowDelegation},
+				keyValidity: map[string]*KeyValidityWindow{key.KeyID: {NotBefore: &notBefore, NotAfter: &notAfter}},
+			},
+			"key validity window without upper bound": {
+				roles:       []Delegation{validDelegation, allowDelegation},
+				keyValidity: map[string]*KeyValidityWindow{key.KeyID: {NotBefore: &notBefore}},
+			},
+			"key validity window ends before it starts": {
+				roles:         []Delegation{validDelegation, allowDelegation},
+				keyValidity:   map[string]*KeyValidityWindow{key.KeyID: {NotBefore: &notAfter, NotAfter: &notBefore}},
+				expectedError: []error{ErrInvalidKeyValidityWindow},
+			},
+			"key validity window for unknown key": {
+				roles:         []Delegation{validDelegation, allowDelegation},
+				keyValidity:   map[string]*KeyValidityWindow{"unknown-key": {NotBefore: &notBefore}},
+				expectedError: []error{ErrInvalidKeyValidityWindow},
+			},
+			"multiple failures": {
+				roles: []Delegation{{
+					Name:  "many-problems",
+					Paths: []string{},
+					Role:  Role{KeyIDs: []string{"unknown-key"}, Threshold: 2},
+				}},
+				expectedError: []error{ErrInvalidDelegationPatterns, ErrDelegationKeyMissing, ErrInvalidDelegationThreshold},
+			},
+		}
+
+		for name, test := range tests {
+			delegations := &Delegations{Keys: map[string]*Key{key.KeyID: key}, Roles: test.roles, KeyValidity: test.keyValidity}
+			err := delegations.Validate()
+			if len(test.expectedError) == 0 {
+				assert.Nil(t, err, fmt.Sprintf("unexpected error in test '%s'", name))
+				continue
+			}
+			for _, expectedError := range test.expectedError {
+				assert.ErrorIs(t, err, expectedError, fmt.Sprintf("unexpected error in test '%s'", name))
+			}
+		}
+
+		// Validation failures in the delegations are reported for the
+		// targets metadata
+		targetsMetadata.Delegations = &Delegations{Keys: map[string]*Key{key.KeyID: key}, Roles: []Delegation{validDelegation, validDelegation}}
+		targetsMetadata.Targets = map[string]any{"test": true}
+		err := targetsMetadata.Validate()
+		assert.ErrorIs(t, err, ErrTargetsNotEmpty)
+		assert.ErrorIs(t, err, ErrDuplicateDelegationName)
+		targetsMetadata.Targets = nil
+		targetsMetadata.Delegations = &Delegations{}
+	})
+
+	t.Run("test Delegation MatchesOnRef", func(t *testing.T) {
+		delegation := Delegation{
+			Name:  "deploy",
+			Paths: []string{"file:deploy/*.yaml"},
+			Refs:  []string{"refs/heads/prod", "refs/heads/release/*"},
+		}
+
+		tests := map[string]struct {
+			target   string
+			refName  string
+			expected bool
+		}{
+			"path and ref match": {
+				target:   "file:deploy/app.yaml",
+				refName:  "refs/heads/prod",
+				expected: true,
+			},
+			"path and ref pattern match": {
+				target:   "file:deploy/app.yaml",
+				refName:  "refs/heads/release/1.0",
+				expected: true,
+			},
+			"path matches, ref does not": {
+				target:   "file:deploy/app.yaml",
+				refName:  "refs/heads/main",
+				expected: false,
+			},
+			"ref matches, path does not": {
+				target:   "file:src/main.go",
+				refName:  "refs/heads/prod",
+				expected: false,
+			},
+			"path matches, ref unknown": {
+				target:   "file:deploy/app.yaml",
+				expected: true,
+			},
+		}
+
+		for name, test := range tests {
+			assert.Equal(t, test.expected, delegation.MatchesOnRef(test.target, test.refName), fmt.Sprintf("unexpected result in test '%s'", name))
+		}
+
+		// Delegations without ref patterns match on any ref
+		delegation.Refs = nil
+		assert.True(t, delegation.MatchesOnRef("file:deploy/app.yaml", "refs/heads/main"))
+	})
+
+	t.Run("test Delegation AppliesToRef", func(t *testing.T) {
+		delegation := Delegation{
+			Name:  "deploy",
+			Paths: []string{"file:deploy/*.yaml"},
+			Refs:  []string{"refs/heads/release/*"},
+		}
+
+		assert.True(t, delegation.AppliesToRef("refs/heads/release/1.0"))
+		assert.False(t, delegation.AppliesToRef("refs/heads/main"))
+		assert.True(t, delegation.AppliesToRef(""))
+
+		delegation.Refs = nil
+		assert.True(t, delegation.AppliesToRef("refs/heads/main"))
+	})
+
+	t.Run("test Delegation IsExpired", func(t *testing.T) {
+		expires := time.Date(2030, time.January, 1, 0, 0, 0, 0, time.UTC)
+		delegation := Delegation{Name: "contractor", Paths: []string{"file:*"}}
+
+		// Delegations without an expiry never expire
+		assert.False(t, delegation.IsExpired(expires.AddDate(100, 0, 0)))
+
+		delegation.Expires = &expires
+		assert.False(t, delegation.IsExpired(expires.Add(-time.Second)))
+		assert.False(t, delegation.IsExpired(expires))
+		assert.True(t, delegation.IsExpired(expires.Add(time.Second)))
+	})
+}
+
+func TestMetadataUnrecognizedFields(t *testing.T) {
+	t.Run("root metadata", func(t *testing.T) {
+		rootMetadata := NewRootMetadata()
+		rootMetadata.SetVersion(1)
+		rootMetadata.AddRole("targets", Role{KeyIDs: []string{"key"}, Threshold: 1})
+
+		contents, err := json.Marshal(rootMetadata)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.NotContains(t, string(contents), "UnrecognizedFields")
+
+		// Add a field from a future version of gittuf
+		fields := map[string]json.RawMessage{}
+		if err := json.Unmarshal(contents, &fields); err != nil {
+			t.Fatal(err)
+		}
+		fields["future_field"] = json.RawMessage(`{"enabled":true}`)
+		contents, err = json.Marshal(fields)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		loadedRootMetadata := &RootMetadata{}
+		err = json.Unmarshal(contents, loadedRootMetadata)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, loadedRootMetadata.Version)
+		assert.Equal(t, rootMetadata.Roles, loadedRootMetadata.Roles)
+		assert.Equal(t, map[string]json.RawMessage{"future_field": json.RawMessage(`{"enabled":true}`)}, loadedRootMetadata.UnrecognizedFields)
+
+		// The field is preserved when the metadata is updated
+		loadedRootMetadata.SetVersion(2)
+		contents, err = json.Marshal(loadedRootMetadata)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Contains(t, string(contents), `"future_field":{"enabled":true}`)
+		assert.Contains(t, string(contents), `"version":2`)
+	})
+
+	t.Run("targets metadata", func(t *testing.T) {
+		contents := []byte(`{"type":"targets","spec_version":"1.0","version":1,"expires":"","targets":{},"delegations":{"keys":{},"roles":[]},"future_field":["a","b"]}`)
+
+		targetsMetadata := &TargetsMetadata{}
+		err := json.Unmarshal(contents, targetsMetadata)
+		assert.Nil(t, err)
+		assert.Equal(t, "targets", targetsMetadata.Type)
+		assert.Equal(t, map[string]json.RawMessage{"future_field": json.RawMessage(`["a","b"]`)}, targetsMetadata.UnrecognizedFields)
+
+		targetsMetadata.SetVersion(2)
+		contents, err = json.Marshal(targetsMetadata)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Contains(t, string(contents), `"future_field":["a","b"]`)
+
+		// Fields known to gittuf can't be overridden
+		targetsMetadata.UnrecognizedFields["version"] = json.RawMessage(`10`)
+		contents, err = json.Marshal(targetsMetadata)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Contains(t, string(contents), `"version":2`)
+		assert.NotContains(t, string(contents), `"version":10`)
+	})
+
+	t.Run("root metadata with unrecognized fields in roles and keys", func(t *testing.T) {
+		contents := []byte(`{"type":"root","spec_version":"1.0","version":1,"expires":"","keys":{"key":{"keytype":"ecdsa","scheme":"ecdsa-sha2-nistp256","keyid":"key","keyval":{"public":"pub","future_keyval_field":"a"},"future_key_field":"b"}},"roles":{"targets":{"keyids":["key"],"threshold":1,"future_role_field":"c"}}}`)
+
+		rootMetadata := &RootMetadata{}
+		err := json.Unmarshal(contents, rootMetadata)
+		assert.Nil(t, err)
+		assert.Nil(t, rootMetadata.UnrecognizedFields)
+		assert.Equal(t, Role{KeyIDs: []string{"key"}, Threshold: 1}, rootMetadata.Roles["targets"])
+
+		rootMetadata.SetVersion(2)
+		contents, err = json.Marshal(rootMetadata)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Contains(t, string(contents), `"future_keyval_field":"a"`)
+		assert.Contains(t, string(contents), `"future_key_field":"b"`)
+		assert.Contains(t, string(contents), `"future_role_field":"c"`)
+		assert.Contains(t, string(contents), `"version":2`)
+	})
+
+	t.Run("targets metadata with unrecognized fields in a rule", func(t *testing.T) {
+		contents := []byte(`{"type":"targets","spec_version":"1.0","version":1,"expires":"","targets":{},"delegations":{"keys":{},"roles":[{"name":"protect-main","paths":["git:refs/heads/main"],"terminating":false,"keyids":["key"],"threshold":1,"future_rule_field":{"enabled":true}},{"name":"gittuf-allow-rule","paths":["*"],"terminating":true,"keyids":[],"threshold":1}]}}`)
+
+		targetsMetadata := &TargetsMetadata{}
+		err := json.Unmarshal(contents, targetsMetadata)
+		assert.Nil(t, err)
+		assert.Nil(t, targetsMetadata.UnrecognizedFields)
+		assert.Equal(t, map[string]json.RawMessage{"future_rule_field": json.RawMessage(`{"enabled":true}`)}, targetsMetadata.Delegations.Roles[0].UnrecognizedFields)
+		assert.Nil(t, targetsMetadata.Delegations.Roles[1].UnrecognizedFields)
+
+		// The field is preserved when the rule is updated and the metadata
+		// is signed again
+		targetsMetadata.Delegations.Roles[0].Threshold = 2
+		targetsMetadata.SetVersion(2)
+		contents, err = json.Marshal(targetsMetadata)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		loadedTargetsMetadata := &TargetsMetadata{}
+		err = json.Unmarshal(contents, loadedTargetsMetadata)
+		assert.Nil(t, err)
+		assert.Equal(t, targetsMetadata, loadedTargetsMetadata)
+		assert.Equal(t, 2, loadedTargetsMetadata.Delegations.Roles[0].Threshold)
+		assert.Equal(t, map[string]json.RawMessage{"future_rule_field": json.RawMessage(`{"enabled":true}`)}, loadedTargetsMetadata.Delegations.Roles[0].UnrecognizedFields)
+	})
+
+	t.Run("metadata without unrecognized fields", func(t *testing.T) {
+		targetsMetadata := NewTargetsMetadata()
+		contents, err := json.Marshal(targetsMetadata)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		loadedTargetsMetadata := &TargetsMetadata{}
+		err = json.Unmarshal(contents, loadedTargetsMetadata)
+		assert.Nil(t, err)
+		assert.Equal(t, targetsMetadata, loadedTargetsMetadata)
+	})
+}
+
+func TestCheckSpecVersion(t *testing.T) {
+	tests := map[string]struct {
+		specVersion   string
+		expectedError error
+	}{
+		"current version":        {specVersion: specVersion},
+		"newer minor version":    {specVersion: "1.7"},
+		"older major version":    {specVersion: "0.9"},
+		"newer major version":    {specVersion: "2.0", expectedError: ErrUnsupportedSpecVersion},
+		"missing minor version":  {specVersion: "1", expectedError: ErrInvalidSpecVersion},
+		"non-numeric version":    {specVersion: "one.zero", expectedError: ErrInvalidSpecVersion},
+		"empty version":          {specVersion: "", expectedError: ErrInvalidSpecVersion},
+		"negative major version": {specVersion: "-1.0", expectedError: ErrInvalidSpecVersion},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			rootMetadata := NewRootMetadata()
+			rootMetadata.SpecVersion = test.specVersion
+			targetsMetadata := NewTargetsMetadata()
+			targetsMetadata.SpecVersion = test.specVersion
+
+			if test.expectedError == nil {
+				assert.Nil(t, rootMetadata.CheckSpecVersion())
+				assert.Nil(t, targetsMetadata.CheckSpecVersion())
+			} else {
+				assert.ErrorIs(t, rootMetadata.CheckSpecVersion(), test.expectedError)
+				assert.ErrorIs(t, targetsMetadata.CheckSpecVersion(), test.expectedError)
+			}
+		})
+	}
 }